@@ -12,12 +12,22 @@ func main() {
 		Long:  "aya.is-services CLI provides various functionalities for site management including reporting and administration.", //nolint:lll
 	}
 
+	// Every config value can be overridden for the commands that load it, e.g. --log-level.
+	err := subcommands.BindConfigFlags(rootCmd.PersistentFlags())
+	if err != nil {
+		panic(err)
+	}
+
 	rootCmd.AddCommand(subcommands.CmdID())
 	rootCmd.AddCommand(subcommands.CmdReady())
 	rootCmd.AddCommand(subcommands.CmdProfiles())
+	rootCmd.AddCommand(subcommands.CmdConnections())
+	rootCmd.AddCommand(subcommands.CmdAPIKeys())
+	rootCmd.AddCommand(subcommands.CmdLogLevels())
 	rootCmd.AddCommand(subcommands.CmdScrape())
+	rootCmd.AddCommand(subcommands.CmdConfig())
 
-	err := rootCmd.Execute()
+	err = rootCmd.Execute()
 	if err != nil {
 		panic(err)
 	}
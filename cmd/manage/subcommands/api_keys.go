@@ -0,0 +1,23 @@
+package subcommands
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func CmdAPIKeys() *cobra.Command {
+	options := &adminOptions{} //nolint:exhaustruct
+
+	apiKeysCmd := &cobra.Command{ //nolint:exhaustruct
+		Use:   "api-keys",
+		Short: "Manages API keys",
+		Long:  "Mints, lists and revokes the API keys of machine clients through the admin API",
+	}
+
+	options.registerFlags(apiKeysCmd)
+
+	apiKeysCmd.AddCommand(CmdAPIKeysList(options))
+	apiKeysCmd.AddCommand(CmdAPIKeysMint(options))
+	apiKeysCmd.AddCommand(CmdAPIKeysRevoke(options))
+
+	return apiKeysCmd
+}
@@ -0,0 +1,20 @@
+package subcommands
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+func CmdAPIKeysList(options *adminOptions) *cobra.Command {
+	apiKeysListCmd := &cobra.Command{ //nolint:exhaustruct
+		Use:   "list",
+		Short: "Lists API keys",
+		Long:  "Lists the API keys with their scopes, expiry and last use",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.do(cmd.Context(), http.MethodGet, "/admin/api-keys", nil, cmd.OutOrStdout())
+		},
+	}
+
+	return apiKeysListCmd
+}
@@ -0,0 +1,53 @@
+package subcommands
+
+import (
+	"net/http"
+	"time"
+
+	adminhttp "github.com/eser/aya.is-services/pkg/api/adapters/http"
+	"github.com/spf13/cobra"
+)
+
+func CmdAPIKeysMint(options *adminOptions) *cobra.Command {
+	var (
+		scopes    []string
+		expiresIn time.Duration
+	)
+
+	apiKeysMintCmd := &cobra.Command{ //nolint:exhaustruct
+		Use:   "mint <name>",
+		Short: "Mints an API key",
+		Long:  "Creates an API key and prints it; the key cannot be shown again",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			request := adminhttp.MintAPIKeyRequest{
+				ExpiresAt: nil,
+				Name:      args[0],
+				Scopes:    scopes,
+			}
+
+			if expiresIn > 0 {
+				expiresAt := time.Now().Add(expiresIn)
+				request.ExpiresAt = &expiresAt
+			}
+
+			return options.do(
+				cmd.Context(),
+				http.MethodPost,
+				"/admin/api-keys",
+				&request,
+				cmd.OutOrStdout(),
+			)
+		},
+	}
+
+	apiKeysMintCmd.Flags().StringSliceVar(&scopes, "scope", nil, "scope to grant (repeatable)")
+	apiKeysMintCmd.Flags().DurationVar(
+		&expiresIn,
+		"expires-in",
+		0,
+		"lifetime of the key, e.g. 720h (never expires when unset)",
+	)
+
+	return apiKeysMintCmd
+}
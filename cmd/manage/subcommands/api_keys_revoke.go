@@ -0,0 +1,28 @@
+package subcommands
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/spf13/cobra"
+)
+
+func CmdAPIKeysRevoke(options *adminOptions) *cobra.Command {
+	apiKeysRevokeCmd := &cobra.Command{ //nolint:exhaustruct
+		Use:   "revoke <id>",
+		Short: "Revokes an API key",
+		Long:  "Revokes an API key so requests using it are rejected",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.do(
+				cmd.Context(),
+				http.MethodDelete,
+				"/admin/api-keys/"+url.PathEscape(args[0]),
+				nil,
+				cmd.OutOrStdout(),
+			)
+		},
+	}
+
+	return apiKeysRevokeCmd
+}
@@ -0,0 +1,17 @@
+package subcommands
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func CmdConfig() *cobra.Command {
+	configCmd := &cobra.Command{ //nolint:exhaustruct
+		Use:   "config",
+		Short: "Inspects the configuration",
+		Long:  "Inspects the configuration loaded from the defaults, config files, environment and flags",
+	}
+
+	configCmd.AddCommand(CmdConfigShow())
+
+	return configCmd
+}
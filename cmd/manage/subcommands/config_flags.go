@@ -0,0 +1,33 @@
+package subcommands
+
+import (
+	"github.com/eser/aya.is-services/pkg/ajan/configfx"
+	"github.com/eser/aya.is-services/pkg/api/adapters/appcontext"
+	"github.com/spf13/pflag"
+)
+
+// configFlags are the config flags bound by BindConfigFlags; nil until then.
+var configFlags *configfx.ConfigFlags //nolint:gochecknoglobals
+
+// BindConfigFlags defines a flag for each config value on flags, usually the
+// persistent flags of the root command, so they override the config of the commands
+// that load it.
+func BindConfigFlags(flags *pflag.FlagSet) error {
+	bound, err := appcontext.NewConfigFlags()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	bound.AddToPFlags(flags)
+	configFlags = bound
+
+	return nil
+}
+
+// newAppContext creates an app context whose config is overridden by the config flags.
+func newAppContext() *appcontext.AppContext {
+	appContext := appcontext.New()
+	appContext.ConfigFlags = configFlags
+
+	return appContext
+}
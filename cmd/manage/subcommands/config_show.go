@@ -0,0 +1,54 @@
+package subcommands
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func CmdConfigShow() *cobra.Command {
+	var source string
+
+	configShowCmd := &cobra.Command{ //nolint:exhaustruct
+		Use:   "show",
+		Short: "Shows the effective configuration",
+		Long:  "Shows every value of the merged configuration with the source that set it: default, file, env or flag. Secrets are masked.", //nolint:lll
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execConfigShow(cmd.OutOrStdout(), source)
+		},
+	}
+
+	configShowCmd.Flags().StringVar(&source, "source", "", "only shows the values set by a source, e.g. env")
+
+	return configShowCmd
+}
+
+func execConfigShow(out io.Writer, source string) error {
+	values, loadErr := newAppContext().InspectConfig()
+	if values == nil {
+		return loadErr
+	}
+
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0) //nolint:mnd
+
+	_, _ = fmt.Fprintln(writer, "KEY\tVALUE\tSOURCE")
+
+	for _, value := range values {
+		if source != "" && value.Source != source {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\n", value.Key, value.Value, value.Source)
+	}
+
+	err := writer.Flush()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// The values are shown even when they fail to validate, so the failures can be
+	// matched to their sources.
+	return loadErr
+}
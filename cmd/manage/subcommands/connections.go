@@ -0,0 +1,125 @@
+package subcommands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const adminRequestTimeout = 30 * time.Second
+
+var ErrAdminRequestFailed = errors.New("admin request failed")
+
+// adminOptions addresses the admin API of a running serve instance.
+type adminOptions struct {
+	endpoint string
+	token    string
+}
+
+func CmdConnections() *cobra.Command {
+	options := &adminOptions{} //nolint:exhaustruct
+
+	connectionsCmd := &cobra.Command{ //nolint:exhaustruct
+		Use:   "connections",
+		Short: "Manages connections",
+		Long:  "Manages the connections of a running serve instance through its admin API",
+	}
+
+	options.registerFlags(connectionsCmd)
+
+	connectionsCmd.AddCommand(CmdConnectionsList(options))
+	connectionsCmd.AddCommand(CmdConnectionsAdd(options))
+	connectionsCmd.AddCommand(CmdConnectionsUpdate(options))
+	connectionsCmd.AddCommand(CmdConnectionsRemove(options))
+	connectionsCmd.AddCommand(CmdConnectionsRedrive(options))
+
+	return connectionsCmd
+}
+
+func (options *adminOptions) registerFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(
+		&options.endpoint,
+		"endpoint",
+		"http://localhost:8080",
+		"base URL of the running serve instance",
+	)
+	cmd.PersistentFlags().StringVar(
+		&options.token,
+		"token",
+		os.Getenv("ADMIN__TOKEN"),
+		"admin API token (defaults to $ADMIN__TOKEN)",
+	)
+}
+
+func (options *adminOptions) do(
+	ctx context.Context,
+	method string,
+	path string,
+	payload any,
+	out io.Writer,
+) error {
+	var body io.Reader
+
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrAdminRequestFailed, err)
+		}
+
+		body = bytes.NewReader(encoded)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, adminRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		method,
+		strings.TrimSuffix(options.endpoint, "/")+path,
+		body,
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrAdminRequestFailed, err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+options.token)
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrAdminRequestFailed, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrAdminRequestFailed, err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf(
+			"%w (status=%d): %s",
+			ErrAdminRequestFailed,
+			resp.StatusCode,
+			strings.TrimSpace(string(respBody)),
+		)
+	}
+
+	if len(respBody) > 0 {
+		_, _ = fmt.Fprintln(out, string(respBody))
+	}
+
+	return nil
+}
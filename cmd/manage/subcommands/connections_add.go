@@ -0,0 +1,86 @@
+package subcommands
+
+import (
+	"net/http"
+	"net/url"
+
+	adminhttp "github.com/eser/aya.is-services/pkg/api/adapters/http"
+	"github.com/spf13/cobra"
+)
+
+func CmdConnectionsAdd(options *adminOptions) *cobra.Command {
+	var target adminhttp.ConnectionTargetRequest
+
+	connectionsAddCmd := &cobra.Command{ //nolint:exhaustruct
+		Use:   "add <name>",
+		Short: "Adds a connection",
+		Long:  "Adds and dials a new connection on the running instance",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.do(
+				cmd.Context(),
+				http.MethodPost,
+				"/admin/connections/"+url.PathEscape(args[0]),
+				&target,
+				cmd.OutOrStdout(),
+			)
+		},
+	}
+
+	bindConnectionTargetFlags(connectionsAddCmd, &target)
+
+	return connectionsAddCmd
+}
+
+func CmdConnectionsUpdate(options *adminOptions) *cobra.Command {
+	var target adminhttp.ConnectionTargetRequest
+
+	connectionsUpdateCmd := &cobra.Command{ //nolint:exhaustruct
+		Use:   "update <name>",
+		Short: "Reconfigures a connection",
+		Long:  "Replaces an existing connection on the running instance with a new configuration",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.do(
+				cmd.Context(),
+				http.MethodPut,
+				"/admin/connections/"+url.PathEscape(args[0]),
+				&target,
+				cmd.OutOrStdout(),
+			)
+		},
+	}
+
+	bindConnectionTargetFlags(connectionsUpdateCmd, &target)
+
+	return connectionsUpdateCmd
+}
+
+func bindConnectionTargetFlags(cmd *cobra.Command, target *adminhttp.ConnectionTargetRequest) {
+	properties := map[string]string{}
+
+	flags := cmd.Flags()
+	flags.StringVar(&target.Protocol, "protocol", "", "connection protocol (e.g. postgres, redis)")
+	flags.StringVar(&target.DSN, "dsn", "", "data source name")
+	flags.StringVar(&target.URL, "url", "", "connection URL")
+	flags.StringVar(&target.Host, "host", "", "host name")
+	flags.IntVar(&target.Port, "port", 0, "port number")
+	flags.StringVar(&target.Timeout, "timeout", "", "connection timeout (e.g. 5s)")
+	flags.BoolVar(&target.TLS, "tls", false, "enable TLS")
+	flags.BoolVar(&target.TLSSkipVerify, "tls-skip-verify", false, "skip TLS certificate verification")
+	flags.BoolVar(&target.Lazy, "lazy", false, "defer dialing until first use")
+	flags.StringToStringVar(&properties, "property", nil, "adapter property as key=value (repeatable)")
+
+	_ = cmd.MarkFlagRequired("protocol")
+
+	cmd.PreRun = func(_ *cobra.Command, _ []string) {
+		if len(properties) == 0 {
+			return
+		}
+
+		target.Properties = make(map[string]any, len(properties))
+		for key, value := range properties {
+			target.Properties[key] = value
+		}
+	}
+}
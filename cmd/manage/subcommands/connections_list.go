@@ -0,0 +1,20 @@
+package subcommands
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+func CmdConnectionsList(options *adminOptions) *cobra.Command {
+	connectionsListCmd := &cobra.Command{ //nolint:exhaustruct
+		Use:   "list",
+		Short: "Lists connections",
+		Long:  "Lists the connections registered on the running instance with their states",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.do(cmd.Context(), http.MethodGet, "/admin/connections", nil, cmd.OutOrStdout())
+		},
+	}
+
+	return connectionsListCmd
+}
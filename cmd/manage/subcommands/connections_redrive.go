@@ -0,0 +1,40 @@
+package subcommands
+
+import (
+	"net/http"
+	"net/url"
+
+	adminhttp "github.com/eser/aya.is-services/pkg/api/adapters/http"
+	"github.com/spf13/cobra"
+)
+
+func CmdConnectionsRedrive(options *adminOptions) *cobra.Command {
+	var request adminhttp.RedriveRequest
+
+	connectionsRedriveCmd := &cobra.Command{ //nolint:exhaustruct
+		Use:   "redrive <name> <queue>",
+		Short: "Redrives dead-lettered messages",
+		Long:  "Moves the dead-lettered messages of a queue back to the queue on the running instance",
+		Args:  cobra.ExactArgs(2), //nolint:mnd
+		RunE: func(cmd *cobra.Command, args []string) error {
+			request.Queue = args[1]
+
+			return options.do(
+				cmd.Context(),
+				http.MethodPost,
+				"/admin/connections/"+url.PathEscape(args[0])+"/redrive",
+				&request,
+				cmd.OutOrStdout(),
+			)
+		},
+	}
+
+	connectionsRedriveCmd.Flags().IntVar(
+		&request.Limit,
+		"limit",
+		0,
+		"maximum number of messages to move (0 moves all)",
+	)
+
+	return connectionsRedriveCmd
+}
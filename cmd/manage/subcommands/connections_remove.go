@@ -0,0 +1,28 @@
+package subcommands
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/spf13/cobra"
+)
+
+func CmdConnectionsRemove(options *adminOptions) *cobra.Command {
+	connectionsRemoveCmd := &cobra.Command{ //nolint:exhaustruct
+		Use:   "remove <name>",
+		Short: "Removes a connection",
+		Long:  "Closes and removes a connection from the running instance",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.do(
+				cmd.Context(),
+				http.MethodDelete,
+				"/admin/connections/"+url.PathEscape(args[0]),
+				nil,
+				cmd.OutOrStdout(),
+			)
+		},
+	}
+
+	return connectionsRemoveCmd
+}
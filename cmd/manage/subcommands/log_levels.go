@@ -0,0 +1,35 @@
+package subcommands
+
+import (
+	"net/url"
+
+	"github.com/spf13/cobra"
+)
+
+func CmdLogLevels() *cobra.Command {
+	options := &adminOptions{} //nolint:exhaustruct
+
+	logLevelsCmd := &cobra.Command{ //nolint:exhaustruct
+		Use:   "log-levels",
+		Short: "Manages log levels",
+		Long:  "Changes the global and per-scope log levels of a running serve instance through its admin API",
+	}
+
+	options.registerFlags(logLevelsCmd)
+
+	logLevelsCmd.AddCommand(CmdLogLevelsList(options))
+	logLevelsCmd.AddCommand(CmdLogLevelsSet(options))
+	logLevelsCmd.AddCommand(CmdLogLevelsReset(options))
+
+	return logLevelsCmd
+}
+
+// logLevelsPath returns the admin API path of a scope, or of the global level when
+// the scope is empty.
+func logLevelsPath(scope string) string {
+	if scope == "" {
+		return "/admin/log-levels"
+	}
+
+	return "/admin/log-levels/" + url.PathEscape(scope)
+}
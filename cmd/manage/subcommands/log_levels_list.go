@@ -0,0 +1,20 @@
+package subcommands
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+func CmdLogLevelsList(options *adminOptions) *cobra.Command {
+	logLevelsListCmd := &cobra.Command{ //nolint:exhaustruct
+		Use:   "list",
+		Short: "Lists log levels",
+		Long:  "Lists the global log level and the levels changed at runtime on the running instance",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.do(cmd.Context(), http.MethodGet, "/admin/log-levels", nil, cmd.OutOrStdout())
+		},
+	}
+
+	return logLevelsListCmd
+}
@@ -0,0 +1,24 @@
+package subcommands
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+func CmdLogLevelsReset(options *adminOptions) *cobra.Command {
+	var scope string
+
+	logLevelsResetCmd := &cobra.Command{ //nolint:exhaustruct
+		Use:   "reset",
+		Short: "Resets a log level",
+		Long:  "Restores the configured global log level, or the level of a scope with --scope, on the running instance",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.do(cmd.Context(), http.MethodDelete, logLevelsPath(scope), nil, cmd.OutOrStdout())
+		},
+	}
+
+	logLevelsResetCmd.Flags().StringVar(&scope, "scope", "", "scope to reset, e.g. connfx (defaults to global)")
+
+	return logLevelsResetCmd
+}
@@ -0,0 +1,38 @@
+package subcommands
+
+import (
+	"net/http"
+
+	adminhttp "github.com/eser/aya.is-services/pkg/api/adapters/http"
+	"github.com/spf13/cobra"
+)
+
+func CmdLogLevelsSet(options *adminOptions) *cobra.Command {
+	var (
+		request adminhttp.LogLevelRequest
+		scope   string
+	)
+
+	logLevelsSetCmd := &cobra.Command{ //nolint:exhaustruct
+		Use:   "set <level>",
+		Short: "Changes a log level",
+		Long: "Changes the global log level, or the level of a scope with --scope, on the running instance, " +
+			"e.g. `log-levels set debug --scope connfx --for 10m`",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			request.Level = args[0]
+
+			return options.do(cmd.Context(), http.MethodPut, logLevelsPath(scope), &request, cmd.OutOrStdout())
+		},
+	}
+
+	logLevelsSetCmd.Flags().StringVar(&scope, "scope", "", "scope to change, e.g. connfx (defaults to global)")
+	logLevelsSetCmd.Flags().StringVar(
+		&request.Duration,
+		"for",
+		"",
+		"how long the level lasts, e.g. 10m (defaults to until reset)",
+	)
+
+	return logLevelsSetCmd
+}
@@ -3,7 +3,6 @@ package subcommands
 import (
 	"context"
 
-	"github.com/eser/aya.is-services/pkg/api/adapters/appcontext"
 	"github.com/spf13/cobra"
 )
 
@@ -21,7 +20,7 @@ func CmdProfilesImport() *cobra.Command {
 }
 
 func execProfilesImport(ctx context.Context) error {
-	appContext := appcontext.New()
+	appContext := newAppContext()
 
 	err := appContext.Init(ctx)
 	if err != nil {
@@ -3,7 +3,6 @@ package subcommands
 import (
 	"context"
 
-	"github.com/eser/aya.is-services/pkg/api/adapters/appcontext"
 	"github.com/eser/aya.is-services/pkg/lib/cursors"
 	"github.com/spf13/cobra"
 )
@@ -22,7 +21,7 @@ func CmdProfilesList() *cobra.Command {
 }
 
 func execProfilesList(ctx context.Context) error {
-	appContext := appcontext.New()
+	appContext := newAppContext()
 
 	err := appContext.Init(ctx)
 	if err != nil {
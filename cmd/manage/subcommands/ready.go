@@ -2,33 +2,124 @@ package subcommands
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+	"time"
 
-	"github.com/eser/aya.is-services/pkg/api/adapters/appcontext"
+	apihttp "github.com/eser/aya.is-services/pkg/api/adapters/http"
 	"github.com/spf13/cobra"
 )
 
+const readinessRequestTimeout = 5 * time.Second
+
+var (
+	ErrReadinessRequestFailed = errors.New("readiness request failed")
+	ErrNotReady               = errors.New("service is not ready")
+)
+
 func CmdReady() *cobra.Command {
+	var endpoint string
+
 	readyCmd := &cobra.Command{ //nolint:exhaustruct
 		Use:   "ready",
 		Short: "Checks the readiness of the site",
-		Long:  "Checks the readiness of the site",
+		Long:  "Checks that the configuration loads and the connections open, then asks the running serve instance for its readiness, including the states of its goroutines such as queue consumers", //nolint:lll
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return execReady(cmd.Context())
+			return execReady(cmd.Context(), cmd.OutOrStdout(), endpoint)
 		},
 	}
 
+	readyCmd.Flags().StringVar(
+		&endpoint,
+		"endpoint",
+		"http://localhost:8080",
+		"base URL of the running serve instance",
+	)
+
 	return readyCmd
 }
 
-func execReady(ctx context.Context) error {
-	appContext := appcontext.New()
+func execReady(ctx context.Context, out io.Writer, endpoint string) error {
+	appContext := newAppContext()
 
 	err := appContext.Init(ctx)
 	if err != nil {
 		return err //nolint:wrapcheck
 	}
 
-	appContext.Logger.InfoContext(ctx, "readiness check passed")
+	defer appContext.Connections.Close(ctx) //nolint:errcheck
+
+	response, err := fetchReadiness(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0) //nolint:mnd
+
+	_, _ = fmt.Fprintln(writer, "GOROUTINE\tSTATE\tRESTARTS\tERROR")
+
+	for _, goroutine := range response.Goroutines {
+		_, _ = fmt.Fprintf(
+			writer,
+			"%s\t%s\t%d\t%s\n",
+			goroutine.Name,
+			goroutine.State,
+			goroutine.Restarts,
+			goroutine.Error,
+		)
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if response.Status == apihttp.ReadinessStatusNotReady {
+		return fmt.Errorf("%w (status=%q)", ErrNotReady, response.Status)
+	}
+
+	appContext.Logger.InfoContext(ctx, "readiness check passed", "status", response.Status)
 
 	return nil
 }
+
+// fetchReadiness reads the readiness probe of a running instance, which responds with
+// the same body whether it is ready or not.
+func fetchReadiness(ctx context.Context, endpoint string) (*apihttp.ReadinessResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, readinessRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		strings.TrimSuffix(endpoint, "/")+"/readyz",
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReadinessRequestFailed, err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReadinessRequestFailed, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return nil, fmt.Errorf("%w (status=%d)", ErrReadinessRequestFailed, resp.StatusCode)
+	}
+
+	var response apihttp.ReadinessResponse
+
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReadinessRequestFailed, err)
+	}
+
+	return &response, nil
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log/slog"
+	"os"
 
 	"github.com/eser/aya.is-services/pkg/ajan/processfx"
 	"github.com/eser/aya.is-services/pkg/api/adapters/appcontext"
@@ -12,23 +14,91 @@ import (
 func main() {
 	baseCtx := context.Background()
 
+	configFlags, err := appcontext.NewConfigFlags()
+	if err != nil {
+		panic(err)
+	}
+
+	configFlags.AddTo(flag.CommandLine)
+	flag.Parse()
+
 	appContext := appcontext.New()
+	appContext.ConfigFlags = configFlags
 
-	err := appContext.Init(baseCtx)
+	err = appContext.Init(baseCtx)
 	if err != nil {
 		panic(err)
 	}
 
 	process := processfx.New(baseCtx, appContext.Logger)
+	process.Configure(&appContext.Config.Process)
+
+	err = appContext.RegisterLifecycleHooks(process)
+	if err != nil {
+		panic(err)
+	}
+
+	// The HTTP server starts serving once every module has started, and stops accepting
+	// in the first phase of the shutdown.
+	err = process.AddLifecycleHook(processfx.LifecycleHook{ //nolint:exhaustruct
+		Name:      "http-server",
+		DependsOn: []string{"connections", "config-watcher"},
+		OnReady: func(context.Context) error {
+			process.StartGoroutine("http-server", runHTTPServer(appContext, process))
+
+			return nil
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	startErr := process.Start()
+	if startErr != nil {
+		appContext.Logger.ErrorContext(
+			baseCtx,
+			"[Main] Process start failed",
+			slog.String("module", "main"),
+			slog.Any("error", startErr))
 
-	process.StartGoroutine("http-server", func(ctx context.Context) error {
+		// Shuts down the hooks started so far.
+		process.Cancel()
+	}
+
+	process.Wait()
+	process.Shutdown()
+
+	// Flushes the log file, so it comes after the last log of the shutdown.
+	_ = appContext.Logger.Close()
+
+	if startErr != nil {
+		os.Exit(1)
+	}
+}
+
+func runHTTPServer(
+	appContext *appcontext.AppContext,
+	process *processfx.Process,
+) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
 		cleanup, err := http.Run(
 			ctx,
 			&appContext.Config.HTTP,
+			&appContext.Config.Admin,
+			&appContext.Config.Auth,
+			&appContext.Config.Locales,
 			appContext.Logger,
+			appContext.Connections,
 			appContext.ProfilesService,
 			appContext.StoriesService,
 			appContext.UsersService,
+			appContext.SitemapsService,
+			appContext.CommentsService,
+			appContext.RequestLimits,
+			appContext.ResponseCache,
+			appContext.Idempotency,
+			appContext.CommentRateLimiter,
+			process,
 		)
 		if err != nil {
 			appContext.Logger.ErrorContext(
@@ -36,6 +106,8 @@ func main() {
 				"[Main] HTTP server run failed",
 				slog.String("module", "main"),
 				slog.Any("error", err))
+
+			return nil
 		}
 
 		defer cleanup()
@@ -43,8 +115,5 @@ func main() {
 		<-ctx.Done()
 
 		return nil
-	})
-
-	process.Wait()
-	process.Shutdown()
+	}
 }
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "api_key" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "name" TEXT NOT NULL,
+  "key_prefix" TEXT NOT NULL,
+  "key_hash" TEXT NOT NULL CONSTRAINT "api_key_key_hash_unique" UNIQUE,
+  "scopes" TEXT NOT NULL,
+  "expires_at" TIMESTAMP WITH TIME ZONE,
+  "last_used_at" TIMESTAMP WITH TIME ZONE,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  "revoked_at" TIMESTAMP WITH TIME ZONE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS "api_key";
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "profile_membership_invitation" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "profile_id" CHAR(26) NOT NULL CONSTRAINT "profile_membership_invitation_profile_id_fk" REFERENCES "profile",
+  "member_profile_id" CHAR(26) NOT NULL CONSTRAINT "profile_membership_invitation_member_profile_id_fk" REFERENCES "profile",
+  "kind" TEXT NOT NULL,
+  "token_hash" TEXT NOT NULL CONSTRAINT "profile_membership_invitation_token_hash_unique" UNIQUE,
+  "invited_by_user_id" CHAR(26) NOT NULL CONSTRAINT "profile_membership_invitation_invited_by_user_id_fk" REFERENCES "user",
+  "expires_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+  "accepted_at" TIMESTAMP WITH TIME ZONE,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS "profile_membership_invitation";
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "profile_custom_domain_claim" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "profile_id" CHAR(26) NOT NULL CONSTRAINT "profile_custom_domain_claim_profile_id_fk" REFERENCES "profile",
+  "domain" TEXT NOT NULL,
+  "challenge_token" TEXT NOT NULL,
+  "status" TEXT NOT NULL,
+  "attempts" INTEGER DEFAULT 0 NOT NULL,
+  "last_error" TEXT,
+  "last_checked_at" TIMESTAMP WITH TIME ZONE,
+  "verified_at" TIMESTAMP WITH TIME ZONE,
+  "expires_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS "profile_custom_domain_claim_status_idx" ON "profile_custom_domain_claim" ("status");
+
+-- +goose Down
+DROP TABLE IF EXISTS "profile_custom_domain_claim";
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "profile_follow" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "follower_profile_id" CHAR(26) NOT NULL CONSTRAINT "profile_follow_follower_profile_id_fk" REFERENCES "profile",
+  "followed_profile_id" CHAR(26) NOT NULL CONSTRAINT "profile_follow_followed_profile_id_fk" REFERENCES "profile",
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  CONSTRAINT "profile_follow_follower_profile_id_followed_profile_id_unique" UNIQUE ("follower_profile_id", "followed_profile_id")
+);
+
+CREATE INDEX IF NOT EXISTS "profile_follow_followed_profile_id_idx" ON "profile_follow" ("followed_profile_id");
+
+-- +goose Down
+DROP TABLE IF EXISTS "profile_follow";
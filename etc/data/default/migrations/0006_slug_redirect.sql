@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "slug_redirect" (
+  "kind" TEXT NOT NULL,
+  "old_slug" TEXT NOT NULL,
+  "target_id" CHAR(26) NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  PRIMARY KEY ("kind", "old_slug")
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS "slug_redirect";
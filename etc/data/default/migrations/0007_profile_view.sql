@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "profile_view" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "profile_id" CHAR(26) NOT NULL CONSTRAINT "profile_view_profile_id_fk" REFERENCES "profile",
+  "story_id" CHAR(26) CONSTRAINT "profile_view_story_id_fk" REFERENCES "story",
+  "visitor_hash" TEXT NOT NULL,
+  "viewed_on" DATE NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "profile_view_profile_id_visitor_hash_viewed_on_unique" ON "profile_view" ("profile_id", "visitor_hash", "viewed_on")
+WHERE "story_id" IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS "profile_view_story_id_visitor_hash_viewed_on_unique" ON "profile_view" ("story_id", "visitor_hash", "viewed_on")
+WHERE "story_id" IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS "profile_view_profile_id_viewed_on_idx" ON "profile_view" ("profile_id", "viewed_on");
+
+-- +goose Down
+DROP TABLE IF EXISTS "profile_view";
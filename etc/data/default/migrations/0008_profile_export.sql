@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "profile_export" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "profile_id" CHAR(26) NOT NULL CONSTRAINT "profile_export_profile_id_fk" REFERENCES "profile",
+  "requested_by_user_id" CHAR(26) NOT NULL CONSTRAINT "profile_export_requested_by_user_id_fk" REFERENCES "user",
+  "status" TEXT NOT NULL,
+  "attempts" INTEGER DEFAULT 0 NOT NULL,
+  "object_key" TEXT,
+  "size_bytes" BIGINT,
+  "last_error" TEXT,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  "started_at" TIMESTAMP WITH TIME ZONE,
+  "completed_at" TIMESTAMP WITH TIME ZONE,
+  "expires_at" TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS "profile_export_profile_id_created_at_idx" ON "profile_export" ("profile_id", "created_at");
+CREATE INDEX IF NOT EXISTS "profile_export_status_created_at_idx" ON "profile_export" ("status", "created_at");
+
+-- +goose Down
+DROP TABLE IF EXISTS "profile_export";
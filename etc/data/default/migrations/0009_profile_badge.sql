@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "profile_badge" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "profile_id" CHAR(26) NOT NULL CONSTRAINT "profile_badge_profile_id_fk" REFERENCES "profile",
+  "kind" TEXT NOT NULL,
+  "source" TEXT NOT NULL,
+  "note" TEXT,
+  "granted_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  "revoked_at" TIMESTAMP WITH TIME ZONE,
+  "revoked_by" TEXT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "profile_badge_profile_id_kind_unique" ON "profile_badge" ("profile_id", "kind")
+WHERE "revoked_at" IS NULL;
+
+CREATE INDEX IF NOT EXISTS "profile_badge_kind_source_idx" ON "profile_badge" ("kind", "source")
+WHERE "revoked_at" IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS "profile_badge";
@@ -0,0 +1,18 @@
+-- +goose Up
+ALTER TABLE "story" ADD COLUMN IF NOT EXISTS "publish_at" TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS "story_publish_at_idx" ON "story" ("publish_at")
+WHERE "publish_at" IS NOT NULL AND "deleted_at" IS NULL;
+
+CREATE TABLE IF NOT EXISTS "job_lock" (
+  "name" TEXT NOT NULL PRIMARY KEY,
+  "owner" TEXT NOT NULL,
+  "expires_at" TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS "job_lock";
+
+DROP INDEX IF EXISTS "story_publish_at_idx";
+
+ALTER TABLE "story" DROP COLUMN IF EXISTS "publish_at";
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "story_revision" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "story_id" CHAR(26) NOT NULL CONSTRAINT "story_revision_story_id_fk" REFERENCES "story",
+  "locale_code" CHAR(12) NOT NULL,
+  "author_user_id" CHAR(26) NOT NULL CONSTRAINT "story_revision_author_user_id_fk" REFERENCES "user",
+  "title" TEXT NOT NULL,
+  "summary" TEXT NOT NULL,
+  "content" TEXT NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS "story_revision_story_id_locale_code_idx" ON "story_revision" ("story_id", "locale_code", "id" DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS "story_revision";
@@ -0,0 +1,36 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "story_comment" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "story_id" CHAR(26) NOT NULL CONSTRAINT "story_comment_story_id_fk" REFERENCES "story",
+  "parent_id" CHAR(26) CONSTRAINT "story_comment_parent_id_fk" REFERENCES "story_comment",
+  "author_user_id" CHAR(26) NOT NULL CONSTRAINT "story_comment_author_user_id_fk" REFERENCES "user",
+  "content" TEXT NOT NULL,
+  "status" TEXT NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  "updated_at" TIMESTAMP WITH TIME ZONE,
+  "deleted_at" TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS "story_comment_story_id_parent_id_idx" ON "story_comment" ("story_id", "parent_id", "id");
+
+CREATE INDEX IF NOT EXISTS "story_comment_parent_id_idx" ON "story_comment" ("parent_id")
+WHERE "deleted_at" IS NULL;
+
+CREATE TABLE IF NOT EXISTS "story_comment_report" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "comment_id" CHAR(26) NOT NULL CONSTRAINT "story_comment_report_comment_id_fk" REFERENCES "story_comment",
+  "reporter_user_id" CHAR(26) NOT NULL CONSTRAINT "story_comment_report_reporter_user_id_fk" REFERENCES "user",
+  "reason" TEXT NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  "resolved_at" TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "story_comment_report_comment_id_reporter_user_id_unique" ON "story_comment_report" ("comment_id", "reporter_user_id");
+
+CREATE INDEX IF NOT EXISTS "story_comment_report_comment_id_idx" ON "story_comment_report" ("comment_id")
+WHERE "resolved_at" IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS "story_comment_report";
+
+DROP TABLE IF EXISTS "story_comment";
@@ -0,0 +1,24 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "reaction" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "target_kind" TEXT NOT NULL,
+  "target_id" CHAR(26) NOT NULL,
+  "user_id" CHAR(26) NOT NULL CONSTRAINT "reaction_user_id_fk" REFERENCES "user",
+  "kind" TEXT NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "reaction_target_kind_target_id_user_id_kind_unique" ON "reaction" ("target_kind", "target_id", "user_id", "kind");
+
+CREATE TABLE IF NOT EXISTS "reaction_count" (
+  "target_kind" TEXT NOT NULL,
+  "target_id" CHAR(26) NOT NULL,
+  "kind" TEXT NOT NULL,
+  "count" BIGINT NOT NULL,
+  PRIMARY KEY ("target_kind", "target_id", "kind")
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS "reaction_count";
+
+DROP TABLE IF EXISTS "reaction";
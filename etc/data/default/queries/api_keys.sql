@@ -0,0 +1,25 @@
+-- name: GetAPIKeyByHash :one
+SELECT *
+FROM "api_key"
+WHERE key_hash = sqlc.arg(key_hash)
+LIMIT 1;
+
+-- name: ListAPIKeys :many
+SELECT *
+FROM "api_key"
+ORDER BY created_at DESC;
+
+-- name: CreateAPIKey :exec
+INSERT INTO "api_key" (id, name, key_prefix, key_hash, scopes, expires_at, created_at)
+VALUES (sqlc.arg(id), sqlc.arg(name), sqlc.arg(key_prefix), sqlc.arg(key_hash), sqlc.arg(scopes), sqlc.arg(expires_at), sqlc.arg(created_at));
+
+-- name: RevokeAPIKey :execrows
+UPDATE "api_key"
+SET revoked_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND revoked_at IS NULL;
+
+-- name: UpdateAPIKeyLastUsedAt :exec
+UPDATE "api_key"
+SET last_used_at = sqlc.arg(last_used_at)
+WHERE id = sqlc.arg(id);
@@ -0,0 +1,74 @@
+-- name: CreateCustomDomainClaim :exec
+INSERT INTO "profile_custom_domain_claim" (
+    id,
+    profile_id,
+    domain,
+    challenge_token,
+    status,
+    expires_at,
+    created_at
+  )
+VALUES (
+    sqlc.arg(id),
+    sqlc.arg(profile_id),
+    sqlc.arg(domain),
+    sqlc.arg(challenge_token),
+    sqlc.arg(status),
+    sqlc.arg(expires_at),
+    sqlc.arg(created_at)
+  );
+
+-- name: SupersedeCustomDomainClaims :execrows
+UPDATE "profile_custom_domain_claim"
+SET status = 'superseded'
+WHERE profile_id = sqlc.arg(profile_id)
+  AND status = 'pending';
+
+-- name: GetLatestCustomDomainClaimByProfileID :one
+SELECT *
+FROM "profile_custom_domain_claim"
+WHERE profile_id = sqlc.arg(profile_id)
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: ListPendingCustomDomainClaims :many
+SELECT sqlc.embed(c), p.slug AS profile_slug, p.custom_domain AS current_domain
+FROM "profile_custom_domain_claim" c
+  INNER JOIN "profile" p ON p.id = c.profile_id
+  AND p.deleted_at IS NULL
+WHERE c.status = 'pending'
+ORDER BY c.last_checked_at NULLS FIRST
+LIMIT sqlc.arg(limit_count);
+
+-- name: RecordCustomDomainClaimCheck :execrows
+UPDATE "profile_custom_domain_claim"
+SET status = sqlc.arg(status),
+  attempts = attempts + 1,
+  last_error = sqlc.narg(last_error),
+  last_checked_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND status = 'pending';
+
+-- name: VerifyCustomDomainClaim :execrows
+UPDATE "profile_custom_domain_claim"
+SET status = 'verified',
+  attempts = attempts + 1,
+  last_error = NULL,
+  last_checked_at = NOW(),
+  verified_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND status = 'pending';
+
+-- name: SetProfileCustomDomain :execrows
+UPDATE "profile"
+SET custom_domain = sqlc.arg(custom_domain),
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND deleted_at IS NULL
+  AND NOT EXISTS (
+    SELECT 1
+    FROM "profile" other
+    WHERE other.custom_domain = sqlc.arg(custom_domain)
+      AND other.id <> sqlc.arg(id)
+      AND other.deleted_at IS NULL
+  );
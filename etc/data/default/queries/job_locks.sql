@@ -0,0 +1,16 @@
+-- name: TryLockJob :execrows
+INSERT INTO "job_lock" (name, owner, expires_at)
+VALUES (
+    sqlc.arg(name),
+    sqlc.arg(owner),
+    NOW() + (sqlc.arg(ttl_seconds)::INTEGER * INTERVAL '1 second')
+  )
+ON CONFLICT (name) DO UPDATE
+SET owner = EXCLUDED.owner,
+  expires_at = EXCLUDED.expires_at
+WHERE "job_lock".expires_at < NOW();
+
+-- name: UnlockJob :execrows
+DELETE FROM "job_lock"
+WHERE name = sqlc.arg(name)
+  AND owner = sqlc.arg(owner);
@@ -0,0 +1,70 @@
+-- name: GrantProfileBadge :execrows
+INSERT INTO "profile_badge" (id, profile_id, kind, source, note)
+VALUES (sqlc.arg(id), sqlc.arg(profile_id), sqlc.arg(kind), sqlc.arg(source), sqlc.narg(note))
+ON CONFLICT (profile_id, kind) WHERE revoked_at IS NULL DO NOTHING;
+
+-- name: AwardProfileBadge :execrows
+INSERT INTO "profile_badge" (id, profile_id, kind, source)
+SELECT sqlc.arg(id), sqlc.arg(profile_id), sqlc.arg(kind), 'rule'
+WHERE NOT EXISTS (
+  SELECT 1
+  FROM "profile_badge"
+  WHERE profile_id = sqlc.arg(profile_id)
+    AND kind = sqlc.arg(kind)
+    AND revoked_by = 'admin'
+)
+ON CONFLICT (profile_id, kind) WHERE revoked_at IS NULL DO NOTHING;
+
+-- name: RevokeProfileBadge :execrows
+UPDATE "profile_badge"
+SET revoked_at = NOW(),
+  revoked_by = sqlc.arg(revoked_by)
+WHERE profile_id = sqlc.arg(profile_id)
+  AND kind = sqlc.arg(kind)
+  AND (sqlc.narg(filter_source)::TEXT IS NULL OR source = sqlc.narg(filter_source)::TEXT)
+  AND revoked_at IS NULL;
+
+-- name: ListProfileBadges :many
+SELECT *
+FROM "profile_badge"
+WHERE profile_id = sqlc.arg(profile_id)
+  AND revoked_at IS NULL
+ORDER BY granted_at, kind;
+
+-- name: ListProfileBadgeHoldersBySource :many
+SELECT p.id, p.slug
+FROM "profile_badge" pb
+  INNER JOIN "profile" p ON p.id = pb.profile_id
+  AND p.deleted_at IS NULL
+WHERE pb.kind = sqlc.arg(kind)
+  AND pb.source = sqlc.arg(source)
+  AND pb.revoked_at IS NULL;
+
+-- name: ListVerifiedBadgeCandidates :many
+SELECT p.id, p.slug
+FROM "profile" p
+WHERE p.custom_domain IS NOT NULL
+  AND p.deleted_at IS NULL;
+
+-- name: ListCoreContributorBadgeCandidates :many
+SELECT DISTINCT p.id, p.slug
+FROM "profile_membership" pm
+  INNER JOIN "profile" p ON p.id = pm.member_profile_id
+  AND p.kind = 'individual'
+  AND p.deleted_at IS NULL
+  INNER JOIN "profile" pp ON pp.id = pm.profile_id
+  AND pp.kind = 'product'
+  AND pp.deleted_at IS NULL
+WHERE pm.kind IN ('owner', 'maintainer')
+  AND (pm.finished_at IS NULL OR pm.finished_at > NOW())
+  AND pm.deleted_at IS NULL;
+
+-- name: ListEventSpeakerBadgeCandidates :many
+SELECT DISTINCT p.id, p.slug
+FROM "event_attendance" ea
+  INNER JOIN "event" e ON e.id = ea.event_id
+  AND e.deleted_at IS NULL
+  INNER JOIN "profile" p ON p.id = ea.profile_id
+  AND p.deleted_at IS NULL
+WHERE ea.kind = 'speaker'
+  AND ea.deleted_at IS NULL;
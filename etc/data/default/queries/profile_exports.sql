@@ -0,0 +1,126 @@
+-- name: CreateProfileExport :exec
+INSERT INTO "profile_export" (id, profile_id, requested_by_user_id, status)
+VALUES (sqlc.arg(id), sqlc.arg(profile_id), sqlc.arg(requested_by_user_id), 'pending');
+
+-- name: GetProfileExport :one
+SELECT *
+FROM "profile_export"
+WHERE id = sqlc.arg(id)
+  AND profile_id = sqlc.arg(profile_id)
+LIMIT 1;
+
+-- name: GetActiveProfileExport :one
+SELECT *
+FROM "profile_export"
+WHERE profile_id = sqlc.arg(profile_id)
+  AND status IN ('pending', 'running')
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: ClaimProfileExports :many
+UPDATE "profile_export"
+SET status = 'running',
+  attempts = attempts + 1,
+  started_at = NOW()
+WHERE id IN (
+    SELECT pe.id
+    FROM "profile_export" pe
+    WHERE pe.status = 'pending'
+      OR (pe.status = 'running' AND pe.started_at < sqlc.arg(stale_before))
+    ORDER BY pe.created_at
+    LIMIT sqlc.arg(limit_count)
+    FOR UPDATE SKIP LOCKED
+  )
+RETURNING *;
+
+-- name: CompleteProfileExport :execrows
+UPDATE "profile_export"
+SET status = 'completed',
+  object_key = sqlc.arg(object_key),
+  size_bytes = sqlc.arg(size_bytes),
+  last_error = NULL,
+  completed_at = NOW(),
+  expires_at = sqlc.arg(expires_at)
+WHERE id = sqlc.arg(id)
+  AND status = 'running';
+
+-- name: RecordProfileExportFailure :execrows
+UPDATE "profile_export"
+SET status = sqlc.arg(status),
+  last_error = sqlc.arg(last_error)
+WHERE id = sqlc.arg(id)
+  AND status = 'running';
+
+-- name: ListExpiredProfileExports :many
+SELECT *
+FROM "profile_export"
+WHERE status = 'completed'
+  AND expires_at < NOW()
+ORDER BY expires_at
+LIMIT sqlc.arg(limit_count);
+
+-- name: ExpireProfileExport :execrows
+UPDATE "profile_export"
+SET status = 'expired'
+WHERE id = sqlc.arg(id)
+  AND status = 'completed';
+
+-- name: GetProfileForExport :one
+SELECT *
+FROM "profile"
+WHERE id = sqlc.arg(id)
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListProfileTxsForExport :many
+SELECT *
+FROM "profile_tx"
+WHERE profile_id = sqlc.arg(profile_id)
+ORDER BY locale_code;
+
+-- name: ListProfilePagesForExport :many
+SELECT *
+FROM "profile_page"
+WHERE profile_id = sqlc.arg(profile_id)
+  AND deleted_at IS NULL
+ORDER BY "order";
+
+-- name: ListProfilePageTxsForExport :many
+SELECT ppt.*
+FROM "profile_page_tx" ppt
+  INNER JOIN "profile_page" pp ON pp.id = ppt.profile_page_id
+  AND pp.deleted_at IS NULL
+WHERE pp.profile_id = sqlc.arg(profile_id)
+ORDER BY ppt.profile_page_id, ppt.locale_code;
+
+-- name: ListProfileLinksForExport :many
+SELECT id, kind, "order", is_verified, is_hidden, public_id, uri, title, created_at
+FROM "profile_link"
+WHERE profile_id = sqlc.arg(profile_id)
+  AND deleted_at IS NULL
+ORDER BY "order";
+
+-- name: ListStoriesForExport :many
+SELECT *
+FROM "story"
+WHERE author_profile_id = sqlc.arg(author_profile_id)
+  AND deleted_at IS NULL
+ORDER BY created_at;
+
+-- name: ListStoryTxsForExport :many
+SELECT st.*
+FROM "story_tx" st
+  INNER JOIN "story" s ON s.id = st.story_id
+  AND s.deleted_at IS NULL
+WHERE s.author_profile_id = sqlc.arg(author_profile_id)
+ORDER BY st.story_id, st.locale_code;
+
+-- name: ListProfileMembershipsForExport :many
+SELECT pm.id, p.slug AS profile_slug, mp.slug AS member_profile_slug, pm.kind,
+  pm.started_at, pm.finished_at, pm.created_at
+FROM "profile_membership" pm
+  INNER JOIN "profile" p ON p.id = pm.profile_id
+  INNER JOIN "profile" mp ON mp.id = pm.member_profile_id
+WHERE (pm.profile_id = sqlc.arg(profile_id) OR pm.member_profile_id = sqlc.arg(profile_id))
+  AND pm.deleted_at IS NULL
+ORDER BY pm.created_at;
@@ -0,0 +1,33 @@
+-- name: FollowProfile :execrows
+INSERT INTO "profile_follow" (id, follower_profile_id, followed_profile_id)
+VALUES (sqlc.arg(id), sqlc.arg(follower_profile_id), sqlc.arg(followed_profile_id))
+ON CONFLICT (follower_profile_id, followed_profile_id) DO NOTHING;
+
+-- name: UnfollowProfile :execrows
+DELETE FROM "profile_follow"
+WHERE follower_profile_id = sqlc.arg(follower_profile_id)
+  AND followed_profile_id = sqlc.arg(followed_profile_id);
+
+-- name: ListProfileFollowers :many
+SELECT sqlc.embed(pf), sqlc.embed(p), sqlc.embed(pt)
+FROM "profile_follow" pf
+  INNER JOIN "profile" p ON p.id = pf.follower_profile_id
+  AND p.deleted_at IS NULL
+  INNER JOIN "profile_tx" pt ON pt.profile_id = p.id
+  AND pt.locale_code = sqlc.arg(locale_code)
+WHERE pf.followed_profile_id = sqlc.arg(profile_id)
+  AND (sqlc.narg(cursor_id)::CHAR(26) IS NULL OR pf.id < sqlc.narg(cursor_id)::CHAR(26))
+ORDER BY pf.id DESC
+LIMIT sqlc.arg(limit_count);
+
+-- name: ListProfileFollowing :many
+SELECT sqlc.embed(pf), sqlc.embed(p), sqlc.embed(pt)
+FROM "profile_follow" pf
+  INNER JOIN "profile" p ON p.id = pf.followed_profile_id
+  AND p.deleted_at IS NULL
+  INNER JOIN "profile_tx" pt ON pt.profile_id = p.id
+  AND pt.locale_code = sqlc.arg(locale_code)
+WHERE pf.follower_profile_id = sqlc.arg(profile_id)
+  AND (sqlc.narg(cursor_id)::CHAR(26) IS NULL OR pf.id < sqlc.narg(cursor_id)::CHAR(26))
+ORDER BY pf.id DESC
+LIMIT sqlc.arg(limit_count);
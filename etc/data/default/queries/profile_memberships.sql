@@ -0,0 +1,84 @@
+-- name: GetProfileMembershipKind :one
+SELECT kind
+FROM "profile_membership"
+WHERE profile_id = sqlc.arg(profile_id)
+  AND member_profile_id = sqlc.arg(member_profile_id)
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: CountProfileMembershipsOfKind :one
+SELECT COUNT(*)
+FROM "profile_membership"
+WHERE profile_id = sqlc.arg(profile_id)
+  AND kind = sqlc.arg(kind)
+  AND deleted_at IS NULL;
+
+-- name: UpsertProfileMembership :exec
+INSERT INTO "profile_membership" (id, profile_id, member_profile_id, kind, started_at)
+VALUES (
+    sqlc.arg(id),
+    sqlc.arg(profile_id),
+    sqlc.arg(member_profile_id),
+    sqlc.arg(kind),
+    NOW()
+  )
+ON CONFLICT (profile_id, member_profile_id) DO UPDATE SET kind = sqlc.arg(kind),
+  started_at = NOW(),
+  finished_at = NULL,
+  updated_at = NOW(),
+  deleted_at = NULL;
+
+-- name: UpdateProfileMembershipKind :execrows
+UPDATE "profile_membership"
+SET kind = sqlc.arg(kind),
+  updated_at = NOW()
+WHERE profile_id = sqlc.arg(profile_id)
+  AND member_profile_id = sqlc.arg(member_profile_id)
+  AND deleted_at IS NULL;
+
+-- name: RemoveProfileMembership :execrows
+UPDATE "profile_membership"
+SET finished_at = NOW(),
+  deleted_at = NOW()
+WHERE profile_id = sqlc.arg(profile_id)
+  AND member_profile_id = sqlc.arg(member_profile_id)
+  AND deleted_at IS NULL;
+
+-- name: CreateProfileMembershipInvitation :exec
+INSERT INTO "profile_membership_invitation" (
+    id,
+    profile_id,
+    member_profile_id,
+    kind,
+    token_hash,
+    invited_by_user_id,
+    expires_at,
+    created_at
+  )
+VALUES (
+    sqlc.arg(id),
+    sqlc.arg(profile_id),
+    sqlc.arg(member_profile_id),
+    sqlc.arg(kind),
+    sqlc.arg(token_hash),
+    sqlc.arg(invited_by_user_id),
+    sqlc.arg(expires_at),
+    sqlc.arg(created_at)
+  );
+
+-- name: GetProfileMembershipInvitationByTokenHash :one
+SELECT sqlc.embed(pmi), p.slug AS profile_slug, mp.slug AS member_profile_slug
+FROM "profile_membership_invitation" pmi
+  INNER JOIN "profile" p ON p.id = pmi.profile_id
+  AND p.deleted_at IS NULL
+  INNER JOIN "profile" mp ON mp.id = pmi.member_profile_id
+  AND mp.deleted_at IS NULL
+WHERE pmi.token_hash = sqlc.arg(token_hash)
+  AND pmi.accepted_at IS NULL
+LIMIT 1;
+
+-- name: AcceptProfileMembershipInvitation :execrows
+UPDATE "profile_membership_invitation"
+SET accepted_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND accepted_at IS NULL;
@@ -0,0 +1,40 @@
+-- name: RecordProfileView :execrows
+INSERT INTO "profile_view" (id, profile_id, story_id, visitor_hash, viewed_on)
+SELECT sqlc.arg(id), p.id, NULL, sqlc.arg(visitor_hash), sqlc.arg(viewed_on)
+FROM "profile" p
+WHERE p.slug = sqlc.arg(slug)
+  AND p.deleted_at IS NULL
+LIMIT 1
+ON CONFLICT DO NOTHING;
+
+-- name: RecordStoryView :execrows
+INSERT INTO "profile_view" (id, profile_id, story_id, visitor_hash, viewed_on)
+SELECT sqlc.arg(id), s.author_profile_id, s.id, sqlc.arg(visitor_hash), sqlc.arg(viewed_on)
+FROM "story" s
+WHERE s.slug = sqlc.arg(slug)
+  AND s.author_profile_id IS NOT NULL
+  AND s.deleted_at IS NULL
+LIMIT 1
+ON CONFLICT DO NOTHING;
+
+-- name: GetProfileViewSummary :one
+SELECT
+  COUNT(*) FILTER (WHERE story_id IS NULL) AS profile_views,
+  COUNT(*) FILTER (WHERE story_id IS NOT NULL) AS story_views,
+  COUNT(DISTINCT story_id) AS viewed_stories
+FROM "profile_view"
+WHERE profile_id = sqlc.arg(profile_id)
+  AND viewed_on >= sqlc.arg(from_date)::DATE
+  AND viewed_on <= sqlc.arg(to_date)::DATE;
+
+-- name: ListProfileViewsByDay :many
+SELECT
+  viewed_on,
+  COUNT(*) FILTER (WHERE story_id IS NULL) AS profile_views,
+  COUNT(*) FILTER (WHERE story_id IS NOT NULL) AS story_views
+FROM "profile_view"
+WHERE profile_id = sqlc.arg(profile_id)
+  AND viewed_on >= sqlc.arg(from_date)::DATE
+  AND viewed_on <= sqlc.arg(to_date)::DATE
+GROUP BY viewed_on
+ORDER BY viewed_on;
@@ -12,6 +12,13 @@ WHERE custom_domain = sqlc.arg(custom_domain)
   AND deleted_at IS NULL
 LIMIT 1;
 
+-- name: GetProfileKindByID :one
+SELECT kind
+FROM "profile"
+WHERE id = sqlc.arg(id)
+  AND deleted_at IS NULL
+LIMIT 1;
+
 -- name: GetProfileByID :one
 SELECT sqlc.embed(p), sqlc.embed(pt)
 FROM "profile" p
@@ -30,12 +37,31 @@ WHERE (sqlc.narg(filter_kind)::TEXT IS NULL OR p.kind = ANY(string_to_array(sqlc
   AND p.deleted_at IS NULL;
 
 -- name: CreateProfile :exec
-INSERT INTO "profile" (id, slug)
-VALUES (sqlc.arg(id), sqlc.arg(slug));
+INSERT INTO "profile" (id, slug, kind, profile_picture_uri, pronouns)
+VALUES (
+    sqlc.arg(id),
+    sqlc.arg(slug),
+    sqlc.arg(kind),
+    sqlc.narg(profile_picture_uri),
+    sqlc.narg(pronouns)
+  );
+
+-- name: UpsertProfileTx :exec
+INSERT INTO "profile_tx" (profile_id, locale_code, title, description)
+VALUES (
+    sqlc.arg(profile_id),
+    sqlc.arg(locale_code),
+    sqlc.arg(title),
+    sqlc.arg(description)
+  )
+ON CONFLICT (profile_id, locale_code) DO UPDATE SET title = sqlc.arg(title), description = sqlc.arg(description);
 
 -- name: UpdateProfile :execrows
 UPDATE "profile"
-SET slug = sqlc.arg(slug)
+SET slug = COALESCE(sqlc.narg(slug), slug),
+  profile_picture_uri = COALESCE(sqlc.narg(profile_picture_uri), profile_picture_uri),
+  pronouns = COALESCE(sqlc.narg(pronouns), pronouns),
+  updated_at = NOW()
 WHERE id = sqlc.arg(id)
   AND deleted_at IS NULL;
 
@@ -45,6 +71,48 @@ SET deleted_at = NOW()
 WHERE id = sqlc.arg(id)
   AND deleted_at IS NULL;
 
+-- name: GetUserIndividualProfileID :one
+SELECT individual_profile_id
+FROM "user"
+WHERE id = sqlc.arg(user_id)
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: SetUserIndividualProfileID :execrows
+UPDATE "user"
+SET individual_profile_id = sqlc.arg(individual_profile_id),
+  updated_at = NOW()
+WHERE id = sqlc.arg(user_id)
+  AND individual_profile_id IS NULL
+  AND deleted_at IS NULL;
+
+-- name: ClearUserIndividualProfileID :execrows
+UPDATE "user"
+SET individual_profile_id = NULL,
+  updated_at = NOW()
+WHERE individual_profile_id = sqlc.arg(individual_profile_id);
+
+-- name: CreateProfileMembershipForUser :execrows
+INSERT INTO "profile_membership" (id, profile_id, member_profile_id, kind, started_at)
+SELECT sqlc.arg(id), sqlc.arg(profile_id), u.individual_profile_id, sqlc.arg(kind), NOW()
+FROM "user" u
+WHERE u.id = sqlc.arg(user_id)
+  AND u.individual_profile_id IS NOT NULL
+  AND u.deleted_at IS NULL;
+
+-- name: IsProfileOwnedByUser :one
+SELECT EXISTS (
+    SELECT 1
+    FROM "user" u
+      LEFT JOIN "profile_membership" pm ON pm.member_profile_id = u.individual_profile_id
+      AND pm.profile_id = sqlc.arg(profile_id)
+      AND pm.kind = 'owner'
+      AND pm.deleted_at IS NULL
+    WHERE u.id = sqlc.arg(user_id)
+      AND u.deleted_at IS NULL
+      AND (u.individual_profile_id = sqlc.arg(profile_id) OR pm.id IS NOT NULL)
+  ) AS is_owner;
+
 -- name: ListProfileLinksForKind :many
 SELECT pl.*
 FROM "profile_link" pl
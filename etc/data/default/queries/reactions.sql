@@ -0,0 +1,38 @@
+-- name: AddReaction :execrows
+INSERT INTO "reaction" (id, target_kind, target_id, user_id, kind)
+VALUES (
+    sqlc.arg(id),
+    sqlc.arg(target_kind),
+    sqlc.arg(target_id),
+    sqlc.arg(user_id),
+    sqlc.arg(kind)
+  )
+ON CONFLICT (target_kind, target_id, user_id, kind) DO NOTHING;
+
+-- name: RemoveReaction :execrows
+DELETE FROM "reaction"
+WHERE target_kind = sqlc.arg(target_kind)
+  AND target_id = sqlc.arg(target_id)
+  AND user_id = sqlc.arg(user_id)
+  AND kind = sqlc.arg(kind);
+
+-- name: IncrementReactionCount :exec
+INSERT INTO "reaction_count" (target_kind, target_id, kind, count)
+VALUES (sqlc.arg(target_kind), sqlc.arg(target_id), sqlc.arg(kind), 1)
+ON CONFLICT (target_kind, target_id, kind) DO UPDATE
+SET count = "reaction_count".count + 1;
+
+-- name: DecrementReactionCount :exec
+UPDATE "reaction_count"
+SET count = GREATEST(count - 1, 0)
+WHERE target_kind = sqlc.arg(target_kind)
+  AND target_id = sqlc.arg(target_id)
+  AND kind = sqlc.arg(kind);
+
+-- name: ListReactionCounts :many
+SELECT target_id, kind, count
+FROM "reaction_count"
+WHERE target_kind = sqlc.arg(target_kind)
+  AND target_id = ANY(sqlc.arg(target_ids)::CHAR(26)[])
+  AND count > 0
+ORDER BY target_id, count DESC, kind;
@@ -0,0 +1,42 @@
+-- name: ListSitemapProfiles :many
+SELECT
+  p.slug,
+  pt.locale_code,
+  COALESCE(p.updated_at, p.created_at) AS last_modified_at
+FROM "profile" p
+  INNER JOIN "profile_tx" pt ON pt.profile_id = p.id
+WHERE p.deleted_at IS NULL
+ORDER BY p.slug, pt.locale_code;
+
+-- name: ListSitemapProfilePages :many
+SELECT
+  p.slug AS profile_slug,
+  pp.slug,
+  ppt.locale_code,
+  COALESCE(pp.updated_at, pp.created_at) AS last_modified_at
+FROM "profile_page" pp
+  INNER JOIN "profile" p ON p.id = pp.profile_id
+  AND p.deleted_at IS NULL
+  INNER JOIN "profile_page_tx" ppt ON ppt.profile_page_id = pp.id
+WHERE pp.deleted_at IS NULL
+ORDER BY p.slug, pp."order", pp.slug, ppt.locale_code;
+
+-- name: ListSitemapStories :many
+SELECT
+  s.slug,
+  st.locale_code,
+  COALESCE(s.updated_at, s.created_at) AS last_modified_at
+FROM "story" s
+  INNER JOIN "profile" p ON p.id = s.author_profile_id
+  AND p.deleted_at IS NULL
+  INNER JOIN "story_tx" st ON st.story_id = s.id
+WHERE s.deleted_at IS NULL
+  AND EXISTS (
+    SELECT 1
+    FROM "story_publication" sp
+      INNER JOIN "profile" p2 ON p2.id = sp.profile_id
+      AND p2.deleted_at IS NULL
+    WHERE sp.story_id = s.id
+      AND sp.deleted_at IS NULL
+  )
+ORDER BY s.slug, st.locale_code;
@@ -0,0 +1,24 @@
+-- name: UpsertSlugRedirect :exec
+INSERT INTO "slug_redirect" (kind, old_slug, target_id)
+VALUES (sqlc.arg(kind), sqlc.arg(old_slug), sqlc.arg(target_id))
+ON CONFLICT (kind, old_slug) DO UPDATE
+SET target_id = EXCLUDED.target_id,
+  created_at = NOW();
+
+-- name: GetProfileSlugRedirect :one
+SELECT p.slug
+FROM "slug_redirect" sr
+  INNER JOIN "profile" p ON p.id = sr.target_id
+  AND p.deleted_at IS NULL
+WHERE sr.kind = 'profile'
+  AND sr.old_slug = sqlc.arg(old_slug)
+LIMIT 1;
+
+-- name: GetStorySlugRedirect :one
+SELECT s.slug
+FROM "slug_redirect" sr
+  INNER JOIN "story" s ON s.id = sr.target_id
+  AND s.deleted_at IS NULL
+WHERE sr.kind = 'story'
+  AND sr.old_slug = sqlc.arg(old_slug)
+LIMIT 1;
@@ -82,3 +82,153 @@ WHERE
   AND (sqlc.narg(filter_author_profile_id)::CHAR(26) IS NULL OR s.author_profile_id = sqlc.narg(filter_author_profile_id)::CHAR(26))
   AND s.deleted_at IS NULL
 ORDER BY s.created_at DESC;
+
+-- name: ListStoriesOfFollowedProfiles :many
+SELECT
+  sqlc.embed(s),
+  sqlc.embed(st),
+  sqlc.embed(p1),
+  sqlc.embed(p1t),
+  pb.publications
+FROM "story" s
+  INNER JOIN "story_tx" st ON st.story_id = s.id
+  AND st.locale_code = sqlc.arg(locale_code)
+  LEFT JOIN "profile" p1 ON p1.id = s.author_profile_id
+  AND p1.deleted_at IS NULL
+  INNER JOIN "profile_tx" p1t ON p1t.profile_id = p1.id
+  AND p1t.locale_code = sqlc.arg(locale_code)
+  LEFT JOIN LATERAL (
+    SELECT JSONB_AGG(
+      JSONB_BUILD_OBJECT('profile', row_to_json(p2), 'profile_tx', row_to_json(p2t))
+    ) AS "publications"
+    FROM story_publication sp
+      INNER JOIN "profile" p2 ON p2.id = sp.profile_id
+      AND p2.deleted_at IS NULL
+      INNER JOIN "profile_tx" p2t ON p2t.profile_id = p2.id
+      AND p2t.locale_code = sqlc.arg(locale_code)
+    WHERE sp.story_id = s.id
+      AND sp.deleted_at IS NULL
+  ) pb ON TRUE
+WHERE
+  pb.publications IS NOT NULL
+  AND (
+    s.author_profile_id IN (
+      SELECT pf.followed_profile_id
+      FROM "profile_follow" pf
+      WHERE pf.follower_profile_id = sqlc.arg(follower_profile_id)
+    )
+    OR EXISTS (
+      SELECT 1
+      FROM "story_publication" fsp
+        INNER JOIN "profile_follow" fpf ON fpf.followed_profile_id = fsp.profile_id
+        AND fpf.follower_profile_id = sqlc.arg(follower_profile_id)
+      WHERE fsp.story_id = s.id
+        AND fsp.deleted_at IS NULL
+    )
+  )
+  AND (sqlc.narg(cursor_id)::CHAR(26) IS NULL OR s.id < sqlc.narg(cursor_id)::CHAR(26))
+  AND s.deleted_at IS NULL
+ORDER BY s.id DESC
+LIMIT sqlc.arg(limit_count);
+
+-- name: GetAuthoredStoryBySlug :one
+SELECT
+  s.id,
+  s.status,
+  p.id AS author_profile_id,
+  p.slug AS author_profile_slug
+FROM "story" s
+  INNER JOIN "profile" p ON p.id = s.author_profile_id
+  AND p.deleted_at IS NULL
+WHERE s.slug = sqlc.arg(slug)
+  AND s.deleted_at IS NULL
+LIMIT 1;
+
+-- name: CreateStory :exec
+INSERT INTO "story" (id, author_profile_id, slug, kind, status, story_picture_uri, title, summary, content)
+VALUES (
+    sqlc.arg(id),
+    sqlc.arg(author_profile_id),
+    sqlc.arg(slug),
+    sqlc.arg(kind),
+    sqlc.arg(status),
+    sqlc.narg(story_picture_uri),
+    sqlc.arg(title),
+    sqlc.arg(summary),
+    sqlc.arg(content)
+  );
+
+-- name: UpsertStoryTx :exec
+INSERT INTO "story_tx" (story_id, locale_code, title, summary, content)
+VALUES (
+    sqlc.arg(story_id),
+    sqlc.arg(locale_code),
+    sqlc.arg(title),
+    sqlc.arg(summary),
+    sqlc.arg(content)
+  )
+ON CONFLICT (story_id, locale_code) DO UPDATE SET title = sqlc.arg(title), summary = sqlc.arg(summary), content = sqlc.arg(content);
+
+-- name: UpdateStory :execrows
+UPDATE "story"
+SET slug = COALESCE(sqlc.narg(slug), slug),
+  story_picture_uri = COALESCE(sqlc.narg(story_picture_uri), story_picture_uri),
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND deleted_at IS NULL;
+
+-- name: UpdateStoryStatus :execrows
+UPDATE "story"
+SET status = sqlc.arg(status),
+  publish_at = CASE WHEN sqlc.arg(status)::TEXT = 'published' THEN NULL ELSE publish_at END,
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND status = sqlc.arg(current_status)
+  AND deleted_at IS NULL;
+
+-- name: UpsertStoryPublication :exec
+INSERT INTO "story_publication" (id, story_id, profile_id, kind)
+VALUES (
+    sqlc.arg(id),
+    sqlc.arg(story_id),
+    sqlc.arg(profile_id),
+    sqlc.arg(kind)
+  )
+ON CONFLICT (story_id, profile_id, kind) DO UPDATE SET deleted_at = NULL, updated_at = NOW();
+
+-- name: RemoveStoryPublication :execrows
+UPDATE "story_publication"
+SET deleted_at = NOW()
+WHERE story_id = sqlc.arg(story_id)
+  AND profile_id = sqlc.arg(profile_id)
+  AND kind = sqlc.arg(kind)
+  AND deleted_at IS NULL;
+
+-- name: RemoveStory :execrows
+UPDATE "story"
+SET deleted_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND deleted_at IS NULL;
+
+-- name: SetStoryPublishAt :execrows
+UPDATE "story"
+SET publish_at = sqlc.narg(publish_at),
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND status IN ('draft', 'in_review')
+  AND deleted_at IS NULL;
+
+-- name: ListDueStories :many
+SELECT
+  s.id,
+  s.status,
+  p.id AS author_profile_id,
+  p.slug AS author_profile_slug
+FROM "story" s
+  INNER JOIN "profile" p ON p.id = s.author_profile_id
+  AND p.deleted_at IS NULL
+WHERE s.publish_at <= sqlc.arg(due_at)
+  AND s.status IN ('draft', 'in_review')
+  AND s.deleted_at IS NULL
+ORDER BY s.publish_at
+LIMIT sqlc.arg(limit_count);
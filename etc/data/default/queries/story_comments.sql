@@ -0,0 +1,112 @@
+-- name: CreateStoryComment :exec
+INSERT INTO "story_comment" (id, story_id, parent_id, author_user_id, content, status)
+VALUES (
+    sqlc.arg(id),
+    sqlc.arg(story_id),
+    sqlc.narg(parent_id),
+    sqlc.arg(author_user_id),
+    sqlc.arg(content),
+    sqlc.arg(status)
+  );
+
+-- name: GetStoryComment :one
+SELECT sqlc.embed(c),
+  u.name AS author_name,
+  p.slug AS author_profile_slug,
+  (
+    SELECT COUNT(*)
+    FROM "story_comment" r
+    WHERE r.parent_id = c.id
+      AND r.deleted_at IS NULL
+  ) AS reply_count,
+  (
+    SELECT COUNT(*)
+    FROM "story_comment_report" cr
+    WHERE cr.comment_id = c.id
+      AND cr.resolved_at IS NULL
+  ) AS report_count
+FROM "story_comment" c
+  INNER JOIN "user" u ON u.id = c.author_user_id
+  LEFT JOIN "profile" p ON p.id = u.individual_profile_id
+  AND p.deleted_at IS NULL
+WHERE c.id = sqlc.arg(id)
+  AND c.story_id = sqlc.arg(story_id)
+  AND c.deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListStoryComments :many
+SELECT sqlc.embed(c),
+  u.name AS author_name,
+  p.slug AS author_profile_slug,
+  (
+    SELECT COUNT(*)
+    FROM "story_comment" r
+    WHERE r.parent_id = c.id
+      AND r.deleted_at IS NULL
+  ) AS reply_count,
+  (
+    SELECT COUNT(*)
+    FROM "story_comment_report" cr
+    WHERE cr.comment_id = c.id
+      AND cr.resolved_at IS NULL
+  ) AS report_count
+FROM "story_comment" c
+  INNER JOIN "user" u ON u.id = c.author_user_id
+  LEFT JOIN "profile" p ON p.id = u.individual_profile_id
+  AND p.deleted_at IS NULL
+WHERE c.story_id = sqlc.arg(story_id)
+  AND (
+    (sqlc.narg(filter_parent_id)::CHAR(26) IS NULL AND c.parent_id IS NULL)
+    OR c.parent_id = sqlc.narg(filter_parent_id)::CHAR(26)
+  )
+  AND (
+    c.deleted_at IS NULL
+    OR EXISTS (
+      SELECT 1
+      FROM "story_comment" r
+      WHERE r.parent_id = c.id
+        AND r.deleted_at IS NULL
+    )
+  )
+  AND (
+    NOT sqlc.arg(only_reported)::BOOLEAN
+    OR EXISTS (
+      SELECT 1
+      FROM "story_comment_report" cr
+      WHERE cr.comment_id = c.id
+        AND cr.resolved_at IS NULL
+    )
+  )
+  AND (sqlc.narg(cursor_id)::CHAR(26) IS NULL OR c.id > sqlc.narg(cursor_id)::CHAR(26))
+ORDER BY c.id
+LIMIT sqlc.arg(limit_count);
+
+-- name: UpdateStoryCommentStatus :execrows
+UPDATE "story_comment"
+SET status = sqlc.arg(status),
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND deleted_at IS NULL;
+
+-- name: RemoveStoryComment :execrows
+UPDATE "story_comment"
+SET status = 'deleted',
+  deleted_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND deleted_at IS NULL;
+
+-- name: CreateStoryCommentReport :execrows
+INSERT INTO "story_comment_report" (id, comment_id, reporter_user_id, reason)
+VALUES (
+    sqlc.arg(id),
+    sqlc.arg(comment_id),
+    sqlc.arg(reporter_user_id),
+    sqlc.arg(reason)
+  )
+ON CONFLICT (comment_id, reporter_user_id) DO NOTHING;
+
+-- name: ResolveStoryCommentReports :execrows
+UPDATE "story_comment_report"
+SET resolved_at = NOW()
+WHERE comment_id = sqlc.arg(comment_id)
+  AND resolved_at IS NULL;
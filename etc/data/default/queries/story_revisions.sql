@@ -0,0 +1,39 @@
+-- name: CreateStoryRevision :exec
+INSERT INTO "story_revision" (id, story_id, locale_code, author_user_id, title, summary, content)
+VALUES (
+    sqlc.arg(id),
+    sqlc.arg(story_id),
+    sqlc.arg(locale_code),
+    sqlc.arg(author_user_id),
+    sqlc.arg(title),
+    sqlc.arg(summary),
+    sqlc.arg(content)
+  );
+
+-- name: ListStoryRevisions :many
+SELECT *
+FROM "story_revision"
+WHERE story_id = sqlc.arg(story_id)
+  AND locale_code = sqlc.arg(locale_code)
+ORDER BY id DESC;
+
+-- name: GetStoryRevision :one
+SELECT *
+FROM "story_revision"
+WHERE id = sqlc.arg(id)
+  AND story_id = sqlc.arg(story_id)
+  AND locale_code = sqlc.arg(locale_code)
+LIMIT 1;
+
+-- name: PruneStoryRevisions :execrows
+DELETE FROM "story_revision"
+WHERE story_id = sqlc.arg(story_id)
+  AND locale_code = sqlc.arg(locale_code)
+  AND id NOT IN (
+    SELECT id
+    FROM "story_revision"
+    WHERE story_id = sqlc.arg(story_id)
+      AND locale_code = sqlc.arg(locale_code)
+    ORDER BY id DESC
+    LIMIT sqlc.arg(keep_count)
+  );
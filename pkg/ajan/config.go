@@ -5,6 +5,7 @@ import (
 	"github.com/eser/aya.is-services/pkg/ajan/httpclient"
 	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
 	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/ajan/processfx"
 )
 
 type BaseConfig struct {
@@ -16,4 +17,5 @@ type BaseConfig struct {
 	Log        logfx.Config      `conf:"log"`
 	HTTP       httpfx.Config     `conf:"http"`
 	HTTPClient httpclient.Config `conf:"http_client"`
+	Process    processfx.Config  `conf:"process"`
 }
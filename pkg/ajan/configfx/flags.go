@@ -0,0 +1,187 @@
+package configfx
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/lib"
+	"github.com/spf13/pflag"
+)
+
+const FlagSeparator = "-"
+
+// ConfigFlags holds a command-line flag for each config value of a struct, named
+// after its key, e.g. --http-addr for http__addr. FromFlags applies the flags that are
+// set on the command line, so they override the files and the environment.
+type ConfigFlags struct {
+	flags []*configFlag
+}
+
+// configFlag is a flag.Value and a pflag.Value; values are parsed while loading, like
+// the values of the other resources.
+type configFlag struct {
+	key      string
+	name     string
+	usage    string
+	typeName string
+	value    string
+	isBool   bool
+	isSet    bool
+}
+
+// NewFlags creates the flags of the config values of i, a pointer to a struct. Maps
+// and slices of structs have no flags.
+func (cl *ConfigManager) NewFlags(i any) (*ConfigFlags, error) {
+	meta, err := cl.LoadMeta(i)
+	if err != nil {
+		return nil, err
+	}
+
+	flags := &ConfigFlags{flags: make([]*configFlag, 0)}
+
+	err = flags.collect(meta, "", make(map[string]struct{}))
+	if err != nil {
+		return nil, err
+	}
+
+	return flags, nil
+}
+
+// FromFlags sets the config values of the flags that are set. A nil flags sets nothing.
+func (cl *ConfigManager) FromFlags(flags *ConfigFlags) ConfigResource {
+	return func(target *map[string]any) error {
+		if flags == nil {
+			return nil
+		}
+
+		for _, f := range flags.flags {
+			if f.isSet {
+				lib.CaseInsensitiveSet(target, f.key, f.value)
+			}
+		}
+
+		return nil
+	}
+}
+
+// FlagName returns the flag name of a config key, e.g. http-addr for http__addr.
+func FlagName(key string) string {
+	name := strings.ReplaceAll(strings.ToLower(key), Separator, FlagSeparator)
+
+	return strings.ReplaceAll(name, "_", FlagSeparator)
+}
+
+// AddTo defines the flags on a flag set of the standard library. Flags whose names
+// are already defined are skipped.
+func (f *ConfigFlags) AddTo(flags *flag.FlagSet) {
+	for _, configFlag := range f.flags {
+		if flags.Lookup(configFlag.name) != nil {
+			continue
+		}
+
+		flags.Var(configFlag, configFlag.name, configFlag.usage)
+	}
+}
+
+// AddToPFlags defines the flags on a flag set of pflag, such as the persistent flags
+// of a cobra command. Flags whose names are already defined are skipped.
+func (f *ConfigFlags) AddToPFlags(flags *pflag.FlagSet) {
+	for _, configFlag := range f.flags {
+		if flags.Lookup(configFlag.name) != nil {
+			continue
+		}
+
+		defined := flags.VarPF(configFlag, configFlag.name, "", configFlag.usage)
+
+		if configFlag.isBool {
+			defined.NoOptDefVal = "true"
+		}
+	}
+}
+
+func (f *ConfigFlags) collect(meta ConfigItemMeta, prefix string, names map[string]struct{}) error {
+	for _, child := range meta.Children {
+		key := prefix + child.Name
+
+		switch {
+		case child.Type.Kind() == reflect.Struct:
+			err := f.collect(child, key+Separator, names)
+			if err != nil {
+				return err
+			}
+		case child.Type.Kind() == reflect.Ptr && child.Type.Elem().Kind() == reflect.Struct:
+			children, err := reflectMeta(reflect.New(child.Type.Elem()).Elem())
+			if err != nil {
+				return err
+			}
+
+			err = f.collect(ConfigItemMeta{Children: children}, key+Separator, names) //nolint:exhaustruct
+			if err != nil {
+				return err
+			}
+		case child.Type.Kind() == reflect.Map,
+			child.Type.Kind() == reflect.Slice && child.Type.Elem().Kind() == reflect.Struct:
+			continue
+		default:
+			name := FlagName(key)
+
+			// The first of the keys that share a flag name gets the flag.
+			if _, exists := names[name]; exists {
+				continue
+			}
+
+			names[name] = struct{}{}
+
+			f.flags = append(f.flags, &configFlag{
+				key:      key,
+				name:     name,
+				usage:    fmt.Sprintf("overrides the config value %s", key),
+				typeName: flagTypeName(child.Type),
+				value:    child.DefaultValue,
+				isBool:   child.Type.Kind() == reflect.Bool,
+				isSet:    false,
+			})
+		}
+	}
+
+	return nil
+}
+
+func flagTypeName(fieldType reflect.Type) string {
+	switch {
+	case fieldType == reflect.TypeFor[time.Duration]():
+		return "duration"
+	case fieldType.Kind() == reflect.Ptr:
+		return flagTypeName(fieldType.Elem())
+	case fieldType.Kind() == reflect.Slice:
+		return flagTypeName(fieldType.Elem()) + "s"
+	default:
+		return fieldType.Kind().String()
+	}
+}
+
+func (f *configFlag) String() string {
+	if f == nil {
+		return ""
+	}
+
+	return f.value
+}
+
+func (f *configFlag) Set(value string) error {
+	f.value = value
+	f.isSet = true
+
+	return nil
+}
+
+func (f *configFlag) Type() string {
+	return f.typeName
+}
+
+func (f *configFlag) IsBoolFlag() bool {
+	return f.isBool
+}
@@ -0,0 +1,123 @@
+package configfx_test
+
+import (
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/configfx"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type TestFlagsConfig struct {
+	Labels map[string]string `conf:"labels"`
+	TLS    *TestConfigTLS    `conf:"tls"`
+	HTTP   struct {
+		Addr    string        `conf:"addr"    default:":8080"`
+		Timeout time.Duration `conf:"timeout" default:"5s"`
+	} `conf:"http"`
+	Log struct {
+		Level string `conf:"level" default:"INFO"`
+	} `conf:"log"`
+	Origins    []string `conf:"origins"`
+	Port       int      `conf:"port"       default:"8080"`
+	SelfSigned bool     `conf:"self_signed"`
+}
+
+func TestFlags(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should override files with the stdlib flags that are set", func(t *testing.T) {
+		t.Parallel()
+
+		cl := configfx.NewConfigManager()
+
+		flags, err := cl.NewFlags(&TestFlagsConfig{}) //nolint:exhaustruct
+		require.NoError(t, err)
+
+		fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		flags.AddTo(fs)
+
+		assert.NotNil(t, fs.Lookup("http-addr"))
+		assert.NotNil(t, fs.Lookup("tls-cert-file"))
+		assert.Nil(t, fs.Lookup("labels"))
+		assert.Equal(t, "INFO", fs.Lookup("log-level").DefValue)
+
+		err = fs.Parse([]string{"--log-level", "DEBUG", "--self-signed", "--origins=a.com,b.com"})
+		require.NoError(t, err)
+
+		config := &TestFlagsConfig{} //nolint:exhaustruct
+
+		err = cl.Load(
+			config,
+			cl.FromJSONString(`{"log": {"level": "WARN"}, "port": 9090, "http": {"addr": ":80"}}`),
+			cl.FromFlags(flags),
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, "DEBUG", config.Log.Level)
+		assert.True(t, config.SelfSigned)
+		assert.Equal(t, []string{"a.com", "b.com"}, config.Origins)
+		assert.Equal(t, ":80", config.HTTP.Addr)
+		assert.Equal(t, 9090, config.Port)
+		assert.Nil(t, config.TLS)
+	})
+
+	t.Run("should define cobra flags", func(t *testing.T) {
+		t.Parallel()
+
+		cl := configfx.NewConfigManager()
+
+		flags, err := cl.NewFlags(&TestFlagsConfig{}) //nolint:exhaustruct
+		require.NoError(t, err)
+
+		fs := pflag.NewFlagSet("manage", pflag.ContinueOnError)
+		fs.String("port", "", "an existing flag")
+		flags.AddToPFlags(fs)
+
+		assert.Equal(t, "duration", fs.Lookup("http-timeout").Value.Type())
+		assert.Equal(t, "an existing flag", fs.Lookup("port").Usage)
+
+		err = fs.Parse([]string{"--http-timeout=30s", "--tls-cert-file", "cert.pem", "--self-signed"})
+		require.NoError(t, err)
+
+		config := &TestFlagsConfig{} //nolint:exhaustruct
+
+		err = cl.Load(config, cl.FromFlags(flags))
+		require.NoError(t, err)
+
+		assert.Equal(t, 30*time.Second, config.HTTP.Timeout)
+		assert.True(t, config.SelfSigned)
+		require.NotNil(t, config.TLS)
+		assert.Equal(t, "cert.pem", config.TLS.CertFile)
+	})
+
+	t.Run("should report invalid flag values while loading", func(t *testing.T) {
+		t.Parallel()
+
+		cl := configfx.NewConfigManager()
+
+		flags, err := cl.NewFlags(&TestFlagsConfig{}) //nolint:exhaustruct
+		require.NoError(t, err)
+
+		fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+		flags.AddTo(fs)
+
+		require.NoError(t, fs.Parse([]string{"-port", "eighty"}))
+
+		err = cl.Load(&TestFlagsConfig{}, cl.FromFlags(flags)) //nolint:exhaustruct
+		require.ErrorIs(t, err, configfx.ErrInvalidConfigValue)
+	})
+}
+
+func TestFlagName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "http-addr", configfx.FlagName("http__addr"))
+	assert.Equal(t, "locales-supported", configfx.FlagName("LOCALES__SUPPORTED"))
+	assert.Equal(t, "http-client-max-retries", configfx.FlagName("http_client__max_retries"))
+}
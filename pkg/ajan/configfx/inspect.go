@@ -0,0 +1,282 @@
+package configfx
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Names of the sources of config values, as reported by Inspect.
+const (
+	SourceDefault  = "default"
+	SourceFile     = "file"
+	SourceEnv      = "env"
+	SourceFlag     = "flag"
+	SourceRemote   = "remote"
+	SourceOverride = "override"
+	// SourceSecret marks ResolveSecrets; the values it resolves keep the source of their
+	// reference and are masked.
+	SourceSecret = "secret"
+
+	MaskedValue = "********"
+)
+
+// SecretKeyWords mark the keys whose values are masked by Inspect when any of them is
+// part of the key, e.g. auth__jwt_secret.
+var SecretKeyWords = []string{ //nolint:gochecknoglobals
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"apikey",
+	"api_key",
+	"private_key",
+	"credential",
+}
+
+// URLKeyWords mark the keys whose values are masked as a whole by Inspect when they
+// are not URLs or key/value DSNs it can mask the password of, e.g. conn__dsn.
+var URLKeyWords = []string{ //nolint:gochecknoglobals
+	"dsn",
+	"url",
+	"uri",
+}
+
+// passwordPairPattern matches the passwords of key/value DSNs, such as those of libpq
+// ("password=..." or "password='...'") and ADO.NET ("Password=...;").
+var passwordPairPattern = regexp.MustCompile( //nolint:gochecknoglobals
+	`(?i)\b(password|passwd|pwd)(\s*=\s*)('(?:[^'\\]|\\.)*'|[^\s;]*)`,
+)
+
+// ConfigSource names a resource, so Inspect can report the values it sets.
+type ConfigSource struct {
+	Resource ConfigResource
+	Name     string
+}
+
+// ConfigValue is a value of a loaded config with the source that set it.
+type ConfigValue struct {
+	Key    string
+	Value  string
+	Source string
+	// IsMasked is set when Value hides a secret, or the password of a URL.
+	IsMasked bool
+}
+
+// Inspect loads i like Load from the resources of sources, and returns each value of
+// i with the name of the last source that changed it, or SourceDefault for the values
+// no source set. Secrets are masked. The values are returned with the error when i
+// fails to bind or validate, so misconfigured values can be told apart.
+func (cl *ConfigManager) Inspect(i any, sources ...ConfigSource) ([]ConfigValue, error) {
+	meta, err := cl.LoadMeta(i)
+	if err != nil {
+		return nil, err
+	}
+
+	target := make(map[string]any)
+	origins := make(map[string]string)
+	resolved := make(map[string]struct{})
+
+	for _, source := range sources {
+		previous := flattenValues(&target)
+
+		err := source.Resource(&target)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range diffValues(previous, flattenValues(&target)) {
+			if source.Name == SourceSecret {
+				resolved[key] = struct{}{}
+
+				continue
+			}
+
+			origins[key] = source.Name
+		}
+	}
+
+	loadErr := reflectSet(meta, "", &target)
+
+	values := make([]ConfigValue, 0)
+
+	inspectChildren(meta.Children, "", func(key string, value string) {
+		lowered := strings.ToLower(key)
+
+		source, exists := origins[lowered]
+		if !exists {
+			source = SourceDefault
+		}
+
+		_, isResolved := resolved[lowered]
+		masked, isMasked := maskValue(lowered, value, isResolved)
+
+		values = append(values, ConfigValue{
+			Key:      key,
+			Value:    masked,
+			Source:   source,
+			IsMasked: isMasked,
+		})
+	})
+
+	return values, loadErr
+}
+
+// InspectDefaults inspects i loaded like LoadDefaults, with overrides such as a
+// FromFlags resource named SourceFlag.
+func (cl *ConfigManager) InspectDefaults(i any, overrides ...ConfigSource) ([]ConfigValue, error) {
+	return cl.Inspect(i, cl.defaultSources(overrides)...)
+}
+
+func resourcesOf(sources []ConfigSource) []ConfigResource {
+	resources := make([]ConfigResource, len(sources))
+
+	for i, source := range sources {
+		resources[i] = source.Resource
+	}
+
+	return resources
+}
+
+// inspectChildren visits the values of the fields of children, with the keys they are
+// loaded from, e.g. conn__targets__default__dsn for an entry of a map of structs.
+func inspectChildren(children []ConfigItemMeta, prefix string, visit func(key string, value string)) {
+	for _, child := range children {
+		key := prefix + child.Name
+
+		switch {
+		case child.Type.Kind() == reflect.Struct:
+			inspectChildren(child.Children, key+Separator, visit)
+		case child.Type.Kind() == reflect.Ptr && child.Type.Elem().Kind() == reflect.Struct:
+			if child.Field.IsNil() {
+				continue
+			}
+
+			elements, _ := reflectMeta(child.Field.Elem())
+
+			inspectChildren(elements, key+Separator, visit)
+		case child.Type.Kind() == reflect.Map:
+			inspectMap(child, key, visit)
+		case child.Type.Kind() == reflect.Slice && child.Type.Elem().Kind() == reflect.Struct:
+			for index := range child.Field.Len() {
+				elements, _ := reflectMeta(child.Field.Index(index))
+
+				inspectChildren(elements, key+Separator+strconv.Itoa(index)+Separator, visit)
+			}
+		default:
+			visit(key, formatValue(child.Field, child.ElementSeparator))
+		}
+	}
+}
+
+func inspectMap(child ConfigItemMeta, key string, visit func(key string, value string)) {
+	mapKeys := child.Field.MapKeys()
+
+	slices.SortFunc(mapKeys, func(a, b reflect.Value) int {
+		return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+	})
+
+	for _, mapKey := range mapKeys {
+		entryKey := key + Separator + fmt.Sprint(mapKey.Interface())
+		entry := child.Field.MapIndex(mapKey)
+
+		if child.Type.Elem().Kind() != reflect.Struct {
+			visit(entryKey, formatValue(entry, child.ElementSeparator))
+
+			continue
+		}
+
+		// Map entries are not addressable; their fields are read from a copy.
+		element := reflect.New(child.Type.Elem()).Elem()
+		element.Set(entry)
+
+		elements, _ := reflectMeta(element)
+
+		inspectChildren(elements, entryKey+Separator, visit)
+	}
+}
+
+func formatValue(value reflect.Value, separator string) string {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return ""
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() == reflect.Slice {
+		if separator == "" {
+			separator = DefaultElementSeparator
+		}
+
+		elements := make([]string, value.Len())
+
+		for i := range value.Len() {
+			elements[i] = formatValue(value.Index(i), separator)
+		}
+
+		return strings.Join(elements, separator)
+	}
+
+	return fmt.Sprint(value.Interface())
+}
+
+// maskValue masks the values of secret keys and of resolved secret references, and
+// the passwords of URLs and key/value DSNs.
+func maskValue(key string, value string, isResolved bool) (string, bool) {
+	if value == "" {
+		return value, false
+	}
+
+	if isResolved {
+		return MaskedValue, true
+	}
+
+	if containsAny(key, SecretKeyWords) {
+		return MaskedValue, true
+	}
+
+	// URLs may carry passwords in their queries as well.
+	isMasked := passwordPairPattern.MatchString(value)
+	if isMasked {
+		value = passwordPairPattern.ReplaceAllString(value, "${1}${2}"+MaskedValue)
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil {
+		// The password of a URL that cannot be parsed cannot be told apart.
+		if strings.Contains(value, "://") || containsAny(key, URLKeyWords) {
+			return MaskedValue, true
+		}
+
+		return value, isMasked
+	}
+
+	if parsed.User == nil {
+		return value, isMasked
+	}
+
+	if _, hasPassword := parsed.User.Password(); !hasPassword {
+		return value, isMasked
+	}
+
+	// The mask is set after formatting, as URLs escape its characters.
+	parsed.User = url.UserPassword(parsed.User.Username(), "")
+
+	return strings.Replace(parsed.String(), ":@", ":"+MaskedValue+"@", 1), true
+}
+
+func containsAny(key string, words []string) bool {
+	for _, word := range words {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+
+	return false
+}
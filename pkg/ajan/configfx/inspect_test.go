@@ -0,0 +1,135 @@
+package configfx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/configfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type TestInspectedConfig struct {
+	Targets map[string]struct {
+		DSN string `conf:"dsn"`
+	} `conf:"targets"`
+	Auth struct {
+		JWTSecret string `conf:"jwt_secret"`
+		Issuer    string `conf:"issuer"`
+	} `conf:"auth"`
+	Host     string   `conf:"host"     default:"localhost"`
+	Password string   `conf:"db_pass"`
+	Origins  []string `conf:"origins"`
+	Port     int      `conf:"port"     conf-validate:"max=65535" default:"8080"`
+}
+
+func TestInspect(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should report the source of each value and mask secrets", func(t *testing.T) {
+		t.Parallel()
+
+		cl := configfx.NewConfigManager()
+		cl.RegisterSecretProvider("mem", configfx.SecretProviderFunc(
+			func(_ context.Context, _ string, _ string) (string, error) {
+				return "s3cr3t", nil
+			},
+		))
+
+		config := &TestInspectedConfig{} //nolint:exhaustruct
+
+		values, err := cl.Inspect(
+			config,
+			configfx.ConfigSource{Name: configfx.SourceFile, Resource: cl.FromJSONString(`{
+				"targets": {"main": {"dsn": "postgres://app:hunter2@db/app"}},
+				"auth": {"jwt_secret": "plain", "issuer": "aya"},
+				"origins": ["a.com", "b.com"],
+				"port": 9090
+			}`)},
+			configfx.ConfigSource{Name: configfx.SourceEnv, Resource: cl.FromJSONString(
+				`{"port": 9091, "db_pass": "mem://db"}`,
+			)},
+			configfx.ConfigSource{Name: configfx.SourceSecret, Resource: cl.ResolveSecrets(t.Context())},
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, 9091, config.Port)
+		assert.Equal(t, "s3cr3t", config.Password)
+
+		assert.Equal(t, []configfx.ConfigValue{
+			{Key: "targets__main__dsn", Value: "postgres://app:********@db/app", Source: "file", IsMasked: true},
+			{Key: "auth__jwt_secret", Value: "********", Source: "file", IsMasked: true},
+			{Key: "auth__issuer", Value: "aya", Source: "file", IsMasked: false},
+			{Key: "host", Value: "localhost", Source: "default", IsMasked: false},
+			{Key: "db_pass", Value: "********", Source: "env", IsMasked: true},
+			{Key: "origins", Value: "a.com,b.com", Source: "file", IsMasked: false},
+			{Key: "port", Value: "9091", Source: "env", IsMasked: false},
+		}, values)
+	})
+
+	t.Run("should mask the passwords of key/value DSNs and unparsable URLs", func(t *testing.T) {
+		t.Parallel()
+
+		cl := configfx.NewConfigManager()
+
+		values, err := cl.Inspect(
+			&TestInspectedConfig{}, //nolint:exhaustruct
+			configfx.ConfigSource{Name: configfx.SourceEnv, Resource: cl.FromJSONString(`{
+				"targets": {
+					"libpq": {"dsn": "host=db user=app password=hunter2 dbname=app"},
+					"quoted": {"dsn": "host=db password='hunter 2' user=app"},
+					"query": {"dsn": "postgres://db/app?user=app&password=hunter2"},
+					"broken": {"dsn": "postgres://app:hunter2@db:port/app"},
+					"plain": {"dsn": "host=db user=app"}
+				},
+				"auth": {"issuer": "https://app:hunter2@[::1/"}
+			}`)},
+		)
+		require.NoError(t, err)
+
+		for _, expected := range []configfx.ConfigValue{
+			{
+				Key:      "targets__libpq__dsn",
+				Value:    "host=db user=app password=******** dbname=app",
+				Source:   "env",
+				IsMasked: true,
+			},
+			{
+				Key:      "targets__quoted__dsn",
+				Value:    "host=db password=******** user=app",
+				Source:   "env",
+				IsMasked: true,
+			},
+			{
+				Key:      "targets__query__dsn",
+				Value:    "postgres://db/app?user=app&password=********",
+				Source:   "env",
+				IsMasked: true,
+			},
+			{Key: "targets__broken__dsn", Value: "********", Source: "env", IsMasked: true},
+			{Key: "targets__plain__dsn", Value: "host=db user=app", Source: "env", IsMasked: false},
+			{Key: "auth__issuer", Value: "********", Source: "env", IsMasked: true},
+		} {
+			assert.Contains(t, values, expected)
+		}
+	})
+
+	t.Run("should return the values with the validation error", func(t *testing.T) {
+		t.Parallel()
+
+		cl := configfx.NewConfigManager()
+
+		values, err := cl.Inspect(
+			&TestInspectedConfig{}, //nolint:exhaustruct
+			configfx.ConfigSource{Name: configfx.SourceFlag, Resource: cl.FromJSONString(`{"port": 70000}`)},
+		)
+		require.ErrorIs(t, err, configfx.ErrConfigValidationFailed)
+
+		assert.Contains(t, values, configfx.ConfigValue{
+			Key:      "port",
+			Value:    "70000",
+			Source:   "flag",
+			IsMasked: false,
+		})
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 )
 
 var ErrParsingError = errors.New("parsing error")
@@ -22,11 +23,18 @@ func ParseBytes(data []byte, out *map[string]any) error {
 		return fmt.Errorf("%w: %w", ErrParsingError, err)
 	}
 
-	flattenJSON(raw, "", out)
+	Flatten(raw, out)
 
 	return nil
 }
 
+// Flatten copies the values of a nested map into out, with the keys of nested maps
+// joined by Separator. Other parsers producing nested maps share it, so every file
+// format is flattened the same way.
+func Flatten(input map[string]any, out *map[string]any) {
+	flattenJSON(input, "", out)
+}
+
 func Parse(m *map[string]any, r io.Reader) error { //nolint:varnamelen
 	var buf bytes.Buffer
 
@@ -49,7 +57,10 @@ func tryParseFile(m *map[string]any, filename string) (err error) { //nolint:var
 	}
 
 	defer func() {
-		err = file.Close()
+		closeErr := file.Close()
+		if err == nil {
+			err = closeErr
+		}
 	}()
 
 	return Parse(m, file)
@@ -88,8 +99,24 @@ func flattenJSON(input map[string]any, currentNode string, out *map[string]any)
 		// if false {
 		arrValue, isArray := value.([]any)
 		if isArray {
-			for _, arrValue := range arrValue {
+			elements := make([]string, 0, len(arrValue))
+
+			for i, arrValue := range arrValue {
+				// Maps are keyed by their index, e.g. targets__0__name.
+				if mapValue, isMap := arrValue.(map[string]any); isMap {
+					flattenJSON(mapValue, prefix+key+Separator+strconv.Itoa(i), out)
+
+					continue
+				}
+
 				(*out)[prefix+key+Separator+fmt.Sprintf("%v", arrValue)] = ""
+
+				elements = append(elements, fmt.Sprintf("%v", arrValue))
+			}
+
+			// The elements are also kept in order under the key itself, for slices.
+			if len(elements) > 0 {
+				(*out)[prefix+key] = elements
 			}
 
 			continue
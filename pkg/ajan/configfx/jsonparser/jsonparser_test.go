@@ -24,7 +24,7 @@ func TestTryParseFiles(t *testing.T) {
 		assert.Empty(t, m["test5__a"])
 		assert.Empty(t, m["test5__b"])
 		assert.NotContains(t, m, "test5__c")
-		// assert.Equal(t, []any{"a", "b"}, m["test5"])
+		assert.Equal(t, []string{"a", "b"}, m["test5"])
 		// assert.Equal(t, float64(6), m["test6"])
 		assert.Equal(t, "6", m["test6"])
 	})
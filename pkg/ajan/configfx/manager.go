@@ -1,25 +1,35 @@
 package configfx
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/lib"
 )
 
 var (
 	ErrNotStruct                  = errors.New("not a struct")
 	ErrMissingRequiredConfigValue = errors.New("missing required config value")
+	ErrInvalidConfigValue         = errors.New("invalid config value")
 )
 
-type ConfigManager struct{}
+type ConfigManager struct {
+	secretProviders map[string]SecretProvider
+
+	mu sync.RWMutex
+}
 
 var _ ConfigLoader = (*ConfigManager)(nil)
 
 func NewConfigManager() *ConfigManager {
-	return &ConfigManager{}
+	return &ConfigManager{} //nolint:exhaustruct
 }
 
 func (cl *ConfigManager) LoadMeta(i any) (ConfigItemMeta, error) {
@@ -31,12 +41,14 @@ func (cl *ConfigManager) LoadMeta(i any) (ConfigItemMeta, error) {
 	}
 
 	return ConfigItemMeta{
-		Name:            "root",
-		Field:           r,
-		Type:            nil,
-		IsRequired:      false,
-		HasDefaultValue: false,
-		DefaultValue:    "",
+		Name:             "root",
+		Field:            r,
+		Type:             nil,
+		IsRequired:       false,
+		HasDefaultValue:  false,
+		DefaultValue:     "",
+		Validate:         "",
+		ElementSeparator: "",
 
 		Children: children,
 	}, nil
@@ -70,21 +82,48 @@ func (cl *ConfigManager) Load(i any, resources ...ConfigResource) error {
 		return err
 	}
 
-	err = reflectSet(meta, "", target)
-	if err != nil {
-		return err
+	return reflectSet(meta, "", target)
+}
+
+// LoadDefaults loads i from the files of the environment profile selected by APP_ENV,
+// then the system environment, then overrides such as FromFlags. See the README for
+// the precedence.
+func (cl *ConfigManager) LoadDefaults(i any, overrides ...ConfigResource) error {
+	return cl.Load(i, cl.defaultResources(overrides)...)
+}
+
+func (cl *ConfigManager) defaultResources(overrides []ConfigResource) []ConfigResource {
+	sources := make([]ConfigSource, len(overrides))
+
+	for i, override := range overrides {
+		sources[i] = ConfigSource{Name: SourceOverride, Resource: override}
 	}
 
-	return nil
+	return resourcesOf(cl.defaultSources(sources))
 }
 
-func (cl *ConfigManager) LoadDefaults(i any) error {
-	return cl.Load(
-		i,
-		cl.FromJSONFile("config.json"),
-		cl.FromEnvFile(".env", true),
-		cl.FromSystemEnv(true),
-	)
+func (cl *ConfigManager) defaultSources(overrides []ConfigSource) []ConfigSource {
+	sources := []ConfigSource{
+		{Name: SourceEnv, Resource: cl.fromProfile()},
+		{Name: SourceFile, Resource: cl.FromJSONFile("config.json")},
+		{Name: SourceFile, Resource: cl.FromEnvFile(".env", true)},
+		{Name: SourceEnv, Resource: cl.FromSystemEnv(true)},
+	}
+
+	// Secret references of the overrides are resolved as well.
+	sources = append(sources, overrides...)
+
+	return append(sources, ConfigSource{Name: SourceSecret, Resource: cl.ResolveSecrets(context.Background())})
+}
+
+// fromProfile sets ProfileKey to the environment profile, so the fields bound to it
+// name the profile the files were selected by.
+func (cl *ConfigManager) fromProfile() ConfigResource {
+	return func(target *map[string]any) error {
+		(*target)[ProfileKey] = lib.EnvGetCurrent()
+
+		return nil
+	}
 }
 
 func reflectMeta(r reflect.Value) ([]ConfigItemMeta, error) { //nolint:varnamelen
@@ -122,6 +161,8 @@ func reflectMeta(r reflect.Value) ([]ConfigItemMeta, error) { //nolint:varnamele
 
 		_, isRequired := structFieldType.Tag.Lookup(TagRequired)
 		defaultValue, hasDefaultValue := structFieldType.Tag.Lookup(TagDefault)
+		validate := structFieldType.Tag.Get(TagValidate)
+		elementSeparator := structFieldType.Tag.Get(TagSeparator)
 
 		var children []ConfigItemMeta = nil
 
@@ -135,12 +176,14 @@ func reflectMeta(r reflect.Value) ([]ConfigItemMeta, error) { //nolint:varnamele
 		}
 
 		result = append(result, ConfigItemMeta{
-			Name:            tag,
-			Field:           structField,
-			Type:            structFieldType.Type,
-			IsRequired:      isRequired,
-			HasDefaultValue: hasDefaultValue,
-			DefaultValue:    defaultValue,
+			Name:             tag,
+			Field:            structField,
+			Type:             structFieldType.Type,
+			IsRequired:       isRequired,
+			HasDefaultValue:  hasDefaultValue,
+			DefaultValue:     defaultValue,
+			Validate:         validate,
+			ElementSeparator: elementSeparator,
 
 			Children: children,
 		})
@@ -149,11 +192,26 @@ func reflectMeta(r reflect.Value) ([]ConfigItemMeta, error) { //nolint:varnamele
 	return result, nil
 }
 
-func reflectSet( //nolint:cyclop,gocognit,funlen
+// reflectSet sets the fields of meta from target, and reports every missing, invalid
+// or failing value together in a ConfigValidationError.
+func reflectSet(meta ConfigItemMeta, prefix string, target *map[string]any) error {
+	issues := make([]ConfigIssue, 0)
+
+	reflectSetChildren(meta, prefix, target, &issues)
+
+	if len(issues) > 0 {
+		return &ConfigValidationError{Issues: issues}
+	}
+
+	return nil
+}
+
+func reflectSetChildren( //nolint:cyclop,gocognit,funlen
 	meta ConfigItemMeta,
 	prefix string,
 	target *map[string]any,
-) error {
+	issues *[]ConfigIssue,
+) {
 	for _, child := range meta.Children {
 		key := prefix + child.Name
 
@@ -179,35 +237,34 @@ func reflectSet( //nolint:cyclop,gocognit,funlen
 				valueType := child.Type.Elem()
 				mapValue := reflect.New(valueType).Elem()
 
-				if valueType.Kind() == reflect.String {
-					value, valueOk := (*target)[targetKey].(string)
-
+				if valueType.Kind() != reflect.Struct {
+					value, valueOk := lookupValue(target, targetKey, valueType)
 					if valueOk {
-						mapValue.SetString(value)
+						reflectSetValue(mapValue, valueType, value, child.ElementSeparator, targetKey, issues)
 					}
+
+					newMap.SetMapIndex(reflect.ValueOf(mapKey), mapValue)
+
+					continue
 				}
 
 				// Recursively set the fields of the map value
-				subMeta := ConfigItemMeta{
-					Name:            mapKey,
-					Field:           mapValue,
-					Type:            valueType,
-					IsRequired:      child.IsRequired,
-					HasDefaultValue: child.HasDefaultValue,
-					DefaultValue:    child.DefaultValue,
-
-					Children: nil,
-				}
+				children, _ := reflectMeta(mapValue)
 
-				if valueType.Kind() == reflect.Struct {
-					children, _ := reflectMeta(mapValue)
-					subMeta.Children = children
+				subMeta := ConfigItemMeta{
+					Name:             mapKey,
+					Field:            mapValue,
+					Type:             valueType,
+					IsRequired:       child.IsRequired,
+					HasDefaultValue:  child.HasDefaultValue,
+					DefaultValue:     child.DefaultValue,
+					Validate:         "",
+					ElementSeparator: "",
+
+					Children: children,
 				}
 
-				err := reflectSet(subMeta, prefix+mapKey+Separator, target)
-				if err != nil {
-					return err
-				}
+				reflectSetChildren(subMeta, prefix+mapKey+Separator, target, issues)
 
 				// Set the value in the map
 				newMap.SetMapIndex(reflect.ValueOf(mapKey), mapValue)
@@ -215,118 +272,328 @@ func reflectSet( //nolint:cyclop,gocognit,funlen
 
 			child.Field.Set(newMap)
 
+			*issues = append(*issues, validateField(key, child.Field, child.Validate)...)
+
 			continue
 		}
 
 		if child.Type.Kind() == reflect.Struct {
-			err := reflectSet(child, key+Separator, target)
-			if err != nil {
-				return err
+			reflectSetChildren(child, key+Separator, target, issues)
+
+			continue
+		}
+
+		if child.Type.Kind() == reflect.Slice && child.Type.Elem().Kind() == reflect.Struct {
+			// Elements are keyed by their index, e.g. targets__0__name.
+			indices := collectIndices(target, key+Separator)
+			if len(indices) == 0 {
+				continue
+			}
+
+			slice := reflect.MakeSlice(child.Type, len(indices), len(indices))
+
+			for i, index := range indices {
+				element := slice.Index(i)
+				children, _ := reflectMeta(element)
+
+				subMeta := ConfigItemMeta{
+					Name:             strconv.Itoa(index),
+					Field:            element,
+					Type:             child.Type.Elem(),
+					IsRequired:       false,
+					HasDefaultValue:  false,
+					DefaultValue:     "",
+					Validate:         "",
+					ElementSeparator: "",
+
+					Children: children,
+				}
+
+				reflectSetChildren(subMeta, key+Separator+subMeta.Name+Separator, target, issues)
 			}
 
+			child.Field.Set(slice)
+
+			*issues = append(*issues, validateField(key, child.Field, child.Validate)...)
+
+			continue
+		}
+
+		if child.Type.Kind() == reflect.Ptr && child.Type.Elem().Kind() == reflect.Struct {
+			// Optional sections are only allocated when any of their keys is set.
+			if !hasKeyWithPrefix(target, key+Separator) {
+				continue
+			}
+
+			if child.Field.IsNil() {
+				child.Field.Set(reflect.New(child.Type.Elem()))
+			}
+
+			children, _ := reflectMeta(child.Field.Elem())
+			child.Children = children
+
+			reflectSetChildren(child, key+Separator, target, issues)
+
 			continue
 		}
 
 		// Check if the target map has the key with the child name
-		value, valueOk := (*target)[key].(string)
+		value, valueOk := lookupValue(target, key, child.Type)
 		if !valueOk {
-			if child.HasDefaultValue {
-				reflectSetField(child.Field, child.Type, child.DefaultValue)
+			if !child.HasDefaultValue {
+				if child.IsRequired {
+					*issues = append(*issues, ConfigIssue{
+						Key: key,
+						Err: fmt.Errorf(
+							"%w (key=%q, child_name=%q, child_type=%s)",
+							ErrMissingRequiredConfigValue,
+							key,
+							child.Name,
+							child.Type.String(),
+						),
+					})
+				}
 
 				continue
 			}
 
-			if child.IsRequired {
-				return fmt.Errorf(
-					"%w (key=%q, child_name=%q, child_type=%s)",
-					ErrMissingRequiredConfigValue,
-					key,
-					child.Name,
-					child.Type.String(),
-				)
-			}
+			value = child.DefaultValue
+		}
 
+		if !reflectSetValue(child.Field, child.Type, value, child.ElementSeparator, key, issues) {
 			continue
 		}
 
-		reflectSetField(child.Field, child.Type, value)
+		*issues = append(*issues, validateField(key, child.Field, child.Validate)...)
 	}
+}
 
-	return nil
+// lookupValue returns the value of key in target. Values are strings, or for slices
+// also the elements of an array.
+func lookupValue(target *map[string]any, key string, fieldType reflect.Type) (any, bool) {
+	switch value := (*target)[key].(type) {
+	case string:
+		return value, true
+	case []string:
+		return value, fieldType.Kind() == reflect.Slice
+	default:
+		return nil, false
+	}
 }
 
+// collectIndices returns the sorted indices of the elements under prefix, e.g. 0 and 1
+// for targets__0__name and targets__1__name.
+func collectIndices(target *map[string]any, prefix string) []int {
+	indices := make([]int, 0)
+
+	for targetKey := range *target {
+		if !strings.HasPrefix(strings.ToLower(targetKey), strings.ToLower(prefix)) {
+			continue
+		}
+
+		segment, _, _ := strings.Cut(targetKey[len(prefix):], Separator)
+
+		index, err := strconv.Atoi(segment)
+		if err != nil || index < 0 || slices.Contains(indices, index) {
+			continue
+		}
+
+		indices = append(indices, index)
+	}
+
+	slices.Sort(indices)
+
+	return indices
+}
+
+func hasKeyWithPrefix(target *map[string]any, prefix string) bool {
+	prefix = strings.ToLower(prefix)
+
+	for targetKey := range *target {
+		if strings.HasPrefix(strings.ToLower(targetKey), prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reflectSetValue sets field to value, and records an issue when it cannot be parsed.
+// It reports whether the field was set.
+func reflectSetValue(
+	field reflect.Value,
+	fieldType reflect.Type,
+	value any,
+	separator string,
+	key string,
+	issues *[]ConfigIssue,
+) bool {
+	var err error
+
+	elements, isElements := value.([]string)
+	if isElements {
+		err = reflectSetSlice(field, fieldType, elements)
+	} else {
+		err = reflectSetField(field, fieldType, value.(string), separator) //nolint:forcetypeassert
+	}
+
+	if err != nil {
+		*issues = append(*issues, ConfigIssue{
+			Key: key,
+			Err: fmt.Errorf(
+				"%w (key=%q, type=%s, value=%q): %w",
+				ErrInvalidConfigValue,
+				key,
+				fieldType.String(),
+				fmt.Sprintf("%v", value),
+				err,
+			),
+		})
+
+		return false
+	}
+
+	return true
+}
+
+// reflectSetField parses value into field. Empty values leave non-string fields at
+// their zero values.
 func reflectSetField( //nolint:cyclop,funlen
 	field reflect.Value,
 	fieldType reflect.Type,
 	value string,
-) {
-	var finalValue reflect.Value
+	separator string,
+) error {
+	var (
+		finalValue reflect.Value
+		err        error
+	)
+
+	if value == "" && fieldType != reflect.TypeFor[string]() {
+		return nil
+	}
+
+	switch fieldType.Kind() { //nolint:exhaustive
+	case reflect.Ptr:
+		// Handle pointer types by allocating a new instance
+		ptr := reflect.New(fieldType.Elem())
+
+		err = reflectSetField(ptr.Elem(), fieldType.Elem(), value, separator)
+		if err != nil {
+			return err
+		}
+
+		field.Set(ptr)
+
+		return nil
+	case reflect.Slice:
+		return reflectSetSlice(field, fieldType, splitElements(value, separator))
+	}
 
 	switch fieldType {
 	case reflect.TypeFor[string]():
 		finalValue = reflect.ValueOf(value)
 	case reflect.TypeFor[int]():
-		intValue, _ := strconv.Atoi(value)
+		var intValue int
+		intValue, err = strconv.Atoi(value)
 		finalValue = reflect.ValueOf(intValue)
 	case reflect.TypeFor[int8]():
-		int64Value, _ := strconv.ParseInt(value, 10, 8)
-		int8Value := int8(int64Value)
-		finalValue = reflect.ValueOf(int8Value)
+		var int64Value int64
+		int64Value, err = strconv.ParseInt(value, 10, 8)
+		finalValue = reflect.ValueOf(int8(int64Value))
 	case reflect.TypeFor[int16]():
-		int64Value, _ := strconv.ParseInt(value, 10, 16)
-		int16Value := int16(int64Value)
-		finalValue = reflect.ValueOf(int16Value)
+		var int64Value int64
+		int64Value, err = strconv.ParseInt(value, 10, 16)
+		finalValue = reflect.ValueOf(int16(int64Value))
 	case reflect.TypeFor[int32]():
-		int64Value, _ := strconv.ParseInt(value, 10, 32)
-		int32Value := int32(int64Value)
-		finalValue = reflect.ValueOf(int32Value)
+		var int64Value int64
+		int64Value, err = strconv.ParseInt(value, 10, 32)
+		finalValue = reflect.ValueOf(int32(int64Value))
 	case reflect.TypeFor[int64]():
-		int64Value, _ := strconv.ParseInt(value, 10, 64)
+		var int64Value int64
+		int64Value, err = strconv.ParseInt(value, 10, 64)
 		finalValue = reflect.ValueOf(int64Value)
 	case reflect.TypeFor[uint]():
-		uint64Value, _ := strconv.ParseUint(value, 10, 64)
-		uintValue := uint(uint64Value)
-		finalValue = reflect.ValueOf(uintValue)
+		var uint64Value uint64
+		uint64Value, err = strconv.ParseUint(value, 10, 64)
+		finalValue = reflect.ValueOf(uint(uint64Value))
 	case reflect.TypeFor[uint8]():
-		uint64Value, _ := strconv.ParseUint(value, 10, 8)
-		uint8Value := uint8(uint64Value)
-		finalValue = reflect.ValueOf(uint8Value)
+		var uint64Value uint64
+		uint64Value, err = strconv.ParseUint(value, 10, 8)
+		finalValue = reflect.ValueOf(uint8(uint64Value))
 	case reflect.TypeFor[uint16]():
-		uint64Value, _ := strconv.ParseUint(value, 10, 16)
-		uint16Value := uint16(uint64Value)
-		finalValue = reflect.ValueOf(uint16Value)
+		var uint64Value uint64
+		uint64Value, err = strconv.ParseUint(value, 10, 16)
+		finalValue = reflect.ValueOf(uint16(uint64Value))
 	case reflect.TypeFor[uint32]():
-		uint64Value, _ := strconv.ParseUint(value, 10, 32)
-		uint32Value := uint32(uint64Value)
-		finalValue = reflect.ValueOf(uint32Value)
+		var uint64Value uint64
+		uint64Value, err = strconv.ParseUint(value, 10, 32)
+		finalValue = reflect.ValueOf(uint32(uint64Value))
 	case reflect.TypeFor[uint64]():
-		uint64Value, _ := strconv.ParseUint(value, 10, 64)
+		var uint64Value uint64
+		uint64Value, err = strconv.ParseUint(value, 10, 64)
 		finalValue = reflect.ValueOf(uint64Value)
 	case reflect.TypeFor[float32]():
-		floatValue, _ := strconv.ParseFloat(value, 32)
-		finalValue = reflect.ValueOf(floatValue)
+		var floatValue float64
+		floatValue, err = strconv.ParseFloat(value, 32)
+		finalValue = reflect.ValueOf(float32(floatValue))
 	case reflect.TypeFor[float64]():
-		floatValue, _ := strconv.ParseFloat(value, 64)
+		var floatValue float64
+		floatValue, err = strconv.ParseFloat(value, 64)
 		finalValue = reflect.ValueOf(floatValue)
 	case reflect.TypeFor[bool]():
-		boolValue, _ := strconv.ParseBool(value)
+		var boolValue bool
+		boolValue, err = strconv.ParseBool(value)
 		finalValue = reflect.ValueOf(boolValue)
 	case reflect.TypeFor[time.Duration]():
-		durationValue, _ := time.ParseDuration(value)
+		var durationValue time.Duration
+		durationValue, err = time.ParseDuration(value)
 		finalValue = reflect.ValueOf(durationValue)
 	default:
-		return
+		return nil
 	}
 
-	if field.Kind() == reflect.Ptr {
-		// Handle pointer types by allocating a new instance
-		ptr := reflect.New(fieldType.Elem())
-		ptr.Elem().Set(finalValue)
-		field.Set(ptr)
-
-		return
+	if err != nil {
+		return err //nolint:wrapcheck
 	}
 
 	// Set the field directly
 	field.Set(finalValue)
+
+	return nil
+}
+
+// reflectSetSlice parses each of the elements into a new slice, and sets field to it.
+func reflectSetSlice(field reflect.Value, fieldType reflect.Type, elements []string) error {
+	slice := reflect.MakeSlice(fieldType, len(elements), len(elements))
+
+	for i, element := range elements {
+		err := reflectSetField(slice.Index(i), fieldType.Elem(), element, "")
+		if err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+
+	field.Set(slice)
+
+	return nil
+}
+
+// splitElements splits a list such as "a, b, c" by separator, which is a comma unless
+// set by the conf-separator tag. Empty elements are left out.
+func splitElements(value string, separator string) []string {
+	if separator == "" {
+		separator = DefaultElementSeparator
+	}
+
+	elements := make([]string, 0)
+
+	for element := range strings.SplitSeq(value, separator) {
+		element = strings.TrimSpace(element)
+		if element != "" {
+			elements = append(elements, element)
+		}
+	}
+
+	return elements
 }
@@ -6,6 +6,8 @@ import (
 
 	"github.com/eser/aya.is-services/pkg/ajan/configfx/envparser"
 	"github.com/eser/aya.is-services/pkg/ajan/configfx/jsonparser"
+	"github.com/eser/aya.is-services/pkg/ajan/configfx/tomlparser"
+	"github.com/eser/aya.is-services/pkg/ajan/configfx/yamlparser"
 	"github.com/eser/aya.is-services/pkg/ajan/lib"
 )
 
@@ -13,6 +15,8 @@ var (
 	ErrFailedToParseEnvFile    = errors.New("failed to parse env file")
 	ErrFailedToParseJSONFile   = errors.New("failed to parse JSON file")
 	ErrFailedToParseJSONString = errors.New("failed to parse JSON string")
+	ErrFailedToParseYAMLFile   = errors.New("failed to parse YAML file")
+	ErrFailedToParseTOMLFile   = errors.New("failed to parse TOML file")
 )
 
 func (cl *ConfigManager) FromEnvFileDirect(
@@ -86,3 +90,53 @@ func (cl *ConfigManager) FromJSONString(jsonStr string) ConfigResource {
 		return nil
 	}
 }
+
+func (cl *ConfigManager) FromYAMLFileDirect(filename string) ConfigResource {
+	return func(target *map[string]any) error {
+		err := yamlparser.TryParseFiles(target, filename)
+		if err != nil {
+			return fmt.Errorf("%w (filename=%q): %w", ErrFailedToParseYAMLFile, filename, err)
+		}
+
+		return nil
+	}
+}
+
+func (cl *ConfigManager) FromYAMLFile(filename string) ConfigResource {
+	return func(target *map[string]any) error {
+		env := lib.EnvGetCurrent()
+		filenames := lib.EnvAwareFilenames(env, filename)
+
+		err := yamlparser.TryParseFiles(target, filenames...)
+		if err != nil {
+			return fmt.Errorf("%w (filename=%q): %w", ErrFailedToParseYAMLFile, filename, err)
+		}
+
+		return nil
+	}
+}
+
+func (cl *ConfigManager) FromTOMLFileDirect(filename string) ConfigResource {
+	return func(target *map[string]any) error {
+		err := tomlparser.TryParseFiles(target, filename)
+		if err != nil {
+			return fmt.Errorf("%w (filename=%q): %w", ErrFailedToParseTOMLFile, filename, err)
+		}
+
+		return nil
+	}
+}
+
+func (cl *ConfigManager) FromTOMLFile(filename string) ConfigResource {
+	return func(target *map[string]any) error {
+		env := lib.EnvGetCurrent()
+		filenames := lib.EnvAwareFilenames(env, filename)
+
+		err := tomlparser.TryParseFiles(target, filenames...)
+		if err != nil {
+			return fmt.Errorf("%w (filename=%q): %w", ErrFailedToParseTOMLFile, filename, err)
+		}
+
+		return nil
+	}
+}
@@ -64,7 +64,117 @@ func TestLoad(t *testing.T) {
 			map[string]string{"key": "value", "key2": "value2", "key3": "value3"},
 			config.Dictionary,
 		)
-		// assert.Equal(t, []TestConfigNestedKV{{Name: "eser"}}, config.Array)
+		assert.Equal(t, []TestConfigNestedKV{{Name: "eser"}}, config.Array)
+	})
+
+	t.Run("should load config from yaml file", func(t *testing.T) {
+		t.Parallel()
+
+		config := TestConfigNested{} //nolint:exhaustruct
+
+		cl := configfx.NewConfigManager()
+		err := cl.Load(&config, cl.FromYAMLFile("testdata/config.yaml"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "yaml.local", config.Host)
+		assert.Equal(t, 8082, config.Port)
+		assert.Equal(t, uint16(10), config.MaxRetry)
+		assert.Equal(t, map[string]string{"key": "value", "key2": "yaml"}, config.Dictionary)
+	})
+
+	t.Run("should load config from toml file", func(t *testing.T) {
+		t.Parallel()
+
+		config := TestConfigNested{} //nolint:exhaustruct
+
+		cl := configfx.NewConfigManager()
+		err := cl.Load(&config, cl.FromTOMLFile("testdata/config.toml"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "toml.local", config.Host)
+		assert.Equal(t, 8083, config.Port)
+		assert.Equal(t, map[string]string{"key": "value", "key2": "toml"}, config.Dictionary)
+	})
+}
+
+type TestConfigCollections struct {
+	TLS     *TestConfigTLS `conf:"tls"`
+	Cache   *TestConfigTLS `conf:"cache"`
+	Timeout *int           `conf:"timeout"`
+
+	Origins []string       `conf:"origins"`
+	Buckets []string       `conf:"buckets" conf-separator:";"`
+	Ports   []int          `conf:"ports"   default:"80,443"`
+	Weights map[string]int `conf:"weights"`
+}
+
+type TestConfigTLS struct {
+	CertFile string `conf:"cert_file"`
+	Port     int    `conf:"port" default:"8443"`
+}
+
+func TestLoad_Collections(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should load slices, maps and pointers", func(t *testing.T) {
+		t.Parallel()
+
+		config := TestConfigCollections{} //nolint:exhaustruct
+
+		cl := configfx.NewConfigManager()
+		err := cl.Load(
+			&config,
+			cl.FromJSONString(`{
+				"origins": ["https://aya.is", "https://eser.live"],
+				"weights": {"primary": 3, "replica": 1},
+				"tls": {"cert_file": "cert.pem"},
+				"timeout": 30
+			}`),
+			cl.FromJSONString(`{"buckets": "media; avatars"}`),
+		)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://aya.is", "https://eser.live"}, config.Origins)
+		assert.Equal(t, []string{"media", "avatars"}, config.Buckets)
+		assert.Equal(t, []int{80, 443}, config.Ports)
+		assert.Equal(t, map[string]int{"primary": 3, "replica": 1}, config.Weights)
+
+		require.NotNil(t, config.TLS)
+		assert.Equal(t, "cert.pem", config.TLS.CertFile)
+		assert.Equal(t, 8443, config.TLS.Port)
+		assert.Nil(t, config.Cache)
+
+		require.NotNil(t, config.Timeout)
+		assert.Equal(t, 30, *config.Timeout)
+	})
+
+	t.Run("should load slices from env values", func(t *testing.T) {
+		t.Parallel()
+
+		config := TestConfigCollections{} //nolint:exhaustruct
+
+		cl := configfx.NewConfigManager()
+		err := cl.Load(&config, cl.FromJSONString(`{"origins": "a, b,,c", "ports": "8080"}`))
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b", "c"}, config.Origins)
+		assert.Equal(t, []int{8080}, config.Ports)
+	})
+
+	t.Run("should report invalid elements", func(t *testing.T) {
+		t.Parallel()
+
+		config := TestConfigCollections{} //nolint:exhaustruct
+
+		cl := configfx.NewConfigManager()
+		err := cl.Load(
+			&config,
+			cl.FromJSONString(`{"ports": [80, "http"], "weights": {"primary": "high"}}`),
+		)
+
+		require.ErrorIs(t, err, configfx.ErrInvalidConfigValue)
+		assert.Contains(t, err.Error(), `key="ports"`)
+		assert.Contains(t, err.Error(), `key="weights__primary"`)
 	})
 }
 
@@ -81,12 +191,14 @@ func TestLoadMeta(t *testing.T) { //nolint:funlen
 
 		expected := []configfx.ConfigItemMeta{
 			{
-				Name:            "host",
-				Field:           meta.Children[0].Field,
-				Type:            reflect.TypeFor[string](),
-				IsRequired:      false,
-				HasDefaultValue: true,
-				DefaultValue:    "localhost",
+				Name:             "host",
+				Field:            meta.Children[0].Field,
+				Type:             reflect.TypeFor[string](),
+				IsRequired:       false,
+				HasDefaultValue:  true,
+				DefaultValue:     "localhost",
+				Validate:         "",
+				ElementSeparator: "",
 
 				Children: nil,
 			},
@@ -109,52 +221,62 @@ func TestLoadMeta(t *testing.T) { //nolint:funlen
 
 		expected := []configfx.ConfigItemMeta{
 			{
-				Name:            "host",
-				Field:           meta.Children[0].Field,
-				Type:            reflect.TypeFor[string](),
-				IsRequired:      false,
-				HasDefaultValue: true,
-				DefaultValue:    "localhost",
+				Name:             "host",
+				Field:            meta.Children[0].Field,
+				Type:             reflect.TypeFor[string](),
+				IsRequired:       false,
+				HasDefaultValue:  true,
+				DefaultValue:     "localhost",
+				Validate:         "",
+				ElementSeparator: "",
 
 				Children: nil,
 			},
 			{
-				Name:            "port",
-				Field:           meta.Children[1].Field,
-				Type:            reflect.TypeFor[int](),
-				IsRequired:      false,
-				HasDefaultValue: true,
-				DefaultValue:    "8080",
+				Name:             "port",
+				Field:            meta.Children[1].Field,
+				Type:             reflect.TypeFor[int](),
+				IsRequired:       false,
+				HasDefaultValue:  true,
+				DefaultValue:     "8080",
+				Validate:         "",
+				ElementSeparator: "",
 
 				Children: nil,
 			},
 			{
-				Name:            "max_retry",
-				Field:           meta.Children[2].Field,
-				Type:            reflect.TypeFor[uint16](),
-				IsRequired:      false,
-				HasDefaultValue: true,
-				DefaultValue:    "10",
+				Name:             "max_retry",
+				Field:            meta.Children[2].Field,
+				Type:             reflect.TypeFor[uint16](),
+				IsRequired:       false,
+				HasDefaultValue:  true,
+				DefaultValue:     "10",
+				Validate:         "",
+				ElementSeparator: "",
 
 				Children: nil,
 			},
 			{
-				Name:            "dict",
-				Field:           meta.Children[3].Field,
-				Type:            reflect.TypeFor[map[string]string](),
-				IsRequired:      false,
-				HasDefaultValue: false,
-				DefaultValue:    "",
+				Name:             "dict",
+				Field:            meta.Children[3].Field,
+				Type:             reflect.TypeFor[map[string]string](),
+				IsRequired:       false,
+				HasDefaultValue:  false,
+				DefaultValue:     "",
+				Validate:         "",
+				ElementSeparator: "",
 
 				Children: nil,
 			},
 			{
-				Name:            "arr",
-				Field:           meta.Children[4].Field,
-				Type:            reflect.TypeFor[[]TestConfigNestedKV](),
-				IsRequired:      false,
-				HasDefaultValue: false,
-				DefaultValue:    "",
+				Name:             "arr",
+				Field:            meta.Children[4].Field,
+				Type:             reflect.TypeFor[[]TestConfigNestedKV](),
+				IsRequired:       false,
+				HasDefaultValue:  false,
+				DefaultValue:     "",
+				Validate:         "",
+				ElementSeparator: "",
 
 				Children: nil,
 			},
@@ -167,3 +289,42 @@ func TestLoadMeta(t *testing.T) { //nolint:funlen
 		assert.ElementsMatch(t, expected, meta.Children)
 	})
 }
+
+type TestProfileConfig struct {
+	TestConfig
+	Env      string `conf:"env"       default:"development"`
+	Port     int    `conf:"port"`
+	MaxRetry int    `conf:"max_retry"`
+}
+
+func TestLoadDefaults_Profiles(t *testing.T) { //nolint:paralleltest
+	t.Chdir("testdata/profiles")
+
+	t.Run("should layer the files of the selected profile", func(t *testing.T) {
+		t.Setenv("APP_ENV", "production")
+
+		config := TestProfileConfig{} //nolint:exhaustruct
+
+		err := configfx.NewConfigManager().LoadDefaults(&config)
+
+		require.NoError(t, err)
+		assert.Equal(t, "production", config.Env)
+		assert.Equal(t, "prod.local", config.Host)
+		assert.Equal(t, 9090, config.Port)
+		assert.Equal(t, 7, config.MaxRetry)
+	})
+
+	t.Run("should load the base files for other profiles", func(t *testing.T) {
+		t.Setenv("APP_ENV", "staging")
+
+		config := TestProfileConfig{} //nolint:exhaustruct
+
+		err := configfx.NewConfigManager().LoadDefaults(&config)
+
+		require.NoError(t, err)
+		assert.Equal(t, "staging", config.Env)
+		assert.Equal(t, "base.local", config.Host)
+		assert.Equal(t, 8080, config.Port)
+		assert.Equal(t, 1, config.MaxRetry)
+	})
+}
@@ -0,0 +1,135 @@
+package configfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/eser/aya.is-services/pkg/ajan/lib"
+)
+
+const RemoteKeySeparator = "/"
+
+var ErrFailedToLoadRemoteKV = errors.New("failed to load remote key-value config")
+
+// FromRemoteKV loads the keys under prefix from a key-value store, such as an etcd or
+// Consul connection of connfx, to share a configuration between instances. The rest
+// of each key names the config key, with "/" separating nested keys, e.g.
+// services/api/log/level is log__level for the prefix services/api/. Keys are
+// matched case-insensitively, so they override the values of earlier resources.
+func (cl *ConfigManager) FromRemoteKV(
+	ctx context.Context,
+	source connfx.PrefixRepository,
+	prefix string,
+) ConfigResource {
+	return func(target *map[string]any) error {
+		values, err := source.GetPrefix(ctx, prefix)
+		if err != nil {
+			return fmt.Errorf("%w (prefix=%q): %w", ErrFailedToLoadRemoteKV, prefix, err)
+		}
+
+		for remoteKey, value := range values {
+			key := remoteConfigKey(prefix, remoteKey)
+			if key == "" {
+				continue
+			}
+
+			lib.CaseInsensitiveSet(target, key, string(value))
+		}
+
+		return nil
+	}
+}
+
+// WatchRemoteKV reloads the configuration whenever a key under prefix changes in
+// source, the store read by a FromRemoteKV resource of the watcher. Errors are passed
+// to the OnError handler. It stops with Stop.
+func (w *ConfigWatcher) WatchRemoteKV(ctx context.Context, source connfx.WatchRepository, prefix string) {
+	watchCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	w.mu.Lock()
+	w.remoteCancels = append(w.remoteCancels, cancel)
+	w.mu.Unlock()
+
+	w.wg.Add(1)
+
+	go w.runRemote(watchCtx, source, prefix)
+}
+
+func (w *ConfigWatcher) runRemote(ctx context.Context, source connfx.WatchRepository, prefix string) {
+	defer w.wg.Done()
+
+	for ctx.Err() == nil {
+		events, errs := source.Watch(ctx, prefix)
+
+		w.forwardRemote(ctx, events, errs)
+
+		// The store ended the watch; watch again after a while.
+		select {
+		case <-ctx.Done():
+		case <-time.After(DefaultWatchInterval):
+		}
+	}
+}
+
+func (w *ConfigWatcher) forwardRemote(
+	ctx context.Context,
+	events <-chan connfx.WatchEvent,
+	errs <-chan error,
+) {
+	for events != nil || errs != nil {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-events:
+			if !ok {
+				events = nil
+
+				continue
+			}
+
+			// A change often touches several keys; reload once for the events at hand.
+			drainEvents(events)
+
+			_, err := w.Reload(ctx)
+			if err != nil {
+				w.handleError(ctx, err)
+			}
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+
+				continue
+			}
+
+			w.handleError(ctx, err)
+		}
+	}
+}
+
+func drainEvents(events <-chan connfx.WatchEvent) {
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+// remoteConfigKey returns the config key of a remote key, or "" for the prefix itself
+// and for directory markers such as the ones of Consul.
+func remoteConfigKey(prefix string, remoteKey string) string {
+	key := strings.Trim(strings.TrimPrefix(remoteKey, prefix), RemoteKeySeparator)
+	if key == "" || strings.HasSuffix(remoteKey, RemoteKeySeparator) {
+		return ""
+	}
+
+	return strings.ReplaceAll(key, RemoteKeySeparator, Separator)
+}
@@ -0,0 +1,127 @@
+package configfx_test
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/configfx"
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errRemoteUnavailable = errors.New("remote unavailable")
+
+// fakeRemoteKV is a key-value store that notifies its watchers of every change.
+type fakeRemoteKV struct {
+	err      error
+	values   map[string][]byte
+	watchers []chan connfx.WatchEvent
+	mu       sync.Mutex
+}
+
+func (f *fakeRemoteKV) GetPrefix(_ context.Context, _ string) (map[string][]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return maps.Clone(f.values), f.err
+}
+
+func (f *fakeRemoteKV) Watch(ctx context.Context, _ string) (<-chan connfx.WatchEvent, <-chan error) {
+	events := make(chan connfx.WatchEvent, 10)
+
+	f.mu.Lock()
+	f.watchers = append(f.watchers, events)
+	f.mu.Unlock()
+
+	return events, make(chan error)
+}
+
+func (f *fakeRemoteKV) Set(key string, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.values[key] = []byte(value)
+
+	for _, watcher := range f.watchers {
+		watcher <- connfx.WatchEvent{Type: connfx.WatchEventPut, Key: key, Value: []byte(value)} //nolint:exhaustruct
+	}
+}
+
+func (f *fakeRemoteKV) watching() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.watchers) > 0
+}
+
+func TestFromRemoteKV(t *testing.T) {
+	t.Parallel()
+
+	remote := &fakeRemoteKV{ //nolint:exhaustruct
+		values: map[string][]byte{
+			"services/api/":          nil,
+			"services/api/LOG/level": []byte("DEBUG"),
+			"services/api/port":      []byte("9090"),
+		},
+	}
+
+	cl := configfx.NewConfigManager()
+	config := &TestWatchedConfig{} //nolint:exhaustruct
+
+	err := cl.Load(
+		config,
+		cl.FromJSONString(`{"log": {"level": "INFO"}, "port": 8080}`),
+		cl.FromRemoteKV(t.Context(), remote, "services/api/"),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "DEBUG", config.Log.Level)
+	assert.Equal(t, 9090, config.Port)
+
+	remote.err = errRemoteUnavailable
+
+	err = cl.Load(config, cl.FromRemoteKV(t.Context(), remote, "services/api/"))
+	require.ErrorIs(t, err, configfx.ErrFailedToLoadRemoteKV)
+	require.ErrorIs(t, err, errRemoteUnavailable)
+}
+
+func TestConfigWatcher_WatchRemoteKV(t *testing.T) {
+	t.Parallel()
+
+	remote := &fakeRemoteKV{ //nolint:exhaustruct
+		values: map[string][]byte{"app/port": []byte("8080")},
+	}
+
+	cl := configfx.NewConfigManager()
+	config := &TestWatchedConfig{} //nolint:exhaustruct
+
+	watcher, err := cl.NewWatcher(config, nil, cl.FromRemoteKV(t.Context(), remote, "app/"))
+	require.NoError(t, err)
+
+	reloaded := make(chan *TestWatchedConfig, 1)
+
+	watcher.Subscribe(func(_ context.Context, changes *configfx.ConfigChanges) error {
+		reloaded <- changes.Config.(*TestWatchedConfig) //nolint:forcetypeassert
+
+		return nil
+	}, "port")
+
+	watcher.WatchRemoteKV(t.Context(), remote, "app/")
+	defer watcher.Stop()
+
+	require.Eventually(t, remote.watching, time.Second, 10*time.Millisecond)
+
+	remote.Set("app/port", "9090")
+
+	select {
+	case config := <-reloaded:
+		assert.Equal(t, 9090, config.Port)
+	case <-time.After(time.Second):
+		t.Fatal("config was not reloaded")
+	}
+}
@@ -0,0 +1,216 @@
+package configfx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const secretSchemeSeparator = "://"
+
+var (
+	ErrFailedToResolveSecret = errors.New("failed to resolve secret")
+	ErrSecretNotFound        = errors.New("secret not found")
+	ErrSecretKeyRequired     = errors.New("secret key is required")
+)
+
+// SecretProvider resolves the secret references of a scheme, e.g. vault://path#key.
+// The path is what follows the scheme, and the key what follows the "#", if any.
+type SecretProvider interface {
+	ResolveSecret(ctx context.Context, path string, key string) (string, error)
+}
+
+// SecretProviderFunc adapts a function to a SecretProvider.
+type SecretProviderFunc func(ctx context.Context, path string, key string) (string, error)
+
+func (f SecretProviderFunc) ResolveSecret(ctx context.Context, path string, key string) (string, error) {
+	return f(ctx, path, key)
+}
+
+// RegisterSecretProvider makes ResolveSecrets resolve the values starting with
+// scheme:// through provider. Values with other schemes, such as database URLs, are
+// left as they are.
+func (cl *ConfigManager) RegisterSecretProvider(scheme string, provider SecretProvider) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	if cl.secretProviders == nil {
+		cl.secretProviders = make(map[string]SecretProvider)
+	}
+
+	cl.secretProviders[strings.ToLower(scheme)] = provider
+}
+
+// ResolveSecrets replaces the secret references among the values loaded by the
+// resources before it with the secrets, so they can be kept out of config and .env
+// files. Pass it after the other resources.
+func (cl *ConfigManager) ResolveSecrets(ctx context.Context) ConfigResource {
+	return func(target *map[string]any) error {
+		cl.mu.RLock()
+		providers := maps.Clone(cl.secretProviders)
+		cl.mu.RUnlock()
+
+		if len(providers) == 0 {
+			return nil
+		}
+
+		// Sorted, so the same secret fails first on every load.
+		for _, name := range slices.Sorted(maps.Keys(*target)) {
+			value, isString := (*target)[name].(string)
+			if !isString {
+				continue
+			}
+
+			scheme, reference, isReference := strings.Cut(value, secretSchemeSeparator)
+			if !isReference {
+				continue
+			}
+
+			provider, exists := providers[strings.ToLower(scheme)]
+			if !exists {
+				continue
+			}
+
+			path, key, _ := strings.Cut(reference, "#")
+
+			secret, err := provider.ResolveSecret(ctx, path, key)
+			if err != nil {
+				return fmt.Errorf(
+					"%w (key=%q, scheme=%q, path=%q): %w",
+					ErrFailedToResolveSecret,
+					name,
+					scheme,
+					path,
+					err,
+				)
+			}
+
+			(*target)[name] = secret
+		}
+
+		return nil
+	}
+}
+
+// FileSecretProvider reads secrets from files, such as the ones mounted by Docker or
+// Kubernetes. Without a key the whole file is the secret; with a key the file holds a
+// JSON object and the key names its field.
+type FileSecretProvider struct {
+	// Dir is the directory relative paths are resolved from.
+	Dir string
+}
+
+func NewFileSecretProvider(dir string) *FileSecretProvider {
+	return &FileSecretProvider{Dir: dir}
+}
+
+func (p *FileSecretProvider) ResolveSecret(_ context.Context, path string, key string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(p.Dir, path)
+	}
+
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+
+	if key == "" {
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+
+	var fields map[string]any
+
+	err = json.Unmarshal(content, &fields)
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+
+	return lookupSecretField(fields, key)
+}
+
+// VaultSecretProvider reads secrets from the KV secrets engine of HashiCorp Vault,
+// e.g. vault://secret/data/app#db_password reads the db_password field of the secret
+// at secret/data/app. Both versions of the engine are supported.
+type VaultSecretProvider struct {
+	Client  *http.Client
+	Address string
+	Token   string
+}
+
+func NewVaultSecretProvider(address string, token string) *VaultSecretProvider {
+	return &VaultSecretProvider{
+		Client:  &http.Client{Timeout: 10 * time.Second}, //nolint:exhaustruct,mnd
+		Address: strings.TrimSuffix(address, "/"),
+		Token:   token,
+	}
+}
+
+func (p *VaultSecretProvider) ResolveSecret(ctx context.Context, path string, key string) (string, error) {
+	if key == "" {
+		return "", ErrSecretKeyRequired
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		p.Address+"/v1/"+strings.TrimPrefix(path, "/"),
+		nil,
+	)
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+
+	req.Header.Set("X-Vault-Token", p.Token)
+
+	res, err := p.Client.Do(req)
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+
+	defer res.Body.Close() //nolint:errcheck
+
+	if res.StatusCode == http.StatusNotFound {
+		return "", ErrSecretNotFound
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w (status=%d)", ErrFailedToResolveSecret, res.StatusCode)
+	}
+
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+
+	err = json.NewDecoder(res.Body).Decode(&body)
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+
+	// Version 2 of the engine nests the fields under data.data.
+	if nested, isNested := body.Data["data"].(map[string]any); isNested {
+		return lookupSecretField(nested, key)
+	}
+
+	return lookupSecretField(body.Data, key)
+}
+
+func lookupSecretField(fields map[string]any, key string) (string, error) {
+	value, exists := fields[key]
+	if !exists {
+		return "", fmt.Errorf("%w (key=%q)", ErrSecretNotFound, key)
+	}
+
+	if text, isString := value.(string); isString {
+		return text, nil
+	}
+
+	return fmt.Sprintf("%v", value), nil
+}
@@ -0,0 +1,100 @@
+package configfx_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/configfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type TestSecretsConfig struct {
+	DatabaseURL string `conf:"database_url"`
+	Password    string `conf:"password"`
+	APIKey      string `conf:"api_key"`
+	Token       string `conf:"token"`
+}
+
+func TestResolveSecrets(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "password"), []byte("hunter2\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "keys.json"), []byte(`{"api":"key-123"}`), 0o600))
+
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" || r.URL.Path != "/v1/secret/data/app" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"data":{"data":{"token":"vault-token"},"metadata":{"version":3}}}`))
+	}))
+	defer vault.Close()
+
+	cl := configfx.NewConfigManager()
+	cl.RegisterSecretProvider("file", configfx.NewFileSecretProvider(dir))
+	cl.RegisterSecretProvider("vault", configfx.NewVaultSecretProvider(vault.URL, "root"))
+
+	config := &TestSecretsConfig{} //nolint:exhaustruct
+
+	err := cl.Load(
+		config,
+		cl.FromJSONString(`{
+			"database_url": "postgres://localhost:5432/app",
+			"password": "file://password",
+			"api_key": "file://keys.json#api",
+			"token": "vault://secret/data/app#token"
+		}`),
+		cl.ResolveSecrets(t.Context()),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "postgres://localhost:5432/app", config.DatabaseURL)
+	assert.Equal(t, "hunter2", config.Password)
+	assert.Equal(t, "key-123", config.APIKey)
+	assert.Equal(t, "vault-token", config.Token)
+
+	err = cl.Load(
+		config,
+		cl.FromJSONString(`{"token": "vault://secret/data/app#missing"}`),
+		cl.ResolveSecrets(t.Context()),
+	)
+	require.ErrorIs(t, err, configfx.ErrFailedToResolveSecret)
+	require.ErrorIs(t, err, configfx.ErrSecretNotFound)
+
+	err = cl.Load(
+		config,
+		cl.FromJSONString(`{"token": "vault://secret/data/other#token"}`),
+		cl.ResolveSecrets(t.Context()),
+	)
+	require.ErrorIs(t, err, configfx.ErrSecretNotFound)
+}
+
+func TestResolveSecrets_CustomProvider(t *testing.T) {
+	t.Parallel()
+
+	cl := configfx.NewConfigManager()
+	cl.RegisterSecretProvider("aws-sm", configfx.SecretProviderFunc(
+		func(_ context.Context, path string, key string) (string, error) {
+			return path + "/" + key, nil
+		},
+	))
+
+	config := &TestSecretsConfig{} //nolint:exhaustruct
+
+	err := cl.Load(
+		config,
+		cl.FromJSONString(`{"password": "AWS-SM://prod/db#password"}`),
+		cl.ResolveSecrets(t.Context()),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "prod/db/password", config.Password)
+}
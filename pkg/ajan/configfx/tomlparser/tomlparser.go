@@ -0,0 +1,124 @@
+package tomlparser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/eser/aya.is-services/pkg/ajan/configfx/jsonparser"
+)
+
+var ErrParsingError = errors.New("parsing error")
+
+func ParseBytes(data []byte, out *map[string]any) error {
+	var raw map[string]any
+
+	err := toml.Unmarshal(data, &raw)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrParsingError, err)
+	}
+
+	normalized, _ := normalize(raw).(map[string]any)
+
+	jsonparser.Flatten(normalized, out)
+
+	return nil
+}
+
+func Parse(m *map[string]any, r io.Reader) error { //nolint:varnamelen
+	var buf bytes.Buffer
+
+	_, err := io.Copy(&buf, r)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrParsingError, err)
+	}
+
+	return ParseBytes(buf.Bytes(), m)
+}
+
+func tryParseFile(m *map[string]any, filename string) (err error) { //nolint:varnamelen
+	file, fileErr := os.Open(filepath.Clean(filename))
+	if fileErr != nil {
+		if os.IsNotExist(fileErr) {
+			return nil
+		}
+
+		return fmt.Errorf("%w: %w", ErrParsingError, fileErr)
+	}
+
+	defer func() {
+		closeErr := file.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	return Parse(m, file)
+}
+
+func TryParseFiles(m *map[string]any, filenames ...string) error {
+	for _, filename := range filenames {
+		err := tryParseFile(m, filename)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// normalize converts the values decoded by toml into the ones jsonparser.Flatten
+// expects: arrays of tables become []any, offset date-times are formatted as RFC 3339,
+// and local dates and times keep their TOML form.
+func normalize(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(typed))
+
+		for key, item := range typed {
+			result[key] = normalize(item)
+		}
+
+		return result
+	case []map[string]any:
+		result := make([]any, len(typed))
+
+		for i, item := range typed {
+			result[i] = normalize(item)
+		}
+
+		return result
+	case []any:
+		result := make([]any, len(typed))
+
+		for i, item := range typed {
+			result[i] = normalize(item)
+		}
+
+		return result
+	case time.Time:
+		return formatTime(typed)
+	default:
+		return value
+	}
+}
+
+// formatTime formats a decoded date-time. toml decodes the local dates and times in
+// zones named after their kind, which have no offset to write.
+func formatTime(value time.Time) string {
+	switch value.Location().String() {
+	case "datetime-local":
+		return value.Format("2006-01-02T15:04:05.999999999")
+	case "date-local":
+		return value.Format(time.DateOnly)
+	case "time-local":
+		return value.Format("15:04:05.999999999")
+	default:
+		return value.Format(time.RFC3339Nano)
+	}
+}
@@ -0,0 +1,117 @@
+package tomlparser_test
+
+import (
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/configfx/tomlparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTryParseFiles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should parse a toml config file", func(t *testing.T) {
+		t.Parallel()
+
+		m := make(map[string]any) //nolint:varnamelen
+		err := tomlparser.TryParseFiles(&m, "./testdata/config.toml")
+
+		require.NoError(t, err)
+		assert.Equal(t, "env", m["test"])
+		assert.Equal(t, "6", m["test6"])
+		assert.Equal(t, "0.5", m["ratio"])
+		assert.Equal(t, "true", m["enabled"])
+		assert.Equal(t, "1979-05-27T07:32:00Z", m["released"])
+		assert.Equal(t, "1000000", m["big"])
+		assert.Equal(t, "493", m["mask"])
+		assert.Contains(t, m, "tags__a")
+		assert.Contains(t, m, "tags__b")
+		assert.Equal(t, "env!", m["test2__test3"])
+		assert.Equal(t, "tab\tand é", m["test2__quoted key"])
+		assert.Empty(t, m["test4"])
+		assert.Equal(t, "postgres", m["conn__targets__default__protocol"])
+		assert.Equal(t, "postgres://localhost:5432/app?sslmode=disable", m["conn__targets__default__dsn"])
+		assert.Equal(t, "10", m["conn__targets__default__options__pool"])
+		assert.Equal(t, "false", m["conn__targets__default__options__read.only"])
+		assert.Equal(t, "alpha", m["servers__0__name"])
+		assert.Equal(t, "beta", m["servers__1__name"])
+		assert.Equal(t, "eu", m["servers__1__site__region"])
+	})
+
+	t.Run("should parse multiple toml config files", func(t *testing.T) {
+		t.Parallel()
+
+		m := make(map[string]any) //nolint:varnamelen
+		err := tomlparser.TryParseFiles(
+			&m,
+			"./testdata/config.toml",
+			"./testdata/config.development.toml",
+			"./testdata/config.missing.toml",
+		)
+
+		require.NoError(t, err)
+		assert.Equal(t, "env-development", m["test"])
+		assert.Equal(t, "env!!", m["test2__test3"])
+		assert.Equal(t, "6", m["test6"])
+	})
+}
+
+func TestParseBytes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should parse numbers as toml does", func(t *testing.T) {
+		t.Parallel()
+
+		m := make(map[string]any) //nolint:varnamelen
+		err := tomlparser.ParseBytes([]byte("ten = 10\noctal = 0o10\nhex = 0x10"), &m)
+
+		require.NoError(t, err)
+		assert.Equal(t, "10", m["ten"])
+		assert.Equal(t, "8", m["octal"])
+		assert.Equal(t, "16", m["hex"])
+	})
+
+	t.Run("should keep local dates and times as written", func(t *testing.T) {
+		t.Parallel()
+
+		m := make(map[string]any) //nolint:varnamelen
+		err := tomlparser.ParseBytes([]byte(
+			"datetime = 1979-05-27T07:32:00.5\ndate = 1979-05-27\ntime = 07:32:00",
+		), &m)
+
+		require.NoError(t, err)
+		assert.Equal(t, "1979-05-27T07:32:00.5", m["datetime"])
+		assert.Equal(t, "1979-05-27", m["date"])
+		assert.Equal(t, "07:32:00", m["time"])
+	})
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "DuplicateKey", input: "a = 1\na = 2"},
+		{name: "UnterminatedString", input: `a = "abc`},
+		{name: "InvalidEscape", input: `a = "\q"`},
+		{name: "InvalidValue", input: "a = yes"},
+		{name: "MissingEquals", input: "a 1"},
+		{name: "TwoValuesOnALine", input: "a = 1 b = 2"},
+		{name: "UnclosedArray", input: "a = [1, 2"},
+		{name: "KeyIsNotTable", input: "a = 1\n[a.b]"},
+		{name: "LeadingZero", input: "a = 010"},
+		{name: "LeadingZeroNotOctal", input: "a = 0808"},
+		{name: "DuplicateTable", input: "[a]\nb = 1\n[a]\nc = 2"},
+		{name: "TableAfterArrayOfTables", input: "[[a]]\nb = 1\n[a]\nc = 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := make(map[string]any) //nolint:varnamelen
+			err := tomlparser.ParseBytes([]byte(tt.input), &m)
+
+			require.ErrorIs(t, err, tomlparser.ErrParsingError)
+		})
+	}
+}
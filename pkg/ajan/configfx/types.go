@@ -1,20 +1,34 @@
 package configfx
 
-import "reflect"
+import (
+	"context"
+	"reflect"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+)
 
 const (
-	TagConf     = "conf"
-	TagDefault  = "default"
-	TagRequired = "required"
+	TagConf      = "conf"
+	TagDefault   = "default"
+	TagRequired  = "required"
+	TagValidate  = "conf-validate"
+	TagSeparator = "conf-separator"
 
 	Separator = "__"
+
+	DefaultElementSeparator = ","
+
+	// ProfileKey is set by LoadDefaults to the environment profile selected by APP_ENV.
+	ProfileKey = "env"
 )
 
 type ConfigItemMeta struct {
-	Type         reflect.Type
-	Field        reflect.Value
-	Name         string
-	DefaultValue string
+	Type             reflect.Type
+	Field            reflect.Value
+	Name             string
+	DefaultValue     string
+	Validate         string
+	ElementSeparator string
 
 	Children        []ConfigItemMeta
 	IsRequired      bool
@@ -27,12 +41,29 @@ type ConfigLoader interface {
 	LoadMeta(i any) (ConfigItemMeta, error)
 	LoadMap(resources ...ConfigResource) (*map[string]any, error)
 	Load(i any, resources ...ConfigResource) error
-	LoadDefaults(i any) error
+	LoadDefaults(i any, overrides ...ConfigResource) error
+
+	Inspect(i any, sources ...ConfigSource) ([]ConfigValue, error)
+	InspectDefaults(i any, overrides ...ConfigSource) ([]ConfigValue, error)
 
 	FromEnvFileDirect(filename string, keyCaseInsensitive bool) ConfigResource
 	FromEnvFile(filename string, keyCaseInsensitive bool) ConfigResource
 	FromSystemEnv(keyCaseInsensitive bool) ConfigResource
 
+	NewFlags(i any) (*ConfigFlags, error)
+	FromFlags(flags *ConfigFlags) ConfigResource
+
 	FromJSONFileDirect(filename string) ConfigResource
 	FromJSONFile(filename string) ConfigResource
+
+	RegisterSecretProvider(scheme string, provider SecretProvider)
+	ResolveSecrets(ctx context.Context) ConfigResource
+
+	FromYAMLFileDirect(filename string) ConfigResource
+	FromYAMLFile(filename string) ConfigResource
+
+	FromTOMLFileDirect(filename string) ConfigResource
+	FromTOMLFile(filename string) ConfigResource
+
+	FromRemoteKV(ctx context.Context, source connfx.PrefixRepository, prefix string) ConfigResource
 }
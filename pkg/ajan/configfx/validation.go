@@ -0,0 +1,183 @@
+package configfx
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrConfigValidationFailed = errors.New("config validation failed")
+	ErrInvalidValidationRule  = errors.New("invalid validation rule")
+)
+
+// ConfigIssue is a problem with the value of a single key.
+type ConfigIssue struct {
+	Err error
+	Key string
+}
+
+// ConfigValidationError reports every issue found while loading a configuration, so
+// they can be fixed at once instead of one restart at a time. errors.Is matches the
+// error of any of the issues, e.g. ErrMissingRequiredConfigValue.
+type ConfigValidationError struct {
+	Issues []ConfigIssue
+}
+
+func (e *ConfigValidationError) Error() string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "%d config issue(s):", len(e.Issues))
+
+	for _, issue := range e.Issues {
+		builder.WriteString("\n  - ")
+		builder.WriteString(issue.Err.Error())
+	}
+
+	return builder.String()
+}
+
+func (e *ConfigValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Issues))
+
+	for i, issue := range e.Issues {
+		errs[i] = issue.Err
+	}
+
+	return errs
+}
+
+// validateField checks the value of field against the comma-separated rules of a
+// conf-validate tag:
+//
+//   - min=N, max=N: bounds of numbers, of durations (e.g. max=30s), or of the length of
+//     strings, slices and maps.
+//   - oneof=a b c: the value, as text, is one of the space-separated options.
+func validateField(key string, field reflect.Value, rules string) []ConfigIssue {
+	if rules == "" {
+		return nil
+	}
+
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return nil
+		}
+
+		field = field.Elem()
+	}
+
+	var issues []ConfigIssue
+
+	for rule := range strings.SplitSeq(rules, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		err := validateRule(field, rule)
+		if err == nil {
+			continue
+		}
+
+		if !errors.Is(err, ErrInvalidValidationRule) {
+			err = fmt.Errorf(
+				"%w (key=%q, rule=%q, value=%q): %w",
+				ErrConfigValidationFailed,
+				key,
+				rule,
+				fmt.Sprintf("%v", field.Interface()),
+				err,
+			)
+		} else {
+			err = fmt.Errorf("%w (key=%q, rule=%q)", err, key, rule)
+		}
+
+		issues = append(issues, ConfigIssue{Key: key, Err: err})
+	}
+
+	return issues
+}
+
+func validateRule(field reflect.Value, rule string) error {
+	name, arg, _ := strings.Cut(rule, "=")
+
+	switch name {
+	case "min":
+		return validateBound(field, arg, func(result int) bool { return result >= 0 }, "is less than")
+	case "max":
+		return validateBound(field, arg, func(result int) bool { return result <= 0 }, "is greater than")
+	case "oneof":
+		options := strings.Fields(arg)
+		if len(options) == 0 {
+			return ErrInvalidValidationRule
+		}
+
+		if !slices.Contains(options, fmt.Sprintf("%v", field.Interface())) {
+			return fmt.Errorf("is not one of %s", strings.Join(options, ", ")) //nolint:err113
+		}
+
+		return nil
+	default:
+		return ErrInvalidValidationRule
+	}
+}
+
+// validateBound compares field with arg, and fails unless ok accepts the result of
+// the comparison.
+func validateBound( //nolint:cyclop
+	field reflect.Value,
+	arg string,
+	ok func(result int) bool,
+	description string,
+) error {
+	var (
+		result int
+		err    error
+	)
+
+	switch {
+	case field.Type() == reflect.TypeFor[time.Duration]():
+		var bound time.Duration
+
+		bound, err = time.ParseDuration(arg)
+		result = cmp.Compare(time.Duration(field.Int()), bound)
+	case field.CanInt():
+		var bound int64
+
+		bound, err = strconv.ParseInt(arg, 10, 64)
+		result = cmp.Compare(field.Int(), bound)
+	case field.CanUint():
+		var bound uint64
+
+		bound, err = strconv.ParseUint(arg, 10, 64)
+		result = cmp.Compare(field.Uint(), bound)
+	case field.CanFloat():
+		var bound float64
+
+		bound, err = strconv.ParseFloat(arg, 64)
+		result = cmp.Compare(field.Float(), bound)
+	case field.Kind() == reflect.String, field.Kind() == reflect.Slice, field.Kind() == reflect.Map:
+		var bound int
+
+		bound, err = strconv.Atoi(arg)
+		result = cmp.Compare(field.Len(), bound)
+		description = "has a length that " + description
+	default:
+		return ErrInvalidValidationRule
+	}
+
+	if err != nil {
+		return ErrInvalidValidationRule
+	}
+
+	if !ok(result) {
+		return fmt.Errorf("%s %s", description, arg) //nolint:err113
+	}
+
+	return nil
+}
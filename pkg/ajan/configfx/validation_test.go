@@ -0,0 +1,88 @@
+package configfx_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/configfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type TestValidatedConfig struct {
+	Tags     map[string]string `conf:"tags"     conf-validate:"max=2"`
+	Host     string            `conf:"host"     conf-validate:"min=1"     required:"true"`
+	Mode     string            `conf:"mode"     conf-validate:"oneof=dev prod" default:"dev"`
+	Optional string            `conf:"optional" conf-validate:"min=3"`
+	Port     int               `conf:"port"     conf-validate:"min=1,max=65535" default:"8080"`
+	Timeout  time.Duration     `conf:"timeout"  conf-validate:"max=30s"   default:"5s"`
+	Ratio    float64           `conf:"ratio"    conf-validate:"min=0,max=1"`
+}
+
+type TestInvalidRuleConfig struct {
+	Port int `conf:"port" conf-validate:"between=1" default:"1"`
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should accept valid values", func(t *testing.T) {
+		t.Parallel()
+
+		cl := configfx.NewConfigManager()
+		config := &TestValidatedConfig{} //nolint:exhaustruct
+
+		err := cl.Load(config, cl.FromJSONString(`{"host": "localhost", "ratio": 0.5}`))
+		require.NoError(t, err)
+
+		assert.Equal(t, "dev", config.Mode)
+		assert.Equal(t, 8080, config.Port)
+		assert.Equal(t, 5*time.Second, config.Timeout)
+	})
+
+	t.Run("should report every issue", func(t *testing.T) {
+		t.Parallel()
+
+		cl := configfx.NewConfigManager()
+		config := &TestValidatedConfig{} //nolint:exhaustruct
+
+		err := cl.Load(config, cl.FromJSONString(`{
+			"tags": {"a": "1", "b": "2", "c": "3"},
+			"mode": "staging",
+			"optional": "ab",
+			"port": 70000,
+			"timeout": "1m",
+			"ratio": "high"
+		}`))
+		require.Error(t, err)
+
+		var validationErr *configfx.ConfigValidationError
+
+		require.ErrorAs(t, err, &validationErr)
+
+		keys := make([]string, len(validationErr.Issues))
+		for i, issue := range validationErr.Issues {
+			keys[i] = issue.Key
+		}
+
+		assert.ElementsMatch(t, []string{"tags", "host", "mode", "optional", "port", "timeout", "ratio"}, keys)
+
+		require.ErrorIs(t, err, configfx.ErrMissingRequiredConfigValue)
+		require.ErrorIs(t, err, configfx.ErrConfigValidationFailed)
+		require.ErrorIs(t, err, configfx.ErrInvalidConfigValue)
+		assert.Contains(t, err.Error(), "7 config issue(s)")
+		assert.Contains(t, err.Error(), `rule="oneof=dev prod", value="staging"`)
+	})
+
+	t.Run("should report invalid rules", func(t *testing.T) {
+		t.Parallel()
+
+		cl := configfx.NewConfigManager()
+		config := &TestInvalidRuleConfig{} //nolint:exhaustruct
+
+		err := cl.Load(config)
+		require.ErrorIs(t, err, configfx.ErrInvalidValidationRule)
+		assert.False(t, errors.Is(err, configfx.ErrConfigValidationFailed))
+	})
+}
@@ -0,0 +1,374 @@
+package configfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/lib"
+)
+
+const DefaultWatchInterval = 5 * time.Second
+
+var (
+	ErrWatcherAlreadyRunning   = errors.New("config watcher is already running")
+	ErrInvalidWatchInterval    = errors.New("config watch interval must be positive")
+	ErrFailedToReloadConfig    = errors.New("failed to reload config")
+	ErrConfigSubscriberFailed  = errors.New("config subscriber failed")
+	ErrWatchTargetNotStructPtr = errors.New("watch target is not a pointer to a struct")
+)
+
+// ConfigChanges describes a reload that changed the configuration.
+type ConfigChanges struct {
+	// Config is the reloaded configuration, a new value of the type of the watched one.
+	Config any
+	// Keys lists the flattened keys that were added, changed or removed, in lower case.
+	Keys []string
+}
+
+// Has reports whether a key under any of the given keys changed, e.g. "log" for
+// "log__level". Keys are matched case-insensitively.
+func (c *ConfigChanges) Has(prefixes ...string) bool {
+	for _, key := range c.Keys {
+		if matchesPrefix(key, prefixes) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ConfigSubscriber is notified of the changes of a reload.
+type ConfigSubscriber func(ctx context.Context, changes *ConfigChanges) error
+
+// ConfigWatcher reloads a configuration when the files it is loaded from change, and
+// notifies its subscribers of the changed keys, so selected settings can be applied
+// without a restart. The watched configuration itself is left untouched; subscribers
+// receive the reloaded one.
+type ConfigWatcher struct {
+	manager    *ConfigManager
+	configType reflect.Type
+	current    atomic.Value
+	onError    func(ctx context.Context, err error)
+	cancel     context.CancelFunc
+
+	remoteCancels []context.CancelFunc
+
+	values      map[string]string
+	stamps      map[string]fileStamp
+	resources   []ConfigResource
+	files       []string
+	subscribers []configSubscription
+
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	reloadMu sync.Mutex
+}
+
+type configSubscription struct {
+	subscriber ConfigSubscriber
+	prefixes   []string
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewWatcher creates a watcher of the configuration i, a pointer to a struct loaded
+// from resources. The files are the ones the resources read; they are checked for
+// changes once the watcher is started.
+func (cl *ConfigManager) NewWatcher(
+	i any,
+	files []string,
+	resources ...ConfigResource,
+) (*ConfigWatcher, error) {
+	configType := reflect.TypeOf(i)
+	if configType == nil || configType.Kind() != reflect.Ptr || configType.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w (type=%v)", ErrWatchTargetNotStructPtr, configType)
+	}
+
+	target, err := cl.LoadMap(resources...)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher := &ConfigWatcher{ //nolint:exhaustruct
+		manager:    cl,
+		configType: configType.Elem(),
+		onError: func(ctx context.Context, err error) {
+			slog.ErrorContext(ctx, "config watcher failed", slog.Any("error", err))
+		},
+
+		values:    flattenValues(target),
+		stamps:    statFiles(files),
+		resources: resources,
+		files:     files,
+	}
+
+	watcher.current.Store(i)
+
+	return watcher, nil
+}
+
+// WatchDefaults creates a watcher of the configuration i, loaded from the sources
+// and files of LoadDefaults, with the same overrides.
+func (cl *ConfigManager) WatchDefaults(i any, overrides ...ConfigResource) (*ConfigWatcher, error) {
+	env := lib.EnvGetCurrent()
+
+	files := slices.Concat(
+		lib.EnvAwareFilenames(env, "config.json"),
+		lib.EnvAwareFilenames(env, ".env"),
+	)
+
+	return cl.NewWatcher(i, files, cl.defaultResources(overrides)...)
+}
+
+// Subscribe registers a subscriber that is notified when a key under any of the given
+// keys changes, or when any key changes if none are given.
+func (w *ConfigWatcher) Subscribe(subscriber ConfigSubscriber, prefixes ...string) {
+	lowered := make([]string, len(prefixes))
+
+	for i, prefix := range prefixes {
+		lowered[i] = strings.ToLower(prefix)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subscribers = append(w.subscribers, configSubscription{
+		subscriber: subscriber,
+		prefixes:   lowered,
+	})
+}
+
+// OnError replaces the handler of the errors of the reloads triggered by file and
+// remote changes, which logs them with the default slog logger.
+func (w *ConfigWatcher) OnError(handler func(ctx context.Context, err error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.onError = handler
+}
+
+// Current returns the configuration of the last successful reload, or the watched one
+// if there has been none.
+func (w *ConfigWatcher) Current() any {
+	return w.current.Load()
+}
+
+// Reload loads the configuration again and notifies the subscribers of the changed
+// keys. It returns nil changes when nothing changed. When the configuration fails to
+// load, the previous one is kept.
+func (w *ConfigWatcher) Reload(ctx context.Context) (*ConfigChanges, error) {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	target, err := w.manager.LoadMap(w.resources...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToReloadConfig, err)
+	}
+
+	values := flattenValues(target)
+	keys := diffValues(w.values, values)
+
+	if len(keys) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	config := reflect.New(w.configType).Interface()
+
+	meta, err := w.manager.LoadMeta(config)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToReloadConfig, err)
+	}
+
+	err = reflectSet(meta, "", target)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToReloadConfig, err)
+	}
+
+	w.values = values
+	w.current.Store(config)
+
+	changes := &ConfigChanges{
+		Config: config,
+		Keys:   keys,
+	}
+
+	w.mu.Lock()
+	subscribers := slices.Clone(w.subscribers)
+	w.mu.Unlock()
+
+	var errs []error
+
+	for _, subscription := range subscribers {
+		if len(subscription.prefixes) > 0 && !changes.Has(subscription.prefixes...) {
+			continue
+		}
+
+		err := subscription.subscriber(ctx, changes)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return changes, fmt.Errorf("%w: %w", ErrConfigSubscriberFailed, errors.Join(errs...))
+	}
+
+	return changes, nil
+}
+
+// Start checks the files for changes at the given interval, and reloads the
+// configuration when any of them is created, modified or removed.
+func (w *ConfigWatcher) Start(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return ErrInvalidWatchInterval
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.cancel != nil {
+		return ErrWatcherAlreadyRunning
+	}
+
+	watcherCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	w.cancel = cancel
+
+	w.wg.Add(1)
+
+	go w.run(watcherCtx, interval)
+
+	return nil
+}
+
+// Stop stops checking the files and watching the remote stores. It is safe to call
+// when the watcher is not running.
+func (w *ConfigWatcher) Stop() {
+	w.mu.Lock()
+	cancels := w.remoteCancels
+	if w.cancel != nil {
+		cancels = append(cancels, w.cancel)
+	}
+
+	w.cancel = nil
+	w.remoteCancels = nil
+	w.mu.Unlock()
+
+	for _, cancel := range cancels {
+		cancel()
+	}
+
+	w.wg.Wait()
+}
+
+func (w *ConfigWatcher) run(ctx context.Context, interval time.Duration) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.check(ctx)
+		}
+	}
+}
+
+func (w *ConfigWatcher) check(ctx context.Context) {
+	stamps := statFiles(w.files)
+	if maps.Equal(stamps, w.stamps) {
+		return
+	}
+
+	w.stamps = stamps
+
+	_, err := w.Reload(ctx)
+	if err != nil {
+		w.handleError(ctx, err)
+	}
+}
+
+func (w *ConfigWatcher) handleError(ctx context.Context, err error) {
+	w.mu.Lock()
+	onError := w.onError
+	w.mu.Unlock()
+
+	onError(ctx, err)
+}
+
+// statFiles returns the modification times and sizes of the files; missing files are
+// left out.
+func statFiles(files []string) map[string]fileStamp {
+	stamps := make(map[string]fileStamp, len(files))
+
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+
+		stamps[file] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+
+	return stamps
+}
+
+// flattenValues returns the values of a loaded map as strings, keyed in lower case.
+func flattenValues(target *map[string]any) map[string]string {
+	values := make(map[string]string, len(*target))
+
+	for key, value := range *target {
+		values[strings.ToLower(key)] = fmt.Sprintf("%v", value)
+	}
+
+	return values
+}
+
+// diffValues returns the sorted keys that are only in one of the maps, or whose values
+// differ.
+func diffValues(previous, current map[string]string) []string {
+	keys := make([]string, 0)
+
+	for key, value := range current {
+		if old, exists := previous[key]; !exists || old != value {
+			keys = append(keys, key)
+		}
+	}
+
+	for key := range previous {
+		if _, exists := current[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+func matchesPrefix(key string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		prefix = strings.ToLower(prefix)
+
+		if key == prefix || strings.HasPrefix(key, prefix+Separator) {
+			return true
+		}
+	}
+
+	return false
+}
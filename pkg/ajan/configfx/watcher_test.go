@@ -0,0 +1,135 @@
+package configfx_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/configfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type TestWatchedConfig struct {
+	Log struct {
+		Level string `conf:"level" default:"INFO"`
+	} `conf:"log"`
+	Port int `conf:"port" default:"8080"`
+}
+
+func TestConfigWatcher_Reload(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"log":{"level":"INFO"},"port":8080}`), 0o600))
+
+	cl := configfx.NewConfigManager()
+	config := &TestWatchedConfig{} //nolint:exhaustruct
+
+	require.NoError(t, cl.Load(config, cl.FromJSONFileDirect(file)))
+
+	watcher, err := cl.NewWatcher(config, []string{file}, cl.FromJSONFileDirect(file))
+	require.NoError(t, err)
+
+	var logChanges, allChanges []*configfx.ConfigChanges
+
+	watcher.Subscribe(func(_ context.Context, changes *configfx.ConfigChanges) error {
+		logChanges = append(logChanges, changes)
+
+		return nil
+	}, "LOG")
+	watcher.Subscribe(func(_ context.Context, changes *configfx.ConfigChanges) error {
+		allChanges = append(allChanges, changes)
+
+		return nil
+	})
+
+	changes, err := watcher.Reload(t.Context())
+	require.NoError(t, err)
+	assert.Nil(t, changes)
+
+	require.NoError(t, os.WriteFile(file, []byte(`{"log":{"level":"DEBUG"},"port":8080}`), 0o600))
+
+	changes, err = watcher.Reload(t.Context())
+	require.NoError(t, err)
+	require.NotNil(t, changes)
+	assert.Equal(t, []string{"log__level"}, changes.Keys)
+	assert.True(t, changes.Has("log"))
+	assert.False(t, changes.Has("port", "log__lev"))
+
+	reloaded, ok := changes.Config.(*TestWatchedConfig)
+	require.True(t, ok)
+	assert.Equal(t, "DEBUG", reloaded.Log.Level)
+	assert.Equal(t, 8080, reloaded.Port)
+	assert.Equal(t, "INFO", config.Log.Level)
+	assert.Same(t, reloaded, watcher.Current())
+
+	require.NoError(t, os.WriteFile(file, []byte(`{"log":{"level":"DEBUG"},"port":9090}`), 0o600))
+
+	changes, err = watcher.Reload(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"port"}, changes.Keys)
+
+	assert.Len(t, logChanges, 1)
+	assert.Len(t, allChanges, 2)
+}
+
+func TestConfigWatcher_ReloadFailure(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"port":8080}`), 0o600))
+
+	cl := configfx.NewConfigManager()
+	config := &TestWatchedConfig{} //nolint:exhaustruct
+
+	watcher, err := cl.NewWatcher(config, []string{file}, cl.FromJSONFileDirect(file))
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(file, []byte(`{"port":`), 0o600))
+
+	_, err = watcher.Reload(t.Context())
+	require.ErrorIs(t, err, configfx.ErrFailedToReloadConfig)
+	assert.Same(t, config, watcher.Current())
+
+	_, err = cl.NewWatcher(TestWatchedConfig{}, nil) //nolint:exhaustruct
+	require.ErrorIs(t, err, configfx.ErrWatchTargetNotStructPtr)
+}
+
+func TestConfigWatcher_Start(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"port":8080}`), 0o600))
+
+	cl := configfx.NewConfigManager()
+	config := &TestWatchedConfig{} //nolint:exhaustruct
+
+	watcher, err := cl.NewWatcher(config, []string{file}, cl.FromJSONFileDirect(file))
+	require.NoError(t, err)
+
+	reloaded := make(chan *TestWatchedConfig, 1)
+
+	watcher.Subscribe(func(_ context.Context, changes *configfx.ConfigChanges) error {
+		reloaded <- changes.Config.(*TestWatchedConfig) //nolint:forcetypeassert
+
+		return nil
+	}, "port")
+
+	require.ErrorIs(t, watcher.Start(t.Context(), 0), configfx.ErrInvalidWatchInterval)
+	require.NoError(t, watcher.Start(t.Context(), 10*time.Millisecond))
+	require.ErrorIs(t, watcher.Start(t.Context(), 10*time.Millisecond), configfx.ErrWatcherAlreadyRunning)
+
+	defer watcher.Stop()
+
+	require.NoError(t, os.WriteFile(file, []byte(`{"port":18080}`), 0o600))
+
+	select {
+	case config := <-reloaded:
+		assert.Equal(t, 18080, config.Port)
+	case <-time.After(time.Second):
+		t.Fatal("config was not reloaded")
+	}
+}
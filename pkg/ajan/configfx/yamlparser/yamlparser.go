@@ -0,0 +1,111 @@
+package yamlparser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/configfx/jsonparser"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrParsingError = errors.New("parsing error")
+
+func ParseBytes(data []byte, out *map[string]any) error {
+	var raw map[string]any
+
+	err := yaml.Unmarshal(data, &raw)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrParsingError, err)
+	}
+
+	normalized, _ := normalize(raw).(map[string]any)
+
+	jsonparser.Flatten(normalized, out)
+
+	return nil
+}
+
+func Parse(m *map[string]any, r io.Reader) error { //nolint:varnamelen
+	var buf bytes.Buffer
+
+	_, err := io.Copy(&buf, r)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrParsingError, err)
+	}
+
+	return ParseBytes(buf.Bytes(), m)
+}
+
+func tryParseFile(m *map[string]any, filename string) (err error) { //nolint:varnamelen
+	file, fileErr := os.Open(filepath.Clean(filename))
+	if fileErr != nil {
+		if os.IsNotExist(fileErr) {
+			return nil
+		}
+
+		return fmt.Errorf("%w: %w", ErrParsingError, fileErr)
+	}
+
+	defer func() {
+		closeErr := file.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	return Parse(m, file)
+}
+
+func TryParseFiles(m *map[string]any, filenames ...string) error {
+	for _, filename := range filenames {
+		err := tryParseFile(m, filename)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// normalize converts the values decoded by yaml into the ones jsonparser.Flatten
+// expects: mappings with non-string keys become map[string]any, empty values become
+// empty strings, and timestamps are formatted as RFC 3339.
+func normalize(value any) any {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case map[string]any:
+		result := make(map[string]any, len(typed))
+
+		for key, item := range typed {
+			result[key] = normalize(item)
+		}
+
+		return result
+	case map[any]any:
+		result := make(map[string]any, len(typed))
+
+		for key, item := range typed {
+			result[fmt.Sprintf("%v", key)] = normalize(item)
+		}
+
+		return result
+	case []any:
+		result := make([]any, len(typed))
+
+		for i, item := range typed {
+			result[i] = normalize(item)
+		}
+
+		return result
+	case time.Time:
+		return typed.Format(time.RFC3339Nano)
+	default:
+		return value
+	}
+}
@@ -0,0 +1,58 @@
+package yamlparser_test
+
+import (
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/configfx/yamlparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTryParseFiles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should parse a yaml config file", func(t *testing.T) {
+		t.Parallel()
+
+		m := make(map[string]any) //nolint:varnamelen
+		err := yamlparser.TryParseFiles(&m, "./testdata/config.yaml")
+
+		require.NoError(t, err)
+		assert.Equal(t, "env", m["test"])
+		assert.Equal(t, "env!", m["test2__test3"])
+		assert.Empty(t, m["test4"])
+		assert.Empty(t, m["test5__a"])
+		assert.Empty(t, m["test5__b"])
+		assert.Equal(t, "6", m["test6"])
+		assert.Equal(t, "", m["test7"])
+		assert.Equal(t, "2024-01-15T10:30:00Z", m["released"])
+		assert.Equal(t, "primary", m["targets__0__name"])
+		assert.Equal(t, "5433", m["targets__1__port"])
+	})
+
+	t.Run("should parse multiple yaml config files", func(t *testing.T) {
+		t.Parallel()
+
+		m := make(map[string]any) //nolint:varnamelen
+		err := yamlparser.TryParseFiles(
+			&m,
+			"./testdata/config.yaml",
+			"./testdata/config.development.yaml",
+			"./testdata/config.missing.yaml",
+		)
+
+		require.NoError(t, err)
+		assert.Equal(t, "env-development", m["test"])
+		assert.Equal(t, "env!!", m["test2__test3"])
+		assert.Equal(t, "6", m["test6"])
+	})
+
+	t.Run("should fail on invalid yaml", func(t *testing.T) {
+		t.Parallel()
+
+		m := make(map[string]any) //nolint:varnamelen
+		err := yamlparser.ParseBytes([]byte("test: [unclosed"), &m)
+
+		require.ErrorIs(t, err, yamlparser.ErrParsingError)
+	})
+}
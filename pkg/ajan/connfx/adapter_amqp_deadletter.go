@@ -0,0 +1,174 @@
+package connfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// DeadLetterQueueSuffix names the dead-letter queue of a queue whose dead-letter queue
+// was not declared through this adapter, e.g. "emails" -> "emails.dlq".
+const DeadLetterQueueSuffix = ".dlq"
+
+var ErrRedriveNotConfirmed = errors.New("broker did not confirm the redriven message")
+
+// RedriveDeadLetters moves up to limit messages (all when limit <= 0) from the queue's
+// dead-letter queue back to the queue, e.g. once the failure that rejected them has been
+// fixed. Only the messages present when the redrive starts are moved, so messages that
+// fail again are not redriven in a loop. Each message is removed from the dead-letter
+// queue only after the broker has confirmed its republish.
+func (aa *AMQPAdapter) RedriveDeadLetters(
+	ctx context.Context,
+	queueName string,
+	limit int,
+) (int, error) {
+	if err := aa.ensureConnection(); err != nil {
+		return 0, fmt.Errorf("%w (queue=%q): %w", ErrAMQPClientNotInitialized, queueName, err)
+	}
+
+	aa.mu.Lock()
+	deadLetterQueue := aa.deadLetterQueue(queueName)
+	channel, err := aa.connection.Channel()
+	aa.mu.Unlock()
+
+	if err != nil {
+		return 0, fmt.Errorf("%w (queue=%q): %w", ErrFailedToOpenChannel, deadLetterQueue, err)
+	}
+
+	defer func() { _ = channel.Close() }()
+
+	if err := channel.Confirm(false); err != nil {
+		return 0, fmt.Errorf("%w (operation=confirm, queue=%q): %w", ErrAMQPOperation, deadLetterQueue, err)
+	}
+
+	pending, err := channel.QueueDeclarePassive(deadLetterQueue, false, false, false, false, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%w (operation=inspect, queue=%q): %w", ErrAMQPOperation, deadLetterQueue, err)
+	}
+
+	count := pending.Messages
+	if limit > 0 && limit < count {
+		count = limit
+	}
+
+	redriven := 0
+
+	for redriven < count {
+		delivery, ok, err := channel.Get(deadLetterQueue, false)
+		if err != nil {
+			return redriven, fmt.Errorf("%w (operation=get, queue=%q): %w", ErrAMQPOperation, deadLetterQueue, err)
+		}
+
+		if !ok {
+			break
+		}
+
+		if err := redriveDelivery(ctx, channel, queueName, delivery); err != nil {
+			_ = delivery.Nack(false, true)
+
+			return redriven, err
+		}
+
+		if err := delivery.Ack(false); err != nil {
+			return redriven, fmt.Errorf("%w (operation=ack, queue=%q): %w", ErrAMQPOperation, deadLetterQueue, err)
+		}
+
+		redriven++
+	}
+
+	return redriven, nil
+}
+
+// declareDeadLetterQueue declares the dead-letter queue of config and adds the
+// dead-lettering arguments of the main queue to args.
+func (aa *AMQPAdapter) declareDeadLetterQueue(
+	queueName string,
+	config QueueConfig,
+	args amqp.Table,
+) error {
+	if config.DeadLetterQueue == "" && config.DeadLetterExchange == "" {
+		return nil
+	}
+
+	args["x-dead-letter-exchange"] = config.DeadLetterExchange
+
+	if config.DeadLetterQueue == "" {
+		return nil
+	}
+
+	args["x-dead-letter-routing-key"] = config.DeadLetterQueue
+
+	_, err := aa.channel.QueueDeclare(
+		config.DeadLetterQueue,
+		config.Durable,
+		false, // delete when unused
+		false, // exclusive
+		false, // no-wait
+		nil,   // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("%w (queue=%q): %w", ErrFailedToDeclareQueue, config.DeadLetterQueue, err)
+	}
+
+	aa.mu.Lock()
+	aa.deadLetters[queueName] = config.DeadLetterQueue
+	aa.mu.Unlock()
+
+	return nil
+}
+
+// deadLetterQueue returns the dead-letter queue of a queue. The caller holds aa.mu.
+func (aa *AMQPAdapter) deadLetterQueue(queueName string) string {
+	if deadLetterQueue, exists := aa.deadLetters[queueName]; exists {
+		return deadLetterQueue
+	}
+
+	return queueName + DeadLetterQueueSuffix
+}
+
+// redriveDelivery republishes a dead-lettered message to its queue and waits for the
+// broker to confirm it.
+func redriveDelivery(
+	ctx context.Context,
+	channel *amqp.Channel,
+	queueName string,
+	delivery amqp.Delivery,
+) error {
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(
+		ctx,
+		"",        // exchange
+		queueName, // routing key
+		false,     // mandatory
+		false,     // immediate
+		amqp.Publishing{ //nolint:exhaustruct
+			Headers:         delivery.Headers,
+			ContentType:     delivery.ContentType,
+			ContentEncoding: delivery.ContentEncoding,
+			DeliveryMode:    delivery.DeliveryMode,
+			Priority:        delivery.Priority,
+			CorrelationId:   delivery.CorrelationId,
+			ReplyTo:         delivery.ReplyTo,
+			MessageId:       delivery.MessageId,
+			Timestamp:       delivery.Timestamp,
+			Type:            delivery.Type,
+			AppId:           delivery.AppId,
+			Body:            delivery.Body,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("%w (queue=%q): %w", ErrFailedToPublishMessage, queueName, err)
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("%w (queue=%q): %w", ErrRedriveNotConfirmed, queueName, err)
+	}
+
+	if !acked {
+		return fmt.Errorf("%w (queue=%q)", ErrRedriveNotConfirmed, queueName)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package connfx
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DefaultAMQPChannelPoolSize is the number of channels publishers can use at once.
+const DefaultAMQPChannelPoolSize = 8
+
+// pooledChannel is the part of an AMQP channel the pool manages.
+type pooledChannel interface {
+	IsClosed() bool
+	Close() error
+}
+
+// channelPool hands out channels of one connection to publishers, so concurrent Publish
+// calls never share a channel. Channels are opened on demand, up to the pool size, and
+// channels closed by the broker are replaced.
+type channelPool[C pooledChannel] struct {
+	open  func() (C, error)
+	idle  chan C
+	slots chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func newChannelPool[C pooledChannel](open func() (C, error), size int) *channelPool[C] {
+	if size <= 0 {
+		size = DefaultAMQPChannelPoolSize
+	}
+
+	return &channelPool[C]{
+		open:   open,
+		idle:   make(chan C, size),
+		slots:  make(chan struct{}, size),
+		mu:     sync.Mutex{},
+		closed: false,
+	}
+}
+
+// acquire returns a channel for exclusive use, waiting while all of them are in use.
+// Every acquired channel is given back with release.
+func (pool *channelPool[C]) acquire(ctx context.Context) (C, error) {
+	var zero C
+
+	select {
+	case pool.slots <- struct{}{}:
+	case <-ctx.Done():
+		return zero, fmt.Errorf("%w: %w", ErrNoChannelAvailable, ctx.Err())
+	}
+
+	for {
+		select {
+		case channel := <-pool.idle:
+			if !channel.IsClosed() {
+				return channel, nil
+			}
+		default:
+			channel, err := pool.open()
+			if err != nil {
+				<-pool.slots
+
+				return zero, fmt.Errorf("%w: %w", ErrFailedToOpenChannel, err)
+			}
+
+			return channel, nil
+		}
+	}
+}
+
+// release gives a channel back to the pool. A closed channel is dropped, and once the
+// pool is closed the channel is closed instead of kept.
+func (pool *channelPool[C]) release(channel C) {
+	pool.mu.Lock()
+
+	keep := !pool.closed && !channel.IsClosed()
+	if keep {
+		// the released slot is still held, so idle always has room for the channel.
+		pool.idle <- channel
+	}
+
+	pool.mu.Unlock()
+
+	if !keep && !channel.IsClosed() {
+		_ = channel.Close()
+	}
+
+	<-pool.slots
+}
+
+// close closes the idle channels, and the channels in use as they are released.
+func (pool *channelPool[C]) close() {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.closed = true
+
+	for {
+		select {
+		case channel := <-pool.idle:
+			_ = channel.Close()
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package connfx_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errChannelRefused = errors.New("channel refused")
+
+// channelOpener opens test channels and remembers every channel it opened.
+type channelOpener struct {
+	err    error
+	opened []*connfx.TestChannel
+	mu     sync.Mutex
+}
+
+func (o *channelOpener) open() (*connfx.TestChannel, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.err != nil {
+		return nil, o.err
+	}
+
+	channel := &connfx.TestChannel{} //nolint:exhaustruct
+	o.opened = append(o.opened, channel)
+
+	return channel, nil
+}
+
+func (o *channelOpener) openedChannels() []*connfx.TestChannel {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.opened
+}
+
+func TestChannelPool(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should reuse released channels", func(t *testing.T) {
+		t.Parallel()
+
+		opener := &channelOpener{} //nolint:exhaustruct
+		pool := connfx.NewTestChannelPool(opener.open, 2)
+
+		first, err := pool.Acquire(t.Context())
+		require.NoError(t, err)
+		pool.Release(first)
+
+		second, err := pool.Acquire(t.Context())
+		require.NoError(t, err)
+		assert.Same(t, first, second)
+		assert.Len(t, opener.openedChannels(), 1)
+	})
+
+	t.Run("should wait while all channels are in use", func(t *testing.T) {
+		t.Parallel()
+
+		opener := &channelOpener{} //nolint:exhaustruct
+		pool := connfx.NewTestChannelPool(opener.open, 1)
+
+		channel, err := pool.Acquire(t.Context())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = pool.Acquire(ctx)
+		require.ErrorIs(t, err, connfx.ErrNoChannelAvailable)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		pool.Release(channel)
+
+		_, err = pool.Acquire(t.Context())
+		require.NoError(t, err)
+	})
+
+	t.Run("should replace closed channels", func(t *testing.T) {
+		t.Parallel()
+
+		opener := &channelOpener{} //nolint:exhaustruct
+		pool := connfx.NewTestChannelPool(opener.open, 1)
+
+		first, err := pool.Acquire(t.Context())
+		require.NoError(t, err)
+		pool.Release(first)
+
+		_ = first.Close()
+
+		second, err := pool.Acquire(t.Context())
+		require.NoError(t, err)
+		assert.NotSame(t, first, second)
+		assert.False(t, second.IsClosed())
+	})
+
+	t.Run("should free the slot when a channel fails to open", func(t *testing.T) {
+		t.Parallel()
+
+		opener := &channelOpener{err: errChannelRefused} //nolint:exhaustruct
+		pool := connfx.NewTestChannelPool(opener.open, 1)
+
+		_, err := pool.Acquire(t.Context())
+		require.ErrorIs(t, err, connfx.ErrFailedToOpenChannel)
+		require.ErrorIs(t, err, errChannelRefused)
+
+		opener.mu.Lock()
+		opener.err = nil
+		opener.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+		defer cancel()
+
+		_, err = pool.Acquire(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("should close the idle channels", func(t *testing.T) {
+		t.Parallel()
+
+		opener := &channelOpener{} //nolint:exhaustruct
+		pool := connfx.NewTestChannelPool(opener.open, 1)
+
+		channel, err := pool.Acquire(t.Context())
+		require.NoError(t, err)
+		pool.Release(channel)
+
+		pool.Close()
+		assert.True(t, channel.IsClosed())
+	})
+
+	t.Run("should close the channels released after the pool is closed", func(t *testing.T) {
+		t.Parallel()
+
+		opener := &channelOpener{} //nolint:exhaustruct
+		pool := connfx.NewTestChannelPool(opener.open, 1)
+
+		channel, err := pool.Acquire(t.Context())
+		require.NoError(t, err)
+
+		pool.Close()
+		assert.False(t, channel.IsClosed())
+
+		pool.Release(channel)
+		assert.True(t, channel.IsClosed())
+	})
+
+	t.Run("should close every channel when closed during publishes", func(t *testing.T) {
+		t.Parallel()
+
+		opener := &channelOpener{} //nolint:exhaustruct
+		pool := connfx.NewTestChannelPool(opener.open, 4)
+
+		var wg sync.WaitGroup
+
+		for range 16 {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				for range 50 {
+					channel, err := pool.Acquire(t.Context())
+					if err != nil {
+						return
+					}
+
+					pool.Release(channel)
+				}
+			}()
+		}
+
+		pool.Close()
+		wg.Wait()
+
+		for _, channel := range opener.openedChannels() {
+			assert.True(t, channel.IsClosed())
+		}
+	})
+}
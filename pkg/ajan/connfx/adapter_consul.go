@@ -0,0 +1,883 @@
+package connfx
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultConsulTimeout  = 10 * time.Second
+	DefaultConsulWaitTime = 5 * time.Minute
+	defaultConsulPort     = 8500
+
+	consulWatchBufferSize = 100
+	consulWatchRetryDelay = time.Second
+)
+
+var (
+	ErrConsulClientNotInitialized = errors.New("consul client not initialized")
+	ErrConsulConnectionFailed     = errors.New("failed to connect to consul")
+	ErrFailedToCreateConsulClient = errors.New("failed to create consul client")
+	ErrConsulOperation            = errors.New("consul operation failed")
+	ErrConsulWatchFailed          = errors.New("consul watch failed")
+	ErrConsulUnsupportedOperation = errors.New("operation not supported by consul")
+	ErrConsulUnexpectedStatus     = errors.New("unexpected consul response status")
+)
+
+// ConsulConfig holds Consul-specific configuration options.
+type ConsulConfig struct {
+	Address               string // base URL of the HTTP API, e.g. http://localhost:8500
+	Token                 string
+	Datacenter            string
+	Namespace             string // optional key prefix applied to every operation
+	CAFile                string
+	Timeout               time.Duration
+	WaitTime              time.Duration // how long a watch request blocks for changes
+	TLSInsecureSkipVerify bool
+}
+
+// NewDefaultConsulConfig creates a Consul configuration with sensible defaults.
+func NewDefaultConsulConfig() *ConsulConfig {
+	return &ConsulConfig{
+		Address:               "http://localhost:8500",
+		Token:                 "",
+		Datacenter:            "",
+		Namespace:             "",
+		CAFile:                "",
+		Timeout:               DefaultConsulTimeout,
+		WaitTime:              DefaultConsulWaitTime,
+		TLSInsecureSkipVerify: false,
+	}
+}
+
+// ConsulAdapter implements the Repository, PrefixRepository and WatchRepository
+// interfaces for the key-value store of Consul.
+type ConsulAdapter struct {
+	client *http.Client
+	config *ConsulConfig
+}
+
+// ConsulConnection implements the connfx.Connection interface for Consul connections.
+type ConsulConnection struct {
+	adapter  *ConsulAdapter
+	protocol string
+	state    int32 // atomic field for connection state
+}
+
+type consulKVPair struct {
+	Key         string `json:"Key"`
+	Value       []byte `json:"Value"`
+	ModifyIndex int64  `json:"ModifyIndex"`
+}
+
+// NewConsulConnection creates a new Consul connection.
+func NewConsulConnection(protocol string, config *ConsulConfig) *ConsulConnection {
+	if config == nil {
+		config = NewDefaultConsulConfig()
+	}
+
+	adapter := &ConsulAdapter{
+		client: nil, // Will be initialized on connect
+		config: config,
+	}
+
+	return &ConsulConnection{
+		adapter:  adapter,
+		protocol: protocol,
+		state:    int32(ConnectionStateNotInitialized),
+	}
+}
+
+// Connection interface implementation.
+func (cc *ConsulConnection) GetBehaviors() []ConnectionBehavior {
+	return []ConnectionBehavior{
+		ConnectionBehaviorStateless,
+		ConnectionBehaviorStreaming,
+	}
+}
+
+func (cc *ConsulConnection) GetCapabilities() []ConnectionCapability {
+	return []ConnectionCapability{
+		ConnectionCapabilityKeyValue,
+		ConnectionCapabilityWatch,
+	}
+}
+
+func (cc *ConsulConnection) GetProtocol() string {
+	return cc.protocol
+}
+
+func (cc *ConsulConnection) GetState() ConnectionState {
+	return ConnectionState(atomic.LoadInt32(&cc.state))
+}
+
+func (cc *ConsulConnection) HealthCheck(ctx context.Context) *HealthStatus {
+	start := time.Now()
+
+	status := &HealthStatus{
+		Timestamp: start,
+		State:     cc.GetState(),
+		Error:     nil,
+		Message:   "",
+		Latency:   0,
+	}
+
+	if cc.adapter.client == nil {
+		status.State = ConnectionStateError
+		status.Error = ErrConsulClientNotInitialized
+		status.Message = "consul client not initialized"
+
+		return status
+	}
+
+	var leader string
+
+	_, err := cc.adapter.do(ctx, http.MethodGet, "/v1/status/leader", nil, nil, &leader)
+	status.Latency = time.Since(start)
+
+	if err != nil {
+		atomic.StoreInt32(&cc.state, int32(ConnectionStateError))
+		status.State = ConnectionStateError
+		status.Error = err
+		status.Message = fmt.Sprintf("consul status check failed: %v", err)
+
+		return status
+	}
+
+	if leader == "" {
+		// The agent answers but the cluster cannot serve consistent reads until a
+		// leader is elected.
+		atomic.StoreInt32(&cc.state, int32(ConnectionStateLive))
+		status.State = ConnectionStateLive
+		status.Message = fmt.Sprintf("consul agent is reachable but has no leader (address=%s)", cc.adapter.config.Address)
+
+		return status
+	}
+
+	atomic.StoreInt32(&cc.state, int32(ConnectionStateReady))
+	status.State = ConnectionStateReady
+	status.Message = fmt.Sprintf(
+		"consul connection is ready (address=%s, leader=%s)",
+		cc.adapter.config.Address,
+		leader,
+	)
+
+	return status
+}
+
+func (cc *ConsulConnection) Close(ctx context.Context) error {
+	atomic.StoreInt32(&cc.state, int32(ConnectionStateDisconnected))
+
+	return cc.adapter.Close(ctx)
+}
+
+func (cc *ConsulConnection) GetRawConnection() any {
+	return cc.adapter
+}
+
+// connect creates the HTTP client. Reachability is verified by the health check
+// afterwards.
+func (cc *ConsulConnection) connect() error {
+	tlsConfig, err := cc.adapter.config.buildTLSConfig()
+	if err != nil {
+		return err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.TLSClientConfig = tlsConfig
+
+	// Watches block for up to WaitTime, so the client timeout only bounds the
+	// requests through their contexts.
+	cc.adapter.client = &http.Client{Transport: transport} //nolint:exhaustruct
+
+	atomic.StoreInt32(&cc.state, int32(ConnectionStateConnected))
+
+	return nil
+}
+
+// Repository interface implementation.
+
+// Get retrieves a value by key. Returns nil if the key does not exist.
+func (ca *ConsulAdapter) Get(ctx context.Context, key string) ([]byte, error) {
+	var pairs []consulKVPair
+
+	found, err := ca.do(ctx, http.MethodGet, ca.kvPath(key), nil, nil, &pairs)
+	if err != nil {
+		return nil, fmt.Errorf("%w (operation=get, key=%q): %w", ErrConsulOperation, key, err)
+	}
+
+	if !found || len(pairs) == 0 {
+		return nil, nil
+	}
+
+	return pairs[0].Value, nil
+}
+
+// Set stores a value with the given key.
+func (ca *ConsulAdapter) Set(ctx context.Context, key string, value []byte) error {
+	_, err := ca.do(ctx, http.MethodPut, ca.kvPath(key), nil, value, nil)
+	if err != nil {
+		return fmt.Errorf("%w (operation=put, key=%q): %w", ErrConsulOperation, key, err)
+	}
+
+	return nil
+}
+
+// Remove deletes the given keys.
+func (ca *ConsulAdapter) Remove(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		_, err := ca.do(ctx, http.MethodDelete, ca.kvPath(key), nil, nil, nil)
+		if err != nil {
+			return fmt.Errorf("%w (operation=delete, key=%q): %w", ErrConsulOperation, key, err)
+		}
+	}
+
+	return nil
+}
+
+// Update updates an existing value by key.
+func (ca *ConsulAdapter) Update(ctx context.Context, key string, value []byte) error {
+	return ca.Set(ctx, key, value)
+}
+
+// Exists checks if a key exists.
+func (ca *ConsulAdapter) Exists(ctx context.Context, key string) (bool, error) {
+	found, err := ca.do(ctx, http.MethodGet, ca.kvPath(key), nil, nil, nil)
+	if err != nil {
+		return false, fmt.Errorf("%w (operation=exists, key=%q): %w", ErrConsulOperation, key, err)
+	}
+
+	return found, nil
+}
+
+// FlushAll deletes every key. When a namespace is configured, only keys inside
+// the namespace are deleted.
+func (ca *ConsulAdapter) FlushAll(ctx context.Context) error {
+	_, err := ca.do(ctx, http.MethodDelete, ca.kvPath(""), url.Values{"recurse": {""}}, nil, nil)
+	if err != nil {
+		return fmt.Errorf("%w (operation=flushall): %w", ErrConsulOperation, err)
+	}
+
+	return nil
+}
+
+// EnsureTableExists is a no-op for Consul since it's schema-less.
+func (ca *ConsulAdapter) EnsureTableExists(
+	ctx context.Context,
+	tableName string,
+	primaryKeyAttributeName string,
+) error {
+	return nil
+}
+
+// Close releases the idle connections of the client.
+func (ca *ConsulAdapter) Close(ctx context.Context) error {
+	if ca.client == nil {
+		return nil
+	}
+
+	ca.client.CloseIdleConnections()
+	ca.client = nil
+
+	return nil
+}
+
+// Eval is not supported by Consul.
+func (ca *ConsulAdapter) Eval(
+	ctx context.Context,
+	script string,
+	keys []string,
+	args ...any,
+) (any, error) {
+	return nil, fmt.Errorf("%w (operation=eval)", ErrConsulUnsupportedOperation)
+}
+
+// ListItems lists all items stored under the table prefix and populates the provided slice.
+func (ca *ConsulAdapter) ListItems(
+	ctx context.Context,
+	tableName string,
+	items any,
+) error {
+	sliceValue := reflect.ValueOf(items)
+	if sliceValue.Kind() != reflect.Ptr || sliceValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("%w (items=%v): %w", ErrConsulOperation, items, ErrExpectedPointerToSlice)
+	}
+
+	sliceElem := sliceValue.Elem()
+	sliceType := sliceElem.Type()
+	elemType := sliceType.Elem()
+
+	pairs, err := ca.list(ctx, consulItemKey(tableName, ""), nil)
+	if err != nil {
+		return fmt.Errorf("%w (operation=list, table=%q): %w", ErrConsulOperation, tableName, err)
+	}
+
+	newSlice := reflect.MakeSlice(sliceType, 0, len(pairs))
+
+	for _, pair := range pairs {
+		newElem := reflect.New(elemType).Interface()
+
+		if err := json.Unmarshal(pair.Value, newElem); err != nil {
+			return fmt.Errorf(
+				"%w: (key=%q, value=%q): %w",
+				ErrCorruptedJSONData,
+				pair.Key,
+				pair.Value,
+				err,
+			)
+		}
+
+		newSlice = reflect.Append(newSlice, reflect.ValueOf(newElem).Elem())
+	}
+
+	sliceElem.Set(newSlice)
+
+	return nil
+}
+
+// GetItem retrieves a specific item from a table by key and populates the provided struct.
+func (ca *ConsulAdapter) GetItem(
+	ctx context.Context,
+	tableName string,
+	pkName string,
+	key string,
+	item any,
+) (bool, error) {
+	itemKey := consulItemKey(tableName, key)
+
+	value, err := ca.Get(ctx, itemKey)
+	if err != nil {
+		return false, err
+	}
+
+	if value == nil {
+		return false, nil // Item not found
+	}
+
+	if err := json.Unmarshal(value, item); err != nil {
+		return false, fmt.Errorf(
+			"%w: (key=%q, value=%q): %w",
+			ErrCorruptedJSONData,
+			itemKey,
+			value,
+			err,
+		)
+	}
+
+	_ = pkName // pkName is not used in Consul key-value storage
+
+	return true, nil
+}
+
+// UpsertItem inserts or updates an item in Consul.
+func (ca *ConsulAdapter) UpsertItem(
+	ctx context.Context,
+	tableName string,
+	pkName string,
+	key string,
+	item any,
+) error {
+	itemKey := consulItemKey(tableName, key)
+
+	jsonValue, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf(
+			"%w: (key=%q, item=%v): %w",
+			ErrCorruptedJSONData,
+			itemKey,
+			item,
+			err,
+		)
+	}
+
+	_ = pkName // pkName is not used in Consul key-value storage
+
+	return ca.Set(ctx, itemKey, jsonValue)
+}
+
+// PrefixRepository interface implementation.
+
+// GetPrefix returns the values of all keys starting with prefix.
+func (ca *ConsulAdapter) GetPrefix(ctx context.Context, prefix string) (map[string][]byte, error) {
+	pairs, err := ca.list(ctx, prefix, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w (operation=get_prefix, prefix=%q): %w", ErrConsulOperation, prefix, err)
+	}
+
+	values := make(map[string][]byte, len(pairs))
+
+	for _, pair := range pairs {
+		values[pair.Key] = pair.Value
+	}
+
+	return values, nil
+}
+
+// WatchRepository interface implementation.
+
+// Watch streams changes to all keys starting with prefix, using blocking queries.
+// Both channels are closed once ctx is done.
+func (ca *ConsulAdapter) Watch(ctx context.Context, prefix string) (<-chan WatchEvent, <-chan error) {
+	events := make(chan WatchEvent, consulWatchBufferSize)
+	errs := make(chan error, 1)
+
+	if ca.client == nil {
+		errs <- fmt.Errorf("%w (prefix=%q)", ErrConsulClientNotInitialized, prefix)
+
+		close(events)
+		close(errs)
+
+		return events, errs
+	}
+
+	go ca.watchPrefix(ctx, prefix, events, errs)
+
+	return events, errs
+}
+
+func (ca *ConsulAdapter) watchPrefix(
+	ctx context.Context,
+	prefix string,
+	events chan<- WatchEvent,
+	errs chan<- error,
+) {
+	defer close(events)
+	defer close(errs)
+
+	var (
+		index    int64
+		previous map[string]consulKVPair
+	)
+
+	for ctx.Err() == nil {
+		query := url.Values{}
+		if previous != nil {
+			query.Set("index", strconv.FormatInt(index, 10))
+			query.Set("wait", ca.config.WaitTime.String())
+		}
+
+		pairs, nextIndex, err := ca.listWithIndex(ctx, prefix, query)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			select {
+			case errs <- fmt.Errorf("%w (prefix=%q): %w", ErrConsulWatchFailed, prefix, err):
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case <-time.After(consulWatchRetryDelay):
+			case <-ctx.Done():
+				return
+			}
+
+			continue
+		}
+
+		current := make(map[string]consulKVPair, len(pairs))
+		for _, pair := range pairs {
+			current[pair.Key] = pair
+		}
+
+		// The first query only records the current values.
+		if previous != nil && !sendConsulChanges(ctx, previous, current, nextIndex, events) {
+			return
+		}
+
+		// The index must not go backwards, or the query would not block.
+		if nextIndex < index || nextIndex <= 0 {
+			nextIndex = 0
+		}
+
+		index = nextIndex
+		previous = current
+	}
+}
+
+// sendConsulChanges sends the differences of two snapshots as watch events. It
+// reports false when ctx is done.
+func sendConsulChanges(
+	ctx context.Context,
+	previous map[string]consulKVPair,
+	current map[string]consulKVPair,
+	index int64,
+	events chan<- WatchEvent,
+) bool {
+	changes := make([]WatchEvent, 0)
+
+	for key, pair := range current {
+		old, existed := previous[key]
+		if existed && old.ModifyIndex == pair.ModifyIndex {
+			continue
+		}
+
+		event := WatchEvent{
+			Type:      WatchEventPut,
+			Key:       key,
+			Value:     pair.Value,
+			PrevValue: nil,
+			Revision:  pair.ModifyIndex,
+		}
+
+		if existed {
+			event.PrevValue = old.Value
+		}
+
+		changes = append(changes, event)
+	}
+
+	for key, old := range previous {
+		if _, exists := current[key]; exists {
+			continue
+		}
+
+		changes = append(changes, WatchEvent{
+			Type:      WatchEventDelete,
+			Key:       key,
+			Value:     nil,
+			PrevValue: old.Value,
+			Revision:  index,
+		})
+	}
+
+	for _, event := range changes {
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	return true
+}
+
+func (ca *ConsulAdapter) list(ctx context.Context, prefix string, query url.Values) ([]consulKVPair, error) {
+	requestCtx, cancel := context.WithTimeout(ctx, ca.config.Timeout)
+	defer cancel()
+
+	pairs, _, err := ca.listWithIndex(requestCtx, prefix, query)
+
+	return pairs, err
+}
+
+// listWithIndex returns the pairs under prefix with the namespace trimmed from their
+// keys, and the index of the response for blocking queries.
+func (ca *ConsulAdapter) listWithIndex(
+	ctx context.Context,
+	prefix string,
+	query url.Values,
+) ([]consulKVPair, int64, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+
+	query.Set("recurse", "")
+
+	var pairs []consulKVPair
+
+	res, err := ca.request(ctx, http.MethodGet, ca.kvPath(prefix), query, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer res.Body.Close() //nolint:errcheck
+
+	index, _ := strconv.ParseInt(res.Header.Get("X-Consul-Index"), 10, 64)
+
+	if res.StatusCode == http.StatusNotFound {
+		return pairs, index, nil
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("%w (status=%d)", ErrConsulUnexpectedStatus, res.StatusCode)
+	}
+
+	err = json.NewDecoder(res.Body).Decode(&pairs)
+	if err != nil {
+		return nil, 0, err //nolint:wrapcheck
+	}
+
+	for i := range pairs {
+		pairs[i].Key = strings.TrimPrefix(pairs[i].Key, ca.config.Namespace)
+	}
+
+	return pairs, index, nil
+}
+
+// do sends a request and decodes the JSON response into out, if given. It reports
+// false when the key or path is not found.
+func (ca *ConsulAdapter) do(
+	ctx context.Context,
+	method string,
+	path string,
+	query url.Values,
+	body []byte,
+	out any,
+) (bool, error) {
+	requestCtx, cancel := context.WithTimeout(ctx, ca.config.Timeout)
+	defer cancel()
+
+	res, err := ca.request(requestCtx, method, path, query, body)
+	if err != nil {
+		return false, err
+	}
+
+	defer res.Body.Close() //nolint:errcheck
+
+	if res.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("%w (status=%d)", ErrConsulUnexpectedStatus, res.StatusCode)
+	}
+
+	if out == nil {
+		_, _ = io.Copy(io.Discard, res.Body)
+
+		return true, nil
+	}
+
+	err = json.NewDecoder(res.Body).Decode(out)
+	if err != nil {
+		return false, err //nolint:wrapcheck
+	}
+
+	return true, nil
+}
+
+func (ca *ConsulAdapter) request(
+	ctx context.Context,
+	method string,
+	path string,
+	query url.Values,
+	body []byte,
+) (*http.Response, error) {
+	if ca.client == nil {
+		return nil, ErrConsulClientNotInitialized
+	}
+
+	if query == nil {
+		query = url.Values{}
+	}
+
+	if ca.config.Datacenter != "" {
+		query.Set("dc", ca.config.Datacenter)
+	}
+
+	target := ca.config.Address + path
+	if encoded := query.Encode(); encoded != "" {
+		target += "?" + encoded
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if ca.config.Token != "" {
+		req.Header.Set("X-Consul-Token", ca.config.Token)
+	}
+
+	return ca.client.Do(req) //nolint:wrapcheck
+}
+
+func (ca *ConsulAdapter) kvPath(key string) string {
+	return "/v1/kv/" + (&url.URL{Path: ca.config.Namespace + key}).EscapedPath() //nolint:exhaustruct
+}
+
+func (c *ConsulConfig) buildTLSConfig() (*tls.Config, error) {
+	if !strings.HasPrefix(c.Address, "https://") {
+		return nil, nil //nolint:nilnil
+	}
+
+	tlsConfig := &tls.Config{ //nolint:exhaustruct
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.TLSInsecureSkipVerify, //nolint:gosec
+	}
+
+	if c.CAFile != "" {
+		caCert, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w (path=%q): %w", ErrFailedToLoadCACertificate, c.CAFile, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("%w (path=%q)", ErrFailedToLoadCACertificate, c.CAFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+func consulItemKey(tableName string, key string) string {
+	return tableName + "/" + key
+}
+
+// ConsulConnectionFactory creates Consul connections.
+type ConsulConnectionFactory struct {
+	protocol string
+}
+
+// NewConsulConnectionFactory creates a new Consul connection factory for a specific protocol.
+func NewConsulConnectionFactory(protocol string) *ConsulConnectionFactory {
+	return &ConsulConnectionFactory{
+		protocol: protocol,
+	}
+}
+
+func (f *ConsulConnectionFactory) CreateConnection( //nolint:ireturn
+	ctx context.Context,
+	config *ConfigTarget,
+) (Connection, error) {
+	consulConfig, err := f.BuildConsulConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToCreateConsulClient, err)
+	}
+
+	conn := NewConsulConnection(f.protocol, consulConfig)
+
+	if err := conn.connect(); err != nil {
+		return nil, err
+	}
+
+	// Test the connection
+	status := conn.HealthCheck(ctx)
+	if status.State == ConnectionStateError {
+		_ = conn.Close(ctx)
+
+		return nil, fmt.Errorf("%w: %w", ErrConsulConnectionFailed, status.Error)
+	}
+
+	return conn, nil
+}
+
+func (f *ConsulConnectionFactory) GetProtocol() string {
+	return f.protocol
+}
+
+// BuildConsulConfig builds a Consul configuration from a connection target. The DSN
+// accepts consul:// and consuls:// URLs with an optional ACL token as the user and a
+// key namespace as the path, e.g. consul://token@consul:8500/services?dc=eu-west.
+func (f *ConsulConnectionFactory) BuildConsulConfig(config *ConfigTarget) (*ConsulConfig, error) {
+	consulConfig := NewDefaultConsulConfig()
+
+	switch {
+	case config.DSN != "":
+		if err := f.parseConsulDSN(consulConfig, config.DSN); err != nil {
+			return nil, err
+		}
+	case config.URL != "":
+		if err := f.parseConsulDSN(consulConfig, config.URL); err != nil {
+			return nil, err
+		}
+	case config.Host != "":
+		scheme := "http"
+		if config.TLS {
+			scheme = "https"
+		}
+
+		consulConfig.Address = fmt.Sprintf(
+			"%s://%s:%d",
+			scheme,
+			config.Host,
+			getOrDefault(config.Port, defaultConsulPort),
+		)
+	}
+
+	consulConfig.TLSInsecureSkipVerify = config.TLSSkipVerify
+	consulConfig.CAFile = config.CAFile
+
+	if config.Timeout > 0 {
+		consulConfig.Timeout = config.Timeout
+	}
+
+	if config.Properties != nil {
+		f.configureFromProperties(consulConfig, config.Properties)
+	}
+
+	return consulConfig, nil
+}
+
+func (f *ConsulConnectionFactory) parseConsulDSN(consulConfig *ConsulConfig, dsn string) error {
+	parsedURL, err := url.Parse(dsn)
+	if err != nil {
+		return fmt.Errorf("%w (dsn=%q): %w", ErrInvalidDSN, dsn, err)
+	}
+
+	if parsedURL.Host == "" {
+		return fmt.Errorf("%w (dsn=%q)", ErrInvalidDSN, dsn)
+	}
+
+	scheme := "http"
+	if parsedURL.Scheme == "consuls" || parsedURL.Scheme == "https" {
+		scheme = "https"
+	}
+
+	host := parsedURL.Host
+	if parsedURL.Port() == "" {
+		host += ":" + strconv.Itoa(defaultConsulPort)
+	}
+
+	consulConfig.Address = scheme + "://" + host
+
+	if parsedURL.User != nil {
+		consulConfig.Token = parsedURL.User.Username()
+	}
+
+	if ns := strings.TrimPrefix(parsedURL.Path, "/"); ns != "" {
+		consulConfig.Namespace = strings.TrimSuffix(ns, "/") + "/"
+	}
+
+	if dc := parsedURL.Query().Get("dc"); dc != "" {
+		consulConfig.Datacenter = dc
+	}
+
+	return nil
+}
+
+func (f *ConsulConnectionFactory) configureFromProperties(
+	consulConfig *ConsulConfig,
+	properties map[string]any,
+) {
+	if token, ok := properties["token"].(string); ok {
+		consulConfig.Token = token
+	}
+
+	if datacenter, ok := properties["datacenter"].(string); ok {
+		consulConfig.Datacenter = datacenter
+	}
+
+	if ns, ok := properties["namespace"].(string); ok {
+		consulConfig.Namespace = ns
+	}
+
+	if waitTime, ok := properties["wait_time"].(time.Duration); ok {
+		consulConfig.WaitTime = waitTime
+	}
+}
@@ -0,0 +1,294 @@
+package connfx_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConsul serves the parts of the Consul HTTP API the adapter uses, including
+// blocking queries on the key-value store.
+type fakeConsul struct {
+	values  map[string][]byte
+	indices map[string]int64
+	changed chan struct{}
+	index   int64
+	mu      sync.Mutex
+}
+
+func newFakeConsul() *fakeConsul {
+	return &fakeConsul{
+		values:  make(map[string][]byte),
+		indices: make(map[string]int64),
+		changed: make(chan struct{}),
+		index:   1,
+		mu:      sync.Mutex{},
+	}
+}
+
+func (f *fakeConsul) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Consul-Token") != "secret" {
+		w.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	if r.URL.Path == "/v1/status/leader" {
+		_, _ = w.Write([]byte(`"10.0.0.1:8300"`))
+
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+	_, recurse := r.URL.Query()["recurse"]
+
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+
+		f.mu.Lock()
+		f.index++
+		f.values[key] = body
+		f.indices[key] = f.index
+		f.notify()
+		f.mu.Unlock()
+
+		_, _ = w.Write([]byte("true"))
+	case http.MethodDelete:
+		f.mu.Lock()
+		f.index++
+
+		for existing := range f.values {
+			if existing == key || (recurse && strings.HasPrefix(existing, key)) {
+				delete(f.values, existing)
+				delete(f.indices, existing)
+			}
+		}
+
+		f.notify()
+		f.mu.Unlock()
+
+		_, _ = w.Write([]byte("true"))
+	default:
+		f.get(w, r, key, recurse)
+	}
+}
+
+func (f *fakeConsul) get(w http.ResponseWriter, r *http.Request, key string, recurse bool) {
+	waitIndex, _ := strconv.ParseInt(r.URL.Query().Get("index"), 10, 64)
+
+	f.mu.Lock()
+	if waitIndex > 0 && waitIndex >= f.index {
+		changed := f.changed
+		f.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+			return
+		}
+
+		f.mu.Lock()
+	}
+	defer f.mu.Unlock()
+
+	pairs := make([]map[string]any, 0)
+
+	for _, existing := range slices.Sorted(func(yield func(string) bool) {
+		for k := range f.values {
+			if !yield(k) {
+				return
+			}
+		}
+	}) {
+		if existing == key || (recurse && strings.HasPrefix(existing, key)) {
+			pairs = append(pairs, map[string]any{
+				"Key":         existing,
+				"Value":       f.values[existing],
+				"ModifyIndex": f.indices[existing],
+			})
+		}
+	}
+
+	w.Header().Set("X-Consul-Index", strconv.FormatInt(f.index, 10))
+
+	if len(pairs) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(pairs)
+}
+
+func (f *fakeConsul) notify() {
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func TestConsulAdapter_BuildConfig(t *testing.T) {
+	t.Parallel()
+
+	factory := connfx.NewConsulConnectionFactory("consul")
+	assert.Equal(t, "consul", factory.GetProtocol())
+
+	t.Run("dsn with token, namespace and datacenter", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := factory.BuildConsulConfig(&connfx.ConfigTarget{ //nolint:exhaustruct
+			Protocol: "consul",
+			DSN:      "consuls://acl-token@consul.internal/services?dc=eu-west",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "https://consul.internal:8500", config.Address)
+		assert.Equal(t, "acl-token", config.Token)
+		assert.Equal(t, "services/", config.Namespace)
+		assert.Equal(t, "eu-west", config.Datacenter)
+	})
+
+	t.Run("host and port", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := factory.BuildConsulConfig(&connfx.ConfigTarget{ //nolint:exhaustruct
+			Protocol: "consul",
+			Host:     "localhost",
+			Port:     8501,
+			Properties: map[string]any{
+				"token":     "secret",
+				"wait_time": time.Minute,
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "http://localhost:8501", config.Address)
+		assert.Equal(t, "secret", config.Token)
+		assert.Equal(t, time.Minute, config.WaitTime)
+		assert.Equal(t, connfx.DefaultConsulTimeout, config.Timeout)
+	})
+
+	t.Run("dsn without host", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := factory.BuildConsulConfig(&connfx.ConfigTarget{ //nolint:exhaustruct
+			Protocol: "consul",
+			DSN:      "consul:///services",
+		})
+		require.ErrorIs(t, err, connfx.ErrInvalidDSN)
+	})
+}
+
+func TestConsulAdapter_KeyValue(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(newFakeConsul())
+	defer server.Close()
+
+	registry := connfx.NewRegistry(
+		connfx.WithLogger(newMockLogger()),
+		connfx.WithDefaultFactories(),
+	)
+
+	_, err := registry.AddConnection(t.Context(), "config", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "consul",
+		URL:      strings.Replace(server.URL, "http://", "consul://secret@", 1) + "/app",
+	})
+	require.NoError(t, err)
+
+	kv, err := connfx.GetPrefix(registry, "config")
+	require.NoError(t, err)
+
+	repo, ok := kv.(connfx.Repository)
+	require.True(t, ok)
+
+	ctx := t.Context()
+
+	require.NoError(t, repo.Set(ctx, "log/level", []byte("debug")))
+	require.NoError(t, repo.Set(ctx, "http/port", []byte("8080")))
+
+	value, err := repo.Get(ctx, "log/level")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("debug"), value)
+
+	value, err = repo.Get(ctx, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	exists, err := repo.Exists(ctx, "http/port")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	values, err := kv.GetPrefix(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"log/level": []byte("debug"), "http/port": []byte("8080")}, values)
+
+	require.NoError(t, repo.Remove(ctx, "http/port"))
+
+	values, err = kv.GetPrefix(ctx, "http/")
+	require.NoError(t, err)
+	assert.Empty(t, values)
+}
+
+func TestConsulAdapter_Watch(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(newFakeConsul())
+	defer server.Close()
+
+	conn := connfx.NewConsulConnectionFactory("consul")
+
+	connection, err := conn.CreateConnection(t.Context(), &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol:   "consul",
+		URL:        server.URL,
+		Properties: map[string]any{"token": "secret"},
+	})
+	require.NoError(t, err)
+
+	adapter, ok := connection.GetRawConnection().(*connfx.ConsulAdapter)
+	require.True(t, ok)
+
+	ctx := t.Context()
+
+	require.NoError(t, adapter.Set(ctx, "flags/beta", []byte("off")))
+
+	events, errs := adapter.Watch(ctx, "flags/")
+
+	// Let the watch record the current values before changing them.
+	time.Sleep(100 * time.Millisecond)
+
+	require.NoError(t, adapter.Set(ctx, "flags/beta", []byte("on")))
+
+	select {
+	case event := <-events:
+		assert.Equal(t, connfx.WatchEventPut, event.Type)
+		assert.Equal(t, "flags/beta", event.Key)
+		assert.Equal(t, []byte("on"), event.Value)
+		assert.Equal(t, []byte("off"), event.PrevValue)
+	case err := <-errs:
+		t.Fatalf("watch failed: %v", err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no watch event")
+	}
+
+	require.NoError(t, adapter.Remove(ctx, "flags/beta"))
+
+	select {
+	case event := <-events:
+		assert.Equal(t, connfx.WatchEventDelete, event.Type)
+		assert.Equal(t, "flags/beta", event.Key)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no delete event")
+	}
+}
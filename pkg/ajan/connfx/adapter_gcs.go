@@ -0,0 +1,526 @@
+package connfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+const DefaultGCSTimeout = 10 * time.Second
+
+var (
+	ErrGCSClientNotInitialized = errors.New("GCS client not initialized")
+	ErrGCSConnectionFailed     = errors.New("failed to connect to GCS")
+	ErrFailedToCreateGCSClient = errors.New("failed to create GCS client")
+	ErrFailedToCloseGCSClient  = errors.New("failed to close GCS client")
+	ErrGCSOperation            = errors.New("GCS operation failed")
+	ErrGCSNoBucket             = errors.New("no GCS bucket configured")
+)
+
+// GCSConfig holds Google Cloud Storage-specific configuration options.
+type GCSConfig struct {
+	Bucket          string
+	ProjectID       string // only required when the bucket is created on connect
+	CredentialsFile string
+	CredentialsJSON string
+	Endpoint        string // overrides the API endpoint, e.g. for fake-gcs-server
+	Timeout         time.Duration
+	CreateBucket    bool
+	WithoutAuth     bool
+}
+
+// NewDefaultGCSConfig creates a GCS configuration with sensible defaults.
+func NewDefaultGCSConfig() *GCSConfig {
+	return &GCSConfig{
+		Bucket:          "",
+		ProjectID:       "",
+		CredentialsFile: "",
+		CredentialsJSON: "",
+		Endpoint:        "",
+		Timeout:         DefaultGCSTimeout,
+		CreateBucket:    false,
+		WithoutAuth:     false,
+	}
+}
+
+// GCSAdapter implements the ObjectStorageRepository interface for a single
+// Google Cloud Storage bucket.
+type GCSAdapter struct {
+	client *storage.Client
+	bucket *storage.BucketHandle
+	config *GCSConfig
+}
+
+// GCSConnection implements the connfx.Connection interface for Google Cloud Storage.
+type GCSConnection struct {
+	adapter  *GCSAdapter
+	protocol string
+	state    int32 // atomic field for connection state
+}
+
+// NewGCSConnection creates a new GCS connection.
+func NewGCSConnection(protocol string, config *GCSConfig) *GCSConnection {
+	if config == nil {
+		config = NewDefaultGCSConfig()
+	}
+
+	adapter := &GCSAdapter{
+		client: nil, // Will be initialized on connect
+		bucket: nil,
+		config: config,
+	}
+
+	return &GCSConnection{
+		adapter:  adapter,
+		protocol: protocol,
+		state:    int32(ConnectionStateNotInitialized),
+	}
+}
+
+// Connection interface implementation.
+func (gc *GCSConnection) GetBehaviors() []ConnectionBehavior {
+	return []ConnectionBehavior{
+		ConnectionBehaviorStateless,
+	}
+}
+
+func (gc *GCSConnection) GetCapabilities() []ConnectionCapability {
+	return []ConnectionCapability{
+		ConnectionCapabilityObjectStorage,
+	}
+}
+
+func (gc *GCSConnection) GetProtocol() string {
+	return gc.protocol
+}
+
+func (gc *GCSConnection) GetState() ConnectionState {
+	return ConnectionState(atomic.LoadInt32(&gc.state))
+}
+
+func (gc *GCSConnection) HealthCheck(ctx context.Context) *HealthStatus {
+	start := time.Now()
+
+	status := &HealthStatus{
+		Timestamp: start,
+		State:     gc.GetState(),
+		Error:     nil,
+		Message:   "",
+		Latency:   0,
+	}
+
+	if gc.adapter.bucket == nil {
+		status.State = ConnectionStateError
+		status.Error = ErrGCSClientNotInitialized
+		status.Message = "GCS client not initialized"
+
+		return status
+	}
+
+	healthCtx, cancel := context.WithTimeout(ctx, gc.adapter.config.Timeout)
+	defer cancel()
+
+	_, err := gc.adapter.bucket.Attrs(healthCtx)
+	status.Latency = time.Since(start)
+
+	if err != nil {
+		if errors.Is(err, storage.ErrBucketNotExist) {
+			// The API is reachable and authorized, but the bucket is missing.
+			atomic.StoreInt32(&gc.state, int32(ConnectionStateLive))
+			status.State = ConnectionStateLive
+			status.Error = err
+			status.Message = fmt.Sprintf("GCS bucket does not exist (bucket=%s)", gc.adapter.config.Bucket)
+
+			return status
+		}
+
+		atomic.StoreInt32(&gc.state, int32(ConnectionStateError))
+		status.State = ConnectionStateError
+		status.Error = err
+		status.Message = fmt.Sprintf("GCS health check failed: %v", err)
+
+		return status
+	}
+
+	atomic.StoreInt32(&gc.state, int32(ConnectionStateReady))
+	status.State = ConnectionStateReady
+	status.Message = fmt.Sprintf("GCS connection is ready (bucket=%s)", gc.adapter.config.Bucket)
+
+	return status
+}
+
+func (gc *GCSConnection) Close(ctx context.Context) error {
+	atomic.StoreInt32(&gc.state, int32(ConnectionStateDisconnected))
+
+	return gc.adapter.Close()
+}
+
+func (gc *GCSConnection) GetRawConnection() any {
+	return gc.adapter
+}
+
+// GetClient returns the underlying GCS client.
+func (gc *GCSConnection) GetClient() *storage.Client {
+	return gc.adapter.client
+}
+
+// connect creates the storage client and optionally the bucket itself.
+func (gc *GCSConnection) connect(ctx context.Context) error {
+	client, err := storage.NewClient(ctx, gc.adapter.config.clientOptions()...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToCreateGCSClient, err)
+	}
+
+	gc.adapter.client = client
+	gc.adapter.bucket = client.Bucket(gc.adapter.config.Bucket)
+
+	if gc.adapter.config.CreateBucket {
+		createCtx, cancel := context.WithTimeout(ctx, gc.adapter.config.Timeout)
+		defer cancel()
+
+		err := gc.adapter.bucket.Create(createCtx, gc.adapter.config.ProjectID, nil)
+		if err != nil && !isGCSAlreadyExists(err) {
+			_ = gc.adapter.Close()
+
+			return fmt.Errorf(
+				"%w (operation=create_bucket, bucket=%q): %w",
+				ErrGCSOperation,
+				gc.adapter.config.Bucket,
+				err,
+			)
+		}
+	}
+
+	atomic.StoreInt32(&gc.state, int32(ConnectionStateConnected))
+
+	return nil
+}
+
+// Close closes the underlying GCS client.
+func (ga *GCSAdapter) Close() error {
+	if ga.client == nil {
+		return nil
+	}
+
+	err := ga.client.Close()
+
+	ga.client = nil
+	ga.bucket = nil
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToCloseGCSClient, err)
+	}
+
+	return nil
+}
+
+// ObjectStorageRepository interface implementation.
+
+// PutObject uploads an object, replacing it if it already exists.
+func (ga *GCSAdapter) PutObject(
+	ctx context.Context,
+	key string,
+	body io.Reader,
+	options *PutObjectOptions,
+) error {
+	if ga.bucket == nil {
+		return fmt.Errorf("%w (key=%q)", ErrGCSClientNotInitialized, key)
+	}
+
+	// Cancelling the writer's context is the only way to abort a partial upload.
+	writeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	writer := ga.bucket.Object(key).NewWriter(writeCtx)
+
+	if options != nil {
+		writer.ContentType = options.ContentType
+		writer.Metadata = options.Metadata
+	}
+
+	if _, err := io.Copy(writer, body); err != nil {
+		cancel()
+		_ = writer.Close()
+
+		return fmt.Errorf("%w (operation=put, key=%q): %w", ErrGCSOperation, key, err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("%w (operation=put, key=%q): %w", ErrGCSOperation, key, err)
+	}
+
+	return nil
+}
+
+// GetObject opens an object for reading. The caller must close the returned reader.
+// The returned info carries no metadata or ETag; use StatObject for those.
+func (ga *GCSAdapter) GetObject(
+	ctx context.Context,
+	key string,
+) (io.ReadCloser, *ObjectInfo, error) {
+	if ga.bucket == nil {
+		return nil, nil, fmt.Errorf("%w (key=%q)", ErrGCSClientNotInitialized, key)
+	}
+
+	reader, err := ga.bucket.Object(key).NewReader(ctx)
+	if err != nil {
+		return nil, nil, ga.wrapError("get", key, err)
+	}
+
+	info := &ObjectInfo{
+		LastModified: reader.Attrs.LastModified,
+		Metadata:     nil,
+		Key:          key,
+		ContentType:  reader.Attrs.ContentType,
+		ETag:         "",
+		Size:         reader.Attrs.Size,
+	}
+
+	return reader, info, nil
+}
+
+// StatObject returns object metadata without downloading its content.
+func (ga *GCSAdapter) StatObject(ctx context.Context, key string) (*ObjectInfo, error) {
+	if ga.bucket == nil {
+		return nil, fmt.Errorf("%w (key=%q)", ErrGCSClientNotInitialized, key)
+	}
+
+	attrs, err := ga.bucket.Object(key).Attrs(ctx)
+	if err != nil {
+		return nil, ga.wrapError("stat", key, err)
+	}
+
+	return gcsObjectInfo(attrs), nil
+}
+
+// DeleteObject removes an object. Deleting a missing object is not an error.
+func (ga *GCSAdapter) DeleteObject(ctx context.Context, key string) error {
+	if ga.bucket == nil {
+		return fmt.Errorf("%w (key=%q)", ErrGCSClientNotInitialized, key)
+	}
+
+	err := ga.bucket.Object(key).Delete(ctx)
+	if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+		return fmt.Errorf("%w (operation=delete, key=%q): %w", ErrGCSOperation, key, err)
+	}
+
+	return nil
+}
+
+// ObjectExists checks if an object exists.
+func (ga *GCSAdapter) ObjectExists(ctx context.Context, key string) (bool, error) {
+	_, err := ga.StatObject(ctx, key)
+	if err != nil {
+		if errors.Is(err, ErrObjectNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ListObjects lists the objects whose names start with the given prefix.
+func (ga *GCSAdapter) ListObjects(ctx context.Context, prefix string) ([]*ObjectInfo, error) {
+	if ga.bucket == nil {
+		return nil, fmt.Errorf("%w (prefix=%q)", ErrGCSClientNotInitialized, prefix)
+	}
+
+	objects := make([]*ObjectInfo, 0)
+
+	it := ga.bucket.Objects(ctx, &storage.Query{Prefix: prefix}) //nolint:exhaustruct
+
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w (operation=list, prefix=%q): %w", ErrGCSOperation, prefix, err)
+		}
+
+		objects = append(objects, gcsObjectInfo(attrs))
+	}
+
+	return objects, nil
+}
+
+func (ga *GCSAdapter) wrapError(operation string, key string, err error) error {
+	if errors.Is(err, storage.ErrObjectNotExist) || errors.Is(err, storage.ErrBucketNotExist) {
+		return fmt.Errorf("%w (key=%q): %w", ErrObjectNotFound, key, err)
+	}
+
+	return fmt.Errorf("%w (operation=%s, key=%q): %w", ErrGCSOperation, operation, key, err)
+}
+
+func (c *GCSConfig) clientOptions() []option.ClientOption {
+	options := make([]option.ClientOption, 0)
+
+	if c.Endpoint != "" {
+		options = append(options, option.WithEndpoint(c.Endpoint))
+	}
+
+	switch {
+	case c.WithoutAuth:
+		options = append(options, option.WithoutAuthentication())
+	case c.CredentialsJSON != "":
+		options = append(options, option.WithCredentialsJSON([]byte(c.CredentialsJSON)))
+	case c.CredentialsFile != "":
+		options = append(options, option.WithCredentialsFile(c.CredentialsFile))
+	}
+
+	return options
+}
+
+func gcsObjectInfo(attrs *storage.ObjectAttrs) *ObjectInfo {
+	return &ObjectInfo{
+		LastModified: attrs.Updated,
+		Metadata:     attrs.Metadata,
+		Key:          attrs.Name,
+		ContentType:  attrs.ContentType,
+		ETag:         attrs.Etag,
+		Size:         attrs.Size,
+	}
+}
+
+func isGCSAlreadyExists(err error) bool {
+	var apiErr *googleapi.Error
+
+	// The JSON API reports an existing bucket as 409 Conflict.
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusConflict
+}
+
+// GCSConnectionFactory creates Google Cloud Storage connections.
+type GCSConnectionFactory struct {
+	protocol string
+}
+
+// NewGCSConnectionFactory creates a new GCS connection factory for a specific protocol.
+func NewGCSConnectionFactory(protocol string) *GCSConnectionFactory {
+	return &GCSConnectionFactory{
+		protocol: protocol,
+	}
+}
+
+func (f *GCSConnectionFactory) CreateConnection( //nolint:ireturn
+	ctx context.Context,
+	config *ConfigTarget,
+) (Connection, error) {
+	gcsConfig, err := f.BuildGCSConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToCreateGCSClient, err)
+	}
+
+	conn := NewGCSConnection(f.protocol, gcsConfig)
+
+	if err := conn.connect(ctx); err != nil {
+		return nil, err
+	}
+
+	// Test the connection
+	status := conn.HealthCheck(ctx)
+	if status.State == ConnectionStateError {
+		_ = conn.Close(ctx)
+
+		return nil, fmt.Errorf("%w: %w", ErrGCSConnectionFailed, status.Error)
+	}
+
+	return conn, nil
+}
+
+func (f *GCSConnectionFactory) GetProtocol() string {
+	return f.protocol
+}
+
+// BuildGCSConfig builds a GCS configuration from a connection target. The DSN has
+// the form gs://bucket; Host/Port (or URL) override the API endpoint, which is
+// mostly useful for emulators such as fake-gcs-server.
+func (f *GCSConnectionFactory) BuildGCSConfig(config *ConfigTarget) (*GCSConfig, error) {
+	gcsConfig := NewDefaultGCSConfig()
+
+	if config.DSN != "" {
+		parsedURL, err := url.Parse(config.DSN)
+		if err != nil {
+			return nil, fmt.Errorf("%w (dsn=%q): %w", ErrInvalidDSN, config.DSN, err)
+		}
+
+		gcsConfig.Bucket = parsedURL.Host
+	}
+
+	switch {
+	case config.URL != "":
+		gcsConfig.Endpoint = config.URL
+	case config.Host != "":
+		scheme := "http"
+		if config.TLS {
+			scheme = "https"
+		}
+
+		host := config.Host
+		if config.Port != 0 {
+			host += ":" + strconv.Itoa(config.Port)
+		}
+
+		gcsConfig.Endpoint = scheme + "://" + host + "/storage/v1/"
+	}
+
+	if config.Timeout > 0 {
+		gcsConfig.Timeout = config.Timeout
+	}
+
+	if config.Properties != nil {
+		f.configureFromProperties(gcsConfig, config.Properties)
+	}
+
+	if gcsConfig.Bucket == "" {
+		return nil, ErrGCSNoBucket
+	}
+
+	return gcsConfig, nil
+}
+
+func (f *GCSConnectionFactory) configureFromProperties(
+	gcsConfig *GCSConfig,
+	properties map[string]any,
+) {
+	if bucket, ok := properties["bucket"].(string); ok {
+		gcsConfig.Bucket = bucket
+	}
+
+	if projectID, ok := properties["project_id"].(string); ok {
+		gcsConfig.ProjectID = projectID
+	}
+
+	if credentialsFile, ok := properties["credentials_file"].(string); ok {
+		gcsConfig.CredentialsFile = credentialsFile
+	}
+
+	if credentialsJSON, ok := properties["credentials_json"].(string); ok {
+		gcsConfig.CredentialsJSON = credentialsJSON
+	}
+
+	if endpoint, ok := properties["endpoint"].(string); ok {
+		gcsConfig.Endpoint = endpoint
+	}
+
+	if withoutAuth, ok := properties["without_authentication"].(bool); ok {
+		gcsConfig.WithoutAuth = withoutAuth
+	}
+
+	if createBucket, ok := properties["create_bucket"].(bool); ok {
+		gcsConfig.CreateBucket = createBucket
+	}
+}
@@ -0,0 +1,118 @@
+package connfx_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGCSAdapter_BuildConfig(t *testing.T) {
+	t.Parallel()
+
+	factory := connfx.NewGCSConnectionFactory("gcs")
+	assert.Equal(t, "gcs", factory.GetProtocol())
+
+	t.Run("dsn with properties", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := factory.BuildGCSConfig(&connfx.ConfigTarget{ //nolint:exhaustruct
+			Protocol: "gcs",
+			DSN:      "gs://aya-uploads",
+			Properties: map[string]any{
+				"project_id":       "aya-is",
+				"credentials_file": "/etc/aya/gcs.json",
+				"create_bucket":    true,
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "aya-uploads", config.Bucket)
+		assert.Equal(t, "aya-is", config.ProjectID)
+		assert.Equal(t, "/etc/aya/gcs.json", config.CredentialsFile)
+		assert.Empty(t, config.Endpoint)
+		assert.True(t, config.CreateBucket)
+		assert.Equal(t, connfx.DefaultGCSTimeout, config.Timeout)
+	})
+
+	t.Run("emulator host", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := factory.BuildGCSConfig(&connfx.ConfigTarget{ //nolint:exhaustruct
+			Protocol: "gcs",
+			DSN:      "gs://aya-uploads",
+			Host:     "localhost",
+			Port:     4443,
+			Properties: map[string]any{
+				"without_authentication": true,
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "http://localhost:4443/storage/v1/", config.Endpoint)
+		assert.True(t, config.WithoutAuth)
+	})
+
+	t.Run("missing bucket", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := factory.BuildGCSConfig(&connfx.ConfigTarget{ //nolint:exhaustruct
+			Protocol: "gcs",
+		})
+		require.ErrorIs(t, err, connfx.ErrGCSNoBucket)
+	})
+}
+
+func TestGCSAdapter_MissingBucket(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"code":404,"message":"The specified bucket does not exist."}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	factory := connfx.NewGCSConnectionFactory("gcs")
+
+	conn, err := factory.CreateConnection(t.Context(), &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "gcs",
+		DSN:      "gs://aya-uploads",
+		URL:      server.URL + "/storage/v1/",
+		Timeout:  2 * time.Second,
+		Properties: map[string]any{
+			"without_authentication": true,
+		},
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = conn.Close(t.Context()) })
+
+	assert.Equal(t, connfx.ConnectionStateLive, conn.GetState())
+	assert.Contains(t, conn.GetCapabilities(), connfx.ConnectionCapabilityObjectStorage)
+
+	_, ok := conn.GetRawConnection().(connfx.ObjectStorageRepository)
+	assert.True(t, ok)
+}
+
+func TestGCSAdapter_Unreachable(t *testing.T) {
+	t.Parallel()
+
+	factory := connfx.NewGCSConnectionFactory("gcs")
+
+	_, err := factory.CreateConnection(t.Context(), &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "gcs",
+		DSN:      "gs://aya-uploads",
+		Host:     "127.0.0.1",
+		Port:     1,
+		Timeout:  200 * time.Millisecond,
+		Properties: map[string]any{
+			"without_authentication": true,
+		},
+	})
+	require.ErrorIs(t, err, connfx.ErrGCSConnectionFailed)
+}
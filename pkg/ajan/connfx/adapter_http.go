@@ -52,12 +52,13 @@ var (
 
 // HTTPConnection represents an HTTP API connection with resilience features.
 type HTTPConnection struct {
-	lastHealth time.Time
-	client     *httpclient.Client
-	headers    map[string]string
-	protocol   string
-	baseURL    string
-	state      int32 // atomic field for connection state
+	lastHealth   time.Time
+	client       *httpclient.Client
+	certificates *certificateFiles
+	headers      map[string]string
+	protocol     string
+	baseURL      string
+	state        int32 // atomic field for connection state
 }
 
 // HTTPConnectionFactory creates HTTP connections.
@@ -76,8 +77,19 @@ func (f *HTTPConnectionFactory) CreateConnection( //nolint:ireturn
 	ctx context.Context,
 	config *ConfigTarget,
 ) (Connection, error) {
+	var certificates *certificateFiles
+
+	if config.TLS || config.TLSSkipVerify {
+		var err error
+
+		certificates, err = newCertificateFiles(config.CertFile, config.KeyFile, config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrFailedToCreateHTTPClient, err)
+		}
+	}
+
 	// Create resilient HTTP client with configuration
-	client, headers, err := f.buildResilientHTTPClient(config)
+	client, headers, err := f.buildResilientHTTPClient(config, certificates)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrFailedToCreateHTTPClient, err)
 	}
@@ -86,12 +98,13 @@ func (f *HTTPConnectionFactory) CreateConnection( //nolint:ireturn
 
 	// Initial health check
 	conn := &HTTPConnection{
-		protocol:   f.protocol,
-		client:     client,
-		baseURL:    baseURL,
-		headers:    headers,
-		state:      int32(ConnectionStateConnected),
-		lastHealth: time.Time{},
+		protocol:     f.protocol,
+		client:       client,
+		certificates: certificates,
+		baseURL:      baseURL,
+		headers:      headers,
+		state:        int32(ConnectionStateConnected),
+		lastHealth:   time.Time{},
 	}
 
 	// Perform initial health check
@@ -107,50 +120,33 @@ func (f *HTTPConnectionFactory) GetProtocol() string {
 	return f.protocol
 }
 
-func (f *HTTPConnectionFactory) buildResilientHTTPClient( //nolint:cyclop
+func (f *HTTPConnectionFactory) buildResilientHTTPClient(
 	config *ConfigTarget,
+	certificates *certificateFiles,
 ) (*httpclient.Client, map[string]string, error) {
 	// Create resilient HTTP client configuration
 	clientConfig := f.buildClientConfig(config)
 
+	if err := clientConfig.Proxy.Validate(); err != nil {
+		return nil, nil, err //nolint:wrapcheck
+	}
+
 	// Create resilient client with custom transport
 	clientOptions := []httpclient.NewClientOption{
 		httpclient.WithConfig(clientConfig),
 	}
 
 	if config.TLS || config.TLSSkipVerify {
-		tlsConfig := &tls.Config{ //nolint:exhaustruct
+		// Client certificates are served from certificates so they can be rotated.
+		tlsConfig := certificates.clientConfig(&tls.Config{ //nolint:exhaustruct
 			InsecureSkipVerify: config.TLSSkipVerify, //nolint:gosec
-		}
-
-		// Load client certificates if provided
-		if config.CertFile != "" && config.KeyFile != "" {
-			cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
-			if err != nil {
-				return nil, nil, fmt.Errorf(
-					"%w (cert_file=%q, key_file=%q): %w",
-					ErrFailedToLoadCertificate,
-					config.CertFile,
-					config.KeyFile,
-					err,
-				)
-			}
-
-			tlsConfig.Certificates = []tls.Certificate{cert}
-		}
+		})
 
 		clientOptions = append(clientOptions, httpclient.WithTLSClientConfig(tlsConfig))
 	}
 
 	client := httpclient.NewClient(clientOptions...)
 
-	// Set timeout
-	if config.Timeout > 0 {
-		client.Timeout = config.Timeout
-	} else {
-		client.Timeout = DefaultHTTPTimeout
-	}
-
 	// Build default headers
 	headers := make(map[string]string)
 	headers["User-Agent"] = "connfx-http-client/1.0"
@@ -190,12 +186,23 @@ func (f *HTTPConnectionFactory) buildClientConfig(config *ConfigTarget) *httpcli
 			RandomFactor:    DefaultRetryRandomFactor,
 		},
 		ServerErrorThreshold: DefaultServerErrorThreshold,
+		Timeout:              DefaultHTTPTimeout,
+		HedgeDelay:           0,
+		Cache:                httpclient.CacheConfig{},     //nolint:exhaustruct
+		RateLimit:            httpclient.RateLimitConfig{}, //nolint:exhaustruct
+		Proxy:                httpclient.ProxyConfig{},     //nolint:exhaustruct
+	}
+
+	// Set in the transport rather than on http.Client, so requests can override it.
+	if config.Timeout > 0 {
+		clientConfig.Timeout = config.Timeout
 	}
 
 	if config.Properties != nil {
 		f.applyCircuitBreakerConfig(clientConfig, config.Properties)
 		f.applyRetryStrategyConfig(clientConfig, config.Properties)
 		f.applyServerErrorThreshold(clientConfig, config.Properties)
+		f.applyProxyConfig(clientConfig, config.Properties)
 	}
 
 	return clientConfig
@@ -270,6 +277,27 @@ func (f *HTTPConnectionFactory) applyServerErrorThreshold(
 	}
 }
 
+func (f *HTTPConnectionFactory) applyProxyConfig(
+	clientConfig *httpclient.Config,
+	properties map[string]any,
+) {
+	if proxyURL, ok := properties["proxy_url"].(string); ok {
+		clientConfig.Proxy.URL = proxyURL
+	}
+
+	if noProxy, ok := properties["no_proxy"].(string); ok {
+		clientConfig.Proxy.NoProxy = noProxy
+	}
+
+	if username, ok := properties["proxy_username"].(string); ok {
+		clientConfig.Proxy.Username = username
+	}
+
+	if password, ok := properties["proxy_password"].(string); ok {
+		clientConfig.Proxy.Password = password
+	}
+}
+
 // Connection interface implementation
 
 func (c *HTTPConnection) GetBehaviors() []ConnectionBehavior {
@@ -336,6 +364,25 @@ func (c *HTTPConnection) GetRawConnection() any {
 	return c.client
 }
 
+// ReloadCertificates re-reads the client certificate and CA files if they changed.
+// Idle keep-alive connections are closed so new requests handshake with the new files.
+func (c *HTTPConnection) ReloadCertificates() (bool, error) {
+	if c.certificates == nil {
+		return false, nil
+	}
+
+	reloaded, err := c.certificates.reload()
+	if err != nil || !reloaded {
+		return false, err
+	}
+
+	if transport, ok := c.client.Transport.Transport.(*http.Transport); ok {
+		transport.CloseIdleConnections()
+	}
+
+	return true, nil
+}
+
 // Additional HTTP-specific methods
 
 // GetClient returns the underlying resilient HTTP client.
@@ -0,0 +1,214 @@
+package connfx_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const influxDBQueryResponse = `#datatype,string,long,dateTime:RFC3339,dateTime:RFC3339,dateTime:RFC3339,double,string,string,string
+#group,false,false,true,true,false,false,true,true,true
+#default,_result,,,,,,,,
+,result,table,_start,_stop,_time,_value,_field,_measurement,profile
+,,0,2025-01-01T00:00:00Z,2025-01-02T00:00:00Z,2025-01-01T10:00:00Z,42,views,profile_traffic,eser
+
+`
+
+// startFakeInfluxDBServer serves the health, write and query endpoints of the
+// InfluxDB 2.x HTTP API and forwards written line protocol to the returned channel.
+func startFakeInfluxDBServer(t *testing.T) (string, <-chan string) {
+	t.Helper()
+
+	writes := make(chan string, 10)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"influxdb","status":"pass","version":"v2.7.11"}`))
+	})
+	mux.HandleFunc("POST /api/v2/write", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		writes <- r.URL.Query().Get("org") + "/" + r.URL.Query().Get("bucket") + "\n" + string(body)
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /api/v2/query", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		_, _ = w.Write([]byte(influxDBQueryResponse))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server.URL, writes
+}
+
+func TestInfluxDBAdapter_BuildConfig(t *testing.T) {
+	t.Parallel()
+
+	factory := connfx.NewInfluxDBConnectionFactory("influxdb")
+	assert.Equal(t, "influxdb", factory.GetProtocol())
+
+	t.Run("dsn with org and bucket", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := factory.BuildInfluxDBConfig(&connfx.ConfigTarget{ //nolint:exhaustruct
+			Protocol: "influxdb",
+			DSN:      "influxdbs://s3cr3t@metrics.example.com/aya/profile_traffic",
+			Properties: map[string]any{
+				"batch_size":     500,
+				"flush_interval": 5 * time.Second,
+				"precision":      "s",
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "https://metrics.example.com:8086", config.ServerURL)
+		assert.Equal(t, "s3cr3t", config.Token)
+		assert.Equal(t, "aya", config.Org)
+		assert.Equal(t, "profile_traffic", config.Bucket)
+		assert.Equal(t, uint(500), config.BatchSize)
+		assert.Equal(t, 5*time.Second, config.FlushInterval)
+		assert.Equal(t, time.Second, config.Precision)
+	})
+
+	t.Run("missing bucket", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := factory.BuildInfluxDBConfig(&connfx.ConfigTarget{ //nolint:exhaustruct
+			Protocol: "influxdb",
+			Host:     "localhost",
+		})
+		require.ErrorIs(t, err, connfx.ErrInfluxDBNoBucket)
+	})
+
+	t.Run("invalid precision", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := factory.BuildInfluxDBConfig(&connfx.ConfigTarget{ //nolint:exhaustruct
+			Protocol:   "influxdb",
+			DSN:        "influxdb://token@localhost/aya/metrics",
+			Properties: map[string]any{"precision": "h"},
+		})
+		require.ErrorIs(t, err, connfx.ErrInfluxDBInvalidPrecision)
+	})
+}
+
+func TestInfluxDBAdapter_WriteAndQuery(t *testing.T) {
+	t.Parallel()
+
+	serverURL, writes := startFakeInfluxDBServer(t)
+
+	factory := connfx.NewInfluxDBConnectionFactory("influxdb")
+
+	conn, err := factory.CreateConnection(t.Context(), &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "influxdb",
+		URL:      serverURL,
+		Timeout:  2 * time.Second,
+		Properties: map[string]any{
+			"token":  "s3cr3t",
+			"org":    "aya",
+			"bucket": "profile_traffic",
+		},
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = conn.Close(t.Context()) })
+
+	assert.Equal(t, connfx.ConnectionStateReady, conn.GetState())
+	assert.Contains(t, conn.GetCapabilities(), connfx.ConnectionCapabilityTimeSeries)
+
+	series, ok := conn.GetRawConnection().(connfx.TimeSeriesRepository)
+	require.True(t, ok)
+
+	at := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	err = series.WritePoints(t.Context(),
+		&connfx.TimeSeriesPoint{
+			Time:        at,
+			Tags:        map[string]string{"profile": "eser"},
+			Fields:      map[string]any{"views": 42},
+			Measurement: "profile_traffic",
+		},
+		&connfx.TimeSeriesPoint{
+			Time:        at,
+			Tags:        map[string]string{"profile": "aya"},
+			Fields:      map[string]any{"views": 7},
+			Measurement: "profile_traffic",
+		},
+	)
+	require.NoError(t, err)
+
+	written := <-writes
+	lines := strings.Split(strings.TrimSpace(written), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "aya/profile_traffic", lines[0])
+	assert.Equal(t, "profile_traffic,profile=eser views=42i 1735725600000000000", lines[1])
+
+	records, err := series.QueryTimeSeries(t.Context(), `from(bucket: "profile_traffic") |> range(start: -1d)`)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+
+	assert.Equal(t, "profile_traffic", records[0].Measurement)
+	assert.Equal(t, "views", records[0].Field)
+	assert.InDelta(t, 42.0, records[0].Value, 0)
+	assert.Equal(t, "eser", records[0].Values["profile"])
+	assert.Equal(t, at, records[0].Time)
+}
+
+func TestInfluxDBAdapter_WritePointAsync(t *testing.T) {
+	t.Parallel()
+
+	serverURL, writes := startFakeInfluxDBServer(t)
+
+	factory := connfx.NewInfluxDBConnectionFactory("influxdb")
+
+	conn, err := factory.CreateConnection(t.Context(), &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "influxdb",
+		DSN:      strings.Replace(serverURL, "http://", "influxdb://token@", 1) + "/aya/profile_traffic",
+		Timeout:  2 * time.Second,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = conn.Close(t.Context()) })
+
+	influx, ok := conn.GetRawConnection().(*connfx.InfluxDBAdapter)
+	require.True(t, ok)
+
+	for range 3 {
+		influx.WritePointAsync(&connfx.TimeSeriesPoint{ //nolint:exhaustruct
+			Fields:      map[string]any{"views": 1},
+			Measurement: "profile_traffic",
+		})
+	}
+
+	influx.Flush()
+
+	written := <-writes
+	assert.Len(t, strings.Split(strings.TrimSpace(written), "\n"), 4)
+	require.NoError(t, influx.LastWriteError())
+}
+
+func TestInfluxDBAdapter_Unreachable(t *testing.T) {
+	t.Parallel()
+
+	factory := connfx.NewInfluxDBConnectionFactory("influxdb")
+
+	_, err := factory.CreateConnection(t.Context(), &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "influxdb",
+		Host:     "127.0.0.1",
+		Port:     1,
+		Timeout:  200 * time.Millisecond,
+		Properties: map[string]any{
+			"bucket": "profile_traffic",
+		},
+	})
+	require.ErrorIs(t, err, connfx.ErrInfluxDBConnectionFailed)
+}
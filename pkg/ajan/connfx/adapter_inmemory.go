@@ -0,0 +1,974 @@
+package connfx
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TTL values reported by InMemoryAdapter.GetTTL, mirroring the Redis TTL command.
+const (
+	InMemoryTTLNoExpiration = time.Duration(-1)
+	InMemoryTTLKeyMissing   = time.Duration(-2)
+)
+
+var (
+	ErrInMemoryUnsupportedOperation = errors.New("operation not supported by in-memory adapter")
+	ErrInMemoryKeyNotFound          = errors.New("key not found")
+	ErrInMemoryStreamNotFound       = errors.New("stream not found")
+	ErrInMemoryGroupNotFound        = errors.New("consumer group not found")
+	ErrInMemoryMessageNotFound      = errors.New("message not found")
+)
+
+// InMemoryConnection implements the connfx.Connection interface for a process-local store.
+// It needs no external service, which makes it a drop-in for Redis-backed
+// repositories in tests.
+type InMemoryConnection struct {
+	adapter  *InMemoryAdapter
+	protocol string
+	state    int32 // atomic field for connection state
+}
+
+// InMemoryAdapter implements CacheRepository and QueueStreamRepository in memory.
+// Keys expire lazily on access. Queues follow Redis Streams semantics: direct consumers
+// receive messages published after they subscribed, consumer groups track a delivery
+// cursor and a pending list that must be acknowledged or claimed.
+type InMemoryAdapter struct {
+	values  map[string]*inMemoryValue
+	streams map[string]*inMemoryStream
+	closed  chan struct{}
+	mu      sync.Mutex
+}
+
+type inMemoryValue struct {
+	expiresAt time.Time
+	data      []byte
+}
+
+type inMemoryStream struct {
+	groups map[string]*inMemoryGroup
+	// signal is closed and replaced on every publish to wake up blocked consumers
+	signal  chan struct{}
+	entries []*inMemoryEntry
+	lastSeq uint64
+	added   int64
+}
+
+type inMemoryEntry struct {
+	timestamp time.Time
+	headers   map[string]any
+	id        string
+	body      []byte
+	seq       uint64
+}
+
+type inMemoryGroup struct {
+	pending       map[string]*inMemoryPending
+	consumers     map[string]struct{}
+	lastDelivered uint64
+	entriesRead   int64
+}
+
+type inMemoryPending struct {
+	deliveredAt   time.Time
+	entry         *inMemoryEntry
+	consumer      string
+	deliveryCount int
+}
+
+// NewInMemoryConnection creates a new in-memory connection with an empty store.
+func NewInMemoryConnection(protocol string) *InMemoryConnection {
+	return &InMemoryConnection{
+		adapter:  NewInMemoryAdapter(),
+		protocol: protocol,
+		state:    int32(ConnectionStateReady),
+	}
+}
+
+// NewInMemoryAdapter creates an empty in-memory store.
+func NewInMemoryAdapter() *InMemoryAdapter {
+	return &InMemoryAdapter{
+		values:  make(map[string]*inMemoryValue),
+		streams: make(map[string]*inMemoryStream),
+		closed:  make(chan struct{}),
+		mu:      sync.Mutex{},
+	}
+}
+
+// Connection interface implementation.
+func (c *InMemoryConnection) GetBehaviors() []ConnectionBehavior {
+	return []ConnectionBehavior{
+		ConnectionBehaviorStateful,
+		ConnectionBehaviorStreaming,
+	}
+}
+
+func (c *InMemoryConnection) GetCapabilities() []ConnectionCapability {
+	return []ConnectionCapability{
+		ConnectionCapabilityKeyValue,
+		ConnectionCapabilityCache,
+		ConnectionCapabilityQueue,
+	}
+}
+
+func (c *InMemoryConnection) GetProtocol() string {
+	return c.protocol
+}
+
+func (c *InMemoryConnection) GetState() ConnectionState {
+	return ConnectionState(atomic.LoadInt32(&c.state))
+}
+
+func (c *InMemoryConnection) HealthCheck(ctx context.Context) *HealthStatus {
+	state := c.GetState()
+
+	status := &HealthStatus{
+		Timestamp: time.Now(),
+		State:     state,
+		Error:     nil,
+		Message:   "In-memory store is ready",
+		Latency:   0,
+	}
+
+	if state == ConnectionStateDisconnected {
+		status.Message = "In-memory store is closed"
+	}
+
+	return status
+}
+
+// Close stops running consumers. Stored data stays readable.
+func (c *InMemoryConnection) Close(ctx context.Context) error {
+	if atomic.SwapInt32(&c.state, int32(ConnectionStateDisconnected)) != int32(ConnectionStateDisconnected) {
+		close(c.adapter.closed)
+	}
+
+	return nil
+}
+
+func (c *InMemoryConnection) GetRawConnection() any {
+	return c.adapter
+}
+
+// GetAdapter returns the underlying in-memory store.
+func (c *InMemoryConnection) GetAdapter() *InMemoryAdapter {
+	return c.adapter
+}
+
+// Repository interface implementation.
+
+// Get returns nil without an error when the key does not exist, as the Redis adapter does.
+func (a *InMemoryAdapter) Get(ctx context.Context, key string) ([]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	value := a.lookup(key)
+	if value == nil {
+		return nil, nil
+	}
+
+	return slices.Clone(value.data), nil
+}
+
+func (a *InMemoryAdapter) Set(ctx context.Context, key string, value []byte) error {
+	return a.SetWithExpiration(ctx, key, value, 0)
+}
+
+func (a *InMemoryAdapter) Remove(ctx context.Context, keys ...string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, key := range keys {
+		delete(a.values, key)
+		delete(a.streams, key)
+	}
+
+	return nil
+}
+
+func (a *InMemoryAdapter) Update(ctx context.Context, key string, value []byte) error {
+	// As with Redis, update is the same as set
+	return a.Set(ctx, key, value)
+}
+
+func (a *InMemoryAdapter) Exists(ctx context.Context, key string) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.lookup(key) != nil, nil
+}
+
+func (a *InMemoryAdapter) FlushAll(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.values = make(map[string]*inMemoryValue)
+	a.streams = make(map[string]*inMemoryStream)
+
+	return nil
+}
+
+// EnsureTableExists is a no-op; tables are key prefixes created on first use.
+func (a *InMemoryAdapter) EnsureTableExists(
+	ctx context.Context,
+	tableName string,
+	primaryKeyAttributeName string,
+) error {
+	return nil
+}
+
+// Close is a no-op; consumers are stopped by InMemoryConnection.Close.
+func (a *InMemoryAdapter) Close(ctx context.Context) error {
+	return nil
+}
+
+func (a *InMemoryAdapter) Eval(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+	return nil, fmt.Errorf("%w (operation=eval)", ErrInMemoryUnsupportedOperation)
+}
+
+// ListItems unmarshals every item stored under the "<table>:" prefix into items,
+// which must be a pointer to a slice. Items are ordered by key.
+func (a *InMemoryAdapter) ListItems(ctx context.Context, tableName string, items any) error {
+	sliceValue := reflect.ValueOf(items)
+	if sliceValue.Kind() != reflect.Ptr || sliceValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("%w (items=%v)", ErrExpectedPointerToSlice, items)
+	}
+
+	a.mu.Lock()
+
+	prefix := tableName + ":"
+	keys := make([]string, 0)
+
+	for key := range a.values {
+		if strings.HasPrefix(key, prefix) && a.lookup(key) != nil {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		values[i] = a.values[key].data
+	}
+
+	a.mu.Unlock()
+
+	sliceElem := sliceValue.Elem()
+	elemType := sliceElem.Type().Elem()
+	newSlice := reflect.MakeSlice(sliceElem.Type(), 0, len(values))
+
+	for i, value := range values {
+		newElem := reflect.New(elemType)
+
+		if err := json.Unmarshal(value, newElem.Interface()); err != nil {
+			return fmt.Errorf("%w (key=%q): %w", ErrCorruptedJSONData, keys[i], err)
+		}
+
+		newSlice = reflect.Append(newSlice, newElem.Elem())
+	}
+
+	sliceElem.Set(newSlice)
+
+	return nil
+}
+
+func (a *InMemoryAdapter) GetItem(
+	ctx context.Context,
+	tableName string,
+	pkName string,
+	key string,
+	item any,
+) (bool, error) {
+	itemKey := tableName + ":" + key
+
+	data, err := a.Get(ctx, itemKey)
+	if err != nil || data == nil {
+		return false, err
+	}
+
+	if err := json.Unmarshal(data, item); err != nil {
+		return false, fmt.Errorf("%w (key=%q): %w", ErrCorruptedJSONData, itemKey, err)
+	}
+
+	return true, nil
+}
+
+func (a *InMemoryAdapter) UpsertItem(
+	ctx context.Context,
+	tableName string,
+	pkName string,
+	key string,
+	item any,
+) error {
+	itemKey := tableName + ":" + key
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("%w (key=%q): %w", ErrCorruptedJSONData, itemKey, err)
+	}
+
+	return a.Set(ctx, itemKey, data)
+}
+
+// DeleteItem removes an item stored with UpsertItem.
+func (a *InMemoryAdapter) DeleteItem(ctx context.Context, tableName string, pkName string, key string) error {
+	return a.Remove(ctx, tableName+":"+key)
+}
+
+// CacheRepository interface implementation.
+
+// SetWithExpiration stores a value; a zero expiration keeps it until removed.
+func (a *InMemoryAdapter) SetWithExpiration(
+	ctx context.Context,
+	key string,
+	value []byte,
+	expiration time.Duration,
+) error {
+	stored := &inMemoryValue{
+		data:      slices.Clone(value),
+		expiresAt: time.Time{},
+	}
+
+	if expiration > 0 {
+		stored.expiresAt = time.Now().Add(expiration)
+	}
+
+	a.mu.Lock()
+	a.values[key] = stored
+	a.mu.Unlock()
+
+	return nil
+}
+
+// GetTTL returns the remaining time-to-live, InMemoryTTLNoExpiration for keys without
+// one and InMemoryTTLKeyMissing for keys that do not exist.
+func (a *InMemoryAdapter) GetTTL(ctx context.Context, key string) (time.Duration, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	value := a.lookup(key)
+
+	switch {
+	case value == nil:
+		return InMemoryTTLKeyMissing, nil
+	case value.expiresAt.IsZero():
+		return InMemoryTTLNoExpiration, nil
+	default:
+		return time.Until(value.expiresAt), nil
+	}
+}
+
+// Expire sets the time-to-live of an existing key. A non-positive expiration removes it.
+func (a *InMemoryAdapter) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	value := a.lookup(key)
+	if value == nil {
+		return fmt.Errorf("%w (key=%q)", ErrInMemoryKeyNotFound, key)
+	}
+
+	if expiration <= 0 {
+		delete(a.values, key)
+
+		return nil
+	}
+
+	value.expiresAt = time.Now().Add(expiration)
+
+	return nil
+}
+
+// QueueRepository interface implementation.
+
+func (a *InMemoryAdapter) QueueDeclare(ctx context.Context, name string) (string, error) {
+	a.mu.Lock()
+	a.stream(name)
+	a.mu.Unlock()
+
+	return name, nil
+}
+
+// QueueDeclareWithConfig declares a queue and, as the Redis adapter does, trims it to
+// config.MaxLength when set.
+func (a *InMemoryAdapter) QueueDeclareWithConfig(
+	ctx context.Context,
+	name string,
+	config QueueConfig,
+) (string, error) {
+	if _, err := a.QueueDeclare(ctx, name); err != nil {
+		return "", err
+	}
+
+	if config.MaxLength > 0 {
+		if err := a.TrimStream(ctx, name, config.MaxLength); err != nil {
+			return "", err
+		}
+	}
+
+	return name, nil
+}
+
+func (a *InMemoryAdapter) CreateQueueIfNotExists(
+	ctx context.Context,
+	queueName string,
+	consumerGroup string,
+	attributes map[string]string,
+) (*string, error) {
+	streamName, err := a.QueueDeclare(ctx, queueName)
+	if err != nil {
+		return nil, err
+	}
+
+	if consumerGroup != "" {
+		if err := a.CreateConsumerGroup(ctx, streamName, consumerGroup, "0"); err != nil {
+			return nil, err
+		}
+	}
+
+	return &streamName, nil
+}
+
+func (a *InMemoryAdapter) Publish(ctx context.Context, queueName string, body []byte) error {
+	return a.PublishWithHeaders(ctx, queueName, body, nil)
+}
+
+func (a *InMemoryAdapter) PublishWithHeaders(
+	ctx context.Context,
+	queueName string,
+	body []byte,
+	headers map[string]any,
+) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	stream := a.stream(queueName)
+	stream.lastSeq++
+	stream.added++
+
+	now := time.Now()
+
+	stream.entries = append(stream.entries, &inMemoryEntry{
+		timestamp: now,
+		headers:   maps.Clone(headers),
+		id:        strconv.FormatInt(now.UnixMilli(), 10) + "-" + strconv.FormatUint(stream.lastSeq, 10),
+		body:      slices.Clone(body),
+		seq:       stream.lastSeq,
+	})
+
+	close(stream.signal)
+	stream.signal = make(chan struct{})
+
+	return nil
+}
+
+// Consume delivers messages published after the call, without acknowledgment.
+func (a *InMemoryAdapter) Consume(
+	ctx context.Context,
+	queueName string,
+	config ConsumerConfig,
+) (<-chan Message, <-chan error) {
+	messages := make(chan Message)
+	errCh := make(chan error)
+
+	a.mu.Lock()
+	cursor := a.stream(queueName).lastSeq
+	a.mu.Unlock()
+
+	go func() {
+		defer close(messages)
+		defer close(errCh)
+
+		a.consumeLoop(ctx, messages, func() ([]Message, <-chan struct{}) {
+			stream := a.stream(queueName)
+			entries := entriesAfter(stream, cursor, config.PrefetchCount)
+
+			batch := make([]Message, len(entries))
+			for i, entry := range entries {
+				cursor = entry.seq
+				batch[i] = a.createMessage(ctx, queueName, "", entry, 1)
+			}
+
+			return batch, stream.signal
+		})
+	}()
+
+	return messages, errCh
+}
+
+// ConsumeWithGroup delivers each message to a single consumer of the group. Delivered
+// messages stay pending until acknowledged. The group is created on first use and
+// starts at the beginning of the stream.
+func (a *InMemoryAdapter) ConsumeWithGroup(
+	ctx context.Context,
+	queueName string,
+	consumerGroup string,
+	consumerName string,
+	config ConsumerConfig,
+) (<-chan Message, <-chan error) {
+	messages := make(chan Message)
+	errCh := make(chan error)
+
+	go func() {
+		defer close(messages)
+		defer close(errCh)
+
+		a.consumeLoop(ctx, messages, func() ([]Message, <-chan struct{}) {
+			stream := a.stream(queueName)
+			group := stream.group(consumerGroup, 0)
+			group.consumers[consumerName] = struct{}{}
+
+			entries := entriesAfter(stream, group.lastDelivered, config.PrefetchCount)
+			now := time.Now()
+
+			batch := make([]Message, len(entries))
+			for i, entry := range entries {
+				group.lastDelivered = entry.seq
+				group.entriesRead++
+				group.pending[entry.id] = &inMemoryPending{
+					deliveredAt:   now,
+					entry:         entry,
+					consumer:      consumerName,
+					deliveryCount: 1,
+				}
+
+				batch[i] = a.createMessage(ctx, queueName, consumerGroup, entry, 1)
+			}
+
+			return batch, stream.signal
+		})
+	}()
+
+	return messages, errCh
+}
+
+// ClaimPendingMessages moves pending messages that have been idle for at least
+// minIdleTime to the given consumer and returns them.
+func (a *InMemoryAdapter) ClaimPendingMessages(
+	ctx context.Context,
+	queueName string,
+	consumerGroup string,
+	consumerName string,
+	minIdleTime time.Duration,
+	count int,
+) ([]Message, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	group, err := a.existingGroup(queueName, consumerGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := slices.SortedFunc(maps.Values(group.pending), func(x, y *inMemoryPending) int {
+		return cmp.Compare(x.entry.seq, y.entry.seq)
+	})
+
+	now := time.Now()
+	claimed := make([]Message, 0)
+
+	for _, entry := range pending {
+		if count > 0 && len(claimed) >= count {
+			break
+		}
+
+		if now.Sub(entry.deliveredAt) < minIdleTime {
+			continue
+		}
+
+		entry.consumer = consumerName
+		entry.deliveredAt = now
+		entry.deliveryCount++
+		group.consumers[consumerName] = struct{}{}
+
+		claimed = append(
+			claimed,
+			a.createMessage(ctx, queueName, consumerGroup, entry.entry, entry.deliveryCount),
+		)
+	}
+
+	return claimed, nil
+}
+
+// AckMessage removes a message from the group's pending list.
+func (a *InMemoryAdapter) AckMessage(
+	ctx context.Context,
+	queueName, consumerGroup, receiptHandle string,
+) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	group, err := a.existingGroup(queueName, consumerGroup)
+	if err != nil {
+		return err
+	}
+
+	delete(group.pending, receiptHandle)
+
+	return nil
+}
+
+// DeleteMessage removes a message from the stream and from every pending list.
+func (a *InMemoryAdapter) DeleteMessage(ctx context.Context, queueName, receiptHandle string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	stream, exists := a.streams[queueName]
+	if !exists {
+		return fmt.Errorf("%w (queue=%q)", ErrInMemoryStreamNotFound, queueName)
+	}
+
+	index := slices.IndexFunc(stream.entries, func(entry *inMemoryEntry) bool {
+		return entry.id == receiptHandle
+	})
+	if index < 0 {
+		return fmt.Errorf("%w (queue=%q, handle=%q)", ErrInMemoryMessageNotFound, queueName, receiptHandle)
+	}
+
+	stream.entries = slices.Delete(stream.entries, index, index+1)
+
+	for _, group := range stream.groups {
+		delete(group.pending, receiptHandle)
+	}
+
+	return nil
+}
+
+// QueueStreamRepository interface implementation.
+
+// CreateConsumerGroup creates a group reading from startID: "0" for the beginning of
+// the stream, "$" for new messages only, or an existing message ID. Creating an
+// existing group is not an error.
+func (a *InMemoryAdapter) CreateConsumerGroup(
+	ctx context.Context,
+	streamName, consumerGroup, startID string,
+) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	stream := a.stream(streamName)
+
+	var start uint64
+
+	switch startID {
+	case "0", "":
+	case "$":
+		start = stream.lastSeq
+	default:
+		index := slices.IndexFunc(stream.entries, func(entry *inMemoryEntry) bool {
+			return entry.id == startID
+		})
+		if index < 0 {
+			return fmt.Errorf("%w (stream=%q, id=%q)", ErrInMemoryMessageNotFound, streamName, startID)
+		}
+
+		start = stream.entries[index].seq
+	}
+
+	stream.group(consumerGroup, start)
+
+	return nil
+}
+
+func (a *InMemoryAdapter) StreamInfo(ctx context.Context, streamName string) (StreamInfo, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	stream, exists := a.streams[streamName]
+	if !exists {
+		return StreamInfo{}, fmt.Errorf("%w (stream=%q)", ErrInMemoryStreamNotFound, streamName)
+	}
+
+	info := StreamInfo{
+		FirstEntry:      nil,
+		LastEntry:       nil,
+		Metadata:        make(map[string]string),
+		LastGeneratedID: "0-0",
+		MaxDeletedID:    "",
+		RecordedFirstID: "",
+		Length:          int64(len(stream.entries)),
+		RadixTreeKeys:   0,
+		RadixTreeNodes:  0,
+		Groups:          int64(len(stream.groups)),
+		EntriesAdded:    stream.added,
+	}
+
+	if len(stream.entries) > 0 {
+		first := stream.entries[0]
+		last := stream.entries[len(stream.entries)-1]
+
+		info.FirstEntry = &StreamEntry{ID: first.id, Fields: first.fields()}
+		info.LastEntry = &StreamEntry{ID: last.id, Fields: last.fields()}
+		info.RecordedFirstID = first.id
+		info.LastGeneratedID = last.id
+	}
+
+	return info, nil
+}
+
+func (a *InMemoryAdapter) ConsumerGroupInfo(
+	ctx context.Context,
+	streamName string,
+) ([]ConsumerGroupInfo, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	stream, exists := a.streams[streamName]
+	if !exists {
+		return nil, fmt.Errorf("%w (stream=%q)", ErrInMemoryStreamNotFound, streamName)
+	}
+
+	infos := make([]ConsumerGroupInfo, 0, len(stream.groups))
+
+	for _, name := range slices.Sorted(maps.Keys(stream.groups)) {
+		group := stream.groups[name]
+
+		lastDeliveredID := "0-0"
+		if index := slices.IndexFunc(stream.entries, func(entry *inMemoryEntry) bool {
+			return entry.seq == group.lastDelivered
+		}); index >= 0 {
+			lastDeliveredID = stream.entries[index].id
+		}
+
+		infos = append(infos, ConsumerGroupInfo{
+			Name:            name,
+			LastDeliveredID: lastDeliveredID,
+			Consumers:       int64(len(group.consumers)),
+			Pending:         int64(len(group.pending)),
+			EntriesRead:     group.entriesRead,
+			Lag:             int64(len(entriesAfter(stream, group.lastDelivered, 0))),
+		})
+	}
+
+	return infos, nil
+}
+
+// TrimStream keeps the newest maxLen messages of a stream.
+func (a *InMemoryAdapter) TrimStream(ctx context.Context, streamName string, maxLen int64) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	stream, exists := a.streams[streamName]
+	if !exists {
+		return nil
+	}
+
+	if excess := int64(len(stream.entries)) - maxLen; excess > 0 {
+		stream.entries = slices.Clone(stream.entries[excess:])
+	}
+
+	return nil
+}
+
+// lookup returns a live value, dropping it when it has expired. The caller holds a.mu.
+func (a *InMemoryAdapter) lookup(key string) *inMemoryValue {
+	value, exists := a.values[key]
+	if !exists {
+		return nil
+	}
+
+	if !value.expiresAt.IsZero() && !time.Now().Before(value.expiresAt) {
+		delete(a.values, key)
+
+		return nil
+	}
+
+	return value
+}
+
+// stream returns the named stream, creating it on first use. The caller holds a.mu.
+func (a *InMemoryAdapter) stream(name string) *inMemoryStream {
+	stream, exists := a.streams[name]
+	if !exists {
+		stream = &inMemoryStream{
+			groups:  make(map[string]*inMemoryGroup),
+			signal:  make(chan struct{}),
+			entries: nil,
+			lastSeq: 0,
+			added:   0,
+		}
+
+		a.streams[name] = stream
+	}
+
+	return stream
+}
+
+// existingGroup looks up a consumer group without creating it. The caller holds a.mu.
+func (a *InMemoryAdapter) existingGroup(streamName, groupName string) (*inMemoryGroup, error) {
+	stream, exists := a.streams[streamName]
+	if !exists {
+		return nil, fmt.Errorf("%w (stream=%q)", ErrInMemoryStreamNotFound, streamName)
+	}
+
+	group, exists := stream.groups[groupName]
+	if !exists {
+		return nil, fmt.Errorf("%w (stream=%q, group=%q)", ErrInMemoryGroupNotFound, streamName, groupName)
+	}
+
+	return group, nil
+}
+
+// consumeLoop delivers the batches returned by next, waiting for new publishes in
+// between, until ctx is done or the connection is closed. next runs under a.mu.
+func (a *InMemoryAdapter) consumeLoop(
+	ctx context.Context,
+	messages chan<- Message,
+	next func() ([]Message, <-chan struct{}),
+) {
+	for {
+		a.mu.Lock()
+		batch, signal := next()
+		a.mu.Unlock()
+
+		for _, msg := range batch {
+			select {
+			case messages <- msg:
+			case <-ctx.Done():
+				return
+			case <-a.closed:
+				return
+			}
+		}
+
+		if len(batch) > 0 {
+			continue
+		}
+
+		select {
+		case <-signal:
+		case <-ctx.Done():
+			return
+		case <-a.closed:
+			return
+		}
+	}
+}
+
+func (a *InMemoryAdapter) createMessage(
+	ctx context.Context,
+	queueName string,
+	consumerGroup string,
+	entry *inMemoryEntry,
+	deliveryCount int,
+) Message {
+	msg := Message{ //nolint:exhaustruct
+		Timestamp:     entry.timestamp,
+		Headers:       maps.Clone(entry.headers),
+		ReceiptHandle: entry.id,
+		MessageID:     entry.id,
+		ConsumerGroup: consumerGroup,
+		StreamName:    queueName,
+		Body:          slices.Clone(entry.body),
+		DeliveryCount: deliveryCount,
+	}
+
+	if msg.Headers == nil {
+		msg.Headers = make(map[string]any)
+	}
+
+	if consumerGroup == "" {
+		msg.SetAckFunc(func() error { return nil })
+		msg.SetNackFunc(func(requeue bool) error { return nil })
+
+		return msg
+	}
+
+	msg.SetAckFunc(func() error {
+		return a.AckMessage(ctx, queueName, consumerGroup, entry.id)
+	})
+
+	msg.SetNackFunc(func(requeue bool) error {
+		// As with Redis Streams, a requeued message stays pending until it is claimed
+		if !requeue {
+			return a.AckMessage(ctx, queueName, consumerGroup, entry.id)
+		}
+
+		return nil
+	})
+
+	return msg
+}
+
+// group returns the named consumer group, creating it at start on first use.
+func (s *inMemoryStream) group(name string, start uint64) *inMemoryGroup {
+	group, exists := s.groups[name]
+	if !exists {
+		group = &inMemoryGroup{
+			pending:       make(map[string]*inMemoryPending),
+			consumers:     make(map[string]struct{}),
+			lastDelivered: start,
+			entriesRead:   0,
+		}
+
+		s.groups[name] = group
+	}
+
+	return group
+}
+
+// fields flattens an entry the way it would be stored in a Redis stream.
+func (e *inMemoryEntry) fields() map[string]string {
+	fields := convertValues(e.headers)
+	fields["data"] = string(e.body)
+
+	return fields
+}
+
+// entriesAfter returns up to limit entries whose sequence is greater than cursor.
+// A non-positive limit returns all of them.
+func entriesAfter(stream *inMemoryStream, cursor uint64, limit int) []*inMemoryEntry {
+	index, _ := slices.BinarySearchFunc(stream.entries, cursor+1, func(entry *inMemoryEntry, seq uint64) int {
+		switch {
+		case entry.seq < seq:
+			return -1
+		case entry.seq > seq:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	entries := stream.entries[index:]
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	return entries
+}
+
+// InMemoryConnectionFactory creates in-memory connections.
+type InMemoryConnectionFactory struct {
+	protocol string
+}
+
+// NewInMemoryConnectionFactory creates a new in-memory connection factory for a specific protocol.
+func NewInMemoryConnectionFactory(protocol string) *InMemoryConnectionFactory {
+	return &InMemoryConnectionFactory{
+		protocol: protocol,
+	}
+}
+
+// CreateConnection returns a connection with its own empty store; the target's
+// address fields are ignored.
+func (f *InMemoryConnectionFactory) CreateConnection( //nolint:ireturn
+	ctx context.Context,
+	config *ConfigTarget,
+) (Connection, error) {
+	return NewInMemoryConnection(f.protocol), nil
+}
+
+func (f *InMemoryConnectionFactory) GetProtocol() string {
+	return f.protocol
+}
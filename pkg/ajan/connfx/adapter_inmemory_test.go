@@ -0,0 +1,221 @@
+package connfx_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type inMemoryProfile struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func newInMemoryRegistry(t *testing.T) *connfx.Registry {
+	t.Helper()
+
+	registry := connfx.NewRegistry(
+		connfx.WithLogger(newMockLogger()),
+		connfx.WithDefaultFactories(),
+	)
+
+	_, err := registry.AddConnection(t.Context(), "default", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "inmemory",
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = registry.Close(context.Background())
+	})
+
+	return registry
+}
+
+func TestInMemoryAdapter_Repository(t *testing.T) {
+	t.Parallel()
+
+	registry := newInMemoryRegistry(t)
+	ctx := t.Context()
+
+	repo, err := registry.GetRepository(connfx.DefaultConnection)
+	require.NoError(t, err)
+
+	value, err := repo.Get(ctx, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	require.NoError(t, repo.Set(ctx, "greeting", []byte("hello")))
+
+	value, err = repo.Get(ctx, "greeting")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello"), value)
+
+	require.NoError(t, repo.UpsertItem(ctx, "profiles", "id", "b", inMemoryProfile{ID: "b", Name: "Bob"}))
+	require.NoError(t, repo.UpsertItem(ctx, "profiles", "id", "a", inMemoryProfile{ID: "a", Name: "Alice"}))
+
+	var profile inMemoryProfile
+
+	found, err := repo.GetItem(ctx, "profiles", "id", "a", &profile)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "Alice", profile.Name)
+
+	var profiles []inMemoryProfile
+
+	require.NoError(t, repo.ListItems(ctx, "profiles", &profiles))
+	assert.Equal(t, []inMemoryProfile{{ID: "a", Name: "Alice"}, {ID: "b", Name: "Bob"}}, profiles)
+
+	require.NoError(t, repo.FlushAll(ctx))
+
+	exists, err := repo.Exists(ctx, "greeting")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestInMemoryAdapter_TTL(t *testing.T) {
+	t.Parallel()
+
+	registry := newInMemoryRegistry(t)
+	ctx := t.Context()
+
+	conn := registry.GetNamed(connfx.DefaultConnection)
+	cache := conn.GetRawConnection().(connfx.CacheRepository) //nolint:forcetypeassert
+
+	ttl, err := cache.GetTTL(ctx, "session")
+	require.NoError(t, err)
+	assert.Equal(t, connfx.InMemoryTTLKeyMissing, ttl)
+
+	require.NoError(t, cache.Set(ctx, "session", []byte("token")))
+
+	ttl, err = cache.GetTTL(ctx, "session")
+	require.NoError(t, err)
+	assert.Equal(t, connfx.InMemoryTTLNoExpiration, ttl)
+
+	require.NoError(t, cache.Expire(ctx, "session", 20*time.Millisecond))
+
+	ttl, err = cache.GetTTL(ctx, "session")
+	require.NoError(t, err)
+	assert.Positive(t, ttl)
+
+	require.Eventually(t, func() bool {
+		value, _ := cache.Get(ctx, "session")
+
+		return value == nil
+	}, time.Second, 5*time.Millisecond)
+
+	require.ErrorIs(t, cache.Expire(ctx, "session", time.Minute), connfx.ErrInMemoryKeyNotFound)
+}
+
+func TestInMemoryAdapter_Consume(t *testing.T) {
+	t.Parallel()
+
+	registry := newInMemoryRegistry(t)
+	ctx := t.Context()
+
+	queue, err := registry.GetQueueRepository(connfx.DefaultConnection)
+	require.NoError(t, err)
+
+	// Messages published before subscribing are not delivered to direct consumers.
+	require.NoError(t, queue.Publish(ctx, "events", []byte("before")))
+
+	messages, _ := queue.Consume(ctx, "events", connfx.DefaultConsumerConfig())
+
+	require.NoError(t, queue.PublishWithHeaders(ctx, "events", []byte("after"), map[string]any{
+		"type": "created",
+	}))
+
+	select {
+	case msg := <-messages:
+		assert.Equal(t, []byte("after"), msg.Body)
+		assert.Equal(t, "created", msg.Headers["type"])
+		require.NoError(t, msg.Ack())
+	case <-time.After(time.Second):
+		t.Fatal("message was not delivered")
+	}
+}
+
+func TestInMemoryAdapter_ConsumeWithGroup(t *testing.T) {
+	t.Parallel()
+
+	registry := newInMemoryRegistry(t)
+	ctx := t.Context()
+
+	queue, err := registry.GetQueueRepository(connfx.DefaultConnection)
+	require.NoError(t, err)
+
+	streams := registry.GetNamed(connfx.DefaultConnection).
+		GetRawConnection().(connfx.QueueStreamRepository) //nolint:forcetypeassert
+
+	_, err = queue.CreateQueueIfNotExists(ctx, "jobs", "workers", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, queue.Publish(ctx, "jobs", []byte("job-1")))
+	require.NoError(t, queue.Publish(ctx, "jobs", []byte("job-2")))
+
+	consumeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	messages, _ := queue.ConsumeWithGroup(consumeCtx, "jobs", "workers", "worker-1", connfx.DefaultConsumerConfig())
+
+	first := <-messages
+	second := <-messages
+
+	assert.Equal(t, []byte("job-1"), first.Body)
+	assert.Equal(t, "workers", first.ConsumerGroup)
+	assert.Equal(t, []byte("job-2"), second.Body)
+
+	require.NoError(t, first.Ack())
+	// A requeued message stays pending until another consumer claims it.
+	require.NoError(t, second.Nack(true))
+
+	groups, err := streams.ConsumerGroupInfo(ctx, "jobs")
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, int64(1), groups[0].Pending)
+	assert.Equal(t, int64(0), groups[0].Lag)
+
+	claimed, err := queue.ClaimPendingMessages(ctx, "jobs", "workers", "worker-2", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, second.MessageID, claimed[0].MessageID)
+	assert.Equal(t, 2, claimed[0].DeliveryCount)
+
+	require.NoError(t, claimed[0].Ack())
+
+	groups, err = streams.ConsumerGroupInfo(ctx, "jobs")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), groups[0].Pending)
+	assert.Equal(t, int64(2), groups[0].Consumers)
+
+	info, err := streams.StreamInfo(ctx, "jobs")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), info.Length)
+
+	require.NoError(t, streams.TrimStream(ctx, "jobs", 1))
+
+	info, err = streams.StreamInfo(ctx, "jobs")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), info.Length)
+	assert.Equal(t, "job-2", info.FirstEntry.Fields["data"])
+}
+
+func TestInMemoryConnection_CloseStopsConsumers(t *testing.T) {
+	t.Parallel()
+
+	conn := connfx.NewInMemoryConnection("inmemory")
+	messages, _ := conn.GetAdapter().Consume(t.Context(), "events", connfx.DefaultConsumerConfig())
+
+	require.NoError(t, conn.Close(t.Context()))
+	assert.Equal(t, connfx.ConnectionStateDisconnected, conn.GetState())
+
+	select {
+	case _, open := <-messages:
+		assert.False(t, open)
+	case <-time.After(time.Second):
+		t.Fatal("consumer was not stopped")
+	}
+}
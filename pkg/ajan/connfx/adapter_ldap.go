@@ -0,0 +1,565 @@
+package connfx
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+const (
+	DefaultLDAPTimeout        = 10 * time.Second
+	DefaultLDAPUserFilter     = "(&(objectClass=person)(uid=%s))"
+	DefaultLDAPGroupFilter    = "(&(objectClass=groupOfNames)(member=%s))"
+	DefaultLDAPGroupAttribute = "cn"
+
+	defaultLDAPPort  = 389
+	defaultLDAPSPort = 636
+)
+
+var (
+	ErrLDAPConnectionFailed     = errors.New("failed to connect to LDAP server")
+	ErrFailedToCreateLDAPClient = errors.New("failed to create LDAP client")
+	ErrFailedToCloseLDAPClient  = errors.New("failed to close LDAP client")
+	ErrLDAPOperation            = errors.New("LDAP operation failed")
+	ErrLDAPInvalidCredentials   = errors.New("invalid LDAP credentials")
+	ErrLDAPUserNotFound         = errors.New("LDAP user not found")
+	ErrLDAPAmbiguousUser        = errors.New("LDAP user filter matched multiple entries")
+	ErrLDAPNoBaseDN             = errors.New("LDAP base DN is not configured")
+)
+
+// LDAPConfig holds LDAP-specific configuration options.
+type LDAPConfig struct {
+	URL            string
+	BindDN         string // service account used for searches
+	BindPassword   string
+	BaseDN         string
+	UserFilter     string // %s is replaced with the escaped username
+	GroupFilter    string // %s is replaced with the escaped user DN
+	GroupAttribute string // attribute returned as the group name
+	UserAttributes []string
+	Timeout        time.Duration
+
+	StartTLS              bool
+	TLSInsecureSkipVerify bool
+}
+
+// NewDefaultLDAPConfig creates an LDAP configuration with sensible defaults.
+func NewDefaultLDAPConfig() *LDAPConfig {
+	return &LDAPConfig{
+		URL:                   "ldap://localhost:389",
+		BindDN:                "",
+		BindPassword:          "",
+		BaseDN:                "",
+		UserFilter:            DefaultLDAPUserFilter,
+		GroupFilter:           DefaultLDAPGroupFilter,
+		GroupAttribute:        DefaultLDAPGroupAttribute,
+		UserAttributes:        []string{"cn", "mail", "uid", "displayName"},
+		Timeout:               DefaultLDAPTimeout,
+		StartTLS:              false,
+		TLSInsecureSkipVerify: false,
+	}
+}
+
+// LDAPEntry represents a single directory entry.
+type LDAPEntry struct {
+	Attributes map[string][]string
+	DN         string
+}
+
+// GetAttribute returns the first value of an attribute, or an empty string.
+func (e *LDAPEntry) GetAttribute(name string) string {
+	if values := e.Attributes[name]; len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// LDAPAdapter provides directory operations over a service-account connection.
+// User credentials are verified on separate short-lived connections so the
+// shared connection always stays bound as the service account.
+type LDAPAdapter struct {
+	conn   *ldap.Conn
+	config *LDAPConfig
+	mu     sync.Mutex
+}
+
+// LDAPConnection implements the connfx.Connection interface for LDAP directories.
+type LDAPConnection struct {
+	adapter  *LDAPAdapter
+	protocol string
+	state    int32 // atomic field for connection state
+}
+
+// NewLDAPConnection creates a new LDAP connection.
+func NewLDAPConnection(protocol string, config *LDAPConfig) *LDAPConnection {
+	if config == nil {
+		config = NewDefaultLDAPConfig()
+	}
+
+	return &LDAPConnection{
+		adapter: &LDAPAdapter{
+			conn:   nil, // Will be initialized on connect
+			config: config,
+			mu:     sync.Mutex{},
+		},
+		protocol: protocol,
+		state:    int32(ConnectionStateNotInitialized),
+	}
+}
+
+// Connection interface implementation.
+func (lc *LDAPConnection) GetBehaviors() []ConnectionBehavior {
+	return []ConnectionBehavior{
+		ConnectionBehaviorStateful,
+	}
+}
+
+func (lc *LDAPConnection) GetCapabilities() []ConnectionCapability {
+	return []ConnectionCapability{
+		ConnectionCapabilityDirectory,
+	}
+}
+
+func (lc *LDAPConnection) GetProtocol() string {
+	return lc.protocol
+}
+
+func (lc *LDAPConnection) GetState() ConnectionState {
+	return ConnectionState(atomic.LoadInt32(&lc.state))
+}
+
+func (lc *LDAPConnection) HealthCheck(ctx context.Context) *HealthStatus {
+	start := time.Now()
+
+	status := &HealthStatus{
+		Timestamp: start,
+		State:     lc.GetState(),
+		Error:     nil,
+		Message:   "",
+		Latency:   0,
+	}
+
+	// Reading the root DSE works on every LDAPv3 server, including Active Directory.
+	_, err := lc.adapter.Search(
+		ctx,
+		"",
+		ldap.ScopeBaseObject,
+		"(objectClass=*)",
+		[]string{"supportedLDAPVersion"},
+	)
+	status.Latency = time.Since(start)
+
+	if err != nil {
+		atomic.StoreInt32(&lc.state, int32(ConnectionStateError))
+		status.State = ConnectionStateError
+		status.Error = err
+		status.Message = fmt.Sprintf("LDAP health check failed: %v", err)
+
+		return status
+	}
+
+	atomic.StoreInt32(&lc.state, int32(ConnectionStateReady))
+	status.State = ConnectionStateReady
+	status.Message = "LDAP connection is ready (server=" + lc.adapter.config.URL + ")"
+
+	return status
+}
+
+func (lc *LDAPConnection) Close(ctx context.Context) error {
+	atomic.StoreInt32(&lc.state, int32(ConnectionStateDisconnected))
+
+	return lc.adapter.Close(ctx)
+}
+
+func (lc *LDAPConnection) GetRawConnection() any {
+	return lc.adapter
+}
+
+// Authenticate looks up the user with the configured user filter and verifies the
+// password by binding as the user. It returns the user's directory entry.
+func (la *LDAPAdapter) Authenticate(
+	ctx context.Context,
+	username string,
+	password string,
+) (*LDAPEntry, error) {
+	// An empty password would be an unauthenticated bind, which most servers accept.
+	if password == "" {
+		return nil, ErrLDAPInvalidCredentials
+	}
+
+	user, err := la.FindUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := la.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close() //nolint:errcheck
+
+	if err := conn.Bind(user.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, fmt.Errorf("%w (user=%q)", ErrLDAPInvalidCredentials, username)
+		}
+
+		return nil, fmt.Errorf("%w (operation=bind, dn=%q): %w", ErrLDAPOperation, user.DN, err)
+	}
+
+	return user, nil
+}
+
+// FindUser looks up a single user entry by username with the configured user filter.
+func (la *LDAPAdapter) FindUser(ctx context.Context, username string) (*LDAPEntry, error) {
+	if la.config.BaseDN == "" {
+		return nil, ErrLDAPNoBaseDN
+	}
+
+	filter := fmt.Sprintf(la.config.UserFilter, ldap.EscapeFilter(username))
+
+	entries, err := la.Search(
+		ctx,
+		la.config.BaseDN,
+		ldap.ScopeWholeSubtree,
+		filter,
+		la.config.UserAttributes,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(entries) {
+	case 0:
+		return nil, fmt.Errorf("%w (user=%q)", ErrLDAPUserNotFound, username)
+	case 1:
+		return entries[0], nil
+	default:
+		return nil, fmt.Errorf("%w (user=%q, matches=%d)", ErrLDAPAmbiguousUser, username, len(entries))
+	}
+}
+
+// GetUserGroups returns the names of the groups the given user DN is a member of.
+func (la *LDAPAdapter) GetUserGroups(ctx context.Context, userDN string) ([]string, error) {
+	if la.config.BaseDN == "" {
+		return nil, ErrLDAPNoBaseDN
+	}
+
+	filter := fmt.Sprintf(la.config.GroupFilter, ldap.EscapeFilter(userDN))
+
+	entries, err := la.Search(
+		ctx,
+		la.config.BaseDN,
+		ldap.ScopeWholeSubtree,
+		filter,
+		[]string{la.config.GroupAttribute},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		if name := entry.GetAttribute(la.config.GroupAttribute); name != "" {
+			groups = append(groups, name)
+		}
+	}
+
+	return groups, nil
+}
+
+// IsMemberOf reports whether the given user DN is a member of the group with the given name.
+func (la *LDAPAdapter) IsMemberOf(ctx context.Context, userDN string, group string) (bool, error) {
+	groups, err := la.GetUserGroups(ctx, userDN)
+	if err != nil {
+		return false, err
+	}
+
+	for _, name := range groups {
+		if strings.EqualFold(name, group) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Search runs a search with the service account and returns the matching entries.
+func (la *LDAPAdapter) Search(
+	ctx context.Context,
+	baseDN string,
+	scope int,
+	filter string,
+	attributes []string,
+) ([]*LDAPEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("%w (operation=search): %w", ErrLDAPOperation, err)
+	}
+
+	conn, err := la.serviceConn()
+	if err != nil {
+		return nil, err
+	}
+
+	request := ldap.NewSearchRequest(
+		baseDN,
+		scope,
+		ldap.NeverDerefAliases,
+		0,
+		int(la.config.Timeout.Seconds()),
+		false,
+		filter,
+		attributes,
+		nil,
+	)
+
+	result, err := conn.Search(request)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return []*LDAPEntry{}, nil
+		}
+
+		return nil, fmt.Errorf(
+			"%w (operation=search, base=%q, filter=%q): %w",
+			ErrLDAPOperation,
+			baseDN,
+			filter,
+			err,
+		)
+	}
+
+	entries := make([]*LDAPEntry, 0, len(result.Entries))
+
+	for _, entry := range result.Entries {
+		attrs := make(map[string][]string, len(entry.Attributes))
+		for _, attr := range entry.Attributes {
+			attrs[attr.Name] = attr.Values
+		}
+
+		entries = append(entries, &LDAPEntry{
+			DN:         entry.DN,
+			Attributes: attrs,
+		})
+	}
+
+	return entries, nil
+}
+
+// Close unbinds and closes the service-account connection.
+func (la *LDAPAdapter) Close(ctx context.Context) error {
+	la.mu.Lock()
+	defer la.mu.Unlock()
+
+	if la.conn == nil {
+		return nil
+	}
+
+	err := la.conn.Close()
+	la.conn = nil
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToCloseLDAPClient, err)
+	}
+
+	return nil
+}
+
+// serviceConn returns the shared service-account connection, reconnecting if the
+// previous one was closed by the server.
+func (la *LDAPAdapter) serviceConn() (*ldap.Conn, error) {
+	la.mu.Lock()
+	defer la.mu.Unlock()
+
+	if la.conn != nil && !la.conn.IsClosing() {
+		return la.conn, nil
+	}
+
+	conn, err := la.dial()
+	if err != nil {
+		return nil, err
+	}
+
+	if la.config.BindDN != "" {
+		if err := conn.Bind(la.config.BindDN, la.config.BindPassword); err != nil {
+			_ = conn.Close()
+
+			return nil, fmt.Errorf(
+				"%w (operation=bind, dn=%q): %w",
+				ErrLDAPOperation,
+				la.config.BindDN,
+				err,
+			)
+		}
+	}
+
+	la.conn = conn
+
+	return conn, nil
+}
+
+func (la *LDAPAdapter) dial() (*ldap.Conn, error) {
+	tlsConfig := &tls.Config{ //nolint:exhaustruct
+		InsecureSkipVerify: la.config.TLSInsecureSkipVerify, //nolint:gosec
+	}
+
+	if parsedURL, err := url.Parse(la.config.URL); err == nil {
+		tlsConfig.ServerName = parsedURL.Hostname()
+	}
+
+	conn, err := ldap.DialURL(
+		la.config.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: la.config.Timeout}), //nolint:exhaustruct
+		ldap.DialWithTLSConfig(tlsConfig),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w (server=%q): %w", ErrLDAPConnectionFailed, la.config.URL, err)
+	}
+
+	conn.SetTimeout(la.config.Timeout)
+
+	if la.config.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			_ = conn.Close()
+
+			return nil, fmt.Errorf("%w (operation=starttls): %w", ErrLDAPOperation, err)
+		}
+	}
+
+	return conn, nil
+}
+
+// LDAPConnectionFactory creates LDAP connections.
+type LDAPConnectionFactory struct {
+	protocol string
+}
+
+// NewLDAPConnectionFactory creates a new LDAP connection factory for a specific protocol.
+func NewLDAPConnectionFactory(protocol string) *LDAPConnectionFactory {
+	return &LDAPConnectionFactory{
+		protocol: protocol,
+	}
+}
+
+func (f *LDAPConnectionFactory) CreateConnection( //nolint:ireturn
+	ctx context.Context,
+	config *ConfigTarget,
+) (Connection, error) {
+	ldapConfig, err := f.BuildLDAPConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToCreateLDAPClient, err)
+	}
+
+	conn := NewLDAPConnection(f.protocol, ldapConfig)
+
+	// Test the connection
+	status := conn.HealthCheck(ctx)
+	if status.State == ConnectionStateError {
+		_ = conn.Close(ctx)
+
+		return nil, fmt.Errorf("%w: %w", ErrLDAPConnectionFailed, status.Error)
+	}
+
+	return conn, nil
+}
+
+func (f *LDAPConnectionFactory) GetProtocol() string {
+	return f.protocol
+}
+
+// BuildLDAPConfig builds an LDAP configuration from a connection target. The DSN
+// accepts ldap:// and ldaps:// URLs; the URL path, if any, is used as the base DN,
+// e.g. ldaps://ldap.example.com/dc=example,dc=com.
+func (f *LDAPConnectionFactory) BuildLDAPConfig(config *ConfigTarget) (*LDAPConfig, error) {
+	ldapConfig := NewDefaultLDAPConfig()
+
+	dsn := config.DSN
+	if dsn == "" {
+		dsn = config.URL
+	}
+
+	switch {
+	case dsn != "":
+		parsedURL, err := url.Parse(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("%w (dsn=%q): %w", ErrInvalidDSN, dsn, err)
+		}
+
+		if baseDN := strings.TrimPrefix(parsedURL.Path, "/"); baseDN != "" {
+			ldapConfig.BaseDN = baseDN
+		}
+
+		parsedURL.Path = ""
+		ldapConfig.URL = parsedURL.String()
+	case config.Host != "":
+		scheme, port := "ldap", defaultLDAPPort
+		if config.TLS {
+			scheme, port = "ldaps", defaultLDAPSPort
+		}
+
+		ldapConfig.URL = fmt.Sprintf("%s://%s:%d", scheme, config.Host, getOrDefault(config.Port, port))
+	}
+
+	// For plain ldap:// URLs, TLS is negotiated with StartTLS.
+	if config.TLS && strings.HasPrefix(ldapConfig.URL, "ldap://") {
+		ldapConfig.StartTLS = true
+	}
+
+	ldapConfig.TLSInsecureSkipVerify = config.TLSSkipVerify
+
+	if config.Timeout > 0 {
+		ldapConfig.Timeout = config.Timeout
+	}
+
+	if config.Properties != nil {
+		f.configureFromProperties(ldapConfig, config.Properties)
+	}
+
+	return ldapConfig, nil
+}
+
+func (f *LDAPConnectionFactory) configureFromProperties(
+	ldapConfig *LDAPConfig,
+	properties map[string]any,
+) {
+	if bindDN, ok := properties["bind_dn"].(string); ok {
+		ldapConfig.BindDN = bindDN
+	}
+
+	if bindPassword, ok := properties["bind_password"].(string); ok {
+		ldapConfig.BindPassword = bindPassword
+	}
+
+	if baseDN, ok := properties["base_dn"].(string); ok {
+		ldapConfig.BaseDN = baseDN
+	}
+
+	if userFilter, ok := properties["user_filter"].(string); ok {
+		ldapConfig.UserFilter = userFilter
+	}
+
+	if groupFilter, ok := properties["group_filter"].(string); ok {
+		ldapConfig.GroupFilter = groupFilter
+	}
+
+	if groupAttribute, ok := properties["group_attribute"].(string); ok {
+		ldapConfig.GroupAttribute = groupAttribute
+	}
+
+	if userAttributes, ok := properties["user_attributes"].([]string); ok {
+		ldapConfig.UserAttributes = userAttributes
+	}
+
+	if startTLS, ok := properties["start_tls"].(bool); ok {
+		ldapConfig.StartTLS = startTLS
+	}
+}
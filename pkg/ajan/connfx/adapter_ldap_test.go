@@ -0,0 +1,82 @@
+package connfx_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLDAPAdapter_BuildConfig(t *testing.T) {
+	t.Parallel()
+
+	factory := connfx.NewLDAPConnectionFactory("ldap")
+	assert.Equal(t, "ldap", factory.GetProtocol())
+
+	t.Run("dsn with base dn", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := factory.BuildLDAPConfig(&connfx.ConfigTarget{ //nolint:exhaustruct
+			Protocol: "ldaps",
+			DSN:      "ldaps://dc1.corp.example.com:636/dc=corp,dc=example,dc=com",
+			Properties: map[string]any{
+				"bind_dn":       "cn=svc-aya,ou=services,dc=corp,dc=example,dc=com",
+				"bind_password": "secret",
+				"user_filter":   "(sAMAccountName=%s)",
+				"group_filter":  "(member:1.2.840.113556.1.4.1941:=%s)",
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "ldaps://dc1.corp.example.com:636", config.URL)
+		assert.Equal(t, "dc=corp,dc=example,dc=com", config.BaseDN)
+		assert.Equal(t, "cn=svc-aya,ou=services,dc=corp,dc=example,dc=com", config.BindDN)
+		assert.Equal(t, "(sAMAccountName=%s)", config.UserFilter)
+		assert.Equal(t, connfx.DefaultLDAPGroupAttribute, config.GroupAttribute)
+		assert.False(t, config.StartTLS)
+	})
+
+	t.Run("host with tls uses start tls", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := factory.BuildLDAPConfig(&connfx.ConfigTarget{ //nolint:exhaustruct
+			Protocol: "ldap",
+			URL:      "ldap://ldap.example.com",
+			TLS:      true,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "ldap://ldap.example.com", config.URL)
+		assert.True(t, config.StartTLS)
+		assert.Equal(t, connfx.DefaultLDAPTimeout, config.Timeout)
+	})
+}
+
+func TestLDAPAdapter_AuthenticateRejectsEmptyPassword(t *testing.T) {
+	t.Parallel()
+
+	conn := connfx.NewLDAPConnection("ldap", nil)
+
+	adapter, ok := conn.GetRawConnection().(*connfx.LDAPAdapter)
+	require.True(t, ok)
+
+	// Must fail before contacting the server, as an empty password is an anonymous bind.
+	_, err := adapter.Authenticate(t.Context(), "ada", "")
+	require.ErrorIs(t, err, connfx.ErrLDAPInvalidCredentials)
+}
+
+func TestLDAPAdapter_Unreachable(t *testing.T) {
+	t.Parallel()
+
+	factory := connfx.NewLDAPConnectionFactory("ldap")
+
+	_, err := factory.CreateConnection(t.Context(), &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "ldap",
+		Host:     "127.0.0.1",
+		Port:     1,
+		Timeout:  200 * time.Millisecond,
+	})
+	require.ErrorIs(t, err, connfx.ErrLDAPConnectionFailed)
+}
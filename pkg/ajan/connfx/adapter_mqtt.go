@@ -0,0 +1,803 @@
+package connfx
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+)
+
+const (
+	DefaultMQTTKeepAlive      = 30 // seconds
+	DefaultMQTTConnectTimeout = 10 * time.Second
+	DefaultMQTTQoS            = 1
+
+	// MQTTQoSHeader overrides the QoS level of a single published message.
+	MQTTQoSHeader = "x-mqtt-qos"
+	// MQTTRetainHeader marks a published message as retained, and is set on
+	// consumed messages that were delivered from the retained store.
+	MQTTRetainHeader = "x-mqtt-retain"
+	// MQTTTopicHeader is set on consumed messages to the concrete topic they were published to.
+	MQTTTopicHeader = "x-mqtt-topic"
+
+	mqttMaxQoS       = 2
+	mqttSharedPrefix = "$share/"
+)
+
+var (
+	ErrMQTTClientNotInitialized = errors.New("MQTT client not initialized")
+	ErrMQTTConnectionFailed     = errors.New("failed to connect to MQTT broker")
+	ErrFailedToCreateMQTTClient = errors.New("failed to create MQTT client")
+	ErrFailedToCloseMQTTClient  = errors.New("failed to close MQTT client")
+	ErrFailedToPublishMQTT      = errors.New("failed to publish MQTT message")
+	ErrFailedToSubscribeMQTT    = errors.New("failed to subscribe to MQTT topic")
+	ErrMQTTInvalidQoS           = errors.New("invalid MQTT QoS level")
+	ErrMQTTUnsupportedOperation = errors.New("operation not supported by MQTT")
+)
+
+// MQTTConfig holds MQTT-specific configuration options.
+type MQTTConfig struct {
+	ServerURL             string
+	ClientID              string
+	Username              string
+	Password              string
+	ConnectTimeout        time.Duration
+	SessionExpiryInterval uint32 // seconds
+	KeepAlive             uint16 // seconds
+	QoS                   byte
+	CleanStart            bool
+	TLSEnabled            bool
+	TLSInsecureSkipVerify bool
+}
+
+// NewDefaultMQTTConfig creates an MQTT configuration with sensible defaults.
+func NewDefaultMQTTConfig() *MQTTConfig {
+	return &MQTTConfig{
+		ServerURL:             "mqtt://localhost:1883",
+		ClientID:              "",
+		Username:              "",
+		Password:              "",
+		ConnectTimeout:        DefaultMQTTConnectTimeout,
+		SessionExpiryInterval: 0,
+		KeepAlive:             DefaultMQTTKeepAlive,
+		QoS:                   DefaultMQTTQoS,
+		CleanStart:            true,
+		TLSEnabled:            false,
+		TLSInsecureSkipVerify: false,
+	}
+}
+
+// mqttSubscription tracks an active consumer so it can be re-established after reconnects.
+type mqttSubscription struct {
+	ctx      context.Context //nolint:containedctx
+	messages chan<- Message
+	filter   string // topic filter used for matching incoming messages
+	topic    string // topic (or shared subscription) sent to the broker
+	mu       sync.RWMutex
+	closed   bool
+	qos      byte
+}
+
+// MQTTAdapter implements the QueueRepository interface for MQTT 5 brokers.
+type MQTTAdapter struct {
+	manager       *autopaho.ConnectionManager
+	config        *MQTTConfig
+	subscriptions map[*mqttSubscription]struct{}
+	mu            sync.Mutex
+}
+
+// MQTTConnection implements the connfx.Connection interface for MQTT connections.
+type MQTTConnection struct {
+	adapter  *MQTTAdapter
+	cancel   context.CancelFunc
+	protocol string
+	state    int32 // atomic field for connection state
+}
+
+// NewMQTTConnection creates a new MQTT connection.
+func NewMQTTConnection(protocol string, config *MQTTConfig) *MQTTConnection {
+	if config == nil {
+		config = NewDefaultMQTTConfig()
+	}
+
+	adapter := &MQTTAdapter{
+		manager:       nil,
+		config:        config,
+		subscriptions: make(map[*mqttSubscription]struct{}),
+		mu:            sync.Mutex{},
+	}
+
+	return &MQTTConnection{
+		adapter:  adapter,
+		cancel:   nil,
+		protocol: protocol,
+		state:    int32(ConnectionStateNotInitialized),
+	}
+}
+
+// Connection interface implementation.
+func (mc *MQTTConnection) GetBehaviors() []ConnectionBehavior {
+	return []ConnectionBehavior{
+		ConnectionBehaviorStateful,
+		ConnectionBehaviorStreaming,
+	}
+}
+
+func (mc *MQTTConnection) GetCapabilities() []ConnectionCapability {
+	return []ConnectionCapability{
+		ConnectionCapabilityQueue,
+	}
+}
+
+func (mc *MQTTConnection) GetProtocol() string {
+	return mc.protocol
+}
+
+func (mc *MQTTConnection) GetState() ConnectionState {
+	return ConnectionState(atomic.LoadInt32(&mc.state))
+}
+
+func (mc *MQTTConnection) HealthCheck(ctx context.Context) *HealthStatus {
+	start := time.Now()
+
+	status := &HealthStatus{
+		Timestamp: start,
+		State:     mc.GetState(),
+		Error:     nil,
+		Message:   "",
+		Latency:   0,
+	}
+
+	if mc.adapter.manager == nil {
+		status.State = ConnectionStateError
+		status.Error = ErrMQTTClientNotInitialized
+		status.Message = "MQTT client not initialized"
+
+		return status
+	}
+
+	awaitCtx, cancel := context.WithTimeout(ctx, mc.adapter.config.ConnectTimeout)
+	defer cancel()
+
+	err := mc.adapter.manager.AwaitConnection(awaitCtx)
+	status.Latency = time.Since(start)
+
+	if err != nil {
+		// autopaho keeps retrying in the background, so the connection is reconnecting
+		// rather than permanently broken.
+		atomic.StoreInt32(&mc.state, int32(ConnectionStateReconnecting))
+		status.State = ConnectionStateReconnecting
+		status.Error = err
+		status.Message = fmt.Sprintf("MQTT broker not reachable: %v", err)
+
+		return status
+	}
+
+	atomic.StoreInt32(&mc.state, int32(ConnectionStateReady))
+	status.State = ConnectionStateReady
+	status.Message = "MQTT connection is ready (server=" + mc.adapter.config.ServerURL + ")"
+
+	return status
+}
+
+func (mc *MQTTConnection) Close(ctx context.Context) error {
+	atomic.StoreInt32(&mc.state, int32(ConnectionStateDisconnected))
+
+	err := mc.adapter.Close(ctx)
+
+	if mc.cancel != nil {
+		mc.cancel()
+	}
+
+	return err
+}
+
+func (mc *MQTTConnection) GetRawConnection() any {
+	return mc.adapter
+}
+
+// GetConnectionManager returns the underlying autopaho connection manager.
+func (mc *MQTTConnection) GetConnectionManager() *autopaho.ConnectionManager {
+	return mc.adapter.manager
+}
+
+// connect starts the autopaho connection manager, which keeps reconnecting
+// in the background for the lifetime of the connection.
+func (mc *MQTTConnection) connect() error {
+	serverURL, err := url.Parse(mc.adapter.config.ServerURL)
+	if err != nil {
+		return fmt.Errorf("%w (server=%q): %w", ErrInvalidURL, mc.adapter.config.ServerURL, err)
+	}
+
+	clientConfig := autopaho.ClientConfig{ //nolint:exhaustruct
+		ServerUrls:                    []*url.URL{serverURL},
+		KeepAlive:                     mc.adapter.config.KeepAlive,
+		CleanStartOnInitialConnection: mc.adapter.config.CleanStart,
+		SessionExpiryInterval:         mc.adapter.config.SessionExpiryInterval,
+		ConnectTimeout:                mc.adapter.config.ConnectTimeout,
+		ReconnectBackoff:              autopaho.DefaultExponentialBackoff(),
+		ConnectUsername:               mc.adapter.config.Username,
+		ConnectPassword:               []byte(mc.adapter.config.Password),
+		OnConnectionUp: func(cm *autopaho.ConnectionManager, _ *paho.Connack) {
+			atomic.StoreInt32(&mc.state, int32(ConnectionStateReady))
+			mc.adapter.resubscribe(cm)
+		},
+		OnConnectError: func(error) {
+			atomic.StoreInt32(&mc.state, int32(ConnectionStateReconnecting))
+		},
+		ClientConfig: paho.ClientConfig{ //nolint:exhaustruct
+			ClientID:                   mc.adapter.config.ClientID,
+			EnableManualAcknowledgment: true,
+			OnClientError: func(error) {
+				atomic.StoreInt32(&mc.state, int32(ConnectionStateReconnecting))
+			},
+			OnServerDisconnect: func(*paho.Disconnect) {
+				atomic.StoreInt32(&mc.state, int32(ConnectionStateReconnecting))
+			},
+		},
+	}
+
+	if mc.adapter.config.TLSEnabled {
+		clientConfig.TlsCfg = &tls.Config{ //nolint:exhaustruct
+			InsecureSkipVerify: mc.adapter.config.TLSInsecureSkipVerify, //nolint:gosec
+		}
+	}
+
+	// The manager must outlive the context of the call that created it.
+	managerCtx, cancel := context.WithCancel(context.Background())
+
+	manager, err := autopaho.NewConnection(managerCtx, clientConfig)
+	if err != nil {
+		cancel()
+
+		return fmt.Errorf("%w: %w", ErrFailedToCreateMQTTClient, err)
+	}
+
+	mc.adapter.manager = manager
+	mc.cancel = cancel
+	atomic.StoreInt32(&mc.state, int32(ConnectionStateConnected))
+
+	return nil
+}
+
+// QueueRepository interface implementation.
+
+// QueueDeclare returns the topic name; MQTT topics do not need to be declared.
+func (ma *MQTTAdapter) QueueDeclare(ctx context.Context, name string) (string, error) {
+	return name, nil
+}
+
+// QueueDeclareWithConfig returns the topic name; MQTT topics do not need to be declared.
+func (ma *MQTTAdapter) QueueDeclareWithConfig(
+	ctx context.Context,
+	name string,
+	config QueueConfig,
+) (string, error) {
+	return name, nil
+}
+
+// CreateQueueIfNotExists returns the topic filter consumers of the group subscribe to.
+// A non-empty consumer group maps onto an MQTT 5 shared subscription.
+func (ma *MQTTAdapter) CreateQueueIfNotExists(
+	ctx context.Context,
+	queueName string,
+	consumerGroup string,
+	attributes map[string]string,
+) (*string, error) {
+	topic := mqttSubscriptionTopic(queueName, consumerGroup)
+
+	return &topic, nil
+}
+
+func (ma *MQTTAdapter) Publish(ctx context.Context, queueName string, body []byte) error {
+	return ma.PublishWithHeaders(ctx, queueName, body, nil)
+}
+
+// PublishWithHeaders publishes a message to a topic. Headers are sent as MQTT 5 user
+// properties, except MQTTQoSHeader and MQTTRetainHeader which control delivery.
+func (ma *MQTTAdapter) PublishWithHeaders(
+	ctx context.Context,
+	queueName string,
+	body []byte,
+	headers map[string]any,
+) error {
+	if ma.manager == nil {
+		return fmt.Errorf("%w (topic=%q)", ErrMQTTClientNotInitialized, queueName)
+	}
+
+	publish := &paho.Publish{ //nolint:exhaustruct
+		Topic:      queueName,
+		QoS:        ma.config.QoS,
+		Payload:    body,
+		Properties: &paho.PublishProperties{}, //nolint:exhaustruct
+	}
+
+	for key, value := range headers {
+		switch key {
+		case MQTTQoSHeader:
+			qos, err := parseMQTTQoS(value)
+			if err != nil {
+				return fmt.Errorf("%w (topic=%q): %w", ErrFailedToPublishMQTT, queueName, err)
+			}
+
+			publish.QoS = qos
+		case MQTTRetainHeader:
+			retain, _ := strconv.ParseBool(fmt.Sprint(value))
+			publish.Retain = retain
+		default:
+			publish.Properties.User.Add(key, fmt.Sprint(value))
+		}
+	}
+
+	if _, err := ma.manager.Publish(ctx, publish); err != nil {
+		return fmt.Errorf("%w (topic=%q): %w", ErrFailedToPublishMQTT, queueName, err)
+	}
+
+	return nil
+}
+
+// Consume subscribes to a topic filter (wildcards are allowed).
+func (ma *MQTTAdapter) Consume(
+	ctx context.Context,
+	queueName string,
+	config ConsumerConfig,
+) (<-chan Message, <-chan error) {
+	return ma.ConsumeWithGroup(ctx, queueName, "", "", config)
+}
+
+// ConsumeWithGroup subscribes using an MQTT 5 shared subscription ($share/group/topic),
+// so the broker load-balances messages between consumers of the same group.
+func (ma *MQTTAdapter) ConsumeWithGroup(
+	ctx context.Context,
+	queueName string,
+	consumerGroup string,
+	consumerName string,
+	config ConsumerConfig,
+) (<-chan Message, <-chan error) {
+	messages := make(chan Message)
+	errors := make(chan error, 1)
+
+	if ma.manager == nil {
+		errors <- fmt.Errorf("%w (topic=%q)", ErrMQTTClientNotInitialized, queueName)
+
+		close(messages)
+		close(errors)
+
+		return messages, errors
+	}
+
+	qos := ma.config.QoS
+	if value, ok := config.Args[MQTTQoSHeader]; ok {
+		parsed, err := parseMQTTQoS(value)
+		if err != nil {
+			errors <- fmt.Errorf("%w (topic=%q): %w", ErrFailedToSubscribeMQTT, queueName, err)
+
+			close(messages)
+			close(errors)
+
+			return messages, errors
+		}
+
+		qos = parsed
+	}
+
+	subscription := &mqttSubscription{
+		ctx:      ctx,
+		messages: messages,
+		filter:   queueName,
+		topic:    mqttSubscriptionTopic(queueName, consumerGroup),
+		mu:       sync.RWMutex{},
+		closed:   false,
+		qos:      qos,
+	}
+
+	removeHandler := ma.manager.AddOnPublishReceived(
+		func(received autopaho.PublishReceived) (bool, error) {
+			return ma.deliver(subscription, received, config.AutoAck), nil
+		},
+	)
+
+	ma.mu.Lock()
+	ma.subscriptions[subscription] = struct{}{}
+	ma.mu.Unlock()
+
+	go func() {
+		defer close(errors)
+
+		if err := ma.subscribe(ctx, ma.manager, subscription); err != nil {
+			errors <- err
+		}
+
+		<-ctx.Done()
+
+		removeHandler()
+
+		ma.mu.Lock()
+		delete(ma.subscriptions, subscription)
+		ma.mu.Unlock()
+
+		unsubscribeCtx, cancel := context.WithTimeout(context.Background(), ma.config.ConnectTimeout)
+		defer cancel()
+
+		_, _ = ma.manager.Unsubscribe(unsubscribeCtx, &paho.Unsubscribe{ //nolint:exhaustruct
+			Topics: []string{subscription.topic},
+		})
+
+		subscription.mu.Lock()
+		subscription.closed = true
+		close(messages)
+		subscription.mu.Unlock()
+	}()
+
+	return messages, errors
+}
+
+func (ma *MQTTAdapter) ClaimPendingMessages(
+	ctx context.Context,
+	queueName string,
+	consumerGroup string,
+	consumerName string,
+	minIdleTime time.Duration,
+	count int,
+) ([]Message, error) {
+	return []Message{}, fmt.Errorf(
+		"%w: MQTT does not support pending message claiming",
+		ErrMQTTUnsupportedOperation,
+	)
+}
+
+func (ma *MQTTAdapter) AckMessage(
+	ctx context.Context,
+	queueName, consumerGroup, receiptHandle string,
+) error {
+	// In MQTT, acknowledgment is handled through the message's Ack method
+	// This is a no-op for compatibility
+	return nil
+}
+
+func (ma *MQTTAdapter) DeleteMessage(ctx context.Context, queueName, receiptHandle string) error {
+	return fmt.Errorf(
+		"%w: MQTT does not support individual message deletion",
+		ErrMQTTUnsupportedOperation,
+	)
+}
+
+// Close disconnects from the broker.
+func (ma *MQTTAdapter) Close(ctx context.Context) error {
+	if ma.manager == nil {
+		return nil
+	}
+
+	err := ma.manager.Disconnect(ctx)
+	ma.manager = nil
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToCloseMQTTClient, err)
+	}
+
+	return nil
+}
+
+func (ma *MQTTAdapter) subscribe(
+	ctx context.Context,
+	manager *autopaho.ConnectionManager,
+	subscription *mqttSubscription,
+) error {
+	_, err := manager.Subscribe(ctx, &paho.Subscribe{ //nolint:exhaustruct
+		Subscriptions: []paho.SubscribeOptions{
+			{ //nolint:exhaustruct
+				Topic: subscription.topic,
+				QoS:   subscription.qos,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("%w (topic=%q): %w", ErrFailedToSubscribeMQTT, subscription.topic, err)
+	}
+
+	return nil
+}
+
+// resubscribe re-establishes all active subscriptions after a (re)connect.
+func (ma *MQTTAdapter) resubscribe(manager *autopaho.ConnectionManager) {
+	ma.mu.Lock()
+
+	subscriptions := make([]*mqttSubscription, 0, len(ma.subscriptions))
+	for subscription := range ma.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	ma.mu.Unlock()
+
+	for _, subscription := range subscriptions {
+		if subscription.ctx.Err() != nil {
+			continue
+		}
+
+		_ = ma.subscribe(subscription.ctx, manager, subscription)
+	}
+}
+
+// deliver forwards a received publish to the subscription when its filter matches.
+// It reports whether the message was handled.
+func (ma *MQTTAdapter) deliver(
+	subscription *mqttSubscription,
+	received autopaho.PublishReceived,
+	autoAck bool,
+) bool {
+	packet := received.Packet
+
+	if received.AlreadyHandled || !matchMQTTTopic(subscription.filter, packet.Topic) {
+		return false
+	}
+
+	subscription.mu.RLock()
+	defer subscription.mu.RUnlock()
+
+	if subscription.closed {
+		return false
+	}
+
+	msg := ma.createMessage(received, subscription.topic)
+
+	if autoAck {
+		_ = received.Client.Ack(packet)
+	}
+
+	select {
+	case subscription.messages <- msg:
+		return true
+	case <-subscription.ctx.Done():
+		return false
+	}
+}
+
+// createMessage creates a connfx.Message from an MQTT publish packet. MQTT has no
+// negative acknowledgement, so Nack acknowledges the packet as well; requesting a
+// requeue reports ErrMQTTUnsupportedOperation.
+func (ma *MQTTAdapter) createMessage(received autopaho.PublishReceived, topic string) Message {
+	packet := received.Packet
+
+	headers := map[string]any{
+		MQTTTopicHeader:  packet.Topic,
+		MQTTRetainHeader: packet.Retain,
+		MQTTQoSHeader:    int(packet.QoS),
+	}
+
+	if packet.Properties != nil {
+		for _, property := range packet.Properties.User {
+			headers[property.Key] = property.Value
+		}
+	}
+
+	msg := Message{ //nolint:exhaustruct
+		Timestamp:     time.Now(),
+		Headers:       headers,
+		Body:          packet.Payload,
+		ReceiptHandle: strconv.Itoa(int(packet.PacketID)),
+		MessageID:     strconv.Itoa(int(packet.PacketID)),
+		ConsumerGroup: topic,
+		StreamName:    packet.Topic,
+		DeliveryCount: 1,
+	}
+
+	ack := func() error {
+		err := received.Client.Ack(packet)
+		if err != nil && !errors.Is(err, paho.ErrManualAcknowledgmentDisabled) {
+			return fmt.Errorf("%w (operation=ack): %w", ErrMQTTUnsupportedOperation, err)
+		}
+
+		return nil
+	}
+
+	msg.SetAckFunc(ack)
+
+	msg.SetNackFunc(func(requeue bool) error {
+		if err := ack(); err != nil {
+			return err
+		}
+
+		if requeue {
+			return fmt.Errorf("%w: MQTT cannot requeue messages", ErrMQTTUnsupportedOperation)
+		}
+
+		return nil
+	})
+
+	return msg
+}
+
+// mqttSubscriptionTopic returns the topic to subscribe to for a consumer group.
+func mqttSubscriptionTopic(topic string, consumerGroup string) string {
+	if consumerGroup == "" {
+		return topic
+	}
+
+	return mqttSharedPrefix + consumerGroup + "/" + topic
+}
+
+// matchMQTTTopic reports whether a topic name matches a topic filter with
+// single-level (+) and multi-level (#) wildcards.
+func matchMQTTTopic(filter string, topic string) bool {
+	filterLevels := strings.Split(filter, "/")
+	topicLevels := strings.Split(topic, "/")
+
+	// Wildcards must not match topics starting with $ (e.g. $SYS)
+	if strings.HasPrefix(topic, "$") && len(filterLevels) > 0 &&
+		(filterLevels[0] == "+" || filterLevels[0] == "#") {
+		return false
+	}
+
+	for i, level := range filterLevels {
+		if level == "#" {
+			return true
+		}
+
+		if i >= len(topicLevels) {
+			return false
+		}
+
+		if level != "+" && level != topicLevels[i] {
+			return false
+		}
+	}
+
+	return len(filterLevels) == len(topicLevels)
+}
+
+func parseMQTTQoS(value any) (byte, error) {
+	qos, err := strconv.Atoi(fmt.Sprint(value))
+	if err != nil || qos < 0 || qos > mqttMaxQoS {
+		return 0, fmt.Errorf("%w: %v", ErrMQTTInvalidQoS, value)
+	}
+
+	return byte(qos), nil
+}
+
+// MQTTConnectionFactory creates MQTT connections.
+type MQTTConnectionFactory struct {
+	protocol string
+}
+
+// NewMQTTConnectionFactory creates a new MQTT connection factory for a specific protocol.
+func NewMQTTConnectionFactory(protocol string) *MQTTConnectionFactory {
+	return &MQTTConnectionFactory{
+		protocol: protocol,
+	}
+}
+
+func (f *MQTTConnectionFactory) CreateConnection( //nolint:ireturn
+	ctx context.Context,
+	config *ConfigTarget,
+) (Connection, error) {
+	mqttConfig, err := f.BuildMQTTConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToCreateMQTTClient, err)
+	}
+
+	conn := NewMQTTConnection(f.protocol, mqttConfig)
+
+	if err := conn.connect(); err != nil {
+		return nil, err
+	}
+
+	// Test the connection
+	status := conn.HealthCheck(ctx)
+	if status.State != ConnectionStateReady {
+		_ = conn.Close(ctx)
+
+		return nil, fmt.Errorf("%w: %w", ErrMQTTConnectionFailed, status.Error)
+	}
+
+	return conn, nil
+}
+
+func (f *MQTTConnectionFactory) GetProtocol() string {
+	return f.protocol
+}
+
+// BuildMQTTConfig builds an MQTT configuration from a connection target. The DSN
+// accepts mqtt://, mqtts://, tcp://, ssl://, ws:// and wss:// URLs with optional
+// user credentials.
+func (f *MQTTConnectionFactory) BuildMQTTConfig(config *ConfigTarget) (*MQTTConfig, error) {
+	mqttConfig := NewDefaultMQTTConfig()
+
+	switch {
+	case config.DSN != "":
+		mqttConfig.ServerURL = config.DSN
+	case config.URL != "":
+		mqttConfig.ServerURL = config.URL
+	case config.Host != "":
+		mqttConfig.ServerURL = fmt.Sprintf("mqtt://%s:%d", config.Host, getOrDefault(config.Port, 1883))
+	}
+
+	parsedURL, err := url.Parse(mqttConfig.ServerURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w (dsn=%q): %w", ErrInvalidDSN, mqttConfig.ServerURL, err)
+	}
+
+	if parsedURL.User != nil {
+		mqttConfig.Username = parsedURL.User.Username()
+		mqttConfig.Password, _ = parsedURL.User.Password()
+
+		parsedURL.User = nil
+	}
+
+	if parsedURL.Scheme == "mqtts" || parsedURL.Scheme == "ssl" || parsedURL.Scheme == "wss" {
+		mqttConfig.TLSEnabled = true
+	}
+
+	if parsedURL.Scheme == "mqtts" {
+		// autopaho understands tls:// rather than mqtts://
+		parsedURL.Scheme = "tls"
+	}
+
+	mqttConfig.ServerURL = parsedURL.String()
+
+	if config.TLS {
+		mqttConfig.TLSEnabled = true
+	}
+
+	mqttConfig.TLSInsecureSkipVerify = config.TLSSkipVerify
+
+	if config.Timeout > 0 {
+		mqttConfig.ConnectTimeout = config.Timeout
+	}
+
+	if config.Properties != nil {
+		if err := f.configureFromProperties(mqttConfig, config.Properties); err != nil {
+			return nil, err
+		}
+	}
+
+	return mqttConfig, nil
+}
+
+func (f *MQTTConnectionFactory) configureFromProperties(
+	mqttConfig *MQTTConfig,
+	properties map[string]any,
+) error {
+	if clientID, ok := properties["client_id"].(string); ok {
+		mqttConfig.ClientID = clientID
+	}
+
+	if username, ok := properties["username"].(string); ok {
+		mqttConfig.Username = username
+	}
+
+	if password, ok := properties["password"].(string); ok {
+		mqttConfig.Password = password
+	}
+
+	if keepAlive, ok := properties["keep_alive"].(int); ok && keepAlive >= 0 {
+		mqttConfig.KeepAlive = uint16(keepAlive) //nolint:gosec
+	}
+
+	if sessionExpiry, ok := properties["session_expiry"].(time.Duration); ok {
+		mqttConfig.SessionExpiryInterval = uint32(sessionExpiry.Seconds())
+	}
+
+	if cleanStart, ok := properties["clean_start"].(bool); ok {
+		mqttConfig.CleanStart = cleanStart
+	}
+
+	if qos, ok := properties["qos"]; ok {
+		parsed, err := parseMQTTQoS(qos)
+		if err != nil {
+			return err
+		}
+
+		mqttConfig.QoS = parsed
+	}
+
+	return nil
+}
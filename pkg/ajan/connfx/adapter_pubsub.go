@@ -0,0 +1,773 @@
+package connfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cloud.google.com/go/pubsub/v2"
+	"cloud.google.com/go/pubsub/v2/apiv1/pubsubpb"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	DefaultPubSubAckDeadline        = 30 * time.Second
+	DefaultPubSubMaxExtension       = 10 * time.Minute
+	DefaultPubSubSubscriptionSuffix = "connfx"
+
+	// PubSubOrderingKeyHeader is the message header used to carry the Pub/Sub ordering key.
+	PubSubOrderingKeyHeader = "x-ordering-key"
+)
+
+var (
+	ErrPubSubClientNotInitialized   = errors.New("pub/sub client not initialized")
+	ErrPubSubConnectionFailed       = errors.New("failed to connect to Pub/Sub")
+	ErrFailedToCreatePubSubClient   = errors.New("failed to create Pub/Sub client")
+	ErrFailedToClosePubSubClient    = errors.New("failed to close Pub/Sub client")
+	ErrPubSubProjectIDRequired      = errors.New("pub/sub project ID is required")
+	ErrPubSubTopicNotFound          = errors.New("pub/sub topic not found")
+	ErrPubSubSubscriptionNotFound   = errors.New("pub/sub subscription not found")
+	ErrFailedToEnsurePubSubTopic    = errors.New("failed to ensure Pub/Sub topic")
+	ErrFailedToEnsurePubSubSub      = errors.New("failed to ensure Pub/Sub subscription")
+	ErrFailedToPublishPubSubMessage = errors.New("failed to publish Pub/Sub message")
+	ErrPubSubReceiveFailed          = errors.New("pub/sub receive failed")
+	ErrPubSubUnsupportedOperation   = errors.New("operation not supported by Pub/Sub")
+)
+
+// PubSubConfig holds Google Cloud Pub/Sub specific configuration options.
+type PubSubConfig struct {
+	ProjectID          string
+	EmulatorHost       string
+	CredentialsFile    string
+	SubscriptionSuffix string
+	AckDeadline        time.Duration
+	MaxExtension       time.Duration
+	AutoCreate         bool
+	MessageOrdering    bool
+}
+
+// NewDefaultPubSubConfig creates a Pub/Sub configuration with sensible defaults.
+func NewDefaultPubSubConfig() *PubSubConfig {
+	return &PubSubConfig{
+		ProjectID:          "",
+		EmulatorHost:       "",
+		CredentialsFile:    "",
+		SubscriptionSuffix: DefaultPubSubSubscriptionSuffix,
+		AckDeadline:        DefaultPubSubAckDeadline,
+		MaxExtension:       DefaultPubSubMaxExtension,
+		AutoCreate:         true,
+		MessageOrdering:    false,
+	}
+}
+
+// PubSubAdapter implements the QueueRepository interface for Google Cloud Pub/Sub.
+type PubSubAdapter struct {
+	client     *pubsub.Client
+	config     *PubSubConfig
+	publishers map[string]*pubsub.Publisher
+	topics     map[string]struct{} // topics known to exist
+	mu         sync.Mutex
+}
+
+// PubSubConnection implements the connfx.Connection interface for Pub/Sub connections.
+type PubSubConnection struct {
+	adapter  *PubSubAdapter
+	protocol string
+	state    int32 // atomic field for connection state
+}
+
+// NewPubSubConnection creates a new Pub/Sub connection.
+func NewPubSubConnection(protocol string, config *PubSubConfig) *PubSubConnection {
+	if config == nil {
+		config = NewDefaultPubSubConfig()
+	}
+
+	adapter := &PubSubAdapter{
+		client:     nil,
+		config:     config,
+		publishers: make(map[string]*pubsub.Publisher),
+		topics:     make(map[string]struct{}),
+		mu:         sync.Mutex{},
+	}
+
+	return &PubSubConnection{
+		adapter:  adapter,
+		protocol: protocol,
+		state:    int32(ConnectionStateNotInitialized),
+	}
+}
+
+// Connection interface implementation.
+func (pc *PubSubConnection) GetBehaviors() []ConnectionBehavior {
+	return []ConnectionBehavior{
+		ConnectionBehaviorStateful,
+		ConnectionBehaviorStreaming,
+	}
+}
+
+func (pc *PubSubConnection) GetCapabilities() []ConnectionCapability {
+	return []ConnectionCapability{
+		ConnectionCapabilityQueue,
+	}
+}
+
+func (pc *PubSubConnection) GetProtocol() string {
+	return pc.protocol
+}
+
+func (pc *PubSubConnection) GetState() ConnectionState {
+	return ConnectionState(atomic.LoadInt32(&pc.state))
+}
+
+func (pc *PubSubConnection) HealthCheck(ctx context.Context) *HealthStatus {
+	start := time.Now()
+
+	status := &HealthStatus{
+		Timestamp: start,
+		State:     pc.GetState(),
+		Error:     nil,
+		Message:   "",
+		Latency:   0,
+	}
+
+	if err := pc.adapter.ensureClient(ctx); err != nil {
+		atomic.StoreInt32(&pc.state, int32(ConnectionStateError))
+		status.State = ConnectionStateError
+		status.Error = err
+		status.Message = fmt.Sprintf("Failed to initialize Pub/Sub client: %v", err)
+		status.Latency = time.Since(start)
+
+		return status
+	}
+
+	// Listing a single topic is the cheapest authenticated round-trip available.
+	topics := pc.adapter.client.TopicAdminClient.ListTopics(ctx, &pubsubpb.ListTopicsRequest{ //nolint:exhaustruct
+		Project:  "projects/" + pc.adapter.config.ProjectID,
+		PageSize: 1,
+	})
+
+	_, err := topics.Next()
+	status.Latency = time.Since(start)
+
+	if err != nil && !errors.Is(err, iterator.Done) {
+		atomic.StoreInt32(&pc.state, int32(ConnectionStateError))
+		status.State = ConnectionStateError
+		status.Error = err
+		status.Message = fmt.Sprintf("Pub/Sub health check failed: %v", err)
+
+		return status
+	}
+
+	atomic.StoreInt32(&pc.state, int32(ConnectionStateReady))
+	status.State = ConnectionStateReady
+	status.Message = "Pub/Sub connection is ready (project=" + pc.adapter.config.ProjectID + ")"
+
+	return status
+}
+
+func (pc *PubSubConnection) Close(ctx context.Context) error {
+	atomic.StoreInt32(&pc.state, int32(ConnectionStateDisconnected))
+
+	if err := pc.adapter.Close(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (pc *PubSubConnection) GetRawConnection() any {
+	return pc.adapter
+}
+
+// GetClient returns the underlying Pub/Sub client for advanced operations.
+func (pc *PubSubConnection) GetClient() *pubsub.Client {
+	return pc.adapter.client
+}
+
+// QueueRepository interface implementation.
+
+// QueueDeclare ensures a topic with the given name exists and returns its name.
+func (pa *PubSubAdapter) QueueDeclare(ctx context.Context, name string) (string, error) {
+	return pa.QueueDeclareWithConfig(ctx, name, DefaultQueueConfig())
+}
+
+// QueueDeclareWithConfig ensures a topic exists. MessageTTL is mapped onto the
+// topic's message retention duration; the remaining options have no Pub/Sub equivalent.
+func (pa *PubSubAdapter) QueueDeclareWithConfig(
+	ctx context.Context,
+	name string,
+	config QueueConfig,
+) (string, error) {
+	if err := pa.ensureClient(ctx); err != nil {
+		return "", fmt.Errorf("%w (topic=%q): %w", ErrPubSubClientNotInitialized, name, err)
+	}
+
+	if err := pa.ensureTopic(ctx, name, config.MessageTTL); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// CreateQueueIfNotExists ensures both the topic and the subscription for the given
+// consumer group exist, and returns the subscription name.
+func (pa *PubSubAdapter) CreateQueueIfNotExists(
+	ctx context.Context,
+	queueName string,
+	consumerGroup string,
+	attributes map[string]string,
+) (*string, error) {
+	if err := pa.ensureClient(ctx); err != nil {
+		return nil, fmt.Errorf("%w (topic=%q): %w", ErrPubSubClientNotInitialized, queueName, err)
+	}
+
+	if err := pa.ensureTopic(ctx, queueName, 0); err != nil {
+		return nil, err
+	}
+
+	subscriptionName := pa.subscriptionName(queueName, consumerGroup)
+
+	if err := pa.ensureSubscription(ctx, queueName, subscriptionName, attributes); err != nil {
+		return nil, err
+	}
+
+	return &subscriptionName, nil
+}
+
+func (pa *PubSubAdapter) Publish(ctx context.Context, queueName string, body []byte) error {
+	return pa.PublishWithHeaders(ctx, queueName, body, nil)
+}
+
+// PublishWithHeaders publishes a message and waits for the server to acknowledge it.
+// Headers are sent as message attributes; the PubSubOrderingKeyHeader header, when
+// present, is used as the ordering key instead.
+func (pa *PubSubAdapter) PublishWithHeaders(
+	ctx context.Context,
+	queueName string,
+	body []byte,
+	headers map[string]any,
+) error {
+	if err := pa.ensureClient(ctx); err != nil {
+		return fmt.Errorf("%w (topic=%q): %w", ErrPubSubClientNotInitialized, queueName, err)
+	}
+
+	if err := pa.ensureTopic(ctx, queueName, 0); err != nil {
+		return err
+	}
+
+	msg := &pubsub.Message{ //nolint:exhaustruct
+		Data:       body,
+		Attributes: make(map[string]string, len(headers)),
+	}
+
+	for key, value := range headers {
+		if key == PubSubOrderingKeyHeader {
+			msg.OrderingKey = fmt.Sprint(value)
+
+			continue
+		}
+
+		msg.Attributes[key] = fmt.Sprint(value)
+	}
+
+	publisher := pa.getPublisher(queueName)
+
+	_, err := publisher.Publish(ctx, msg).Get(ctx)
+	if err != nil {
+		if msg.OrderingKey != "" {
+			// Publishing for an ordering key is paused after a failure until resumed.
+			publisher.ResumePublish(msg.OrderingKey)
+		}
+
+		return fmt.Errorf("%w (topic=%q): %w", ErrFailedToPublishPubSubMessage, queueName, err)
+	}
+
+	return nil
+}
+
+// Consume receives messages from the adapter's default subscription for the topic.
+func (pa *PubSubAdapter) Consume(
+	ctx context.Context,
+	queueName string,
+	config ConsumerConfig,
+) (<-chan Message, <-chan error) {
+	return pa.ConsumeWithGroup(ctx, queueName, "", "", config)
+}
+
+// ConsumeWithGroup receives messages from the subscription named after the consumer group.
+// Pub/Sub load-balances messages between all consumers attached to the same subscription,
+// so consumerName is informational only.
+func (pa *PubSubAdapter) ConsumeWithGroup(
+	ctx context.Context,
+	queueName string,
+	consumerGroup string,
+	consumerName string,
+	config ConsumerConfig,
+) (<-chan Message, <-chan error) {
+	messages := make(chan Message)
+	errors := make(chan error)
+
+	go func() {
+		defer close(messages)
+		defer close(errors)
+
+		pa.receiveLoop(ctx, queueName, consumerGroup, config, messages, errors)
+	}()
+
+	return messages, errors
+}
+
+func (pa *PubSubAdapter) ClaimPendingMessages(
+	ctx context.Context,
+	queueName string,
+	consumerGroup string,
+	consumerName string,
+	minIdleTime time.Duration,
+	count int,
+) ([]Message, error) {
+	// Pub/Sub redelivers messages once their ack deadline expires
+	return []Message{}, fmt.Errorf(
+		"%w: Pub/Sub redelivers expired messages automatically",
+		ErrPubSubUnsupportedOperation,
+	)
+}
+
+func (pa *PubSubAdapter) AckMessage(
+	ctx context.Context,
+	queueName, consumerGroup, receiptHandle string,
+) error {
+	// In Pub/Sub, acknowledgment is handled through the message's Ack method
+	// This is a no-op for compatibility
+	return nil
+}
+
+func (pa *PubSubAdapter) DeleteMessage(ctx context.Context, queueName, receiptHandle string) error {
+	return fmt.Errorf(
+		"%w: Pub/Sub does not support individual message deletion",
+		ErrPubSubUnsupportedOperation,
+	)
+}
+
+// Close stops all publishers and closes the underlying client.
+func (pa *PubSubAdapter) Close(ctx context.Context) error {
+	pa.mu.Lock()
+	defer pa.mu.Unlock()
+
+	for name, publisher := range pa.publishers {
+		publisher.Stop()
+		delete(pa.publishers, name)
+	}
+
+	clear(pa.topics)
+
+	if pa.client == nil {
+		return nil
+	}
+
+	err := pa.client.Close()
+	pa.client = nil
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToClosePubSubClient, err)
+	}
+
+	return nil
+}
+
+// ensureClient creates the Pub/Sub client if it does not exist yet.
+func (pa *PubSubAdapter) ensureClient(ctx context.Context) error {
+	pa.mu.Lock()
+	defer pa.mu.Unlock()
+
+	if pa.client != nil {
+		return nil
+	}
+
+	if pa.config.ProjectID == "" {
+		return ErrPubSubProjectIDRequired
+	}
+
+	var options []option.ClientOption
+
+	if pa.config.EmulatorHost != "" {
+		options = append(options,
+			option.WithEndpoint(pa.config.EmulatorHost),
+			option.WithoutAuthentication(),
+			option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+		)
+	} else if pa.config.CredentialsFile != "" {
+		options = append(options, option.WithCredentialsFile(pa.config.CredentialsFile))
+	}
+
+	client, err := pubsub.NewClient(ctx, pa.config.ProjectID, options...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToCreatePubSubClient, err)
+	}
+
+	pa.client = client
+
+	return nil
+}
+
+// getPublisher returns a cached publisher for the topic.
+func (pa *PubSubAdapter) getPublisher(topicName string) *pubsub.Publisher {
+	pa.mu.Lock()
+	defer pa.mu.Unlock()
+
+	if publisher, ok := pa.publishers[topicName]; ok {
+		return publisher
+	}
+
+	publisher := pa.client.Publisher(topicName)
+	publisher.EnableMessageOrdering = pa.config.MessageOrdering
+
+	pa.publishers[topicName] = publisher
+
+	return publisher
+}
+
+func (pa *PubSubAdapter) topicPath(topicName string) string {
+	if strings.HasPrefix(topicName, "projects/") {
+		return topicName
+	}
+
+	return fmt.Sprintf("projects/%s/topics/%s", pa.config.ProjectID, topicName)
+}
+
+func (pa *PubSubAdapter) subscriptionPath(subscriptionName string) string {
+	if strings.HasPrefix(subscriptionName, "projects/") {
+		return subscriptionName
+	}
+
+	return fmt.Sprintf("projects/%s/subscriptions/%s", pa.config.ProjectID, subscriptionName)
+}
+
+// subscriptionName derives the subscription ID for a topic and consumer group.
+func (pa *PubSubAdapter) subscriptionName(topicName string, consumerGroup string) string {
+	if consumerGroup != "" {
+		return consumerGroup
+	}
+
+	return topicName + "-" + pa.config.SubscriptionSuffix
+}
+
+// ensureTopic creates the topic when auto-creation is enabled, otherwise verifies it exists.
+func (pa *PubSubAdapter) ensureTopic(
+	ctx context.Context,
+	topicName string,
+	retention time.Duration,
+) error {
+	topicPath := pa.topicPath(topicName)
+
+	pa.mu.Lock()
+	_, known := pa.topics[topicPath]
+	pa.mu.Unlock()
+
+	if known {
+		return nil
+	}
+
+	if !pa.config.AutoCreate {
+		_, err := pa.client.TopicAdminClient.GetTopic(ctx, &pubsubpb.GetTopicRequest{ //nolint:exhaustruct
+			Topic: topicPath,
+		})
+		if status.Code(err) == codes.NotFound {
+			return fmt.Errorf("%w (topic=%q)", ErrPubSubTopicNotFound, topicName)
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w (topic=%q): %w", ErrFailedToEnsurePubSubTopic, topicName, err)
+		}
+
+		pa.markTopicKnown(topicPath)
+
+		return nil
+	}
+
+	topic := &pubsubpb.Topic{ //nolint:exhaustruct
+		Name: topicPath,
+	}
+
+	if retention > 0 {
+		topic.MessageRetentionDuration = durationpb.New(retention)
+	}
+
+	_, err := pa.client.TopicAdminClient.CreateTopic(ctx, topic)
+	if err != nil && status.Code(err) != codes.AlreadyExists {
+		return fmt.Errorf("%w (topic=%q): %w", ErrFailedToEnsurePubSubTopic, topicName, err)
+	}
+
+	pa.markTopicKnown(topicPath)
+
+	return nil
+}
+
+func (pa *PubSubAdapter) markTopicKnown(topicPath string) {
+	pa.mu.Lock()
+	defer pa.mu.Unlock()
+
+	pa.topics[topicPath] = struct{}{}
+}
+
+// ensureSubscription creates the subscription when auto-creation is enabled,
+// otherwise verifies it exists. The "filter" attribute is passed through as the
+// subscription filter.
+func (pa *PubSubAdapter) ensureSubscription(
+	ctx context.Context,
+	topicName string,
+	subscriptionName string,
+	attributes map[string]string,
+) error {
+	subscriptionPath := pa.subscriptionPath(subscriptionName)
+
+	if !pa.config.AutoCreate {
+		_, err := pa.client.SubscriptionAdminClient.GetSubscription(
+			ctx,
+			&pubsubpb.GetSubscriptionRequest{ //nolint:exhaustruct
+				Subscription: subscriptionPath,
+			},
+		)
+		if status.Code(err) == codes.NotFound {
+			return fmt.Errorf("%w (subscription=%q)", ErrPubSubSubscriptionNotFound, subscriptionName)
+		}
+
+		if err != nil {
+			return fmt.Errorf(
+				"%w (subscription=%q): %w",
+				ErrFailedToEnsurePubSubSub,
+				subscriptionName,
+				err,
+			)
+		}
+
+		return nil
+	}
+
+	subscription := &pubsubpb.Subscription{ //nolint:exhaustruct
+		Name:                  subscriptionPath,
+		Topic:                 pa.topicPath(topicName),
+		AckDeadlineSeconds:    int32(pa.config.AckDeadline.Seconds()),
+		EnableMessageOrdering: pa.config.MessageOrdering,
+		Labels:                map[string]string{},
+	}
+
+	if filter, ok := attributes["filter"]; ok {
+		subscription.Filter = filter
+	}
+
+	_, err := pa.client.SubscriptionAdminClient.CreateSubscription(ctx, subscription)
+	if err != nil && status.Code(err) != codes.AlreadyExists {
+		return fmt.Errorf(
+			"%w (subscription=%q): %w",
+			ErrFailedToEnsurePubSubSub,
+			subscriptionName,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// receiveLoop streams messages from a subscription until ctx is done.
+func (pa *PubSubAdapter) receiveLoop(
+	ctx context.Context,
+	queueName string,
+	consumerGroup string,
+	config ConsumerConfig,
+	messages chan<- Message,
+	errors chan<- error,
+) {
+	subscriptionName, err := pa.CreateQueueIfNotExists(ctx, queueName, consumerGroup, nil)
+	if err != nil {
+		select {
+		case errors <- err:
+		case <-ctx.Done():
+		}
+
+		return
+	}
+
+	subscriber := pa.client.Subscriber(*subscriptionName)
+	subscriber.ReceiveSettings.MaxExtension = pa.config.MaxExtension
+
+	if config.PrefetchCount > 0 {
+		subscriber.ReceiveSettings.MaxOutstandingMessages = config.PrefetchCount
+	}
+
+	err = subscriber.Receive(ctx, func(msgCtx context.Context, received *pubsub.Message) {
+		msg := pa.createMessage(received, *subscriptionName, queueName)
+
+		if config.AutoAck {
+			received.Ack()
+		}
+
+		select {
+		case messages <- msg:
+		case <-msgCtx.Done():
+			// Let the message be redelivered to another consumer
+			received.Nack()
+		}
+	})
+	if err != nil {
+		select {
+		case errors <- fmt.Errorf("%w (subscription=%q): %w", ErrPubSubReceiveFailed, *subscriptionName, err):
+		case <-ctx.Done():
+		}
+	}
+}
+
+// createMessage creates a connfx.Message from a Pub/Sub message. Nack with requeue
+// triggers immediate redelivery, while Nack without requeue acknowledges the
+// message so it is not delivered again.
+func (pa *PubSubAdapter) createMessage(
+	received *pubsub.Message,
+	subscriptionName string,
+	topicName string,
+) Message {
+	headers := make(map[string]any, len(received.Attributes)+1)
+	for key, value := range received.Attributes {
+		headers[key] = value
+	}
+
+	if received.OrderingKey != "" {
+		headers[PubSubOrderingKeyHeader] = received.OrderingKey
+	}
+
+	deliveryCount := 1
+	if received.DeliveryAttempt != nil {
+		deliveryCount = *received.DeliveryAttempt
+	}
+
+	msg := Message{ //nolint:exhaustruct
+		Headers:       headers,
+		Body:          received.Data,
+		ReceiptHandle: received.ID,
+		MessageID:     received.ID,
+		Timestamp:     received.PublishTime,
+		ConsumerGroup: subscriptionName,
+		StreamName:    topicName,
+		DeliveryCount: deliveryCount,
+	}
+
+	msg.SetAckFunc(func() error {
+		received.Ack()
+
+		return nil
+	})
+
+	msg.SetNackFunc(func(requeue bool) error {
+		if requeue {
+			received.Nack()
+		} else {
+			received.Ack()
+		}
+
+		return nil
+	})
+
+	return msg
+}
+
+// PubSubConnectionFactory creates Pub/Sub connections.
+type PubSubConnectionFactory struct {
+	protocol string
+}
+
+// NewPubSubConnectionFactory creates a new Pub/Sub connection factory for a specific protocol.
+func NewPubSubConnectionFactory(protocol string) *PubSubConnectionFactory {
+	return &PubSubConnectionFactory{
+		protocol: protocol,
+	}
+}
+
+func (f *PubSubConnectionFactory) CreateConnection( //nolint:ireturn
+	ctx context.Context,
+	config *ConfigTarget,
+) (Connection, error) {
+	pubsubConfig := f.BuildPubSubConfig(config)
+
+	conn := NewPubSubConnection(f.protocol, pubsubConfig)
+
+	// Test the connection
+	status := conn.HealthCheck(ctx)
+	if status.State == ConnectionStateError {
+		_ = conn.Close(ctx)
+
+		return nil, fmt.Errorf("%w: %w", ErrPubSubConnectionFailed, status.Error)
+	}
+
+	return conn, nil
+}
+
+func (f *PubSubConnectionFactory) GetProtocol() string {
+	return f.protocol
+}
+
+// BuildPubSubConfig builds a Pub/Sub configuration from a connection target.
+// The project ID is read from a DSN in the form pubsub://project-id, or from
+// the Host field. An emulator endpoint can be given via the "emulator_host"
+// property.
+func (f *PubSubConnectionFactory) BuildPubSubConfig(config *ConfigTarget) *PubSubConfig {
+	pubsubConfig := NewDefaultPubSubConfig()
+
+	pubsubConfig.ProjectID = config.Host
+	pubsubConfig.CredentialsFile = config.CertFile
+
+	if config.DSN != "" {
+		parsedURL, err := url.Parse(config.DSN)
+		if err == nil && parsedURL.Scheme != "" {
+			pubsubConfig.ProjectID = parsedURL.Host
+		} else {
+			pubsubConfig.ProjectID = config.DSN
+		}
+	}
+
+	if config.Timeout > 0 {
+		pubsubConfig.AckDeadline = config.Timeout
+	}
+
+	if config.Properties == nil {
+		return pubsubConfig
+	}
+
+	if emulatorHost, ok := config.Properties["emulator_host"].(string); ok {
+		pubsubConfig.EmulatorHost = emulatorHost
+	}
+
+	if credentialsFile, ok := config.Properties["credentials_file"].(string); ok {
+		pubsubConfig.CredentialsFile = credentialsFile
+	}
+
+	if suffix, ok := config.Properties["subscription_suffix"].(string); ok && suffix != "" {
+		pubsubConfig.SubscriptionSuffix = suffix
+	}
+
+	if ackDeadline, ok := config.Properties["ack_deadline"].(time.Duration); ok {
+		pubsubConfig.AckDeadline = ackDeadline
+	}
+
+	if maxExtension, ok := config.Properties["max_extension"].(time.Duration); ok {
+		pubsubConfig.MaxExtension = maxExtension
+	}
+
+	if autoCreate, ok := config.Properties["auto_create"].(bool); ok {
+		pubsubConfig.AutoCreate = autoCreate
+	}
+
+	if ordering, ok := config.Properties["message_ordering"].(bool); ok {
+		pubsubConfig.MessageOrdering = ordering
+	}
+
+	return pubsubConfig
+}
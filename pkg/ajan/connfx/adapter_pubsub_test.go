@@ -0,0 +1,96 @@
+package connfx_test
+
+import (
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub/v2/pstest"
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPubSubAdapter_BuildConfig(t *testing.T) {
+	t.Parallel()
+
+	factory := connfx.NewPubSubConnectionFactory("pubsub")
+	assert.Equal(t, "pubsub", factory.GetProtocol())
+
+	config := factory.BuildPubSubConfig(&connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "pubsub",
+		DSN:      "pubsub://my-project",
+		Properties: map[string]any{
+			"emulator_host":    "localhost:8085",
+			"ack_deadline":     20 * time.Second,
+			"message_ordering": true,
+			"auto_create":      false,
+		},
+	})
+
+	assert.Equal(t, "my-project", config.ProjectID)
+	assert.Equal(t, "localhost:8085", config.EmulatorHost)
+	assert.Equal(t, 20*time.Second, config.AckDeadline)
+	assert.Equal(t, connfx.DefaultPubSubMaxExtension, config.MaxExtension)
+	assert.True(t, config.MessageOrdering)
+	assert.False(t, config.AutoCreate)
+}
+
+func TestPubSubAdapter_PublishConsume(t *testing.T) {
+	t.Parallel()
+
+	srv := pstest.NewServer()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	ctx := t.Context()
+
+	registry := connfx.NewRegistry(connfx.WithLogger(newMockLogger()))
+	registry.RegisterFactory(connfx.NewPubSubConnectionFactory("pubsub"))
+
+	conn, err := registry.AddConnection(ctx, "events", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "pubsub",
+		DSN:      "pubsub://test-project",
+		Properties: map[string]any{
+			"emulator_host":    srv.Addr,
+			"message_ordering": true,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, connfx.ConnectionStateReady, conn.GetState())
+	assert.Contains(t, conn.GetCapabilities(), connfx.ConnectionCapabilityQueue)
+
+	t.Cleanup(func() { _ = registry.Close(ctx) })
+
+	queue, ok := conn.GetRawConnection().(connfx.QueueRepository)
+	require.True(t, ok)
+
+	subscription, err := queue.CreateQueueIfNotExists(ctx, "story-events", "indexer", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "indexer", *subscription)
+
+	err = queue.PublishWithHeaders(ctx, "story-events", []byte("hello"), map[string]any{
+		"kind":                         "created",
+		connfx.PubSubOrderingKeyHeader: "story-1",
+	})
+	require.NoError(t, err)
+
+	messages, errs := queue.ConsumeWithGroup(
+		ctx,
+		"story-events",
+		"indexer",
+		"worker-1",
+		connfx.DefaultConsumerConfig(),
+	)
+
+	select {
+	case msg := <-messages:
+		assert.Equal(t, []byte("hello"), msg.Body)
+		assert.Equal(t, "created", msg.Headers["kind"])
+		assert.Equal(t, "story-1", msg.Headers[connfx.PubSubOrderingKeyHeader])
+		assert.Equal(t, "story-events", msg.StreamName)
+		require.NoError(t, msg.Ack())
+	case err := <-errs:
+		t.Fatalf("unexpected consume error: %v", err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for message")
+	}
+}
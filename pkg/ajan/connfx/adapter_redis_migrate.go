@@ -0,0 +1,208 @@
+package connfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const defaultScanBatchSize = 500
+
+var ErrInvalidExpiration = errors.New("expiration must be positive")
+
+// KeyMigration is what ScanAndMigrate does with a key. The zero value leaves it untouched.
+type KeyMigration struct {
+	NewKey string        // renames the key when set
+	TTL    time.Duration // sets the expiration when positive, after any rename
+}
+
+// KeyTransform decides the migration of a key found by ScanAndMigrate.
+type KeyTransform func(key string) KeyMigration
+
+// ScanAndMigrate walks the keys matching pattern with SCAN and applies the migration
+// returned by transform, one pipelined batch per SCAN page. Unlike KEYS, this does not
+// block the server on large namespaces. In cluster mode every master is scanned, and a
+// renamed key must hash to the same slot as the original.
+//
+// Renamed keys should not match pattern, or SCAN may return them again. A key that fails
+// to migrate does not stop the walk; the number of migrated keys is returned together
+// with the first failure.
+func (ra *RedisAdapter) ScanAndMigrate(
+	ctx context.Context,
+	pattern string,
+	transform KeyTransform,
+) (int, error) {
+	if ra.client == nil {
+		return 0, fmt.Errorf("%w (pattern=%q)", ErrRedisClientNotInitialized, pattern)
+	}
+
+	var (
+		migrated int
+		failed   int
+		firstErr error
+	)
+
+	err := ra.scanPages(ctx, pattern, func(keys []string) error {
+		pageMigrated, pageFailed, pageErr := ra.migrateKeys(ctx, keys, transform)
+
+		migrated += pageMigrated
+		failed += pageFailed
+
+		if firstErr == nil {
+			firstErr = pageErr
+		}
+
+		return nil
+	})
+	if err != nil {
+		return migrated, fmt.Errorf("%w (operation=scan, pattern=%q): %w", ErrRedisOperation, pattern, err)
+	}
+
+	if firstErr != nil {
+		return migrated, fmt.Errorf(
+			"%w (operation=migrate, pattern=%q, failed=%d): %w",
+			ErrRedisOperation,
+			pattern,
+			failed,
+			firstErr,
+		)
+	}
+
+	return migrated, nil
+}
+
+// ExpireMany sets the expiration of every key matching pattern and returns how many
+// keys were updated. Like ScanAndMigrate, it walks the keys with SCAN.
+func (ra *RedisAdapter) ExpireMany(
+	ctx context.Context,
+	pattern string,
+	expiration time.Duration,
+) (int, error) {
+	if expiration <= 0 {
+		return 0, fmt.Errorf("%w (expiration=%s)", ErrInvalidExpiration, expiration)
+	}
+
+	return ra.ScanAndMigrate(ctx, pattern, func(string) KeyMigration {
+		return KeyMigration{NewKey: "", TTL: expiration}
+	})
+}
+
+// scanPages calls fn with every page of keys matching pattern. In cluster mode the
+// masters are scanned concurrently, but fn is never called concurrently.
+func (ra *RedisAdapter) scanPages(
+	ctx context.Context,
+	pattern string,
+	fn func(keys []string) error,
+) error {
+	clusterClient, isCluster := ra.client.(*redis.ClusterClient)
+	if !isCluster {
+		return scanNodePages(ctx, ra.client, pattern, fn)
+	}
+
+	var mu sync.Mutex
+
+	return clusterClient.ForEachMaster(ctx, func(ctx context.Context, master *redis.Client) error { //nolint:wrapcheck
+		return scanNodePages(ctx, master, pattern, func(keys []string) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			return fn(keys)
+		})
+	})
+}
+
+// migrateKeys applies the migrations of one page in a single pipeline and reports how
+// many keys were migrated and how many failed, with the first failure.
+func (ra *RedisAdapter) migrateKeys(
+	ctx context.Context,
+	keys []string,
+	transform KeyTransform,
+) (int, int, error) {
+	commands := make([][]redis.Cmder, 0, len(keys))
+
+	_, _ = ra.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			migration := transform(key)
+			target := key
+
+			var keyCommands []redis.Cmder
+
+			if migration.NewKey != "" && migration.NewKey != key {
+				keyCommands = append(keyCommands, pipe.Rename(ctx, key, migration.NewKey))
+				target = migration.NewKey
+			}
+
+			if migration.TTL > 0 {
+				keyCommands = append(keyCommands, pipe.PExpire(ctx, target, migration.TTL))
+			}
+
+			if len(keyCommands) > 0 {
+				commands = append(commands, keyCommands)
+			}
+		}
+
+		return nil
+	})
+
+	var (
+		migrated int
+		failed   int
+		firstErr error
+	)
+
+	for _, keyCommands := range commands {
+		var keyErr error
+
+		for _, cmd := range keyCommands {
+			if keyErr = cmd.Err(); keyErr != nil {
+				break
+			}
+		}
+
+		if keyErr != nil {
+			failed++
+
+			if firstErr == nil {
+				firstErr = keyErr
+			}
+
+			continue
+		}
+
+		migrated++
+	}
+
+	return migrated, failed, firstErr
+}
+
+func scanNodePages(
+	ctx context.Context,
+	client redis.Cmdable,
+	pattern string,
+	fn func(keys []string) error,
+) error {
+	var cursor uint64
+
+	for {
+		keys, next, err := client.Scan(ctx, cursor, pattern, defaultScanBatchSize).Result()
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+
+		cursor = next
+	}
+}
@@ -0,0 +1,108 @@
+package connfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrScriptAlreadyRegistered = errors.New("script is already registered")
+	ErrScriptNotRegistered     = errors.New("script is not registered")
+)
+
+// ScriptManager runs named Lua scripts on Redis. Scripts are sent by their SHA1 digest
+// with EVALSHA; when the server does not know a script yet (NOSCRIPT, e.g. after a
+// restart or failover), it is sent once in full and cached by the server again.
+type ScriptManager struct {
+	adapter *RedisAdapter
+	scripts map[string]*redis.Script
+	mu      sync.RWMutex
+}
+
+// NewScriptManager creates a script manager that runs scripts on the adapter's client.
+func NewScriptManager(adapter *RedisAdapter) *ScriptManager {
+	return &ScriptManager{
+		adapter: adapter,
+		scripts: make(map[string]*redis.Script),
+		mu:      sync.RWMutex{},
+	}
+}
+
+// Register adds a script under a name. Register scripts at startup, then call Load.
+func (m *ScriptManager) Register(name string, source string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.scripts[name]; exists {
+		return fmt.Errorf("%w (name=%q)", ErrScriptAlreadyRegistered, name)
+	}
+
+	m.scripts[name] = redis.NewScript(source)
+
+	return nil
+}
+
+// Load sends every registered script to the server with SCRIPT LOAD, so the first runs
+// do not need the NOSCRIPT fallback. In cluster mode the scripts are loaded on every master.
+func (m *ScriptManager) Load(ctx context.Context) error {
+	if m.adapter.client == nil {
+		return fmt.Errorf("%w (operation=script load)", ErrRedisClientNotInitialized)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, name := range slices.Sorted(maps.Keys(m.scripts)) {
+		if err := m.scripts[name].Load(ctx, m.adapter.client).Err(); err != nil {
+			return fmt.Errorf("%w (operation=script load, name=%q): %w", ErrRedisOperation, name, err)
+		}
+	}
+
+	return nil
+}
+
+// Run executes a registered script. A script that returns nil yields a nil result
+// without an error.
+func (m *ScriptManager) Run(
+	ctx context.Context,
+	name string,
+	keys []string,
+	args ...any,
+) (any, error) {
+	m.mu.RLock()
+	script, exists := m.scripts[name]
+	m.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("%w (name=%q)", ErrScriptNotRegistered, name)
+	}
+
+	if m.adapter.client == nil {
+		return nil, fmt.Errorf("%w (script=%q)", ErrRedisClientNotInitialized, name)
+	}
+
+	result, err := script.Run(ctx, m.adapter.client, keys, args...).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("%w (operation=evalsha, script=%q): %w", ErrRedisOperation, name, err)
+	}
+
+	return result, nil
+}
+
+// Names returns the names of the registered scripts in sorted order.
+func (m *ScriptManager) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return slices.Sorted(maps.Keys(m.scripts))
+}
@@ -0,0 +1,169 @@
+package connfx_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisAdapter_BuildConfig(t *testing.T) {
+	t.Parallel()
+
+	factory := connfx.NewRedisConnectionFactory("redis")
+
+	t.Run("standalone dsn", func(t *testing.T) {
+		t.Parallel()
+
+		config := factory.BuildRedisConfig(&connfx.ConfigTarget{ //nolint:exhaustruct
+			Protocol: "redis",
+			DSN:      "rediss://:secret@cache.example.com:6380/2",
+		})
+
+		assert.Equal(t, connfx.RedisModeStandalone, config.Mode)
+		assert.Equal(t, "cache.example.com:6380", config.Address)
+		assert.Equal(t, "secret", config.Password)
+		assert.Equal(t, 2, config.DB)
+		assert.True(t, config.TLSEnabled)
+	})
+
+	t.Run("cluster dsn", func(t *testing.T) {
+		t.Parallel()
+
+		config := factory.BuildRedisConfig(&connfx.ConfigTarget{ //nolint:exhaustruct
+			Protocol: "redis",
+			DSN:      "rediss+cluster://:secret@node-1:7000,node-2:7001,node-3",
+		})
+
+		assert.Equal(t, connfx.RedisModeCluster, config.Mode)
+		assert.Equal(t, []string{"node-1:7000", "node-2:7001", "node-3:6379"}, config.ClusterAddresses)
+		assert.Equal(t, "secret", config.Password)
+		assert.True(t, config.TLSEnabled)
+	})
+
+	t.Run("cluster addresses property", func(t *testing.T) {
+		t.Parallel()
+
+		config := factory.BuildRedisConfig(&connfx.ConfigTarget{ //nolint:exhaustruct
+			Protocol: "redis",
+			Properties: map[string]any{
+				"cluster_addresses": []string{"10.0.0.1:6379", "10.0.0.2:6379"},
+			},
+		})
+
+		assert.Equal(t, connfx.RedisModeCluster, config.Mode)
+		assert.Equal(t, []string{"10.0.0.1:6379", "10.0.0.2:6379"}, config.ClusterAddresses)
+	})
+}
+
+func TestRedisAdapter_BuildSentinelConfig(t *testing.T) {
+	t.Parallel()
+
+	factory := connfx.NewRedisConnectionFactory("redis")
+
+	t.Run("sentinel dsn", func(t *testing.T) {
+		t.Parallel()
+
+		config := factory.BuildRedisConfig(&connfx.ConfigTarget{ //nolint:exhaustruct
+			Protocol: "redis",
+			DSN:      "redis+sentinel://:secret@sentinel-1,sentinel-2:26380/aya-master/3",
+			Properties: map[string]any{
+				"sentinel_password": "sentinel-secret",
+			},
+		})
+
+		assert.Equal(t, connfx.RedisModeSentinel, config.Mode)
+		assert.Equal(t, []string{"sentinel-1:26379", "sentinel-2:26380"}, config.SentinelAddresses)
+		assert.Equal(t, "aya-master", config.MasterName)
+		assert.Equal(t, 3, config.DB)
+		assert.Equal(t, "secret", config.Password)
+		assert.Equal(t, "sentinel-secret", config.SentinelPassword)
+	})
+
+	t.Run("missing master name", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := factory.CreateConnection(t.Context(), &connfx.ConfigTarget{ //nolint:exhaustruct
+			Protocol: "redis",
+			Properties: map[string]any{
+				"sentinel_addresses": []string{"127.0.0.1:1"},
+			},
+		})
+		require.ErrorIs(t, err, connfx.ErrRedisSentinelMisconfigured)
+	})
+}
+
+func TestRedisAdapter_PipelineRequiresClient(t *testing.T) {
+	t.Parallel()
+
+	adapter := connfx.NewRedisConnection("redis", &connfx.RedisConfig{}).GetAdapter() //nolint:exhaustruct
+
+	queue := func(pipe connfx.Pipeliner) error {
+		pipe.Set(t.Context(), "key", "value", 0)
+
+		return nil
+	}
+
+	require.ErrorIs(t, adapter.Pipeline(t.Context(), queue), connfx.ErrRedisClientNotInitialized)
+	require.ErrorIs(t, adapter.TxPipeline(t.Context(), queue), connfx.ErrRedisClientNotInitialized)
+}
+
+func TestRedisAdapter_SortedSetsRequireClient(t *testing.T) {
+	t.Parallel()
+
+	adapter := connfx.NewRedisConnection("redis", &connfx.RedisConfig{}).GetAdapter() //nolint:exhaustruct
+	ctx := t.Context()
+
+	err := adapter.ZAdd(ctx, "trending", connfx.ScoredMember{Member: "story-1", Score: 1})
+	require.ErrorIs(t, err, connfx.ErrRedisClientNotInitialized)
+
+	_, err = adapter.ZIncrBy(ctx, "trending", 1, "story-1")
+	require.ErrorIs(t, err, connfx.ErrRedisClientNotInitialized)
+
+	_, err = adapter.ZRevRangeByScore(ctx, "trending", "-inf", "+inf", 0, 10)
+	require.ErrorIs(t, err, connfx.ErrRedisClientNotInitialized)
+}
+
+func TestScriptManager(t *testing.T) {
+	t.Parallel()
+
+	adapter := connfx.NewRedisConnection("redis", &connfx.RedisConfig{}).GetAdapter() //nolint:exhaustruct
+	scripts := connfx.NewScriptManager(adapter)
+	ctx := t.Context()
+
+	require.NoError(t, scripts.Register("incr", "return redis.call('INCR', KEYS[1])"))
+	require.NoError(t, scripts.Register("touch", "return redis.call('EXPIRE', KEYS[1], ARGV[1])"))
+
+	err := scripts.Register("touch", "return 0")
+	require.ErrorIs(t, err, connfx.ErrScriptAlreadyRegistered)
+
+	assert.Equal(t, []string{"incr", "touch"}, scripts.Names())
+
+	_, err = scripts.Run(ctx, "missing", nil)
+	require.ErrorIs(t, err, connfx.ErrScriptNotRegistered)
+
+	_, err = scripts.Run(ctx, "touch", []string{"key"}, 60)
+	require.ErrorIs(t, err, connfx.ErrRedisClientNotInitialized)
+
+	require.ErrorIs(t, scripts.Load(ctx), connfx.ErrRedisClientNotInitialized)
+}
+
+func TestRedisAdapter_ScanAndMigrate(t *testing.T) {
+	t.Parallel()
+
+	adapter := connfx.NewRedisConnection("redis", &connfx.RedisConfig{}).GetAdapter() //nolint:exhaustruct
+	ctx := t.Context()
+
+	_, err := adapter.ExpireMany(ctx, "profiles:*", 0)
+	require.ErrorIs(t, err, connfx.ErrInvalidExpiration)
+
+	_, err = adapter.ExpireMany(ctx, "profiles:*", time.Hour)
+	require.ErrorIs(t, err, connfx.ErrRedisClientNotInitialized)
+
+	_, err = adapter.ScanAndMigrate(ctx, "profiles:*", func(key string) connfx.KeyMigration {
+		return connfx.KeyMigration{NewKey: "v2:" + key, TTL: 0}
+	})
+	require.ErrorIs(t, err, connfx.ErrRedisClientNotInitialized)
+}
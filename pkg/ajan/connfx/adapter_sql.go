@@ -3,8 +3,11 @@ package connfx
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"net/url"
+	"strings"
 	"sync/atomic"
 	"time"
 )
@@ -16,16 +19,45 @@ var (
 	ErrUnsupportedSQLProtocol    = errors.New("unsupported SQL protocol")
 	ErrFailedToCloseSQLDB        = errors.New("failed to close SQL database")
 	ErrSQLConnectionNil          = errors.New("SQL connection is nil")
+	ErrFailedToQuerySQL          = errors.New("failed to run SQL query")
+	ErrFailedToExecuteSQL        = errors.New("failed to execute SQL command")
+	ErrFailedToVerifySQLCreds    = errors.New("failed to verify rotated SQL credentials")
 )
 
 // SQLConnection represents a SQL database connection.
 type SQLConnection struct {
 	lastHealth time.Time
 	db         *sql.DB
+	connector  *sqlConnector
 	protocol   string
 	state      int32 // atomic field for connection state
 }
 
+// sqlConnector dials with the DSN that is current at dial time, so rotated credentials
+// apply to connections the pool opens afterwards while open ones finish their work.
+type sqlConnector struct {
+	driver  driver.Driver
+	current atomic.Pointer[sqlConnectorTarget]
+}
+
+type sqlConnectorTarget struct {
+	connector driver.Connector // nil when the driver does not implement driver.DriverContext
+	dsn       string
+}
+
+// sqlExecuteResult adapts sql.Result to ExecuteResult.
+type sqlExecuteResult struct {
+	result sql.Result
+}
+
+func (r sqlExecuteResult) RowsAffected() (int64, error) {
+	return r.result.RowsAffected() //nolint:wrapcheck
+}
+
+func (r sqlExecuteResult) LastInsertID() (int64, error) {
+	return r.result.LastInsertId() //nolint:wrapcheck
+}
+
 // SQLConnectionFactory creates SQL connections.
 type SQLConnectionFactory struct {
 	protocol string
@@ -42,7 +74,19 @@ func (f *SQLConnectionFactory) CreateConnection( //nolint:ireturn
 	ctx context.Context,
 	config *ConfigTarget,
 ) (Connection, error) {
-	db, err := sql.Open(f.protocol, config.DSN) //nolint:varnamelen
+	dsn := config.DSN
+	if f.protocol == "postgres" {
+		var err error
+
+		dsn, err = applyPostgresTLSFiles(dsn, config)
+		if err != nil {
+			return nil, fmt.Errorf("%w (protocol=%q): %w", ErrFailedToOpenSQLConnection, f.protocol, err)
+		}
+	}
+
+	// sql.Open resolves the registered driver; the pool itself dials through a connector
+	// whose DSN can be swapped by RotateCredentials.
+	base, err := sql.Open(f.protocol, dsn)
 	if err != nil {
 		return nil, fmt.Errorf(
 			"%w (protocol=%q, dsn=%q): %w",
@@ -53,6 +97,21 @@ func (f *SQLConnectionFactory) CreateConnection( //nolint:ireturn
 		)
 	}
 
+	connector := &sqlConnector{driver: base.Driver(), current: atomic.Pointer[sqlConnectorTarget]{}}
+	_ = base.Close()
+
+	if err := connector.setDSN(dsn); err != nil {
+		return nil, fmt.Errorf(
+			"%w (protocol=%q, dsn=%q): %w",
+			ErrFailedToOpenSQLConnection,
+			f.protocol,
+			config.DSN,
+			err,
+		)
+	}
+
+	db := sql.OpenDB(connector) //nolint:varnamelen
+
 	// Configure connection pool if timeout is specified
 	if config.Timeout > 0 {
 		db.SetConnMaxLifetime(config.Timeout)
@@ -68,6 +127,7 @@ func (f *SQLConnectionFactory) CreateConnection( //nolint:ireturn
 	conn := &SQLConnection{
 		protocol:   f.protocol,
 		db:         db,
+		connector:  connector,
 		state:      int32(ConnectionStateConnected),
 		lastHealth: time.Time{},
 	}
@@ -171,6 +231,73 @@ func (c *SQLConnection) Stats() sql.DBStats {
 	return c.db.Stats()
 }
 
+// PoolStats returns the connection pool usage for telemetry.
+func (c *SQLConnection) PoolStats() PoolStats {
+	stats := c.db.Stats()
+
+	return PoolStats{
+		Total:        int64(stats.OpenConnections),
+		Idle:         int64(stats.Idle),
+		InUse:        int64(stats.InUse),
+		WaitCount:    stats.WaitCount,
+		WaitDuration: stats.WaitDuration,
+	}
+}
+
+// RotateCredentials switches the pool to new credentials. A connection is dialed with
+// them first; if that fails, the current credentials stay in place. Pooled connections
+// keep serving in-flight queries and are replaced as they expire (see ConfigTarget.Timeout).
+func (c *SQLConnection) RotateCredentials(ctx context.Context, creds Credentials) error {
+	dsn, err := applyDSNCredentials(c.connector.current.Load().dsn, creds)
+	if err != nil {
+		return err
+	}
+
+	candidate := &sqlConnector{driver: c.connector.driver, current: atomic.Pointer[sqlConnectorTarget]{}}
+	if err := candidate.setDSN(dsn); err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToVerifySQLCreds, err)
+	}
+
+	probe, err := candidate.Connect(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToVerifySQLCreds, err)
+	}
+
+	_ = probe.Close()
+
+	c.connector.current.Store(candidate.current.Load())
+
+	return nil
+}
+
+// QueryRepository interface implementation.
+
+func (c *SQLConnection) Query( //nolint:ireturn
+	ctx context.Context,
+	query string,
+	args ...any,
+) (QueryResult, error) {
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToQuerySQL, err)
+	}
+
+	return rows, nil
+}
+
+func (c *SQLConnection) Execute( //nolint:ireturn
+	ctx context.Context,
+	command string,
+	args ...any,
+) (ExecuteResult, error) {
+	result, err := c.db.ExecContext(ctx, command, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToExecuteSQL, err)
+	}
+
+	return sqlExecuteResult{result: result}, nil
+}
+
 func (c *SQLConnection) determineConnectionState(stats sql.DBStats, status *HealthStatus) {
 	switch {
 	case stats.OpenConnections == 0:
@@ -208,3 +335,104 @@ func (c *SQLConnection) determineConnectionState(stats sql.DBStats, status *Heal
 			c.protocol, stats.OpenConnections, stats.InUse, stats.Idle)
 	}
 }
+
+// driver.Connector implementation for credential rotation.
+
+func (c *sqlConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	target := c.current.Load()
+
+	if target.connector != nil {
+		return target.connector.Connect(ctx) //nolint:wrapcheck
+	}
+
+	return c.driver.Open(target.dsn) //nolint:wrapcheck
+}
+
+func (c *sqlConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+func (c *sqlConnector) setDSN(dsn string) error {
+	target := &sqlConnectorTarget{connector: nil, dsn: dsn}
+
+	if driverCtx, ok := c.driver.(driver.DriverContext); ok {
+		connector, err := driverCtx.OpenConnector(dsn)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		target.connector = connector
+	}
+
+	c.current.Store(target)
+
+	return nil
+}
+
+// applyPostgresTLSFiles adds the target's certificate files to a postgres DSN unless
+// the DSN already sets them. The driver reads the files on every dial, so rotated
+// certificates are picked up by new pool connections without a reload.
+func applyPostgresTLSFiles(dsn string, config *ConfigTarget) (string, error) {
+	if !config.TLS && !config.TLSSkipVerify &&
+		config.CertFile == "" && config.KeyFile == "" && config.CAFile == "" {
+		return dsn, nil
+	}
+
+	params := [][2]string{
+		{"sslcert", config.CertFile},
+		{"sslkey", config.KeyFile},
+		{"sslrootcert", config.CAFile},
+	}
+
+	switch {
+	case config.TLSSkipVerify:
+		params = append(params, [2]string{"sslmode", "require"})
+	case config.TLS && config.CAFile != "":
+		params = append(params, [2]string{"sslmode", "verify-full"})
+	case config.TLS:
+		params = append(params, [2]string{"sslmode", "require"})
+	}
+
+	if strings.Contains(dsn, "://") {
+		parsed, err := url.Parse(dsn)
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrInvalidDSN, err)
+		}
+
+		query := parsed.Query()
+
+		for _, param := range params {
+			if param[1] != "" && !query.Has(param[0]) {
+				query.Set(param[0], param[1])
+			}
+		}
+
+		parsed.RawQuery = query.Encode()
+
+		return parsed.String(), nil
+	}
+
+	fields := strings.Fields(dsn)
+
+	for _, param := range params {
+		if param[1] == "" {
+			continue
+		}
+
+		present := false
+
+		for _, field := range fields {
+			if key, _, _ := strings.Cut(field, "="); key == param[0] {
+				present = true
+
+				break
+			}
+		}
+
+		if !present {
+			fields = append(fields, param[0]+"="+quoteDSNValue(param[1]))
+		}
+	}
+
+	return strings.Join(fields, " "), nil
+}
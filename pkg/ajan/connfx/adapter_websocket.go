@@ -0,0 +1,606 @@
+package connfx
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	DefaultWebSocketHandshakeTimeout = 10 * time.Second
+	DefaultWebSocketWriteTimeout     = 10 * time.Second
+	DefaultWebSocketPingInterval     = 30 * time.Second
+	DefaultWebSocketPongTimeout      = 10 * time.Second
+	DefaultWebSocketMinBackoff       = 500 * time.Millisecond
+	DefaultWebSocketMaxBackoff       = 30 * time.Second
+	DefaultWebSocketBufferSize       = 100
+
+	defaultWebSocketPort       = 80
+	defaultWebSocketSecurePort = 443
+)
+
+// WebSocketMessageType is the frame type of a WebSocket data message.
+type WebSocketMessageType int
+
+const (
+	// WebSocketMessageText is a UTF-8 encoded text message.
+	WebSocketMessageText WebSocketMessageType = websocket.TextMessage
+	// WebSocketMessageBinary is a binary message.
+	WebSocketMessageBinary WebSocketMessageType = websocket.BinaryMessage
+)
+
+var (
+	ErrWebSocketConnectionFailed     = errors.New("failed to connect to WebSocket server")
+	ErrFailedToCreateWebSocketClient = errors.New("failed to create WebSocket client")
+	ErrWebSocketNotConnected         = errors.New("WebSocket is not connected")
+	ErrWebSocketOperation            = errors.New("WebSocket operation failed")
+	ErrWebSocketInvalidURL           = errors.New("invalid WebSocket URL")
+)
+
+// WebSocketConfig holds WebSocket client configuration options.
+type WebSocketConfig struct {
+	Headers               map[string]string
+	URL                   string
+	Subprotocols          []string
+	HandshakeTimeout      time.Duration
+	WriteTimeout          time.Duration
+	PingInterval          time.Duration // zero disables keepalive pings
+	PongTimeout           time.Duration
+	MinBackoff            time.Duration
+	MaxBackoff            time.Duration
+	ReadLimit             int64 // maximum message size in bytes, zero means unlimited
+	BufferSize            int   // capacity of the incoming message channel
+	TLSInsecureSkipVerify bool
+}
+
+// NewDefaultWebSocketConfig creates a WebSocket configuration with sensible defaults.
+func NewDefaultWebSocketConfig() *WebSocketConfig {
+	return &WebSocketConfig{
+		Headers:               map[string]string{},
+		URL:                   "",
+		Subprotocols:          nil,
+		HandshakeTimeout:      DefaultWebSocketHandshakeTimeout,
+		WriteTimeout:          DefaultWebSocketWriteTimeout,
+		PingInterval:          DefaultWebSocketPingInterval,
+		PongTimeout:           DefaultWebSocketPongTimeout,
+		MinBackoff:            DefaultWebSocketMinBackoff,
+		MaxBackoff:            DefaultWebSocketMaxBackoff,
+		ReadLimit:             0,
+		BufferSize:            DefaultWebSocketBufferSize,
+		TLSInsecureSkipVerify: false,
+	}
+}
+
+// WebSocketMessage is a single data message received from or sent to the server.
+type WebSocketMessage struct {
+	Data []byte
+	Type WebSocketMessageType
+}
+
+// WebSocketAdapter exposes a persistent WebSocket connection as a message stream.
+// Incoming messages from every underlying connection are delivered to a single
+// channel, so consumers are unaffected by reconnects.
+type WebSocketAdapter struct {
+	conn      *websocket.Conn
+	config    *WebSocketConfig
+	dialer    *websocket.Dialer
+	messages  chan WebSocketMessage
+	onConnect func(ctx context.Context) error
+	mu        sync.RWMutex
+	writeMu   sync.Mutex // gorilla/websocket supports a single concurrent writer
+}
+
+// WebSocketConnection implements the connfx.Connection interface for WebSocket clients.
+type WebSocketConnection struct {
+	adapter  *WebSocketAdapter
+	cancel   context.CancelFunc
+	done     chan struct{}
+	protocol string
+	state    int32 // atomic field for connection state
+}
+
+// NewWebSocketConnection creates a new WebSocket connection.
+func NewWebSocketConnection(protocol string, config *WebSocketConfig) *WebSocketConnection {
+	if config == nil {
+		config = NewDefaultWebSocketConfig()
+	}
+
+	adapter := &WebSocketAdapter{
+		conn:   nil, // Will be initialized on connect
+		config: config,
+		dialer: &websocket.Dialer{ //nolint:exhaustruct
+			Proxy:            http.ProxyFromEnvironment,
+			HandshakeTimeout: config.HandshakeTimeout,
+			Subprotocols:     config.Subprotocols,
+			TLSClientConfig: &tls.Config{ //nolint:exhaustruct
+				InsecureSkipVerify: config.TLSInsecureSkipVerify, //nolint:gosec
+			},
+		},
+		messages:  make(chan WebSocketMessage, config.BufferSize),
+		onConnect: nil,
+		mu:        sync.RWMutex{},
+		writeMu:   sync.Mutex{},
+	}
+
+	return &WebSocketConnection{
+		adapter:  adapter,
+		cancel:   nil,
+		done:     nil,
+		protocol: protocol,
+		state:    int32(ConnectionStateNotInitialized),
+	}
+}
+
+// Connection interface implementation.
+func (wc *WebSocketConnection) GetBehaviors() []ConnectionBehavior {
+	return []ConnectionBehavior{
+		ConnectionBehaviorStateful,
+		ConnectionBehaviorStreaming,
+	}
+}
+
+func (wc *WebSocketConnection) GetCapabilities() []ConnectionCapability {
+	return []ConnectionCapability{
+		ConnectionCapabilityStream,
+	}
+}
+
+func (wc *WebSocketConnection) GetProtocol() string {
+	return wc.protocol
+}
+
+func (wc *WebSocketConnection) GetState() ConnectionState {
+	return ConnectionState(atomic.LoadInt32(&wc.state))
+}
+
+func (wc *WebSocketConnection) HealthCheck(ctx context.Context) *HealthStatus {
+	start := time.Now()
+
+	status := &HealthStatus{
+		Timestamp: start,
+		State:     wc.GetState(),
+		Error:     nil,
+		Message:   "",
+		Latency:   0,
+	}
+
+	if status.State == ConnectionStateNotInitialized || status.State == ConnectionStateDisconnected {
+		status.Error = ErrWebSocketNotConnected
+		status.Message = "WebSocket connection is not open"
+
+		return status
+	}
+
+	err := wc.adapter.ping()
+	status.Latency = time.Since(start)
+
+	if err != nil {
+		// The run loop keeps reconnecting in the background, so the connection is
+		// reconnecting rather than permanently broken.
+		atomic.StoreInt32(&wc.state, int32(ConnectionStateReconnecting))
+		status.State = ConnectionStateReconnecting
+		status.Error = err
+		status.Message = fmt.Sprintf("WebSocket server not reachable: %v", err)
+
+		return status
+	}
+
+	atomic.StoreInt32(&wc.state, int32(ConnectionStateReady))
+	status.State = ConnectionStateReady
+	status.Message = "WebSocket connection is ready (url=" + wc.adapter.config.URL + ")"
+
+	return status
+}
+
+func (wc *WebSocketConnection) Close(ctx context.Context) error {
+	atomic.StoreInt32(&wc.state, int32(ConnectionStateDisconnected))
+
+	if wc.cancel == nil {
+		return nil
+	}
+
+	wc.cancel()
+	err := wc.adapter.closeConn(true)
+
+	// Wait for the run loop so the message channel can be closed safely.
+	select {
+	case <-wc.done:
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck
+	}
+
+	return err
+}
+
+func (wc *WebSocketConnection) GetRawConnection() any {
+	return wc.adapter
+}
+
+// connect dials the server once so that configuration errors surface immediately,
+// then starts the run loop that reads messages and reconnects with exponential
+// backoff for the lifetime of the connection.
+func (wc *WebSocketConnection) connect(ctx context.Context) error {
+	conn, err := wc.adapter.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	wc.adapter.setConn(conn)
+	atomic.StoreInt32(&wc.state, int32(ConnectionStateReady))
+
+	// The run loop must outlive the context of the call that created it.
+	runCtx, cancel := context.WithCancel(context.Background())
+
+	wc.cancel = cancel
+	wc.done = make(chan struct{})
+
+	go wc.run(runCtx, conn)
+
+	return nil
+}
+
+func (wc *WebSocketConnection) run(ctx context.Context, conn *websocket.Conn) {
+	defer close(wc.done)
+	defer close(wc.adapter.messages)
+
+	for {
+		wc.adapter.readLoop(ctx, conn)
+		_ = wc.adapter.closeConn(false)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		atomic.StoreInt32(&wc.state, int32(ConnectionStateReconnecting))
+
+		conn = wc.reconnect(ctx)
+		if conn == nil {
+			return
+		}
+
+		atomic.StoreInt32(&wc.state, int32(ConnectionStateReady))
+	}
+}
+
+// reconnect dials until it succeeds or the context is cancelled, doubling the
+// delay between attempts up to MaxBackoff.
+func (wc *WebSocketConnection) reconnect(ctx context.Context) *websocket.Conn {
+	backoff := wc.adapter.config.MinBackoff
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+
+		conn, err := wc.adapter.dial(ctx)
+		if err == nil {
+			wc.adapter.setConn(conn)
+			wc.adapter.runOnConnect(ctx)
+
+			return conn
+		}
+
+		backoff = min(backoff*2, wc.adapter.config.MaxBackoff)
+	}
+}
+
+// Messages returns the channel of incoming data messages. The channel stays open
+// across reconnects and is closed when the connection is closed.
+func (wa *WebSocketAdapter) Messages() <-chan WebSocketMessage {
+	return wa.messages
+}
+
+// Send writes a single message. It fails with ErrWebSocketNotConnected while the
+// connection is being re-established.
+func (wa *WebSocketAdapter) Send(ctx context.Context, message WebSocketMessage) error {
+	wa.mu.RLock()
+	conn := wa.conn
+	wa.mu.RUnlock()
+
+	if conn == nil {
+		return ErrWebSocketNotConnected
+	}
+
+	wa.writeMu.Lock()
+	defer wa.writeMu.Unlock()
+
+	deadline := time.Now().Add(wa.config.WriteTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	_ = conn.SetWriteDeadline(deadline)
+
+	if err := conn.WriteMessage(int(message.Type), message.Data); err != nil {
+		return fmt.Errorf("%w (operation=send): %w", ErrWebSocketOperation, err)
+	}
+
+	return nil
+}
+
+// SendText writes a text message.
+func (wa *WebSocketAdapter) SendText(ctx context.Context, text string) error {
+	return wa.Send(ctx, WebSocketMessage{Data: []byte(text), Type: WebSocketMessageText})
+}
+
+// OnConnect registers a hook that runs after every successful reconnect, e.g. to
+// re-send subscription requests. It does not run for the initial connection.
+func (wa *WebSocketAdapter) OnConnect(hook func(ctx context.Context) error) {
+	wa.mu.Lock()
+	defer wa.mu.Unlock()
+
+	wa.onConnect = hook
+}
+
+func (wa *WebSocketAdapter) dial(ctx context.Context) (*websocket.Conn, error) {
+	header := http.Header{}
+	for name, value := range wa.config.Headers {
+		header.Set(name, value)
+	}
+
+	conn, resp, err := wa.dialer.DialContext(ctx, wa.config.URL, header)
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w (url=%q): %w", ErrWebSocketConnectionFailed, wa.config.URL, err)
+	}
+
+	if wa.config.ReadLimit > 0 {
+		conn.SetReadLimit(wa.config.ReadLimit)
+	}
+
+	return conn, nil
+}
+
+func (wa *WebSocketAdapter) setConn(conn *websocket.Conn) {
+	wa.mu.Lock()
+	defer wa.mu.Unlock()
+
+	wa.conn = conn
+}
+
+func (wa *WebSocketAdapter) runOnConnect(ctx context.Context) {
+	wa.mu.RLock()
+	hook := wa.onConnect
+	wa.mu.RUnlock()
+
+	if hook != nil {
+		// A failing hook is retried on the next reconnect; the connection itself is usable.
+		_ = hook(ctx)
+	}
+}
+
+// closeConn closes the current underlying connection, optionally sending a close
+// frame to the server first.
+func (wa *WebSocketAdapter) closeConn(graceful bool) error {
+	wa.mu.Lock()
+	conn := wa.conn
+	wa.conn = nil
+	wa.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+
+	if graceful {
+		wa.writeMu.Lock()
+		_ = conn.WriteControl(
+			websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+			time.Now().Add(wa.config.WriteTimeout),
+		)
+		wa.writeMu.Unlock()
+	}
+
+	if err := conn.Close(); err != nil {
+		return fmt.Errorf("%w (operation=close): %w", ErrWebSocketOperation, err)
+	}
+
+	return nil
+}
+
+func (wa *WebSocketAdapter) ping() error {
+	wa.mu.RLock()
+	conn := wa.conn
+	wa.mu.RUnlock()
+
+	if conn == nil {
+		return ErrWebSocketNotConnected
+	}
+
+	err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wa.config.WriteTimeout))
+	if err != nil {
+		return fmt.Errorf("%w (operation=ping): %w", ErrWebSocketOperation, err)
+	}
+
+	return nil
+}
+
+// readLoop delivers messages from a single underlying connection until it fails.
+// When keepalive pings are enabled, a connection that does not answer within
+// PingInterval+PongTimeout is considered dead.
+func (wa *WebSocketAdapter) readLoop(ctx context.Context, conn *websocket.Conn) {
+	loopCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if wa.config.PingInterval > 0 {
+		readTimeout := wa.config.PingInterval + wa.config.PongTimeout
+
+		_ = conn.SetReadDeadline(time.Now().Add(readTimeout))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(readTimeout))
+		})
+
+		go wa.keepalive(loopCtx)
+	}
+
+	// Unblock ReadMessage when the connection is closed from outside.
+	go func() {
+		<-loopCtx.Done()
+		_ = conn.SetReadDeadline(time.Now())
+	}()
+
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		select {
+		case wa.messages <- WebSocketMessage{Data: data, Type: WebSocketMessageType(messageType)}:
+		case <-loopCtx.Done():
+			return
+		}
+	}
+}
+
+func (wa *WebSocketAdapter) keepalive(ctx context.Context) {
+	ticker := time.NewTicker(wa.config.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = wa.ping()
+		}
+	}
+}
+
+// WebSocketConnectionFactory creates WebSocket client connections.
+type WebSocketConnectionFactory struct {
+	protocol string
+}
+
+// NewWebSocketConnectionFactory creates a new WebSocket connection factory for a specific protocol.
+func NewWebSocketConnectionFactory(protocol string) *WebSocketConnectionFactory {
+	return &WebSocketConnectionFactory{
+		protocol: protocol,
+	}
+}
+
+func (f *WebSocketConnectionFactory) CreateConnection( //nolint:ireturn
+	ctx context.Context,
+	config *ConfigTarget,
+) (Connection, error) {
+	wsConfig, err := f.BuildWebSocketConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToCreateWebSocketClient, err)
+	}
+
+	conn := NewWebSocketConnection(f.protocol, wsConfig)
+
+	if err := conn.connect(ctx); err != nil {
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (f *WebSocketConnectionFactory) GetProtocol() string {
+	return f.protocol
+}
+
+// BuildWebSocketConfig builds a WebSocket configuration from a connection target.
+// The DSN (or URL) must be a ws:// or wss:// URL.
+func (f *WebSocketConnectionFactory) BuildWebSocketConfig( //nolint:cyclop
+	config *ConfigTarget,
+) (*WebSocketConfig, error) {
+	wsConfig := NewDefaultWebSocketConfig()
+
+	switch {
+	case config.DSN != "":
+		wsConfig.URL = config.DSN
+	case config.URL != "":
+		wsConfig.URL = config.URL
+	case config.Host != "":
+		scheme, defaultPort := "ws", defaultWebSocketPort
+		if config.TLS {
+			scheme, defaultPort = "wss", defaultWebSocketSecurePort
+		}
+
+		wsConfig.URL = fmt.Sprintf("%s://%s:%d/", scheme, config.Host, getOrDefault(config.Port, defaultPort))
+	}
+
+	parsedURL, err := url.Parse(wsConfig.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%w (url=%q): %w", ErrInvalidURL, wsConfig.URL, err)
+	}
+
+	if parsedURL.Scheme != "ws" && parsedURL.Scheme != "wss" {
+		return nil, fmt.Errorf("%w (url=%q)", ErrWebSocketInvalidURL, wsConfig.URL)
+	}
+
+	wsConfig.TLSInsecureSkipVerify = config.TLSSkipVerify
+
+	if config.Timeout > 0 {
+		wsConfig.HandshakeTimeout = config.Timeout
+		wsConfig.WriteTimeout = config.Timeout
+	}
+
+	if config.Properties != nil {
+		f.configureFromProperties(wsConfig, config.Properties)
+	}
+
+	return wsConfig, nil
+}
+
+func (f *WebSocketConnectionFactory) configureFromProperties( //nolint:cyclop
+	wsConfig *WebSocketConfig,
+	properties map[string]any,
+) {
+	if headers, ok := properties["headers"].(map[string]any); ok {
+		for name, value := range headers {
+			if str, ok := value.(string); ok {
+				wsConfig.Headers[name] = str
+			}
+		}
+	}
+
+	if headers, ok := properties["headers"].(map[string]string); ok {
+		for name, value := range headers {
+			wsConfig.Headers[name] = value
+		}
+	}
+
+	if subprotocols, ok := properties["subprotocols"].([]string); ok {
+		wsConfig.Subprotocols = subprotocols
+	}
+
+	if pingInterval, ok := properties["ping_interval"].(time.Duration); ok {
+		wsConfig.PingInterval = pingInterval
+	}
+
+	if pongTimeout, ok := properties["pong_timeout"].(time.Duration); ok {
+		wsConfig.PongTimeout = pongTimeout
+	}
+
+	if minBackoff, ok := properties["min_backoff"].(time.Duration); ok && minBackoff > 0 {
+		wsConfig.MinBackoff = minBackoff
+	}
+
+	if maxBackoff, ok := properties["max_backoff"].(time.Duration); ok && maxBackoff > 0 {
+		wsConfig.MaxBackoff = maxBackoff
+	}
+
+	if readLimit, ok := properties["read_limit"].(int); ok {
+		wsConfig.ReadLimit = int64(readLimit)
+	}
+
+	if bufferSize, ok := properties["buffer_size"].(int); ok && bufferSize >= 0 {
+		wsConfig.BufferSize = bufferSize
+	}
+}
@@ -0,0 +1,207 @@
+package connfx_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startEchoWebSocketServer echoes every message back. The first connection is
+// dropped after its first echo when dropFirst is set, to exercise reconnects.
+func startEchoWebSocketServer(t *testing.T, dropFirst bool) (string, *atomic.Int32) {
+	t.Helper()
+
+	var connections atomic.Int32
+
+	upgrader := websocket.Upgrader{} //nolint:exhaustruct
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		count := connections.Add(1)
+
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			if err := conn.WriteMessage(messageType, data); err != nil {
+				return
+			}
+
+			if dropFirst && count == 1 {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http"), &connections
+}
+
+func receiveWebSocketMessage(t *testing.T, messages <-chan connfx.WebSocketMessage) string {
+	t.Helper()
+
+	select {
+	case message := <-messages:
+		return string(message.Data)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for WebSocket message")
+
+		return ""
+	}
+}
+
+func TestWebSocketAdapter_BuildConfig(t *testing.T) {
+	t.Parallel()
+
+	factory := connfx.NewWebSocketConnectionFactory("wss")
+	assert.Equal(t, "wss", factory.GetProtocol())
+
+	t.Run("url with properties", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := factory.BuildWebSocketConfig(&connfx.ConfigTarget{ //nolint:exhaustruct
+			Protocol: "wss",
+			URL:      "wss://jetstream.example.com/subscribe",
+			Timeout:  3 * time.Second,
+			Properties: map[string]any{
+				"headers":       map[string]any{"Authorization": "Bearer token"},
+				"subprotocols":  []string{"v1.firehose"},
+				"ping_interval": 15 * time.Second,
+				"max_backoff":   time.Minute,
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "wss://jetstream.example.com/subscribe", config.URL)
+		assert.Equal(t, "Bearer token", config.Headers["Authorization"])
+		assert.Equal(t, []string{"v1.firehose"}, config.Subprotocols)
+		assert.Equal(t, 3*time.Second, config.HandshakeTimeout)
+		assert.Equal(t, 15*time.Second, config.PingInterval)
+		assert.Equal(t, time.Minute, config.MaxBackoff)
+		assert.Equal(t, connfx.DefaultWebSocketMinBackoff, config.MinBackoff)
+	})
+
+	t.Run("host with tls", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := factory.BuildWebSocketConfig(&connfx.ConfigTarget{ //nolint:exhaustruct
+			Protocol: "wss",
+			Host:     "stream.example.com",
+			TLS:      true,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "wss://stream.example.com:443/", config.URL)
+	})
+
+	t.Run("invalid scheme", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := factory.BuildWebSocketConfig(&connfx.ConfigTarget{ //nolint:exhaustruct
+			Protocol: "ws",
+			URL:      "http://example.com/",
+		})
+		require.ErrorIs(t, err, connfx.ErrWebSocketInvalidURL)
+	})
+}
+
+func TestWebSocketAdapter_SendAndReceive(t *testing.T) {
+	t.Parallel()
+
+	serverURL, _ := startEchoWebSocketServer(t, false)
+
+	factory := connfx.NewWebSocketConnectionFactory("ws")
+
+	conn, err := factory.CreateConnection(t.Context(), &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "ws",
+		URL:      serverURL,
+		Timeout:  2 * time.Second,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = conn.Close(context.Background()) })
+
+	assert.Equal(t, connfx.ConnectionStateReady, conn.GetState())
+	assert.Contains(t, conn.GetCapabilities(), connfx.ConnectionCapabilityStream)
+
+	status := conn.HealthCheck(t.Context())
+	assert.Equal(t, connfx.ConnectionStateReady, status.State)
+
+	stream, ok := conn.GetRawConnection().(*connfx.WebSocketAdapter)
+	require.True(t, ok)
+
+	require.NoError(t, stream.SendText(t.Context(), "hello"))
+	assert.Equal(t, "hello", receiveWebSocketMessage(t, stream.Messages()))
+
+	require.NoError(t, conn.Close(t.Context()))
+
+	_, open := <-stream.Messages()
+	assert.False(t, open)
+	assert.ErrorIs(t, stream.SendText(t.Context(), "late"), connfx.ErrWebSocketNotConnected)
+}
+
+func TestWebSocketAdapter_Reconnect(t *testing.T) {
+	t.Parallel()
+
+	serverURL, connections := startEchoWebSocketServer(t, true)
+
+	factory := connfx.NewWebSocketConnectionFactory("ws")
+
+	conn, err := factory.CreateConnection(t.Context(), &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "ws",
+		URL:      serverURL,
+		Timeout:  2 * time.Second,
+		Properties: map[string]any{
+			"min_backoff": 10 * time.Millisecond,
+		},
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = conn.Close(context.Background()) })
+
+	stream, ok := conn.GetRawConnection().(*connfx.WebSocketAdapter)
+	require.True(t, ok)
+
+	// Re-subscribe after every reconnect, as a firehose consumer would.
+	stream.OnConnect(func(ctx context.Context) error {
+		return stream.SendText(ctx, "resubscribe")
+	})
+
+	require.NoError(t, stream.SendText(t.Context(), "subscribe"))
+	assert.Equal(t, "subscribe", receiveWebSocketMessage(t, stream.Messages()))
+
+	// The server drops the first connection; the hook runs on the new one.
+	assert.Equal(t, "resubscribe", receiveWebSocketMessage(t, stream.Messages()))
+	assert.Equal(t, int32(2), connections.Load())
+	assert.Equal(t, connfx.ConnectionStateReady, conn.GetState())
+}
+
+func TestWebSocketAdapter_Unreachable(t *testing.T) {
+	t.Parallel()
+
+	factory := connfx.NewWebSocketConnectionFactory("ws")
+
+	_, err := factory.CreateConnection(t.Context(), &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "ws",
+		Host:     "127.0.0.1",
+		Port:     1,
+		Timeout:  200 * time.Millisecond,
+	})
+	require.ErrorIs(t, err, connfx.ErrWebSocketConnectionFailed)
+}
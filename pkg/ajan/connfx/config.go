@@ -21,6 +21,11 @@ type Config struct {
 // ConfigTarget represents the configuration data for a connection.
 type ConfigTarget struct {
 	Properties map[string]any `conf:"properties"`
+	// Endpoints turns the target into a failover group. Each endpoint inherits the unset
+	// fields of this target; "primary" is preferred, the rest are ordered by name.
+	Endpoints map[string]ConfigTarget `conf:"endpoints"`
+	// Tags label the target, e.g. "tier": "critical" or "env": "staging,prod".
+	Tags map[string]string `conf:"tags"`
 
 	Protocol string `conf:"protocol"` // e.g., "postgres", "redis", "http"
 	DSN      string `conf:"dsn"`
@@ -29,12 +34,24 @@ type ConfigTarget struct {
 	CertFile string `conf:"cert_file"`
 	KeyFile  string `conf:"key_file"`
 	CAFile   string `conf:"ca_file"`
+	// Policy selects how a failover group routes: "failover" (default) or "round_robin".
+	Policy string `conf:"policy"`
+	// DependsOn lists comma-separated targets that LoadFromConfig starts before this one.
+	DependsOn string `conf:"depends_on"`
 
 	// External credential management
 	Port    int           `conf:"port"`
 	Timeout time.Duration `conf:"timeout"`
+	// StartupTimeout bounds how long LoadFromConfig waits for this target to connect.
+	StartupTimeout time.Duration `conf:"startup_timeout"`
 
 	// Authentication and security
 	TLS           bool `conf:"tls"`
 	TLSSkipVerify bool `conf:"tls_skip_verify"`
+
+	// Lazy defers dialing until the connection is first requested from the registry.
+	Lazy bool `conf:"lazy"`
+	// Instrument emits OpenTelemetry spans and metrics for this connection's operations
+	// once the registry's telemetry is enabled.
+	Instrument bool `conf:"instrument"`
 }
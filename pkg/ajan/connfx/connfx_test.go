@@ -387,3 +387,93 @@ func TestRegistry_ErrorHandling(t *testing.T) {
 	require.Error(t, err)
 	require.ErrorIs(t, err, connfx.ErrConnectionNotFound)
 }
+
+func TestRegistry_LazyConnection(t *testing.T) {
+	t.Parallel()
+
+	registry := connfx.NewRegistry(connfx.WithLogger(newMockLogger()))
+
+	factory := &flakyFactory{} //nolint:exhaustruct
+	registry.RegisterFactory(factory)
+
+	ctx := t.Context()
+
+	conn, err := registry.AddConnection(ctx, "optional", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "flaky",
+		Lazy:     true,
+	})
+	require.NoError(t, err)
+	assert.Nil(t, conn)
+	assert.Empty(t, factory.created)
+	assert.Contains(t, registry.ListConnections(), "optional")
+
+	_, err = registry.AddConnection(ctx, "optional", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "flaky",
+		Lazy:     true,
+	})
+	require.ErrorIs(t, err, connfx.ErrConnectionAlreadyExists)
+
+	// A failed first dial leaves the connection pending so it can be retried.
+	factory.failures.Store(1)
+	assert.Nil(t, registry.GetNamed("optional"))
+
+	typed, err := connfx.GetTypedConnection[*flakyConnection](registry, "optional")
+	require.NoError(t, err)
+	require.NotNil(t, typed)
+	assert.Len(t, factory.created, 1)
+
+	// Subsequent lookups reuse the dialed connection.
+	assert.Same(t, typed, registry.GetNamed("optional").GetRawConnection())
+	assert.Len(t, factory.created, 1)
+
+	require.NoError(t, registry.RemoveConnection(ctx, "optional"))
+	assert.Nil(t, registry.GetNamed("optional"))
+}
+
+func TestRegistry_ReconfigureConnection(t *testing.T) {
+	t.Parallel()
+
+	registry := connfx.NewRegistry(connfx.WithLogger(newMockLogger()))
+
+	factory := &flakyFactory{} //nolint:exhaustruct
+	registry.RegisterFactory(factory)
+
+	ctx := t.Context()
+	config := &connfx.ConfigTarget{Protocol: "flaky"} //nolint:exhaustruct
+
+	_, err := registry.ReconfigureConnection(ctx, "cache", config)
+	require.ErrorIs(t, err, connfx.ErrConnectionNotFound)
+
+	old, err := registry.AddConnection(ctx, "cache", config)
+	require.NoError(t, err)
+
+	_, err = registry.AddConnection(ctx, "reports", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "flaky",
+		Lazy:     true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []connfx.ConnectionInfo{
+		{Name: "cache", Protocol: "flaky", State: connfx.ConnectionStateReady, Lazy: false},
+		{Name: "reports", Protocol: "flaky", State: connfx.ConnectionStateNotInitialized, Lazy: true},
+	}, registry.ListConnectionInfo())
+
+	// A failing dial keeps the current connection in place.
+	factory.failures.Store(1)
+
+	_, err = registry.ReconfigureConnection(ctx, "cache", config)
+	require.ErrorIs(t, err, connfx.ErrFailedToCreateConnection)
+	assert.Same(t, old, registry.GetNamed("cache"))
+	assert.False(t, old.(*flakyConnection).closed.Load()) //nolint:forcetypeassert
+
+	replaced, err := registry.ReconfigureConnection(ctx, "cache", config)
+	require.NoError(t, err)
+	assert.NotSame(t, old, replaced)
+	assert.Same(t, replaced, registry.GetNamed("cache"))
+	assert.True(t, old.(*flakyConnection).closed.Load()) //nolint:forcetypeassert
+
+	_, err = registry.ReconfigureConnection(ctx, "cache", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "unknown",
+	})
+	require.ErrorIs(t, err, connfx.ErrUnsupportedProtocol)
+}
@@ -2,9 +2,18 @@ package connfx
 
 import (
 	"context"
+	"errors"
+	"io"
 	"time"
 )
 
+var (
+	// ErrObjectNotFound is returned by ObjectStorageRepository implementations when an object does not exist.
+	ErrObjectNotFound = errors.New("object not found")
+	// ErrFileNotFound is returned by FileTransferRepository implementations when a remote file does not exist.
+	ErrFileNotFound = errors.New("remote file not found")
+)
+
 // Default values for consumer configuration.
 const (
 	DefaultPrefetchCount = 10
@@ -32,6 +41,27 @@ const (
 
 	// ConnectionCapabilityQueue represents message queue behavior.
 	ConnectionCapabilityQueue ConnectionCapability = "queue"
+
+	// ConnectionCapabilityWatch represents change notification behavior for stored keys.
+	ConnectionCapabilityWatch ConnectionCapability = "watch"
+
+	// ConnectionCapabilityEmail represents outgoing email delivery behavior.
+	ConnectionCapabilityEmail ConnectionCapability = "email"
+
+	// ConnectionCapabilityDirectory represents directory lookup and authentication behavior (LDAP).
+	ConnectionCapabilityDirectory ConnectionCapability = "directory"
+
+	// ConnectionCapabilityObjectStorage represents blob/object storage behavior (S3, Azure Blob, GCS).
+	ConnectionCapabilityObjectStorage ConnectionCapability = "object-storage"
+
+	// ConnectionCapabilityStream represents a persistent bidirectional message stream (WebSocket).
+	ConnectionCapabilityStream ConnectionCapability = "stream"
+
+	// ConnectionCapabilityTimeSeries represents time-series storage behavior (InfluxDB).
+	ConnectionCapabilityTimeSeries ConnectionCapability = "time-series"
+
+	// ConnectionCapabilityFileTransfer represents remote file transfer behavior (SFTP).
+	ConnectionCapabilityFileTransfer ConnectionCapability = "file-transfer"
 )
 
 // Repository defines the port for data access operations.
@@ -117,6 +147,193 @@ type TransactionContext interface {
 	GetRepository() Repository
 }
 
+// WatchRepository defines the port for observing changes to stored keys.
+type WatchRepository interface {
+	// Watch streams changes to all keys starting with the given prefix until ctx is done
+	Watch(ctx context.Context, prefix string) (<-chan WatchEvent, <-chan error)
+}
+
+// PrefixRepository defines the port for reading every key under a prefix at once.
+type PrefixRepository interface {
+	// GetPrefix returns the values of all keys starting with prefix, keyed by their full keys
+	GetPrefix(ctx context.Context, prefix string) (map[string][]byte, error)
+}
+
+// WatchEventType represents the kind of change reported by a watch.
+type WatchEventType string
+
+const (
+	// WatchEventPut is reported when a key is created or updated.
+	WatchEventPut WatchEventType = "put"
+
+	// WatchEventDelete is reported when a key is deleted or expires.
+	WatchEventDelete WatchEventType = "delete"
+)
+
+// WatchEvent represents a single change to a watched key.
+type WatchEvent struct {
+	// Type is the kind of change
+	Type WatchEventType
+	// Key is the key that changed
+	Key string
+	// Value is the new value (nil for deletions)
+	Value []byte
+	// PrevValue is the value before the change, if the storage provides it
+	PrevValue []byte
+	// Revision is the storage revision at which the change happened
+	Revision int64
+}
+
+// EmailRepository defines the port for sending emails. It is implemented by the
+// SMTP adapter and can be implemented by API-based providers such as SES.
+type EmailRepository interface {
+	// SendEmail delivers a single message to all of its recipients
+	SendEmail(ctx context.Context, message *EmailMessage) error
+}
+
+// EmailMessage represents an outgoing email.
+type EmailMessage struct {
+	// Headers holds additional headers, e.g. List-Unsubscribe
+	Headers map[string]string
+	// From is the sender address; providers fall back to their configured default when empty
+	From string
+	// ReplyTo is an optional Reply-To address
+	ReplyTo string
+	// Subject is the message subject
+	Subject string
+	// TextBody is the plain text body
+	TextBody string
+	// HTMLBody is the HTML body; sent as multipart/alternative together with TextBody
+	HTMLBody string
+	// To holds the primary recipients
+	To []string
+	// Cc holds the carbon copy recipients
+	Cc []string
+	// Bcc holds the blind carbon copy recipients, which are never written to the headers
+	Bcc []string
+}
+
+// Recipients returns every address the message must be delivered to.
+func (m *EmailMessage) Recipients() []string {
+	recipients := make([]string, 0, len(m.To)+len(m.Cc)+len(m.Bcc))
+	recipients = append(recipients, m.To...)
+	recipients = append(recipients, m.Cc...)
+	recipients = append(recipients, m.Bcc...)
+
+	return recipients
+}
+
+// ObjectStorageRepository defines the port for blob/object storages. Objects are
+// addressed by key inside the bucket (or container) the connection is configured for.
+type ObjectStorageRepository interface {
+	// PutObject uploads an object, replacing it if it already exists
+	PutObject(ctx context.Context, key string, body io.Reader, options *PutObjectOptions) error
+
+	// GetObject opens an object for reading; the caller must close the returned reader
+	GetObject(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error)
+
+	// StatObject returns object metadata without downloading its content
+	StatObject(ctx context.Context, key string) (*ObjectInfo, error)
+
+	// DeleteObject removes an object; deleting a missing object is not an error
+	DeleteObject(ctx context.Context, key string) error
+
+	// ObjectExists checks if an object exists
+	ObjectExists(ctx context.Context, key string) (bool, error)
+
+	// ListObjects lists the objects whose keys start with the given prefix
+	ListObjects(ctx context.Context, prefix string) ([]*ObjectInfo, error)
+}
+
+// PutObjectOptions holds optional settings for uploaded objects.
+type PutObjectOptions struct {
+	// Metadata holds user-defined metadata stored alongside the object
+	Metadata map[string]string
+	// ContentType is the MIME type of the object
+	ContentType string
+}
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	// LastModified is the time the object was last written
+	LastModified time.Time
+	// Metadata holds user-defined metadata
+	Metadata map[string]string
+	// Key is the object key inside the bucket
+	Key string
+	// ContentType is the MIME type of the object
+	ContentType string
+	// ETag is the entity tag reported by the storage
+	ETag string
+	// Size is the object size in bytes
+	Size int64
+}
+
+// FileTransferRepository defines the port for remote file systems such as SFTP.
+// Relative paths are resolved against the connection's base directory.
+type FileTransferRepository interface {
+	// Upload writes the content of body to the remote path, replacing an existing file
+	Upload(ctx context.Context, remotePath string, body io.Reader) error
+
+	// Download opens a remote file for reading; the caller must close the returned reader
+	Download(ctx context.Context, remotePath string) (io.ReadCloser, error)
+
+	// List returns the entries of a remote directory
+	List(ctx context.Context, remoteDir string) ([]*RemoteFileInfo, error)
+
+	// Remove deletes a remote file; removing a missing file is not an error
+	Remove(ctx context.Context, remotePath string) error
+}
+
+// RemoteFileInfo describes an entry of a remote directory.
+type RemoteFileInfo struct {
+	// ModTime is the last modification time
+	ModTime time.Time
+	// Name is the base name of the entry
+	Name string
+	// Path is the full remote path of the entry
+	Path string
+	// Size is the file size in bytes
+	Size int64
+	// IsDir reports whether the entry is a directory
+	IsDir bool
+}
+
+// TimeSeriesRepository defines the port for time-series storages.
+type TimeSeriesRepository interface {
+	// WritePoints writes the given points in a single batch and waits for the result
+	WritePoints(ctx context.Context, points ...*TimeSeriesPoint) error
+
+	// QueryTimeSeries runs a query in the storage's native language (e.g. Flux)
+	QueryTimeSeries(ctx context.Context, query string) ([]*TimeSeriesRecord, error)
+}
+
+// TimeSeriesPoint is a single measurement at a point in time.
+type TimeSeriesPoint struct {
+	// Time is the timestamp of the point; the storage assigns one when zero
+	Time time.Time
+	// Tags hold indexed metadata, e.g. profile slug
+	Tags map[string]string
+	// Fields hold the measured values
+	Fields map[string]any
+	// Measurement is the name of the series, e.g. profile_traffic
+	Measurement string
+}
+
+// TimeSeriesRecord is a single row returned by a time-series query.
+type TimeSeriesRecord struct {
+	// Time is the timestamp of the record
+	Time time.Time
+	// Value is the value of the record's field
+	Value any
+	// Values holds every column of the row, including tags
+	Values map[string]any
+	// Measurement is the name of the series
+	Measurement string
+	// Field is the name of the field the value belongs to
+	Field string
+}
+
 // QueryRepository defines the port for query operations (for SQL-like storages).
 type QueryRepository interface {
 	// Query executes a query and returns raw results
@@ -224,10 +441,24 @@ type QueueStreamRepository interface {
 	TrimStream(ctx context.Context, streamName string, maxLen int64) error
 }
 
+// DeadLetterRedriver is implemented by queue adapters that can move dead-lettered
+// messages back to the queue they were rejected from.
+type DeadLetterRedriver interface {
+	// RedriveDeadLetters moves up to limit messages (all when limit <= 0) from the
+	// queue's dead-letter queue back to the queue and returns how many were moved
+	RedriveDeadLetters(ctx context.Context, queueName string, limit int) (int, error)
+}
+
 // QueueConfig holds configuration for queue declaration.
 type QueueConfig struct {
 	// Args contains additional queue-specific arguments
 	Args map[string]any
+	// DeadLetterQueue is declared alongside the queue and receives its rejected and
+	// expired messages (empty = no dead-lettering)
+	DeadLetterQueue string
+	// DeadLetterExchange routes dead-lettered messages through an exchange instead of
+	// the default exchange
+	DeadLetterExchange string
 	// MaxLength sets maximum number of messages in queue (0 = unlimited)
 	MaxLength int64
 	// MessageTTL sets default TTL for messages
@@ -361,5 +592,8 @@ func DefaultQueueConfig() QueueConfig {
 		Args:       make(map[string]any),
 		MaxLength:  0, // Unlimited
 		MessageTTL: 0, // No TTL
+
+		DeadLetterQueue:    "",
+		DeadLetterExchange: "",
 	}
 }
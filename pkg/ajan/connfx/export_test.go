@@ -0,0 +1,42 @@
+package connfx
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// TestChannel is a channel for the channel pool tests.
+type TestChannel struct {
+	closed atomic.Bool
+}
+
+func (c *TestChannel) IsClosed() bool {
+	return c.closed.Load()
+}
+
+func (c *TestChannel) Close() error {
+	c.closed.Store(true)
+
+	return nil
+}
+
+// TestChannelPool exposes the publisher channel pool to the tests.
+type TestChannelPool struct {
+	pool *channelPool[*TestChannel]
+}
+
+func NewTestChannelPool(open func() (*TestChannel, error), size int) *TestChannelPool {
+	return &TestChannelPool{pool: newChannelPool(open, size)}
+}
+
+func (p *TestChannelPool) Acquire(ctx context.Context) (*TestChannel, error) {
+	return p.pool.acquire(ctx)
+}
+
+func (p *TestChannelPool) Release(channel *TestChannel) {
+	p.pool.release(channel)
+}
+
+func (p *TestChannelPool) Close() {
+	p.pool.close()
+}
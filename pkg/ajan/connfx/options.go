@@ -1,5 +1,7 @@
 package connfx
 
+import "time"
+
 // NewRegistryOption defines functional options for Registry.
 type NewRegistryOption func(*Registry)
 
@@ -10,23 +12,105 @@ func WithLogger(logger Logger) NewRegistryOption {
 	}
 }
 
+// WithAutoReconnect enables the background reconnection manager with the given
+// configuration. The manager runs once StartAutoReconnect is called.
+func WithAutoReconnect(config *ReconnectConfig) NewRegistryOption {
+	return func(r *Registry) {
+		r.reconnectConfig = config
+	}
+}
+
+// WithDrainTimeout sets how long connections dropped by ApplyConfig keep serving
+// in-flight operations before they are closed.
+func WithDrainTimeout(timeout time.Duration) NewRegistryOption {
+	return func(r *Registry) {
+		r.drainTimeout = timeout
+	}
+}
+
+// WithEnvironment scopes configuration loading to an environment: LoadFromConfig and
+// ApplyConfig skip targets whose "env" tag does not list it. Untagged targets are
+// always loaded.
+func WithEnvironment(environment string) NewRegistryOption {
+	return func(r *Registry) {
+		r.environment = environment
+	}
+}
+
+// WithShutdownTimeout sets how long Close waits for queue consumers to acknowledge
+// the messages they received before connections are closed.
+func WithShutdownTimeout(timeout time.Duration) NewRegistryOption {
+	return func(r *Registry) {
+		r.shutdownTimeout = timeout
+	}
+}
+
 func WithDefaultFactories() NewRegistryOption {
 	return func(r *Registry) { //nolint:varnamelen
+		// adapter_inmemory.go
+		r.RegisterFactory(NewInMemoryConnectionFactory("inmemory"))
+
 		// adapter_sql.go
 		r.RegisterFactory(NewSQLConnectionFactory("sqlite"))
 		r.RegisterFactory(NewSQLConnectionFactory("postgres"))
 		r.RegisterFactory(NewSQLConnectionFactory("mysql"))
 
+		// adapter_clickhouse.go
+		r.RegisterFactory(NewClickHouseConnectionFactory("clickhouse"))
+
+		// adapter_cassandra.go
+		r.RegisterFactory(NewCassandraConnectionFactory("cassandra"))
+		r.RegisterFactory(NewCassandraConnectionFactory("scylla"))
+
+		// adapter_influxdb.go
+		r.RegisterFactory(NewInfluxDBConnectionFactory("influxdb"))
+		r.RegisterFactory(NewInfluxDBConnectionFactory("influxdbs"))
+
 		// adapter_http.go
 		r.RegisterFactory(NewHTTPConnectionFactory("http"))
 		r.RegisterFactory(NewHTTPConnectionFactory("https"))
 
+		// adapter_websocket.go
+		r.RegisterFactory(NewWebSocketConnectionFactory("ws"))
+		r.RegisterFactory(NewWebSocketConnectionFactory("wss"))
+
 		// adapter_redis.go
 		r.RegisterFactory(NewRedisConnectionFactory("redis"))
 
+		// adapter_etcd.go
+		r.RegisterFactory(NewEtcdConnectionFactory("etcd"))
+
+		// adapter_consul.go
+		r.RegisterFactory(NewConsulConnectionFactory("consul"))
+		r.RegisterFactory(NewConsulConnectionFactory("consuls"))
+
+		// adapter_azureblob.go
+		r.RegisterFactory(NewAzureBlobConnectionFactory("azblob"))
+
+		// adapter_gcs.go
+		r.RegisterFactory(NewGCSConnectionFactory("gcs"))
+		r.RegisterFactory(NewGCSConnectionFactory("gs"))
+
 		// adapter_amqp.go
 		r.RegisterFactory(NewAMQPConnectionFactory("amqp"))
 
+		// adapter_pubsub.go
+		r.RegisterFactory(NewPubSubConnectionFactory("pubsub"))
+
+		// adapter_mqtt.go
+		r.RegisterFactory(NewMQTTConnectionFactory("mqtt"))
+
+		// adapter_smtp.go
+		r.RegisterFactory(NewSMTPConnectionFactory("smtp"))
+		r.RegisterFactory(NewSMTPConnectionFactory("smtps"))
+
+		// adapter_sftp.go
+		r.RegisterFactory(NewSFTPConnectionFactory("sftp"))
+
+		// adapter_ldap.go
+		r.RegisterFactory(NewLDAPConnectionFactory("ldap"))
+		r.RegisterFactory(NewLDAPConnectionFactory("ldaps"))
+
 		// adapter_otlp.go
 		r.RegisterFactory(NewOTLPConnectionFactory("otlp"))
 	}
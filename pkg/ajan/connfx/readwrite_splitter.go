@@ -0,0 +1,335 @@
+package connfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultMaxReplicationLag   = 5 * time.Second
+	DefaultReplicaCheckTimeout = 2 * time.Second
+)
+
+var (
+	ErrReplicaLagExceeded           = errors.New("replica replication lag exceeds the limit")
+	ErrReplicaCheckFailed           = errors.New("replica check failed")
+	ErrReplicaMonitorAlreadyRunning = errors.New("replica monitor is already running")
+	ErrInvalidReplicaCheckInterval  = errors.New("replica check interval must be positive")
+)
+
+// ReplicationLagProbe measures how far a read replica lags behind its primary.
+type ReplicationLagProbe func(ctx context.Context, replica QueryRepository) (time.Duration, error)
+
+// ReadWriteSplitConfig configures a ReadWriteSplitter.
+type ReadWriteSplitConfig struct {
+	// LagProbe measures replica lag. When nil, replicas are only checked for liveness.
+	LagProbe ReplicationLagProbe
+	// MaxReplicationLag excludes replicas lagging further behind. Zero disables the limit.
+	MaxReplicationLag time.Duration
+	// CheckTimeout bounds a single replica check.
+	CheckTimeout time.Duration
+}
+
+// ReplicaStatus describes the last check of a read replica.
+type ReplicaStatus struct {
+	LastCheck time.Time
+	Error     error
+	Lag       time.Duration
+	Index     int
+	Healthy   bool
+}
+
+// ReadWriteSplitter is a QueryRepository that routes Query calls to read replicas and
+// Execute calls to the primary. Replicas that fail their check or lag behind
+// MaxReplicationLag are excluded until a later check succeeds; when no replica is
+// eligible, queries fall back to the primary.
+type ReadWriteSplitter struct {
+	primary  QueryRepository
+	config   *ReadWriteSplitConfig
+	monitor  *replicaMonitor
+	replicas []*readReplica
+	next     atomic.Uint64 // round-robin cursor
+	mu       sync.Mutex
+}
+
+var _ QueryRepository = (*ReadWriteSplitter)(nil)
+
+// NewDefaultReadWriteSplitConfig returns a configuration for PostgreSQL replicas.
+func NewDefaultReadWriteSplitConfig() *ReadWriteSplitConfig {
+	return &ReadWriteSplitConfig{
+		LagProbe:          PostgresReplicationLag,
+		MaxReplicationLag: DefaultMaxReplicationLag,
+		CheckTimeout:      DefaultReplicaCheckTimeout,
+	}
+}
+
+// NewReadWriteSplitter creates a splitter over a primary and its read replicas.
+// Replicas are considered healthy until their first check.
+func NewReadWriteSplitter(
+	primary QueryRepository,
+	replicas []QueryRepository,
+	config *ReadWriteSplitConfig,
+) *ReadWriteSplitter {
+	if config == nil {
+		config = NewDefaultReadWriteSplitConfig()
+	}
+
+	splitter := &ReadWriteSplitter{
+		primary:  primary,
+		config:   config,
+		monitor:  nil,
+		replicas: make([]*readReplica, len(replicas)),
+		next:     atomic.Uint64{},
+		mu:       sync.Mutex{},
+	}
+
+	for i, replica := range replicas {
+		splitter.replicas[i] = &readReplica{repo: replica, healthy: atomic.Bool{}}
+		splitter.replicas[i].healthy.Store(true)
+	}
+
+	return splitter
+}
+
+// PostgresReplicationLag reports the replay delay of a PostgreSQL standby. A replica that
+// has replayed everything it received reports no lag, so an idle primary does not make
+// its replicas look stale. Primaries report zero.
+func PostgresReplicationLag(ctx context.Context, replica QueryRepository) (time.Duration, error) {
+	rows, err := replica.Query(ctx, `SELECT CASE
+		WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END`)
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	var seconds float64
+
+	if rows.Next() {
+		if err := rows.Scan(&seconds); err != nil {
+			return 0, err //nolint:wrapcheck
+		}
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// Query runs the query on an eligible replica, or on the primary when none is.
+func (s *ReadWriteSplitter) Query( //nolint:ireturn
+	ctx context.Context,
+	query string,
+	args ...any,
+) (QueryResult, error) {
+	return s.Reader().Query(ctx, query, args...) //nolint:wrapcheck
+}
+
+// Execute runs the command on the primary.
+func (s *ReadWriteSplitter) Execute( //nolint:ireturn
+	ctx context.Context,
+	command string,
+	args ...any,
+) (ExecuteResult, error) {
+	return s.primary.Execute(ctx, command, args...) //nolint:wrapcheck
+}
+
+// Primary returns the primary repository, for reads that must observe prior writes.
+func (s *ReadWriteSplitter) Primary() QueryRepository { //nolint:ireturn
+	return s.primary
+}
+
+// Reader returns the next eligible replica in round-robin order, or the primary.
+func (s *ReadWriteSplitter) Reader() QueryRepository { //nolint:ireturn
+	count := len(s.replicas)
+	if count == 0 {
+		return s.primary
+	}
+
+	start := int(s.next.Add(1)-1) % count //nolint:gosec
+
+	for offset := range count {
+		replica := s.replicas[(start+offset)%count]
+		if replica.healthy.Load() {
+			return replica.repo
+		}
+	}
+
+	return s.primary
+}
+
+// CheckReplicas probes every replica and updates which ones are eligible for reads.
+func (s *ReadWriteSplitter) CheckReplicas(ctx context.Context) []ReplicaStatus {
+	statuses := make([]ReplicaStatus, len(s.replicas))
+
+	var wg sync.WaitGroup
+
+	for i, replica := range s.replicas {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			statuses[i] = s.checkReplica(ctx, i, replica)
+		}()
+	}
+
+	wg.Wait()
+
+	s.mu.Lock()
+	for i, replica := range s.replicas {
+		replica.status = statuses[i]
+	}
+	s.mu.Unlock()
+
+	return statuses
+}
+
+// GetReplicaStatuses returns the result of the last check of each replica.
+func (s *ReadWriteSplitter) GetReplicaStatuses() []ReplicaStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]ReplicaStatus, len(s.replicas))
+	for i, replica := range s.replicas {
+		statuses[i] = replica.status
+		statuses[i].Index = i
+		statuses[i].Healthy = replica.healthy.Load()
+	}
+
+	return statuses
+}
+
+// StartReplicaMonitor runs CheckReplicas at the given interval until StopReplicaMonitor.
+func (s *ReadWriteSplitter) StartReplicaMonitor(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return ErrInvalidReplicaCheckInterval
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.monitor != nil {
+		return ErrReplicaMonitorAlreadyRunning
+	}
+
+	monitorCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	s.monitor = &replicaMonitor{
+		cancel: cancel,
+		wg:     sync.WaitGroup{},
+	}
+
+	s.monitor.wg.Add(1)
+
+	go s.runMonitor(monitorCtx, interval, s.monitor)
+
+	return nil
+}
+
+// StopReplicaMonitor stops the replica monitor. It is safe to call when it is not running.
+func (s *ReadWriteSplitter) StopReplicaMonitor() {
+	s.mu.Lock()
+	monitor := s.monitor
+	s.monitor = nil
+	s.mu.Unlock()
+
+	if monitor == nil {
+		return
+	}
+
+	monitor.cancel()
+	monitor.wg.Wait()
+}
+
+type readReplica struct {
+	repo    QueryRepository
+	status  ReplicaStatus
+	healthy atomic.Bool
+}
+
+type replicaMonitor struct {
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func (s *ReadWriteSplitter) checkReplica(
+	ctx context.Context,
+	index int,
+	replica *readReplica,
+) ReplicaStatus {
+	checkCtx := ctx
+
+	if s.config.CheckTimeout > 0 {
+		var cancel context.CancelFunc
+
+		checkCtx, cancel = context.WithTimeout(ctx, s.config.CheckTimeout)
+		defer cancel()
+	}
+
+	status := ReplicaStatus{
+		LastCheck: time.Now(),
+		Error:     nil,
+		Lag:       0,
+		Index:     index,
+		Healthy:   false,
+	}
+
+	if s.config.LagProbe != nil {
+		status.Lag, status.Error = s.config.LagProbe(checkCtx, replica.repo)
+	} else {
+		status.Error = pingReplica(checkCtx, replica.repo)
+	}
+
+	switch {
+	case status.Error != nil:
+		status.Error = fmt.Errorf("%w (replica=%d): %w", ErrReplicaCheckFailed, index, status.Error)
+	case s.config.MaxReplicationLag > 0 && status.Lag > s.config.MaxReplicationLag:
+		status.Error = fmt.Errorf(
+			"%w (replica=%d, lag=%s, max=%s)",
+			ErrReplicaLagExceeded,
+			index,
+			status.Lag,
+			s.config.MaxReplicationLag,
+		)
+	default:
+		status.Healthy = true
+	}
+
+	replica.healthy.Store(status.Healthy)
+
+	return status
+}
+
+func (s *ReadWriteSplitter) runMonitor(
+	ctx context.Context,
+	interval time.Duration,
+	monitor *replicaMonitor,
+) {
+	defer monitor.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.CheckReplicas(ctx)
+		}
+	}
+}
+
+func pingReplica(ctx context.Context, replica QueryRepository) error {
+	rows, err := replica.Query(ctx, "SELECT 1")
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return rows.Close() //nolint:wrapcheck
+}
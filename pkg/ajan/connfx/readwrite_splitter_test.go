@@ -0,0 +1,187 @@
+package connfx_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func newSplitterTestDatabase(t *testing.T, registry *connfx.Registry, name string) connfx.QueryRepository {
+	t.Helper()
+
+	ctx := t.Context()
+
+	_, err := registry.AddConnection(ctx, name, &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "sqlite",
+		DSN:      "file:" + t.Name() + "_" + name + "?mode=memory&cache=shared",
+	})
+	require.NoError(t, err)
+
+	repo, err := registry.GetQueryRepository(name)
+	require.NoError(t, err)
+
+	_, err = repo.Execute(ctx, "CREATE TABLE nodes (name TEXT)")
+	require.NoError(t, err)
+
+	_, err = repo.Execute(ctx, "INSERT INTO nodes (name) VALUES (?)", name)
+	require.NoError(t, err)
+
+	return repo
+}
+
+func queryNodeName(t *testing.T, repo connfx.QueryRepository) string {
+	t.Helper()
+
+	rows, err := repo.Query(t.Context(), "SELECT name FROM nodes LIMIT 1")
+	require.NoError(t, err)
+
+	defer func() { _ = rows.Close() }()
+
+	var name string
+
+	require.True(t, rows.Next())
+	require.NoError(t, rows.Scan(&name))
+
+	return name
+}
+
+func TestReadWriteSplitter(t *testing.T) {
+	t.Parallel()
+
+	registry := connfx.NewRegistry(connfx.WithLogger(newMockLogger()))
+	registry.RegisterFactory(connfx.NewSQLConnectionFactory("sqlite"))
+
+	t.Cleanup(func() { _ = registry.Close(context.Background()) })
+
+	primary := newSplitterTestDatabase(t, registry, "primary")
+	replica1 := newSplitterTestDatabase(t, registry, "replica1")
+	replica2 := newSplitterTestDatabase(t, registry, "replica2")
+
+	var (
+		lags   = map[connfx.QueryRepository]time.Duration{}
+		lagsMu sync.Mutex
+	)
+
+	splitter := connfx.NewReadWriteSplitter(
+		primary,
+		[]connfx.QueryRepository{replica1, replica2},
+		&connfx.ReadWriteSplitConfig{
+			LagProbe: func(_ context.Context, replica connfx.QueryRepository) (time.Duration, error) {
+				lagsMu.Lock()
+				defer lagsMu.Unlock()
+
+				return lags[replica], nil
+			},
+			MaxReplicationLag: time.Second,
+			CheckTimeout:      time.Second,
+		},
+	)
+
+	ctx := t.Context()
+
+	// Reads rotate over the replicas.
+	seen := map[string]int{}
+	for range 4 {
+		seen[queryNodeName(t, splitter)]++
+	}
+
+	assert.Equal(t, map[string]int{"replica1": 2, "replica2": 2}, seen)
+
+	// Writes go to the primary.
+	result, err := splitter.Execute(ctx, "INSERT INTO nodes (name) VALUES (?)", "written")
+	require.NoError(t, err)
+
+	affected, err := result.RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+
+	rows, err := splitter.Primary().Query(ctx, "SELECT COUNT(*) FROM nodes")
+	require.NoError(t, err)
+
+	var count int
+
+	require.True(t, rows.Next())
+	require.NoError(t, rows.Scan(&count))
+	require.NoError(t, rows.Close())
+	assert.Equal(t, 2, count)
+
+	// A lagging replica is excluded until it catches up.
+	lagsMu.Lock()
+	lags[replica1] = 10 * time.Second
+	lagsMu.Unlock()
+
+	statuses := splitter.CheckReplicas(ctx)
+	require.Len(t, statuses, 2)
+	require.ErrorIs(t, statuses[0].Error, connfx.ErrReplicaLagExceeded)
+	assert.False(t, statuses[0].Healthy)
+	assert.True(t, statuses[1].Healthy)
+
+	for range 3 {
+		assert.Equal(t, "replica2", queryNodeName(t, splitter))
+	}
+
+	// Reads fall back to the primary when no replica is eligible.
+	lagsMu.Lock()
+	lags[replica2] = 10 * time.Second
+	lagsMu.Unlock()
+
+	splitter.CheckReplicas(ctx)
+	assert.Equal(t, "primary", queryNodeName(t, splitter))
+
+	lagsMu.Lock()
+	clear(lags)
+	lagsMu.Unlock()
+
+	splitter.CheckReplicas(ctx)
+
+	for _, status := range splitter.GetReplicaStatuses() {
+		assert.True(t, status.Healthy)
+	}
+}
+
+func TestReadWriteSplitter_LivenessCheck(t *testing.T) {
+	t.Parallel()
+
+	registry := connfx.NewRegistry(connfx.WithLogger(newMockLogger()))
+	registry.RegisterFactory(connfx.NewSQLConnectionFactory("sqlite"))
+
+	primary := newSplitterTestDatabase(t, registry, "primary")
+	replica := newSplitterTestDatabase(t, registry, "replica")
+
+	splitter := connfx.NewReadWriteSplitter(
+		primary,
+		[]connfx.QueryRepository{replica},
+		&connfx.ReadWriteSplitConfig{ //nolint:exhaustruct
+			CheckTimeout: time.Second,
+		},
+	)
+
+	ctx := t.Context()
+
+	statuses := splitter.CheckReplicas(ctx)
+	require.NoError(t, statuses[0].Error)
+	assert.Equal(t, "replica", queryNodeName(t, splitter))
+
+	// Closing the replica makes its liveness check fail.
+	require.NoError(t, registry.RemoveConnection(ctx, "replica"))
+
+	statuses = splitter.CheckReplicas(ctx)
+	require.ErrorIs(t, statuses[0].Error, connfx.ErrReplicaCheckFailed)
+	assert.Equal(t, "primary", queryNodeName(t, splitter))
+
+	require.NoError(t, splitter.StartReplicaMonitor(ctx, 10*time.Millisecond))
+	require.ErrorIs(
+		t,
+		splitter.StartReplicaMonitor(ctx, 10*time.Millisecond),
+		connfx.ErrReplicaMonitorAlreadyRunning,
+	)
+	splitter.StopReplicaMonitor()
+
+	require.NoError(t, registry.Close(ctx))
+}
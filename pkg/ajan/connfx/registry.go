@@ -9,6 +9,7 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"time"
 )
 
 var (
@@ -24,21 +25,76 @@ var (
 
 const DefaultConnection = "default"
 
+// ConnectionInfo describes a registered connection without exposing its configuration.
+type ConnectionInfo struct {
+	Tags     map[string]string
+	Name     string
+	Protocol string
+	State    ConnectionState
+	Lazy     bool // registered lazily and not dialed yet
+}
+
 // Registry manages all connections in the system.
 type Registry struct {
 	connections map[string]Connection
+	configs     map[string]*ConfigTarget     // name -> config the connection was created from
+	pending     map[string]*ConfigTarget     // name -> lazy config not dialed yet
+	applied     map[string]ConfigTarget      // name -> target as loaded from configuration
 	factories   map[string]ConnectionFactory // protocol -> factory
+	draining    map[Connection]*time.Timer   // detached connections awaiting their close
+	consumers   *consumerTracker             // messages delivered but not acknowledged yet
+	bootReport  *BootReport                  // outcome of the last LoadFromConfig
 	logger      Logger
-	mu          sync.RWMutex
+
+	environment     string // targets tagged for other environments are skipped
+	drainTimeout    time.Duration
+	shutdownTimeout time.Duration
+	closing         bool
+
+	reconnectConfig *ReconnectConfig
+	reconnector     *reconnectManager
+	healthMonitor   *healthMonitor
+	certWatcher     *certificateWatcher
+	telemetry       *connectionTelemetry
+
+	states        map[string]ConnectionState // last observed state per connection
+	healthStats   map[string]*ConnectionHealthStats
+	stateHandlers []StateChangeHandler
+
+	mu      sync.RWMutex
+	stateMu sync.RWMutex
 }
 
 // NewRegistry creates a new connection registry.
 func NewRegistry(options ...NewRegistryOption) *Registry {
 	registry := &Registry{
 		connections: make(map[string]Connection),
+		configs:     make(map[string]*ConfigTarget),
+		pending:     make(map[string]*ConfigTarget),
+		applied:     make(map[string]ConfigTarget),
 		factories:   make(map[string]ConnectionFactory),
+		draining:    make(map[Connection]*time.Timer),
+		consumers:   newConsumerTracker(),
+		bootReport:  nil,
 		logger:      slog.Default(),
-		mu:          sync.RWMutex{},
+
+		environment:     "",
+		drainTimeout:    DefaultDrainTimeout,
+		shutdownTimeout: DefaultShutdownTimeout,
+		closing:         false,
+
+		reconnectConfig: nil,
+		reconnector:     nil,
+		healthMonitor:   nil,
+		certWatcher:     nil,
+		telemetry:       nil,
+
+		states:        make(map[string]ConnectionState),
+		healthStats:   make(map[string]*ConnectionHealthStats),
+		stateHandlers: nil,
+
+		mu:      sync.RWMutex{},
+		stateMu: sync.RWMutex{},
 	}
 
 	for _, option := range options {
@@ -60,18 +116,28 @@ func (registry *Registry) RegisterFactory(factory ConnectionFactory) {
 
 // GetDefault returns the default connection.
 func (registry *Registry) GetDefault() Connection { //nolint:ireturn
-	registry.mu.RLock()
-	defer registry.mu.RUnlock()
-
-	return registry.connections[DefaultConnection]
+	return registry.GetNamed(DefaultConnection)
 }
 
-// GetNamed returns a named connection.
+// GetNamed returns a named connection. Lazy connections are dialed on their first
+// request; if dialing fails, nil is returned and the next call tries again.
+// Once Close has been called, nil is returned for every name.
 func (registry *Registry) GetNamed(name string) Connection { //nolint:ireturn
 	registry.mu.RLock()
-	defer registry.mu.RUnlock()
+	conn := registry.connections[name]
+	_, isPending := registry.pending[name]
+	closing := registry.closing
+	registry.mu.RUnlock()
+
+	if closing {
+		return nil
+	}
 
-	return registry.connections[name]
+	if conn != nil || !isPending {
+		return conn
+	}
+
+	return registry.initLazyConnection(context.Background(), name)
 }
 
 // GetByBehavior returns all connections of a specific behavior.
@@ -125,14 +191,52 @@ func (registry *Registry) ListConnections() []string {
 	registry.mu.RLock()
 	defer registry.mu.RUnlock()
 
-	names := make([]string, 0, len(registry.connections))
+	names := make([]string, 0, len(registry.connections)+len(registry.pending))
 	for name := range registry.connections {
 		names = append(names, name)
 	}
 
+	for name := range registry.pending {
+		names = append(names, name)
+	}
+
 	return names
 }
 
+// ListConnectionInfo returns the name, protocol and state of every registered connection.
+// Lazy connections that have not been dialed yet are reported without being dialed.
+func (registry *Registry) ListConnectionInfo() []ConnectionInfo {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	infos := make([]ConnectionInfo, 0, len(registry.connections)+len(registry.pending))
+	for name, conn := range registry.connections {
+		infos = append(infos, ConnectionInfo{
+			Tags:     registry.tags(name),
+			Name:     name,
+			Protocol: conn.GetProtocol(),
+			State:    conn.GetState(),
+			Lazy:     false,
+		})
+	}
+
+	for name, config := range registry.pending {
+		infos = append(infos, ConnectionInfo{
+			Tags:     maps.Clone(config.Tags),
+			Name:     name,
+			Protocol: config.Protocol,
+			State:    ConnectionStateNotInitialized,
+			Lazy:     true,
+		})
+	}
+
+	slices.SortFunc(infos, func(a, b ConnectionInfo) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return infos
+}
+
 // ListRegisteredProtocols returns all registered protocols.
 func (registry *Registry) ListRegisteredProtocols() []string {
 	registry.mu.RLock()
@@ -147,6 +251,8 @@ func (registry *Registry) ListRegisteredProtocols() []string {
 }
 
 // AddConnection adds a new connection to the registry.
+// When config.Lazy is set, the connection is only registered and nil is returned;
+// it is dialed on the first GetNamed or GetTypedConnection call.
 func (registry *Registry) AddConnection( //nolint:ireturn
 	ctx context.Context,
 	name string,
@@ -155,17 +261,38 @@ func (registry *Registry) AddConnection( //nolint:ireturn
 	registry.mu.Lock()
 	defer registry.mu.Unlock()
 
+	if registry.closing {
+		return nil, fmt.Errorf("%w (name=%q)", ErrRegistryClosed, name)
+	}
+
 	// Check if connection already exists
 	if _, exists := registry.connections[name]; exists {
 		return nil, fmt.Errorf("%w (name=%q)", ErrConnectionAlreadyExists, name)
 	}
 
+	if _, exists := registry.pending[name]; exists {
+		return nil, fmt.Errorf("%w (name=%q)", ErrConnectionAlreadyExists, name)
+	}
+
 	// Get factory for this protocol
 	factory, exists := registry.factories[config.Protocol]
 	if !exists {
 		return nil, fmt.Errorf("%w (protocol=%q)", ErrUnsupportedProtocol, config.Protocol)
 	}
 
+	if config.Lazy {
+		registry.pending[name] = config
+
+		registry.logger.InfoContext(
+			ctx,
+			"registered lazy connection",
+			slog.String("name", name),
+			slog.String("protocol", config.Protocol),
+		)
+
+		return nil, nil //nolint:nilnil
+	}
+
 	registry.logger.InfoContext(
 		ctx,
 		"creating connection",
@@ -174,7 +301,7 @@ func (registry *Registry) AddConnection( //nolint:ireturn
 	)
 
 	// Create the connection
-	conn, err := factory.CreateConnection(ctx, config)
+	conn, err := registry.createConnection(ctx, factory, config)
 	if err != nil {
 		registry.logger.ErrorContext(
 			ctx,
@@ -188,6 +315,7 @@ func (registry *Registry) AddConnection( //nolint:ireturn
 	}
 
 	registry.connections[name] = conn
+	registry.configs[name] = config
 
 	registry.logger.InfoContext(
 		ctx,
@@ -204,6 +332,20 @@ func (registry *Registry) RemoveConnection(ctx context.Context, name string) err
 	registry.mu.Lock()
 	defer registry.mu.Unlock()
 
+	delete(registry.applied, name)
+
+	if _, isPending := registry.pending[name]; isPending {
+		delete(registry.pending, name)
+
+		registry.logger.InfoContext(
+			ctx,
+			"removed lazy connection",
+			slog.String("name", name),
+		)
+
+		return nil
+	}
+
 	conn, exists := registry.connections[name]
 	if !exists {
 		return fmt.Errorf("%w (name=%q)", ErrConnectionNotFound, name)
@@ -220,6 +362,8 @@ func (registry *Registry) RemoveConnection(ctx context.Context, name string) err
 	}
 
 	delete(registry.connections, name)
+	delete(registry.configs, name)
+	registry.forgetState(name)
 
 	registry.logger.InfoContext(
 		ctx,
@@ -230,14 +374,54 @@ func (registry *Registry) RemoveConnection(ctx context.Context, name string) err
 	return nil
 }
 
-func (registry *Registry) LoadFromConfig(ctx context.Context, config *Config) error {
-	for name, target := range config.Targets {
-		if _, err := registry.AddConnection(ctx, name, &target); err != nil {
-			return fmt.Errorf("%w (name=%q): %w", ErrFailedToAddConnection, name, err)
+// ReconfigureConnection replaces an existing connection with one created from the new
+// configuration. The new connection is dialed before the old one is closed, so a failed
+// reconfiguration leaves the current connection in place.
+func (registry *Registry) ReconfigureConnection( //nolint:ireturn
+	ctx context.Context,
+	name string,
+	config *ConfigTarget,
+) (Connection, error) {
+	conn, old, err := registry.replaceConnection(ctx, name, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if old != nil {
+		if err := old.Close(ctx); err != nil {
+			registry.logger.WarnContext(
+				ctx,
+				"error closing replaced connection",
+				slog.String("error", err.Error()),
+				slog.String("name", name),
+			)
 		}
 	}
 
-	return nil
+	return conn, nil
+}
+
+// LoadFromConfig adds a connection for every target. Targets start after the targets
+// they depend on (see ConfigTarget.DependsOn); a target whose dependency fails is skipped,
+// while independent targets still start. The outcome is kept in BootReport.
+// The targets are remembered, so a later ApplyConfig can tell which connections the
+// configuration added, changed or dropped. Targets scoped to another environment
+// (see WithEnvironment) are skipped.
+func (registry *Registry) LoadFromConfig(ctx context.Context, config *Config) error {
+	targets := registry.scopeTargets(ctx, config.Targets)
+
+	order, err := startupOrder(targets)
+	if err != nil {
+		return err
+	}
+
+	report, err := registry.startTargets(ctx, targets, order)
+
+	registry.mu.Lock()
+	registry.bootReport = report
+	registry.mu.Unlock()
+
+	return err
 }
 
 // HealthCheck performs health checks on all connections.
@@ -248,42 +432,40 @@ func (registry *Registry) HealthCheck(ctx context.Context) map[string]*HealthSta
 	maps.Copy(connections, registry.connections)
 	registry.mu.RUnlock()
 
-	results := make(map[string]*HealthStatus)
-
-	// Use a channel to collect results
-	type healthResult struct {
-		status *HealthStatus
-		name   string
-	}
-
-	resultChan := make(chan healthResult, len(connections))
+	return checkHealth(ctx, connections)
+}
 
-	// Perform health checks concurrently
-	for name, conn := range connections {
-		go func(name string, conn Connection) {
-			status := conn.HealthCheck(ctx)
-			resultChan <- healthResult{name: name, status: status}
-		}(name, conn)
-	}
+// Close shuts the registry down in order: connections stop being handed out and queue
+// consumers stop receiving messages, then Close waits up to the shutdown timeout (or
+// until ctx is done) for delivered messages to be acknowledged, and finally closes
+// streaming, stateless and stateful connections, in that order.
+// A closed registry does not accept new connections.
+func (registry *Registry) Close(ctx context.Context) error {
+	registry.stopAccepting()
 
-	// Collect results
-	for range len(connections) {
-		result := <-resultChan
-		results[result.name] = result.status
-	}
+	// Stop background monitors before tearing connections down, so nothing gets recreated.
+	registry.StopHealthMonitor()
+	registry.StopAutoReconnect()
+	registry.StopCertificateWatcher()
 
-	return results
-}
+	registry.waitForConsumers(ctx)
 
-// Close closes all connections in the registry.
-func (registry *Registry) Close(ctx context.Context) error {
 	registry.mu.Lock()
 	defer registry.mu.Unlock()
 
 	var errors []error
 
-	for name, conn := range registry.connections {
-		if err := conn.Close(ctx); err != nil {
+	// Connections still draining are closed right away.
+	for conn, timer := range registry.draining {
+		if timer.Stop() {
+			if err := conn.Close(ctx); err != nil {
+				errors = append(errors, fmt.Errorf("%w (draining): %w", ErrFailedToCloseConnection, err))
+			}
+		}
+	}
+
+	for _, name := range registry.closeOrder() {
+		if err := registry.connections[name].Close(ctx); err != nil {
 			errors = append(
 				errors,
 				fmt.Errorf("%w (name=%q): %w", ErrFailedToCloseConnection, name, err),
@@ -291,8 +473,25 @@ func (registry *Registry) Close(ctx context.Context) error {
 		}
 	}
 
+	if registry.telemetry != nil {
+		if err := registry.telemetry.close(); err != nil {
+			errors = append(errors, err)
+		}
+
+		registry.telemetry = nil
+	}
+
 	// Clear the connections map
 	registry.connections = make(map[string]Connection)
+	registry.configs = make(map[string]*ConfigTarget)
+	registry.pending = make(map[string]*ConfigTarget)
+	registry.applied = make(map[string]ConfigTarget)
+	registry.draining = make(map[Connection]*time.Timer)
+
+	registry.stateMu.Lock()
+	registry.states = make(map[string]ConnectionState)
+	registry.healthStats = make(map[string]*ConnectionHealthStats)
+	registry.stateMu.Unlock()
 
 	if len(errors) > 0 {
 		errStrs := make([]string, len(errors))
@@ -311,29 +510,206 @@ func (registry *Registry) Close(ctx context.Context) error {
 
 // GetRepository returns a Repository from a connection if it supports it.
 func (registry *Registry) GetRepository(name string) (Repository, error) { //nolint:ireturn
+	conn, repo, err := getCapability[Repository](registry, name, "data repository operations",
+		ConnectionCapabilityKeyValue, ConnectionCapabilityDocument, ConnectionCapabilityRelational)
+	if err != nil {
+		return nil, err
+	}
+
 	registry.mu.RLock()
 	defer registry.mu.RUnlock()
 
-	conn := registry.connections[name]
-	if conn == nil {
-		return nil, fmt.Errorf("%w (name=%q)", ErrConnectionNotFound, name)
+	if instrumentation := registry.instrumentation(name, conn); instrumentation != nil {
+		return &instrumentedRepository{Repository: repo, instrumentation: instrumentation}, nil
 	}
 
-	// Check if the connection supports key-value behavior
-	capabilities := conn.GetCapabilities()
-	if !slices.Contains(capabilities, ConnectionCapabilityKeyValue) &&
-		!slices.Contains(capabilities, ConnectionCapabilityDocument) &&
-		!slices.Contains(capabilities, ConnectionCapabilityRelational) {
-		return nil, fmt.Errorf("%w (name=%q, operation=%q)",
-			ErrConnectionNotSupported, name, "data repository operations")
+	return repo, nil
+}
+
+// GetQueueRepository returns a QueueRepository from a connection if it supports it.
+func (registry *Registry) GetQueueRepository(name string) (QueueRepository, error) { //nolint:ireturn
+	conn, raw, err := getCapability[QueueRepository](registry, name, "queue operations",
+		ConnectionCapabilityQueue)
+	if err != nil {
+		return nil, err
 	}
 
-	// Try to get the repository from the raw connection
-	repo, ok := conn.GetRawConnection().(Repository)
-	if !ok {
-		return nil, fmt.Errorf("%w (name=%q, interface=%q)",
-			ErrInterfaceNotImplemented, name, "Repository")
+	var repo QueueRepository = &trackedQueueRepository{QueueRepository: raw, tracker: registry.consumers}
+
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	if instrumentation := registry.instrumentation(name, conn); instrumentation != nil {
+		return &instrumentedQueueRepository{QueueRepository: repo, instrumentation: instrumentation}, nil
 	}
 
 	return repo, nil
 }
+
+// GetQueryRepository returns a QueryRepository from a connection if it supports it.
+func (registry *Registry) GetQueryRepository(name string) (QueryRepository, error) { //nolint:ireturn
+	// Adapters such as SQL expose the driver handle as the raw connection and
+	// implement the repository on the connection itself.
+	conn, repo, err := getCapability[QueryRepository](registry, name, "query operations",
+		ConnectionCapabilityRelational)
+	if err != nil {
+		return nil, err
+	}
+
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	if instrumentation := registry.instrumentation(name, conn); instrumentation != nil {
+		return &instrumentedQueryRepository{QueryRepository: repo, instrumentation: instrumentation}, nil
+	}
+
+	return repo, nil
+}
+
+func (registry *Registry) initLazyConnection(ctx context.Context, name string) Connection { //nolint:ireturn
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	// Another caller may have dialed it while we were waiting for the lock.
+	if conn, exists := registry.connections[name]; exists {
+		return conn
+	}
+
+	config, isPending := registry.pending[name]
+	if !isPending {
+		return nil
+	}
+
+	factory, exists := registry.factories[config.Protocol]
+	if !exists {
+		return nil
+	}
+
+	registry.logger.InfoContext(
+		ctx,
+		"initializing lazy connection",
+		slog.String("name", name),
+		slog.String("protocol", config.Protocol),
+	)
+
+	conn, err := registry.createConnection(ctx, factory, config)
+	if err != nil {
+		registry.logger.ErrorContext(
+			ctx,
+			"failed to initialize lazy connection",
+			slog.String("error", err.Error()),
+			slog.String("name", name),
+			slog.String("protocol", config.Protocol),
+		)
+
+		return nil
+	}
+
+	delete(registry.pending, name)
+	registry.connections[name] = conn
+	registry.configs[name] = config
+
+	return conn
+}
+
+// replaceConnection swaps a registered connection for one created from config and returns
+// the replaced connection, which the caller is responsible for closing.
+func (registry *Registry) replaceConnection(
+	ctx context.Context,
+	name string,
+	config *ConfigTarget,
+) (Connection, Connection, error) {
+	registry.mu.Lock()
+
+	old, exists := registry.connections[name]
+	_, isPending := registry.pending[name]
+
+	if !exists && !isPending {
+		registry.mu.Unlock()
+
+		return nil, nil, fmt.Errorf("%w (name=%q)", ErrConnectionNotFound, name)
+	}
+
+	factory, hasFactory := registry.factories[config.Protocol]
+	if !hasFactory {
+		registry.mu.Unlock()
+
+		return nil, nil, fmt.Errorf("%w (protocol=%q)", ErrUnsupportedProtocol, config.Protocol)
+	}
+
+	var conn Connection
+
+	if config.Lazy {
+		delete(registry.connections, name)
+		delete(registry.configs, name)
+		registry.pending[name] = config
+	} else {
+		var err error
+
+		conn, err = registry.createConnection(ctx, factory, config)
+		if err != nil {
+			registry.mu.Unlock()
+
+			return nil, nil, fmt.Errorf("%w (name=%q): %w", ErrFailedToCreateConnection, name, err)
+		}
+
+		delete(registry.pending, name)
+		registry.connections[name] = conn
+		registry.configs[name] = config
+	}
+
+	registry.mu.Unlock()
+
+	registry.forgetState(name)
+
+	registry.logger.InfoContext(
+		ctx,
+		"reconfigured connection",
+		slog.String("name", name),
+		slog.String("protocol", config.Protocol),
+	)
+
+	return conn, old, nil
+}
+
+// createConnection dials a target, building a failover group when it declares endpoints.
+func (registry *Registry) createConnection( //nolint:ireturn
+	ctx context.Context,
+	factory ConnectionFactory,
+	config *ConfigTarget,
+) (Connection, error) {
+	if len(config.Endpoints) > 0 {
+		return newFailoverConnection(ctx, registry.logger, factory, config)
+	}
+
+	return factory.CreateConnection(ctx, config) //nolint:wrapcheck
+}
+
+// checkHealth runs the health checks of the given connections concurrently.
+func checkHealth(ctx context.Context, connections map[string]Connection) map[string]*HealthStatus {
+	results := make(map[string]*HealthStatus)
+
+	// Use a channel to collect results
+	type healthResult struct {
+		status *HealthStatus
+		name   string
+	}
+
+	resultChan := make(chan healthResult, len(connections))
+
+	// Perform health checks concurrently
+	for name, conn := range connections {
+		go func(name string, conn Connection) {
+			status := conn.HealthCheck(ctx)
+			resultChan <- healthResult{name: name, status: status}
+		}(name, conn)
+	}
+
+	// Collect results
+	for range len(connections) {
+		result := <-resultChan
+		results[result.name] = result.status
+	}
+
+	return results
+}
@@ -0,0 +1,147 @@
+package connfx
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// GetCache returns the CacheRepository of a connection with the cache capability.
+func GetCache(registry *Registry, name string) (CacheRepository, error) { //nolint:ireturn
+	_, repo, err := getCapability[CacheRepository](registry, name, "cache operations",
+		ConnectionCapabilityCache)
+
+	return repo, err
+}
+
+// GetQueue returns the QueueRepository of a connection with the queue capability.
+// It is the same as Registry.GetQueueRepository.
+func GetQueue(registry *Registry, name string) (QueueRepository, error) { //nolint:ireturn
+	if registry == nil {
+		return nil, ErrRegistryIsNil
+	}
+
+	return registry.GetQueueRepository(name)
+}
+
+// GetStream returns the QueueStreamRepository of a stream-based queue connection
+// such as Redis Streams. Like GetQueue, consumers stop receiving messages once the
+// registry starts closing.
+func GetStream(registry *Registry, name string) (QueueStreamRepository, error) { //nolint:ireturn
+	_, repo, err := getCapability[QueueStreamRepository](registry, name, "stream operations",
+		ConnectionCapabilityQueue)
+	if err != nil {
+		return nil, err
+	}
+
+	return &trackedQueueStreamRepository{
+		trackedQueueRepository: &trackedQueueRepository{
+			QueueRepository: repo,
+			tracker:         registry.consumers,
+		},
+		stream: repo,
+	}, nil
+}
+
+// GetTransactional returns the TransactionalRepository of a transactional connection.
+func GetTransactional(registry *Registry, name string) (TransactionalRepository, error) { //nolint:ireturn
+	_, repo, err := getCapability[TransactionalRepository](registry, name, "transactions",
+		ConnectionCapabilityTransactional)
+
+	return repo, err
+}
+
+// GetWatch returns the WatchRepository of a connection with the watch capability.
+func GetWatch(registry *Registry, name string) (WatchRepository, error) { //nolint:ireturn
+	_, repo, err := getCapability[WatchRepository](registry, name, "watch operations",
+		ConnectionCapabilityWatch)
+
+	return repo, err
+}
+
+// GetPrefix returns the PrefixRepository of a key-value connection that can read every
+// key under a prefix, such as etcd or Consul.
+func GetPrefix(registry *Registry, name string) (PrefixRepository, error) { //nolint:ireturn
+	_, repo, err := getCapability[PrefixRepository](registry, name, "prefix reads",
+		ConnectionCapabilityKeyValue)
+
+	return repo, err
+}
+
+// GetEmail returns the EmailRepository of a connection with the email capability.
+func GetEmail(registry *Registry, name string) (EmailRepository, error) { //nolint:ireturn
+	_, repo, err := getCapability[EmailRepository](registry, name, "email delivery",
+		ConnectionCapabilityEmail)
+
+	return repo, err
+}
+
+// GetObjectStorage returns the ObjectStorageRepository of an object storage connection.
+func GetObjectStorage(registry *Registry, name string) (ObjectStorageRepository, error) { //nolint:ireturn
+	_, repo, err := getCapability[ObjectStorageRepository](registry, name, "object storage operations",
+		ConnectionCapabilityObjectStorage)
+
+	return repo, err
+}
+
+// GetFileTransfer returns the FileTransferRepository of a file transfer connection.
+func GetFileTransfer(registry *Registry, name string) (FileTransferRepository, error) { //nolint:ireturn
+	_, repo, err := getCapability[FileTransferRepository](registry, name, "file transfer operations",
+		ConnectionCapabilityFileTransfer)
+
+	return repo, err
+}
+
+// GetTimeSeries returns the TimeSeriesRepository of a time-series connection.
+func GetTimeSeries(registry *Registry, name string) (TimeSeriesRepository, error) { //nolint:ireturn
+	_, repo, err := getCapability[TimeSeriesRepository](registry, name, "time-series operations",
+		ConnectionCapabilityTimeSeries)
+
+	return repo, err
+}
+
+// getCapability looks up a connection that has at least one of the given capabilities
+// and returns it together with its implementation of T. The raw connection is checked
+// first; adapters that expose a driver handle as the raw connection may implement T
+// on the connection itself.
+func getCapability[T any]( //nolint:ireturn
+	registry *Registry,
+	name string,
+	operation string,
+	capabilities ...ConnectionCapability,
+) (Connection, T, error) {
+	var zero T
+
+	if registry == nil {
+		return nil, zero, ErrRegistryIsNil
+	}
+
+	conn := registry.GetNamed(name)
+	if conn == nil {
+		if registry.isClosing() {
+			return nil, zero, fmt.Errorf("%w (name=%q)", ErrRegistryClosed, name)
+		}
+
+		return nil, zero, fmt.Errorf("%w (name=%q)", ErrConnectionNotFound, name)
+	}
+
+	supported := slices.ContainsFunc(capabilities, func(capability ConnectionCapability) bool {
+		return slices.Contains(conn.GetCapabilities(), capability)
+	})
+	if !supported {
+		return nil, zero, fmt.Errorf("%w (name=%q, operation=%q)",
+			ErrConnectionNotSupported, name, operation)
+	}
+
+	repo, ok := conn.GetRawConnection().(T)
+	if !ok {
+		repo, ok = conn.(T)
+	}
+
+	if !ok {
+		return nil, zero, fmt.Errorf("%w (name=%q, interface=%q)",
+			ErrInterfaceNotImplemented, name, reflect.TypeFor[T]().Name())
+	}
+
+	return conn, repo, nil
+}
@@ -0,0 +1,70 @@
+package connfx_test
+
+import (
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypedCapabilityAccessors(t *testing.T) {
+	t.Parallel()
+
+	registry := connfx.NewRegistry(
+		connfx.WithLogger(newMockLogger()),
+		connfx.WithDefaultFactories(),
+	)
+	registry.RegisterFactory(&flakyFactory{}) //nolint:exhaustruct
+
+	ctx := t.Context()
+
+	_, err := registry.AddConnection(ctx, "memory", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "inmemory",
+	})
+	require.NoError(t, err)
+
+	_, err = registry.AddConnection(ctx, "flaky", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "flaky",
+	})
+	require.NoError(t, err)
+
+	t.Run("returns the requested interface", func(t *testing.T) {
+		t.Parallel()
+
+		cache, err := connfx.GetCache(registry, "memory")
+		require.NoError(t, err)
+		require.NoError(t, cache.SetWithExpiration(ctx, "key", []byte("value"), 0))
+
+		queue, err := connfx.GetQueue(registry, "memory")
+		require.NoError(t, err)
+		require.NoError(t, queue.Publish(ctx, "events", []byte("payload")))
+
+		stream, err := connfx.GetStream(registry, "memory")
+		require.NoError(t, err)
+
+		info, err := stream.StreamInfo(ctx, "events")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), info.Length)
+	})
+
+	t.Run("missing capability", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := connfx.GetCache(registry, "flaky")
+		require.ErrorIs(t, err, connfx.ErrConnectionNotSupported)
+
+		_, err = connfx.GetObjectStorage(registry, "memory")
+		require.ErrorIs(t, err, connfx.ErrConnectionNotSupported)
+	})
+
+	t.Run("missing connection", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := connfx.GetStream(registry, "missing")
+		require.ErrorIs(t, err, connfx.ErrConnectionNotFound)
+
+		_, err = connfx.GetCache(nil, "memory")
+		require.ErrorIs(t, err, connfx.ErrRegistryIsNil)
+	})
+}
@@ -0,0 +1,289 @@
+package connfx
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const DefaultCertificateWatchInterval = 30 * time.Second
+
+var (
+	ErrCertificateWatcherAlreadyRunning = errors.New("certificate watcher is already running")
+	ErrInvalidCertificateWatchInterval  = errors.New("certificate watch interval must be positive")
+	ErrFailedToLoadCA                   = errors.New("failed to load CA certificate")
+	ErrIncompleteCertificateFiles       = errors.New("cert_file and key_file must be set together")
+	ErrServerCertificateNotVerified     = errors.New("server certificate could not be verified")
+)
+
+// CertificateReloader is implemented by connections that read their TLS client
+// certificate or CA bundle from files. ReloadCertificates re-reads the files when they
+// have changed and reports whether anything was reloaded. On failure the previously
+// loaded certificates stay in use.
+type CertificateReloader interface {
+	ReloadCertificates() (bool, error)
+}
+
+// StartCertificateWatcher checks the certificate files of every connection implementing
+// CertificateReloader at the given interval, so short-lived certificates can be rotated
+// on disk without restarting the process. New TLS handshakes use the reloaded files.
+func (registry *Registry) StartCertificateWatcher(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return ErrInvalidCertificateWatchInterval
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if registry.certWatcher != nil {
+		return ErrCertificateWatcherAlreadyRunning
+	}
+
+	watcherCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	watcher := &certificateWatcher{
+		registry: registry,
+		interval: interval,
+		cancel:   cancel,
+		wg:       sync.WaitGroup{},
+	}
+
+	registry.certWatcher = watcher
+
+	watcher.wg.Add(1)
+
+	go watcher.run(watcherCtx)
+
+	registry.logger.InfoContext(
+		ctx,
+		"certificate watcher started",
+		slog.Duration("interval", interval),
+	)
+
+	return nil
+}
+
+// StopCertificateWatcher stops the certificate watcher. It is safe to call when it is
+// not running.
+func (registry *Registry) StopCertificateWatcher() {
+	registry.mu.Lock()
+	watcher := registry.certWatcher
+	registry.certWatcher = nil
+	registry.mu.Unlock()
+
+	if watcher == nil {
+		return
+	}
+
+	watcher.cancel()
+	watcher.wg.Wait()
+}
+
+type certificateWatcher struct {
+	registry *Registry
+	cancel   context.CancelFunc
+	interval time.Duration
+	wg       sync.WaitGroup
+}
+
+func (w *certificateWatcher) run(ctx context.Context) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.check(ctx)
+		}
+	}
+}
+
+func (w *certificateWatcher) check(ctx context.Context) {
+	w.registry.mu.RLock()
+	connections := maps.Clone(w.registry.connections)
+	w.registry.mu.RUnlock()
+
+	for name, conn := range connections {
+		reloader, ok := conn.(CertificateReloader)
+		if !ok {
+			continue
+		}
+
+		reloaded, err := reloader.ReloadCertificates()
+		if err != nil {
+			w.registry.logger.ErrorContext(
+				ctx,
+				"failed to reload certificates, keeping the current ones",
+				slog.String("error", err.Error()),
+				slog.String("name", name),
+			)
+
+			continue
+		}
+
+		if reloaded {
+			w.registry.logger.InfoContext(
+				ctx,
+				"reloaded connection certificates",
+				slog.String("name", name),
+				slog.String("protocol", conn.GetProtocol()),
+			)
+		}
+	}
+}
+
+// certificateFiles serves a client certificate and CA pool loaded from files and
+// reloads them when the files change.
+type certificateFiles struct {
+	cert     atomic.Pointer[tls.Certificate]
+	roots    atomic.Pointer[x509.CertPool]
+	stamps   map[string]time.Time
+	certFile string
+	keyFile  string
+	caFile   string
+	mu       sync.Mutex
+}
+
+// newCertificateFiles loads the files referenced by a target. It returns nil when the
+// target references none.
+func newCertificateFiles(certFile, keyFile, caFile string) (*certificateFiles, error) {
+	if certFile == "" && keyFile == "" && caFile == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	if (certFile == "") != (keyFile == "") {
+		return nil, fmt.Errorf("%w (cert_file=%q, key_file=%q)",
+			ErrIncompleteCertificateFiles, certFile, keyFile)
+	}
+
+	files := &certificateFiles{
+		cert:     atomic.Pointer[tls.Certificate]{},
+		roots:    atomic.Pointer[x509.CertPool]{},
+		stamps:   make(map[string]time.Time),
+		certFile: certFile,
+		keyFile:  keyFile,
+		caFile:   caFile,
+		mu:       sync.Mutex{},
+	}
+
+	if _, err := files.reload(); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// reload re-reads the files if any of their modification times changed.
+func (f *certificateFiles) reload() (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stamps := make(map[string]time.Time, len(f.stamps))
+	changed := false
+
+	for _, path := range []string{f.certFile, f.keyFile, f.caFile} {
+		if path == "" {
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return false, fmt.Errorf("%w (file=%q): %w", ErrFailedToLoadCertificate, path, err)
+		}
+
+		stamps[path] = info.ModTime()
+		changed = changed || !info.ModTime().Equal(f.stamps[path])
+	}
+
+	if !changed {
+		return false, nil
+	}
+
+	if f.certFile != "" {
+		cert, err := tls.LoadX509KeyPair(f.certFile, f.keyFile)
+		if err != nil {
+			return false, fmt.Errorf("%w (cert_file=%q, key_file=%q): %w",
+				ErrFailedToLoadCertificate, f.certFile, f.keyFile, err)
+		}
+
+		f.cert.Store(&cert)
+	}
+
+	if f.caFile != "" {
+		pem, err := os.ReadFile(f.caFile)
+		if err != nil {
+			return false, fmt.Errorf("%w (ca_file=%q): %w", ErrFailedToLoadCA, f.caFile, err)
+		}
+
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return false, fmt.Errorf("%w (ca_file=%q): no certificates found", ErrFailedToLoadCA, f.caFile)
+		}
+
+		f.roots.Store(roots)
+	}
+
+	f.stamps = stamps
+
+	return true, nil
+}
+
+// clientConfig returns a copy of base that presents the current client certificate and
+// verifies servers against the current CA pool at every handshake.
+func (f *certificateFiles) clientConfig(base *tls.Config) *tls.Config {
+	config := &tls.Config{} //nolint:exhaustruct,gosec
+	if base != nil {
+		config = base.Clone()
+	}
+
+	if f == nil {
+		return config
+	}
+
+	if f.certFile != "" {
+		config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return f.cert.Load(), nil
+		}
+	}
+
+	if f.caFile != "" && !config.InsecureSkipVerify {
+		// The standard verification would pin RootCAs at build time; verify against the
+		// pool that is current at handshake time instead.
+		config.InsecureSkipVerify = true //nolint:gosec
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return fmt.Errorf("%w: no certificate presented", ErrServerCertificateNotVerified)
+			}
+
+			options := x509.VerifyOptions{ //nolint:exhaustruct
+				Roots:         f.roots.Load(),
+				DNSName:       state.ServerName,
+				Intermediates: x509.NewCertPool(),
+			}
+
+			for _, cert := range state.PeerCertificates[1:] {
+				options.Intermediates.AddCert(cert)
+			}
+
+			if _, err := state.PeerCertificates[0].Verify(options); err != nil {
+				return fmt.Errorf("%w: %w", ErrServerCertificateNotVerified, err)
+			}
+
+			return nil
+		}
+	}
+
+	return config
+}
@@ -0,0 +1,187 @@
+package connfx_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeClientCertificate writes a self-signed client certificate and key with the given
+// common name, stamped with the given modification time.
+func writeClientCertificate(t *testing.T, dir, commonName string, modTime time.Time) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{ //nolint:exhaustruct
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName}, //nolint:exhaustruct
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "client.crt")
+	keyFile := filepath.Join(dir, "client.key")
+
+	require.NoError(t, os.WriteFile(certFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)) //nolint:exhaustruct
+	require.NoError(t, os.WriteFile(keyFile,
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600)) //nolint:exhaustruct
+
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+
+	return certFile, keyFile
+}
+
+func TestHTTPConnection_ReloadCertificates(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu        sync.Mutex
+		presented []string
+	)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) > 0 {
+			mu.Lock()
+			presented = append(presented, r.TLS.PeerCertificates[0].Subject.CommonName)
+			mu.Unlock()
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert} //nolint:exhaustruct,gosec
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(caFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600)) //nolint:exhaustruct
+
+	certFile, keyFile := writeClientCertificate(t, dir, "first", time.Now().Add(-time.Minute))
+
+	registry := connfx.NewRegistry(
+		connfx.WithLogger(newMockLogger()),
+		connfx.WithDefaultFactories(),
+	)
+
+	ctx := t.Context()
+
+	conn, err := registry.AddConnection(ctx, "api", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "https",
+		URL:      server.URL,
+		TLS:      true,
+		CertFile: certFile,
+		KeyFile:  keyFile,
+		CAFile:   caFile,
+	})
+	require.NoError(t, err)
+
+	reloader, ok := conn.(connfx.CertificateReloader)
+	require.True(t, ok)
+
+	reloaded, err := reloader.ReloadCertificates()
+	require.NoError(t, err)
+	assert.False(t, reloaded, "unchanged files are not reloaded")
+
+	writeClientCertificate(t, dir, "second", time.Now())
+
+	reloaded, err = reloader.ReloadCertificates()
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+
+	status := conn.HealthCheck(ctx)
+	require.NoError(t, status.Error)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.NotEmpty(t, presented)
+	assert.Equal(t, "first", presented[0])
+	assert.Equal(t, "second", presented[len(presented)-1])
+}
+
+func TestHTTPConnection_ReloadCertificatesKeepsCurrentOnError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	certFile, keyFile := writeClientCertificate(t, dir, "client", time.Now().Add(-time.Minute))
+
+	registry := connfx.NewRegistry(
+		connfx.WithLogger(newMockLogger()),
+		connfx.WithDefaultFactories(),
+	)
+
+	ctx := t.Context()
+
+	conn, err := registry.AddConnection(ctx, "api", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol:      "https",
+		URL:           server.URL,
+		TLSSkipVerify: true,
+		CertFile:      certFile,
+		KeyFile:       keyFile,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(keyFile, []byte("not a key"), 0o600))
+
+	reloaded, err := conn.(connfx.CertificateReloader).ReloadCertificates() //nolint:forcetypeassert
+	require.ErrorIs(t, err, connfx.ErrFailedToLoadCertificate)
+	assert.False(t, reloaded)
+
+	status := conn.HealthCheck(ctx)
+	assert.NoError(t, status.Error)
+}
+
+func TestRegistry_StartCertificateWatcher(t *testing.T) {
+	t.Parallel()
+
+	registry := connfx.NewRegistry(connfx.WithLogger(newMockLogger()))
+
+	ctx := t.Context()
+
+	err := registry.StartCertificateWatcher(ctx, 0)
+	require.ErrorIs(t, err, connfx.ErrInvalidCertificateWatchInterval)
+
+	require.NoError(t, registry.StartCertificateWatcher(ctx, time.Minute))
+
+	err = registry.StartCertificateWatcher(ctx, time.Minute)
+	require.ErrorIs(t, err, connfx.ErrCertificateWatcherAlreadyRunning)
+
+	registry.StopCertificateWatcher()
+	registry.StopCertificateWatcher()
+
+	require.NoError(t, registry.StartCertificateWatcher(ctx, time.Minute))
+	require.NoError(t, registry.Close(ctx))
+}
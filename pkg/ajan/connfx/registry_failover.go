@@ -0,0 +1,484 @@
+package connfx
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	FailoverPolicyFailover   = "failover"
+	FailoverPolicyRoundRobin = "round_robin"
+
+	primaryEndpointName = "primary"
+)
+
+var (
+	ErrUnknownFailoverPolicy  = errors.New("unknown failover policy")
+	ErrNoEndpointAvailable    = errors.New("no endpoint of the failover group is available")
+	ErrFailedToCloseEndpoints = errors.New("failed to close failover endpoints")
+)
+
+// FailoverConnection groups several endpoints of the same target behind one connection.
+// GetRawConnection routes to a healthy endpoint according to the group's policy, so
+// callers of GetNamed and GetTypedConnection transparently move to a replica when the
+// primary's health check fails.
+type FailoverConnection struct {
+	logger    Logger
+	factory   ConnectionFactory
+	policy    string
+	protocol  string
+	endpoints []*failoverEndpoint
+	next      atomic.Uint64 // round-robin cursor
+	active    atomic.Int32  // index of the last endpoint routed to, -1 when none
+	mu        sync.RWMutex
+}
+
+// EndpointStatus describes one endpoint of a failover group.
+type EndpointStatus struct {
+	Name   string
+	State  ConnectionState
+	Active bool
+}
+
+// GetBehaviors returns the behaviors of the group's endpoints.
+func (fc *FailoverConnection) GetBehaviors() []ConnectionBehavior {
+	if conn := fc.anyConnection(); conn != nil {
+		return conn.GetBehaviors()
+	}
+
+	return nil
+}
+
+// GetCapabilities returns the capabilities of the group's endpoints.
+func (fc *FailoverConnection) GetCapabilities() []ConnectionCapability {
+	if conn := fc.anyConnection(); conn != nil {
+		return conn.GetCapabilities()
+	}
+
+	return nil
+}
+
+// GetProtocol returns the protocol shared by the group's endpoints.
+func (fc *FailoverConnection) GetProtocol() string {
+	return fc.protocol
+}
+
+// GetState returns the best state among the group's endpoints.
+func (fc *FailoverConnection) GetState() ConnectionState {
+	fc.mu.RLock()
+	defer fc.mu.RUnlock()
+
+	best := ConnectionStateError
+	for _, endpoint := range fc.endpoints {
+		if endpoint.conn == nil {
+			continue
+		}
+
+		state := endpoint.conn.GetState()
+		if stateRank(state) > stateRank(best) {
+			best = state
+		}
+	}
+
+	return best
+}
+
+// HealthCheck checks every endpoint, re-dials endpoints that could not be created and
+// reports the group as healthy while at least one endpoint is.
+func (fc *FailoverConnection) HealthCheck(ctx context.Context) *HealthStatus {
+	start := time.Now()
+
+	fc.redialMissing(ctx)
+
+	fc.mu.RLock()
+	endpoints := make([]failoverEndpoint, len(fc.endpoints))
+	for i, endpoint := range fc.endpoints {
+		endpoints[i] = *endpoint
+	}
+	fc.mu.RUnlock()
+
+	var (
+		wg       sync.WaitGroup
+		statuses = make([]*HealthStatus, len(endpoints))
+	)
+
+	for i, endpoint := range endpoints {
+		if endpoint.conn == nil {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			statuses[i] = endpoint.conn.HealthCheck(ctx)
+		}()
+	}
+
+	wg.Wait()
+
+	status := &HealthStatus{
+		Timestamp: time.Now(),
+		Error:     nil,
+		Message:   "",
+		Latency:   0,
+		State:     ConnectionStateError,
+	}
+
+	var (
+		healthy int
+		errs    []error
+	)
+
+	for i, endpointStatus := range statuses {
+		if endpointStatus == nil {
+			errs = append(errs, fmt.Errorf("%s: %w", endpoints[i].name, endpoints[i].lastErr))
+
+			continue
+		}
+
+		if isRoutable(endpointStatus.State) {
+			healthy++
+		} else if endpointStatus.Error != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", endpoints[i].name, endpointStatus.Error))
+		}
+
+		if stateRank(endpointStatus.State) > stateRank(status.State) {
+			status.State = endpointStatus.State
+		}
+	}
+
+	previous := fc.active.Load()
+	active := fc.selectEndpoint(false)
+
+	if previous != int32(active) && active >= 0 && previous >= 0 { //nolint:gosec
+		fc.logger.WarnContext(
+			ctx,
+			"failover group switched endpoint",
+			slog.String("protocol", fc.protocol),
+			slog.String("from", endpoints[previous].name),
+			slog.String("to", endpoints[active].name),
+		)
+	}
+
+	status.Latency = time.Since(start)
+	status.Message = fmt.Sprintf("%d/%d endpoints healthy (policy=%s)", healthy, len(endpoints), fc.policy)
+
+	if healthy == 0 {
+		status.Error = fmt.Errorf("%w: %w", ErrNoEndpointAvailable, errors.Join(errs...))
+	}
+
+	return status
+}
+
+// Close closes every endpoint of the group.
+func (fc *FailoverConnection) Close(ctx context.Context) error {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	var errs []error
+
+	for _, endpoint := range fc.endpoints {
+		if endpoint.conn == nil {
+			continue
+		}
+
+		if err := endpoint.conn.Close(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", endpoint.name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrFailedToCloseEndpoints, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// GetRawConnection returns the raw connection of the endpoint selected by the policy.
+func (fc *FailoverConnection) GetRawConnection() any {
+	conn := fc.Current()
+	if conn == nil {
+		return nil
+	}
+
+	return conn.GetRawConnection()
+}
+
+// Current returns the endpoint connection selected by the policy, or nil when no
+// endpoint is available.
+func (fc *FailoverConnection) Current() Connection { //nolint:ireturn
+	index := fc.selectEndpoint(fc.policy == FailoverPolicyRoundRobin)
+	if index < 0 {
+		return nil
+	}
+
+	fc.mu.RLock()
+	defer fc.mu.RUnlock()
+
+	return fc.endpoints[index].conn
+}
+
+// GetEndpoints returns the state of each endpoint in priority order.
+func (fc *FailoverConnection) GetEndpoints() []EndpointStatus {
+	active := fc.active.Load()
+
+	fc.mu.RLock()
+	defer fc.mu.RUnlock()
+
+	statuses := make([]EndpointStatus, 0, len(fc.endpoints))
+	for i, endpoint := range fc.endpoints {
+		state := ConnectionStateNotInitialized
+		if endpoint.conn != nil {
+			state = endpoint.conn.GetState()
+		}
+
+		statuses = append(statuses, EndpointStatus{
+			Name:   endpoint.name,
+			State:  state,
+			Active: int32(i) == active, //nolint:gosec
+		})
+	}
+
+	return statuses
+}
+
+type failoverEndpoint struct {
+	conn    Connection
+	lastErr error
+	config  *ConfigTarget
+	name    string
+}
+
+// newFailoverConnection dials every endpoint of the group. Endpoints that fail to dial are
+// retried on health checks; creation fails only when none of them could be dialed.
+func newFailoverConnection(
+	ctx context.Context,
+	logger Logger,
+	factory ConnectionFactory,
+	config *ConfigTarget,
+) (*FailoverConnection, error) {
+	policy := config.Policy
+	if policy == "" {
+		policy = FailoverPolicyFailover
+	}
+
+	if policy != FailoverPolicyFailover && policy != FailoverPolicyRoundRobin {
+		return nil, fmt.Errorf("%w (policy=%q)", ErrUnknownFailoverPolicy, config.Policy)
+	}
+
+	names := slices.Collect(maps.Keys(config.Endpoints))
+	slices.SortFunc(names, func(a, b string) int {
+		switch {
+		case a == primaryEndpointName:
+			return -1
+		case b == primaryEndpointName:
+			return 1
+		default:
+			return strings.Compare(a, b)
+		}
+	})
+
+	fc := &FailoverConnection{
+		logger:    logger,
+		factory:   factory,
+		policy:    policy,
+		protocol:  config.Protocol,
+		endpoints: make([]*failoverEndpoint, 0, len(names)),
+		next:      atomic.Uint64{},
+		active:    atomic.Int32{},
+		mu:        sync.RWMutex{},
+	}
+	fc.active.Store(-1)
+
+	var (
+		connected int
+		errs      []error
+	)
+
+	for _, name := range names {
+		endpointConfig := mergeEndpointConfig(config, config.Endpoints[name])
+		endpoint := &failoverEndpoint{
+			conn:    nil,
+			lastErr: nil,
+			config:  endpointConfig,
+			name:    name,
+		}
+
+		conn, err := factory.CreateConnection(ctx, endpointConfig)
+		if err != nil {
+			endpoint.lastErr = err
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+
+			logger.WarnContext(
+				ctx,
+				"failed to create failover endpoint",
+				slog.String("error", err.Error()),
+				slog.String("endpoint", name),
+				slog.String("protocol", config.Protocol),
+			)
+		} else {
+			endpoint.conn = conn
+			connected++
+		}
+
+		fc.endpoints = append(fc.endpoints, endpoint)
+	}
+
+	if connected == 0 {
+		return nil, fmt.Errorf("%w: %w", ErrNoEndpointAvailable, errors.Join(errs...))
+	}
+
+	return fc, nil
+}
+
+// selectEndpoint returns the index of the endpoint to route to, or -1.
+func (fc *FailoverConnection) selectEndpoint(rotate bool) int {
+	fc.mu.RLock()
+	defer fc.mu.RUnlock()
+
+	count := len(fc.endpoints)
+	start := 0
+
+	if rotate {
+		start = int(fc.next.Add(1)-1) % count //nolint:gosec
+	}
+
+	fallback := -1
+
+	for offset := range count {
+		index := (start + offset) % count
+		endpoint := fc.endpoints[index]
+
+		if endpoint.conn == nil {
+			continue
+		}
+
+		if isRoutable(endpoint.conn.GetState()) {
+			fc.active.Store(int32(index)) //nolint:gosec
+
+			return index
+		}
+
+		// Keep the first dialed endpoint as a last resort, so callers still get an error
+		// from the driver instead of a nil connection.
+		if fallback < 0 {
+			fallback = index
+		}
+	}
+
+	fc.active.Store(int32(fallback)) //nolint:gosec
+
+	return fallback
+}
+
+// redialMissing retries endpoints that could not be created, without holding the lock
+// while dialing so routing to the other endpoints is not blocked.
+func (fc *FailoverConnection) redialMissing(ctx context.Context) {
+	fc.mu.RLock()
+	missing := make([]*failoverEndpoint, 0, len(fc.endpoints))
+	for _, endpoint := range fc.endpoints {
+		if endpoint.conn == nil {
+			missing = append(missing, endpoint)
+		}
+	}
+	fc.mu.RUnlock()
+
+	for _, endpoint := range missing {
+		conn, err := fc.factory.CreateConnection(ctx, endpoint.config)
+
+		fc.mu.Lock()
+		if err != nil {
+			endpoint.lastErr = err
+		} else if endpoint.conn == nil {
+			endpoint.conn = conn
+			endpoint.lastErr = nil
+			conn = nil
+		}
+		fc.mu.Unlock()
+
+		// Another health check won the race; drop the duplicate.
+		if conn != nil {
+			_ = conn.Close(ctx)
+		}
+	}
+}
+
+func (fc *FailoverConnection) anyConnection() Connection { //nolint:ireturn
+	fc.mu.RLock()
+	defer fc.mu.RUnlock()
+
+	for _, endpoint := range fc.endpoints {
+		if endpoint.conn != nil {
+			return endpoint.conn
+		}
+	}
+
+	return nil
+}
+
+// mergeEndpointConfig returns the endpoint configuration with unset fields inherited from
+// the group's target.
+func mergeEndpointConfig(parent *ConfigTarget, endpoint ConfigTarget) *ConfigTarget {
+	merged := *parent
+	merged.Endpoints = nil
+	merged.Policy = ""
+	merged.Lazy = false
+
+	merged.Properties = make(map[string]any, len(parent.Properties)+len(endpoint.Properties))
+	maps.Copy(merged.Properties, parent.Properties)
+	maps.Copy(merged.Properties, endpoint.Properties)
+
+	merged.Protocol = cmp.Or(endpoint.Protocol, parent.Protocol)
+	merged.DSN = cmp.Or(endpoint.DSN, parent.DSN)
+	merged.URL = cmp.Or(endpoint.URL, parent.URL)
+	merged.Host = cmp.Or(endpoint.Host, parent.Host)
+	merged.CertFile = cmp.Or(endpoint.CertFile, parent.CertFile)
+	merged.KeyFile = cmp.Or(endpoint.KeyFile, parent.KeyFile)
+	merged.CAFile = cmp.Or(endpoint.CAFile, parent.CAFile)
+	merged.Port = cmp.Or(endpoint.Port, parent.Port)
+	merged.Timeout = cmp.Or(endpoint.Timeout, parent.Timeout)
+	merged.TLS = endpoint.TLS || parent.TLS
+	merged.TLSSkipVerify = endpoint.TLSSkipVerify || parent.TLSSkipVerify
+
+	return &merged
+}
+
+// isRoutable reports whether a connection in the given state can serve requests.
+func isRoutable(state ConnectionState) bool {
+	return state == ConnectionStateReady ||
+		state == ConnectionStateLive ||
+		state == ConnectionStateConnected
+}
+
+// stateRank orders states from least to most usable.
+func stateRank(state ConnectionState) int {
+	switch state {
+	case ConnectionStateReady:
+		return 6 //nolint:mnd
+	case ConnectionStateLive:
+		return 5 //nolint:mnd
+	case ConnectionStateConnected:
+		return 4 //nolint:mnd
+	case ConnectionStateReconnecting:
+		return 3 //nolint:mnd
+	case ConnectionStateNotInitialized:
+		return 2 //nolint:mnd
+	case ConnectionStateDisconnected:
+		return 1
+	case ConnectionStateError:
+		return 0
+	default:
+		return 0
+	}
+}
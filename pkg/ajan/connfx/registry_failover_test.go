@@ -0,0 +1,146 @@
+package connfx_test
+
+import (
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFailoverTestTarget(policy string) *connfx.ConfigTarget {
+	return &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "flaky",
+		Policy:   policy,
+		Endpoints: map[string]connfx.ConfigTarget{
+			"replica":  {Host: "replica.local"},  //nolint:exhaustruct
+			"primary":  {Host: "primary.local"},  //nolint:exhaustruct
+			"replica2": {Host: "replica2.local"}, //nolint:exhaustruct
+		},
+	}
+}
+
+func TestRegistry_FailoverGroup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("routes to a healthy replica when the primary fails", func(t *testing.T) {
+		t.Parallel()
+
+		registry := connfx.NewRegistry(connfx.WithLogger(newMockLogger()))
+		factory := &flakyFactory{} //nolint:exhaustruct
+		registry.RegisterFactory(factory)
+
+		ctx := t.Context()
+
+		_, err := registry.AddConnection(ctx, "postgres", newFailoverTestTarget(""))
+		require.NoError(t, err)
+		require.Len(t, factory.created, 3)
+
+		primary, replica := factory.created[0], factory.created[1]
+
+		conn := registry.GetNamed("postgres")
+		require.NotNil(t, conn)
+		assert.Same(t, primary, conn.GetRawConnection())
+
+		primary.state.Store(int32(connfx.ConnectionStateError))
+
+		status := conn.HealthCheck(ctx)
+		require.NoError(t, status.Error)
+		assert.Equal(t, connfx.ConnectionStateReady, status.State)
+		assert.Same(t, replica, conn.GetRawConnection())
+
+		group, ok := conn.(*connfx.FailoverConnection)
+		require.True(t, ok)
+
+		endpoints := group.GetEndpoints()
+		require.Len(t, endpoints, 3)
+		assert.Equal(t, "primary", endpoints[0].Name)
+		assert.Equal(t, connfx.ConnectionStateError, endpoints[0].State)
+		assert.True(t, endpoints[1].Active)
+
+		// Back to the primary once it recovers.
+		primary.state.Store(int32(connfx.ConnectionStateReady))
+		assert.Same(t, primary, conn.GetRawConnection())
+
+		require.NoError(t, registry.Close(ctx))
+
+		for _, created := range factory.created {
+			assert.True(t, created.closed.Load())
+		}
+	})
+
+	t.Run("round robin rotates over healthy endpoints", func(t *testing.T) {
+		t.Parallel()
+
+		registry := connfx.NewRegistry(connfx.WithLogger(newMockLogger()))
+		factory := &flakyFactory{} //nolint:exhaustruct
+		registry.RegisterFactory(factory)
+
+		_, err := registry.AddConnection(
+			t.Context(),
+			"postgres",
+			newFailoverTestTarget(connfx.FailoverPolicyRoundRobin),
+		)
+		require.NoError(t, err)
+
+		factory.created[1].state.Store(int32(connfx.ConnectionStateDisconnected))
+
+		conn := registry.GetNamed("postgres")
+
+		seen := map[any]int{}
+		for range 4 {
+			seen[conn.GetRawConnection()]++
+		}
+
+		assert.Len(t, seen, 2)
+		assert.NotContains(t, seen, factory.created[1])
+	})
+
+	t.Run("tolerates endpoints that fail to dial", func(t *testing.T) {
+		t.Parallel()
+
+		registry := connfx.NewRegistry(connfx.WithLogger(newMockLogger()))
+		factory := &flakyFactory{} //nolint:exhaustruct
+		factory.failures.Store(1)
+		registry.RegisterFactory(factory)
+
+		ctx := t.Context()
+
+		conn, err := registry.AddConnection(ctx, "postgres", newFailoverTestTarget(""))
+		require.NoError(t, err)
+		require.Len(t, factory.created, 2)
+
+		group, ok := conn.(*connfx.FailoverConnection)
+		require.True(t, ok)
+		assert.Equal(t, connfx.ConnectionStateNotInitialized, group.GetEndpoints()[0].State)
+		assert.Same(t, factory.created[0], conn.GetRawConnection())
+
+		// The failed primary is re-dialed by the next health check.
+		conn.HealthCheck(ctx)
+		require.Len(t, factory.created, 3)
+		assert.Same(t, factory.created[2], conn.GetRawConnection())
+	})
+
+	t.Run("fails when no endpoint can be dialed", func(t *testing.T) {
+		t.Parallel()
+
+		registry := connfx.NewRegistry(connfx.WithLogger(newMockLogger()))
+		factory := &flakyFactory{} //nolint:exhaustruct
+		factory.failures.Store(3)
+		registry.RegisterFactory(factory)
+
+		_, err := registry.AddConnection(t.Context(), "postgres", newFailoverTestTarget(""))
+		require.ErrorIs(t, err, connfx.ErrFailedToCreateConnection)
+		require.ErrorIs(t, err, connfx.ErrNoEndpointAvailable)
+	})
+
+	t.Run("rejects unknown policies", func(t *testing.T) {
+		t.Parallel()
+
+		registry := connfx.NewRegistry(connfx.WithLogger(newMockLogger()))
+		registry.RegisterFactory(&flakyFactory{}) //nolint:exhaustruct
+
+		_, err := registry.AddConnection(t.Context(), "postgres", newFailoverTestTarget("random"))
+		require.ErrorIs(t, err, connfx.ErrUnknownFailoverPolicy)
+	})
+}
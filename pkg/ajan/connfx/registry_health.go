@@ -0,0 +1,252 @@
+package connfx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"maps"
+	"sync"
+	"time"
+)
+
+var (
+	ErrHealthMonitorAlreadyRunning  = errors.New("health monitor is already running")
+	ErrInvalidHealthMonitorInterval = errors.New("health monitor interval must be positive")
+)
+
+// StateChangeEvent describes a connection moving from one state to another.
+type StateChangeEvent struct {
+	Timestamp time.Time
+	Error     error
+	Name      string
+	Protocol  string
+	Previous  ConnectionState
+	Current   ConnectionState
+	Attempt   uint
+}
+
+// StateChangeHandler receives connection state-change events.
+// Handlers are invoked synchronously from the background monitors and must not block.
+type StateChangeHandler func(event StateChangeEvent)
+
+// ConnectionHealthStats aggregates the health-check results of a single connection.
+type ConnectionHealthStats struct {
+	LastCheck   time.Time
+	LastError   error
+	LastLatency time.Duration
+	MinLatency  time.Duration
+	MaxLatency  time.Duration
+	AvgLatency  time.Duration
+	Checks      uint64
+	Failures    uint64
+	State       ConnectionState
+}
+
+// OnStateChange registers a handler that is notified whenever the health monitor or the
+// reconnection manager observes a connection changing state.
+func (registry *Registry) OnStateChange(handler StateChangeHandler) {
+	registry.stateMu.Lock()
+	defer registry.stateMu.Unlock()
+
+	registry.stateHandlers = append(registry.stateHandlers, handler)
+}
+
+// StartHealthMonitor runs HealthCheck on every connection at the given interval, records
+// latency statistics and notifies OnStateChange subscribers of state transitions.
+// Connections found in ConnectionStateError are handed to the reconnection manager
+// when it is running.
+func (registry *Registry) StartHealthMonitor(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		return ErrInvalidHealthMonitorInterval
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if registry.healthMonitor != nil {
+		return ErrHealthMonitorAlreadyRunning
+	}
+
+	monitorCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	monitor := &healthMonitor{
+		registry: registry,
+		interval: interval,
+		cancel:   cancel,
+		wg:       sync.WaitGroup{},
+	}
+
+	registry.healthMonitor = monitor
+
+	monitor.wg.Add(1)
+
+	go monitor.run(monitorCtx)
+
+	registry.logger.InfoContext(
+		ctx,
+		"health monitor started",
+		slog.Duration("interval", interval),
+	)
+
+	return nil
+}
+
+// StopHealthMonitor stops the health monitor. It is safe to call when it is not running.
+func (registry *Registry) StopHealthMonitor() {
+	registry.mu.Lock()
+	monitor := registry.healthMonitor
+	registry.healthMonitor = nil
+	registry.mu.Unlock()
+
+	if monitor == nil {
+		return
+	}
+
+	monitor.cancel()
+	monitor.wg.Wait()
+}
+
+// GetHealthStats returns a snapshot of the recorded health statistics per connection.
+func (registry *Registry) GetHealthStats() map[string]ConnectionHealthStats {
+	registry.stateMu.RLock()
+	defer registry.stateMu.RUnlock()
+
+	stats := make(map[string]ConnectionHealthStats, len(registry.healthStats))
+	for name, stat := range registry.healthStats {
+		stats[name] = *stat
+	}
+
+	return stats
+}
+
+// recordState stores the latest known state of a connection and notifies subscribers
+// when it differs from the previously recorded one.
+func (registry *Registry) recordState(
+	name string,
+	protocol string,
+	state ConnectionState,
+	err error,
+	attempt uint,
+) {
+	registry.stateMu.Lock()
+	previous, known := registry.states[name]
+	registry.states[name] = state
+
+	handlers := make([]StateChangeHandler, len(registry.stateHandlers))
+	copy(handlers, registry.stateHandlers)
+	registry.stateMu.Unlock()
+
+	if known && previous == state {
+		return
+	}
+
+	event := StateChangeEvent{
+		Timestamp: time.Now(),
+		Error:     err,
+		Name:      name,
+		Protocol:  protocol,
+		Previous:  previous,
+		Current:   state,
+		Attempt:   attempt,
+	}
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+func (registry *Registry) recordHealth(name string, status *HealthStatus) {
+	registry.stateMu.Lock()
+	defer registry.stateMu.Unlock()
+
+	stats, exists := registry.healthStats[name]
+	if !exists {
+		stats = &ConnectionHealthStats{} //nolint:exhaustruct
+		registry.healthStats[name] = stats
+	}
+
+	stats.Checks++
+	stats.LastCheck = status.Timestamp
+	stats.LastError = status.Error
+	stats.LastLatency = status.Latency
+	stats.State = status.State
+
+	if status.Error != nil || status.State == ConnectionStateError {
+		stats.Failures++
+	}
+
+	if stats.Checks == 1 || status.Latency < stats.MinLatency {
+		stats.MinLatency = status.Latency
+	}
+
+	if status.Latency > stats.MaxLatency {
+		stats.MaxLatency = status.Latency
+	}
+
+	// Running average, avoids keeping a total that could overflow on long-lived processes.
+	stats.AvgLatency += (status.Latency - stats.AvgLatency) / time.Duration(stats.Checks) //nolint:gosec
+}
+
+func (registry *Registry) forgetState(name string) {
+	registry.stateMu.Lock()
+	defer registry.stateMu.Unlock()
+
+	delete(registry.states, name)
+	delete(registry.healthStats, name)
+}
+
+type healthMonitor struct {
+	registry *Registry
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	interval time.Duration
+}
+
+func (m *healthMonitor) run(ctx context.Context) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.check(ctx)
+		}
+	}
+}
+
+func (m *healthMonitor) check(ctx context.Context) {
+	checkCtx, cancel := context.WithTimeout(ctx, m.interval)
+	defer cancel()
+
+	results := m.registry.HealthCheck(checkCtx)
+
+	m.registry.mu.RLock()
+	connections := make(map[string]Connection, len(m.registry.connections))
+	maps.Copy(connections, m.registry.connections)
+	reconnector := m.registry.reconnector
+	m.registry.mu.RUnlock()
+
+	for name, status := range results {
+		conn, exists := connections[name]
+		if !exists {
+			// Removed while the check was running.
+			continue
+		}
+
+		// The reconnection manager owns the state while it is recovering the connection.
+		if reconnector != nil && reconnector.isInFlight(name) {
+			continue
+		}
+
+		m.registry.recordHealth(name, status)
+		m.registry.recordState(name, conn.GetProtocol(), status.State, status.Error, 0)
+
+		if status.State == ConnectionStateError && reconnector != nil {
+			reconnector.trigger(name, conn)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package connfx_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_HealthMonitor(t *testing.T) {
+	t.Parallel()
+
+	registry := connfx.NewRegistry(connfx.WithLogger(newMockLogger()))
+
+	factory := &flakyFactory{} //nolint:exhaustruct
+	registry.RegisterFactory(factory)
+
+	var (
+		events   []connfx.StateChangeEvent
+		eventsMu sync.Mutex
+	)
+
+	registry.OnStateChange(func(event connfx.StateChangeEvent) {
+		eventsMu.Lock()
+		events = append(events, event)
+		eventsMu.Unlock()
+	})
+
+	ctx := t.Context()
+
+	conn, err := registry.AddConnection(ctx, "cache", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "flaky",
+	})
+	require.NoError(t, err)
+
+	require.ErrorIs(
+		t,
+		registry.StartHealthMonitor(ctx, 0),
+		connfx.ErrInvalidHealthMonitorInterval,
+	)
+	require.NoError(t, registry.StartHealthMonitor(ctx, 10*time.Millisecond))
+	t.Cleanup(registry.StopHealthMonitor)
+
+	require.ErrorIs(
+		t,
+		registry.StartHealthMonitor(ctx, time.Second),
+		connfx.ErrHealthMonitorAlreadyRunning,
+	)
+
+	require.Eventually(t, func() bool {
+		return registry.GetHealthStats()["cache"].Checks >= 2
+	}, time.Second, 5*time.Millisecond)
+
+	conn.(*flakyConnection).state.Store(int32(connfx.ConnectionStateError)) //nolint:forcetypeassert
+
+	require.Eventually(t, func() bool {
+		eventsMu.Lock()
+		defer eventsMu.Unlock()
+
+		return len(events) == 2
+	}, time.Second, 5*time.Millisecond)
+
+	eventsMu.Lock()
+	assert.Equal(t, connfx.ConnectionStateReady, events[0].Current)
+	assert.Equal(t, connfx.ConnectionStateReady, events[1].Previous)
+	assert.Equal(t, connfx.ConnectionStateError, events[1].Current)
+	assert.Equal(t, "cache", events[1].Name)
+	assert.Equal(t, "flaky", events[1].Protocol)
+	eventsMu.Unlock()
+
+	stats := registry.GetHealthStats()["cache"]
+	assert.Equal(t, connfx.ConnectionStateError, stats.State)
+	assert.Positive(t, stats.Failures)
+	assert.LessOrEqual(t, stats.MinLatency, stats.MaxLatency)
+
+	registry.StopHealthMonitor()
+
+	require.NoError(t, registry.RemoveConnection(ctx, "cache"))
+	assert.NotContains(t, registry.GetHealthStats(), "cache")
+}
@@ -0,0 +1,324 @@
+package connfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpclient"
+)
+
+const (
+	DefaultReconnectCheckInterval = 5 * time.Second
+	DefaultReconnectMaxAttempts   = 10
+	DefaultReconnectMinBackoff    = 500 * time.Millisecond
+	DefaultReconnectMaxBackoff    = 30 * time.Second
+)
+
+var (
+	ErrAutoReconnectNotConfigured  = errors.New("auto-reconnect is not configured")
+	ErrAutoReconnectAlreadyRunning = errors.New("auto-reconnect is already running")
+	ErrReconnectAttemptsExhausted  = errors.New("reconnect attempts exhausted")
+	ErrConnectionReplaced          = errors.New("connection was replaced or removed during reconnect")
+)
+
+// ReconnectConfig configures the registry's background reconnection manager.
+type ReconnectConfig struct {
+	// Backoff controls the delay between reconnect attempts. MaxAttempts bounds a single
+	// reconnect round; a connection still in error on the next check starts a new round.
+	Backoff httpclient.RetryStrategyConfig
+
+	// CheckInterval is how often connections are health-checked for state changes.
+	CheckInterval time.Duration
+	// CheckTimeout bounds a single health check. Defaults to CheckInterval.
+	CheckTimeout time.Duration
+}
+
+// NewDefaultReconnectConfig returns the default reconnection configuration.
+func NewDefaultReconnectConfig() *ReconnectConfig {
+	return &ReconnectConfig{
+		Backoff: httpclient.RetryStrategyConfig{
+			Enabled:         true,
+			MaxAttempts:     DefaultReconnectMaxAttempts,
+			InitialInterval: DefaultReconnectMinBackoff,
+			MaxInterval:     DefaultReconnectMaxBackoff,
+			Multiplier:      httpclient.DefaultMultiplier,
+			RandomFactor:    httpclient.DefaultRandomFactor,
+		},
+		CheckInterval: DefaultReconnectCheckInterval,
+		CheckTimeout:  DefaultReconnectCheckInterval,
+	}
+}
+
+// StartAutoReconnect starts the background reconnection manager. Connections observed in
+// ConnectionStateError are recreated from their original configuration through their
+// protocol factory, retrying with exponential backoff and jitter.
+func (registry *Registry) StartAutoReconnect(ctx context.Context) error {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if registry.reconnectConfig == nil {
+		return ErrAutoReconnectNotConfigured
+	}
+
+	if registry.reconnector != nil {
+		return ErrAutoReconnectAlreadyRunning
+	}
+
+	managerCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	manager := &reconnectManager{
+		registry: registry,
+		config:   registry.reconnectConfig,
+		strategy: httpclient.NewRetryStrategy(&registry.reconnectConfig.Backoff),
+		ctx:      managerCtx,
+		cancel:   cancel,
+		inFlight: make(map[string]struct{}),
+		wg:       sync.WaitGroup{},
+		mu:       sync.Mutex{},
+	}
+
+	registry.reconnector = manager
+
+	manager.wg.Add(1)
+
+	go manager.run()
+
+	registry.logger.InfoContext(
+		ctx,
+		"auto-reconnect started",
+		slog.Duration("check_interval", registry.reconnectConfig.CheckInterval),
+	)
+
+	return nil
+}
+
+// StopAutoReconnect stops the background reconnection manager and waits for in-flight
+// reconnect attempts to finish. It is safe to call when the manager is not running.
+func (registry *Registry) StopAutoReconnect() {
+	registry.mu.Lock()
+	manager := registry.reconnector
+	registry.reconnector = nil
+	registry.mu.Unlock()
+
+	if manager == nil {
+		return
+	}
+
+	manager.cancel()
+	manager.wg.Wait()
+}
+
+type reconnectManager struct {
+	registry *Registry
+	config   *ReconnectConfig
+	strategy *httpclient.RetryStrategy
+	ctx      context.Context //nolint:containedctx
+	cancel   context.CancelFunc
+	inFlight map[string]struct{}
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+}
+
+func (m *reconnectManager) run() {
+	defer m.wg.Done()
+
+	ctx := m.ctx
+
+	ticker := time.NewTicker(m.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.check(ctx)
+		}
+	}
+}
+
+func (m *reconnectManager) check(ctx context.Context) {
+	m.registry.mu.RLock()
+	connections := make(map[string]Connection, len(m.registry.connections))
+	maps.Copy(connections, m.registry.connections)
+	m.registry.mu.RUnlock()
+
+	for name, conn := range connections {
+		if m.isInFlight(name) {
+			continue
+		}
+
+		status := m.probe(ctx, conn)
+		m.registry.recordState(name, conn.GetProtocol(), status.State, status.Error, 0)
+
+		if status.State == ConnectionStateError {
+			m.startReconnect(ctx, name, conn)
+		}
+	}
+}
+
+func (m *reconnectManager) probe(ctx context.Context, conn Connection) *HealthStatus {
+	timeout := m.config.CheckTimeout
+	if timeout <= 0 {
+		timeout = m.config.CheckInterval
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return conn.HealthCheck(checkCtx)
+}
+
+func (m *reconnectManager) isInFlight(name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, exists := m.inFlight[name]
+
+	return exists
+}
+
+// trigger starts recovering a connection reported broken by another observer, such as
+// the health monitor.
+func (m *reconnectManager) trigger(name string, conn Connection) {
+	if m.ctx.Err() != nil {
+		return
+	}
+
+	m.startReconnect(m.ctx, name, conn)
+}
+
+func (m *reconnectManager) startReconnect(ctx context.Context, name string, conn Connection) {
+	m.mu.Lock()
+	if _, exists := m.inFlight[name]; exists {
+		m.mu.Unlock()
+
+		return
+	}
+
+	m.inFlight[name] = struct{}{}
+	m.mu.Unlock()
+
+	m.wg.Add(1)
+
+	go func() {
+		defer m.wg.Done()
+		defer func() {
+			m.mu.Lock()
+			delete(m.inFlight, name)
+			m.mu.Unlock()
+		}()
+
+		err := m.reconnect(ctx, name, conn)
+		if err != nil && ctx.Err() == nil {
+			m.registry.logger.WarnContext(
+				ctx,
+				"reconnect failed",
+				slog.String("error", err.Error()),
+				slog.String("name", name),
+			)
+		}
+	}()
+}
+
+func (m *reconnectManager) reconnect(ctx context.Context, name string, old Connection) error {
+	m.registry.mu.RLock()
+	config := m.registry.configs[name]
+	var factory ConnectionFactory
+	if config != nil {
+		factory = m.registry.factories[config.Protocol]
+	}
+	m.registry.mu.RUnlock()
+
+	if config == nil {
+		return fmt.Errorf("%w (name=%q)", ErrConnectionNotFound, name)
+	}
+
+	if factory == nil {
+		return fmt.Errorf("%w (protocol=%q)", ErrUnsupportedProtocol, config.Protocol)
+	}
+
+	var lastErr error
+
+	for attempt := uint(0); ; attempt++ {
+		delay := m.strategy.NextBackoff(attempt)
+		if delay == 0 {
+			m.registry.recordState(name, config.Protocol, ConnectionStateError, lastErr, attempt)
+
+			return fmt.Errorf("%w (name=%q, attempts=%d): %w",
+				ErrReconnectAttemptsExhausted, name, attempt, lastErr)
+		}
+
+		m.registry.recordState(name, config.Protocol, ConnectionStateReconnecting, lastErr, attempt+1)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err() //nolint:wrapcheck
+		case <-time.After(delay):
+		}
+
+		conn, err := m.registry.createConnection(ctx, factory, config)
+		if err != nil {
+			lastErr = err
+
+			m.registry.logger.DebugContext(
+				ctx,
+				"reconnect attempt failed",
+				slog.String("error", err.Error()),
+				slog.String("name", name),
+				slog.Uint64("attempt", uint64(attempt+1)),
+			)
+
+			continue
+		}
+
+		if err := m.swap(ctx, name, old, conn); err != nil {
+			return err
+		}
+
+		m.registry.logger.InfoContext(
+			ctx,
+			"connection reconnected",
+			slog.String("name", name),
+			slog.String("protocol", config.Protocol),
+			slog.Uint64("attempts", uint64(attempt+1)),
+		)
+
+		m.registry.recordState(name, config.Protocol, conn.GetState(), nil, attempt+1)
+
+		return nil
+	}
+}
+
+// swap replaces the broken connection with its replacement, unless the registry entry has
+// changed in the meantime (e.g. it was removed or re-added by the application).
+func (m *reconnectManager) swap(ctx context.Context, name string, old Connection, conn Connection) error {
+	m.registry.mu.Lock()
+
+	if m.registry.connections[name] != old {
+		m.registry.mu.Unlock()
+
+		_ = conn.Close(ctx)
+
+		return fmt.Errorf("%w (name=%q)", ErrConnectionReplaced, name)
+	}
+
+	m.registry.connections[name] = conn
+	m.registry.mu.Unlock()
+
+	if err := old.Close(ctx); err != nil {
+		m.registry.logger.DebugContext(
+			ctx,
+			"error closing broken connection",
+			slog.String("error", err.Error()),
+			slog.String("name", name),
+		)
+	}
+
+	return nil
+}
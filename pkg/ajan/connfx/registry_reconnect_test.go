@@ -0,0 +1,177 @@
+package connfx_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errFlakyUnavailable = errors.New("flaky backend unavailable")
+
+type flakyConnection struct {
+	state  atomic.Int32
+	closed atomic.Bool
+}
+
+func (c *flakyConnection) GetBehaviors() []connfx.ConnectionBehavior {
+	return []connfx.ConnectionBehavior{connfx.ConnectionBehaviorStateful}
+}
+
+func (c *flakyConnection) GetCapabilities() []connfx.ConnectionCapability {
+	return []connfx.ConnectionCapability{connfx.ConnectionCapabilityKeyValue}
+}
+
+func (c *flakyConnection) GetProtocol() string {
+	return "flaky"
+}
+
+func (c *flakyConnection) GetState() connfx.ConnectionState {
+	return connfx.ConnectionState(c.state.Load())
+}
+
+func (c *flakyConnection) HealthCheck(_ context.Context) *connfx.HealthStatus {
+	return &connfx.HealthStatus{ //nolint:exhaustruct
+		Timestamp: time.Now(),
+		State:     c.GetState(),
+	}
+}
+
+func (c *flakyConnection) Close(_ context.Context) error {
+	c.closed.Store(true)
+
+	return nil
+}
+
+func (c *flakyConnection) GetRawConnection() any {
+	return c
+}
+
+type flakyFactory struct {
+	created  []*flakyConnection
+	failures atomic.Int32
+	mu       sync.Mutex
+}
+
+func (f *flakyFactory) CreateConnection( //nolint:ireturn
+	_ context.Context,
+	_ *connfx.ConfigTarget,
+) (connfx.Connection, error) {
+	if f.failures.Add(-1) >= 0 {
+		return nil, errFlakyUnavailable
+	}
+
+	conn := &flakyConnection{} //nolint:exhaustruct
+	conn.state.Store(int32(connfx.ConnectionStateReady))
+
+	f.mu.Lock()
+	f.created = append(f.created, conn)
+	f.mu.Unlock()
+
+	return conn, nil
+}
+
+func (f *flakyFactory) GetProtocol() string {
+	return "flaky"
+}
+
+func newReconnectTestConfig() *connfx.ReconnectConfig {
+	config := connfx.NewDefaultReconnectConfig()
+	config.CheckInterval = 10 * time.Millisecond
+	config.Backoff.InitialInterval = 5 * time.Millisecond
+	config.Backoff.MaxInterval = 20 * time.Millisecond
+
+	return config
+}
+
+func TestRegistry_AutoReconnect(t *testing.T) {
+	t.Parallel()
+
+	registry := connfx.NewRegistry(
+		connfx.WithLogger(newMockLogger()),
+		connfx.WithAutoReconnect(newReconnectTestConfig()),
+	)
+
+	factory := &flakyFactory{} //nolint:exhaustruct
+	registry.RegisterFactory(factory)
+
+	var (
+		events   []connfx.StateChangeEvent
+		eventsMu sync.Mutex
+	)
+
+	registry.OnStateChange(func(event connfx.StateChangeEvent) {
+		eventsMu.Lock()
+		events = append(events, event)
+		eventsMu.Unlock()
+	})
+
+	ctx := t.Context()
+
+	conn, err := registry.AddConnection(ctx, "cache", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "flaky",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, registry.StartAutoReconnect(ctx))
+	t.Cleanup(registry.StopAutoReconnect)
+
+	require.ErrorIs(t, registry.StartAutoReconnect(ctx), connfx.ErrAutoReconnectAlreadyRunning)
+
+	// Drop the connection; the first two reconnect attempts fail as well.
+	factory.failures.Store(2)
+	conn.(*flakyConnection).state.Store(int32(connfx.ConnectionStateError)) //nolint:forcetypeassert
+
+	require.Eventually(t, func() bool {
+		current := registry.GetNamed("cache")
+
+		return current != conn && current.GetState() == connfx.ConnectionStateReady
+	}, 2*time.Second, 5*time.Millisecond)
+
+	assert.True(t, conn.(*flakyConnection).closed.Load()) //nolint:forcetypeassert
+
+	require.Eventually(t, func() bool {
+		eventsMu.Lock()
+		defer eventsMu.Unlock()
+
+		return len(events) > 0 && events[len(events)-1].Current == connfx.ConnectionStateReady
+	}, time.Second, 5*time.Millisecond)
+
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+
+	var sawError, sawReconnecting bool
+
+	for _, event := range events {
+		assert.Equal(t, "cache", event.Name)
+
+		switch event.Current { //nolint:exhaustive
+		case connfx.ConnectionStateError:
+			sawError = true
+		case connfx.ConnectionStateReconnecting:
+			sawReconnecting = true
+		}
+	}
+
+	assert.True(t, sawError)
+	assert.True(t, sawReconnecting)
+	assert.Equal(t, uint(3), events[len(events)-1].Attempt)
+}
+
+func TestRegistry_AutoReconnect_NotConfigured(t *testing.T) {
+	t.Parallel()
+
+	registry := connfx.NewRegistry(connfx.WithLogger(newMockLogger()))
+
+	err := registry.StartAutoReconnect(t.Context())
+	require.ErrorIs(t, err, connfx.ErrAutoReconnectNotConfigured)
+
+	// Stopping a manager that never started is a no-op.
+	registry.StopAutoReconnect()
+}
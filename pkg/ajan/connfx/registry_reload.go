@@ -0,0 +1,174 @@
+package connfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+)
+
+const DefaultDrainTimeout = 30 * time.Second
+
+var ErrFailedToApplyConfig = errors.New("failed to apply connection config")
+
+// ConfigDiff reports what ApplyConfig did to each target, by connection name.
+type ConfigDiff struct {
+	Added     []string
+	Updated   []string
+	Removed   []string
+	Unchanged []string
+}
+
+// ApplyConfig brings the registry in line with a reloaded configuration. Targets are
+// compared with the ones from the previous LoadFromConfig or ApplyConfig call:
+// new targets are added, changed targets are reconfigured and dropped targets are
+// removed. Connections added through AddConnection are left alone, and targets scoped
+// to another environment count as dropped.
+//
+// Replaced and removed connections are detached first, so they are no longer handed
+// out, and closed after the drain timeout to let in-flight operations finish.
+// Targets are applied in dependency order. A target that fails to apply keeps its
+// current connection; the other targets are still applied and the failures are
+// returned together.
+func (registry *Registry) ApplyConfig(ctx context.Context, config *Config) (*ConfigDiff, error) {
+	registry.mu.RLock()
+	previous := maps.Clone(registry.applied)
+	registry.mu.RUnlock()
+
+	diff := &ConfigDiff{
+		Added:     nil,
+		Updated:   nil,
+		Removed:   nil,
+		Unchanged: nil,
+	}
+
+	var errs []error
+
+	targets := registry.scopeTargets(ctx, config.Targets)
+
+	order, err := startupOrder(targets)
+	if err != nil {
+		return diff, fmt.Errorf("%w: %w", ErrFailedToApplyConfig, err)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(previous)) {
+		if _, kept := targets[name]; kept {
+			continue
+		}
+
+		registry.detachConnection(ctx, name)
+		diff.Removed = append(diff.Removed, name)
+	}
+
+	for _, name := range order {
+		target := targets[name]
+
+		current, known := previous[name]
+
+		switch {
+		case !known:
+			if _, err := registry.AddConnection(ctx, name, &target); err != nil {
+				errs = append(errs, fmt.Errorf("%w (name=%q): %w", ErrFailedToAddConnection, name, err))
+
+				continue
+			}
+
+			diff.Added = append(diff.Added, name)
+		case reflect.DeepEqual(current, target):
+			diff.Unchanged = append(diff.Unchanged, name)
+
+			continue
+		default:
+			_, old, err := registry.replaceConnection(ctx, name, &target)
+			if err != nil {
+				errs = append(errs, err)
+
+				continue
+			}
+
+			if old != nil {
+				registry.drainConnection(ctx, name, old)
+			}
+
+			diff.Updated = append(diff.Updated, name)
+		}
+
+		registry.mu.Lock()
+		registry.applied[name] = target
+		registry.mu.Unlock()
+	}
+
+	registry.logger.InfoContext(
+		ctx,
+		"applied connection config",
+		slog.Any("added", diff.Added),
+		slog.Any("updated", diff.Updated),
+		slog.Any("removed", diff.Removed),
+	)
+
+	if len(errs) > 0 {
+		return diff, fmt.Errorf("%w: %w", ErrFailedToApplyConfig, errors.Join(errs...))
+	}
+
+	return diff, nil
+}
+
+// detachConnection unregisters a connection and drains it instead of closing it at once.
+func (registry *Registry) detachConnection(ctx context.Context, name string) {
+	registry.mu.Lock()
+	conn := registry.connections[name]
+
+	delete(registry.connections, name)
+	delete(registry.configs, name)
+	delete(registry.pending, name)
+	delete(registry.applied, name)
+	registry.mu.Unlock()
+
+	registry.forgetState(name)
+
+	if conn != nil {
+		registry.drainConnection(ctx, name, conn)
+	}
+
+	registry.logger.InfoContext(
+		ctx,
+		"removed connection dropped from config",
+		slog.String("name", name),
+	)
+}
+
+// drainConnection closes a detached connection once the drain timeout elapses.
+// Registry.Close closes connections that are still draining.
+func (registry *Registry) drainConnection(ctx context.Context, name string, conn Connection) {
+	closeCtx := context.WithoutCancel(ctx)
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.draining[conn] = time.AfterFunc(registry.drainTimeout, func() {
+		registry.mu.Lock()
+		delete(registry.draining, conn)
+		registry.mu.Unlock()
+
+		if err := conn.Close(closeCtx); err != nil {
+			registry.logger.WarnContext(
+				closeCtx,
+				"error closing drained connection",
+				slog.String("error", err.Error()),
+				slog.String("name", name),
+			)
+
+			return
+		}
+
+		registry.logger.InfoContext(
+			closeCtx,
+			"closed drained connection",
+			slog.String("name", name),
+		)
+	})
+}
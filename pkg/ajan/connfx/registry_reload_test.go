@@ -0,0 +1,111 @@
+package connfx_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_ApplyConfig(t *testing.T) {
+	t.Parallel()
+
+	registry := connfx.NewRegistry(
+		connfx.WithLogger(newMockLogger()),
+		connfx.WithDrainTimeout(20*time.Millisecond),
+	)
+	registry.RegisterFactory(&flakyFactory{}) //nolint:exhaustruct
+
+	ctx := t.Context()
+
+	err := registry.LoadFromConfig(ctx, &connfx.Config{
+		Targets: map[string]connfx.ConfigTarget{
+			"cache":   {Protocol: "flaky", DSN: "flaky://cache"},   //nolint:exhaustruct
+			"queue":   {Protocol: "flaky", DSN: "flaky://queue"},   //nolint:exhaustruct
+			"session": {Protocol: "flaky", DSN: "flaky://session"}, //nolint:exhaustruct
+		},
+	})
+	require.NoError(t, err)
+
+	// Connections added outside the configuration are not touched by a reload.
+	_, err = registry.AddConnection(ctx, "manual", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "flaky",
+	})
+	require.NoError(t, err)
+
+	oldQueue := registry.GetNamed("queue").(*flakyConnection)     //nolint:forcetypeassert
+	oldSession := registry.GetNamed("session").(*flakyConnection) //nolint:forcetypeassert
+	cache := registry.GetNamed("cache")
+
+	diff, err := registry.ApplyConfig(ctx, &connfx.Config{
+		Targets: map[string]connfx.ConfigTarget{
+			"cache":  {Protocol: "flaky", DSN: "flaky://cache"},    //nolint:exhaustruct
+			"queue":  {Protocol: "flaky", DSN: "flaky://queue-v2"}, //nolint:exhaustruct
+			"events": {Protocol: "flaky", DSN: "flaky://events"},   //nolint:exhaustruct
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"events"}, diff.Added)
+	assert.Equal(t, []string{"queue"}, diff.Updated)
+	assert.Equal(t, []string{"session"}, diff.Removed)
+	assert.Equal(t, []string{"cache"}, diff.Unchanged)
+
+	assert.Same(t, cache, registry.GetNamed("cache"))
+	assert.NotSame(t, oldQueue, registry.GetNamed("queue"))
+	assert.Nil(t, registry.GetNamed("session"))
+	assert.NotNil(t, registry.GetNamed("events"))
+	assert.NotNil(t, registry.GetNamed("manual"))
+
+	// Detached connections keep serving until the drain timeout elapses.
+	assert.False(t, oldQueue.closed.Load())
+	assert.False(t, oldSession.closed.Load())
+
+	require.Eventually(t, func() bool {
+		return oldQueue.closed.Load() && oldSession.closed.Load()
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestRegistry_ApplyConfig_KeepsConnectionOnFailure(t *testing.T) {
+	t.Parallel()
+
+	registry := connfx.NewRegistry(connfx.WithLogger(newMockLogger()))
+	factory := &flakyFactory{} //nolint:exhaustruct
+	registry.RegisterFactory(factory)
+
+	ctx := t.Context()
+
+	err := registry.LoadFromConfig(ctx, &connfx.Config{
+		Targets: map[string]connfx.ConfigTarget{
+			"cache": {Protocol: "flaky", DSN: "flaky://cache"}, //nolint:exhaustruct
+		},
+	})
+	require.NoError(t, err)
+
+	cache := registry.GetNamed("cache")
+
+	factory.failures.Store(1)
+
+	diff, err := registry.ApplyConfig(ctx, &connfx.Config{
+		Targets: map[string]connfx.ConfigTarget{
+			"cache": {Protocol: "flaky", DSN: "flaky://cache-v2"}, //nolint:exhaustruct
+		},
+	})
+	require.ErrorIs(t, err, connfx.ErrFailedToApplyConfig)
+	assert.Empty(t, diff.Updated)
+	assert.Same(t, cache, registry.GetNamed("cache"))
+
+	// The failed target is retried by the next reload.
+	diff, err = registry.ApplyConfig(ctx, &connfx.Config{
+		Targets: map[string]connfx.ConfigTarget{
+			"cache": {Protocol: "flaky", DSN: "flaky://cache-v2"}, //nolint:exhaustruct
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cache"}, diff.Updated)
+
+	require.NoError(t, registry.Close(ctx))
+	assert.True(t, cache.(*flakyConnection).closed.Load()) //nolint:forcetypeassert
+}
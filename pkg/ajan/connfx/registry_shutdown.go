@@ -0,0 +1,352 @@
+package connfx
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+const DefaultShutdownTimeout = 30 * time.Second
+
+var ErrRegistryClosed = errors.New("connection registry is closed")
+
+// consumerTracker counts messages handed to queue consumers that have not been
+// acknowledged yet, so Close can wait for them before tearing connections down.
+type consumerTracker struct {
+	idle     chan struct{} // closed when the last in-flight message is acknowledged
+	stopped  chan struct{} // closed when no more messages may be delivered
+	inflight int
+	stopOnce sync.Once
+	mu       sync.Mutex
+}
+
+// trackedQueueRepository stops delivering messages once the registry starts closing
+// and reports acknowledgments to the consumer tracker.
+type trackedQueueRepository struct {
+	QueueRepository
+
+	tracker *consumerTracker
+}
+
+// trackedQueueStreamRepository is the QueueStreamRepository counterpart of
+// trackedQueueRepository.
+type trackedQueueStreamRepository struct {
+	*trackedQueueRepository
+
+	stream QueueStreamRepository
+}
+
+func newConsumerTracker() *consumerTracker {
+	return &consumerTracker{
+		idle:     nil,
+		stopped:  make(chan struct{}),
+		inflight: 0,
+		stopOnce: sync.Once{},
+		mu:       sync.Mutex{},
+	}
+}
+
+func (r *trackedQueueRepository) Consume(
+	ctx context.Context,
+	queueName string,
+	config ConsumerConfig,
+) (<-chan Message, <-chan error) {
+	consumeCtx, cancel := context.WithCancel(ctx)
+	messages, errs := r.QueueRepository.Consume(consumeCtx, queueName, config)
+
+	return r.tracker.forward(consumeCtx, cancel, messages), errs
+}
+
+func (r *trackedQueueRepository) ConsumeWithGroup(
+	ctx context.Context,
+	queueName string,
+	consumerGroup string,
+	consumerName string,
+	config ConsumerConfig,
+) (<-chan Message, <-chan error) {
+	consumeCtx, cancel := context.WithCancel(ctx)
+	messages, errs := r.QueueRepository.ConsumeWithGroup(
+		consumeCtx,
+		queueName,
+		consumerGroup,
+		consumerName,
+		config,
+	)
+
+	return r.tracker.forward(consumeCtx, cancel, messages), errs
+}
+
+func (r *trackedQueueRepository) ClaimPendingMessages(
+	ctx context.Context,
+	queueName string,
+	consumerGroup string,
+	consumerName string,
+	minIdleTime time.Duration,
+	count int,
+) ([]Message, error) {
+	messages, err := r.QueueRepository.ClaimPendingMessages(
+		ctx,
+		queueName,
+		consumerGroup,
+		consumerName,
+		minIdleTime,
+		count,
+	)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	for i, msg := range messages {
+		if r.tracker.begin() {
+			messages[i] = r.tracker.track(msg)
+		}
+	}
+
+	return messages, nil
+}
+
+func (r *trackedQueueStreamRepository) CreateConsumerGroup(
+	ctx context.Context,
+	streamName, consumerGroup, startID string,
+) error {
+	return r.stream.CreateConsumerGroup(ctx, streamName, consumerGroup, startID) //nolint:wrapcheck
+}
+
+func (r *trackedQueueStreamRepository) StreamInfo(
+	ctx context.Context,
+	streamName string,
+) (StreamInfo, error) {
+	return r.stream.StreamInfo(ctx, streamName) //nolint:wrapcheck
+}
+
+func (r *trackedQueueStreamRepository) ConsumerGroupInfo(
+	ctx context.Context,
+	streamName string,
+) ([]ConsumerGroupInfo, error) {
+	return r.stream.ConsumerGroupInfo(ctx, streamName) //nolint:wrapcheck
+}
+
+func (r *trackedQueueStreamRepository) TrimStream(
+	ctx context.Context,
+	streamName string,
+	maxLen int64,
+) error {
+	return r.stream.TrimStream(ctx, streamName, maxLen) //nolint:wrapcheck
+}
+
+// stopAccepting marks the registry as closing. Connections are no longer handed out
+// and queue consumers stop receiving new messages.
+func (registry *Registry) stopAccepting() {
+	registry.mu.Lock()
+	registry.closing = true
+	registry.mu.Unlock()
+
+	registry.consumers.stop()
+}
+
+// isClosing reports whether Close has been called.
+func (registry *Registry) isClosing() bool {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	return registry.closing
+}
+
+// waitForConsumers blocks until every delivered message has been acknowledged, the
+// shutdown timeout elapses or ctx is done, whichever comes first.
+func (registry *Registry) waitForConsumers(ctx context.Context) {
+	waitCtx, cancel := context.WithTimeout(ctx, registry.shutdownTimeout)
+	defer cancel()
+
+	if inflight, err := registry.consumers.wait(waitCtx); err != nil {
+		registry.logger.WarnContext(
+			ctx,
+			"closing connections with unacknowledged messages",
+			slog.Int("inflight", inflight),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// closeOrder returns connection names in the order Close tears them down: streaming
+// connections first so no new messages arrive, stateful ones (databases, caches) last
+// since they are what in-flight work writes its results to. Within each group,
+// connections tagged as critical are closed last.
+func (registry *Registry) closeOrder() []string {
+	phase := func(conn Connection) int {
+		behaviors := conn.GetBehaviors()
+
+		switch {
+		case slices.Contains(behaviors, ConnectionBehaviorStreaming):
+			return 0
+		case slices.Contains(behaviors, ConnectionBehaviorStateful):
+			return 2 //nolint:mnd
+		default:
+			return 1
+		}
+	}
+
+	names := make([]string, 0, len(registry.connections))
+	for name := range registry.connections {
+		names = append(names, name)
+	}
+
+	slices.SortFunc(names, func(a, b string) int {
+		return cmp.Or(
+			cmp.Compare(phase(registry.connections[a]), phase(registry.connections[b])),
+			cmp.Compare(registry.isCritical(a), registry.isCritical(b)),
+			cmp.Compare(a, b),
+		)
+	})
+
+	return names
+}
+
+// isCritical returns 1 for connections tagged as critical, 0 otherwise. The caller
+// holds registry.mu.
+func (registry *Registry) isCritical(name string) int {
+	if config := registry.configs[name]; config != nil && config.HasTag(TagTier, TierCritical) {
+		return 1
+	}
+
+	return 0
+}
+
+// begin registers a message about to be delivered. It returns false once the
+// tracker is stopped.
+func (t *consumerTracker) begin() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	select {
+	case <-t.stopped:
+		return false
+	default:
+	}
+
+	t.inflight++
+	if t.inflight == 1 {
+		t.idle = make(chan struct{})
+	}
+
+	return true
+}
+
+// done releases a message registered with begin.
+func (t *consumerTracker) done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.inflight--
+	if t.inflight == 0 {
+		close(t.idle)
+	}
+}
+
+func (t *consumerTracker) stop() {
+	t.stopOnce.Do(func() {
+		close(t.stopped)
+	})
+}
+
+// wait blocks until no message is in flight and returns how many were still in
+// flight when ctx ended.
+func (t *consumerTracker) wait(ctx context.Context) (int, error) {
+	t.mu.Lock()
+	idle := t.idle
+	inflight := t.inflight
+	t.mu.Unlock()
+
+	if inflight == 0 {
+		return 0, nil
+	}
+
+	select {
+	case <-idle:
+		return 0, nil
+	case <-ctx.Done():
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		return t.inflight, ctx.Err()
+	}
+}
+
+// track wraps the acknowledgment functions of a message registered with begin, so
+// the first Ack or Nack releases it.
+func (t *consumerTracker) track(msg Message) Message {
+	var once sync.Once
+
+	release := func() {
+		once.Do(t.done)
+	}
+
+	ack, nack := msg.ack, msg.nack
+
+	msg.SetAckFunc(func() error {
+		defer release()
+
+		return ack()
+	})
+
+	msg.SetNackFunc(func(requeue bool) error {
+		defer release()
+
+		return nack(requeue)
+	})
+
+	return msg
+}
+
+// forward relays messages to the consumer until the source closes, ctx is done or the
+// tracker is stopped; cancel then stops the underlying consumer.
+func (t *consumerTracker) forward(
+	ctx context.Context,
+	cancel context.CancelFunc,
+	messages <-chan Message,
+) <-chan Message {
+	if messages == nil {
+		cancel()
+
+		return nil
+	}
+
+	out := make(chan Message)
+
+	go func() {
+		defer cancel()
+		defer close(out)
+
+		for {
+			select {
+			case msg, ok := <-messages:
+				if !ok || !t.begin() {
+					// Undelivered group messages stay pending and are redelivered later.
+					return
+				}
+
+				select {
+				case out <- t.track(msg):
+				case <-t.stopped:
+					t.done()
+
+					return
+				case <-ctx.Done():
+					t.done()
+
+					return
+				}
+			case <-t.stopped:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
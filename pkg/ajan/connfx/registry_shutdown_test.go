@@ -0,0 +1,99 @@
+package connfx_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newShutdownRegistry(t *testing.T, timeout time.Duration) (*connfx.Registry, connfx.QueueRepository) {
+	t.Helper()
+
+	registry := connfx.NewRegistry(
+		connfx.WithLogger(newMockLogger()),
+		connfx.WithDefaultFactories(),
+		connfx.WithShutdownTimeout(timeout),
+	)
+
+	_, err := registry.AddConnection(t.Context(), "queue", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "inmemory",
+	})
+	require.NoError(t, err)
+
+	queue, err := registry.GetQueueRepository("queue")
+	require.NoError(t, err)
+
+	return registry, queue
+}
+
+func TestRegistry_Close_WaitsForAcknowledgments(t *testing.T) {
+	t.Parallel()
+
+	registry, queue := newShutdownRegistry(t, 5*time.Second)
+	ctx := t.Context()
+
+	messages, _ := queue.ConsumeWithGroup(ctx, "jobs", "workers", "worker-1", connfx.DefaultConsumerConfig())
+
+	require.NoError(t, queue.Publish(ctx, "jobs", []byte("job-1")))
+
+	msg := <-messages
+
+	closed := make(chan error)
+
+	go func() {
+		closed <- registry.Close(ctx)
+	}()
+
+	// New operations are refused while the registry is closing.
+	require.Eventually(t, func() bool {
+		return registry.GetNamed("queue") == nil
+	}, time.Second, 5*time.Millisecond)
+
+	_, err := registry.GetQueueRepository("queue")
+	require.ErrorIs(t, err, connfx.ErrRegistryClosed)
+
+	_, err = registry.AddConnection(ctx, "late", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "inmemory",
+	})
+	require.ErrorIs(t, err, connfx.ErrRegistryClosed)
+
+	// No further messages are delivered to the consumer.
+	_, open := <-messages
+	assert.False(t, open)
+
+	select {
+	case <-closed:
+		t.Fatal("registry closed before the in-flight message was acknowledged")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, msg.Ack())
+
+	select {
+	case err := <-closed:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("registry did not close after the message was acknowledged")
+	}
+}
+
+func TestRegistry_Close_ShutdownTimeout(t *testing.T) {
+	t.Parallel()
+
+	registry, queue := newShutdownRegistry(t, 20*time.Millisecond)
+	ctx := t.Context()
+
+	conn := registry.GetNamed("queue")
+	messages, _ := queue.ConsumeWithGroup(ctx, "jobs", "workers", "worker-1", connfx.DefaultConsumerConfig())
+
+	require.NoError(t, queue.Publish(ctx, "jobs", []byte("job-1")))
+
+	<-messages
+
+	// The message is never acknowledged; Close gives up after the shutdown timeout.
+	require.NoError(t, registry.Close(ctx))
+	assert.Equal(t, connfx.ConnectionStateDisconnected, conn.GetState())
+}
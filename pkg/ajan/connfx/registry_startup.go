@@ -0,0 +1,224 @@
+package connfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+var (
+	ErrUnknownDependency    = errors.New("target depends on a target that is not configured")
+	ErrDependencyCycle      = errors.New("targets depend on each other in a cycle")
+	ErrDependencyNotStarted = errors.New("dependency did not start")
+	ErrStartupTimedOut      = errors.New("connection did not start within its startup timeout")
+)
+
+// BootReport summarizes how LoadFromConfig started the targets, in startup order.
+type BootReport struct {
+	Targets  []BootTarget
+	Duration time.Duration
+}
+
+// BootTarget is the startup outcome of a single target. Err is nil when the target
+// started, and wraps ErrDependencyNotStarted when it was skipped.
+type BootTarget struct {
+	Err       error
+	Name      string
+	Protocol  string
+	DependsOn []string
+	Duration  time.Duration
+}
+
+// Dependencies returns the names of the targets this target depends on.
+func (config *ConfigTarget) Dependencies() []string {
+	if config.DependsOn == "" {
+		return nil
+	}
+
+	return slices.DeleteFunc(splitCommaList(config.DependsOn), func(name string) bool {
+		return name == ""
+	})
+}
+
+// Failed returns the names of the targets that failed or were skipped.
+func (report *BootReport) Failed() []string {
+	var names []string
+
+	for _, target := range report.Targets {
+		if target.Err != nil {
+			names = append(names, target.Name)
+		}
+	}
+
+	return names
+}
+
+// BootReport returns the report of the last LoadFromConfig, or nil before the first one.
+func (registry *Registry) BootReport() *BootReport {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	return registry.bootReport
+}
+
+// startTargets adds the targets in dependency order. A target whose dependency failed
+// is skipped; independent targets are still started.
+func (registry *Registry) startTargets(
+	ctx context.Context,
+	targets map[string]ConfigTarget,
+	order []string,
+) (*BootReport, error) {
+	report := &BootReport{
+		Targets:  make([]BootTarget, 0, len(order)),
+		Duration: 0,
+	}
+
+	start := time.Now()
+	failed := make(map[string]bool)
+
+	var errs []error
+
+	for _, name := range order {
+		target := targets[name]
+
+		entry := registry.startTarget(ctx, name, &target, failed)
+		if entry.Err != nil {
+			failed[name] = true
+			errs = append(errs, entry.Err)
+		}
+
+		report.Targets = append(report.Targets, entry)
+	}
+
+	report.Duration = time.Since(start)
+
+	registry.logger.InfoContext(
+		ctx,
+		"connections started",
+		slog.Any("order", order),
+		slog.Int("started", len(order)-len(errs)),
+		slog.Any("failed", report.Failed()),
+		slog.Duration("duration", report.Duration),
+	)
+
+	return report, errors.Join(errs...)
+}
+
+func (registry *Registry) startTarget(
+	ctx context.Context,
+	name string,
+	target *ConfigTarget,
+	failed map[string]bool,
+) BootTarget {
+	entry := BootTarget{
+		Err:       nil,
+		Name:      name,
+		Protocol:  target.Protocol,
+		DependsOn: target.Dependencies(),
+		Duration:  0,
+	}
+
+	for _, dependency := range entry.DependsOn {
+		if failed[dependency] {
+			registry.logger.WarnContext(
+				ctx,
+				"skipping connection, a dependency did not start",
+				slog.String("name", name),
+				slog.String("dependency", dependency),
+			)
+
+			entry.Err = fmt.Errorf("%w (name=%q, dependency=%q)", ErrDependencyNotStarted, name, dependency)
+
+			return entry
+		}
+	}
+
+	startCtx := ctx
+
+	if target.StartupTimeout > 0 {
+		var cancel context.CancelFunc
+
+		startCtx, cancel = context.WithTimeout(ctx, target.StartupTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	_, err := registry.AddConnection(startCtx, name, target)
+	entry.Duration = time.Since(start)
+
+	switch {
+	case err == nil:
+		registry.mu.Lock()
+		registry.applied[name] = *target
+		registry.mu.Unlock()
+	case ctx.Err() == nil && errors.Is(startCtx.Err(), context.DeadlineExceeded):
+		entry.Err = fmt.Errorf("%w (name=%q, timeout=%s): %w",
+			ErrStartupTimedOut, name, target.StartupTimeout, err)
+	default:
+		entry.Err = fmt.Errorf("%w (name=%q): %w", ErrFailedToAddConnection, name, err)
+	}
+
+	return entry
+}
+
+// startupOrder sorts the targets so every target comes after its dependencies. Targets
+// that do not depend on each other are ordered by name.
+func startupOrder(targets map[string]ConfigTarget) ([]string, error) {
+	pending := make(map[string]int, len(targets))
+	dependents := make(map[string][]string)
+
+	for name, target := range targets {
+		for _, dependency := range target.Dependencies() {
+			if _, exists := targets[dependency]; !exists {
+				return nil, fmt.Errorf("%w (name=%q, depends_on=%q)", ErrUnknownDependency, name, dependency)
+			}
+
+			pending[name]++
+			dependents[dependency] = append(dependents[dependency], name)
+		}
+	}
+
+	ready := make([]string, 0, len(targets))
+
+	for name := range targets {
+		if pending[name] == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	order := make([]string, 0, len(targets))
+
+	for len(ready) > 0 {
+		slices.Sort(ready)
+
+		name := ready[0]
+		ready = ready[1:]
+		order = append(order, name)
+
+		for _, dependent := range dependents[name] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if len(order) < len(targets) {
+		var cycle []string
+
+		for name := range targets {
+			if !slices.Contains(order, name) {
+				cycle = append(cycle, name)
+			}
+		}
+
+		slices.Sort(cycle)
+
+		return nil, fmt.Errorf("%w (names=%q)", ErrDependencyCycle, cycle)
+	}
+
+	return order, nil
+}
@@ -0,0 +1,124 @@
+package connfx_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startupFactory records the hosts it dials, fails for "down" and blocks on "slow"
+// until the startup context is done.
+type startupFactory struct {
+	dialed []string
+	mu     sync.Mutex
+}
+
+func (f *startupFactory) CreateConnection( //nolint:ireturn
+	ctx context.Context,
+	config *connfx.ConfigTarget,
+) (connfx.Connection, error) {
+	f.mu.Lock()
+	f.dialed = append(f.dialed, config.Host)
+	f.mu.Unlock()
+
+	switch config.Host {
+	case "down":
+		return nil, errFlakyUnavailable
+	case "slow":
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	}
+
+	conn := &flakyConnection{} //nolint:exhaustruct
+	conn.state.Store(int32(connfx.ConnectionStateReady))
+
+	return conn, nil
+}
+
+func (f *startupFactory) GetProtocol() string {
+	return "startup"
+}
+
+func TestRegistry_LoadFromConfigDependencyOrder(t *testing.T) {
+	t.Parallel()
+
+	registry := connfx.NewRegistry(connfx.WithLogger(newMockLogger()))
+	factory := &startupFactory{} //nolint:exhaustruct
+	registry.RegisterFactory(factory)
+
+	err := registry.LoadFromConfig(t.Context(), &connfx.Config{
+		Targets: map[string]connfx.ConfigTarget{
+			"api":      {Protocol: "startup", Host: "api", DependsOn: "cache, database"}, //nolint:exhaustruct
+			"cache":    {Protocol: "startup", Host: "cache", DependsOn: "database"},      //nolint:exhaustruct
+			"database": {Protocol: "startup", Host: "database"},                          //nolint:exhaustruct
+			"audit":    {Protocol: "startup", Host: "audit"},                             //nolint:exhaustruct
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"audit", "database", "cache", "api"}, factory.dialed)
+
+	report := registry.BootReport()
+	require.NotNil(t, report)
+	require.Len(t, report.Targets, 4)
+	assert.Equal(t, "api", report.Targets[3].Name)
+	assert.Equal(t, []string{"cache", "database"}, report.Targets[3].DependsOn)
+	assert.Empty(t, report.Failed())
+}
+
+func TestRegistry_LoadFromConfigSkipsDependentsOfFailedTargets(t *testing.T) {
+	t.Parallel()
+
+	registry := connfx.NewRegistry(connfx.WithLogger(newMockLogger()))
+	factory := &startupFactory{} //nolint:exhaustruct
+	registry.RegisterFactory(factory)
+
+	err := registry.LoadFromConfig(t.Context(), &connfx.Config{
+		Targets: map[string]connfx.ConfigTarget{
+			"database": {Protocol: "startup", Host: "down"},                                        //nolint:exhaustruct
+			"cache":    {Protocol: "startup", Host: "cache", DependsOn: "database"},                //nolint:exhaustruct
+			"search":   {Protocol: "startup", Host: "slow", StartupTimeout: 20 * time.Millisecond}, //nolint:exhaustruct,lll
+			"audit":    {Protocol: "startup", Host: "audit"},                                       //nolint:exhaustruct
+		},
+	})
+	require.ErrorIs(t, err, connfx.ErrFailedToAddConnection)
+	require.ErrorIs(t, err, connfx.ErrDependencyNotStarted)
+	require.ErrorIs(t, err, connfx.ErrStartupTimedOut)
+
+	assert.NotContains(t, factory.dialed, "cache")
+	assert.NotNil(t, registry.GetNamed("audit"))
+	assert.Equal(t, []string{"database", "cache", "search"}, registry.BootReport().Failed())
+}
+
+func TestRegistry_LoadFromConfigInvalidDependencies(t *testing.T) {
+	t.Parallel()
+
+	registry := connfx.NewRegistry(connfx.WithLogger(newMockLogger()))
+	factory := &startupFactory{} //nolint:exhaustruct
+	registry.RegisterFactory(factory)
+
+	ctx := t.Context()
+
+	err := registry.LoadFromConfig(ctx, &connfx.Config{
+		Targets: map[string]connfx.ConfigTarget{
+			"cache": {Protocol: "startup", DependsOn: "database"}, //nolint:exhaustruct
+		},
+	})
+	require.ErrorIs(t, err, connfx.ErrUnknownDependency)
+
+	err = registry.LoadFromConfig(ctx, &connfx.Config{
+		Targets: map[string]connfx.ConfigTarget{
+			"a": {Protocol: "startup", DependsOn: "b"}, //nolint:exhaustruct
+			"b": {Protocol: "startup", DependsOn: "a"}, //nolint:exhaustruct
+			"c": {Protocol: "startup"},                 //nolint:exhaustruct
+		},
+	})
+	require.ErrorIs(t, err, connfx.ErrDependencyCycle)
+	assert.Empty(t, factory.dialed)
+}
@@ -0,0 +1,112 @@
+package connfx
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Well-known target tags.
+const (
+	// TagEnvironment scopes a target to one or more comma-separated environments.
+	TagEnvironment = "env"
+	// TagTier marks how essential a dependency is, e.g. TierCritical.
+	TagTier = "tier"
+
+	TierCritical = "critical"
+	TierOptional = "optional"
+)
+
+// HasTag reports whether the target carries the tag with the given value.
+func (config *ConfigTarget) HasTag(key, value string) bool {
+	tagValue, exists := config.Tags[key]
+
+	return exists && tagValue == value
+}
+
+// GetByTag returns all dialed connections whose target carries the tag with the given value.
+func (registry *Registry) GetByTag(key, value string) []Connection {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	var connections []Connection
+	for name, conn := range registry.connections {
+		if config := registry.configs[name]; config != nil && config.HasTag(key, value) {
+			connections = append(connections, conn)
+		}
+	}
+
+	return connections
+}
+
+// HealthCheckByTag performs health checks on the connections carrying the tag, e.g.
+// only the critical dependencies for a readiness probe.
+func (registry *Registry) HealthCheckByTag(
+	ctx context.Context,
+	key, value string,
+) map[string]*HealthStatus {
+	registry.mu.RLock()
+
+	connections := make(map[string]Connection)
+	for name, conn := range registry.connections {
+		if config := registry.configs[name]; config != nil && config.HasTag(key, value) {
+			connections[name] = conn
+		}
+	}
+
+	registry.mu.RUnlock()
+
+	return checkHealth(ctx, connections)
+}
+
+// tags returns a copy of the tags of a dialed connection. The caller holds registry.mu.
+func (registry *Registry) tags(name string) map[string]string {
+	if config := registry.configs[name]; config != nil {
+		return maps.Clone(config.Tags)
+	}
+
+	return nil
+}
+
+// scopeTargets drops the targets whose environment tag does not include the
+// registry's environment. Without WithEnvironment every target is kept.
+func (registry *Registry) scopeTargets(
+	ctx context.Context,
+	targets map[string]ConfigTarget,
+) map[string]ConfigTarget {
+	if registry.environment == "" {
+		return targets
+	}
+
+	scoped := make(map[string]ConfigTarget, len(targets))
+
+	for name, target := range targets {
+		environments, scopedTarget := target.Tags[TagEnvironment]
+		if scopedTarget && !slices.Contains(splitCommaList(environments), registry.environment) {
+			registry.logger.InfoContext(
+				ctx,
+				"skipping connection scoped to another environment",
+				slog.String("name", name),
+				slog.String("environments", environments),
+				slog.String("environment", registry.environment),
+			)
+
+			continue
+		}
+
+		scoped[name] = target
+	}
+
+	return scoped
+}
+
+func splitCommaList(value string) []string {
+	values := strings.Split(value, ",")
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+
+	return values
+}
@@ -0,0 +1,91 @@
+package connfx_test
+
+import (
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_GetByTag(t *testing.T) {
+	t.Parallel()
+
+	registry := connfx.NewRegistry(connfx.WithLogger(newMockLogger()))
+	registry.RegisterFactory(&flakyFactory{}) //nolint:exhaustruct
+
+	ctx := t.Context()
+
+	err := registry.LoadFromConfig(ctx, &connfx.Config{
+		Targets: map[string]connfx.ConfigTarget{
+			"database": { //nolint:exhaustruct
+				Protocol: "flaky",
+				Tags:     map[string]string{connfx.TagTier: connfx.TierCritical},
+			},
+			"analytics": { //nolint:exhaustruct
+				Protocol: "flaky",
+				Tags:     map[string]string{connfx.TagTier: connfx.TierOptional},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	critical := registry.GetByTag(connfx.TagTier, connfx.TierCritical)
+	require.Len(t, critical, 1)
+	assert.Same(t, registry.GetNamed("database"), critical[0])
+
+	health := registry.HealthCheckByTag(ctx, connfx.TagTier, connfx.TierCritical)
+	assert.Len(t, health, 1)
+	assert.Contains(t, health, "database")
+
+	for _, info := range registry.ListConnectionInfo() {
+		if info.Name == "analytics" {
+			assert.Equal(t, map[string]string{connfx.TagTier: connfx.TierOptional}, info.Tags)
+		}
+	}
+}
+
+func TestRegistry_WithEnvironment(t *testing.T) {
+	t.Parallel()
+
+	registry := connfx.NewRegistry(
+		connfx.WithLogger(newMockLogger()),
+		connfx.WithEnvironment("staging"),
+	)
+	registry.RegisterFactory(&flakyFactory{}) //nolint:exhaustruct
+
+	ctx := t.Context()
+
+	err := registry.LoadFromConfig(ctx, &connfx.Config{
+		Targets: map[string]connfx.ConfigTarget{
+			"shared": {Protocol: "flaky"}, //nolint:exhaustruct
+			"mirror": { //nolint:exhaustruct
+				Protocol: "flaky",
+				Tags:     map[string]string{connfx.TagEnvironment: "staging, production"},
+			},
+			"sandbox": { //nolint:exhaustruct
+				Protocol: "flaky",
+				Tags:     map[string]string{connfx.TagEnvironment: "development"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.NotNil(t, registry.GetNamed("shared"))
+	assert.NotNil(t, registry.GetNamed("mirror"))
+	assert.Nil(t, registry.GetNamed("sandbox"))
+
+	// A reload that moves a target out of the environment removes it.
+	diff, err := registry.ApplyConfig(ctx, &connfx.Config{
+		Targets: map[string]connfx.ConfigTarget{
+			"shared": {Protocol: "flaky"}, //nolint:exhaustruct
+			"mirror": { //nolint:exhaustruct
+				Protocol: "flaky",
+				Tags:     map[string]string{connfx.TagEnvironment: "production"},
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mirror"}, diff.Removed)
+	assert.Nil(t, registry.GetNamed("mirror"))
+}
@@ -0,0 +1,441 @@
+package connfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const telemetryScopeName = "github.com/eser/aya.is-services/pkg/ajan/connfx"
+
+var (
+	ErrFailedToBuildConnectionMetrics = errors.New("failed to build connection metrics")
+	ErrFailedToUnregisterMetrics      = errors.New("failed to unregister connection metrics")
+	ErrTelemetryAlreadyEnabled        = errors.New("connection telemetry is already enabled")
+)
+
+// PoolStats is a snapshot of a connection pool's usage.
+type PoolStats struct {
+	Total        int64
+	Idle         int64
+	InUse        int64
+	WaitCount    int64
+	WaitDuration time.Duration
+}
+
+// PoolStatsProvider is implemented by connections backed by a connection pool.
+type PoolStatsProvider interface {
+	PoolStats() PoolStats
+}
+
+// EnableTelemetry turns on OpenTelemetry spans and metrics for connections whose target
+// sets Instrument. Providers typically come from the OTLP connection's resource:
+//
+//	resource, _ := otlpConn.CreateResource(name, version, env)
+//	registry.EnableTelemetry(resource.GetTracerProvider(), resource.GetMeterProvider())
+//
+// Operations on repositories obtained through GetRepository, GetQueueRepository and
+// GetQueryRepository are traced and measured; pool statistics are reported as gauges.
+func (registry *Registry) EnableTelemetry(
+	tracerProvider trace.TracerProvider,
+	meterProvider metric.MeterProvider,
+) error {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if registry.telemetry != nil {
+		return ErrTelemetryAlreadyEnabled
+	}
+
+	telemetry, err := newConnectionTelemetry(registry, tracerProvider, meterProvider)
+	if err != nil {
+		return err
+	}
+
+	registry.telemetry = telemetry
+
+	return nil
+}
+
+// instrumentation returns the per-connection instrumentation for name, or nil when
+// telemetry is disabled or the target did not opt in. Callers must hold registry.mu.
+func (registry *Registry) instrumentation(name string, conn Connection) *connectionInstrumentation {
+	if registry.telemetry == nil {
+		return nil
+	}
+
+	config := registry.configs[name]
+	if config == nil || !config.Instrument {
+		return nil
+	}
+
+	return &connectionInstrumentation{
+		telemetry: registry.telemetry,
+		attrs: []attribute.KeyValue{
+			attribute.String("connection.name", name),
+			attribute.String("connection.protocol", conn.GetProtocol()),
+		},
+	}
+}
+
+type connectionTelemetry struct {
+	tracer       trace.Tracer
+	duration     metric.Float64Histogram
+	errors       metric.Int64Counter
+	consumed     metric.Int64Counter
+	registration metric.Registration
+}
+
+func newConnectionTelemetry( //nolint:funlen
+	registry *Registry,
+	tracerProvider trace.TracerProvider,
+	meterProvider metric.MeterProvider,
+) (*connectionTelemetry, error) {
+	meter := meterProvider.Meter(telemetryScopeName)
+
+	duration, err := meter.Float64Histogram(
+		"connfx.operation.duration",
+		metric.WithDescription("Duration of connection operations"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToBuildConnectionMetrics, err)
+	}
+
+	operationErrors, err := meter.Int64Counter(
+		"connfx.operation.errors",
+		metric.WithDescription("Number of failed connection operations"),
+		metric.WithUnit("{error}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToBuildConnectionMetrics, err)
+	}
+
+	consumed, err := meter.Int64Counter(
+		"connfx.messages.consumed",
+		metric.WithDescription("Number of messages delivered to consumers"),
+		metric.WithUnit("{message}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToBuildConnectionMetrics, err)
+	}
+
+	poolConnections, err := meter.Int64ObservableGauge(
+		"connfx.pool.connections",
+		metric.WithDescription("Number of pooled connections by state"),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToBuildConnectionMetrics, err)
+	}
+
+	poolWaits, err := meter.Int64ObservableCounter(
+		"connfx.pool.waits",
+		metric.WithDescription("Number of times callers waited for a pooled connection"),
+		metric.WithUnit("{wait}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToBuildConnectionMetrics, err)
+	}
+
+	registration, err := meter.RegisterCallback(
+		func(_ context.Context, observer metric.Observer) error {
+			registry.mu.RLock()
+			defer registry.mu.RUnlock()
+
+			for name, conn := range registry.connections {
+				config := registry.configs[name]
+				if config == nil || !config.Instrument {
+					continue
+				}
+
+				provider, ok := conn.(PoolStatsProvider)
+				if !ok {
+					continue
+				}
+
+				stats := provider.PoolStats()
+				attrs := []attribute.KeyValue{
+					attribute.String("connection.name", name),
+					attribute.String("connection.protocol", conn.GetProtocol()),
+				}
+
+				observer.ObserveInt64(poolConnections, stats.Idle, metric.WithAttributes(
+					append(attrs, attribute.String("state", "idle"))...,
+				))
+				observer.ObserveInt64(poolConnections, stats.InUse, metric.WithAttributes(
+					append(attrs, attribute.String("state", "in_use"))...,
+				))
+				observer.ObserveInt64(poolWaits, stats.WaitCount, metric.WithAttributes(attrs...))
+			}
+
+			return nil
+		},
+		poolConnections,
+		poolWaits,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToBuildConnectionMetrics, err)
+	}
+
+	return &connectionTelemetry{
+		tracer:       tracerProvider.Tracer(telemetryScopeName),
+		duration:     duration,
+		errors:       operationErrors,
+		consumed:     consumed,
+		registration: registration,
+	}, nil
+}
+
+func (t *connectionTelemetry) close() error {
+	if err := t.registration.Unregister(); err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToUnregisterMetrics, err)
+	}
+
+	return nil
+}
+
+type connectionInstrumentation struct {
+	telemetry *connectionTelemetry
+	attrs     []attribute.KeyValue
+}
+
+// observe runs fn inside a client span and records its duration and failure.
+func (i *connectionInstrumentation) observe(
+	ctx context.Context,
+	operation string,
+	fn func(ctx context.Context) error,
+) error {
+	attrs := append([]attribute.KeyValue{attribute.String("operation", operation)}, i.attrs...)
+
+	ctx, span := i.telemetry.tracer.Start(
+		ctx,
+		"connfx."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+
+	start := time.Now()
+	err := fn(ctx)
+
+	i.telemetry.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		i.telemetry.errors.Add(ctx, 1, metric.WithAttributes(attrs...))
+	}
+
+	return err
+}
+
+// observeConsume counts delivered messages and consumer errors while forwarding them.
+func (i *connectionInstrumentation) observeConsume(
+	ctx context.Context,
+	operation string,
+	messages <-chan Message,
+	errs <-chan error,
+) (<-chan Message, <-chan error) {
+	attrs := append([]attribute.KeyValue{attribute.String("operation", operation)}, i.attrs...)
+
+	_, span := i.telemetry.tracer.Start(
+		ctx,
+		"connfx."+operation,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attrs...),
+	)
+	span.End()
+
+	if messages == nil || errs == nil {
+		return messages, errs
+	}
+
+	outMessages := make(chan Message)
+	outErrs := make(chan error)
+
+	go func() {
+		defer close(outMessages)
+
+		for msg := range messages {
+			i.telemetry.consumed.Add(ctx, 1, metric.WithAttributes(attrs...))
+
+			select {
+			case outMessages <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		defer close(outErrs)
+
+		for err := range errs {
+			i.telemetry.errors.Add(ctx, 1, metric.WithAttributes(attrs...))
+
+			select {
+			case outErrs <- err:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return outMessages, outErrs
+}
+
+type instrumentedRepository struct {
+	Repository
+
+	instrumentation *connectionInstrumentation
+}
+
+func (r *instrumentedRepository) Get(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+
+	err := r.instrumentation.observe(ctx, "get", func(ctx context.Context) error {
+		var err error
+
+		value, err = r.Repository.Get(ctx, key)
+
+		return err //nolint:wrapcheck
+	})
+
+	return value, err
+}
+
+func (r *instrumentedRepository) Set(ctx context.Context, key string, value []byte) error {
+	return r.instrumentation.observe(ctx, "set", func(ctx context.Context) error {
+		return r.Repository.Set(ctx, key, value) //nolint:wrapcheck
+	})
+}
+
+func (r *instrumentedRepository) Remove(ctx context.Context, keys ...string) error {
+	return r.instrumentation.observe(ctx, "remove", func(ctx context.Context) error {
+		return r.Repository.Remove(ctx, keys...) //nolint:wrapcheck
+	})
+}
+
+func (r *instrumentedRepository) Update(ctx context.Context, key string, value []byte) error {
+	return r.instrumentation.observe(ctx, "update", func(ctx context.Context) error {
+		return r.Repository.Update(ctx, key, value) //nolint:wrapcheck
+	})
+}
+
+func (r *instrumentedRepository) Exists(ctx context.Context, key string) (bool, error) {
+	var exists bool
+
+	err := r.instrumentation.observe(ctx, "exists", func(ctx context.Context) error {
+		var err error
+
+		exists, err = r.Repository.Exists(ctx, key)
+
+		return err //nolint:wrapcheck
+	})
+
+	return exists, err
+}
+
+type instrumentedQueueRepository struct {
+	QueueRepository
+
+	instrumentation *connectionInstrumentation
+}
+
+func (r *instrumentedQueueRepository) Publish(
+	ctx context.Context,
+	queueName string,
+	body []byte,
+) error {
+	return r.instrumentation.observe(ctx, "publish", func(ctx context.Context) error {
+		return r.QueueRepository.Publish(ctx, queueName, body) //nolint:wrapcheck
+	})
+}
+
+func (r *instrumentedQueueRepository) PublishWithHeaders(
+	ctx context.Context,
+	queueName string,
+	body []byte,
+	headers map[string]any,
+) error {
+	return r.instrumentation.observe(ctx, "publish", func(ctx context.Context) error {
+		return r.QueueRepository.PublishWithHeaders(ctx, queueName, body, headers) //nolint:wrapcheck
+	})
+}
+
+func (r *instrumentedQueueRepository) Consume(
+	ctx context.Context,
+	queueName string,
+	config ConsumerConfig,
+) (<-chan Message, <-chan error) {
+	messages, errs := r.QueueRepository.Consume(ctx, queueName, config)
+
+	return r.instrumentation.observeConsume(ctx, "consume", messages, errs)
+}
+
+func (r *instrumentedQueueRepository) ConsumeWithGroup(
+	ctx context.Context,
+	queueName string,
+	consumerGroup string,
+	consumerName string,
+	config ConsumerConfig,
+) (<-chan Message, <-chan error) {
+	messages, errs := r.QueueRepository.ConsumeWithGroup(
+		ctx,
+		queueName,
+		consumerGroup,
+		consumerName,
+		config,
+	)
+
+	return r.instrumentation.observeConsume(ctx, "consume", messages, errs)
+}
+
+type instrumentedQueryRepository struct {
+	QueryRepository
+
+	instrumentation *connectionInstrumentation
+}
+
+func (r *instrumentedQueryRepository) Query( //nolint:ireturn
+	ctx context.Context,
+	query string,
+	args ...any,
+) (QueryResult, error) {
+	var result QueryResult
+
+	err := r.instrumentation.observe(ctx, "query", func(ctx context.Context) error {
+		var err error
+
+		result, err = r.QueryRepository.Query(ctx, query, args...)
+
+		return err //nolint:wrapcheck
+	})
+
+	return result, err
+}
+
+func (r *instrumentedQueryRepository) Execute( //nolint:ireturn
+	ctx context.Context,
+	command string,
+	args ...any,
+) (ExecuteResult, error) {
+	var result ExecuteResult
+
+	err := r.instrumentation.observe(ctx, "execute", func(ctx context.Context) error {
+		var err error
+
+		result, err = r.QueryRepository.Execute(ctx, command, args...)
+
+		return err //nolint:wrapcheck
+	})
+
+	return result, err
+}
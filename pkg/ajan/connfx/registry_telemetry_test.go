@@ -0,0 +1,182 @@
+package connfx_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	_ "modernc.org/sqlite"
+)
+
+var errKeyMissing = errors.New("key missing")
+
+// kvConnection is a flakyConnection whose raw connection is an in-memory Repository.
+// Only the key-value operations are implemented; the rest of Repository is left nil.
+type kvConnection struct {
+	connfx.Repository
+	flakyConnection
+
+	values map[string][]byte
+	mu     sync.Mutex
+}
+
+func (c *kvConnection) Close(ctx context.Context) error {
+	return c.flakyConnection.Close(ctx)
+}
+
+func (c *kvConnection) GetRawConnection() any {
+	return c
+}
+
+func (c *kvConnection) Get(_ context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, ok := c.values[key]
+	if !ok {
+		return nil, errKeyMissing
+	}
+
+	return value, nil
+}
+
+func (c *kvConnection) Set(_ context.Context, key string, value []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.values[key] = value
+
+	return nil
+}
+
+func (c *kvConnection) Remove(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		delete(c.values, key)
+	}
+
+	return nil
+}
+
+func (c *kvConnection) Update(ctx context.Context, key string, value []byte) error {
+	return c.Set(ctx, key, value)
+}
+
+func (c *kvConnection) Exists(_ context.Context, key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.values[key]
+
+	return ok, nil
+}
+
+type kvFactory struct{}
+
+func (f *kvFactory) CreateConnection( //nolint:ireturn
+	_ context.Context,
+	_ *connfx.ConfigTarget,
+) (connfx.Connection, error) {
+	conn := &kvConnection{values: map[string][]byte{}} //nolint:exhaustruct
+	conn.state.Store(int32(connfx.ConnectionStateReady))
+
+	return conn, nil
+}
+
+func (f *kvFactory) GetProtocol() string {
+	return "flaky"
+}
+
+func TestRegistry_Telemetry(t *testing.T) {
+	t.Parallel()
+
+	spans := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	registry := connfx.NewRegistry(connfx.WithLogger(newMockLogger()))
+	registry.RegisterFactory(&kvFactory{})
+	registry.RegisterFactory(connfx.NewSQLConnectionFactory("sqlite"))
+
+	require.NoError(t, registry.EnableTelemetry(tracerProvider, meterProvider))
+	require.ErrorIs(
+		t,
+		registry.EnableTelemetry(tracerProvider, meterProvider),
+		connfx.ErrTelemetryAlreadyEnabled,
+	)
+
+	ctx := t.Context()
+
+	_, err := registry.AddConnection(ctx, "cache", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol:   "flaky",
+		Instrument: true,
+	})
+	require.NoError(t, err)
+
+	_, err = registry.AddConnection(ctx, "plain", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "flaky",
+	})
+	require.NoError(t, err)
+
+	_, err = registry.AddConnection(ctx, "db", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol:   "sqlite",
+		DSN:        ":memory:",
+		Instrument: true,
+	})
+	require.NoError(t, err)
+
+	repo, err := registry.GetRepository("cache")
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Set(ctx, "greeting", []byte("hello")))
+
+	value, err := repo.Get(ctx, "greeting")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello"), value)
+
+	_, err = repo.Get(ctx, "missing")
+	require.ErrorIs(t, err, errKeyMissing)
+
+	// Targets that did not opt in are returned as-is.
+	plain, err := registry.GetRepository("plain")
+	require.NoError(t, err)
+	require.NoError(t, plain.Set(ctx, "key", []byte("value")))
+
+	ended := spans.Ended()
+	require.Len(t, ended, 3)
+	assert.Equal(t, "connfx.set", ended[0].Name())
+	assert.Equal(t, "connfx.get", ended[1].Name())
+	assert.Equal(t, "Error", ended[2].Status().Code.String())
+
+	var metrics metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &metrics))
+
+	names := map[string]metricdata.Aggregation{}
+	for _, scope := range metrics.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			names[m.Name] = m.Data
+		}
+	}
+
+	require.Contains(t, names, "connfx.operation.duration")
+	require.Contains(t, names, "connfx.operation.errors")
+	require.Contains(t, names, "connfx.pool.connections")
+
+	errorsSum, ok := names["connfx.operation.errors"].(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, errorsSum.DataPoints, 1)
+	assert.Equal(t, int64(1), errorsSum.DataPoints[0].Value)
+
+	require.NoError(t, registry.Close(ctx))
+}
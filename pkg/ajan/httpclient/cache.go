@@ -0,0 +1,312 @@
+package httpclient
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultCacheKeyPrefix     = "httpclient:"
+	DefaultCacheMaxBodySize   = 1 << 20
+	DefaultCacheRevalidateTTL = 24 * time.Hour
+)
+
+// CacheStore stores cached responses. Every connfx CacheRepository satisfies it. Get
+// returns a nil value without an error when the key does not exist.
+type CacheStore interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	SetWithExpiration(ctx context.Context, key string, value []byte, expiration time.Duration) error
+}
+
+// CachingTransport serves GET requests from a CacheStore as a shared cache would:
+// responses are stored as long as their Cache-Control or Expires headers allow, and
+// stale responses with an ETag or Last-Modified are revalidated with a conditional
+// request. Failures of the store are ignored, so requests then go to the upstream.
+type CachingTransport struct {
+	Transport http.RoundTripper
+	Store     CacheStore
+	Config    *CacheConfig
+}
+
+// cacheEntry is a stored response.
+type cacheEntry struct {
+	Header     http.Header       `json:"header"`
+	Vary       map[string]string `json:"vary,omitempty"`
+	StoredAt   time.Time         `json:"stored_at"`
+	FreshUntil time.Time         `json:"fresh_until"`
+	Body       []byte            `json:"body"`
+	StatusCode int               `json:"status_code"`
+}
+
+func NewCachingTransport(
+	transport http.RoundTripper,
+	store CacheStore,
+	config *CacheConfig,
+) *CachingTransport {
+	return &CachingTransport{
+		Transport: transport,
+		Store:     store,
+		Config:    config,
+	}
+}
+
+func (t *CachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestDirectives := parseCacheControl(req.Header.Get("Cache-Control"))
+
+	if req.Method != http.MethodGet || requestDirectives.has("no-store") {
+		return t.Transport.RoundTrip(req) //nolint:wrapcheck
+	}
+
+	ctx := req.Context()
+	key := cmp.Or(t.Config.KeyPrefix, DefaultCacheKeyPrefix) + req.URL.String()
+	now := time.Now()
+
+	entry := t.load(ctx, key, req)
+	if entry != nil && !requestDirectives.has("no-cache") && now.Before(entry.FreshUntil) {
+		return entry.response(req, now), nil
+	}
+
+	outgoing := req
+
+	if entry != nil && entry.hasValidators() {
+		outgoing = req.Clone(ctx)
+
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			outgoing.Header.Set("If-None-Match", etag)
+		}
+
+		if lastModified := entry.Header.Get("Last-Modified"); lastModified != "" {
+			outgoing.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := t.Transport.RoundTrip(outgoing)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if entry != nil && resp.StatusCode == http.StatusNotModified {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		entry.refresh(resp.Header, now)
+		t.save(ctx, key, entry, now)
+
+		return entry.response(req, now), nil
+	}
+
+	return t.storeResponse(ctx, key, req, resp, now)
+}
+
+func (t *CachingTransport) load(ctx context.Context, key string, req *http.Request) *cacheEntry {
+	value, err := t.Store.Get(ctx, key)
+	if err != nil || value == nil {
+		return nil
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(value, &entry); err != nil {
+		return nil
+	}
+
+	for name, stored := range entry.Vary {
+		if req.Header.Get(name) != stored {
+			return nil
+		}
+	}
+
+	return &entry
+}
+
+func (t *CachingTransport) save(ctx context.Context, key string, entry *cacheEntry, now time.Time) {
+	expiration := entry.FreshUntil.Sub(now)
+	if entry.hasValidators() {
+		expiration += cmp.Or(t.Config.RevalidateTTL, DefaultCacheRevalidateTTL)
+	}
+
+	if expiration <= 0 {
+		return
+	}
+
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	_ = t.Store.SetWithExpiration(ctx, key, value, expiration)
+}
+
+// storeResponse stores a cacheable response and returns it with a readable body.
+func (t *CachingTransport) storeResponse(
+	ctx context.Context,
+	key string,
+	req *http.Request,
+	resp *http.Response,
+	now time.Time,
+) (*http.Response, error) {
+	directives := parseCacheControl(resp.Header.Get("Cache-Control"))
+
+	if resp.StatusCode != http.StatusOK ||
+		directives.has("no-store") ||
+		directives.has("private") {
+		return resp, nil
+	}
+
+	// A shared cache must not reuse answers to authorized requests unless allowed to.
+	if req.Header.Get("Authorization") != "" &&
+		!directives.has("public") && !directives.has("s-maxage") {
+		return resp, nil
+	}
+
+	vary := make(map[string]string)
+
+	for _, field := range resp.Header.Values("Vary") {
+		for name := range strings.SplitSeq(field, ",") {
+			name = strings.TrimSpace(name)
+			if name == "*" {
+				return resp, nil
+			}
+
+			if name != "" {
+				vary[http.CanonicalHeaderKey(name)] = req.Header.Get(name)
+			}
+		}
+	}
+
+	entry := &cacheEntry{
+		Header:     resp.Header.Clone(),
+		Vary:       vary,
+		StoredAt:   now,
+		FreshUntil: now.Add(freshnessLifetime(resp.Header, directives, now)),
+		Body:       nil,
+		StatusCode: resp.StatusCode,
+	}
+
+	if !entry.FreshUntil.After(now) && !entry.hasValidators() {
+		return resp, nil
+	}
+
+	maxBodySize := cmp.Or(t.Config.MaxBodySize, DefaultCacheMaxBodySize)
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize+1))
+	if err != nil {
+		_ = resp.Body.Close()
+
+		return nil, fmt.Errorf("%w: %w", ErrTransportError, err)
+	}
+
+	if int64(len(body)) > maxBodySize {
+		// Too large to cache; hand out what was read followed by the rest.
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+
+		return resp, nil
+	}
+
+	_ = resp.Body.Close()
+
+	entry.Body = body
+	t.save(ctx, key, entry, now)
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return resp, nil
+}
+
+func (entry *cacheEntry) hasValidators() bool {
+	return entry.Header.Get("ETag") != "" || entry.Header.Get("Last-Modified") != ""
+}
+
+// refresh applies the headers of a 304 Not Modified answer and restarts the freshness.
+func (entry *cacheEntry) refresh(header http.Header, now time.Time) {
+	for _, name := range []string{"Cache-Control", "Date", "ETag", "Expires", "Last-Modified"} {
+		if values := header.Values(name); len(values) > 0 {
+			entry.Header[name] = values
+		}
+	}
+
+	directives := parseCacheControl(entry.Header.Get("Cache-Control"))
+
+	entry.StoredAt = now
+	entry.FreshUntil = now.Add(freshnessLifetime(entry.Header, directives, now))
+}
+
+func (entry *cacheEntry) response(req *http.Request, now time.Time) *http.Response {
+	header := entry.Header.Clone()
+	header.Set("Age", strconv.Itoa(int(now.Sub(entry.StoredAt).Seconds())))
+
+	return &http.Response{ //nolint:exhaustruct
+		Status:        fmt.Sprintf("%d %s", entry.StatusCode, http.StatusText(entry.StatusCode)),
+		StatusCode:    entry.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+	}
+}
+
+type cacheDirectives map[string]string
+
+func (directives cacheDirectives) has(name string) bool {
+	_, exists := directives[name]
+
+	return exists
+}
+
+func parseCacheControl(value string) cacheDirectives {
+	directives := make(cacheDirectives)
+
+	for part := range strings.SplitSeq(value, ",") {
+		name, argument, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if name == "" {
+			continue
+		}
+
+		directives[strings.ToLower(name)] = strings.Trim(argument, `"`)
+	}
+
+	return directives
+}
+
+// freshnessLifetime returns how long a response stays fresh in a shared cache.
+func freshnessLifetime(header http.Header, directives cacheDirectives, now time.Time) time.Duration {
+	if directives.has("no-cache") {
+		return 0
+	}
+
+	for _, name := range []string{"s-maxage", "max-age"} {
+		if directives.has(name) {
+			seconds, err := strconv.Atoi(directives[name])
+			if err != nil || seconds < 0 {
+				return 0
+			}
+
+			return time.Duration(seconds) * time.Second
+		}
+	}
+
+	expires, err := http.ParseTime(header.Get("Expires"))
+	if err != nil {
+		return 0
+	}
+
+	date, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		date = now
+	}
+
+	return max(expires.Sub(date), 0)
+}
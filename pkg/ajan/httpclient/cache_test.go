@@ -0,0 +1,139 @@
+package httpclient_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryCacheStore struct {
+	values map[string][]byte
+	mu     sync.Mutex
+}
+
+func (s *memoryCacheStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.values[key], nil
+}
+
+func (s *memoryCacheStore) SetWithExpiration(
+	_ context.Context,
+	key string,
+	value []byte,
+	_ time.Duration,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = value
+
+	return nil
+}
+
+func getBody(t *testing.T, client *httpclient.Client, url string, header http.Header) string {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+
+	defer closeBody(t, resp)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+func TestClientCache(t *testing.T) {
+	t.Parallel()
+
+	var (
+		hits        atomic.Int32
+		revalidated atomic.Int32
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/fresh", func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		_, _ = w.Write([]byte("fresh"))
+	})
+	mux.HandleFunc("/etag", func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("ETag", `"v1"`)
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			revalidated.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		_, _ = w.Write([]byte("etag"))
+	})
+	mux.HandleFunc("/no-store", func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = w.Write([]byte("no-store"))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	store := &memoryCacheStore{values: map[string][]byte{}} //nolint:exhaustruct
+	client := httpclient.NewClient(httpclient.WithCache(store))
+
+	tests := []struct {
+		name            string
+		path            string
+		header          http.Header
+		wantHits        int32
+		wantRevalidated int32
+	}{
+		{name: "fresh", path: "/fresh", header: nil, wantHits: 1, wantRevalidated: 0},
+		{name: "revalidated", path: "/etag", header: nil, wantHits: 2, wantRevalidated: 1},
+		{name: "no-store", path: "/no-store", header: nil, wantHits: 2, wantRevalidated: 0},
+		{
+			name:            "request no-cache",
+			path:            "/fresh",
+			header:          http.Header{"Cache-Control": {"no-cache"}},
+			wantHits:        1, // the first request is served from the "fresh" case
+			wantRevalidated: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { //nolint:paralleltest
+			hits.Store(0)
+			revalidated.Store(0)
+
+			want := tt.path[1:]
+			assert.Equal(t, want, getBody(t, client, server.URL+tt.path, nil))
+			assert.Equal(t, want, getBody(t, client, server.URL+tt.path, tt.header))
+
+			assert.Equal(t, tt.wantHits, hits.Load())
+			assert.Equal(t, tt.wantRevalidated, revalidated.Load())
+		})
+	}
+}
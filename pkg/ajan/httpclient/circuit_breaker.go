@@ -18,6 +18,8 @@ type CircuitBreaker struct {
 	lastFailureTime time.Time
 
 	Config *CircuitBreakerConfig
+	// OnStateChange, when set, is called on every state transition.
+	OnStateChange func(from CircuitState, to CircuitState)
 
 	state                CircuitState
 	failureCount         uint
@@ -43,7 +45,9 @@ func (cb *CircuitBreaker) IsAllowed() bool {
 		if time.Since(cb.lastFailureTime) > cb.Config.ResetTimeout {
 			cb.mu.RUnlock()
 			cb.mu.Lock()
-			cb.state = StateHalfOpen
+			if cb.state == StateOpen {
+				cb.setState(StateHalfOpen)
+			}
 			cb.halfOpenSuccessCount = 0
 			cb.mu.Unlock()
 			cb.mu.RLock()
@@ -66,7 +70,7 @@ func (cb *CircuitBreaker) OnSuccess() {
 	if cb.state == StateHalfOpen {
 		cb.halfOpenSuccessCount++
 		if cb.halfOpenSuccessCount >= cb.Config.HalfOpenSuccessNeeded {
-			cb.state = StateClosed
+			cb.setState(StateClosed)
 			cb.failureCount = 0
 		}
 	}
@@ -85,7 +89,7 @@ func (cb *CircuitBreaker) OnFailure() {
 
 	if cb.state == StateHalfOpen ||
 		(cb.state == StateClosed && cb.failureCount >= cb.Config.FailureThreshold) {
-		cb.state = StateOpen
+		cb.setState(StateOpen)
 	}
 }
 
@@ -95,3 +99,13 @@ func (cb *CircuitBreaker) State() CircuitState {
 
 	return cb.state
 }
+
+// setState changes the state and reports the transition. The caller holds cb.mu.
+func (cb *CircuitBreaker) setState(state CircuitState) {
+	from := cb.state
+	cb.state = state
+
+	if cb.OnStateChange != nil {
+		cb.OnStateChange(from, state)
+	}
+}
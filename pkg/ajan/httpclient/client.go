@@ -12,6 +12,14 @@ type Client struct {
 	Config          *Config
 	Transport       *ResilientTransport
 	TLSClientConfig *tls.Config
+
+	RequestHooks  []RequestHook
+	ResponseHooks []ResponseHook
+	Metrics       *Metrics
+	CacheStore    CacheStore
+	Signer        Signer
+
+	ClientCredentials *ClientCredentialsConfig
 }
 
 // NewClient creates a new http client with the specified circuit breaker and retry strategy.
@@ -37,8 +45,29 @@ func NewClient(options ...NewClientOption) *Client {
 			},
 
 			ServerErrorThreshold: DefaultServerErrorThreshold,
+			Timeout:              0,
+			HedgeDelay:           0,
+			Cache: CacheConfig{
+				KeyPrefix:     DefaultCacheKeyPrefix,
+				MaxBodySize:   DefaultCacheMaxBodySize,
+				RevalidateTTL: DefaultCacheRevalidateTTL,
+			},
+			RateLimit: RateLimitConfig{
+				Routes:  nil,
+				Default: RateLimit{RequestsPerSecond: 0, Burst: 0},
+				MaxWait: 0,
+			},
+			Proxy: ProxyConfig{URL: "", NoProxy: "", Username: "", Password: ""},
 		},
 		Transport: nil,
+
+		RequestHooks:  nil,
+		ResponseHooks: nil,
+		Metrics:       nil,
+		CacheStore:    nil,
+		Signer:        nil,
+
+		ClientCredentials: nil,
 	}
 
 	for _, option := range options {
@@ -60,6 +89,8 @@ func NewClient(options ...NewClientOption) *Client {
 			transport.TLSClientConfig = client.TLSClientConfig
 		}
 
+		transport.Proxy = client.Config.Proxy.proxyFunc()
+
 		resilientTransport := NewResilientTransport(
 			transport,
 			client.Config,
@@ -68,8 +99,30 @@ func NewClient(options ...NewClientOption) *Client {
 		client.Transport = resilientTransport
 	}
 
+	client.Transport.RequestHooks = append(client.Transport.RequestHooks, client.RequestHooks...)
+	client.Transport.ResponseHooks = append(client.Transport.ResponseHooks, client.ResponseHooks...)
+
+	if client.Signer != nil {
+		client.Transport.Signer = client.Signer
+	}
+
+	if client.Metrics != nil {
+		client.Transport.Metrics = client.Metrics
+		client.Transport.CircuitBreaker.OnStateChange = client.Metrics.recordCircuitTransition
+	}
+
+	var transport http.RoundTripper = client.Transport
+
+	if client.ClientCredentials != nil {
+		transport = NewTokenTransport(transport, client.ClientCredentials)
+	}
+
+	if client.CacheStore != nil {
+		transport = NewCachingTransport(transport, client.CacheStore, &client.Config.Cache)
+	}
+
 	client.Client = &http.Client{ //nolint:exhaustruct
-		Transport: client.Transport,
+		Transport: transport,
 	}
 
 	return client
@@ -394,3 +394,175 @@ func TestClientContextCancellation(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), context.DeadlineExceeded.Error())
 }
+
+func TestClientPerRequestTimeout(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lib.SleepContext(r.Context(), 100*time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := httpclient.NewClient(
+		httpclient.WithConfig(&httpclient.Config{
+			CircuitBreaker: httpclient.CircuitBreakerConfig{ //nolint:exhaustruct
+				Enabled: false,
+			},
+			RetryStrategy: httpclient.RetryStrategyConfig{ //nolint:exhaustruct
+				Enabled: false,
+			},
+			ServerErrorThreshold: 500,
+			Timeout:              20 * time.Millisecond,
+		}),
+	)
+
+	tests := []struct {
+		ctx     context.Context //nolint:containedctx
+		name    string
+		wantErr bool
+	}{
+		{name: "client timeout", ctx: t.Context(), wantErr: true},
+		{name: "longer timeout", ctx: httpclient.WithTimeout(t.Context(), time.Second), wantErr: false},
+		{name: "no timeout", ctx: httpclient.WithTimeout(t.Context(), 0), wantErr: false},
+		{
+			name:    "past deadline",
+			ctx:     httpclient.WithDeadline(t.Context(), time.Now().Add(-time.Second)),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { //nolint:paralleltest
+			req, err := http.NewRequestWithContext(tt.ctx, http.MethodGet, server.URL, nil)
+			require.NoError(t, err)
+
+			resp, err := client.Do(req)
+			defer closeBody(t, resp)
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, context.DeadlineExceeded)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
+
+func TestClientHedging(t *testing.T) {
+	t.Parallel()
+
+	var requestCount int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first request stalls, so only a hedged attempt can answer in time.
+		if atomic.AddInt32(&requestCount, 1) == 1 {
+			lib.SleepContext(r.Context(), time.Second)
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := httpclient.NewClient(
+		httpclient.WithConfig(&httpclient.Config{
+			CircuitBreaker: httpclient.CircuitBreakerConfig{ //nolint:exhaustruct
+				Enabled: false,
+			},
+			RetryStrategy: httpclient.RetryStrategyConfig{ //nolint:exhaustruct
+				Enabled: false,
+			},
+			ServerErrorThreshold: 500,
+			Timeout:              0,
+			HedgeDelay:           20 * time.Millisecond,
+		}),
+	)
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	start := time.Now()
+
+	resp, err := client.Do(req)
+	defer closeBody(t, resp)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requestCount))
+
+	// Disabled per request, the request waits for the stalled attempt.
+	atomic.StoreInt32(&requestCount, 0)
+
+	ctx := httpclient.WithHedging(t.Context(), 0)
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	start = time.Now()
+
+	resp2, err := client.Do(req)
+	defer closeBody(t, resp2)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requestCount))
+}
+
+func TestClientHooks(t *testing.T) {
+	t.Parallel()
+
+	var attemptCount int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "req-1", r.Header.Get("X-Request-Id"))
+
+		if atomic.AddInt32(&attemptCount, 1) < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var statuses []int
+
+	client := httpclient.NewClient(
+		httpclient.WithConfig(&httpclient.Config{
+			CircuitBreaker: httpclient.CircuitBreakerConfig{ //nolint:exhaustruct
+				Enabled: false,
+			},
+			RetryStrategy: httpclient.RetryStrategyConfig{
+				Enabled:         true,
+				MaxAttempts:     3,
+				InitialInterval: time.Millisecond,
+				MaxInterval:     time.Millisecond,
+				Multiplier:      1,
+				RandomFactor:    0,
+			},
+			ServerErrorThreshold: 500,
+			Timeout:              0,
+			HedgeDelay:           0,
+		}),
+		httpclient.WithRequestHook(func(req *http.Request) {
+			req.Header.Set("X-Request-Id", "req-1")
+		}),
+		httpclient.WithResponseHook(
+			func(req *http.Request, resp *http.Response, err error, duration time.Duration) {
+				assert.NoError(t, err)
+				statuses = append(statuses, resp.StatusCode)
+			},
+		),
+	)
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	defer closeBody(t, resp)
+	require.NoError(t, err)
+	assert.Equal(t, []int{http.StatusServiceUnavailable, http.StatusOK}, statuses)
+	assert.Empty(t, req.Header.Get("X-Request-Id"))
+}
@@ -9,6 +9,28 @@ type Config struct {
 	RetryStrategy  RetryStrategyConfig  `conf:"retry_strategy"`
 
 	ServerErrorThreshold int `conf:"server_error_threshold" default:"500"`
+
+	// Timeout limits each request, including its retries; WithTimeout and WithDeadline
+	// override it per request. Zero means no timeout.
+	Timeout time.Duration `conf:"timeout"`
+
+	// HedgeDelay starts a second attempt of requests still running after it, and returns
+	// the first successful response; WithHedging overrides it per request. Zero disables
+	// hedging.
+	HedgeDelay time.Duration `conf:"hedge_delay"`
+
+	// Cache configures the response cache enabled with WithCache.
+	Cache CacheConfig `conf:"cache"`
+
+	RateLimit RateLimitConfig `conf:"rate_limit"`
+
+	Proxy ProxyConfig `conf:"proxy"`
+}
+
+type CacheConfig struct {
+	KeyPrefix     string        `conf:"key_prefix"     default:"httpclient:"`
+	MaxBodySize   int64         `conf:"max_body_size"  default:"1048576"`
+	RevalidateTTL time.Duration `conf:"revalidate_ttl" default:"24h"`
 }
 
 type CircuitBreakerConfig struct {
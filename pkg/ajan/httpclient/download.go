@@ -0,0 +1,241 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const DefaultDownloadMaxResumes = 3
+
+var (
+	ErrDownloadFailed       = errors.New("download failed")
+	ErrDownloadNotResumable = errors.New("download cannot be resumed")
+	ErrChecksumMismatch     = errors.New("downloaded content does not match the checksum")
+)
+
+// ProgressFunc reports the bytes written so far and the total size, which is -1 when
+// the server did not send it.
+type ProgressFunc func(written int64, total int64)
+
+type DownloadOption func(*downloadOptions)
+
+type downloadOptions struct {
+	checksum   hash.Hash
+	expected   string
+	progress   ProgressFunc
+	maxResumes int
+}
+
+// downloader is the state of a download that is resumed across requests.
+type downloader struct {
+	client    *Client
+	url       string
+	writer    *progressWriter
+	validator string
+}
+
+// progressWriter counts the written bytes, and remembers write errors so they are
+// not mistaken for interrupted transfers.
+type progressWriter struct {
+	writer   io.Writer
+	progress ProgressFunc
+	err      error
+	written  int64
+	total    int64
+}
+
+// WithChecksum verifies the downloaded content against expected, the hex-encoded sum
+// of algorithm, e.g. WithChecksum(sha256.New(), "9f86d08...").
+func WithChecksum(algorithm hash.Hash, expected string) DownloadOption {
+	return func(options *downloadOptions) {
+		options.checksum = algorithm
+		options.expected = strings.ToLower(expected)
+	}
+}
+
+// WithProgress calls progress after every write.
+func WithProgress(progress ProgressFunc) DownloadOption {
+	return func(options *downloadOptions) {
+		options.progress = progress
+	}
+}
+
+// WithMaxResumes limits how often an interrupted download is resumed.
+func WithMaxResumes(maxResumes int) DownloadOption {
+	return func(options *downloadOptions) {
+		options.maxResumes = maxResumes
+	}
+}
+
+// DownloadToWriter streams the content at url into w and returns the number of bytes
+// written. When the transfer is interrupted, it is resumed with a Range request from
+// where it stopped; If-Range makes sure the rest belongs to the same content. Servers
+// that do not support ranges fail the download with ErrDownloadNotResumable, as the
+// bytes already written to w cannot be taken back.
+func (client *Client) DownloadToWriter(
+	ctx context.Context,
+	url string,
+	w io.Writer, //nolint:varnamelen
+	options ...DownloadOption,
+) (int64, error) {
+	opts := downloadOptions{
+		checksum:   nil,
+		expected:   "",
+		progress:   nil,
+		maxResumes: DefaultDownloadMaxResumes,
+	}
+
+	for _, option := range options {
+		option(&opts)
+	}
+
+	if opts.checksum != nil {
+		opts.checksum.Reset()
+		w = io.MultiWriter(w, opts.checksum)
+	}
+
+	download := &downloader{
+		client: client,
+		url:    url,
+		writer: &progressWriter{
+			writer:   w,
+			progress: opts.progress,
+			err:      nil,
+			written:  0,
+			total:    -1,
+		},
+		validator: "",
+	}
+
+	for resumes := 0; ; resumes++ {
+		resumable, err := download.fetch(ctx)
+		if err == nil {
+			break
+		}
+
+		if !resumable || ctx.Err() != nil || resumes >= opts.maxResumes {
+			return download.writer.written, err
+		}
+	}
+
+	if opts.checksum != nil {
+		actual := hex.EncodeToString(opts.checksum.Sum(nil))
+		if actual != opts.expected {
+			return download.writer.written, fmt.Errorf(
+				"%w (url=%q, expected=%q, actual=%q)",
+				ErrChecksumMismatch,
+				url,
+				opts.expected,
+				actual,
+			)
+		}
+	}
+
+	return download.writer.written, nil
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.written += int64(n)
+
+	if err != nil {
+		w.err = err
+	}
+
+	if w.progress != nil {
+		w.progress(w.written, w.total)
+	}
+
+	return n, err //nolint:wrapcheck
+}
+
+// fetch requests the content from the current offset and copies it to the writer. It
+// reports whether a failure may be resumed with another request.
+func (d *downloader) fetch(ctx context.Context) (bool, error) {
+	offset := d.writer.written
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.url, nil)
+	if err != nil {
+		return false, fmt.Errorf("%w (url=%q): %w", ErrDownloadFailed, d.url, err)
+	}
+
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+
+		if d.validator != "" {
+			req.Header.Set("If-Range", d.validator)
+		}
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("%w (url=%q): %w", ErrDownloadFailed, d.url, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case offset == 0 && resp.StatusCode == http.StatusOK:
+		d.writer.total = resp.ContentLength
+		d.validator = rangeValidator(resp.Header)
+	case offset > 0 && resp.StatusCode == http.StatusPartialContent:
+		if start, ok := contentRangeStart(resp.Header.Get("Content-Range")); !ok || start != offset {
+			return false, fmt.Errorf(
+				"%w (url=%q, offset=%d, content_range=%q)",
+				ErrDownloadNotResumable,
+				d.url,
+				offset,
+				resp.Header.Get("Content-Range"),
+			)
+		}
+	case offset > 0 && resp.StatusCode == http.StatusOK:
+		// The server ignored the range, or the content changed since the download started.
+		return false, fmt.Errorf("%w (url=%q, offset=%d)", ErrDownloadNotResumable, d.url, offset)
+	default:
+		return false, fmt.Errorf("%w (url=%q, status=%d)", ErrDownloadFailed, d.url, resp.StatusCode)
+	}
+
+	_, err = io.Copy(d.writer, resp.Body)
+	if err != nil {
+		return d.writer.err == nil, fmt.Errorf("%w (url=%q): %w", ErrDownloadFailed, d.url, err)
+	}
+
+	return false, nil
+}
+
+// rangeValidator returns the value for If-Range: the ETag when it is strong, or else
+// the Last-Modified date.
+func rangeValidator(header http.Header) string {
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+
+	return header.Get("Last-Modified")
+}
+
+// contentRangeStart parses the first byte position of a "bytes 100-199/200" range.
+func contentRangeStart(contentRange string) (int64, bool) {
+	rangeSpec, found := strings.CutPrefix(contentRange, "bytes ")
+	if !found {
+		return 0, false
+	}
+
+	first, _, found := strings.Cut(rangeSpec, "-")
+	if !found {
+		return 0, false
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return start, true
+}
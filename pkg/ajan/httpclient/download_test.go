@@ -0,0 +1,108 @@
+package httpclient_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientDownloadToWriter(t *testing.T) {
+	t.Parallel()
+
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	sum := sha256.Sum256(content)
+
+	var requests atomic.Int32
+
+	// The first transfer breaks off halfway; later ones honour Range requests.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+
+		if requests.Add(1) == 1 {
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			_, _ = w.Write(content[:len(content)/2])
+
+			panic(http.ErrAbortHandler)
+		}
+
+		http.ServeContent(w, r, "media.bin", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	client := httpclient.NewClient()
+
+	var (
+		buffer       bytes.Buffer
+		lastProgress int64
+		lastTotal    int64
+	)
+
+	written, err := client.DownloadToWriter(
+		t.Context(),
+		server.URL,
+		&buffer,
+		httpclient.WithChecksum(sha256.New(), hex.EncodeToString(sum[:])),
+		httpclient.WithProgress(func(written int64, total int64) {
+			lastProgress = written
+			lastTotal = total
+		}),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(len(content)), written)
+	assert.Equal(t, content, buffer.Bytes())
+	assert.Equal(t, int64(len(content)), lastProgress)
+	assert.Equal(t, int64(len(content)), lastTotal)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestClientDownloadToWriterErrors(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("media")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+
+	client := httpclient.NewClient()
+
+	//nolint:paralleltest
+	t.Run("checksum mismatch", func(t *testing.T) {
+		var buffer bytes.Buffer
+
+		_, err := client.DownloadToWriter(
+			t.Context(),
+			server.URL,
+			&buffer,
+			httpclient.WithChecksum(sha256.New(), "00"),
+		)
+		require.ErrorIs(t, err, httpclient.ErrChecksumMismatch)
+	})
+
+	//nolint:paralleltest
+	t.Run("unexpected status", func(t *testing.T) {
+		var buffer bytes.Buffer
+
+		_, err := client.DownloadToWriter(t.Context(), server.URL+"/missing", &buffer)
+		require.ErrorIs(t, err, httpclient.ErrDownloadFailed)
+		assert.Zero(t, buffer.Len())
+	})
+}
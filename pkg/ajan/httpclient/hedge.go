@@ -0,0 +1,138 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type hedgeDelayKey struct{}
+
+type hedgeResult struct {
+	resp  *http.Response
+	err   error
+	index int
+}
+
+// WithHedging returns a context that hedges requests made with it: when a request has
+// not completed after delay, a second attempt is started and the first successful
+// response is returned. This overrides Config.HedgeDelay; a non-positive delay disables
+// hedging for the request.
+//
+// Only hedge requests that are safe to send twice, and give requests with a body a
+// GetBody (http.NewRequest does so for common body types).
+func WithHedging(ctx context.Context, delay time.Duration) context.Context {
+	return context.WithValue(ctx, hedgeDelayKey{}, delay)
+}
+
+// hedgeDelay returns the hedging delay for a request, from its context override or else
+// the client-wide delay.
+func hedgeDelay(req *http.Request, delay time.Duration) time.Duration {
+	if override, ok := req.Context().Value(hedgeDelayKey{}).(time.Duration); ok {
+		return override
+	}
+
+	return delay
+}
+
+// hedgedRoundTrip runs the request, and a second attempt if the first has not completed
+// after delay. The first successful response wins and the other attempt is cancelled;
+// when both fail, the result of the last one is returned.
+func (t *ResilientTransport) hedgedRoundTrip(
+	req *http.Request,
+	delay time.Duration,
+) (*http.Response, error) {
+	if req.Body != nil && req.GetBody == nil {
+		return t.roundTrip(req)
+	}
+
+	results := make(chan hedgeResult, 2) //nolint:mnd
+	cancels := make([]context.CancelFunc, 0, 2)
+
+	start := func(index int) error {
+		ctx, cancel := context.WithCancel(req.Context())
+
+		attempt := req.Clone(ctx)
+
+		if index > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				cancel()
+
+				return err //nolint:wrapcheck
+			}
+
+			attempt.Body = body
+		}
+
+		cancels = append(cancels, cancel)
+
+		go func() {
+			resp, err := t.roundTrip(attempt)
+			results <- hedgeResult{resp: resp, err: err, index: index}
+		}()
+
+		return nil
+	}
+
+	_ = start(0)
+	running := 1
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			if start(1) == nil {
+				running++
+			}
+		case result := <-results:
+			running--
+
+			succeeded := result.err == nil &&
+				result.resp.StatusCode < t.Config.ServerErrorThreshold
+
+			if !succeeded && running > 0 {
+				discardHedge(result, cancels[result.index])
+
+				continue
+			}
+
+			for index, cancel := range cancels {
+				if index != result.index {
+					cancel()
+				}
+			}
+
+			go func(running int) {
+				for range running {
+					other := <-results
+					discardHedge(other, cancels[other.index])
+				}
+			}(running)
+
+			if result.err != nil {
+				cancels[result.index]()
+
+				return nil, result.err
+			}
+
+			result.resp.Body = &cancelOnCloseBody{
+				ReadCloser: result.resp.Body,
+				cancel:     cancels[result.index],
+			}
+
+			return result.resp, nil
+		}
+	}
+}
+
+// discardHedge releases the response and context of an attempt that lost.
+func discardHedge(result hedgeResult, cancel context.CancelFunc) {
+	if result.resp != nil && result.resp.Body != nil {
+		_ = result.resp.Body.Close()
+	}
+
+	cancel()
+}
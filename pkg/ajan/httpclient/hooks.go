@@ -0,0 +1,44 @@
+package httpclient
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// RequestHook is called before every attempt of a request, including retries and hedged
+// attempts. It may modify the request, e.g. to inject headers; the caller's request is
+// not changed.
+type RequestHook func(req *http.Request)
+
+// ResponseHook is called after every attempt of a request with its outcome: a response,
+// or the transport error.
+type ResponseHook func(req *http.Request, resp *http.Response, err error, duration time.Duration)
+
+// LogResponses returns a response hook that logs every attempt, at warn level when it
+// failed or returned a server error.
+func LogResponses(logger *slog.Logger, serverErrorThreshold int) ResponseHook {
+	return func(req *http.Request, resp *http.Response, err error, duration time.Duration) {
+		attrs := []slog.Attr{
+			slog.String("method", req.Method),
+			slog.String("host", req.URL.Host),
+			slog.String("path", req.URL.Path),
+			slog.Duration("duration", duration),
+		}
+
+		level := slog.LevelDebug
+
+		if err != nil {
+			level = slog.LevelWarn
+			attrs = append(attrs, slog.Any("error", err))
+		} else {
+			attrs = append(attrs, slog.Int("status", resp.StatusCode))
+
+			if resp.StatusCode >= serverErrorThreshold {
+				level = slog.LevelWarn
+			}
+		}
+
+		logger.LogAttrs(req.Context(), level, "http client request", attrs...)
+	}
+}
@@ -0,0 +1,127 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+)
+
+var (
+	ErrFailedToBuildRequestsCounter = errors.New(
+		"failed to build HTTP client requests counter",
+	)
+	ErrFailedToBuildRequestDurationHistogram = errors.New(
+		"failed to build HTTP client request duration histogram",
+	)
+	ErrFailedToBuildRetriesCounter = errors.New(
+		"failed to build HTTP client retries counter",
+	)
+	ErrFailedToBuildCircuitTransitionsCounter = errors.New(
+		"failed to build HTTP client circuit transitions counter",
+	)
+)
+
+// Metrics holds the metrics of the requests made to upstream services.
+type Metrics struct {
+	builder *logfx.MetricsBuilder
+
+	RequestsTotal      *logfx.CounterMetric
+	RequestDuration    *logfx.HistogramMetric
+	RetriesTotal       *logfx.CounterMetric
+	CircuitTransitions *logfx.CounterMetric
+}
+
+// NewMetrics creates HTTP client metrics. Call Init before passing them to WithMetrics.
+func NewMetrics(builder *logfx.MetricsBuilder) *Metrics {
+	return &Metrics{
+		builder: builder,
+
+		RequestsTotal:      nil,
+		RequestDuration:    nil,
+		RetriesTotal:       nil,
+		CircuitTransitions: nil,
+	}
+}
+
+func (metrics *Metrics) Init() error {
+	requestsTotal, err := metrics.builder.Counter(
+		"http_client_requests_total",
+		"Total number of outgoing HTTP requests",
+	).WithUnit("{request}").Build()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToBuildRequestsCounter, err)
+	}
+
+	metrics.RequestsTotal = requestsTotal
+
+	requestDuration, err := metrics.builder.Histogram(
+		"http_client_request_duration_seconds",
+		"Outgoing HTTP request duration in seconds, including retries",
+	).WithDurationBuckets().Build()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToBuildRequestDurationHistogram, err)
+	}
+
+	metrics.RequestDuration = requestDuration
+
+	retriesTotal, err := metrics.builder.Counter(
+		"http_client_retries_total",
+		"Total number of outgoing HTTP request retries",
+	).WithUnit("{retry}").Build()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToBuildRetriesCounter, err)
+	}
+
+	metrics.RetriesTotal = retriesTotal
+
+	circuitTransitions, err := metrics.builder.Counter(
+		"http_client_circuit_transitions_total",
+		"Total number of circuit breaker state transitions",
+	).WithUnit("{transition}").Build()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToBuildCircuitTransitionsCounter, err)
+	}
+
+	metrics.CircuitTransitions = circuitTransitions
+
+	return nil
+}
+
+func (metrics *Metrics) recordRequest(
+	req *http.Request,
+	resp *http.Response,
+	err error,
+	duration time.Duration,
+) {
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+
+	attrs := []any{
+		slog.String("http.method", req.Method),
+		slog.String("http.host", req.URL.Host),
+		slog.String("http.status_code", status),
+	}
+
+	metrics.RequestsTotal.Inc(req.Context(), attrs...)
+	metrics.RequestDuration.RecordDuration(req.Context(), duration, attrs...)
+}
+
+func (metrics *Metrics) recordRetry(req *http.Request) {
+	metrics.RetriesTotal.Inc(req.Context(),
+		slog.String("http.method", req.Method),
+		slog.String("http.host", req.URL.Host))
+}
+
+func (metrics *Metrics) recordCircuitTransition(from CircuitState, to CircuitState) {
+	metrics.CircuitTransitions.Inc(context.Background(),
+		slog.String("circuit.from", from.String()),
+		slog.String("circuit.to", to.String()))
+}
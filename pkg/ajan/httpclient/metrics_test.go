@@ -0,0 +1,84 @@
+package httpclient_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpclient"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestClientMetrics(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	metrics := httpclient.NewMetrics(logfx.NewMetricsBuilder(meterProvider, "httpclient_test"))
+	require.NoError(t, metrics.Init())
+
+	client := httpclient.NewClient(
+		httpclient.WithConfig(&httpclient.Config{
+			CircuitBreaker: httpclient.CircuitBreakerConfig{
+				Enabled:               true,
+				FailureThreshold:      3,
+				ResetTimeout:          time.Minute,
+				HalfOpenSuccessNeeded: 1,
+			},
+			RetryStrategy: httpclient.RetryStrategyConfig{
+				Enabled:         true,
+				MaxAttempts:     2,
+				InitialInterval: time.Millisecond,
+				MaxInterval:     time.Millisecond,
+				Multiplier:      1,
+				RandomFactor:    0,
+			},
+			ServerErrorThreshold: 500,
+			Timeout:              0,
+			HedgeDelay:           0,
+		}),
+		httpclient.WithMetrics(metrics),
+	)
+
+	ctx := t.Context()
+
+	// Two requests of two attempts each open the circuit on the third failure.
+	for range 2 {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		resp, err := client.Do(req)
+		defer closeBody(t, resp)
+		require.Error(t, err)
+	}
+
+	var collected metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &collected))
+
+	sums := map[string]int64{}
+
+	for _, scope := range collected.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok {
+				for _, point := range sum.DataPoints {
+					sums[m.Name] += point.Value
+				}
+			}
+		}
+	}
+
+	assert.Equal(t, int64(2), sums["http_client_requests_total"])
+	assert.Equal(t, int64(1), sums["http_client_retries_total"])
+	assert.Equal(t, int64(1), sums["http_client_circuit_transitions_total"])
+}
@@ -0,0 +1,262 @@
+package httpclient
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultTokenExpiryDelta = 30 * time.Second
+
+	maxTokenResponseSize = 1 << 20
+)
+
+var (
+	ErrTokenRequestFailed   = errors.New("failed to obtain an access token")
+	ErrInvalidTokenResponse = errors.New("invalid access token response")
+)
+
+// ClientCredentialsConfig configures the OAuth2 client-credentials grant (RFC 6749,
+// section 4.4) used by TokenTransport.
+type ClientCredentialsConfig struct {
+	TokenURL     string `conf:"token_url"`
+	ClientID     string `conf:"client_id"`
+	ClientSecret string `conf:"client_secret"`
+	// Scope is the space-separated list of requested scopes.
+	Scope string `conf:"scope"`
+	// ExpiryDelta renews tokens this long before they expire, to allow for clock skew
+	// and the time a request takes to reach the API.
+	ExpiryDelta time.Duration `conf:"expiry_delta" default:"30s"`
+}
+
+// Token is an OAuth2 access token. A zero Expiry means the token does not expire.
+type Token struct {
+	Expiry      time.Time
+	AccessToken string
+	TokenType   string
+}
+
+// TokenTransport authorizes requests with bearer tokens obtained with the
+// client-credentials grant. Tokens are cached until shortly before they expire, and
+// concurrent requests share a single token request. When the API answers 401
+// Unauthorized, the token is dropped and the request is sent once more with a new one.
+type TokenTransport struct {
+	Transport http.RoundTripper
+	Config    *ClientCredentialsConfig
+
+	token   *Token
+	refresh *tokenRefresh
+	mu      sync.Mutex
+}
+
+// tokenRefresh is a token request that concurrent callers wait for.
+type tokenRefresh struct {
+	done  chan struct{}
+	token *Token
+	err   error
+}
+
+func NewTokenTransport(transport http.RoundTripper, config *ClientCredentialsConfig) *TokenTransport {
+	return &TokenTransport{
+		Transport: transport,
+		Config:    config,
+
+		token:   nil,
+		refresh: nil,
+		mu:      sync.Mutex{},
+	}
+}
+
+// Token returns a valid token, requesting a new one when there is none or it is about to
+// expire.
+func (t *TokenTransport) Token(ctx context.Context) (*Token, error) {
+	t.mu.Lock()
+
+	if t.token != nil && t.token.valid(cmp.Or(t.Config.ExpiryDelta, DefaultTokenExpiryDelta)) {
+		token := t.token
+		t.mu.Unlock()
+
+		return token, nil
+	}
+
+	refresh := t.refresh
+	if refresh == nil {
+		refresh = &tokenRefresh{done: make(chan struct{}), token: nil, err: nil}
+		t.refresh = refresh
+
+		// The token request outlives a caller that gives up, as others may wait for it.
+		go t.fetch(context.WithoutCancel(ctx), refresh)
+	}
+
+	t.mu.Unlock()
+
+	select {
+	case <-refresh.done:
+		return refresh.token, refresh.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w: %w", ErrTokenRequestFailed, ctx.Err())
+	}
+}
+
+func (t *TokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.Transport.RoundTrip(authorize(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err //nolint:wrapcheck
+	}
+
+	// The token may have been revoked before its expiry; retry once with a new one.
+	t.invalidate(token)
+
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	retry := req.Clone(req.Context())
+
+	if req.Body != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return resp, nil //nolint:nilerr
+		}
+	}
+
+	token, err = t.Token(req.Context())
+	if err != nil {
+		return resp, nil //nolint:nilerr
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	return t.Transport.RoundTrip(authorize(retry, token)) //nolint:wrapcheck
+}
+
+func (t *TokenTransport) fetch(ctx context.Context, refresh *tokenRefresh) {
+	token, err := t.requestToken(ctx)
+
+	t.mu.Lock()
+
+	if err == nil {
+		t.token = token
+	}
+
+	t.refresh = nil
+	t.mu.Unlock()
+
+	refresh.token = token
+	refresh.err = err
+	close(refresh.done)
+}
+
+// invalidate drops the token unless it was already replaced.
+func (t *TokenTransport) invalidate(token *Token) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token == token {
+		t.token = nil
+	}
+}
+
+func (t *TokenTransport) requestToken(ctx context.Context) (*Token, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if t.Config.Scope != "" {
+		form.Set("scope", t.Config.Scope)
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		t.Config.TokenURL,
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenRequestFailed, err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(t.Config.ClientID), url.QueryEscape(t.Config.ClientSecret))
+
+	requestedAt := time.Now()
+
+	resp, err := t.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenRequestFailed, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTokenResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenRequestFailed, err)
+	}
+
+	var payload struct {
+		AccessToken      string `json:"access_token"`
+		TokenType        string `json:"token_type"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+		ExpiresIn        int64  `json:"expires_in"`
+	}
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w (status=%d): %w", ErrInvalidTokenResponse, resp.StatusCode, err)
+	}
+
+	if resp.StatusCode != http.StatusOK || payload.AccessToken == "" {
+		return nil, fmt.Errorf(
+			"%w (status=%d, error=%q, description=%q)",
+			ErrTokenRequestFailed,
+			resp.StatusCode,
+			payload.Error,
+			payload.ErrorDescription,
+		)
+	}
+
+	token := &Token{
+		Expiry:      time.Time{},
+		AccessToken: payload.AccessToken,
+		TokenType:   cmp.Or(payload.TokenType, "Bearer"),
+	}
+
+	// The lifetime counts from when the server issued the token, so measure it from
+	// when the request was sent.
+	if payload.ExpiresIn > 0 {
+		token.Expiry = requestedAt.Add(time.Duration(payload.ExpiresIn) * time.Second)
+	}
+
+	return token, nil
+}
+
+func (token *Token) valid(expiryDelta time.Duration) bool {
+	return token.Expiry.IsZero() || time.Now().Add(expiryDelta).Before(token.Expiry)
+}
+
+// authorize returns a copy of the request that carries the token.
+func authorize(req *http.Request, token *Token) *http.Request {
+	authorized := req.Clone(req.Context())
+
+	tokenType := token.TokenType
+	if strings.EqualFold(tokenType, "bearer") {
+		tokenType = "Bearer"
+	}
+
+	authorized.Header.Set("Authorization", tokenType+" "+token.AccessToken)
+
+	return authorized
+}
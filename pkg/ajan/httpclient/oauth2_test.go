@@ -0,0 +1,115 @@
+package httpclient_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientClientCredentials(t *testing.T) {
+	t.Parallel()
+
+	var (
+		issued  atomic.Int32
+		revoked atomic.Value
+	)
+
+	revoked.Store("")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "client", clientID)
+		assert.Equal(t, "secret", clientSecret)
+		assert.Equal(t, "client_credentials", r.PostFormValue("grant_type"))
+		assert.Equal(t, "read write", r.PostFormValue("scope"))
+
+		time.Sleep(20 * time.Millisecond) // let concurrent requests pile up
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": fmt.Sprintf("token-%d", issued.Add(1)),
+			"token_type":   "bearer",
+			"expires_in":   3600,
+		})
+	})
+	mux.HandleFunc("/api", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer "+revoked.Load().(string) { //nolint:forcetypeassert
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := httpclient.NewClient(
+		httpclient.WithClientCredentials(&httpclient.ClientCredentialsConfig{
+			TokenURL:     server.URL + "/token",
+			ClientID:     "client",
+			ClientSecret: "secret",
+			Scope:        "read write",
+			ExpiryDelta:  time.Minute,
+		}),
+	)
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			assert.Equal(t, "Bearer token-1", getBody(t, client, server.URL+"/api", nil))
+		}()
+	}
+
+	wg.Wait()
+	require.Equal(t, int32(1), issued.Load())
+
+	// A revoked token is replaced and the request is sent again.
+	revoked.Store("token-1")
+
+	assert.Equal(t, "Bearer token-2", getBody(t, client, server.URL+"/api", nil))
+	assert.Equal(t, int32(2), issued.Load())
+}
+
+func TestClientClientCredentialsError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+	}))
+	defer server.Close()
+
+	client := httpclient.NewClient(
+		httpclient.WithClientCredentials(&httpclient.ClientCredentialsConfig{ //nolint:exhaustruct
+			TokenURL: server.URL,
+			ClientID: "client",
+		}),
+	)
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	defer closeBody(t, resp)
+	require.ErrorIs(t, err, httpclient.ErrTokenRequestFailed)
+	assert.Contains(t, err.Error(), "invalid_client")
+}
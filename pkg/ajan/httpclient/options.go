@@ -10,6 +10,51 @@ func WithConfig(config *Config) NewClientOption {
 	}
 }
 
+// WithRequestHook adds a hook that is called before every request attempt.
+func WithRequestHook(hook RequestHook) NewClientOption {
+	return func(client *Client) {
+		client.RequestHooks = append(client.RequestHooks, hook)
+	}
+}
+
+// WithResponseHook adds a hook that is called after every request attempt.
+func WithResponseHook(hook ResponseHook) NewClientOption {
+	return func(client *Client) {
+		client.ResponseHooks = append(client.ResponseHooks, hook)
+	}
+}
+
+// WithMetrics records request counts and durations, retries and circuit breaker
+// transitions. The metrics must be initialized.
+func WithMetrics(metrics *Metrics) NewClientOption {
+	return func(client *Client) {
+		client.Metrics = metrics
+	}
+}
+
+// WithCache serves GET requests from store while their responses are fresh, and
+// revalidates stale ones. See CachingTransport.
+func WithCache(store CacheStore) NewClientOption {
+	return func(client *Client) {
+		client.CacheStore = store
+	}
+}
+
+// WithClientCredentials authorizes requests with OAuth2 bearer tokens obtained with the
+// client-credentials grant. See TokenTransport.
+func WithClientCredentials(config *ClientCredentialsConfig) NewClientOption {
+	return func(client *Client) {
+		client.ClientCredentials = config
+	}
+}
+
+// WithSigner signs every request attempt, e.g. with an HMACSigner or a SigV4Signer.
+func WithSigner(signer Signer) NewClientOption {
+	return func(client *Client) {
+		client.Signer = signer
+	}
+}
+
 func WithTLSClientConfig(tlsConfig *tls.Config) NewClientOption {
 	return func(client *Client) {
 		client.TLSClientConfig = tlsConfig
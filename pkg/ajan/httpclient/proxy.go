@@ -0,0 +1,89 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+var (
+	ErrInvalidProxyURL        = errors.New("invalid proxy URL")
+	ErrUnsupportedProxyScheme = errors.New("unsupported proxy scheme")
+)
+
+// ProxyConfig routes requests through an HTTP, HTTPS or SOCKS5 proxy. When URL is empty,
+// the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables apply.
+type ProxyConfig struct {
+	// URL is the proxy address, e.g. "http://proxy:3128" or "socks5://proxy:1080". Use
+	// the "socks5h" scheme to let the proxy resolve host names.
+	URL string `conf:"url"`
+	// NoProxy is a comma-separated list of hosts, domains (".example.com"), IP addresses
+	// and CIDR ranges that are reached directly. Loopback addresses always are.
+	NoProxy string `conf:"no_proxy"`
+	// Username and Password authenticate with the proxy, in place of credentials in URL.
+	Username string `conf:"username"`
+	Password string `conf:"password"`
+}
+
+// Validate checks that the proxy URL can be used.
+func (config *ProxyConfig) Validate() error {
+	_, err := config.proxyURL()
+
+	return err
+}
+
+func (config *ProxyConfig) proxyURL() (*url.URL, error) {
+	if config.URL == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	proxyURL, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProxyURL, err)
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("%w (scheme=%q)", ErrUnsupportedProxyScheme, proxyURL.Scheme)
+	}
+
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("%w (url=%q): missing host", ErrInvalidProxyURL, proxyURL.Redacted())
+	}
+
+	if config.Username != "" {
+		proxyURL.User = url.UserPassword(config.Username, config.Password)
+	}
+
+	return proxyURL, nil
+}
+
+// proxyFunc returns the proxy selection for http.Transport. An invalid proxy URL fails
+// every request; call Validate to detect it up front.
+func (config *ProxyConfig) proxyFunc() func(*http.Request) (*url.URL, error) {
+	proxyURL, err := config.proxyURL()
+	if err != nil {
+		return func(*http.Request) (*url.URL, error) {
+			return nil, err
+		}
+	}
+
+	if proxyURL == nil {
+		return http.ProxyFromEnvironment
+	}
+
+	selectProxy := (&httpproxy.Config{
+		HTTPProxy:  proxyURL.String(),
+		HTTPSProxy: proxyURL.String(),
+		NoProxy:    config.NoProxy,
+		CGI:        false,
+	}).ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return selectProxy(req.URL)
+	}
+}
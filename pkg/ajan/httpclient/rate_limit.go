@@ -0,0 +1,178 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrRateLimited = errors.New("request would wait longer than the rate limit allows")
+
+// RateLimitConfig throttles outgoing requests with token buckets, so calls to
+// rate-limited APIs are spread out before the API answers 429 Too Many Requests.
+type RateLimitConfig struct {
+	// Routes limits requests by host, or by host and path prefix, such as
+	// "api.github.com" or "api.arcade.dev/v1/tools". The longest matching route applies,
+	// and each route has its own bucket.
+	Routes map[string]RateLimit `conf:"routes"`
+	// Default limits each host that no route matches. A zero rate disables it.
+	Default RateLimit `conf:"default"`
+	// MaxWait fails requests with ErrRateLimited instead of queueing them for longer.
+	// Zero queues them until their context is done.
+	MaxWait time.Duration `conf:"max_wait"`
+}
+
+// RateLimit allows RequestsPerSecond requests on average, and bursts of up to Burst
+// requests.
+type RateLimit struct {
+	RequestsPerSecond float64 `conf:"requests_per_second"`
+	Burst             int     `conf:"burst"`
+}
+
+// RateLimiter queues requests until their route has a token.
+type RateLimiter struct {
+	Config *RateLimitConfig
+
+	buckets map[string]*tokenBucket
+	mu      sync.Mutex
+}
+
+type tokenBucket struct {
+	last   time.Time
+	rate   float64
+	burst  float64
+	tokens float64
+	mu     sync.Mutex
+}
+
+func NewRateLimiter(config *RateLimitConfig) *RateLimiter {
+	return &RateLimiter{
+		Config: config,
+
+		buckets: make(map[string]*tokenBucket),
+		mu:      sync.Mutex{},
+	}
+}
+
+// Wait blocks until the request may be sent. Requests of a route are sent in the order
+// they arrive.
+func (limiter *RateLimiter) Wait(req *http.Request) error {
+	bucket, key := limiter.bucket(req)
+	if bucket == nil {
+		return nil
+	}
+
+	delay, reserved := bucket.reserve(time.Now(), limiter.Config.MaxWait)
+	if !reserved {
+		return fmt.Errorf("%w (route=%q, wait=%s)", ErrRateLimited, key, delay)
+	}
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		bucket.cancel()
+
+		return fmt.Errorf("%w: %w", ErrRequestContextError, req.Context().Err())
+	}
+}
+
+// bucket returns the bucket of the request's route, or nil when it is not limited.
+func (limiter *RateLimiter) bucket(req *http.Request) (*tokenBucket, string) {
+	host := req.URL.Hostname()
+	target := host + req.URL.Path
+
+	key := ""
+	limit := limiter.Config.Default
+
+	for route, routeLimit := range limiter.Config.Routes {
+		if len(route) > len(key) && routeMatches(route, target) {
+			key = route
+			limit = routeLimit
+		}
+	}
+
+	if key == "" {
+		key = host
+	}
+
+	if limit.RequestsPerSecond <= 0 {
+		return nil, key
+	}
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	bucket, exists := limiter.buckets[key]
+	if !exists {
+		burst := float64(max(limit.Burst, 1))
+
+		bucket = &tokenBucket{
+			last:   time.Now(),
+			rate:   limit.RequestsPerSecond,
+			burst:  burst,
+			tokens: burst,
+			mu:     sync.Mutex{},
+		}
+		limiter.buckets[key] = bucket
+	}
+
+	return bucket, key
+}
+
+// reserve takes a token, going into debt when none is left, and returns how long the
+// caller must wait for it. A reservation that would wait longer than maxWait is not made.
+func (bucket *tokenBucket) reserve(now time.Time, maxWait time.Duration) (time.Duration, bool) {
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	if elapsed := now.Sub(bucket.last); elapsed > 0 {
+		bucket.tokens = min(bucket.tokens+elapsed.Seconds()*bucket.rate, bucket.burst)
+		bucket.last = now
+	}
+
+	tokens := bucket.tokens - 1
+
+	var delay time.Duration
+	if tokens < 0 {
+		delay = time.Duration(-tokens / bucket.rate * float64(time.Second))
+	}
+
+	if maxWait > 0 && delay > maxWait {
+		return delay, false
+	}
+
+	bucket.tokens = tokens
+
+	return delay, true
+}
+
+// cancel returns the token of a reservation that is no longer used.
+func (bucket *tokenBucket) cancel() {
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	bucket.tokens = min(bucket.tokens+1, bucket.burst)
+}
+
+// routeMatches reports whether a route ("host" or "host/path") covers the target
+// ("host/path"), matching whole path segments.
+func routeMatches(route string, target string) bool {
+	if !strings.HasPrefix(target, route) {
+		return false
+	}
+
+	return len(target) == len(route) ||
+		strings.HasSuffix(route, "/") ||
+		target[len(route)] == '/'
+}
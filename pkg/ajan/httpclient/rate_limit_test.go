@@ -0,0 +1,97 @@
+package httpclient_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterRoutes(t *testing.T) {
+	t.Parallel()
+
+	limiter := httpclient.NewRateLimiter(&httpclient.RateLimitConfig{
+		Routes: map[string]httpclient.RateLimit{
+			"api.example.com":        {RequestsPerSecond: 1, Burst: 2},
+			"api.example.com/search": {RequestsPerSecond: 1, Burst: 1},
+		},
+		Default: httpclient.RateLimit{RequestsPerSecond: 0, Burst: 0},
+		MaxWait: 100 * time.Millisecond,
+	})
+
+	wait := func(rawURL string) error {
+		target, err := url.Parse(rawURL)
+		require.NoError(t, err)
+
+		return limiter.Wait(&http.Request{URL: target}) //nolint:exhaustruct
+	}
+
+	// The search route has its own bucket of one.
+	require.NoError(t, wait("https://api.example.com/search?q=go"))
+	require.ErrorIs(t, wait("https://api.example.com/search/users"), httpclient.ErrRateLimited)
+
+	// The rest of the host shares a bucket of two; "/searching" is not under "/search".
+	require.NoError(t, wait("https://api.example.com/users"))
+	require.NoError(t, wait("https://api.example.com/searching"))
+	require.ErrorIs(t, wait("https://api.example.com/users"), httpclient.ErrRateLimited)
+
+	// Hosts without a route are not limited.
+	for range 5 {
+		require.NoError(t, wait("https://other.example.com/"))
+	}
+}
+
+func TestClientRateLimitQueues(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	config := &httpclient.Config{
+		CircuitBreaker: httpclient.CircuitBreakerConfig{ //nolint:exhaustruct
+			Enabled: false,
+		},
+		RetryStrategy: httpclient.RetryStrategyConfig{ //nolint:exhaustruct
+			Enabled: false,
+		},
+		ServerErrorThreshold: 500,
+		Timeout:              0,
+		HedgeDelay:           0,
+		Cache:                httpclient.CacheConfig{}, //nolint:exhaustruct
+		RateLimit: httpclient.RateLimitConfig{
+			Routes:  nil,
+			Default: httpclient.RateLimit{RequestsPerSecond: 20, Burst: 1},
+			MaxWait: 0,
+		},
+	}
+
+	client := httpclient.NewClient(httpclient.WithConfig(config))
+
+	start := time.Now()
+
+	for range 3 {
+		assert.Empty(t, getBody(t, client, server.URL, nil))
+	}
+
+	// One request from the burst, then one every 50ms.
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	// A request that gives up while queued fails with its context error.
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	defer closeBody(t, resp)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,184 @@
+package httpclient_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientHMACSigner(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("webhook-secret")
+
+	var verified bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(r.Header.Get(httpclient.DefaultSignatureTimestampHeader) + "."))
+		mac.Write(body)
+
+		expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		verified = string(body) == `{"event":"story.published"}` &&
+			hmac.Equal([]byte(expected), []byte(r.Header.Get(httpclient.DefaultSignatureHeader)))
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := httpclient.NewClient(httpclient.WithSigner(httpclient.NewHMACSigner(secret)))
+
+	req, err := http.NewRequestWithContext(
+		t.Context(),
+		http.MethodPost,
+		server.URL,
+		strings.NewReader(`{"event":"story.published"}`),
+	)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+
+	defer closeBody(t, resp)
+
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.True(t, verified)
+	assert.Empty(t, req.Header.Get(httpclient.DefaultSignatureHeader))
+}
+
+func TestSigV4Signer(t *testing.T) {
+	t.Parallel()
+
+	// The "get-vanilla" case of the AWS Signature Version 4 test suite.
+	signer := &httpclient.SigV4Signer{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		SessionToken:    "",
+		Region:          "us-east-1",
+		Service:         "service",
+	}
+
+	req, err := http.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"https://example.amazonaws.com/",
+		nil,
+	)
+	require.NoError(t, err)
+
+	req.Header.Set("X-Amz-Date", "20150830T123600Z")
+
+	require.NoError(t, signer.Sign(req))
+
+	assert.Equal(
+		t,
+		"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+			"SignedHeaders=host;x-amz-date, "+
+			"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		req.Header.Get("Authorization"),
+	)
+}
+
+func TestSigV4SignerPathEncoding(t *testing.T) {
+	t.Parallel()
+
+	hmacSHA256 := func(key []byte, data string) []byte {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(data))
+
+		return mac.Sum(nil)
+	}
+
+	// The signature of a canonical request, as the AWS Signature Version 4 docs derive it.
+	signatureOf := func(canonicalRequest string) string {
+		sum := sha256.Sum256([]byte(canonicalRequest))
+		stringToSign := "AWS4-HMAC-SHA256\n20150830T123600Z\n" +
+			"20150830/us-east-1/service/aws4_request\n" + hex.EncodeToString(sum[:])
+
+		key := hmacSHA256([]byte("AWS4wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"), "20150830")
+		key = hmacSHA256(key, "us-east-1")
+		key = hmacSHA256(key, "service")
+		key = hmacSHA256(key, "aws4_request")
+
+		return hex.EncodeToString(hmacSHA256(key, stringToSign))
+	}
+
+	signer := &httpclient.SigV4Signer{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		SessionToken:    "",
+		Region:          "us-east-1",
+		Service:         "service",
+	}
+
+	req, err := http.NewRequestWithContext(
+		t.Context(),
+		http.MethodGet,
+		"https://example.amazonaws.com/documents/cover image.png",
+		nil,
+	)
+	require.NoError(t, err)
+
+	req.Header.Set("X-Amz-Date", "20150830T123600Z")
+
+	require.NoError(t, signer.Sign(req))
+
+	// Services other than S3 expect each path segment to be encoded twice.
+	expected := signatureOf(strings.Join([]string{
+		http.MethodGet,
+		"/documents/cover%2520image.png",
+		"",
+		"host:example.amazonaws.com\nx-amz-date:20150830T123600Z\n",
+		"host;x-amz-date",
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+	}, "\n"))
+
+	assert.Contains(t, req.Header.Get("Authorization"), "Signature="+expected)
+}
+
+func TestSigV4SignerS3(t *testing.T) {
+	t.Parallel()
+
+	signer := &httpclient.SigV4Signer{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		SessionToken:    "session",
+		Region:          "eu-central-1",
+		Service:         "s3",
+	}
+
+	req, err := http.NewRequestWithContext(
+		t.Context(),
+		http.MethodPut,
+		"https://media.s3.eu-central-1.amazonaws.com/stories/cover image.png",
+		strings.NewReader("image"),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, signer.Sign(req))
+
+	sum := sha256.Sum256([]byte("image"))
+
+	assert.Equal(t, hex.EncodeToString(sum[:]), req.Header.Get("X-Amz-Content-Sha256"))
+	assert.Equal(t, "session", req.Header.Get("X-Amz-Security-Token"))
+	assert.Contains(
+		t,
+		req.Header.Get("Authorization"),
+		"SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token,",
+	)
+
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "image", string(body))
+}
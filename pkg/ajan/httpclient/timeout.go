@@ -0,0 +1,55 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+type requestDeadlineKey struct{}
+
+// WithTimeout returns a context that gives requests made with it the given timeout in
+// place of the client-wide Config.Timeout. The timeout covers all retry attempts and
+// reading the response body. A non-positive timeout disables the client-wide timeout.
+func WithTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	if timeout <= 0 {
+		return context.WithValue(ctx, requestDeadlineKey{}, time.Time{})
+	}
+
+	return context.WithValue(ctx, requestDeadlineKey{}, time.Now().Add(timeout))
+}
+
+// WithDeadline is like WithTimeout, but with an absolute deadline.
+func WithDeadline(ctx context.Context, deadline time.Time) context.Context {
+	return context.WithValue(ctx, requestDeadlineKey{}, deadline)
+}
+
+// requestDeadline returns the deadline for a request, from its context override or
+// else the client-wide timeout. A zero time means no deadline.
+func requestDeadline(req *http.Request, timeout time.Duration) time.Time {
+	if deadline, ok := req.Context().Value(requestDeadlineKey{}).(time.Time); ok {
+		return deadline
+	}
+
+	if timeout > 0 {
+		return time.Now().Add(timeout)
+	}
+
+	return time.Time{}
+}
+
+// cancelOnCloseBody cancels the request context once the response body is closed, so
+// the deadline keeps applying while the body is read.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+
+	return err //nolint:wrapcheck
+}
@@ -1,6 +1,7 @@
 package httpclient
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -38,6 +39,13 @@ type ResilientTransport struct {
 
 	CircuitBreaker *CircuitBreaker
 	RetryStrategy  *RetryStrategy
+	RateLimiter    *RateLimiter
+
+	RequestHooks  []RequestHook
+	ResponseHooks []ResponseHook
+	Signer        Signer
+
+	Metrics *Metrics
 }
 
 func NewResilientTransport(
@@ -53,10 +61,74 @@ func NewResilientTransport(
 
 		CircuitBreaker: cb,
 		RetryStrategy:  rs,
+		RateLimiter:    NewRateLimiter(&config.RateLimit),
+
+		RequestHooks:  nil,
+		ResponseHooks: nil,
+		Signer:        nil,
+
+		Metrics: nil,
+	}
+}
+
+// RoundTrip runs the request with the circuit breaker and retry strategy, within its
+// deadline and hedged when configured, and records it in Metrics when set.
+func (t *ResilientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.Metrics == nil {
+		return t.roundTripWithin(req)
+	}
+
+	start := time.Now()
+	resp, err := t.roundTripWithin(req)
+	t.Metrics.recordRequest(req, resp, err, time.Since(start))
+
+	return resp, err
+}
+
+// CancelRequest implements the optional CancelRequest method for http.RoundTripper.
+func (t *ResilientTransport) CancelRequest(req *http.Request) {
+	type canceler interface {
+		CancelRequest(req *http.Request)
+	}
+
+	if cr, ok := t.Transport.(canceler); ok {
+		cr.CancelRequest(req)
 	}
 }
 
-func (t *ResilientTransport) RoundTrip( //nolint:cyclop,gocognit,funlen
+// roundTripWithin runs the request within its deadline, set with WithTimeout or
+// WithDeadline, or else within Config.Timeout.
+func (t *ResilientTransport) roundTripWithin(req *http.Request) (*http.Response, error) {
+	deadline := requestDeadline(req, t.Config.Timeout)
+	if deadline.IsZero() {
+		return t.dispatch(req)
+	}
+
+	ctx, cancel := context.WithDeadline(req.Context(), deadline)
+
+	resp, err := t.dispatch(req.WithContext(ctx))
+	if err != nil || resp == nil {
+		cancel()
+
+		return resp, err
+	}
+
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+}
+
+// dispatch runs the request, hedged or not.
+func (t *ResilientTransport) dispatch(req *http.Request) (*http.Response, error) {
+	if delay := hedgeDelay(req, t.Config.HedgeDelay); delay > 0 {
+		return t.hedgedRoundTrip(req, delay)
+	}
+
+	return t.roundTrip(req)
+}
+
+// roundTrip runs the attempts of a request with the circuit breaker and retry strategy.
+func (t *ResilientTransport) roundTrip( //nolint:cyclop,gocognit,funlen
 	req *http.Request,
 ) (*http.Response, error) {
 	// Check circuit breaker before starting (only if enabled)
@@ -89,6 +161,10 @@ func (t *ResilientTransport) RoundTrip( //nolint:cyclop,gocognit,funlen
 			if err != nil {
 				return nil, err
 			}
+
+			if t.Metrics != nil {
+				t.Metrics.recordRetry(req)
+			}
 		}
 
 		// Make the request
@@ -134,20 +210,36 @@ func (t *ResilientTransport) RoundTrip( //nolint:cyclop,gocognit,funlen
 	return nil, ErrMaxRetries
 }
 
-// CancelRequest implements the optional CancelRequest method for http.RoundTripper.
-func (t *ResilientTransport) CancelRequest(req *http.Request) {
-	type canceler interface {
-		CancelRequest(req *http.Request)
+// handleRequest performs a single request attempt and handles the response.
+func (t *ResilientTransport) handleRequest(req *http.Request) (*http.Response, error) {
+	// Waiting for the rate limit is not a failure of the upstream.
+	if err := t.RateLimiter.Wait(req); err != nil {
+		return nil, err
 	}
 
-	if cr, ok := t.Transport.(canceler); ok {
-		cr.CancelRequest(req)
+	if len(t.RequestHooks) > 0 || t.Signer != nil {
+		// Hooks and signers modify the request, which must not change the caller's request.
+		req = req.Clone(req.Context())
+
+		for _, hook := range t.RequestHooks {
+			hook(req)
+		}
 	}
-}
 
-// handleRequest performs a single request attempt and handles the response.
-func (t *ResilientTransport) handleRequest(req *http.Request) (*http.Response, error) {
+	// Signing comes last, so the signature covers what the hooks changed.
+	if t.Signer != nil {
+		if err := t.Signer.Sign(req); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrRequestSigningFailed, err)
+		}
+	}
+
+	start := time.Now()
 	resp, err := t.Transport.RoundTrip(req)
+
+	for _, hook := range t.ResponseHooks {
+		hook(req, resp, err, time.Since(start))
+	}
+
 	if err != nil {
 		// Only notify circuit breaker if it's enabled
 		if t.Config.CircuitBreaker.Enabled {
@@ -0,0 +1,73 @@
+package httpfx
+
+import (
+	"context"
+	"net/http"
+	"slices"
+)
+
+const ContextKeyAuthIdentity ContextKey = "auth_identity"
+
+// AuthIdentity is the authenticated caller of a request, set by an authentication
+// middleware such as middlewares.JWTAuthMiddleware.
+type AuthIdentity struct {
+	Claims  map[string]any
+	Subject string
+	Scopes  []string
+}
+
+// WithAuthIdentity returns a context that carries the identity.
+func WithAuthIdentity(ctx context.Context, identity *AuthIdentity) context.Context {
+	return context.WithValue(ctx, ContextKeyAuthIdentity, identity)
+}
+
+// AuthIdentityFromContext returns the identity of the request, if it was authenticated.
+func AuthIdentityFromContext(ctx context.Context) (*AuthIdentity, bool) {
+	identity, ok := ctx.Value(ContextKeyAuthIdentity).(*AuthIdentity)
+
+	return identity, ok && identity != nil
+}
+
+// HasScope reports whether the identity was granted the scope.
+func (identity *AuthIdentity) HasScope(scope string) bool {
+	return slices.Contains(identity.Scopes, scope)
+}
+
+// RequireAuth rejects requests to the route that were not authenticated with 401
+// Unauthorized. An authentication middleware must run before the route's handlers.
+func (r *Route) RequireAuth() *Route {
+	r.Spec.RequiresAuth = true
+	r.Handlers = append([]Handler{requireScopes(nil)}, r.Handlers...)
+
+	return r
+}
+
+// RequireScope is like RequireAuth, and also rejects identities that lack any of the
+// scopes with 403 Forbidden.
+func (r *Route) RequireScope(scopes ...string) *Route {
+	r.Spec.RequiresAuth = true
+	r.Spec.Scopes = append(r.Spec.Scopes, scopes...)
+	r.Handlers = append([]Handler{requireScopes(scopes)}, r.Handlers...)
+
+	return r
+}
+
+func requireScopes(scopes []string) Handler {
+	return func(ctx *Context) Result {
+		identity, ok := AuthIdentityFromContext(ctx.Request.Context())
+		if !ok {
+			return ctx.Results.Unauthorized(WithPlainText("Authentication required"))
+		}
+
+		for _, scope := range scopes {
+			if !identity.HasScope(scope) {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					WithPlainText("Missing scope: "+scope),
+				)
+			}
+		}
+
+		return ctx.Next()
+	}
+}
@@ -9,17 +9,34 @@ type Config struct {
 
 	CertString        string        `conf:"cert_string"`
 	KeyString         string        `conf:"key_string"`
+	CertFile          string        `conf:"cert_file"`
+	KeyFile           string        `conf:"key_file"`
 	ReadHeaderTimeout time.Duration `conf:"read_header_timeout" default:"5s"`
 	ReadTimeout       time.Duration `conf:"read_timeout"        default:"10s"`
 	WriteTimeout      time.Duration `conf:"write_timeout"       default:"10s"`
 	IdleTimeout       time.Duration `conf:"idle_timeout"        default:"120s"`
+	RequestTimeout    time.Duration `conf:"request_timeout"     default:"8s"`
+
+	// Request limits of the middlewares.RequestLimitsMiddleware; 0 disables a limit.
+	MaxBodySize    int64 `conf:"max_body_size"    default:"1048576"`
+	MaxHeaderCount int   `conf:"max_header_count" default:"100"`
+	MaxHeaderSize  int   `conf:"max_header_size"  default:"32768"`
 
 	InitializationTimeout   time.Duration `conf:"init_timeout"     default:"25s"`
 	GracefulShutdownTimeout time.Duration `conf:"shutdown_timeout" default:"5s"`
 
 	SelfSigned bool `conf:"self_signed" default:"false"`
+	H2CEnabled bool `conf:"h2c"         default:"false"`
+
+	ACMEEnabled       bool   `conf:"acme"                default:"false"`
+	ACMEDomains       string `conf:"acme_domains"`
+	ACMEEmail         string `conf:"acme_email"`
+	ACMECacheDir      string `conf:"acme_cache_dir"      default:"./tmp/acme"`
+	ACMEDirectoryURL  string `conf:"acme_directory_url"`
+	ACMEChallengeAddr string `conf:"acme_challenge_addr"`
 
 	HealthCheckEnabled bool `conf:"health_check" default:"true"`
 	OpenAPIEnabled     bool `conf:"openapi"      default:"true"`
 	ProfilingEnabled   bool `conf:"profiling"    default:"false"`
+	MetricsEnabled     bool `conf:"metrics"      default:"false"`
 }
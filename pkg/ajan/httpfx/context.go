@@ -39,6 +39,11 @@ func (c *Context) Next() Result {
 	return c.Results.Ok()
 }
 
+// Route returns the definition of the route that matched the request.
+func (c *Context) Route() *Route {
+	return c.routeDef
+}
+
 func (c *Context) UpdateContext(ctx context.Context) {
 	c.Request = c.Request.WithContext(ctx)
 }
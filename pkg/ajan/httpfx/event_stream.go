@@ -0,0 +1,227 @@
+package httpfx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const DefaultEventStreamHeartbeatInterval = 15 * time.Second
+
+var (
+	ErrEventStreamClosed  = errors.New("event stream is closed")
+	ErrEventStreamFailed  = errors.New("failed to write to event stream")
+	ErrEventEncodeFailure = errors.New("failed to encode event data")
+)
+
+// Event is a single server-sent event. Only Data is required; multi-line data is
+// split into several data lines, as the protocol requires.
+type Event struct {
+	// ID is sent back by browsers in the Last-Event-ID header when they reconnect.
+	ID string
+	// Event is the event type; clients receive untyped events as "message".
+	Event string
+	Data  []byte
+	// Retry tells clients how long to wait before reconnecting.
+	Retry time.Duration
+}
+
+// EventStream writes server-sent events to a client. It is safe for concurrent use.
+type EventStream struct {
+	ctx        context.Context //nolint:containedctx
+	cancel     context.CancelFunc
+	writer     http.ResponseWriter
+	controller *http.ResponseController
+
+	mu sync.Mutex
+}
+
+// EventStreamFunc produces the events of a stream. The stream is closed when it
+// returns.
+type EventStreamFunc func(stream *EventStream)
+
+// EventStreamOption is a function type that modifies the eventStreamConfig.
+type EventStreamOption func(*eventStreamConfig)
+
+type eventStreamConfig struct {
+	heartbeatInterval time.Duration
+}
+
+// WithHeartbeatInterval sets how often a comment line is sent to keep idle
+// connections open through proxies, and to notice disconnected clients. A zero
+// interval disables heartbeats.
+func WithHeartbeatInterval(interval time.Duration) EventStreamOption {
+	return func(cfg *eventStreamConfig) {
+		cfg.heartbeatInterval = interval
+	}
+}
+
+// EventStream returns a result that streams server-sent events produced by fn.
+// The write timeout of the server does not apply to the stream. fn should return
+// once stream.Done() is closed, which happens when the client disconnects.
+func (r *Results) EventStream(fn EventStreamFunc, options ...EventStreamOption) Result {
+	cfg := &eventStreamConfig{
+		heartbeatInterval: DefaultEventStreamHeartbeatInterval,
+	}
+
+	for _, option := range options {
+		option(cfg)
+	}
+
+	return Result{
+		Result: okResult.New(),
+
+		InnerStatusCode:    http.StatusOK,
+		InnerRedirectToURI: "",
+		InnerContentType:   "",
+		InnerStream: func(responseWriter http.ResponseWriter, req *http.Request) {
+			serveEventStream(responseWriter, req, cfg, fn)
+		},
+		InnerBody: make([]byte, 0),
+	}
+}
+
+// Context returns a context that is canceled when the client disconnects.
+func (s *EventStream) Context() context.Context {
+	return s.ctx
+}
+
+// Done returns a channel that is closed when the client disconnects.
+func (s *EventStream) Done() <-chan struct{} {
+	return s.ctx.Done()
+}
+
+// Send writes an event and flushes it to the client.
+func (s *EventStream) Send(event Event) error {
+	var builder strings.Builder
+
+	if event.ID != "" {
+		builder.WriteString("id: " + sanitizeEventField(event.ID) + "\n")
+	}
+
+	if event.Event != "" {
+		builder.WriteString("event: " + sanitizeEventField(event.Event) + "\n")
+	}
+
+	if event.Retry > 0 {
+		builder.WriteString("retry: " + strconv.FormatInt(event.Retry.Milliseconds(), 10) + "\n")
+	}
+
+	data := strings.ReplaceAll(string(event.Data), "\r\n", "\n")
+	for line := range strings.SplitSeq(data, "\n") {
+		builder.WriteString("data: " + line + "\n")
+	}
+
+	builder.WriteString("\n")
+
+	return s.write(builder.String())
+}
+
+// SendJSON writes an event of the given type with data encoded as JSON.
+func (s *EventStream) SendJSON(eventType string, data any) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("%w (event=%q): %w", ErrEventEncodeFailure, eventType, err)
+	}
+
+	return s.Send(Event{ID: "", Event: eventType, Data: encoded, Retry: 0})
+}
+
+func (s *EventStream) write(payload string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx.Err() != nil {
+		return ErrEventStreamClosed
+	}
+
+	_, err := s.writer.Write([]byte(payload))
+	if err == nil {
+		err = s.controller.Flush()
+	}
+
+	if err != nil {
+		// A failed write means the client is gone; stop the stream.
+		s.cancel()
+
+		return fmt.Errorf("%w: %w", ErrEventStreamFailed, err)
+	}
+
+	return nil
+}
+
+func serveEventStream(
+	responseWriter http.ResponseWriter,
+	req *http.Request,
+	cfg *eventStreamConfig,
+	fn EventStreamFunc,
+) {
+	ctx, cancel := context.WithCancel(req.Context())
+
+	stream := &EventStream{ //nolint:exhaustruct
+		ctx:        ctx,
+		cancel:     cancel,
+		writer:     responseWriter,
+		controller: http.NewResponseController(responseWriter),
+	}
+	defer stream.close()
+
+	// Streams outlive the write timeout of the server; writers that do not support
+	// deadlines have none to clear.
+	_ = stream.controller.SetWriteDeadline(time.Time{})
+
+	headers := responseWriter.Header()
+	headers.Set("Content-Type", "text/event-stream")
+	headers.Set("Cache-Control", "no-cache")
+	headers.Set("Connection", "keep-alive")
+	// Keep reverse proxies such as nginx from buffering the events.
+	headers.Set("X-Accel-Buffering", "no")
+
+	responseWriter.WriteHeader(http.StatusOK)
+
+	if err := stream.controller.Flush(); err != nil {
+		return
+	}
+
+	if cfg.heartbeatInterval > 0 {
+		go stream.heartbeat(cfg.heartbeatInterval)
+	}
+
+	fn(stream)
+}
+
+// close stops the stream and waits for a write in progress, as the response writer
+// must not be used once the handler returns.
+func (s *EventStream) close() {
+	s.cancel()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+}
+
+func (s *EventStream) heartbeat(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.write(": ping\n\n"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// sanitizeEventField drops line breaks, which would end the field early.
+func sanitizeEventField(value string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(value)
+}
@@ -0,0 +1,102 @@
+package httpfx_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStream(t *testing.T) {
+	t.Parallel()
+
+	router := httpfx.NewRouter("/")
+	router.Route("GET /events", func(ctx *httpfx.Context) httpfx.Result {
+		return ctx.Results.EventStream(func(stream *httpfx.EventStream) {
+			_ = stream.Send(httpfx.Event{ID: "1", Event: "story", Data: []byte("line one\nline two")})
+			_ = stream.SendJSON("notification", map[string]string{"kind": "mention"})
+
+			<-stream.Done()
+		}, httpfx.WithHeartbeatInterval(10*time.Millisecond))
+	})
+
+	server := httptest.NewServer(router.GetMux())
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events", nil)
+	require.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer res.Body.Close() //nolint:errcheck
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(res.Body)
+
+	readBlock := func() string {
+		var lines []string
+
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+
+			if line == "\n" {
+				return strings.Join(lines, "")
+			}
+
+			lines = append(lines, line)
+		}
+	}
+
+	assert.Equal(t, "id: 1\nevent: story\ndata: line one\ndata: line two\n", readBlock())
+	assert.Equal(t, "event: notification\ndata: {\"kind\":\"mention\"}\n", readBlock())
+	assert.Equal(t, ": ping\n", readBlock())
+}
+
+func TestEventStreamDisconnect(t *testing.T) {
+	t.Parallel()
+
+	finished := make(chan struct{})
+
+	router := httpfx.NewRouter("/")
+	router.Route("GET /events", func(ctx *httpfx.Context) httpfx.Result {
+		return ctx.Results.EventStream(func(stream *httpfx.EventStream) {
+			defer close(finished)
+
+			<-stream.Done()
+		})
+	})
+
+	server := httptest.NewServer(router.GetMux())
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events", nil)
+	require.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	cancel()
+	_ = res.Body.Close()
+
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("event stream was not stopped after the client disconnected")
+	}
+}
@@ -8,9 +8,12 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"strings"
 
 	"github.com/eser/aya.is-services/pkg/ajan/lib"
 	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
 )
 
 var (
@@ -18,15 +21,20 @@ var (
 	ErrFailedToGenerateSelfSignedCert = errors.New("failed to generate self-signed certificate")
 	ErrFailedToCreateHTTPMetrics      = errors.New("failed to create HTTP metrics")
 	ErrHTTPServiceNetListenError      = errors.New("HTTP service net listen error")
+	ErrACMEDomainsRequired            = errors.New("ACME requires at least one domain")
 )
 
 type HTTPService struct {
 	InnerServer  *http.Server
 	InnerRouter  *Router
 	InnerMetrics *Metrics
+	// InnerACMEManager obtains the certificates when ACME is enabled.
+	InnerACMEManager *autocert.Manager
 
 	Config *Config
 	logger *logfx.Logger
+
+	challengeServer *http.Server
 }
 
 func NewHTTPService(
@@ -45,15 +53,27 @@ func NewHTTPService(
 		Handler: router.GetMux(),
 	}
 
+	if config.H2CEnabled {
+		// HTTP/2 with prior knowledge, as used by gRPC clients and proxies such as Envoy.
+		protocols := new(http.Protocols)
+		protocols.SetHTTP1(true)
+		protocols.SetHTTP2(true)
+		protocols.SetUnencryptedHTTP2(true)
+
+		server.Protocols = protocols
+	}
+
 	metricsBuilder := logger.NewMetricsBuilder("httpfx")
 	metrics := NewMetrics(metricsBuilder)
 
 	return &HTTPService{
-		InnerServer:  server,
-		InnerRouter:  router,
-		InnerMetrics: metrics,
-		Config:       config,
-		logger:       logger,
+		InnerServer:      server,
+		InnerRouter:      router,
+		InnerMetrics:     metrics,
+		InnerACMEManager: nil,
+		Config:           config,
+		logger:           logger,
+		challengeServer:  nil,
 	}
 }
 
@@ -65,6 +85,9 @@ func (hs *HTTPService) Router() *Router {
 	return hs.InnerRouter
 }
 
+// SetupTLS configures TLS from the first of the sources in the config: the
+// certificate strings, the certificate files, ACME, or a self-signed certificate.
+// The server runs without TLS when none is configured.
 func (hs *HTTPService) SetupTLS(ctx context.Context) error {
 	switch {
 	case hs.Config.CertString != "" && hs.Config.KeyString != "":
@@ -77,6 +100,25 @@ func (hs *HTTPService) SetupTLS(ctx context.Context) error {
 			Certificates: []tls.Certificate{cert},
 			MinVersion:   tls.VersionTLS12,
 		}
+	case hs.Config.CertFile != "" && hs.Config.KeyFile != "":
+		cert, err := tls.LoadX509KeyPair(hs.Config.CertFile, hs.Config.KeyFile)
+		if err != nil {
+			return fmt.Errorf(
+				"%w (cert_file=%q): %w",
+				ErrFailedToLoadCertificate,
+				hs.Config.CertFile,
+				err,
+			)
+		}
+
+		hs.InnerServer.TLSConfig = &tls.Config{ //nolint:exhaustruct
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}
+	case hs.Config.ACMEEnabled:
+		if err := hs.setupACME(); err != nil {
+			return err
+		}
 	case hs.Config.SelfSigned:
 		cert, err := lib.GenerateSelfSignedCert()
 		if err != nil {
@@ -87,6 +129,8 @@ func (hs *HTTPService) SetupTLS(ctx context.Context) error {
 			Certificates: []tls.Certificate{cert},
 			MinVersion:   tls.VersionTLS12,
 		}
+	case hs.Config.H2CEnabled:
+		hs.logger.InfoContext(ctx, "HTTPService is starting without TLS, serving HTTP/2 as h2c")
 	default:
 		hs.logger.WarnContext(
 			ctx,
@@ -115,6 +159,12 @@ func (hs *HTTPService) Start(ctx context.Context) (func(), error) {
 		return nil, fmt.Errorf("%w: %w", ErrHTTPServiceNetListenError, lnErr)
 	}
 
+	if err := hs.startChallengeServer(ctx); err != nil {
+		listener.Close() //nolint:errcheck,gosec
+
+		return nil, err
+	}
+
 	go func() {
 		var sErr error
 
@@ -132,9 +182,14 @@ func (hs *HTTPService) Start(ctx context.Context) (func(), error) {
 	cleanup := func() {
 		hs.logger.InfoContext(ctx, "Shutting down server...")
 
-		newCtx, cancel := context.WithTimeout(ctx, hs.Config.GracefulShutdownTimeout)
+		// ctx is usually done by now; the in-flight requests still get their time.
+		newCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), hs.Config.GracefulShutdownTimeout)
 		defer cancel()
 
+		if hs.challengeServer != nil {
+			_ = hs.challengeServer.Shutdown(newCtx)
+		}
+
 		if err := hs.InnerServer.Shutdown(newCtx); err != nil &&
 			!errors.Is(err, http.ErrServerClosed) {
 			hs.logger.ErrorContext(ctx, "HTTPService forced to shutdown", slog.Any("error", err))
@@ -147,3 +202,77 @@ func (hs *HTTPService) Start(ctx context.Context) (func(), error) {
 
 	return cleanup, nil
 }
+
+func (hs *HTTPService) setupACME() error {
+	domains := make([]string, 0)
+
+	for domain := range strings.SplitSeq(hs.Config.ACMEDomains, ",") {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+
+	if len(domains) == 0 {
+		return ErrACMEDomainsRequired
+	}
+
+	manager := &autocert.Manager{ //nolint:exhaustruct
+		Prompt:     autocert.AcceptTOS,
+		Cache:      autocert.DirCache(hs.Config.ACMECacheDir),
+		HostPolicy: autocert.HostWhitelist(domains...),
+		Email:      hs.Config.ACMEEmail,
+	}
+
+	if hs.Config.ACMEDirectoryURL != "" {
+		manager.Client = &acme.Client{ //nolint:exhaustruct
+			DirectoryURL: hs.Config.ACMEDirectoryURL,
+		}
+	}
+
+	// The TLS config answers TLS-ALPN-01 challenges on the server itself.
+	tlsConfig := manager.TLSConfig()
+	tlsConfig.MinVersion = tls.VersionTLS12
+
+	hs.InnerACMEManager = manager
+	hs.InnerServer.TLSConfig = tlsConfig
+
+	return nil
+}
+
+// startChallengeServer answers HTTP-01 challenges, and redirects other plain HTTP
+// requests to HTTPS, when ACME is used with a challenge address such as ":80".
+func (hs *HTTPService) startChallengeServer(ctx context.Context) error {
+	if hs.InnerACMEManager == nil || hs.Config.ACMEChallengeAddr == "" {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", hs.Config.ACMEChallengeAddr)
+	if err != nil {
+		return fmt.Errorf(
+			"%w (addr=%q): %w",
+			ErrHTTPServiceNetListenError,
+			hs.Config.ACMEChallengeAddr,
+			err,
+		)
+	}
+
+	hs.challengeServer = &http.Server{ //nolint:exhaustruct
+		ReadHeaderTimeout: hs.Config.ReadHeaderTimeout,
+		ReadTimeout:       hs.Config.ReadTimeout,
+		WriteTimeout:      hs.Config.WriteTimeout,
+		IdleTimeout:       hs.Config.IdleTimeout,
+
+		Addr: hs.Config.ACMEChallengeAddr,
+
+		Handler: hs.InnerACMEManager.HTTPHandler(nil),
+	}
+
+	go func() {
+		sErr := hs.challengeServer.Serve(listener)
+		if sErr != nil && !errors.Is(sErr, http.ErrServerClosed) {
+			hs.logger.ErrorContext(ctx, "HTTPService ACME challenge server error", slog.Any("error", sErr))
+		}
+	}()
+
+	return nil
+}
@@ -2,9 +2,13 @@ package httpfx_test
 
 import (
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -170,3 +174,115 @@ func TestHTTPService_Start(t *testing.T) { //nolint:funlen
 		resp2.Body.Close() //nolint:errcheck,gosec
 	}
 }
+
+func TestHTTPService_SetupTLS_CertFiles(t *testing.T) {
+	t.Parallel()
+
+	cert, err := lib.GenerateSelfSignedCert()
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}) //nolint:exhaustruct
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})               //nolint:exhaustruct
+
+	require.NoError(t, os.WriteFile(certFile, certPEM, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+
+	config := &httpfx.Config{ //nolint:exhaustruct
+		Addr:     ":8443",
+		CertFile: certFile,
+		KeyFile:  keyFile,
+	}
+
+	service := httpfx.NewHTTPService(config, httpfx.NewRouter("/"), logfx.NewLogger())
+	require.NoError(t, service.SetupTLS(t.Context()))
+
+	require.NotNil(t, service.Server().TLSConfig)
+	assert.Len(t, service.Server().TLSConfig.Certificates, 1)
+
+	config.KeyFile = filepath.Join(dir, "missing.pem")
+
+	service = httpfx.NewHTTPService(config, httpfx.NewRouter("/"), logfx.NewLogger())
+	require.ErrorIs(t, service.SetupTLS(t.Context()), httpfx.ErrFailedToLoadCertificate)
+}
+
+func TestHTTPService_SetupTLS_ACME(t *testing.T) {
+	t.Parallel()
+
+	config := &httpfx.Config{ //nolint:exhaustruct
+		Addr:         ":8443",
+		ACMEEnabled:  true,
+		ACMEDomains:  " api.aya.is, ,www.aya.is",
+		ACMECacheDir: t.TempDir(),
+	}
+
+	service := httpfx.NewHTTPService(config, httpfx.NewRouter("/"), logfx.NewLogger())
+	require.NoError(t, service.SetupTLS(t.Context()))
+
+	require.NotNil(t, service.InnerACMEManager)
+	require.NotNil(t, service.Server().TLSConfig)
+	assert.NotNil(t, service.Server().TLSConfig.GetCertificate)
+	assert.Contains(t, service.Server().TLSConfig.NextProtos, "acme-tls/1")
+	assert.GreaterOrEqual(t, service.Server().TLSConfig.MinVersion, uint16(tls.VersionTLS12))
+
+	require.NoError(t, service.InnerACMEManager.HostPolicy(t.Context(), "api.aya.is"))
+	require.Error(t, service.InnerACMEManager.HostPolicy(t.Context(), "evil.example"))
+
+	config.ACMEDomains = " , "
+
+	service = httpfx.NewHTTPService(config, httpfx.NewRouter("/"), logfx.NewLogger())
+	require.ErrorIs(t, service.SetupTLS(t.Context()), httpfx.ErrACMEDomainsRequired)
+}
+
+func TestHTTPService_Start_H2C(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	addr := listener.Addr().String()
+	listener.Close() //nolint:errcheck,gosec
+
+	router := httpfx.NewRouter("/")
+	router.Route("GET /proto", func(ctx *httpfx.Context) httpfx.Result {
+		return ctx.Results.PlainText([]byte(ctx.Request.Proto))
+	})
+
+	config := &httpfx.Config{ //nolint:exhaustruct
+		Addr:                    addr,
+		ReadHeaderTimeout:       time.Second * 10,
+		H2CEnabled:              true,
+		GracefulShutdownTimeout: time.Second * 5,
+	}
+
+	service := httpfx.NewHTTPService(config, router, logfx.NewLogger())
+
+	cleanup, err := service.Start(t.Context())
+	require.NoError(t, err)
+
+	defer cleanup()
+
+	protocols := new(http.Protocols)
+	protocols.SetUnencryptedHTTP2(true)
+
+	client := &http.Client{ //nolint:exhaustruct
+		Timeout:   time.Second * 5,
+		Transport: &http.Transport{Protocols: protocols}, //nolint:exhaustruct
+	}
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://"+addr+"/proto", nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	assert.Equal(t, 2, resp.ProtoMajor)
+}
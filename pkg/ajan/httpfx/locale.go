@@ -0,0 +1,20 @@
+package httpfx
+
+import (
+	"context"
+)
+
+const ContextKeyLocale ContextKey = "locale"
+
+// WithLocale returns a context that carries the effective locale of the request.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, ContextKeyLocale, locale)
+}
+
+// LocaleFromContext returns the effective locale of the request, as resolved by
+// middlewares.LocaleMiddleware, or an empty string when it was not resolved.
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(ContextKeyLocale).(string)
+
+	return locale
+}
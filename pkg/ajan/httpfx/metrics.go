@@ -14,14 +14,26 @@ var (
 	ErrFailedToBuildHTTPRequestDurationHistogram = errors.New(
 		"failed to build HTTP request duration histogram",
 	)
+	ErrFailedToBuildHTTPPanicsCounter = errors.New(
+		"failed to build HTTP panics counter",
+	)
+	ErrFailedToBuildHTTPRequestsInFlightCounter = errors.New(
+		"failed to build HTTP requests in flight counter",
+	)
+	ErrFailedToBuildHTTPResponseSizeHistogram = errors.New(
+		"failed to build HTTP response size histogram",
+	)
 )
 
 // Metrics holds HTTP-specific metrics using the clean logfx approach.
 type Metrics struct {
 	builder *logfx.MetricsBuilder
 
-	RequestsTotal   *logfx.CounterMetric
-	RequestDuration *logfx.HistogramMetric
+	RequestsTotal    *logfx.CounterMetric
+	RequestDuration  *logfx.HistogramMetric
+	RequestsInFlight *logfx.UpDownCounterMetric
+	ResponseSize     *logfx.HistogramMetric
+	PanicsTotal      *logfx.CounterMetric
 }
 
 // NewMetrics creates HTTP metrics using the clean logfx approach.
@@ -29,8 +41,11 @@ func NewMetrics(builder *logfx.MetricsBuilder) *Metrics {
 	return &Metrics{
 		builder: builder,
 
-		RequestsTotal:   nil,
-		RequestDuration: nil,
+		RequestsTotal:    nil,
+		RequestDuration:  nil,
+		RequestsInFlight: nil,
+		ResponseSize:     nil,
+		PanicsTotal:      nil,
 	}
 }
 
@@ -55,5 +70,35 @@ func (metrics *Metrics) Init() error {
 
 	metrics.RequestDuration = requestDuration
 
+	requestsInFlight, err := metrics.builder.UpDownCounter(
+		"http_requests_in_flight",
+		"Number of HTTP requests being served",
+	).WithUnit("{request}").Build()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToBuildHTTPRequestsInFlightCounter, err)
+	}
+
+	metrics.RequestsInFlight = requestsInFlight
+
+	responseSize, err := metrics.builder.Histogram(
+		"http_response_size_bytes",
+		"HTTP response body size in bytes",
+	).WithUnit("By").WithBuckets(100, 1_000, 10_000, 100_000, 1_000_000, 10_000_000).Build() //nolint:mnd
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToBuildHTTPResponseSizeHistogram, err)
+	}
+
+	metrics.ResponseSize = responseSize
+
+	panicsTotal, err := metrics.builder.Counter(
+		"http_panics_total",
+		"Total number of panics recovered from HTTP handlers",
+	).WithUnit("{panic}").Build()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToBuildHTTPPanicsCounter, err)
+	}
+
+	metrics.PanicsTotal = panicsTotal
+
 	return nil
 }
@@ -0,0 +1,86 @@
+package middlewares
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+)
+
+// etagHashLength is how many bytes of the SHA-256 hash of a body make its ETag.
+const etagHashLength = 16
+
+// ConditionalGetMiddleware answers GET and HEAD requests with 304 Not Modified when
+// their validators match the response. Successful buffered responses get an ETag of
+// their body, unless the handler sets one. When a request has no If-None-Match,
+// its If-Modified-Since is compared with the Last-Modified header of the handler.
+//
+// Put it before a response cache, so cached responses are validated too.
+func ConditionalGetMiddleware() httpfx.Handler {
+	return func(ctx *httpfx.Context) httpfx.Result {
+		if ctx.Request.Method != http.MethodGet && ctx.Request.Method != http.MethodHead {
+			return ctx.Next()
+		}
+
+		result := ctx.Next()
+
+		if result.StatusCode() != http.StatusOK || result.Stream() != nil {
+			return result
+		}
+
+		headers := ctx.ResponseWriter.Header()
+
+		etag := headers.Get("ETag")
+		if etag == "" {
+			sum := sha256.Sum256(result.Body())
+			etag = `"` + hex.EncodeToString(sum[:etagHashLength]) + `"`
+
+			headers.Set("ETag", etag)
+		}
+
+		if !isNotModified(ctx.Request, etag, headers.Get("Last-Modified")) {
+			return result
+		}
+
+		headers.Del("Content-Type")
+		headers.Del("Content-Length")
+
+		return ctx.Results.Bytes(nil).WithStatusCode(http.StatusNotModified)
+	}
+}
+
+// isNotModified follows RFC 9110: If-None-Match takes precedence over
+// If-Modified-Since, and compares the entity tags weakly.
+func isNotModified(req *http.Request, etag string, lastModified string) bool {
+	if ifNoneMatch := req.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for candidate := range strings.SplitSeq(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+
+			if candidate == "*" ||
+				strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	ifModifiedSince := req.Header.Get("If-Modified-Since")
+	if ifModifiedSince == "" || lastModified == "" {
+		return false
+	}
+
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+
+	return !modified.After(since)
+}
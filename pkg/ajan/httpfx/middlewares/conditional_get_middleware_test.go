@@ -0,0 +1,114 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx/middlewares"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditionalGetMiddleware(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	lastModified := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	newRouter := func() *httpfx.Router {
+		router := httpfx.NewRouter("/")
+		router.Use(middlewares.ConditionalGetMiddleware())
+
+		router.Route("GET /feed", func(ctx *httpfx.Context) httpfx.Result {
+			ctx.ResponseWriter.Header().Set("Content-Type", "application/atom+xml")
+			ctx.ResponseWriter.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+
+			return ctx.Results.Bytes([]byte("<feed></feed>"))
+		})
+		router.Route("GET /missing", func(ctx *httpfx.Context) httpfx.Result {
+			return ctx.Results.NotFound()
+		})
+
+		return router
+	}
+
+	serve := func(path string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+
+		recorder := httptest.NewRecorder()
+		newRouter().GetMux().ServeHTTP(recorder, req)
+
+		return recorder
+	}
+
+	first := serve("/feed", nil)
+	require.Equal(t, http.StatusOK, first.Code)
+
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, "<feed></feed>", first.Body.String())
+
+	t.Run("matching_etag", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := serve("/feed", map[string]string{"If-None-Match": `"other", ` + etag})
+
+		assert.Equal(t, http.StatusNotModified, recorder.Code)
+		assert.Empty(t, recorder.Body.String())
+		assert.Equal(t, etag, recorder.Header().Get("ETag"))
+	})
+
+	t.Run("weak_etag", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := serve("/feed", map[string]string{"If-None-Match": "W/" + etag})
+
+		assert.Equal(t, http.StatusNotModified, recorder.Code)
+	})
+
+	t.Run("changed_etag", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := serve("/feed", map[string]string{
+			"If-None-Match":     `"other"`,
+			"If-Modified-Since": lastModified.Format(http.TimeFormat),
+		})
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "<feed></feed>", recorder.Body.String())
+	})
+
+	t.Run("not_modified_since", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := serve("/feed", map[string]string{
+			"If-Modified-Since": lastModified.Add(time.Hour).Format(http.TimeFormat),
+		})
+
+		assert.Equal(t, http.StatusNotModified, recorder.Code)
+	})
+
+	t.Run("modified_since", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := serve("/feed", map[string]string{
+			"If-Modified-Since": lastModified.Add(-time.Hour).Format(http.TimeFormat),
+		})
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("error_response", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := serve("/missing", map[string]string{"If-None-Match": "*"})
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.Empty(t, recorder.Header().Get("ETag"))
+	})
+}
@@ -0,0 +1,182 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+)
+
+// Defaults for the CSRF cookie and header.
+const (
+	DefaultCSRFCookieName = "csrf_token"
+	DefaultCSRFHeaderName = "X-Csrf-Token"
+	DefaultCSRFMaxAge     = 12 * time.Hour
+
+	csrfTokenBytes = 32
+)
+
+// csrfConfig holds the configuration of the CSRF middleware and token handler.
+type csrfConfig struct {
+	cookieName string
+	headerName string
+	path       string
+	domain     string
+	maxAge     time.Duration
+	sameSite   http.SameSite
+	secure     bool
+}
+
+// CSRFOption is a function type that modifies the csrfConfig.
+type CSRFOption func(*csrfConfig)
+
+// WithCSRFCookieName sets the name of the cookie holding the token.
+func WithCSRFCookieName(name string) CSRFOption {
+	return func(cfg *csrfConfig) {
+		cfg.cookieName = name
+	}
+}
+
+// WithCSRFHeaderName sets the request header that must echo the token.
+func WithCSRFHeaderName(name string) CSRFOption {
+	return func(cfg *csrfConfig) {
+		cfg.headerName = name
+	}
+}
+
+// WithCSRFCookieDomain shares the cookie with subdomains, e.g. when the frontend and
+// the API are served from different subdomains.
+func WithCSRFCookieDomain(domain string) CSRFOption {
+	return func(cfg *csrfConfig) {
+		cfg.domain = domain
+	}
+}
+
+// WithCSRFMaxAge sets how long issued tokens are kept by browsers.
+func WithCSRFMaxAge(maxAge time.Duration) CSRFOption {
+	return func(cfg *csrfConfig) {
+		cfg.maxAge = maxAge
+	}
+}
+
+// WithCSRFSameSite sets the SameSite attribute of the cookie. SameSite=None requires
+// the Secure attribute, so it is set along with it.
+func WithCSRFSameSite(sameSite http.SameSite) CSRFOption {
+	return func(cfg *csrfConfig) {
+		cfg.sameSite = sameSite
+	}
+}
+
+// WithCSRFSecure sets the Secure attribute of the cookie. Enable it in production.
+func WithCSRFSecure(secure bool) CSRFOption {
+	return func(cfg *csrfConfig) {
+		cfg.secure = secure
+	}
+}
+
+// CSRFMiddleware protects cookie-authenticated requests against cross-site request
+// forgery with the double-submit-cookie pattern: requests with unsafe methods must
+// send the value of the CSRF cookie in the CSRF header as well. Other sites cannot
+// read the cookie, so they cannot forge the header. Requests without cookies carry
+// no ambient credentials, such as API clients using bearer tokens, and pass through.
+func CSRFMiddleware(options ...CSRFOption) httpfx.Handler {
+	cfg := newCSRFConfig(options...)
+
+	return func(ctx *httpfx.Context) httpfx.Result {
+		switch ctx.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			return ctx.Next()
+		}
+
+		if len(ctx.Request.Cookies()) == 0 {
+			return ctx.Next()
+		}
+
+		cookie, err := ctx.Request.Cookie(cfg.cookieName)
+		if err != nil || cookie.Value == "" {
+			return ctx.Results.Error(
+				http.StatusForbidden,
+				httpfx.WithPlainText("CSRF token cookie is missing"),
+			)
+		}
+
+		header := ctx.Request.Header.Get(cfg.headerName)
+		if subtle.ConstantTimeCompare([]byte(header), []byte(cookie.Value)) != 1 {
+			return ctx.Results.Error(
+				http.StatusForbidden,
+				httpfx.WithPlainText("CSRF token is missing or invalid"),
+			)
+		}
+
+		return ctx.Next()
+	}
+}
+
+// CSRFTokenHandler issues CSRF tokens: it sets the CSRF cookie, keeping a token the
+// client already has, and returns the token as {"token": "..."} so frontends can send
+// it in the CSRF header. Use the same options as for CSRFMiddleware.
+func CSRFTokenHandler(options ...CSRFOption) httpfx.Handler {
+	cfg := newCSRFConfig(options...)
+
+	return func(ctx *httpfx.Context) httpfx.Result {
+		token := ""
+
+		if cookie, err := ctx.Request.Cookie(cfg.cookieName); err == nil {
+			token = cookie.Value
+		}
+
+		if token == "" {
+			random := make([]byte, csrfTokenBytes)
+			if _, err := rand.Read(random); err != nil {
+				return ctx.Results.Error(
+					http.StatusInternalServerError,
+					httpfx.WithPlainText("Failed to generate CSRF token"),
+				)
+			}
+
+			token = base64.RawURLEncoding.EncodeToString(random)
+		}
+
+		// Renew the cookie on every issuance, so active clients keep their token.
+		http.SetCookie(ctx.ResponseWriter, &http.Cookie{ //nolint:exhaustruct
+			Name:     cfg.cookieName,
+			Value:    token,
+			Path:     cfg.path,
+			Domain:   cfg.domain,
+			MaxAge:   int(cfg.maxAge.Seconds()),
+			Secure:   cfg.secure,
+			HttpOnly: false, // the double-submit pattern lets scripts read the token
+			SameSite: cfg.sameSite,
+		})
+
+		ctx.ResponseWriter.Header().Set("Cache-Control", "no-store")
+
+		return ctx.Results.JSON(map[string]string{"token": token})
+	}
+}
+
+func newCSRFConfig(options ...CSRFOption) *csrfConfig {
+	cfg := &csrfConfig{
+		cookieName: DefaultCSRFCookieName,
+		headerName: DefaultCSRFHeaderName,
+		path:       "/",
+		domain:     "",
+		maxAge:     DefaultCSRFMaxAge,
+		sameSite:   http.SameSiteLaxMode,
+		secure:     false,
+	}
+
+	for _, option := range options {
+		option(cfg)
+	}
+
+	// Browsers reject SameSite=None cookies without the Secure attribute.
+	if cfg.sameSite == http.SameSiteNoneMode {
+		cfg.secure = true
+	}
+
+	return cfg
+}
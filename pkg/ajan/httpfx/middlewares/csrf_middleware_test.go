@@ -0,0 +1,86 @@
+package middlewares_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx/middlewares"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSRFTokenHandler(t *testing.T) {
+	t.Parallel()
+
+	handler := middlewares.CSRFTokenHandler(middlewares.WithCSRFSameSite(http.SameSiteNoneMode))
+
+	res := httptest.NewRecorder()
+	ctx := &httpfx.Context{ //nolint:exhaustruct
+		Request:        httptest.NewRequest(http.MethodGet, "/csrf-token", nil),
+		ResponseWriter: res,
+	}
+
+	result := handler(ctx)
+	require.Equal(t, http.StatusOK, result.StatusCode())
+
+	var payload map[string]string
+	require.NoError(t, json.Unmarshal(result.Body(), &payload))
+
+	cookies := res.Result().Cookies() //nolint:bodyclose
+	require.Len(t, cookies, 1)
+
+	assert.Equal(t, middlewares.DefaultCSRFCookieName, cookies[0].Name)
+	assert.Equal(t, payload["token"], cookies[0].Value)
+	assert.NotEmpty(t, payload["token"])
+	assert.Equal(t, http.SameSiteNoneMode, cookies[0].SameSite)
+	assert.True(t, cookies[0].Secure, "SameSite=None cookies must be secure")
+}
+
+func TestCSRFMiddleware(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	const token = "csrf-token-value"
+
+	tests := []struct {
+		name       string
+		method     string
+		cookie     string
+		header     string
+		wantStatus int
+	}{
+		{"safe method", http.MethodGet, "", "", http.StatusNoContent},
+		{"no cookies", http.MethodPost, "", "", http.StatusNoContent},
+		{"missing header", http.MethodPost, token, "", http.StatusForbidden},
+		{"wrong header", http.MethodDelete, token, "other", http.StatusForbidden},
+		{"matching header", http.MethodPost, token, token, http.StatusNoContent},
+	}
+
+	middleware := middlewares.CSRFMiddleware()
+
+	for _, tt := range tests { //nolint:varnamelen
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tt.method, "/stories", nil)
+
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "session", Value: "s"})                               //nolint:exhaustruct
+				req.AddCookie(&http.Cookie{Name: middlewares.DefaultCSRFCookieName, Value: tt.cookie}) //nolint:exhaustruct
+			}
+
+			if tt.header != "" {
+				req.Header.Set(middlewares.DefaultCSRFHeaderName, tt.header)
+			}
+
+			ctx := &httpfx.Context{ //nolint:exhaustruct
+				Request:        req,
+				ResponseWriter: httptest.NewRecorder(),
+			}
+
+			assert.Equal(t, tt.wantStatus, middleware(ctx).StatusCode())
+		})
+	}
+}
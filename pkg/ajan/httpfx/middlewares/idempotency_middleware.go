@@ -0,0 +1,280 @@
+package middlewares
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+)
+
+const (
+	DefaultIdempotencyKeyPrefix = "idempotency:"
+	// DefaultIdempotencyTTL is how long responses are replayed, long enough for
+	// clients that retry after a restart.
+	DefaultIdempotencyTTL = 24 * time.Hour
+	// DefaultIdempotencyLockTimeout is how long a request holds its key while it is
+	// being processed. Keep it longer than the request timeout.
+	DefaultIdempotencyLockTimeout = time.Minute
+
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	MaxIdempotencyKeyLength  = 255
+)
+
+// IdempotencyStore stores the responses of idempotent requests. Every connfx
+// CacheRepository satisfies it. Get returns a nil value without an error when the
+// key does not exist.
+type IdempotencyStore interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	SetWithExpiration(ctx context.Context, key string, value []byte, expiration time.Duration) error
+	Remove(ctx context.Context, keys ...string) error
+}
+
+// Idempotency makes mutating requests safe to retry. Clients send a unique
+// Idempotency-Key header with a request, and send the same key when they retry it;
+// the first response is stored and replayed to the retries instead of running the
+// handler again.
+//
+// A nil *Idempotency runs every request, so callers need no checks when it is not
+// configured.
+type Idempotency struct {
+	store  IdempotencyStore
+	prefix string
+}
+
+// idempotencyEntry is a request being processed, or its stored response.
+type idempotencyEntry struct {
+	Fingerprint string `json:"fingerprint"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	InFlight    bool   `json:"in_flight,omitempty"`
+}
+
+// IdempotencyOption defines a functional option for configuring an idempotent route.
+type IdempotencyOption func(*idempotencyConfig)
+
+// idempotencyConfig holds the internal configuration of an idempotent route.
+type idempotencyConfig struct {
+	LockTimeout time.Duration // How long a request in progress holds its key
+	Required    bool          // Whether requests without a key are rejected
+}
+
+// WithIdempotencyLockTimeout sets how long a request holds its key while it is being
+// processed. Retries within that time are rejected with 409.
+func WithIdempotencyLockTimeout(timeout time.Duration) IdempotencyOption {
+	return func(config *idempotencyConfig) {
+		config.LockTimeout = timeout
+	}
+}
+
+// WithIdempotencyKeyRequired rejects mutating requests without an Idempotency-Key
+// header with 400.
+func WithIdempotencyKeyRequired() IdempotencyOption {
+	return func(config *idempotencyConfig) {
+		config.Required = true
+	}
+}
+
+// NewIdempotency creates an Idempotency that keeps its keys under keyPrefix, or
+// DefaultIdempotencyKeyPrefix when it is empty.
+func NewIdempotency(store IdempotencyStore, keyPrefix string) *Idempotency {
+	return &Idempotency{
+		store:  store,
+		prefix: cmp.Or(keyPrefix, DefaultIdempotencyKeyPrefix),
+	}
+}
+
+// Middleware stores the responses of POST, PUT and PATCH requests that carry an
+// Idempotency-Key header for ttl, and replays them, with an Idempotent-Replayed
+// header, to requests with the same key. Keys are scoped to the authenticated
+// client, so run it after the authentication middlewares.
+//
+// A key reused for a request with another method, path, query or body is rejected
+// with 422, and a retry that arrives while the first request is still being
+// processed with 409. Server errors and streamed responses are not stored, so they
+// can be retried. Store failures are ignored, so requests then run as usual, and
+// requests that arrive at the same instant may both run, as checking and claiming a
+// key are separate round trips.
+func (i *Idempotency) Middleware(ttl time.Duration, options ...IdempotencyOption) httpfx.Handler {
+	cfg := &idempotencyConfig{
+		LockTimeout: DefaultIdempotencyLockTimeout,
+		Required:    false,
+	}
+
+	for _, option := range options {
+		option(cfg)
+	}
+
+	return func(ctx *httpfx.Context) httpfx.Result {
+		if i == nil || !isIdempotentMethod(ctx.Request.Method) {
+			return ctx.Next()
+		}
+
+		idempotencyKey := ctx.Request.Header.Get(IdempotencyKeyHeader)
+
+		switch {
+		case idempotencyKey == "" && cfg.Required:
+			return idempotencyError(ctx, http.StatusBadRequest, "Idempotency-Key header is required")
+		case idempotencyKey == "":
+			return ctx.Next()
+		case len(idempotencyKey) > MaxIdempotencyKeyLength:
+			return idempotencyError(ctx, http.StatusBadRequest, "Idempotency-Key header is too long")
+		}
+
+		fingerprint, err := requestFingerprint(ctx.Request)
+		if err != nil {
+			// RequestLimitsMiddleware reports bodies over the limit as 413 instead.
+			return idempotencyError(ctx, http.StatusBadRequest, "Failed to read request body")
+		}
+
+		requestCtx := ctx.Request.Context()
+		key := i.entryKey(requestCtx, idempotencyKey)
+
+		if entry := i.load(requestCtx, key); entry != nil {
+			return replayIdempotentResponse(ctx, entry, fingerprint)
+		}
+
+		i.save(requestCtx, key, idempotencyEntry{ //nolint:exhaustruct
+			Fingerprint: fingerprint,
+			InFlight:    true,
+		}, cfg.LockTimeout)
+
+		result := ctx.Next()
+
+		// Settle the key even when the client has gone away meanwhile.
+		settleCtx := context.WithoutCancel(requestCtx)
+
+		if result.Stream() != nil || result.StatusCode() >= http.StatusInternalServerError {
+			_ = i.store.Remove(settleCtx, key)
+
+			return result
+		}
+
+		i.save(settleCtx, key, idempotencyEntry{
+			Fingerprint: fingerprint,
+			ContentType: cmp.Or(result.ContentType(), ctx.ResponseWriter.Header().Get("Content-Type")),
+			Body:        result.Body(),
+			StatusCode:  result.StatusCode(),
+			InFlight:    false,
+		}, ttl)
+
+		return result
+	}
+}
+
+func (i *Idempotency) entryKey(ctx context.Context, idempotencyKey string) string {
+	subject := ""
+	if identity, ok := httpfx.AuthIdentityFromContext(ctx); ok {
+		subject = identity.Subject
+	}
+
+	sum := sha256.Sum256([]byte(subject + "\n" + idempotencyKey))
+
+	return i.prefix + hex.EncodeToString(sum[:])
+}
+
+func (i *Idempotency) load(ctx context.Context, key string) *idempotencyEntry {
+	raw, err := i.store.Get(ctx, key)
+	if err != nil || raw == nil {
+		return nil
+	}
+
+	var entry idempotencyEntry
+
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil
+	}
+
+	return &entry
+}
+
+func (i *Idempotency) save(ctx context.Context, key string, entry idempotencyEntry, ttl time.Duration) {
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	_ = i.store.SetWithExpiration(ctx, key, encoded, ttl)
+}
+
+func isIdempotentMethod(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
+}
+
+// requestFingerprint hashes what makes a request distinct, and leaves the body for
+// the handlers to read.
+func requestFingerprint(req *http.Request) (string, error) {
+	var body []byte
+
+	if req.Body != nil && req.Body != http.NoBody {
+		read, err := io.ReadAll(req.Body)
+		if err != nil {
+			return "", err //nolint:wrapcheck
+		}
+
+		body = read
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + " " + req.URL.Path + "?" + req.URL.RawQuery + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func replayIdempotentResponse(
+	ctx *httpfx.Context,
+	entry *idempotencyEntry,
+	fingerprint string,
+) httpfx.Result {
+	if entry.Fingerprint != fingerprint {
+		return idempotencyError(
+			ctx,
+			http.StatusUnprocessableEntity,
+			"Idempotency-Key was already used for a different request",
+		)
+	}
+
+	if entry.InFlight {
+		ctx.ResponseWriter.Header().Set("Retry-After", "1")
+
+		return idempotencyError(
+			ctx,
+			http.StatusConflict,
+			"A request with this Idempotency-Key is being processed",
+		)
+	}
+
+	headers := ctx.ResponseWriter.Header()
+	headers.Set(IdempotentReplayedHeader, "true")
+
+	if entry.ContentType != "" {
+		headers.Set("Content-Type", entry.ContentType)
+	}
+
+	result := ctx.Results.Bytes(entry.Body)
+	result.InnerStatusCode = entry.StatusCode
+
+	return result
+}
+
+func idempotencyError(ctx *httpfx.Context, statusCode int, message string) httpfx.Result {
+	errorResponse := map[string]any{
+		"error":   http.StatusText(statusCode),
+		"message": message,
+	}
+
+	result := ctx.Results.JSON(errorResponse)
+	result.InnerStatusCode = statusCode
+
+	return result
+}
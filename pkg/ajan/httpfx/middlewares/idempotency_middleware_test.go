@@ -0,0 +1,201 @@
+package middlewares_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx/middlewares"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryIdempotencyStore struct {
+	values map[string][]byte
+	mu     sync.Mutex
+}
+
+func (s *memoryIdempotencyStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.values[key], nil
+}
+
+func (s *memoryIdempotencyStore) SetWithExpiration(
+	_ context.Context,
+	key string,
+	value []byte,
+	_ time.Duration,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = value
+
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Remove(_ context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.values, key)
+	}
+
+	return nil
+}
+
+func newIdempotentRouter(
+	idempotency *middlewares.Idempotency,
+	calls *atomic.Int32,
+	options ...middlewares.IdempotencyOption,
+) *httpfx.Router {
+	router := httpfx.NewRouter("/")
+	router.Use(idempotency.Middleware(time.Hour, options...))
+	router.Route("POST /stories", func(ctx *httpfx.Context) httpfx.Result {
+		count := calls.Add(1)
+
+		result := ctx.Results.JSON(map[string]any{"id": count})
+		result.InnerStatusCode = http.StatusCreated
+
+		return result
+	})
+	router.Route("POST /failing", func(ctx *httpfx.Context) httpfx.Result {
+		calls.Add(1)
+
+		return ctx.Results.Error(http.StatusServiceUnavailable)
+	})
+
+	return router
+}
+
+func postWithKey(router *httpfx.Router, path string, key string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(middlewares.IdempotencyKeyHeader, key)
+	}
+
+	res := httptest.NewRecorder()
+	router.GetMux().ServeHTTP(res, req)
+
+	return res
+}
+
+func TestIdempotency(t *testing.T) {
+	t.Parallel()
+
+	store := &memoryIdempotencyStore{values: map[string][]byte{}} //nolint:exhaustruct
+
+	var calls atomic.Int32
+
+	router := newIdempotentRouter(middlewares.NewIdempotency(store, ""), &calls)
+
+	first := postWithKey(router, "/stories", "key-1", `{"title":"hello"}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Empty(t, first.Header().Get(middlewares.IdempotentReplayedHeader))
+
+	retry := postWithKey(router, "/stories", "key-1", `{"title":"hello"}`)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get(middlewares.IdempotentReplayedHeader))
+	assert.JSONEq(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, int32(1), calls.Load())
+
+	mismatch := postWithKey(router, "/stories", "key-1", `{"title":"other"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, mismatch.Code)
+
+	other := postWithKey(router, "/stories", "key-2", `{"title":"hello"}`)
+	assert.Equal(t, http.StatusCreated, other.Code)
+
+	withoutKey := postWithKey(router, "/stories", "", `{"title":"hello"}`)
+	assert.Equal(t, http.StatusCreated, withoutKey.Code)
+	assert.Equal(t, int32(3), calls.Load())
+
+	tooLong := postWithKey(router, "/stories", strings.Repeat("k", 256), `{}`)
+	assert.Equal(t, http.StatusBadRequest, tooLong.Code)
+}
+
+func TestIdempotency_ServerErrorsAreRetried(t *testing.T) {
+	t.Parallel()
+
+	store := &memoryIdempotencyStore{values: map[string][]byte{}} //nolint:exhaustruct
+
+	var calls atomic.Int32
+
+	router := newIdempotentRouter(middlewares.NewIdempotency(store, ""), &calls)
+
+	assert.Equal(t, http.StatusServiceUnavailable, postWithKey(router, "/failing", "key-1", "").Code)
+	assert.Equal(t, http.StatusServiceUnavailable, postWithKey(router, "/failing", "key-1", "").Code)
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Empty(t, store.values)
+}
+
+func TestIdempotency_InFlight(t *testing.T) {
+	t.Parallel()
+
+	store := &memoryIdempotencyStore{values: map[string][]byte{}} //nolint:exhaustruct
+	idempotency := middlewares.NewIdempotency(store, "")
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	router := httpfx.NewRouter("/")
+	router.Use(idempotency.Middleware(time.Hour))
+	router.Route("POST /slow", func(ctx *httpfx.Context) httpfx.Result {
+		close(started)
+		<-release
+
+		return ctx.Results.Ok()
+	})
+
+	done := make(chan int)
+
+	go func() {
+		done <- postWithKey(router, "/slow", "key-1", "").Code
+	}()
+
+	<-started
+
+	retry := postWithKey(router, "/slow", "key-1", "")
+	assert.Equal(t, http.StatusConflict, retry.Code)
+	assert.Equal(t, "1", retry.Header().Get("Retry-After"))
+
+	close(release)
+	assert.Equal(t, http.StatusNoContent, <-done)
+}
+
+func TestIdempotency_Required(t *testing.T) {
+	t.Parallel()
+
+	store := &memoryIdempotencyStore{values: map[string][]byte{}} //nolint:exhaustruct
+
+	var calls atomic.Int32
+
+	router := newIdempotentRouter(
+		middlewares.NewIdempotency(store, ""),
+		&calls,
+		middlewares.WithIdempotencyKeyRequired(),
+	)
+
+	assert.Equal(t, http.StatusBadRequest, postWithKey(router, "/stories", "", `{}`).Code)
+	assert.Equal(t, int32(0), calls.Load())
+}
+
+func TestIdempotency_Nil(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	router := newIdempotentRouter(nil, &calls)
+
+	postWithKey(router, "/stories", "key-1", `{}`)
+	postWithKey(router, "/stories", "key-1", `{}`)
+
+	assert.Equal(t, int32(2), calls.Load())
+}
@@ -0,0 +1,253 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	DefaultJWKSRefreshInterval    = time.Hour
+	DefaultJWKSMinRefreshInterval = 5 * time.Minute
+	DefaultJWKSFetchTimeout       = 10 * time.Second
+
+	maxJWKSResponseSize = 1 << 20
+)
+
+var (
+	ErrJWKSFetchFailed    = errors.New("failed to fetch JWKS")
+	ErrUnknownSigningKey  = errors.New("unknown signing key")
+	ErrUnsupportedJWKType = errors.New("unsupported JWK type")
+	ErrInvalidJWK         = errors.New("invalid JWK")
+)
+
+// jwks caches the keys of a JSON Web Key Set. Keys are fetched again once the refresh
+// interval passes, or when a token names an unknown key, so key rotations are picked up.
+// Fetches run outside the lock, one at a time, and at most once a minimum refresh
+// interval whether they succeed or not.
+type jwks struct {
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	client      *http.Client
+	keys        map[string]any
+	group       singleflight.Group
+	url         string
+	mu          sync.RWMutex
+}
+
+// jsonWebKey holds the members of RSA and EC public keys (RFC 7517, RFC 7518).
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func newJWKS(url string) *jwks {
+	return &jwks{
+		fetchedAt:   time.Time{},
+		attemptedAt: time.Time{},
+		client:      &http.Client{Timeout: DefaultJWKSFetchTimeout}, //nolint:exhaustruct
+		keys:        nil,
+		group:       singleflight.Group{},
+		url:         url,
+		mu:          sync.RWMutex{},
+	}
+}
+
+// keyFunc returns the key named by the token's "kid" header.
+func (set *jwks) keyFunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+
+		set.mu.RLock()
+		key, found := set.keys[kid]
+		isStale := time.Since(set.fetchedAt) >= DefaultJWKSRefreshInterval
+		canRefresh := time.Since(set.attemptedAt) >= DefaultJWKSMinRefreshInterval
+		set.mu.RUnlock()
+
+		if found {
+			// Keep serving the cached key while the set is refreshed in the background.
+			if isStale && canRefresh {
+				set.group.DoChan("refresh", set.refresh)
+			}
+
+			return key, nil
+		}
+
+		if canRefresh {
+			select {
+			case result := <-set.group.DoChan("refresh", set.refresh):
+				if result.Err != nil {
+					return nil, result.Err
+				}
+			case <-ctx.Done():
+				return nil, fmt.Errorf("%w (url=%q): %w", ErrJWKSFetchFailed, set.url, ctx.Err())
+			}
+
+			set.mu.RLock()
+			key, found = set.keys[kid]
+			set.mu.RUnlock()
+		}
+
+		if !found {
+			return nil, fmt.Errorf("%w (kid=%q)", ErrUnknownSigningKey, kid)
+		}
+
+		return key, nil
+	}
+}
+
+// refresh fetches the set again, unless another fetch was attempted within the minimum
+// refresh interval. It does not depend on a request, so a request that gives up does
+// not cancel the fetch the others wait for.
+func (set *jwks) refresh() (any, error) {
+	set.mu.Lock()
+
+	if time.Since(set.attemptedAt) < DefaultJWKSMinRefreshInterval {
+		set.mu.Unlock()
+
+		return nil, nil //nolint:nilnil
+	}
+
+	// Failed fetches count too, so a broken endpoint is not hammered.
+	set.attemptedAt = time.Now()
+	set.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultJWKSFetchTimeout)
+	defer cancel()
+
+	keys, err := set.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	set.mu.Lock()
+	set.keys = keys
+	set.fetchedAt = time.Now()
+	set.mu.Unlock()
+
+	return nil, nil //nolint:nilnil
+}
+
+func (set *jwks) fetch(ctx context.Context) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, set.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w (url=%q): %w", ErrJWKSFetchFailed, set.url, err)
+	}
+
+	resp, err := set.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w (url=%q): %w", ErrJWKSFetchFailed, set.url, err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w (url=%q, status=%d)", ErrJWKSFetchFailed, set.url, resp.StatusCode)
+	}
+
+	var payload struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	err = json.NewDecoder(io.LimitReader(resp.Body, maxJWKSResponseSize)).Decode(&payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w (url=%q): %w", ErrJWKSFetchFailed, set.url, err)
+	}
+
+	keys := make(map[string]any, len(payload.Keys))
+
+	for _, jwk := range payload.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			// Skip keys of other types, so one unusual key does not break the set.
+			continue
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (jwk *jsonWebKey) publicKey() (any, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeJWKInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeJWKInt(jwk.E)
+		if err != nil || !e.IsInt64() {
+			return nil, fmt.Errorf("%w (kid=%q): bad exponent", ErrInvalidJWK, jwk.Kid)
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("%w (kid=%q, crv=%q)", ErrUnsupportedJWKType, jwk.Kid, jwk.Crv)
+		}
+
+		x, err := decodeJWKInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeJWKInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		key := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+
+		// ECDH rejects points that are not on the curve.
+		if _, err := key.ECDH(); err != nil {
+			return nil, fmt.Errorf("%w (kid=%q): %w", ErrInvalidJWK, jwk.Kid, err)
+		}
+
+		return key, nil
+	default:
+		return nil, fmt.Errorf("%w (kid=%q, kty=%q)", ErrUnsupportedJWKType, jwk.Kid, jwk.Kty)
+	}
+}
+
+func decodeJWKInt(value string) (*big.Int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(decoded) == 0 {
+		return nil, fmt.Errorf("%w: bad base64url integer", ErrInvalidJWK)
+	}
+
+	return new(big.Int).SetBytes(decoded), nil
+}
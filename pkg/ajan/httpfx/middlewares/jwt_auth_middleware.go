@@ -0,0 +1,182 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrNoVerificationKey = errors.New("no JWT verification key configured")
+
+// jwtAuthConfig holds the configuration of the JWTAuthMiddleware.
+type jwtAuthConfig struct {
+	jwks         *jwks
+	secret       []byte
+	issuer       string
+	audience     string
+	subjectClaim string
+}
+
+// JWTAuthOption is a function type that modifies the jwtAuthConfig.
+type JWTAuthOption func(*jwtAuthConfig)
+
+// WithJWTSecret verifies HMAC-signed (HS256/384/512) tokens with a shared secret.
+func WithJWTSecret(secret []byte) JWTAuthOption {
+	return func(cfg *jwtAuthConfig) {
+		cfg.secret = secret
+	}
+}
+
+// WithJWKSURL verifies RSA- and ECDSA-signed tokens with the keys published at a JSON
+// Web Key Set URL, such as an identity provider's /.well-known/jwks.json.
+func WithJWKSURL(url string) JWTAuthOption {
+	return func(cfg *jwtAuthConfig) {
+		cfg.jwks = newJWKS(url)
+	}
+}
+
+// WithJWTIssuer rejects tokens whose "iss" claim differs.
+func WithJWTIssuer(issuer string) JWTAuthOption {
+	return func(cfg *jwtAuthConfig) {
+		cfg.issuer = issuer
+	}
+}
+
+// WithJWTAudience rejects tokens whose "aud" claim does not include the audience.
+func WithJWTAudience(audience string) JWTAuthOption {
+	return func(cfg *jwtAuthConfig) {
+		cfg.audience = audience
+	}
+}
+
+// WithJWTSubjectClaim names the claim that identifies the user. Defaults to "sub".
+func WithJWTSubjectClaim(claim string) JWTAuthOption {
+	return func(cfg *jwtAuthConfig) {
+		cfg.subjectClaim = claim
+	}
+}
+
+// JWTAuthMiddleware authenticates requests that carry a bearer JWT, and stores its claims
+// (ContextKeyAuthClaims) and the caller's httpfx.AuthIdentity in the request context.
+// Requests without a token pass through unauthenticated, so routes decide with
+// Route.RequireAuth and Route.RequireScope whether they need one; requests with an
+// invalid or expired token are rejected with 401 Unauthorized.
+func JWTAuthMiddleware(options ...JWTAuthOption) httpfx.Handler {
+	cfg := &jwtAuthConfig{
+		jwks:         nil,
+		secret:       nil,
+		issuer:       "",
+		audience:     "",
+		subjectClaim: "sub",
+	}
+
+	for _, option := range options {
+		option(cfg)
+	}
+
+	return func(ctx *httpfx.Context) httpfx.Result {
+		tokenString, hasToken := getBearerToken(ctx)
+		if !hasToken {
+			return ctx.Next()
+		}
+
+		claims := jwt.MapClaims{}
+
+		token, err := jwt.ParseWithClaims(
+			tokenString,
+			claims,
+			cfg.keyFunc(ctx.Request.Context()),
+			cfg.parserOptions()...,
+		)
+		if err != nil || !token.Valid {
+			return ctx.Results.Unauthorized(httpfx.WithPlainText("Invalid token"))
+		}
+
+		identity := &httpfx.AuthIdentity{
+			Claims:  claims,
+			Subject: stringClaim(claims, cfg.subjectClaim),
+			Scopes:  scopesFromClaims(claims),
+		}
+
+		requestCtx := context.WithValue(ctx.Request.Context(), ContextKeyAuthClaims, claims)
+		ctx.UpdateContext(httpfx.WithAuthIdentity(requestCtx, identity))
+
+		return ctx.Next()
+	}
+}
+
+// keyFunc picks the verification key by the token's algorithm family.
+func (cfg *jwtAuthConfig) keyFunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (any, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			if len(cfg.secret) > 0 {
+				return cfg.secret, nil
+			}
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+			if cfg.jwks != nil {
+				return cfg.jwks.keyFunc(ctx)(token)
+			}
+		}
+
+		return nil, fmt.Errorf("%w (method=%s)", ErrNoVerificationKey, token.Method.Alg())
+	}
+}
+
+func (cfg *jwtAuthConfig) parserOptions() []jwt.ParserOption {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{
+			"HS256", "HS384", "HS512",
+			"RS256", "RS384", "RS512",
+			"PS256", "PS384", "PS512",
+			"ES256", "ES384", "ES512",
+		}),
+	}
+
+	if cfg.issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.issuer))
+	}
+
+	if cfg.audience != "" {
+		options = append(options, jwt.WithAudience(cfg.audience))
+	}
+
+	return options
+}
+
+func stringClaim(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+
+	return value
+}
+
+// scopesFromClaims reads the space-separated "scope" claim (RFC 8693), or the "scp"
+// and "scopes" arrays some identity providers issue instead.
+func scopesFromClaims(claims jwt.MapClaims) []string {
+	if scope, ok := claims["scope"].(string); ok {
+		return strings.Fields(scope)
+	}
+
+	for _, name := range []string{"scp", "scopes"} {
+		switch values := claims[name].(type) {
+		case string:
+			return strings.Fields(values)
+		case []any:
+			scopes := make([]string, 0, len(values))
+
+			for _, value := range values {
+				if scope, ok := value.(string); ok {
+					scopes = append(scopes, scope)
+				}
+			}
+
+			return scopes
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package middlewares_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx/middlewares"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAuthRouter(options ...middlewares.JWTAuthOption) *httpfx.Router {
+	router := httpfx.NewRouter("/")
+	router.Use(middlewares.JWTAuthMiddleware(options...))
+
+	whoami := func(ctx *httpfx.Context) httpfx.Result {
+		identity, ok := httpfx.AuthIdentityFromContext(ctx.Request.Context())
+		if !ok {
+			return ctx.Results.PlainText([]byte("anonymous"))
+		}
+
+		return ctx.Results.PlainText([]byte(identity.Subject))
+	}
+
+	router.Route("GET /public", whoami)
+	router.Route("GET /private", whoami).RequireAuth()
+	router.Route("GET /admin", whoami).RequireScope("admin")
+
+	return router
+}
+
+func serveAuthRequest(router *httpfx.Router, path string, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res := httptest.NewRecorder()
+	router.GetMux().ServeHTTP(res, req)
+
+	return res
+}
+
+func TestJWTAuthMiddleware(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	secret := []byte("jwt-secret")
+
+	sign := func(claims jwt.MapClaims, key []byte) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+		require.NoError(t, err)
+
+		return token
+	}
+
+	valid := sign(jwt.MapClaims{
+		"sub":   "user-1",
+		"iss":   "aya",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "read admin",
+	}, secret)
+	reader := sign(jwt.MapClaims{
+		"sub":   "user-2",
+		"iss":   "aya",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "read",
+	}, secret)
+	expired := sign(jwt.MapClaims{
+		"sub": "user-1",
+		"iss": "aya",
+		"exp": time.Now().Add(-time.Hour).Unix(),
+	}, secret)
+	foreign := sign(jwt.MapClaims{"sub": "user-1", "iss": "other"}, secret)
+	forged := sign(jwt.MapClaims{"sub": "user-1", "iss": "aya"}, []byte("other-secret"))
+
+	router := newAuthRouter(middlewares.WithJWTSecret(secret), middlewares.WithJWTIssuer("aya"))
+
+	tests := []struct {
+		name       string
+		path       string
+		token      string
+		wantBody   string
+		wantStatus int
+	}{
+		{"anonymous public", "/public", "", "anonymous", http.StatusOK},
+		{"authenticated public", "/public", valid, "user-1", http.StatusOK},
+		{"anonymous private", "/private", "", "", http.StatusUnauthorized},
+		{"authenticated private", "/private", valid, "user-1", http.StatusOK},
+		{"expired token", "/public", expired, "", http.StatusUnauthorized},
+		{"wrong issuer", "/private", foreign, "", http.StatusUnauthorized},
+		{"wrong secret", "/private", forged, "", http.StatusUnauthorized},
+		{"scope granted", "/admin", valid, "user-1", http.StatusOK},
+		{"scope missing", "/admin", reader, "", http.StatusForbidden},
+	}
+
+	for _, tt := range tests { //nolint:varnamelen
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res := serveAuthRequest(router, tt.path, tt.token)
+
+			assert.Equal(t, tt.wantStatus, res.Code)
+
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, res.Body.String())
+			}
+		})
+	}
+}
+
+func TestJWTAuthMiddlewareJWKS(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key-1",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	defer jwksServer.Close()
+
+	sign := func(kid string, signingKey *rsa.PrivateKey) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"sub": "user-1",
+			"aud": "aya-api",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		})
+		token.Header["kid"] = kid
+
+		signed, err := token.SignedString(signingKey)
+		require.NoError(t, err)
+
+		return signed
+	}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	router := newAuthRouter(
+		middlewares.WithJWKSURL(jwksServer.URL),
+		middlewares.WithJWTAudience("aya-api"),
+	)
+
+	res := serveAuthRequest(router, "/private", sign("key-1", key))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "user-1", res.Body.String())
+
+	res = serveAuthRequest(router, "/private", sign("key-1", otherKey))
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+
+	res = serveAuthRequest(router, "/private", sign("key-2", key))
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+}
+
+func TestJWTAuthMiddlewareJWKSUnavailable(t *testing.T) {
+	t.Parallel()
+
+	var fetches atomic.Int32
+
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer jwksServer.Close()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"sub": "user-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = "key-1"
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	router := newAuthRouter(middlewares.WithJWKSURL(jwksServer.URL))
+
+	// concurrent requests share a single fetch, and later ones do not retry it within
+	// the minimum refresh interval.
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			res := serveAuthRequest(router, "/private", signed)
+			assert.Equal(t, http.StatusUnauthorized, res.Code)
+		}()
+	}
+
+	wg.Wait()
+
+	res := serveAuthRequest(router, "/private", signed)
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+	assert.Equal(t, int32(1), fetches.Load())
+}
@@ -0,0 +1,176 @@
+package middlewares
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+)
+
+const (
+	DefaultLocale          = "en"
+	DefaultLocalePathValue = "locale"
+)
+
+// LocaleOption defines a functional option for configuring locale resolution.
+type LocaleOption func(*localeConfig)
+
+// localeConfig holds the internal configuration for locale resolution.
+type localeConfig struct {
+	DefaultLocale    string   // Used when neither the path nor Accept-Language names a supported locale
+	PathValue        string   // Name of the path wildcard holding the locale
+	SupportedLocales []string // Locales served; only the default one when empty
+}
+
+// WithDefaultLocale sets the locale used when no supported locale is requested.
+func WithDefaultLocale(locale string) LocaleOption {
+	return func(config *localeConfig) {
+		config.DefaultLocale = locale
+	}
+}
+
+// WithSupportedLocales sets the locales served.
+func WithSupportedLocales(locales ...string) LocaleOption {
+	return func(config *localeConfig) {
+		config.SupportedLocales = locales
+	}
+}
+
+// WithLocalePathValue sets the name of the path wildcard holding the locale.
+func WithLocalePathValue(name string) LocaleOption {
+	return func(config *localeConfig) {
+		config.PathValue = name
+	}
+}
+
+// LocaleMiddleware resolves the effective locale of a request and stores it in the
+// request context, where httpfx.LocaleFromContext reads it. A locale in the path,
+// such as /tr/stories, wins; requests for unsupported locales there get 404 Not
+// Found. Routes without a locale in the path use the best supported match of the
+// Accept-Language header, or else the default locale.
+func LocaleMiddleware(options ...LocaleOption) httpfx.Handler {
+	cfg := &localeConfig{
+		DefaultLocale:    DefaultLocale,
+		PathValue:        DefaultLocalePathValue,
+		SupportedLocales: nil,
+	}
+
+	for _, option := range options {
+		option(cfg)
+	}
+
+	supportedLocales := make([]string, 0, len(cfg.SupportedLocales))
+
+	for _, locale := range cfg.SupportedLocales {
+		if locale = strings.TrimSpace(locale); locale != "" {
+			supportedLocales = append(supportedLocales, locale)
+		}
+	}
+
+	if len(supportedLocales) == 0 {
+		supportedLocales = []string{cfg.DefaultLocale}
+	}
+
+	cfg.SupportedLocales = supportedLocales
+
+	return func(ctx *httpfx.Context) httpfx.Result {
+		locale := cfg.DefaultLocale
+
+		if requested := ctx.Request.PathValue(cfg.PathValue); requested != "" {
+			index := slices.IndexFunc(cfg.SupportedLocales, func(supported string) bool {
+				return strings.EqualFold(supported, requested)
+			})
+			if index < 0 {
+				return ctx.Results.NotFound(
+					httpfx.WithPlainText("Unsupported locale: " + requested),
+				)
+			}
+
+			locale = cfg.SupportedLocales[index]
+		} else if accepted, ok := negotiateLocale(
+			cfg.SupportedLocales,
+			ctx.Request.Header.Get("Accept-Language"),
+		); ok {
+			locale = accepted
+		}
+
+		ctx.ResponseWriter.Header().Set("Content-Language", locale)
+		ctx.UpdateContext(httpfx.WithLocale(ctx.Request.Context(), locale))
+
+		return ctx.Next()
+	}
+}
+
+// negotiateLocale returns the supported locale that matches the highest-weighted
+// language of an Accept-Language header.
+func negotiateLocale(supported []string, acceptLanguage string) (string, bool) {
+	type weightedLanguage struct {
+		tag     string
+		quality float64
+	}
+
+	languages := make([]weightedLanguage, 0)
+
+	for part := range strings.SplitSeq(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+
+			quality = parsed
+		}
+
+		if quality > 0 {
+			languages = append(languages, weightedLanguage{tag: tag, quality: quality})
+		}
+	}
+
+	// Stable, so languages of equal weight keep the order of the header.
+	slices.SortStableFunc(languages, func(a, b weightedLanguage) int {
+		return cmp.Compare(b.quality, a.quality)
+	})
+
+	for _, language := range languages {
+		if locale, ok := matchLocale(supported, language.tag); ok {
+			return locale, true
+		}
+	}
+
+	return "", false
+}
+
+// matchLocale returns the supported locale equal to tag, or else the one sharing its
+// language, so "en-US" matches "en" and "pt" matches "pt-BR".
+func matchLocale(supported []string, tag string) (string, bool) {
+	for _, locale := range supported {
+		if strings.EqualFold(locale, tag) {
+			return locale, true
+		}
+	}
+
+	base := localeLanguage(tag)
+
+	for _, locale := range supported {
+		if strings.EqualFold(localeLanguage(locale), base) {
+			return locale, true
+		}
+	}
+
+	return "", false
+}
+
+func localeLanguage(tag string) string {
+	language, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+
+	return language
+}
@@ -0,0 +1,66 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx/middlewares"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocaleMiddleware(t *testing.T) {
+	t.Parallel()
+
+	router := httpfx.NewRouter("/")
+	router.Use(middlewares.LocaleMiddleware(
+		middlewares.WithDefaultLocale("en"),
+		middlewares.WithSupportedLocales("en", "tr", "pt-BR"),
+	))
+
+	locale := func(ctx *httpfx.Context) httpfx.Result {
+		return ctx.Results.PlainText([]byte(httpfx.LocaleFromContext(ctx.Request.Context())))
+	}
+
+	router.Route("GET /{locale}/stories", locale)
+	router.Route("GET /site", locale)
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptLanguage string
+		wantStatus     int
+		wantLocale     string
+	}{
+		{"path locale", "/tr/stories", "en-US", http.StatusOK, "tr"},
+		{"path locale in other case", "/PT-br/stories", "", http.StatusOK, "pt-BR"},
+		{"unsupported path locale", "/de/stories", "", http.StatusNotFound, ""},
+		{"accept-language exact", "/site", "tr", http.StatusOK, "tr"},
+		{"accept-language by weight", "/site", "de;q=0.9, en-GB;q=0.5, tr-TR;q=0.8", http.StatusOK, "tr"},
+		{"accept-language by language", "/site", "pt", http.StatusOK, "pt-BR"},
+		{"accept-language unsupported", "/site", "de, fr;q=0.5", http.StatusOK, "en"},
+		{"no accept-language", "/site", "", http.StatusOK, "en"},
+	}
+
+	for _, tt := range tests { //nolint:varnamelen
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			res := httptest.NewRecorder()
+			router.GetMux().ServeHTTP(res, req)
+
+			assert.Equal(t, tt.wantStatus, res.Code)
+
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, tt.wantLocale, res.Body.String())
+				assert.Equal(t, tt.wantLocale, res.Header().Get("Content-Language"))
+			}
+		})
+	}
+}
@@ -9,10 +9,19 @@ import (
 )
 
 // MetricsMiddleware creates HTTP metrics middleware using the clean slog-based logfx approach.
+// It counts requests and the ones in flight, and records their durations and response
+// body sizes. Sizes of streamed responses are not known, so they are not recorded.
 func MetricsMiddleware(httpMetrics *httpfx.Metrics) httpfx.Handler {
 	return func(ctx *httpfx.Context) httpfx.Result {
 		startTime := time.Now()
 
+		httpMetrics.RequestsInFlight.Inc(ctx.Request.Context(),
+			slog.String("http.method", ctx.Request.Method),
+		)
+		defer httpMetrics.RequestsInFlight.Dec(ctx.Request.Context(),
+			slog.String("http.method", ctx.Request.Method),
+		)
+
 		result := ctx.Next()
 
 		duration := time.Since(startTime)
@@ -30,6 +39,14 @@ func MetricsMiddleware(httpMetrics *httpfx.Metrics) httpfx.Handler {
 			slog.String("http.status_code", strconv.Itoa(result.StatusCode())),
 		)
 
+		if result.Stream() == nil {
+			httpMetrics.ResponseSize.Record(ctx.Request.Context(), float64(len(result.Body())),
+				slog.String("http.method", ctx.Request.Method),
+				slog.String("http.path", ctx.Request.URL.Path),
+				slog.String("http.status_code", strconv.Itoa(result.StatusCode())),
+			)
+		}
+
 		return result
 	}
 }
@@ -35,9 +35,26 @@ func setupTestMetrics() *httpfx.Metrics {
 		panic(err)
 	}
 
+	requestsInFlight, err := metricsBuilder.
+		UpDownCounter("http_requests_in_flight", "Number of HTTP requests being served").
+		Build()
+	if err != nil {
+		panic(err)
+	}
+
+	responseSize, err := metricsBuilder.
+		Histogram("http_response_size_bytes", "HTTP response body size in bytes").
+		WithUnit("By").
+		Build()
+	if err != nil {
+		panic(err)
+	}
+
 	return &httpfx.Metrics{
-		RequestsTotal:   requestsTotal,
-		RequestDuration: requestDuration,
+		RequestsTotal:    requestsTotal,
+		RequestDuration:  requestDuration,
+		RequestsInFlight: requestsInFlight,
+		ResponseSize:     responseSize,
 	}
 }
 
@@ -99,3 +116,36 @@ func TestMetricsMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricsMiddleware_Prometheus(t *testing.T) {
+	t.Parallel()
+
+	logger := logfx.NewLogger()
+	exporter := logger.EnablePrometheus()
+
+	metrics := httpfx.NewMetrics(logger.NewMetricsBuilder("httpfx"))
+	require.NoError(t, metrics.Init())
+
+	router := httpfx.NewRouter("/")
+	router.Use(middlewares.MetricsMiddleware(metrics))
+	router.Route("GET /hello", func(ctx *httpfx.Context) httpfx.Result {
+		return ctx.Results.PlainText([]byte("hello"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/hello", nil)
+	w := httptest.NewRecorder()
+
+	router.GetMux().ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	exposition, err := exporter.Gather(t.Context())
+	require.NoError(t, err)
+
+	labels := `http_method="GET",http_path="/hello",http_status_code="200",otel_scope_name="httpfx"`
+
+	assert.Contains(t, exposition, "http_requests_total{"+labels+"} 1\n")
+	assert.Contains(t, exposition, "http_request_duration_seconds_count{"+labels+"} 1\n")
+	assert.Contains(t, exposition, "http_response_size_bytes_sum{"+labels+"} 5\n")
+	assert.Contains(t, exposition, `http_requests_in_flight{http_method="GET",otel_scope_name="httpfx"} 0`+"\n")
+}
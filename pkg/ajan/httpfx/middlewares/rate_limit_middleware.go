@@ -23,6 +23,7 @@ type RateLimitOption func(*rateLimitConfig)
 // rateLimitConfig holds the internal configuration for rate limiting.
 type rateLimitConfig struct {
 	KeyFunc           func(*httpfx.Context) string // Function to extract key for rate limiting
+	Store             RateLimitStore               // Shared store; counters stay in memory when nil
 	RequestsPerMinute int                          // Number of requests allowed per minute
 	WindowSize        time.Duration                // Time window for rate limiting
 }
@@ -48,6 +49,15 @@ func WithRateLimiterWindowSize(duration time.Duration) RateLimitOption {
 	}
 }
 
+// WithRateLimiterStore keeps the limits in a shared store, such as a
+// RedisRateLimitStore, so they hold across every replica of the service. When the
+// store fails, requests are let through rather than rejected.
+func WithRateLimiterStore(store RateLimitStore) RateLimitOption {
+	return func(config *rateLimitConfig) {
+		config.Store = store
+	}
+}
+
 // WithRateLimiterIPKeyFunc sets the key function to extract IP addresses for rate limiting.
 func WithRateLimiterIPKeyFunc() RateLimitOption {
 	return func(config *rateLimitConfig) {
@@ -173,38 +183,46 @@ func RateLimitMiddleware(options ...RateLimitOption) httpfx.Handler {
 		option(cfg)
 	}
 
-	// Create a unique key for this configuration
-	// For production use, we want to share rate limiters with same config
-	// For testing, each middleware instance should have its own rate limiter
-	configKey := fmt.Sprintf("%d_%v_%p", cfg.RequestsPerMinute, cfg.WindowSize, cfg)
+	var check func(*httpfx.Context, string) (RateLimitDecision, bool)
 
-	// Initialize rate limiter for this configuration if not already done
-	globalMutex.Lock()
+	if cfg.Store == nil {
+		check = newLocalRateLimitCheck(cfg)
+	} else {
+		check = func(ctx *httpfx.Context, key string) (RateLimitDecision, bool) {
+			decision, err := cfg.Store.Allow(
+				ctx.Request.Context(),
+				key,
+				cfg.RequestsPerMinute,
+				cfg.WindowSize,
+			)
 
-	rateLimiter, exists := globalRateLimiters[configKey]
-	if !exists {
-		rateLimiter = newRateLimiter(cfg)
-		globalRateLimiters[configKey] = rateLimiter
+			return decision, err == nil
+		}
 	}
-	globalMutex.Unlock()
 
 	return func(ctx *httpfx.Context) httpfx.Result {
 		// Extract key for rate limiting
 		key := cfg.KeyFunc(ctx)
 
 		// Check if request is allowed
-		allowed, remaining, resetTime := rateLimiter.isAllowed(key)
+		decision, ok := check(ctx, key)
+		if !ok {
+			// The store is unavailable; fail open instead of rejecting all traffic.
+			return ctx.Next()
+		}
 
 		headers := ctx.ResponseWriter.Header()
 
 		// Set rate limit headers
 		headers.Set("X-Ratelimit-Limit", strconv.Itoa(cfg.RequestsPerMinute))
-		headers.Set("X-Ratelimit-Remaining", strconv.Itoa(remaining))
-		headers.Set("X-Ratelimit-Reset", strconv.FormatInt(resetTime.Unix(), 10))
+		headers.Set("X-Ratelimit-Remaining", strconv.Itoa(decision.Remaining))
+		headers.Set("X-Ratelimit-Reset", strconv.FormatInt(decision.ResetAt.Unix(), 10))
+
+		if !decision.Allowed {
+			retryAfter := int(decision.RetryAfter.Seconds())
 
-		if !allowed {
 			// Rate limit exceeded
-			headers.Set("Retry-After", strconv.Itoa(int(time.Until(resetTime).Seconds())))
+			headers.Set("Retry-After", strconv.Itoa(retryAfter))
 
 			errorResponse := map[string]any{
 				"error": "Rate limit exceeded",
@@ -213,7 +231,7 @@ func RateLimitMiddleware(options ...RateLimitOption) httpfx.Handler {
 					cfg.RequestsPerMinute,
 					cfg.WindowSize,
 				),
-				"retryAfter": int(time.Until(resetTime).Seconds()),
+				"retryAfter": retryAfter,
 			}
 
 			result := ctx.Results.JSON(errorResponse)
@@ -228,3 +246,34 @@ func RateLimitMiddleware(options ...RateLimitOption) httpfx.Handler {
 		return result
 	}
 }
+
+// newLocalRateLimitCheck returns a check against the in-process counters.
+func newLocalRateLimitCheck(
+	cfg *rateLimitConfig,
+) func(*httpfx.Context, string) (RateLimitDecision, bool) {
+	// Create a unique key for this configuration
+	// For production use, we want to share rate limiters with same config
+	// For testing, each middleware instance should have its own rate limiter
+	configKey := fmt.Sprintf("%d_%v_%p", cfg.RequestsPerMinute, cfg.WindowSize, cfg)
+
+	// Initialize rate limiter for this configuration if not already done
+	globalMutex.Lock()
+
+	rateLimiter, exists := globalRateLimiters[configKey]
+	if !exists {
+		rateLimiter = newRateLimiter(cfg)
+		globalRateLimiters[configKey] = rateLimiter
+	}
+	globalMutex.Unlock()
+
+	return func(_ *httpfx.Context, key string) (RateLimitDecision, bool) {
+		allowed, remaining, resetTime := rateLimiter.isAllowed(key)
+
+		return RateLimitDecision{
+			ResetAt:    resetTime,
+			RetryAfter: time.Until(resetTime),
+			Remaining:  remaining,
+			Allowed:    allowed,
+		}, true
+	}
+}
@@ -0,0 +1,126 @@
+package middlewares
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const DefaultRateLimitKeyPrefix = "ratelimit:"
+
+var ErrUnexpectedRateLimitReply = errors.New("unexpected rate limit script reply")
+
+// RateLimitDecision is the outcome of a rate limit check.
+type RateLimitDecision struct {
+	// ResetAt is when the key has its full limit available again.
+	ResetAt time.Time
+	// RetryAfter is how long a rejected request should wait before trying again.
+	RetryAfter time.Duration
+	Remaining  int
+	Allowed    bool
+}
+
+// RateLimitStore keeps rate limit state outside the process, so every replica of a
+// service enforces the same limits.
+type RateLimitStore interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (RateLimitDecision, error)
+}
+
+// LuaEvaluator runs Lua scripts. The Redis connections of connfx satisfy it.
+type LuaEvaluator interface {
+	Eval(ctx context.Context, script string, keys []string, args ...any) (any, error)
+}
+
+// RedisRateLimitStore implements the generic cell rate algorithm (GCRA) in a Lua script,
+// so each check is a single atomic round trip. Requests are spread evenly over the
+// window, with bursts of up to the whole limit, and each key needs a single value that
+// expires once the key is idle. Time is read from the Redis server, so replicas with
+// skewed clocks still agree.
+type RedisRateLimitStore struct {
+	evaluator LuaEvaluator
+	prefix    string
+}
+
+// gcraScript stores the theoretical arrival time (TAT) of the next request, in
+// microseconds. ARGV[1] is the emission interval (window / limit) and ARGV[2] the
+// window, both in microseconds. It returns {allowed, remaining, reset_after,
+// retry_after}, the durations in microseconds.
+const gcraScript = `
+local emission = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local tat = tonumber(redis.call('GET', KEYS[1]))
+if tat == nil or tat < now then
+  tat = now
+end
+
+local new_tat = tat + emission
+local allow_at = new_tat - window
+
+if allow_at > now then
+  return {0, 0, tat - now, allow_at - now}
+end
+
+-- Lua would write the number with 14 significant digits; keep every microsecond.
+redis.call('SET', KEYS[1], string.format('%d', new_tat), 'PX', math.ceil((new_tat - now) / 1000))
+
+return {1, math.floor((now - allow_at) / emission), new_tat - now, 0}
+`
+
+// NewRedisRateLimitStore creates a store that keeps its keys under keyPrefix, or
+// DefaultRateLimitKeyPrefix when it is empty.
+func NewRedisRateLimitStore(evaluator LuaEvaluator, keyPrefix string) *RedisRateLimitStore {
+	return &RedisRateLimitStore{
+		evaluator: evaluator,
+		prefix:    cmp.Or(keyPrefix, DefaultRateLimitKeyPrefix),
+	}
+}
+
+func (s *RedisRateLimitStore) Allow(
+	ctx context.Context,
+	key string,
+	limit int,
+	window time.Duration,
+) (RateLimitDecision, error) {
+	emission := window.Microseconds() / int64(max(limit, 1))
+
+	reply, err := s.evaluator.Eval(
+		ctx,
+		gcraScript,
+		[]string{s.prefix + key},
+		emission,
+		window.Microseconds(),
+	)
+	if err != nil {
+		return RateLimitDecision{}, err //nolint:exhaustruct,wrapcheck
+	}
+
+	values, ok := reply.([]any)
+	if !ok || len(values) != 4 { //nolint:mnd
+		return RateLimitDecision{}, fmt.Errorf("%w (reply=%v)", ErrUnexpectedRateLimitReply, reply) //nolint:exhaustruct
+	}
+
+	numbers := make([]int64, len(values))
+
+	for i, value := range values {
+		number, ok := value.(int64)
+		if !ok {
+			return RateLimitDecision{}, fmt.Errorf("%w (reply=%v)", ErrUnexpectedRateLimitReply, reply) //nolint:exhaustruct
+		}
+
+		numbers[i] = number
+	}
+
+	now := time.Now()
+
+	return RateLimitDecision{
+		ResetAt:    now.Add(time.Duration(numbers[2]) * time.Microsecond),
+		RetryAfter: time.Duration(numbers[3]) * time.Microsecond,
+		Remaining:  int(numbers[1]),
+		Allowed:    numbers[0] == 1,
+	}, nil
+}
@@ -0,0 +1,99 @@
+package middlewares_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx/middlewares"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errEvalUnavailable = errors.New("redis unavailable")
+
+type fakeEvaluator struct {
+	err   error
+	reply any
+	keys  []string
+	args  []any
+}
+
+func (e *fakeEvaluator) Eval(_ context.Context, _ string, keys []string, args ...any) (any, error) {
+	e.keys = keys
+	e.args = args
+
+	return e.reply, e.err
+}
+
+func TestRedisRateLimitStore(t *testing.T) {
+	t.Parallel()
+
+	evaluator := &fakeEvaluator{
+		err:   nil,
+		reply: []any{int64(0), int64(0), int64(60_000_000), int64(1_500_000)},
+		keys:  nil,
+		args:  nil,
+	}
+
+	store := middlewares.NewRedisRateLimitStore(evaluator, "")
+
+	decision, err := store.Allow(t.Context(), "10.0.0.1", 60, time.Minute)
+	require.NoError(t, err)
+
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, 1500*time.Millisecond, decision.RetryAfter)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), decision.ResetAt, time.Second)
+	assert.Equal(t, []string{"ratelimit:10.0.0.1"}, evaluator.keys)
+	assert.Equal(t, []any{int64(1_000_000), int64(60_000_000)}, evaluator.args)
+
+	evaluator.reply = "OK"
+
+	_, err = store.Allow(t.Context(), "10.0.0.1", 60, time.Minute)
+	require.ErrorIs(t, err, middlewares.ErrUnexpectedRateLimitReply)
+}
+
+func TestRateLimitMiddlewareWithStore(t *testing.T) {
+	t.Parallel()
+
+	evaluator := &fakeEvaluator{
+		err:   nil,
+		reply: []any{int64(0), int64(0), int64(30_000_000), int64(2_000_000)},
+		keys:  nil,
+		args:  nil,
+	}
+
+	middleware := middlewares.RateLimitMiddleware(
+		middlewares.WithRateLimiterStore(middlewares.NewRedisRateLimitStore(evaluator, "api:")),
+		middlewares.WithRateLimiterKeyFunc(func(ctx *httpfx.Context) string {
+			return "shared-key"
+		}),
+	)
+
+	serve := func() (httpfx.Result, *httptest.ResponseRecorder) {
+		res := httptest.NewRecorder()
+		ctx := &httpfx.Context{ //nolint:exhaustruct
+			Request:        httptest.NewRequest(http.MethodGet, "/test", nil),
+			ResponseWriter: res,
+			Results:        httpfx.Results{},
+		}
+
+		return middleware(ctx), res
+	}
+
+	result, res := serve()
+	assert.Equal(t, http.StatusTooManyRequests, result.StatusCode())
+	assert.Equal(t, "2", res.Header().Get("Retry-After"))
+	assert.Equal(t, []string{"api:shared-key"}, evaluator.keys)
+
+	// Requests are let through while the store is unavailable.
+	evaluator.err = errEvalUnavailable
+
+	result, res = serve()
+	assert.Equal(t, http.StatusNoContent, result.StatusCode())
+	assert.Empty(t, res.Header().Get("X-Ratelimit-Remaining"))
+}
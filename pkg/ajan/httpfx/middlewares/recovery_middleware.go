@@ -0,0 +1,125 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+)
+
+// PanicReport describes a panic recovered from a handler.
+type PanicReport struct {
+	Value  any
+	Method string
+	Path   string
+	Stack  []byte
+}
+
+// PanicReporter sends recovered panics to an error-tracking service.
+type PanicReporter interface {
+	ReportPanic(ctx context.Context, report PanicReport)
+}
+
+// RecoveryOption defines a functional option for configuring panic recovery.
+type RecoveryOption func(*recoveryConfig)
+
+// recoveryConfig holds the internal configuration for panic recovery.
+type recoveryConfig struct {
+	Metrics  *httpfx.Metrics // Counts panics when set
+	Reporter PanicReporter   // Receives panics when set
+}
+
+// WithRecoveryMetrics counts recovered panics in the PanicsTotal metric.
+func WithRecoveryMetrics(httpMetrics *httpfx.Metrics) RecoveryOption {
+	return func(config *recoveryConfig) {
+		config.Metrics = httpMetrics
+	}
+}
+
+// WithPanicReporter sends recovered panics to an error-tracking service as well.
+func WithPanicReporter(reporter PanicReporter) RecoveryOption {
+	return func(config *recoveryConfig) {
+		config.Reporter = reporter
+	}
+}
+
+// RecoveryMiddleware converts panics in later handlers into 500 responses, so a
+// single faulty request does not take the connection down with it. The panic is
+// logged with its stack on the request context, which correlates it with the trace
+// when TracingMiddleware runs before. http.ErrAbortHandler panics are passed on, as
+// they deliberately abort the response. Event streams run after the middlewares
+// return, so panics in them are not recovered here.
+func RecoveryMiddleware(logger *logfx.Logger, options ...RecoveryOption) httpfx.Handler {
+	cfg := &recoveryConfig{
+		Metrics:  nil,
+		Reporter: nil,
+	}
+
+	for _, option := range options {
+		option(cfg)
+	}
+
+	return func(ctx *httpfx.Context) (result httpfx.Result) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			if recovered == http.ErrAbortHandler { //nolint:errorlint,err113
+				panic(recovered)
+			}
+
+			result = recoverPanic(ctx, logger, cfg, recovered)
+		}()
+
+		return ctx.Next()
+	}
+}
+
+func recoverPanic(
+	ctx *httpfx.Context,
+	logger *logfx.Logger,
+	cfg *recoveryConfig,
+	recovered any,
+) httpfx.Result {
+	// Handlers after this one may have updated the request, e.g. with a span.
+	requestCtx := ctx.Request.Context()
+
+	report := PanicReport{
+		Value:  recovered,
+		Method: ctx.Request.Method,
+		Path:   ctx.Request.URL.Path,
+		Stack:  debug.Stack(),
+	}
+
+	logger.ErrorContext(
+		requestCtx,
+		"HTTP handler panicked",
+		slog.String("scope_name", "http"),
+		slog.String("http.method", report.Method),
+		slog.String("http.path", report.Path),
+		slog.String("panic", fmt.Sprint(recovered)),
+		slog.String("stack", string(report.Stack)),
+	)
+
+	if cfg.Metrics != nil && cfg.Metrics.PanicsTotal != nil {
+		cfg.Metrics.PanicsTotal.Inc(requestCtx,
+			slog.String("http.method", report.Method),
+			slog.String("http.path", report.Path),
+		)
+	}
+
+	if cfg.Reporter != nil {
+		cfg.Reporter.ReportPanic(requestCtx, report)
+	}
+
+	return ctx.Results.Error(
+		http.StatusInternalServerError,
+		httpfx.WithPlainText("Internal Server Error"),
+	)
+}
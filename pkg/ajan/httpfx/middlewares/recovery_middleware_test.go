@@ -0,0 +1,85 @@
+package middlewares_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx/middlewares"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePanicReporter struct {
+	reports []middlewares.PanicReport
+	mu      sync.Mutex
+}
+
+func (r *fakePanicReporter) ReportPanic(_ context.Context, report middlewares.PanicReport) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reports = append(r.reports, report)
+}
+
+func TestRecoveryMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var logBuffer bytes.Buffer
+
+	logger := logfx.NewLogger(logfx.WithWriter(&logBuffer))
+	reporter := &fakePanicReporter{} //nolint:exhaustruct
+
+	router := httpfx.NewRouter("/")
+	router.Use(middlewares.RecoveryMiddleware(
+		logger,
+		middlewares.WithRecoveryMetrics(setupTestMetrics()),
+		middlewares.WithPanicReporter(reporter),
+	))
+	router.Route("GET /panic", func(_ *httpfx.Context) httpfx.Result {
+		panic("story not loaded")
+	})
+	router.Route("GET /ok", func(ctx *httpfx.Context) httpfx.Result {
+		return ctx.Results.PlainText([]byte("ok"))
+	})
+
+	res := httptest.NewRecorder()
+	router.GetMux().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+	assert.Contains(t, logBuffer.String(), "HTTP handler panicked")
+	assert.Contains(t, logBuffer.String(), "story not loaded")
+
+	require.Len(t, reporter.reports, 1)
+	assert.Equal(t, "story not loaded", reporter.reports[0].Value)
+	assert.Equal(t, "/panic", reporter.reports[0].Path)
+	assert.Contains(t, string(reporter.reports[0].Stack), "recovery_middleware_test.go")
+
+	res = httptest.NewRecorder()
+	router.GetMux().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/ok", nil))
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Len(t, reporter.reports, 1)
+}
+
+func TestRecoveryMiddlewareAbortHandler(t *testing.T) {
+	t.Parallel()
+
+	router := httpfx.NewRouter("/")
+	router.Use(middlewares.RecoveryMiddleware(logfx.NewLogger(logfx.WithWriter(&bytes.Buffer{}))))
+	router.Route("GET /abort", func(_ *httpfx.Context) httpfx.Result {
+		panic(http.ErrAbortHandler)
+	})
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		router.GetMux().ServeHTTP(
+			httptest.NewRecorder(),
+			httptest.NewRequest(http.MethodGet, "/abort", nil),
+		)
+	})
+}
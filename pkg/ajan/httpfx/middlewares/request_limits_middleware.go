@@ -0,0 +1,236 @@
+package middlewares
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+)
+
+// Defaults for the request limits. Timeouts default to the ones of the server.
+const (
+	DefaultMaxBodySize    int64 = 1 << 20 // 1 MiB
+	DefaultMaxHeaderCount       = 100
+	DefaultMaxHeaderSize        = 32 << 10 // 32 KiB
+)
+
+// RequestLimitsOption defines a functional option for configuring request limits.
+type RequestLimitsOption func(*requestLimitsConfig)
+
+// requestLimitsConfig holds the internal configuration for request limits.
+type requestLimitsConfig struct {
+	MaxBodySize    int64         // Maximum request body size in bytes; 0 disables the limit
+	MaxHeaderCount int           // Maximum number of header values; 0 disables the limit
+	MaxHeaderSize  int           // Maximum total size of header names and values; 0 disables the limit
+	ReadTimeout    time.Duration // Deadline for reading the request body; 0 keeps the server's
+	WriteTimeout   time.Duration // Deadline for writing the response; 0 keeps the server's
+}
+
+// WithMaxBodySize sets the maximum request body size in bytes.
+func WithMaxBodySize(size int64) RequestLimitsOption {
+	return func(config *requestLimitsConfig) {
+		config.MaxBodySize = size
+	}
+}
+
+// WithMaxHeaderCount sets the maximum number of request header values.
+func WithMaxHeaderCount(count int) RequestLimitsOption {
+	return func(config *requestLimitsConfig) {
+		config.MaxHeaderCount = count
+	}
+}
+
+// WithMaxHeaderSize sets the maximum total size of request header names and values
+// in bytes.
+func WithMaxHeaderSize(size int) RequestLimitsOption {
+	return func(config *requestLimitsConfig) {
+		config.MaxHeaderSize = size
+	}
+}
+
+// WithReadTimeout overrides the server's read timeout for the routes the middleware
+// is used on, e.g. to cut slow uploads short or to allow large ones.
+func WithReadTimeout(timeout time.Duration) RequestLimitsOption {
+	return func(config *requestLimitsConfig) {
+		config.ReadTimeout = timeout
+	}
+}
+
+// WithWriteTimeout overrides the server's write timeout for the routes the middleware
+// is used on, e.g. to allow long-running exports or streams.
+func WithWriteTimeout(timeout time.Duration) RequestLimitsOption {
+	return func(config *requestLimitsConfig) {
+		config.WriteTimeout = timeout
+	}
+}
+
+// RequestLimits holds request limits that can be changed at runtime, e.g. when the
+// configuration is reloaded. Its middleware applies the limits that are current when
+// a request arrives.
+type RequestLimits struct {
+	config atomic.Pointer[requestLimitsConfig]
+}
+
+// NewRequestLimits creates request limits with the defaults and the given options.
+func NewRequestLimits(options ...RequestLimitsOption) *RequestLimits {
+	limits := &RequestLimits{} //nolint:exhaustruct
+
+	limits.Update(options...)
+
+	return limits
+}
+
+// Update replaces the limits with the defaults and the given options.
+func (l *RequestLimits) Update(options ...RequestLimitsOption) {
+	cfg := &requestLimitsConfig{
+		MaxBodySize:    DefaultMaxBodySize,
+		MaxHeaderCount: DefaultMaxHeaderCount,
+		MaxHeaderSize:  DefaultMaxHeaderSize,
+		ReadTimeout:    0,
+		WriteTimeout:   0,
+	}
+
+	for _, option := range options {
+		option(cfg)
+	}
+
+	l.config.Store(cfg)
+}
+
+// RequestLimitsMiddleware protects handlers from oversized requests and slow clients.
+// Requests with too many or too large headers are rejected with 431, and requests
+// with bodies over the limit with 413, both with a JSON error. Bodies without a
+// Content-Length are cut off once they exceed the limit while being read. Use it on
+// a route group with different options to give those routes their own limits.
+func RequestLimitsMiddleware(options ...RequestLimitsOption) httpfx.Handler {
+	return NewRequestLimits(options...).Middleware()
+}
+
+// Middleware returns the middleware of RequestLimitsMiddleware, with the current limits.
+func (l *RequestLimits) Middleware() httpfx.Handler {
+	return func(ctx *httpfx.Context) httpfx.Result {
+		cfg := l.config.Load()
+
+		count, size := measureHeaders(ctx.Request.Header)
+
+		if cfg.MaxHeaderCount > 0 && count > cfg.MaxHeaderCount {
+			return requestLimitExceeded(
+				ctx,
+				http.StatusRequestHeaderFieldsTooLarge,
+				"Request header fields too large",
+				fmt.Sprintf("Too many header fields. Limit: %d", cfg.MaxHeaderCount),
+			)
+		}
+
+		if cfg.MaxHeaderSize > 0 && size > cfg.MaxHeaderSize {
+			return requestLimitExceeded(
+				ctx,
+				http.StatusRequestHeaderFieldsTooLarge,
+				"Request header fields too large",
+				fmt.Sprintf("Header fields are too large. Limit: %d bytes", cfg.MaxHeaderSize),
+			)
+		}
+
+		if cfg.MaxBodySize > 0 && ctx.Request.ContentLength > cfg.MaxBodySize {
+			return bodyTooLarge(ctx, cfg.MaxBodySize)
+		}
+
+		controller := http.NewResponseController(ctx.ResponseWriter)
+
+		// Writers that do not support deadlines keep the server's timeouts.
+		if cfg.ReadTimeout > 0 {
+			_ = controller.SetReadDeadline(time.Now().Add(cfg.ReadTimeout))
+		}
+
+		if cfg.WriteTimeout > 0 {
+			_ = controller.SetWriteDeadline(time.Now().Add(cfg.WriteTimeout))
+		}
+
+		if cfg.MaxBodySize <= 0 || ctx.Request.Body == nil || ctx.Request.Body == http.NoBody {
+			return ctx.Next()
+		}
+
+		body := &limitedBody{
+			ReadCloser: http.MaxBytesReader(ctx.ResponseWriter, ctx.Request.Body, cfg.MaxBodySize),
+			exceeded:   false,
+		}
+		ctx.Request.Body = body
+
+		result := ctx.Next()
+
+		// Handlers usually report a failed read as a bad request; report the real cause.
+		if body.exceeded {
+			return bodyTooLarge(ctx, cfg.MaxBodySize)
+		}
+
+		return result
+	}
+}
+
+// limitedBody records whether the limit of the http.MaxBytesReader it wraps was hit.
+type limitedBody struct {
+	io.ReadCloser
+
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		b.exceeded = true
+	}
+
+	return n, err //nolint:wrapcheck
+}
+
+// measureHeaders returns the number of header values and the total size of the
+// header names and values.
+func measureHeaders(header http.Header) (int, int) {
+	count := 0
+	size := 0
+
+	for name, values := range header {
+		count += len(values)
+
+		for _, value := range values {
+			size += len(name) + len(value)
+		}
+	}
+
+	return count, size
+}
+
+func bodyTooLarge(ctx *httpfx.Context, limit int64) httpfx.Result {
+	return requestLimitExceeded(
+		ctx,
+		http.StatusRequestEntityTooLarge,
+		"Request body too large",
+		fmt.Sprintf("Request body is too large. Limit: %d bytes", limit),
+	)
+}
+
+func requestLimitExceeded(
+	ctx *httpfx.Context,
+	statusCode int,
+	title string,
+	message string,
+) httpfx.Result {
+	// The request is not read any further, so do not keep the connection around.
+	ctx.ResponseWriter.Header().Set("Connection", "close")
+
+	errorResponse := map[string]any{
+		"error":   title,
+		"message": message,
+	}
+
+	result := ctx.Results.JSON(errorResponse)
+	result.InnerStatusCode = statusCode
+
+	return result
+}
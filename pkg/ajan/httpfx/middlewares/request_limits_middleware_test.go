@@ -0,0 +1,116 @@
+package middlewares_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx/middlewares"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLimitsMiddleware(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	router := httpfx.NewRouter("/")
+	router.Use(middlewares.RequestLimitsMiddleware(
+		middlewares.WithMaxBodySize(16),
+		middlewares.WithMaxHeaderCount(4),
+		middlewares.WithMaxHeaderSize(64),
+	))
+	router.Route("POST /echo", func(ctx *httpfx.Context) httpfx.Result {
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			return ctx.Results.BadRequest()
+		}
+
+		return ctx.Results.PlainText(body)
+	})
+
+	tests := []struct {
+		name       string
+		body       string
+		headers    map[string]string
+		chunked    bool
+		wantStatus int
+	}{
+		{"within limits", "hello", nil, false, http.StatusOK},
+		{"declared body too large", strings.Repeat("a", 17), nil, false, http.StatusRequestEntityTooLarge},
+		{"streamed body too large", strings.Repeat("a", 17), nil, true, http.StatusRequestEntityTooLarge},
+		{
+			"too many headers",
+			"",
+			map[string]string{"A": "1", "B": "2", "C": "3", "D": "4", "E": "5"},
+			false,
+			http.StatusRequestHeaderFieldsTooLarge,
+		},
+		{
+			"headers too large",
+			"",
+			map[string]string{"Cookie": strings.Repeat("c", 64)},
+			false,
+			http.StatusRequestHeaderFieldsTooLarge,
+		},
+	}
+
+	for _, tt := range tests { //nolint:varnamelen
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(tt.body))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			res := httptest.NewRecorder()
+			router.GetMux().ServeHTTP(res, req)
+
+			assert.Equal(t, tt.wantStatus, res.Code)
+
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, tt.body, res.Body.String())
+
+				return
+			}
+
+			var payload map[string]any
+			require.NoError(t, json.Unmarshal(res.Body.Bytes(), &payload))
+			assert.NotEmpty(t, payload["error"])
+			assert.NotEmpty(t, payload["message"])
+		})
+	}
+}
+
+func TestRequestLimits_Update(t *testing.T) {
+	t.Parallel()
+
+	limits := middlewares.NewRequestLimits(middlewares.WithMaxBodySize(4))
+
+	router := httpfx.NewRouter("/")
+	router.Use(limits.Middleware())
+	router.Route("POST /echo", func(ctx *httpfx.Context) httpfx.Result {
+		return ctx.Results.Ok()
+	})
+
+	send := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("hello"))
+		res := httptest.NewRecorder()
+		router.GetMux().ServeHTTP(res, req)
+
+		return res.Code
+	}
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send())
+
+	limits.Update(middlewares.WithMaxBodySize(8))
+	assert.Equal(t, http.StatusNoContent, send())
+}
@@ -0,0 +1,220 @@
+package middlewares
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+)
+
+const (
+	DefaultResponseCacheKeyPrefix = "httpcache:"
+	// DefaultResponseCacheTagTTL bounds how long invalidations are remembered. Cache
+	// responses for shorter than this.
+	DefaultResponseCacheTagTTL = 7 * 24 * time.Hour
+)
+
+var ErrResponseCacheInvalidationFailed = errors.New("failed to invalidate cached responses")
+
+// ResponseCacheStore stores cached responses. Every connfx CacheRepository satisfies
+// it. Get returns a nil value without an error when the key does not exist.
+type ResponseCacheStore interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	SetWithExpiration(ctx context.Context, key string, value []byte, expiration time.Duration) error
+}
+
+// ResponseCache caches responses of read endpoints in a ResponseCacheStore. Cached
+// responses are tagged, e.g. with the profile they show, and invalidating a tag
+// drops every response carrying it. Tags are versioned rather than deleted: each
+// response key includes the current versions of its tags, so invalidation is a
+// single write, and stale entries expire on their own.
+//
+// A nil *ResponseCache caches nothing, so callers need no checks when caching is
+// not configured.
+type ResponseCache struct {
+	store  ResponseCacheStore
+	prefix string
+}
+
+// responseCacheEntry is a stored response.
+type responseCacheEntry struct {
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body"`
+	StatusCode  int    `json:"status_code"`
+}
+
+// ResponseCacheOption defines a functional option for configuring a cached route.
+type ResponseCacheOption func(*responseCacheConfig)
+
+// responseCacheConfig holds the internal configuration of a cached route.
+type responseCacheConfig struct {
+	TagsFunc func(*httpfx.Context) []string // Tags to invalidate the response with
+}
+
+// WithResponseCacheTags tags the responses of the route, so they can be dropped
+// with ResponseCache.Invalidate.
+func WithResponseCacheTags(tagsFunc func(*httpfx.Context) []string) ResponseCacheOption {
+	return func(config *responseCacheConfig) {
+		config.TagsFunc = tagsFunc
+	}
+}
+
+// NewResponseCache creates a cache that keeps its keys under keyPrefix, or
+// DefaultResponseCacheKeyPrefix when it is empty.
+func NewResponseCache(store ResponseCacheStore, keyPrefix string) *ResponseCache {
+	return &ResponseCache{
+		store:  store,
+		prefix: cmp.Or(keyPrefix, DefaultResponseCacheKeyPrefix),
+	}
+}
+
+// Middleware caches successful GET responses of a route for ttl. The key consists of
+// the method, path, locale, query string and Accept header. Requests of
+// authenticated clients bypass the cache, as their responses may differ, and
+// requests with "Cache-Control: no-cache" refresh it. Store failures are ignored, so
+// requests then reach the handler. Responses carry an X-Cache header with HIT or MISS.
+func (c *ResponseCache) Middleware(ttl time.Duration, options ...ResponseCacheOption) httpfx.Handler {
+	cfg := &responseCacheConfig{
+		TagsFunc: nil,
+	}
+
+	for _, option := range options {
+		option(cfg)
+	}
+
+	return func(ctx *httpfx.Context) httpfx.Result {
+		if c == nil || ctx.Request.Method != http.MethodGet {
+			return ctx.Next()
+		}
+
+		if _, authenticated := httpfx.AuthIdentityFromContext(ctx.Request.Context()); authenticated {
+			return ctx.Next()
+		}
+
+		requestCtx := ctx.Request.Context()
+
+		key, ok := c.responseKey(requestCtx, ctx, cfg)
+		if !ok {
+			return ctx.Next()
+		}
+
+		if !strings.Contains(ctx.Request.Header.Get("Cache-Control"), "no-cache") {
+			if entry := c.load(requestCtx, key); entry != nil {
+				return cachedResult(ctx, entry)
+			}
+		}
+
+		result := ctx.Next()
+
+		ctx.ResponseWriter.Header().Set("X-Cache", "MISS")
+
+		if result.StatusCode() != http.StatusOK || result.Stream() != nil {
+			return result
+		}
+
+		encoded, err := json.Marshal(responseCacheEntry{
+			ContentType: cmp.Or(result.ContentType(), ctx.ResponseWriter.Header().Get("Content-Type")),
+			Body:        result.Body(),
+			StatusCode:  result.StatusCode(),
+		})
+		if err == nil {
+			_ = c.store.SetWithExpiration(requestCtx, key, encoded, ttl)
+		}
+
+		return result
+	}
+}
+
+// Invalidate drops every cached response tagged with one of tags.
+func (c *ResponseCache) Invalidate(ctx context.Context, tags ...string) error {
+	if c == nil {
+		return nil
+	}
+
+	version := strconv.FormatInt(time.Now().UnixNano(), 36)
+
+	for _, tag := range tags {
+		err := c.store.SetWithExpiration(ctx, c.tagKey(tag), []byte(version), DefaultResponseCacheTagTTL)
+		if err != nil {
+			return fmt.Errorf("%w (tag=%q): %w", ErrResponseCacheInvalidationFailed, tag, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *ResponseCache) responseKey(
+	requestCtx context.Context,
+	ctx *httpfx.Context,
+	cfg *responseCacheConfig,
+) (string, bool) {
+	var builder strings.Builder
+
+	builder.WriteString(ctx.Request.Method + " " + ctx.Request.URL.Path)
+	builder.WriteString("\nlocale=" + cmp.Or(
+		httpfx.LocaleFromContext(requestCtx),
+		ctx.Request.PathValue("locale"),
+	))
+	// Encoding sorts the parameters, so their order does not split the cache.
+	builder.WriteString("\nquery=" + ctx.Request.URL.Query().Encode())
+	// Negotiated results differ by the accepted media types.
+	builder.WriteString("\naccept=" + ctx.Request.Header.Get("Accept"))
+
+	if cfg.TagsFunc != nil {
+		for _, tag := range cfg.TagsFunc(ctx) {
+			version, err := c.store.Get(requestCtx, c.tagKey(tag))
+			if err != nil {
+				// Without the version, an invalidated response could be served.
+				return "", false
+			}
+
+			builder.WriteString("\ntag=" + tag + "@" + string(version))
+		}
+	}
+
+	sum := sha256.Sum256([]byte(builder.String()))
+
+	return c.prefix + "response:" + hex.EncodeToString(sum[:]), true
+}
+
+func (c *ResponseCache) tagKey(tag string) string {
+	return c.prefix + "tag:" + tag
+}
+
+func (c *ResponseCache) load(ctx context.Context, key string) *responseCacheEntry {
+	raw, err := c.store.Get(ctx, key)
+	if err != nil || raw == nil {
+		return nil
+	}
+
+	var entry responseCacheEntry
+
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil
+	}
+
+	return &entry
+}
+
+func cachedResult(ctx *httpfx.Context, entry *responseCacheEntry) httpfx.Result {
+	headers := ctx.ResponseWriter.Header()
+	headers.Set("X-Cache", "HIT")
+
+	if entry.ContentType != "" {
+		headers.Set("Content-Type", entry.ContentType)
+	}
+
+	result := ctx.Results.Bytes(entry.Body)
+	result.InnerStatusCode = entry.StatusCode
+
+	return result
+}
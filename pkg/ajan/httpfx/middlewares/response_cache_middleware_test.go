@@ -0,0 +1,123 @@
+package middlewares_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx/middlewares"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryResponseCacheStore struct {
+	values map[string][]byte
+	mu     sync.Mutex
+}
+
+func (s *memoryResponseCacheStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.values[key], nil
+}
+
+func (s *memoryResponseCacheStore) SetWithExpiration(
+	_ context.Context,
+	key string,
+	value []byte,
+	_ time.Duration,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = value
+
+	return nil
+}
+
+func TestResponseCache(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	store := &memoryResponseCacheStore{values: map[string][]byte{}} //nolint:exhaustruct
+	cache := middlewares.NewResponseCache(store, "")
+
+	var calls atomic.Int32
+
+	router := httpfx.NewRouter("/")
+	router.Route(
+		"GET /{locale}/profiles/{slug}",
+		cache.Middleware(time.Minute, middlewares.WithResponseCacheTags(
+			func(ctx *httpfx.Context) []string {
+				return []string{"profile:" + ctx.Request.PathValue("slug")}
+			},
+		)),
+		func(ctx *httpfx.Context) httpfx.Result {
+			calls.Add(1)
+
+			ctx.ResponseWriter.Header().Set("Content-Type", "application/json")
+
+			return ctx.Results.JSON(map[string]string{"slug": ctx.Request.PathValue("slug")})
+		},
+	)
+
+	get := func(path string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+
+		res := httptest.NewRecorder()
+		router.GetMux().ServeHTTP(res, req)
+
+		return res
+	}
+
+	res := get("/en/profiles/eser?b=2&a=1", nil)
+	assert.Equal(t, "MISS", res.Header().Get("X-Cache"))
+
+	res = get("/en/profiles/eser?a=1&b=2", nil)
+	assert.Equal(t, "HIT", res.Header().Get("X-Cache"))
+	assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"slug":"eser"}`, res.Body.String())
+	assert.Equal(t, int32(1), calls.Load())
+
+	res = get("/tr/profiles/eser?a=1&b=2", nil)
+	assert.Equal(t, "MISS", res.Header().Get("X-Cache"), "locales are cached separately")
+
+	res = get("/en/profiles/eser?a=1&b=2", http.Header{"Cache-Control": {"no-cache"}})
+	assert.Equal(t, "MISS", res.Header().Get("X-Cache"))
+	assert.Equal(t, int32(3), calls.Load())
+
+	require.NoError(t, cache.Invalidate(t.Context(), "profile:eser"))
+
+	res = get("/en/profiles/eser?a=1&b=2", nil)
+	assert.Equal(t, "MISS", res.Header().Get("X-Cache"), "invalidated responses are dropped")
+
+	res = get("/en/profiles/eser?a=1&b=2", nil)
+	assert.Equal(t, "HIT", res.Header().Get("X-Cache"))
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestResponseCacheDisabled(t *testing.T) {
+	t.Parallel()
+
+	var cache *middlewares.ResponseCache
+
+	router := httpfx.NewRouter("/")
+	router.Route("GET /stories", cache.Middleware(time.Minute), func(ctx *httpfx.Context) httpfx.Result {
+		return ctx.Results.PlainText([]byte("stories"))
+	})
+
+	res := httptest.NewRecorder()
+	router.GetMux().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/stories", nil))
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Empty(t, res.Header().Get("X-Cache"))
+	assert.NoError(t, cache.Invalidate(t.Context(), "stories"))
+}
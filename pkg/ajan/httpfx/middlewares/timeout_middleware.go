@@ -0,0 +1,79 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+)
+
+// timeoutWriteGrace is how long past the request timeout the response may still be
+// written, so the 504 reaches the client.
+const timeoutWriteGrace = time.Second
+
+// TimeoutMiddleware bounds how long the later handlers may take. The request context
+// gets a deadline of timeout, or of the route's own timeout set with
+// Route.HasTimeout, and when it is exceeded the response becomes 504 Gateway Timeout,
+// whatever the handler returned. Handlers stop early only if they respect the
+// context, as database and HTTP clients do. The write deadline of the response is
+// moved past the timeout, so routes with longer timeouts are not cut off by the
+// server's write timeout. A zero timeout disables the deadline. Event streams are
+// not bounded; they end when the client disconnects.
+func TimeoutMiddleware(timeout time.Duration) httpfx.Handler {
+	return func(ctx *httpfx.Context) httpfx.Result {
+		routeTimeout := timeout
+		if route := ctx.Route(); route != nil && route.Timeout > 0 {
+			routeTimeout = route.Timeout
+		}
+
+		if routeTimeout <= 0 {
+			return ctx.Next()
+		}
+
+		parentCtx := ctx.Request.Context()
+
+		timeoutCtx, cancel := context.WithTimeout(parentCtx, routeTimeout)
+		defer cancel()
+
+		// Writers that do not support deadlines keep the server's write timeout.
+		_ = http.NewResponseController(ctx.ResponseWriter).
+			SetWriteDeadline(time.Now().Add(routeTimeout + timeoutWriteGrace))
+
+		ctx.UpdateContext(timeoutCtx)
+
+		result := ctx.Next()
+
+		if stream := result.Stream(); stream != nil {
+			result.InnerStream = detachStream(parentCtx, stream)
+
+			return result
+		}
+
+		// A disconnected client sees no response; only report expired deadlines.
+		if errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) && parentCtx.Err() == nil {
+			return ctx.Results.Error(
+				http.StatusGatewayTimeout,
+				httpfx.WithPlainText("Request timed out"),
+			)
+		}
+
+		return result
+	}
+}
+
+// detachStream runs stream without the deadline, which is canceled by the time the
+// stream runs, keeping the values of the request context and its cancellation when
+// the client disconnects.
+func detachStream(parentCtx context.Context, stream httpfx.StreamFunc) httpfx.StreamFunc {
+	return func(responseWriter http.ResponseWriter, req *http.Request) {
+		streamCtx, cancel := context.WithCancel(context.WithoutCancel(req.Context()))
+		defer cancel()
+
+		stop := context.AfterFunc(parentCtx, cancel)
+		defer stop()
+
+		stream(responseWriter, req.WithContext(streamCtx))
+	}
+}
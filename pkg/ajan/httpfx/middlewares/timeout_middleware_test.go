@@ -0,0 +1,108 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx/middlewares"
+	"github.com/stretchr/testify/assert"
+)
+
+func waitForContext(ctx *httpfx.Context) httpfx.Result {
+	select {
+	case <-ctx.Request.Context().Done():
+		return ctx.Results.Error(http.StatusInternalServerError)
+	case <-time.After(time.Second):
+		return ctx.Results.PlainText([]byte("done"))
+	}
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	t.Parallel()
+
+	router := httpfx.NewRouter("/")
+	router.Use(middlewares.TimeoutMiddleware(20 * time.Millisecond))
+	router.Route("GET /fast", func(ctx *httpfx.Context) httpfx.Result {
+		return ctx.Results.PlainText([]byte("fast"))
+	})
+	router.Route("GET /slow", waitForContext)
+	router.Route("GET /export", waitForContext).HasTimeout(5 * time.Second)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "completes within the timeout",
+			path:           "/fast",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "fast",
+		},
+		{
+			name:           "exceeds the timeout",
+			path:           "/slow",
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedBody:   "Request timed out",
+		},
+		{
+			name:           "route overrides the timeout",
+			path:           "/export",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "done",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res := httptest.NewRecorder()
+			router.GetMux().ServeHTTP(res, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, res.Code)
+			assert.Equal(t, tt.expectedBody, res.Body.String())
+		})
+	}
+}
+
+func TestTimeoutMiddleware_RouteTimeoutShorter(t *testing.T) {
+	t.Parallel()
+
+	router := httpfx.NewRouter("/")
+	router.Use(middlewares.TimeoutMiddleware(5 * time.Second))
+	router.Route("GET /slow", waitForContext).HasTimeout(20 * time.Millisecond)
+
+	res := httptest.NewRecorder()
+	router.GetMux().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	assert.Equal(t, http.StatusGatewayTimeout, res.Code)
+}
+
+func TestTimeoutMiddleware_EventStream(t *testing.T) {
+	t.Parallel()
+
+	router := httpfx.NewRouter("/")
+	router.Use(middlewares.TimeoutMiddleware(20 * time.Millisecond))
+	router.Route("GET /events", func(ctx *httpfx.Context) httpfx.Result {
+		return ctx.Results.EventStream(func(stream *httpfx.EventStream) {
+			select {
+			case <-stream.Done():
+				return
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			_ = stream.Send(httpfx.Event{ID: "", Event: "", Data: []byte("late"), Retry: 0})
+		}, httpfx.WithHeartbeatInterval(0))
+	})
+
+	res := httptest.NewRecorder()
+	router.GetMux().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Body.String(), "data: late\n")
+}
@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+)
+
+// RegisterHTTPRoutes serves the metrics of logger on GET /metrics in the Prometheus
+// text format. logger.EnablePrometheus must be called before the metrics are built,
+// otherwise there is nothing to serve and the route is not registered.
+func RegisterHTTPRoutes(routes *httpfx.Router, config *httpfx.Config, logger *logfx.Logger) {
+	if !config.MetricsEnabled || logger.InnerPrometheusExporter == nil {
+		return
+	}
+
+	mux := routes.GetMux()
+
+	mux.Handle("GET /metrics", logger.InnerPrometheusExporter)
+}
@@ -5,6 +5,8 @@ import (
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
+const bearerSecurityScheme = "bearerAuth"
+
 func GenerateOpenAPISpec(identity *APIIdentity, routes *httpfx.Router) any {
 	spec := &openapi3.T{ //nolint:exhaustruct
 		OpenAPI: "3.0.0",
@@ -36,6 +38,20 @@ func GenerateOpenAPISpec(identity *APIIdentity, routes *httpfx.Router) any {
 			Deprecated: route.Spec.Deprecated,
 		}
 
+		if route.Spec.RequiresAuth {
+			spec.Components.SecuritySchemes = openapi3.SecuritySchemes{
+				bearerSecurityScheme: &openapi3.SecuritySchemeRef{ //nolint:exhaustruct
+					Value: openapi3.NewJWTSecurityScheme(),
+				},
+			}
+
+			operation.Security = &openapi3.SecurityRequirements{
+				openapi3.SecurityRequirement{
+					bearerSecurityScheme: append([]string{}, route.Spec.Scopes...),
+				},
+			}
+		}
+
 		for _, response := range route.Spec.Responses {
 			description := ""
 
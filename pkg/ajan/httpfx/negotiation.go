@@ -0,0 +1,275 @@
+package httpfx
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	MediaTypeJSON = "application/json"
+	MediaTypeXML  = "application/xml"
+	MediaTypeCSV  = "text/csv"
+)
+
+var ErrUnsupportedCSVBody = errors.New("body cannot be encoded as CSV")
+
+// CSVExporter is implemented by bodies that wrap their rows, such as paginated
+// responses, to export the rows as CSV. CSVRows returns a slice of structs.
+type CSVExporter interface {
+	CSVRows() any
+}
+
+// xmlDocument gives XML bodies a single root element.
+type xmlDocument struct {
+	XMLName xml.Name `xml:"response"`
+	Items   any      `xml:"item"`
+}
+
+// csvColumn is a struct field exported as a CSV column.
+type csvColumn struct {
+	name  string
+	index []int
+}
+
+// Negotiate encodes body as JSON, XML or CSV, whichever the Accept header of req
+// prefers. JSON is used when the header is missing or accepts anything, and 406 Not
+// Acceptable is returned when none of them is accepted. CSV bodies must be slices of
+// structs, or implement CSVExporter. Columns are named after the JSON names of the
+// fields, and nested values are written as JSON.
+func (r *Results) Negotiate(req *http.Request, body any) Result {
+	mediaType := negotiateMediaType(req.Header.Get("Accept"))
+
+	var (
+		encoded []byte
+		err     error
+	)
+
+	switch mediaType {
+	case MediaTypeJSON:
+		encoded, err = json.Marshal(body)
+	case MediaTypeXML:
+		encoded, err = xml.Marshal(xmlDocument{Items: body}) //nolint:exhaustruct
+		encoded = append([]byte(xml.Header), encoded...)
+	case MediaTypeCSV:
+		encoded, err = encodeCSV(body)
+		mediaType += "; charset=utf-8"
+	default:
+		return r.Error(
+			http.StatusNotAcceptable,
+			WithPlainText("Supported media types: "+MediaTypeJSON+", "+MediaTypeXML+", "+MediaTypeCSV),
+		)
+	}
+
+	if err != nil {
+		// TODO(@eser) Log error
+		return r.Error(
+			http.StatusInternalServerError,
+			WithPlainText("Failed to encode response"),
+		)
+	}
+
+	return Result{
+		Result: okResult.New(),
+
+		InnerStatusCode:    http.StatusOK,
+		InnerRedirectToURI: "",
+		InnerContentType:   mediaType,
+		InnerStream:        nil,
+		InnerBody:          encoded,
+	}
+}
+
+// negotiateMediaType returns the supported media type with the highest quality in
+// accept, preferring JSON on ties, or an empty string when none is acceptable.
+func negotiateMediaType(accept string) string {
+	if strings.TrimSpace(accept) == "" {
+		return MediaTypeJSON
+	}
+
+	best := ""
+	bestQuality := 0.0
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+
+		if q, ok := params["q"]; ok {
+			quality, err = strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+		}
+
+		var supported string
+
+		switch mediaType {
+		case "*/*", "application/*", MediaTypeJSON:
+			supported = MediaTypeJSON
+		case MediaTypeXML, "text/xml":
+			supported = MediaTypeXML
+		case MediaTypeCSV, "text/*":
+			supported = MediaTypeCSV
+		default:
+			continue
+		}
+
+		if quality > bestQuality || (quality == bestQuality && supported == MediaTypeJSON) {
+			best = supported
+			bestQuality = quality
+		}
+	}
+
+	return best
+}
+
+func encodeCSV(body any) ([]byte, error) {
+	if exporter, ok := body.(CSVExporter); ok {
+		body = exporter.CSVRows()
+	}
+
+	rows := reflect.Indirect(reflect.ValueOf(body))
+	if rows.Kind() != reflect.Slice && rows.Kind() != reflect.Array {
+		return nil, fmt.Errorf("%w (type=%T)", ErrUnsupportedCSVBody, body)
+	}
+
+	rowType := rows.Type().Elem()
+	for rowType.Kind() == reflect.Pointer {
+		rowType = rowType.Elem()
+	}
+
+	if rowType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w (type=%T)", ErrUnsupportedCSVBody, body)
+	}
+
+	columns := csvColumns(rowType, nil)
+
+	var buffer bytes.Buffer
+
+	writer := csv.NewWriter(&buffer)
+
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.name
+	}
+
+	_ = writer.Write(header)
+
+	for i := range rows.Len() {
+		row := reflect.Indirect(rows.Index(i))
+		record := make([]string, len(columns))
+
+		for j, column := range columns {
+			if !row.IsValid() {
+				continue
+			}
+
+			field, err := row.FieldByIndexErr(column.index)
+			if err != nil {
+				// A nil embedded struct leaves its columns empty.
+				continue
+			}
+
+			record[j] = csvCell(field)
+		}
+
+		_ = writer.Write(record)
+	}
+
+	writer.Flush()
+
+	return buffer.Bytes(), writer.Error() //nolint:wrapcheck
+}
+
+// csvColumns lists the exported fields of t, flattening embedded structs as
+// encoding/json does.
+func csvColumns(t reflect.Type, parent []int) []csvColumn {
+	columns := make([]csvColumn, 0, t.NumField())
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		index := append(append(make([]int, 0, len(parent)+1), parent...), i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			columns = append(columns, csvColumns(fieldType, index)...)
+
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		columns = append(columns, csvColumn{name: name, index: index})
+	}
+
+	return columns
+}
+
+func csvCell(value reflect.Value) string {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return ""
+		}
+
+		value = value.Elem()
+	}
+
+	if (value.Kind() == reflect.Map || value.Kind() == reflect.Slice) && value.IsNil() {
+		return ""
+	}
+
+	var cell string
+
+	switch typed := value.Interface().(type) {
+	case string:
+		cell = typed
+	case time.Time:
+		cell = typed.Format(time.RFC3339)
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		cell = fmt.Sprint(typed)
+	default:
+		encoded, err := json.Marshal(typed)
+		if err != nil {
+			return ""
+		}
+
+		cell = string(encoded)
+	}
+
+	// Spreadsheets run cells starting with these as formulas.
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		if _, err := strconv.ParseFloat(cell, 64); err != nil {
+			cell = "'" + cell
+		}
+	}
+
+	return cell
+}
@@ -0,0 +1,129 @@
+package httpfx_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/stretchr/testify/assert"
+)
+
+type negotiationBase struct {
+	ID string `json:"id"`
+}
+
+type negotiationRecord struct {
+	*negotiationBase
+
+	CreatedAt time.Time         `json:"created_at"`
+	Tags      []string          `json:"tags"`
+	Pronouns  *string           `json:"pronouns"`
+	Title     string            `json:"title"`
+	Secret    string            `json:"-"`
+	Count     int               `json:"count"`
+	Meta      map[string]string `json:"meta,omitempty"`
+}
+
+type negotiationPage struct {
+	Data []negotiationRecord `json:"data"`
+}
+
+func (p negotiationPage) CSVRows() any {
+	return p.Data
+}
+
+func TestResults_Negotiate(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	records := []negotiationRecord{
+		{
+			negotiationBase: &negotiationBase{ID: "1"},
+			CreatedAt:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Tags:            []string{"go"},
+			Pronouns:        nil,
+			Title:           "=HYPERLINK(\"x\")",
+			Secret:          "hidden",
+			Count:           -3,
+			Meta:            nil,
+		},
+	}
+
+	tests := []struct {
+		name            string
+		accept          string
+		body            any
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "no accept header",
+			accept:          "",
+			body:            map[string]int{"count": 1},
+			wantStatus:      http.StatusOK,
+			wantContentType: httpfx.MediaTypeJSON,
+			wantBody:        `{"count":1}`,
+		},
+		{
+			name:            "browser accept header",
+			accept:          "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+			body:            []string{"a"},
+			wantStatus:      http.StatusOK,
+			wantContentType: httpfx.MediaTypeXML,
+			wantBody:        "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<response><item>a</item></response>",
+		},
+		{
+			name:            "csv",
+			accept:          "text/csv",
+			body:            records,
+			wantStatus:      http.StatusOK,
+			wantContentType: httpfx.MediaTypeCSV + "; charset=utf-8",
+			wantBody: "id,created_at,tags,pronouns,title,count,meta\n" +
+				"1,2024-01-02T03:04:05Z,\"[\"\"go\"\"]\",,\"'=HYPERLINK(\"\"x\"\")\",-3,\n",
+		},
+		{
+			name:            "csv exporter",
+			accept:          "application/json;q=0.5, text/csv",
+			body:            negotiationPage{Data: records[:0]},
+			wantStatus:      http.StatusOK,
+			wantContentType: httpfx.MediaTypeCSV + "; charset=utf-8",
+			wantBody:        "id,created_at,tags,pronouns,title,count,meta\n",
+		},
+		{
+			name:            "csv of a non-slice",
+			accept:          "text/csv",
+			body:            map[string]int{"count": 1},
+			wantStatus:      http.StatusInternalServerError,
+			wantContentType: "",
+			wantBody:        "Failed to encode response",
+		},
+		{
+			name:            "not acceptable",
+			accept:          "image/png",
+			body:            records,
+			wantStatus:      http.StatusNotAcceptable,
+			wantContentType: "",
+			wantBody:        "Supported media types: application/json, application/xml, text/csv",
+		},
+	}
+
+	for _, tt := range tests { //nolint:varnamelen
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/records", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			results := httpfx.Results{}
+			result := results.Negotiate(req, tt.body)
+
+			assert.Equal(t, tt.wantStatus, result.StatusCode())
+			assert.Equal(t, tt.wantContentType, result.ContentType())
+			assert.Equal(t, tt.wantBody, string(result.Body()))
+		})
+	}
+}
@@ -1,14 +1,21 @@
 package httpfx
 
 import (
+	"net/http"
+
 	"github.com/eser/aya.is-services/pkg/ajan/results"
 )
 
+// StreamFunc writes a response incrementally instead of from a buffered body.
+type StreamFunc func(http.ResponseWriter, *http.Request)
+
 type Result struct { //nolint:errname
 	InnerRedirectToURI string
 	results.Result
 
-	InnerBody []byte
+	InnerContentType string
+	InnerBody        []byte
+	InnerStream      StreamFunc
 
 	InnerStatusCode int
 }
@@ -17,10 +24,22 @@ func (r Result) StatusCode() int {
 	return r.InnerStatusCode
 }
 
+// ContentType returns the media type of the body, or an empty string when it is
+// left to the handlers or to content sniffing.
+func (r Result) ContentType() string {
+	return r.InnerContentType
+}
+
 func (r Result) Body() []byte {
 	return r.InnerBody
 }
 
+// Stream returns the function that writes a streamed response, or nil when the
+// result has a buffered body.
+func (r Result) Stream() StreamFunc {
+	return r.InnerStream
+}
+
 func (r Result) RedirectToURI() string {
 	return r.InnerRedirectToURI
 }
@@ -58,6 +58,8 @@ func (r *Results) Ok(options ...ResultOption) Result {
 
 		InnerStatusCode:    http.StatusNoContent,
 		InnerRedirectToURI: "",
+		InnerContentType:   "",
+		InnerStream:        nil,
 		InnerBody:          make([]byte, 0),
 	}
 
@@ -74,6 +76,8 @@ func (r *Results) Accepted(options ...ResultOption) Result {
 
 		InnerStatusCode:    http.StatusAccepted,
 		InnerRedirectToURI: "",
+		InnerContentType:   "",
+		InnerStream:        nil,
 		InnerBody:          make([]byte, 0),
 	}
 
@@ -90,6 +94,8 @@ func (r *Results) NotFound(options ...ResultOption) Result {
 
 		InnerStatusCode:    http.StatusNotFound,
 		InnerRedirectToURI: "",
+		InnerContentType:   "",
+		InnerStream:        nil,
 		InnerBody:          []byte("Not Found"),
 	}
 
@@ -106,6 +112,8 @@ func (r *Results) Unauthorized(options ...ResultOption) Result {
 
 		InnerStatusCode:    http.StatusUnauthorized,
 		InnerRedirectToURI: "",
+		InnerContentType:   "",
+		InnerStream:        nil,
 		InnerBody:          make([]byte, 0),
 	}
 
@@ -122,6 +130,8 @@ func (r *Results) BadRequest(options ...ResultOption) Result {
 
 		InnerStatusCode:    http.StatusBadRequest,
 		InnerRedirectToURI: "",
+		InnerContentType:   "",
+		InnerStream:        nil,
 		InnerBody:          []byte("Bad Request"),
 	}
 
@@ -138,6 +148,8 @@ func (r *Results) Error(statusCode int, options ...ResultOption) Result {
 
 		InnerStatusCode:    statusCode,
 		InnerRedirectToURI: "",
+		InnerContentType:   "",
+		InnerStream:        nil,
 		InnerBody:          make([]byte, 0),
 	}
 
@@ -155,6 +167,8 @@ func (r *Results) Bytes(body []byte) Result {
 
 		InnerStatusCode:    http.StatusOK,
 		InnerRedirectToURI: "",
+		InnerContentType:   "",
+		InnerStream:        nil,
 		InnerBody:          body,
 	}
 }
@@ -165,6 +179,8 @@ func (r *Results) PlainText(body []byte) Result {
 
 		InnerStatusCode:    http.StatusOK,
 		InnerRedirectToURI: "",
+		InnerContentType:   "",
+		InnerStream:        nil,
 		InnerBody:          body,
 	}
 }
@@ -178,6 +194,8 @@ func (r *Results) JSON(body any) Result {
 
 			InnerStatusCode:    http.StatusInternalServerError,
 			InnerRedirectToURI: "",
+			InnerContentType:   "",
+			InnerStream:        nil,
 			InnerBody:          []byte("Failed to encode JSON"),
 		}
 	}
@@ -187,6 +205,8 @@ func (r *Results) JSON(body any) Result {
 
 		InnerStatusCode:    http.StatusOK,
 		InnerRedirectToURI: "",
+		InnerContentType:   "",
+		InnerStream:        nil,
 		InnerBody:          encoded,
 	}
 }
@@ -197,10 +217,46 @@ func (r *Results) Redirect(uri string) Result {
 
 		InnerStatusCode:    http.StatusTemporaryRedirect,
 		InnerRedirectToURI: uri,
+		InnerContentType:   "",
+		InnerStream:        nil,
 		InnerBody:          make([]byte, 0),
 	}
 }
 
+// MovedPermanently redirects to uri for good, e.g. once a resource is renamed. options
+// may set a body for clients that do not follow redirects.
+func (r *Results) MovedPermanently(uri string, options ...ResultOption) Result {
+	result := Result{
+		Result: okResult.New(),
+
+		InnerStatusCode:    http.StatusMovedPermanently,
+		InnerRedirectToURI: uri,
+		InnerContentType:   "",
+		InnerStream:        nil,
+		InnerBody:          make([]byte, 0),
+	}
+
+	for _, option := range options {
+		option(&result)
+	}
+
+	return result
+}
+
+// Stream responds with fn, which writes the headers and the body itself, e.g. to copy
+// a large file without buffering it.
+func (r *Results) Stream(fn StreamFunc) Result {
+	return Result{
+		Result: okResult.New(),
+
+		InnerStatusCode:    http.StatusOK,
+		InnerRedirectToURI: "",
+		InnerContentType:   "",
+		InnerStream:        fn,
+		InnerBody:          nil,
+	}
+}
+
 func (r *Results) Abort() Result {
 	// TODO(@eser) implement this
 	return Result{
@@ -208,6 +264,8 @@ func (r *Results) Abort() Result {
 
 		InnerStatusCode:    http.StatusNotImplemented,
 		InnerRedirectToURI: "",
+		InnerContentType:   "",
+		InnerStream:        nil,
 		InnerBody:          []byte("Not Implemented"),
 	}
 }
@@ -78,6 +78,31 @@ func TestResults_Redirect(t *testing.T) {
 	assert.Equal(t, uri, result.RedirectToURI())
 }
 
+func TestResults_MovedPermanently(t *testing.T) {
+	t.Parallel()
+
+	results := &httpfx.Results{}
+	uri := "/new-location"
+	result := results.MovedPermanently(uri, httpfx.WithPlainText("moved"))
+
+	assert.Equal(t, http.StatusMovedPermanently, result.StatusCode())
+	assert.Equal(t, "moved", string(result.Body()))
+	assert.Equal(t, uri, result.RedirectToURI())
+}
+
+func TestResults_Stream(t *testing.T) {
+	t.Parallel()
+
+	results := &httpfx.Results{}
+	result := results.Stream(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	assert.Equal(t, http.StatusOK, result.StatusCode())
+	assert.NotNil(t, result.Stream())
+	assert.Empty(t, result.Body())
+}
+
 func TestResults_NotFound(t *testing.T) {
 	t.Parallel()
 
@@ -1,16 +1,21 @@
 package httpfx
 
 import (
+	"cmp"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/eser/aya.is-services/pkg/ajan/httpfx/uris"
 	"github.com/eser/aya.is-services/pkg/ajan/lib"
 )
 
 type Router struct {
-	mux  *http.ServeMux
-	path string
+	mux    *http.ServeMux
+	parent *Router
+	path   string
+	// prefix is prepended to the paths of the routes; it is empty for root routers.
+	prefix string
 
 	handlers []Handler
 	routes   []*Route
@@ -20,8 +25,10 @@ func NewRouter(path string) *Router {
 	mux := http.NewServeMux()
 
 	return &Router{
-		mux:  mux,
-		path: path,
+		mux:    mux,
+		parent: nil,
+		path:   path,
+		prefix: "",
 
 		handlers: make([]Handler, 0),
 		routes:   make([]*Route, 0),
@@ -44,8 +51,22 @@ func (r *Router) GetRoutes() []*Route {
 	return r.routes
 }
 
-func (r *Router) Group(path string) *Router {
-	return NewRouter(r.path + path)
+// Group returns a router for the routes under path, sharing the mux of r. Routes
+// of the group run the middlewares of r first, then the handlers given here and
+// the ones added later with Use on the group. The routes of a group are listed
+// by GetRoutes of its parents as well.
+func (r *Router) Group(path string, handlers ...Handler) *Router {
+	path = strings.TrimSuffix(path, "/")
+
+	return &Router{
+		mux:    r.mux,
+		parent: r,
+		path:   cmp.Or(strings.TrimSuffix(r.path, "/")+path, "/"),
+		prefix: r.prefix + path,
+
+		handlers: append(make([]Handler, 0, len(handlers)), handlers...),
+		routes:   make([]*Route, 0),
+	}
 }
 
 func (r *Router) Use(handlers ...Handler) {
@@ -53,7 +74,7 @@ func (r *Router) Use(handlers ...Handler) {
 }
 
 func (r *Router) Route(pattern string, handlers ...Handler) *Route {
-	parsed, err := uris.ParsePattern(pattern)
+	parsed, err := uris.ParsePattern(prefixPattern(pattern, r.prefix))
 	if err != nil {
 		panic(err)
 	}
@@ -62,7 +83,7 @@ func (r *Router) Route(pattern string, handlers ...Handler) *Route {
 
 	route := &Route{Pattern: parsed, Handlers: handlers} //nolint:exhaustruct
 	route.MuxHandlerFunc = func(responseWriter http.ResponseWriter, req *http.Request) {
-		routeHandlers := lib.ArraysCopy(r.handlers, route.Handlers)
+		routeHandlers := lib.ArraysCopy(r.chainHandlers(), route.Handlers)
 
 		ctx := &Context{
 			Request:        req,
@@ -77,6 +98,20 @@ func (r *Router) Route(pattern string, handlers ...Handler) *Route {
 
 		result := routeHandlers[0](ctx)
 
+		if stream := result.Stream(); stream != nil {
+			stream(responseWriter, ctx.Request)
+
+			return
+		}
+
+		if contentType := result.ContentType(); contentType != "" {
+			responseWriter.Header().Set("Content-Type", contentType)
+		}
+
+		if redirectToURI := result.RedirectToURI(); redirectToURI != "" {
+			responseWriter.Header().Set("Location", redirectToURI)
+		}
+
 		responseWriter.WriteHeader(result.StatusCode())
 
 		_, err := responseWriter.Write(result.Body())
@@ -89,7 +124,45 @@ func (r *Router) Route(pattern string, handlers ...Handler) *Route {
 	// TODO(@eser) r.Path+route.Pattern
 	r.mux.HandleFunc(route.Pattern.Str, route.MuxHandlerFunc)
 
-	r.routes = append(r.routes, route)
+	for router := r; router != nil; router = router.parent {
+		router.routes = append(router.routes, route)
+	}
 
 	return route
 }
+
+// chainHandlers returns the middlewares of the router, after the ones of its parents.
+func (r *Router) chainHandlers() []Handler {
+	if r.parent == nil {
+		return r.handlers
+	}
+
+	return lib.ArraysCopy(r.parent.chainHandlers(), r.handlers)
+}
+
+// prefixPattern inserts prefix before the path of pattern, keeping its method and
+// host.
+func prefixPattern(pattern string, prefix string) string {
+	if prefix == "" {
+		return pattern
+	}
+
+	method, rest, found := strings.Cut(pattern, " ")
+	if !found {
+		rest = method
+		method = ""
+	}
+
+	i := strings.IndexByte(rest, '/')
+	if i < 0 {
+		// Leave it to the pattern parser to report.
+		return pattern
+	}
+
+	prefixed := rest[:i] + prefix + rest[i:]
+	if !found {
+		return prefixed
+	}
+
+	return method + " " + prefixed
+}
@@ -176,3 +176,88 @@ func TestRouter_RouteWithMiddleware(t *testing.T) {
 	assert.Equal(t, "test", w.Body.String())
 	assert.Equal(t, "middleware", w.Header().Get("X-Test"))
 }
+
+func TestRouter_RouteWithRedirect(t *testing.T) {
+	t.Parallel()
+
+	router := httpfx.NewRouter("/api")
+	require.NotNil(t, router)
+
+	route := router.Route("GET /old", func(ctx *httpfx.Context) httpfx.Result {
+		return ctx.Results.MovedPermanently("/new")
+	})
+	require.NotNil(t, route)
+
+	req := httptest.NewRequest(http.MethodGet, "/old", nil)
+	w := httptest.NewRecorder() //nolint:varnamelen
+
+	route.MuxHandlerFunc(w, req)
+
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/new", w.Header().Get("Location"))
+}
+
+func TestRouter_GroupRoutes(t *testing.T) {
+	t.Parallel()
+
+	router := httpfx.NewRouter("/")
+
+	tag := func(value string) httpfx.Handler {
+		return func(ctx *httpfx.Context) httpfx.Result {
+			ctx.ResponseWriter.Header().Add("X-Chain", value)
+
+			return ctx.Next()
+		}
+	}
+
+	handler := func(ctx *httpfx.Context) httpfx.Result {
+		return ctx.Results.PlainText([]byte(ctx.Request.URL.Path))
+	}
+
+	router.Use(tag("root"))
+
+	admin := router.Group("/admin", tag("admin"))
+	admin.Route("GET /users/{id}", handler)
+
+	reports := admin.Group("/reports/")
+	reports.Use(tag("reports"))
+	reports.Route("GET /daily", handler)
+
+	router.Route("GET /public", handler)
+
+	assert.Equal(t, "/admin", admin.GetPath())
+	assert.Equal(t, "/admin/reports", reports.GetPath())
+	assert.Len(t, router.GetRoutes(), 3)
+	assert.Len(t, admin.GetRoutes(), 2)
+	assert.Len(t, reports.GetRoutes(), 1)
+
+	tests := []struct {
+		name      string
+		path      string
+		wantChain []string
+		wantCode  int
+	}{
+		{"group route", "/admin/users/1", []string{"root", "admin"}, http.StatusOK},
+		{"nested group route", "/admin/reports/daily", []string{"root", "admin", "reports"}, http.StatusOK},
+		{"root route", "/public", []string{"root"}, http.StatusOK},
+		{"unprefixed group route", "/users/1", nil, http.StatusNotFound},
+	}
+
+	for _, tt := range tests { //nolint:varnamelen
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder() //nolint:varnamelen
+
+			router.GetMux().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Equal(t, tt.wantChain, w.Header().Values("X-Chain"))
+
+			if tt.wantCode == http.StatusOK {
+				assert.Equal(t, tt.path, w.Body.String())
+			}
+		})
+	}
+}
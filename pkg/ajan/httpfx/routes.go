@@ -2,6 +2,7 @@ package httpfx
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/eser/aya.is-services/pkg/ajan/httpfx/uris"
 )
@@ -42,9 +43,13 @@ type RouteOpenAPISpec struct {
 	Description string
 	Tags        []string
 
-	Requests   []RouteOpenAPISpecRequest
-	Responses  []RouteOpenAPISpecResponse
-	Deprecated bool
+	Requests  []RouteOpenAPISpecRequest
+	Responses []RouteOpenAPISpecResponse
+	// Scopes lists the scopes the route requires, set by RequireScope.
+	Scopes []string
+
+	Deprecated   bool
+	RequiresAuth bool
 }
 
 type Route struct {
@@ -52,6 +57,8 @@ type Route struct {
 	Parameters     []RouterParameter
 	Handlers       []Handler
 	MuxHandlerFunc func(http.ResponseWriter, *http.Request)
+	// Timeout overrides the request timeout of TimeoutMiddleware when it is set.
+	Timeout time.Duration
 
 	Spec RouteOpenAPISpec
 }
@@ -80,6 +87,13 @@ func (r *Route) HasTags(tags ...string) *Route {
 	return r
 }
 
+// HasTimeout overrides the request timeout for the route, e.g. for slow exports.
+func (r *Route) HasTimeout(timeout time.Duration) *Route {
+	r.Timeout = timeout
+
+	return r
+}
+
 func (r *Route) IsDeprecated() *Route {
 	r.Spec.Deprecated = true
 
@@ -5,12 +5,26 @@ import (
 	"strings"
 )
 
+const (
+	// EnvVariable selects the environment profile, e.g. APP_ENV=production.
+	EnvVariable = "APP_ENV"
+	// EnvVariableLegacy is read when EnvVariable is not set.
+	EnvVariableLegacy = "env"
+	// EnvDefault is the profile when neither variable is set.
+	EnvDefault = "development"
+)
+
+// EnvGetCurrent returns the environment profile, in lower case.
 func EnvGetCurrent() string {
-	// FIXME(@eser) no need to use os.Lookupenv here
-	env := strings.ToLower(os.Getenv("env"))
+	env := os.Getenv(EnvVariable)
+	if env == "" {
+		env = os.Getenv(EnvVariableLegacy)
+	}
+
+	env = strings.ToLower(strings.TrimSpace(env))
 
 	if env == "" {
-		env = "development"
+		env = EnvDefault
 	}
 
 	return env
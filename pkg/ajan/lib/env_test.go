@@ -147,13 +147,14 @@ func TestEnvGetCurrent(t *testing.T) {
 		},
 		{
 			name:     "should return lowercase value of ENV with leading/trailing spaces",
-			envValue: "staging",
+			envValue: " Staging ",
 			expected: "staging",
 		},
 	}
 
 	for _, tt := range tests { //nolint:varnamelen
 		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("APP_ENV", "")
 			t.Setenv("env", tt.envValue)
 
 			actual := lib.EnvGetCurrent()
@@ -163,6 +164,17 @@ func TestEnvGetCurrent(t *testing.T) {
 	}
 }
 
+func TestEnvGetCurrent_AppEnv(t *testing.T) {
+	t.Setenv("env", "staging")
+	t.Setenv("APP_ENV", " Production ")
+
+	assert.Equal(t, "production", lib.EnvGetCurrent())
+
+	t.Setenv("APP_ENV", "")
+
+	assert.Equal(t, "staging", lib.EnvGetCurrent())
+}
+
 func TestEnvOverrideVariables(t *testing.T) {
 	tests := []struct {
 		name         string
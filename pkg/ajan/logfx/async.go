@@ -0,0 +1,173 @@
+package logfx
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// asyncQueue is a bounded ring buffer of records, drained by a background goroutine.
+// When it is full, the oldest record is dropped to make room for the newest. It is
+// shared by a handler and the handlers derived from it.
+type asyncQueue struct {
+	reporter *Handler
+	drained  *sync.Cond
+	wake     chan struct{}
+	done     chan struct{}
+
+	buffer   []asyncEntry
+	head     int
+	count    int
+	inFlight int
+	closed   bool
+
+	dropped  atomic.Uint64
+	reported uint64 // only accessed by the goroutine
+
+	mu sync.Mutex
+}
+
+type asyncEntry struct {
+	ctx     context.Context //nolint:containedctx
+	handler *Handler
+	rec     slog.Record
+}
+
+func newAsyncQueue(size int, reporter *Handler) *asyncQueue {
+	queue := &asyncQueue{ //nolint:exhaustruct
+		reporter: reporter,
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+		buffer:   make([]asyncEntry, size),
+	}
+
+	queue.drained = sync.NewCond(&queue.mu)
+
+	go queue.run()
+
+	return queue
+}
+
+// enqueue adds an entry to the queue, and returns false when the queue is closed.
+func (q *asyncQueue) enqueue(entry asyncEntry) bool {
+	q.mu.Lock()
+
+	if q.closed {
+		q.mu.Unlock()
+
+		return false
+	}
+
+	if q.count == len(q.buffer) {
+		q.buffer[q.head] = entry
+		q.head = (q.head + 1) % len(q.buffer)
+		q.dropped.Add(1)
+	} else {
+		q.buffer[(q.head+q.count)%len(q.buffer)] = entry
+		q.count++
+	}
+
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+
+	return true
+}
+
+// flush waits until the queued records are handled.
+func (q *asyncQueue) flush() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for q.count > 0 || q.inFlight > 0 {
+		q.drained.Wait()
+	}
+}
+
+// close handles the queued records and stops the goroutine. Records handled
+// afterwards are not queued.
+func (q *asyncQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+
+	<-q.done
+}
+
+func (q *asyncQueue) run() {
+	for {
+		batch := q.take()
+
+		for _, entry := range batch {
+			// There is no caller left to return the error to.
+			_ = entry.handler.write(entry.ctx, entry.rec)
+		}
+
+		q.reportDrops()
+
+		q.mu.Lock()
+		q.inFlight = 0
+		q.drained.Broadcast()
+
+		if q.count > 0 {
+			q.mu.Unlock()
+
+			continue
+		}
+
+		if q.closed {
+			q.mu.Unlock()
+			close(q.done)
+
+			return
+		}
+
+		q.mu.Unlock()
+
+		<-q.wake
+	}
+}
+
+func (q *asyncQueue) take() []asyncEntry {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	batch := make([]asyncEntry, q.count)
+
+	for i := range batch {
+		index := (q.head + i) % len(q.buffer)
+		batch[i] = q.buffer[index]
+		q.buffer[index] = asyncEntry{} //nolint:exhaustruct
+	}
+
+	q.head = 0
+	q.count = 0
+	q.inFlight = len(batch)
+
+	return batch
+}
+
+// reportDrops logs how many records were dropped since the last report.
+func (q *asyncQueue) reportDrops() {
+	dropped := q.dropped.Load()
+	if dropped == q.reported {
+		return
+	}
+
+	rec := slog.NewRecord(time.Now(), slog.LevelWarn, "log records dropped by the async handler", 0)
+	rec.AddAttrs(slog.Uint64("dropped", dropped-q.reported))
+
+	q.reported = dropped
+
+	_ = q.reporter.write(context.Background(), rec)
+}
@@ -0,0 +1,112 @@
+package logfx_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingWriter blocks writes until it is released.
+type blockingWriter struct {
+	release chan struct{}
+	buffer  bytes.Buffer
+	mu      sync.Mutex
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.buffer.Write(p)
+}
+
+func (w *blockingWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.buffer.String()
+}
+
+func TestHandler_Async(t *testing.T) {
+	t.Parallel()
+
+	writer := &blockingWriter{release: make(chan struct{})} //nolint:exhaustruct
+
+	handler := logfx.NewHandler(DefaultScopeName, writer, &logfx.Config{ //nolint:exhaustruct
+		Level:           "INFO",
+		AsyncBufferSize: 16,
+	})
+
+	var exported []string
+
+	handler.AddSubscriber(func(_ context.Context, rec slog.Record) error {
+		exported = append(exported, rec.Message)
+
+		return nil
+	})
+
+	logger := slog.New(handler).With(slog.String("component", "jobs"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Handle returns while the writer is blocked.
+	for i := range 3 {
+		logger.InfoContext(ctx, fmt.Sprintf("message %d", i))
+	}
+
+	cancel()
+	close(writer.release)
+
+	handler.Flush()
+
+	output := writer.String()
+
+	assert.Equal(t, 3, strings.Count(output, `"component":"jobs"`))
+	assert.Equal(t, []string{"message 0", "message 1", "message 2"}, exported)
+	assert.Zero(t, handler.DroppedRecords())
+}
+
+func TestHandler_AsyncDrops(t *testing.T) {
+	t.Parallel()
+
+	writer := &blockingWriter{release: make(chan struct{})} //nolint:exhaustruct
+
+	handler := logfx.NewHandler(DefaultScopeName, writer, &logfx.Config{ //nolint:exhaustruct
+		Level:           "INFO",
+		AsyncBufferSize: 2,
+	})
+
+	logger := slog.New(handler)
+
+	// The first record may be taken by the goroutine before the buffer fills up, so
+	// at least 10 - 1 - 2 records are dropped.
+	for i := range 10 {
+		logger.Info(fmt.Sprintf("message %d", i))
+	}
+
+	close(writer.release)
+	handler.Close()
+
+	dropped := handler.DroppedRecords()
+	assert.GreaterOrEqual(t, dropped, uint64(7))
+
+	output := writer.String()
+
+	assert.Contains(t, output, `"msg":"message 9"`)
+	assert.NotContains(t, output, `"msg":"message 1"`)
+	assert.Contains(t, output, "log records dropped by the async handler")
+	assert.Contains(t, output, fmt.Sprintf(`"dropped":%d`, dropped))
+
+	// After Close, records are handled synchronously.
+	logger.Info("after close")
+	assert.Contains(t, writer.String(), "after close")
+}
@@ -1,11 +1,45 @@
 package logfx
 
+import "time"
+
 type Config struct {
+	// Sampling keeps 1 in N records of a level, e.g. {"debug": "10"}. Records at
+	// ERROR and above are always kept.
+	Sampling map[string]string `conf:"sampling"`
+	// Levels sets the levels of scopes, e.g. {"connfx": "warn", "storage": "debug"};
+	// the other scopes use Level. See Logger.WithScope.
+	Levels map[string]string `conf:"levels"`
+	// RedactPatterns are named regular expressions whose matches are replaced in
+	// messages and string values, e.g. {"bearer": "Bearer\\s+\\S+"}.
+	RedactPatterns map[string]string `conf:"redact_patterns"`
+
 	Level string `conf:"level" default:"INFO"`
+	// MetricFields is a comma-separated list of the numeric fields recorded in
+	// histograms by EnableLogMetrics, e.g. "duration_ms,rows".
+	MetricFields string `conf:"metric_fields"`
+	// RedactKeys is a comma-separated list; the values of attributes whose keys
+	// contain one of them, case-insensitively, are masked.
+	RedactKeys string `conf:"redact_keys" default:"password,token,authorization,cookie,secret"`
+
+	// AsyncBufferSize enables async mode, where records are queued in a buffer of this
+	// size and written and exported by a background goroutine. When the buffer is
+	// full, the oldest records are dropped. 0 handles records synchronously.
+	AsyncBufferSize int `conf:"async_buffer_size" default:"0"`
+
+	// BurstLimit is how many records with the same level and message are kept per
+	// BurstWindow; the rest are dropped. 0 disables the limit.
+	BurstLimit  int           `conf:"burst_limit"  default:"0"`
+	BurstWindow time.Duration `conf:"burst_window" default:"1s"`
+
+	// Metrics derives metrics from the logs, see EnableLogMetrics.
+	Metrics bool `conf:"metrics" default:"false"`
 
 	DefaultLogger bool `conf:"default"    default:"false"`
 	PrettyMode    bool `conf:"pretty"     default:"true"`
 	AddSource     bool `conf:"add_source" default:"false"`
 
+	// File writes the logs to a rotating file instead of the writer of the logger.
+	File FileConfig `conf:"file"`
+
 	NoNativeCollectorRegistration bool `conf:"no_native_collector_registration" default:"false"`
 }
@@ -0,0 +1,30 @@
+package logfx
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+type contextAttrsKey struct{}
+
+// ContextWith returns a copy of ctx carrying attrs, in addition to the ones ctx
+// carries already. Records logged with the returned context, or a context derived from
+// it, get these attributes, e.g. a request_id attached once by a middleware.
+func ContextWith(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+
+	// Copied, so contexts derived from the same parent do not share attributes.
+	merged := slices.Concat(AttrsFromContext(ctx), attrs)
+
+	return context.WithValue(ctx, contextAttrsKey{}, merged)
+}
+
+// AttrsFromContext returns the attributes attached to ctx with ContextWith.
+func AttrsFromContext(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(contextAttrsKey{}).([]slog.Attr)
+
+	return attrs
+}
@@ -0,0 +1,70 @@
+package logfx_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextWith(t *testing.T) {
+	t.Parallel()
+
+	writer := &bytes.Buffer{}
+
+	handler := logfx.NewHandler(DefaultScopeName, writer, &logfx.Config{ //nolint:exhaustruct
+		Level:      "INFO",
+		RedactKeys: "token",
+	})
+	require.NoError(t, handler.InitError)
+
+	logger := slog.New(handler)
+
+	ctx := logfx.ContextWith(t.Context(), slog.String("request_id", "req-1"))
+	userCtx := logfx.ContextWith(ctx, slog.String("user_id", "user-1"), slog.String("token", "abc123"))
+
+	logger.InfoContext(userCtx, "profile updated")
+
+	output := writer.String()
+	assert.Contains(t, output, `"request_id":"req-1"`)
+	assert.Contains(t, output, `"user_id":"user-1"`)
+	assert.Contains(t, output, `"token":"[REDACTED]"`)
+
+	writer.Reset()
+	logger.InfoContext(ctx, "request finished")
+
+	output = writer.String()
+	assert.Contains(t, output, `"request_id":"req-1"`)
+	assert.NotContains(t, output, "user_id")
+
+	writer.Reset()
+	logger.InfoContext(context.Background(), "no request")
+
+	assert.NotContains(t, writer.String(), "request_id")
+}
+
+func TestAttrsFromContext(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, logfx.AttrsFromContext(t.Context()))
+
+	parent := logfx.ContextWith(t.Context(), slog.String("request_id", "req-1"))
+	first := logfx.ContextWith(parent, slog.String("user_id", "user-1"))
+	second := logfx.ContextWith(parent, slog.String("user_id", "user-2"))
+
+	assert.Equal(t, []slog.Attr{slog.String("request_id", "req-1")}, logfx.AttrsFromContext(parent))
+	assert.Equal(
+		t,
+		[]slog.Attr{slog.String("request_id", "req-1"), slog.String("user_id", "user-1")},
+		logfx.AttrsFromContext(first),
+	)
+	assert.Equal(
+		t,
+		[]slog.Attr{slog.String("request_id", "req-1"), slog.String("user_id", "user-2")},
+		logfx.AttrsFromContext(second),
+	)
+}
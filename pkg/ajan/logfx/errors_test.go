@@ -0,0 +1,117 @@
+package logfx_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type recordingProcessor struct {
+	records []sdklog.Record
+}
+
+func (p *recordingProcessor) OnEmit(_ context.Context, record *sdklog.Record) error {
+	p.records = append(p.records, record.Clone())
+
+	return nil
+}
+
+func (p *recordingProcessor) Shutdown(context.Context) error   { return nil }
+func (p *recordingProcessor) ForceFlush(context.Context) error { return nil }
+
+type otlpConnection struct {
+	loggerProvider *sdklog.LoggerProvider
+}
+
+func (c *otlpConnection) GetLoggerProvider() *sdklog.LoggerProvider { return c.loggerProvider }
+func (c *otlpConnection) GetMeterProvider() *sdkmetric.MeterProvider {
+	return sdkmetric.NewMeterProvider()
+}
+
+func (c *otlpConnection) GetTracerProvider() *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider()
+}
+
+type configError struct {
+	path string
+}
+
+func (e *configError) Error() string {
+	return "invalid config " + e.path
+}
+
+func TestErrorValue(t *testing.T) {
+	t.Parallel()
+
+	cause := &configError{path: "config.yaml"}
+	err := fmt.Errorf("failed to start: %w", fmt.Errorf("failed to load config: %w", cause))
+
+	group := logfx.ErrorValue(err).Group()
+	values := make(map[string]slog.Value, len(group))
+
+	for _, attr := range group {
+		values[attr.Key] = attr.Value
+	}
+
+	assert.Equal(t, err.Error(), values["msg"].String())
+	assert.Equal(t, "*logfx_test.configError", values["kind"].String())
+	assert.Equal(
+		t,
+		[]string{"failed to load config: invalid config config.yaml", "invalid config config.yaml"},
+		values["chain"].Any(),
+	)
+	assert.NotContains(t, values, "stack")
+
+	joined := logfx.ErrorValue(errors.Join(errTest, cause)).Group()
+	assert.Equal(t, []string{"test error", "invalid config config.yaml"}, joined[2].Value.Any())
+}
+
+func TestHandler_ErrorFields(t *testing.T) {
+	t.Parallel()
+
+	writer := &bytes.Buffer{}
+	processor := &recordingProcessor{} //nolint:exhaustruct
+
+	logger := logfx.NewLogger(
+		logfx.WithWriter(writer),
+		logfx.WithConfig(&logfx.Config{Level: "INFO"}), //nolint:exhaustruct
+	)
+	logger.EnableOTLP(&otlpConnection{
+		loggerProvider: sdklog.NewLoggerProvider(sdklog.WithProcessor(processor)),
+	})
+
+	err := &mockError{msg: "boom"} //nolint:exhaustruct
+	err.Add(0)
+
+	logger.Error("request failed", slog.Any("error", fmt.Errorf("handling request: %w", err)))
+
+	assert.Contains(t, writer.String(), `"kind":"*logfx_test.mockError"`)
+	assert.Contains(t, writer.String(), `"chain":["boom"]`)
+	assert.Contains(t, writer.String(), `"stack":["unknown"]`)
+
+	require.Len(t, processor.records, 1)
+
+	attrs := map[string]otellog.Value{}
+
+	processor.records[0].WalkAttributes(func(kv otellog.KeyValue) bool {
+		attrs[kv.Key] = kv.Value
+
+		return true
+	})
+
+	assert.Equal(t, "handling request: boom", attrs["error"].AsString())
+	assert.Equal(t, "*logfx_test.mockError", attrs["error.kind"].AsString())
+	assert.Equal(t, otellog.KindSlice, attrs["error.chain"].Kind())
+	assert.Equal(t, "unknown", attrs["error.stack"].AsSlice()[0].AsString())
+}
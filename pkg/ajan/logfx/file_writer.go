@@ -0,0 +1,340 @@
+package logfx
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	backupTimeFormat = "20060102T150405.000"
+	compressedSuffix = ".gz"
+
+	bytesPerMegabyte = 1024 * 1024
+)
+
+var (
+	ErrLogFilePathRequired   = errors.New("log file path is required")
+	ErrFailedToOpenLogFile   = errors.New("failed to open log file")
+	ErrFailedToRotateLogFile = errors.New("failed to rotate log file")
+	ErrLogFileClosed         = errors.New("log file is closed")
+)
+
+// FileConfig configures the rotating file sink. Logs are written to stdout when Path
+// is empty.
+type FileConfig struct {
+	Path string `conf:"path"`
+
+	// MaxAge removes backups older than it; 0 keeps them regardless of their age.
+	MaxAge time.Duration `conf:"max_age" default:"168h"`
+	// Interval rotates the file at every multiple of it, e.g. 24h rotates at
+	// midnight UTC; 0 rotates by size only.
+	Interval time.Duration `conf:"interval" default:"0"`
+
+	// MaxSizeMB rotates the file before it grows over this size; 0 disables it.
+	MaxSizeMB int `conf:"max_size_mb" default:"100"`
+	// MaxBackups is how many rotated files are kept; 0 keeps all of them.
+	MaxBackups int `conf:"max_backups" default:"7"`
+
+	Compress bool `conf:"compress" default:"true"`
+}
+
+// RotatingFileWriter is an io.WriteCloser that writes to a file and rotates it by size
+// and time. Rotated files are renamed to name-<timestamp>.ext in the same directory,
+// then compressed and pruned in the background.
+type RotatingFileWriter struct {
+	config *FileConfig
+
+	file         *os.File
+	nextRotation time.Time
+	size         int64
+
+	mu        sync.Mutex
+	millMu    sync.Mutex
+	millGroup sync.WaitGroup
+}
+
+var _ io.WriteCloser = (*RotatingFileWriter)(nil)
+
+func NewRotatingFileWriter(config *FileConfig) (*RotatingFileWriter, error) {
+	if config.Path == "" {
+		return nil, ErrLogFilePathRequired
+	}
+
+	writer := &RotatingFileWriter{ //nolint:exhaustruct
+		config: config,
+	}
+
+	err := writer.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+func (w *RotatingFileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, ErrLogFileClosed
+	}
+
+	if w.shouldRotate(int64(len(p))) {
+		err := w.rotate()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+
+	return n, err //nolint:wrapcheck
+}
+
+// Rotate closes the current file, renames it to a backup and opens a new one.
+func (w *RotatingFileWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return ErrLogFileClosed
+	}
+
+	return w.rotate()
+}
+
+// Close closes the file and waits for the pending compression and pruning.
+func (w *RotatingFileWriter) Close() error {
+	w.mu.Lock()
+
+	var err error
+
+	if w.file != nil {
+		err = w.file.Close()
+		w.file = nil
+	}
+
+	w.mu.Unlock()
+
+	w.millGroup.Wait()
+
+	return err //nolint:wrapcheck
+}
+
+func (w *RotatingFileWriter) open() error {
+	err := os.MkdirAll(filepath.Dir(w.config.Path), 0o755) //nolint:mnd
+	if err != nil {
+		return fmt.Errorf("%w (path=%q): %w", ErrFailedToOpenLogFile, w.config.Path, err)
+	}
+
+	file, err := os.OpenFile(w.config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644) //nolint:mnd
+	if err != nil {
+		return fmt.Errorf("%w (path=%q): %w", ErrFailedToOpenLogFile, w.config.Path, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return fmt.Errorf("%w (path=%q): %w", ErrFailedToOpenLogFile, w.config.Path, err)
+	}
+
+	w.file = file
+	w.size = info.Size()
+
+	if w.config.Interval > 0 {
+		w.nextRotation = time.Now().Truncate(w.config.Interval).Add(w.config.Interval)
+	}
+
+	return nil
+}
+
+func (w *RotatingFileWriter) shouldRotate(incoming int64) bool {
+	if w.config.Interval > 0 && !time.Now().Before(w.nextRotation) {
+		return true
+	}
+
+	maxSize := int64(w.config.MaxSizeMB) * bytesPerMegabyte
+
+	return maxSize > 0 && w.size > 0 && w.size+incoming > maxSize
+}
+
+func (w *RotatingFileWriter) rotate() error {
+	err := w.file.Close()
+	if err != nil {
+		return fmt.Errorf("%w (path=%q): %w", ErrFailedToRotateLogFile, w.config.Path, err)
+	}
+
+	w.file = nil
+
+	err = os.Rename(w.config.Path, w.nextBackupName(time.Now()))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w (path=%q): %w", ErrFailedToRotateLogFile, w.config.Path, err)
+	}
+
+	err = w.open()
+	if err != nil {
+		return err
+	}
+
+	w.millGroup.Add(1)
+
+	go func() {
+		defer w.millGroup.Done()
+
+		w.mill()
+	}()
+
+	return nil
+}
+
+// backupName returns the name of a backup rotated at the given time, e.g.
+// logs/app-20240115T103000.000.log for logs/app.log.
+func (w *RotatingFileWriter) backupName(rotatedAt time.Time) string {
+	prefix, ext := w.backupPrefixAndExt()
+
+	return prefix + rotatedAt.UTC().Format(backupTimeFormat) + ext
+}
+
+// nextBackupName returns the name of a backup rotated at the given time, or a
+// millisecond later when there is a backup with that name already.
+func (w *RotatingFileWriter) nextBackupName(rotatedAt time.Time) string {
+	for {
+		name := w.backupName(rotatedAt)
+
+		_, err := os.Stat(name)
+		if errors.Is(err, os.ErrNotExist) {
+			_, err = os.Stat(name + compressedSuffix)
+			if errors.Is(err, os.ErrNotExist) {
+				return name
+			}
+		}
+
+		rotatedAt = rotatedAt.Add(time.Millisecond)
+	}
+}
+
+func (w *RotatingFileWriter) backupPrefixAndExt() (string, string) {
+	ext := filepath.Ext(w.config.Path)
+
+	return strings.TrimSuffix(w.config.Path, ext) + "-", ext
+}
+
+// mill compresses the uncompressed backups, and removes the ones over MaxBackups or
+// older than MaxAge. Errors are ignored, as there is nowhere to report them.
+func (w *RotatingFileWriter) mill() {
+	w.millMu.Lock()
+	defer w.millMu.Unlock()
+
+	backups := w.listBackups()
+
+	// Newest first.
+	slices.SortFunc(backups, func(a, b logBackup) int {
+		return b.rotatedAt.Compare(a.rotatedAt)
+	})
+
+	cutoff := time.Time{}
+	if w.config.MaxAge > 0 {
+		cutoff = time.Now().Add(-w.config.MaxAge)
+	}
+
+	for i, backup := range backups {
+		if (w.config.MaxBackups > 0 && i >= w.config.MaxBackups) || backup.rotatedAt.Before(cutoff) {
+			_ = os.Remove(backup.path)
+
+			continue
+		}
+
+		if w.config.Compress && !strings.HasSuffix(backup.path, compressedSuffix) {
+			_ = compressFile(backup.path)
+		}
+	}
+}
+
+type logBackup struct {
+	rotatedAt time.Time
+	path      string
+}
+
+func (w *RotatingFileWriter) listBackups() []logBackup {
+	prefix, ext := w.backupPrefixAndExt()
+
+	entries, err := os.ReadDir(filepath.Dir(w.config.Path))
+	if err != nil {
+		return nil
+	}
+
+	namePrefix := filepath.Base(prefix)
+	backups := make([]logBackup, 0, len(entries))
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if entry.IsDir() || !strings.HasPrefix(name, namePrefix) {
+			continue
+		}
+
+		stamp := strings.TrimPrefix(strings.TrimSuffix(name, compressedSuffix), namePrefix)
+
+		stamp, hasExt := strings.CutSuffix(stamp, ext)
+		if !hasExt {
+			continue
+		}
+
+		rotatedAt, err := time.Parse(backupTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, logBackup{
+			rotatedAt: rotatedAt,
+			path:      filepath.Join(filepath.Dir(w.config.Path), name),
+		})
+	}
+
+	return backups
+}
+
+// compressFile replaces path with a gzip-compressed path.gz.
+func compressFile(path string) error {
+	source, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	defer source.Close() //nolint:errcheck
+
+	target, err := os.OpenFile(path+compressedSuffix, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644) //nolint:mnd
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	gzipWriter := gzip.NewWriter(target)
+
+	_, err = io.Copy(gzipWriter, source)
+	if err == nil {
+		err = gzipWriter.Close()
+	}
+
+	if closeErr := target.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = os.Remove(path + compressedSuffix)
+
+		return err //nolint:wrapcheck
+	}
+
+	return os.Remove(path) //nolint:wrapcheck
+}
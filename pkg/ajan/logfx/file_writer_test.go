@@ -0,0 +1,154 @@
+package logfx_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func listLogFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	return names
+}
+
+func TestRotatingFileWriter_Size(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	writer, err := logfx.NewRotatingFileWriter(&logfx.FileConfig{ //nolint:exhaustruct
+		Path:      filepath.Join(dir, "logs", "app.log"),
+		MaxSizeMB: 1,
+	})
+	require.NoError(t, err)
+
+	chunk := bytes.Repeat([]byte("a"), 600*1024)
+
+	for range 3 {
+		_, err = writer.Write(chunk)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, writer.Close())
+
+	names := listLogFiles(t, filepath.Join(dir, "logs"))
+	assert.Len(t, names, 3)
+	assert.Contains(t, names, "app.log")
+
+	for _, name := range names {
+		info, err := os.Stat(filepath.Join(dir, "logs", name))
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(chunk)), info.Size())
+	}
+
+	_, err = writer.Write([]byte("late"))
+	require.ErrorIs(t, err, logfx.ErrLogFileClosed)
+}
+
+func TestRotatingFileWriter_Interval(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	writer, err := logfx.NewRotatingFileWriter(&logfx.FileConfig{ //nolint:exhaustruct
+		Path:     filepath.Join(dir, "app.log"),
+		Interval: 50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	_, err = writer.Write([]byte("first\n"))
+	require.NoError(t, err)
+
+	time.Sleep(60 * time.Millisecond)
+
+	_, err = writer.Write([]byte("second\n"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	current, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "second\n", string(current))
+	assert.Len(t, listLogFiles(t, dir), 2)
+}
+
+func TestRotatingFileWriter_CompressionAndRetention(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	writer, err := logfx.NewRotatingFileWriter(&logfx.FileConfig{ //nolint:exhaustruct
+		Path:       filepath.Join(dir, "app.log"),
+		MaxBackups: 2,
+		Compress:   true,
+	})
+	require.NoError(t, err)
+
+	for _, line := range []string{"one\n", "two\n", "three\n"} {
+		_, err = writer.Write([]byte(line))
+		require.NoError(t, err)
+		require.NoError(t, writer.Rotate())
+	}
+
+	require.NoError(t, writer.Close())
+
+	backups := make([]string, 0)
+
+	for _, name := range listLogFiles(t, dir) {
+		if name != "app.log" {
+			assert.True(t, strings.HasPrefix(name, "app-") && strings.HasSuffix(name, ".log.gz"), name)
+
+			backups = append(backups, name)
+		}
+	}
+
+	// Sorted by name, which is the time of rotation.
+	require.Len(t, backups, 2)
+
+	file, err := os.Open(filepath.Join(dir, backups[1]))
+	require.NoError(t, err)
+
+	defer file.Close() //nolint:errcheck
+
+	reader, err := gzip.NewReader(file)
+	require.NoError(t, err)
+
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "three\n", string(content))
+}
+
+func TestNewLogger_File(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	logger := logfx.NewLogger(logfx.WithConfig(&logfx.Config{ //nolint:exhaustruct
+		Level: "INFO",
+		File:  logfx.FileConfig{Path: path}, //nolint:exhaustruct
+	}))
+
+	logger.Info("written to the file")
+	require.NoError(t, logger.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"msg":"written to the file"`)
+}
@@ -46,38 +46,43 @@ type Handler struct {
 
 	ScopeName string
 
+	// Levels is shared by the handler and the handlers derived from it.
+	Levels *LevelController
+
 	Subscribers []func(ctx context.Context, rec slog.Record) error
+
+	sampler  *sampler
+	redactor *redactor
+	async    *asyncQueue
 }
 
 var _ slog.Handler = (*Handler)(nil)
 
 func NewHandler(scopeName string, w io.Writer, config *Config) *Handler {
-	var initError error
-
-	var level slog.Leveler
+	// On error, the levels that failed to parse fall back to Info.
+	level, scopeLevels, initError := parseLevels(config)
 
-	level, err := ParseLevel(config.Level, false)
-	if err != nil {
-		initError = fmt.Errorf(
-			"%w (level=%q): %w",
-			ErrFailedToParseLogLevel,
-			config.Level,
-			err,
-		)
-
-		// FIXME(@eser) on error, explicitly set to zero value of slog.Level which is Info
-		level = slog.Level(0)
-	}
+	levels := NewLevelController(level, scopeLevels)
 
 	opts := &slog.HandlerOptions{
-		Level:       level,
+		Level:       levels,
 		ReplaceAttr: ReplacerGenerator(config.PrettyMode),
 		AddSource:   config.AddSource,
 	}
 
 	innerHandler := slog.NewJSONHandler(w, opts)
 
-	return &Handler{
+	sampler, err := newSampler(config)
+	if err != nil {
+		initError = errors.Join(initError, err)
+	}
+
+	redactor, err := newRedactor(config)
+	if err != nil {
+		initError = errors.Join(initError, err)
+	}
+
+	handler := &Handler{
 		InitError: initError,
 
 		InnerHandler: innerHandler,
@@ -87,7 +92,19 @@ func NewHandler(scopeName string, w io.Writer, config *Config) *Handler {
 		Subscribers: []func(ctx context.Context, rec slog.Record) error{},
 
 		ScopeName: scopeName,
+
+		Levels: levels,
+
+		sampler:  sampler,
+		redactor: redactor,
+		async:    nil,
 	}
+
+	if config.AsyncBufferSize > 0 {
+		handler.async = newAsyncQueue(config.AsyncBufferSize, handler)
+	}
+
+	return handler
 }
 
 func (h *Handler) AddSubscriber(subscriber func(ctx context.Context, rec slog.Record) error) {
@@ -95,10 +112,14 @@ func (h *Handler) AddSubscriber(subscriber func(ctx context.Context, rec slog.Re
 }
 
 func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
-	return h.InnerHandler.Enabled(ctx, level)
+	return h.Levels.Enabled(h.ScopeName, level)
 }
 
 func (h *Handler) AddAdditionalAttributes(ctx context.Context, rec *slog.Record) {
+	if attrs := AttrsFromContext(ctx); len(attrs) > 0 {
+		rec.AddAttrs(h.redactor.redactAttrs(attrs)...)
+	}
+
 	span := trace.SpanFromContext(ctx)
 
 	spanCtx := span.SpanContext()
@@ -151,46 +172,80 @@ func (h *Handler) PrettifyMessage(rec slog.Record) string {
 }
 
 func (h *Handler) Handle(ctx context.Context, rec slog.Record) error {
+	// Dropped records are neither written nor exported.
+	if !h.sampler.keep(&rec) {
+		return nil
+	}
+
+	rec = h.redactor.redact(rec)
+
 	h.AddAdditionalAttributes(ctx, &rec)
 
-	var err error
+	if h.async != nil {
+		// The record outlives the call, and the context may be canceled before it is
+		// handled.
+		if h.async.enqueue(asyncEntry{ctx: context.WithoutCancel(ctx), handler: h, rec: rec.Clone()}) {
+			return nil
+		}
+	}
 
-	if h.InnerConfig.PrettyMode {
-		out := h.PrettifyMessage(rec)
+	return h.write(ctx, rec)
+}
 
-		_, err = io.WriteString(h.InnerWriter, out)
-	} else {
-		err = h.InnerHandler.Handle(ctx, rec)
+// Flush waits until the records queued in async mode are handled.
+func (h *Handler) Flush() {
+	if h.async != nil {
+		h.async.flush()
 	}
+}
 
-	if err != nil {
-		return fmt.Errorf("%w: %w", ErrFailedToWriteLog, err)
+// Close handles the records queued in async mode and stops its goroutine. Records
+// handled afterwards are handled synchronously.
+func (h *Handler) Close() {
+	if h.async != nil {
+		h.async.close()
 	}
+}
 
-	for _, subscriber := range h.Subscribers {
-		err := subscriber(ctx, rec)
-		if err != nil {
-			return fmt.Errorf("%w: %w", ErrFailedToHandleLog, err)
-		}
+// DroppedRecords returns how many records were dropped in async mode because the
+// buffer was full.
+func (h *Handler) DroppedRecords() uint64 {
+	if h.async == nil {
+		return 0
 	}
 
-	return nil
+	return h.async.dropped.Load()
 }
 
 func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	return &Handler{
 		InitError: h.InitError,
 
-		InnerHandler: h.InnerHandler.WithAttrs(attrs),
+		InnerHandler: h.InnerHandler.WithAttrs(h.redactor.redactAttrs(attrs)),
 
 		InnerWriter: h.InnerWriter,
 		InnerConfig: h.InnerConfig,
 		Subscribers: h.Subscribers,
 
 		ScopeName: h.ScopeName,
+
+		Levels: h.Levels,
+
+		sampler:  h.sampler,
+		redactor: h.redactor,
+		async:    h.async,
 	}
 }
 
+// WithScope returns a handler whose records belong to the given scope, so they are
+// filtered by the level of the scope and exported under its name.
+func (h *Handler) WithScope(scopeName string) *Handler {
+	scoped := *h
+	scoped.ScopeName = scopeName
+
+	return &scoped
+}
+
 func (h *Handler) WithGroup(name string) slog.Handler {
 	return &Handler{
 		InitError: h.InitError,
@@ -202,9 +257,45 @@ func (h *Handler) WithGroup(name string) slog.Handler {
 		Subscribers: h.Subscribers,
 
 		ScopeName: h.ScopeName,
+
+		Levels: h.Levels,
+
+		sampler:  h.sampler,
+		redactor: h.redactor,
+		async:    h.async,
 	}
 }
 
+// write writes rec and passes it to the subscribers.
+func (h *Handler) write(ctx context.Context, rec slog.Record) error {
+	var err error
+
+	if h.InnerConfig.PrettyMode {
+		out := h.PrettifyMessage(rec)
+
+		_, err = io.WriteString(h.InnerWriter, out)
+	} else {
+		err = h.InnerHandler.Handle(ctx, rec)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToWriteLog, err)
+	}
+
+	// Subscribers that need the scope of the record, such as LogMetrics, find it in
+	// the context.
+	subscriberCtx := context.WithValue(ctx, scopeNameContextKey{}, h.ScopeName)
+
+	for _, subscriber := range h.Subscribers {
+		err := subscriber(subscriberCtx, rec)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToHandleLog, err)
+		}
+	}
+
+	return nil
+}
+
 // enableOTLPExport adds OTLP log export capability to the handler.
 func (h *Handler) enableOTLPExport(loggerProvider log.LoggerProvider) {
 	// Create OTLP log export subscriber
@@ -358,6 +449,18 @@ func ConvertSlogAttrToOtelLog(attr slog.Attr) *log.KeyValue { //nolint:cyclop,du
 
 		return &kv
 	case slog.KindAny, slog.KindGroup, slog.KindLogValuer:
+		// Lists of strings, e.g. error chains and stacks, are kept as lists
+		if strs, ok := value.Any().([]string); ok {
+			values := make([]log.Value, len(strs))
+			for i, str := range strs {
+				values[i] = log.StringValue(str)
+			}
+
+			kv := log.Slice(key, values...)
+
+			return &kv
+		}
+
 		// For complex types, convert to string
 		kv := log.String(key, value.String())
 
@@ -408,27 +511,35 @@ func (h *Handler) convertSlogRecordToOtelLog( //nolint:cyclop
 
 	// Get slog attributes
 	slogAttrs := lib.GetSlogAttrs(rec)
-	slogAttrsLen := len(slogAttrs)
 
 	// Set message body with structured attributes preserved
-	logAttrs := make([]log.KeyValue, slogAttrsLen+1)
+	logAttrs := make([]log.KeyValue, 0, len(slogAttrs)+1)
 
 	scopeName := h.ScopeName
 
-	for i, attr := range slogAttrs {
-		logAttrs[i] = *ConvertSlogAttrToOtelLog(attr)
+	for _, attr := range slogAttrs {
+		// Errors are flattened into key, key.kind, key.chain and key.stack.
+		if err, isError := attr.Value.Any().(error); attr.Value.Kind() == slog.KindAny && isError {
+			for _, errorAttr := range ErrorAttrs(attr.Key, err) {
+				logAttrs = append(logAttrs, *ConvertSlogAttrToOtelLog(errorAttr))
+			}
+
+			continue
+		}
+
+		logAttrs = append(logAttrs, *ConvertSlogAttrToOtelLog(attr))
 
 		if attr.Key == "scope_name" && attr.Value.Kind() == slog.KindString {
 			scopeName = attr.Value.String()
 		}
 	}
 
-	logRecord.AddAttributes(logAttrs[:slogAttrsLen]...)
+	logRecord.AddAttributes(logAttrs...)
 
-	logAttrs[slogAttrsLen] = log.KeyValue{
+	logAttrs = append(logAttrs, log.KeyValue{
 		Key:   "msg",
 		Value: log.StringValue(rec.Message),
-	}
+	})
 
 	logRecord.SetBody(log.MapValue(logAttrs...))
 
@@ -0,0 +1,189 @@
+package logfx
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// GlobalScope addresses the level of the scopes without a level of their own.
+const GlobalScope = ""
+
+// LevelOverride is a level set at runtime. It expires at ExpiresAt, unless that is
+// zero.
+type LevelOverride struct {
+	ExpiresAt time.Time
+	Level     slog.Level
+}
+
+func (o LevelOverride) active(now time.Time) bool {
+	return o.ExpiresAt.IsZero() || now.Before(o.ExpiresAt)
+}
+
+// LevelController holds the levels of a handler and the handlers derived from it, and
+// lets them be changed at runtime, globally or for a scope. A scope is the scope name
+// of a handler, see Logger.WithScope.
+type LevelController struct {
+	// overrides is replaced as a whole on every change, so reads need no lock.
+	overrides atomic.Pointer[map[string]LevelOverride]
+	// scopes holds the configured levels of scopes, and is replaced as a whole too.
+	scopes atomic.Pointer[map[string]slog.Level]
+	base   slog.LevelVar
+
+	mu sync.Mutex
+}
+
+var _ slog.Leveler = (*LevelController)(nil)
+
+// NewLevelController creates a LevelController with the configured global level and
+// levels of scopes. Scope names are matched in lower case.
+func NewLevelController(level slog.Level, scopeLevels map[string]slog.Level) *LevelController {
+	controller := &LevelController{} //nolint:exhaustruct
+
+	controller.Configure(level, scopeLevels)
+	controller.overrides.Store(&map[string]LevelOverride{})
+
+	return controller
+}
+
+// Configure replaces the configured global level and levels of scopes, e.g. when the
+// configuration is reloaded. Overrides set at runtime still take precedence.
+func (c *LevelController) Configure(level slog.Level, scopeLevels map[string]slog.Level) {
+	scopes := make(map[string]slog.Level, len(scopeLevels))
+
+	for scope, scopeLevel := range scopeLevels {
+		scopes[strings.ToLower(scope)] = scopeLevel
+	}
+
+	c.scopes.Store(&scopes)
+	c.base.Set(level)
+}
+
+// ApplyConfig configures the levels of Config.Level and Config.Levels. When any of
+// them is invalid, the current levels are kept.
+func (c *LevelController) ApplyConfig(config *Config) error {
+	level, scopeLevels, err := parseLevels(config)
+	if err != nil {
+		return err
+	}
+
+	c.Configure(level, scopeLevels)
+
+	return nil
+}
+
+// Level returns the global level.
+func (c *LevelController) Level() slog.Level {
+	return c.LevelFor(GlobalScope)
+}
+
+// LevelFor returns the level of a scope: its override, or else its configured level,
+// or else the global override, or else the configured global level.
+func (c *LevelController) LevelFor(scope string) slog.Level {
+	overrides := *c.overrides.Load()
+	now := time.Now()
+
+	if override, exists := overrides[scope]; exists && override.active(now) {
+		return override.Level
+	}
+
+	if level, exists := (*c.scopes.Load())[scope]; exists && scope != GlobalScope {
+		return level
+	}
+
+	if override, exists := overrides[GlobalScope]; exists && override.active(now) {
+		return override.Level
+	}
+
+	return c.base.Level()
+}
+
+func (c *LevelController) Enabled(scope string, level slog.Level) bool {
+	return level >= c.LevelFor(scope)
+}
+
+// SetLevel overrides the level of a scope, or the global level for GlobalScope, for
+// the given duration, or until it is reset when the duration is 0.
+func (c *LevelController) SetLevel(scope string, level slog.Level, duration time.Duration) {
+	override := LevelOverride{ExpiresAt: time.Time{}, Level: level}
+	if duration > 0 {
+		override.ExpiresAt = time.Now().Add(duration)
+	}
+
+	c.update(func(overrides map[string]LevelOverride) {
+		overrides[scope] = override
+	})
+}
+
+// ResetLevel removes the override of a scope, or the global override for GlobalScope,
+// so its configured level applies again.
+func (c *LevelController) ResetLevel(scope string) {
+	c.update(func(overrides map[string]LevelOverride) {
+		delete(overrides, scope)
+	})
+}
+
+// Overrides returns the overrides that have not expired, keyed by scope.
+func (c *LevelController) Overrides() map[string]LevelOverride {
+	now := time.Now()
+	overrides := make(map[string]LevelOverride)
+
+	for scope, override := range *c.overrides.Load() {
+		if override.active(now) {
+			overrides[scope] = override
+		}
+	}
+
+	return overrides
+}
+
+// update applies fn to a copy of the overrides without the expired ones, and stores it.
+func (c *LevelController) update(fn func(overrides map[string]LevelOverride)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	overrides := c.Overrides()
+	fn(overrides)
+
+	c.overrides.Store(&overrides)
+}
+
+// parseLevels parses the global level and the levels of scopes of a config. The
+// levels that fail to parse are left out, and their errors are returned together.
+func parseLevels(config *Config) (slog.Level, map[string]slog.Level, error) {
+	var errs []error
+
+	level := slog.LevelInfo
+
+	parsed, err := ParseLevel(config.Level, false)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("%w (level=%q): %w", ErrFailedToParseLogLevel, config.Level, err))
+	} else {
+		level = *parsed
+	}
+
+	scopeLevels := make(map[string]slog.Level, len(config.Levels))
+
+	for scope, scopeLevelName := range config.Levels {
+		scopeLevel, err := ParseLevel(scopeLevelName, true)
+		if err != nil {
+			errs = append(errs, fmt.Errorf(
+				"%w (scope=%q, level=%q): %w",
+				ErrFailedToParseLogLevel,
+				scope,
+				scopeLevelName,
+				err,
+			))
+
+			continue
+		}
+
+		scopeLevels[scope] = *scopeLevel
+	}
+
+	return level, scopeLevels, errors.Join(errs...)
+}
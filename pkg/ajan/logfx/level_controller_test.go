@@ -0,0 +1,151 @@
+package logfx_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelController(t *testing.T) {
+	t.Parallel()
+
+	levels := logfx.NewLevelController(slog.LevelInfo, nil)
+
+	assert.Equal(t, slog.LevelInfo, levels.Level())
+	assert.False(t, levels.Enabled("connfx", slog.LevelDebug))
+
+	levels.SetLevel("connfx", slog.LevelDebug, 0)
+	assert.True(t, levels.Enabled("connfx", slog.LevelDebug))
+	assert.False(t, levels.Enabled("httpfx", slog.LevelDebug))
+
+	levels.SetLevel(logfx.GlobalScope, slog.LevelWarn, 0)
+	assert.Equal(t, slog.LevelWarn, levels.Level())
+	assert.Equal(t, slog.LevelWarn, levels.LevelFor("httpfx"))
+	assert.Equal(t, slog.LevelDebug, levels.LevelFor("connfx"))
+
+	levels.ResetLevel(logfx.GlobalScope)
+	levels.ResetLevel("connfx")
+	assert.Equal(t, slog.LevelInfo, levels.LevelFor("connfx"))
+	assert.Empty(t, levels.Overrides())
+}
+
+func TestLevelController_Expiry(t *testing.T) {
+	t.Parallel()
+
+	levels := logfx.NewLevelController(slog.LevelInfo, nil)
+
+	levels.SetLevel("connfx", slog.LevelDebug, 20*time.Millisecond)
+	assert.Equal(t, slog.LevelDebug, levels.LevelFor("connfx"))
+	assert.Contains(t, levels.Overrides(), "connfx")
+
+	time.Sleep(30 * time.Millisecond)
+
+	assert.Equal(t, slog.LevelInfo, levels.LevelFor("connfx"))
+	assert.Empty(t, levels.Overrides())
+}
+
+func TestLogger_WithScope(t *testing.T) {
+	t.Parallel()
+
+	writer := &bytes.Buffer{}
+
+	logger := logfx.NewLogger(
+		logfx.WithWriter(writer),
+		logfx.WithConfig(&logfx.Config{Level: "INFO"}), //nolint:exhaustruct
+	)
+	connfxLogger := logger.WithScope("connfx")
+
+	logger.Levels().SetLevel("connfx", slog.LevelDebug, time.Minute)
+
+	connfxLogger.Debug("connfx debug")
+	logger.Debug("default debug")
+
+	assert.Contains(t, writer.String(), "connfx debug")
+	assert.NotContains(t, writer.String(), "default debug")
+	assert.Equal(t, "connfx", connfxLogger.InnerHandler.ScopeName)
+}
+
+func TestLevelController_ScopeLevels(t *testing.T) {
+	t.Parallel()
+
+	levels := logfx.NewLevelController(slog.LevelInfo, map[string]slog.Level{
+		"CONNFX":  slog.LevelWarn,
+		"storage": slog.LevelDebug,
+	})
+
+	assert.Equal(t, slog.LevelWarn, levels.LevelFor("connfx"))
+	assert.Equal(t, slog.LevelDebug, levels.LevelFor("storage"))
+	assert.Equal(t, slog.LevelInfo, levels.LevelFor("httpfx"))
+
+	// The global override does not apply to the configured scopes.
+	levels.SetLevel(logfx.GlobalScope, slog.LevelError, 0)
+	assert.Equal(t, slog.LevelWarn, levels.LevelFor("connfx"))
+	assert.Equal(t, slog.LevelError, levels.LevelFor("httpfx"))
+
+	levels.SetLevel("connfx", slog.LevelDebug, 0)
+	assert.Equal(t, slog.LevelDebug, levels.LevelFor("connfx"))
+
+	levels.ResetLevel("connfx")
+	assert.Equal(t, slog.LevelWarn, levels.LevelFor("connfx"))
+}
+
+func TestNewHandler_Levels(t *testing.T) {
+	t.Parallel()
+
+	writer := &bytes.Buffer{}
+
+	logger := logfx.NewLogger(
+		logfx.WithWriter(writer),
+		logfx.WithConfig(&logfx.Config{ //nolint:exhaustruct
+			Level:  "INFO",
+			Levels: map[string]string{"connfx": "warn", "STORAGE": "debug"},
+		}),
+	)
+
+	logger.WithScope("connfx").Info("connfx info")
+	logger.WithScope("storage").Debug("storage debug")
+	logger.Info("default info")
+
+	assert.NotContains(t, writer.String(), "connfx info")
+	assert.Contains(t, writer.String(), "storage debug")
+	assert.Contains(t, writer.String(), "default info")
+
+	handler := logfx.NewHandler(DefaultScopeName, &bytes.Buffer{}, &logfx.Config{ //nolint:exhaustruct
+		Level:  "INFO",
+		Levels: map[string]string{"connfx": "loud"},
+	})
+	assert.ErrorIs(t, handler.InitError, logfx.ErrFailedToParseLogLevel)
+}
+
+func TestLevelController_ApplyConfig(t *testing.T) {
+	t.Parallel()
+
+	levels := logfx.NewLevelController(slog.LevelInfo, map[string]slog.Level{"connfx": slog.LevelWarn})
+	levels.SetLevel("httpfx", slog.LevelDebug, 0)
+
+	err := levels.ApplyConfig(&logfx.Config{ //nolint:exhaustruct
+		Level:  "warn",
+		Levels: map[string]string{"Storage": "debug"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, slog.LevelWarn, levels.Level())
+	assert.Equal(t, slog.LevelWarn, levels.LevelFor("connfx"))
+	assert.Equal(t, slog.LevelDebug, levels.LevelFor("storage"))
+	// Overrides outlive the reload.
+	assert.Equal(t, slog.LevelDebug, levels.LevelFor("httpfx"))
+
+	err = levels.ApplyConfig(&logfx.Config{ //nolint:exhaustruct
+		Level:  "error",
+		Levels: map[string]string{"storage": "loud"},
+	})
+	require.ErrorIs(t, err, logfx.ErrFailedToParseLogLevel)
+
+	assert.Equal(t, slog.LevelWarn, levels.Level())
+	assert.Equal(t, slog.LevelDebug, levels.LevelFor("storage"))
+}
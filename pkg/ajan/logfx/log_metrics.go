@@ -0,0 +1,128 @@
+package logfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+var (
+	ErrFailedToBuildLogRecordsCounter = errors.New("failed to build log records counter")
+	ErrFailedToBuildLogFieldHistogram = errors.New("failed to build log field histogram")
+	ErrLoggerHasNoHandler             = errors.New("logger has no handler")
+)
+
+// LogMetrics derives metrics from log records: a counter of records per level and
+// scope, and a histogram per configured numeric field. Add its Subscribe method as a
+// subscriber of a handler.
+type LogMetrics struct {
+	RecordsTotal *CounterMetric
+	// Fields holds a histogram per numeric field, keyed by the attribute key.
+	Fields map[string]*HistogramMetric
+}
+
+type scopeNameContextKey struct{}
+
+// NewLogMetrics builds the metrics. Each field is recorded in a log_field_<field>
+// histogram when a record has a numeric attribute with that key; durations are
+// recorded in seconds.
+func NewLogMetrics(builder *MetricsBuilder, fields ...string) (*LogMetrics, error) {
+	recordsTotal, err := builder.Counter(
+		"log_records_total",
+		"Total number of log records",
+	).WithUnit("{record}").Build()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToBuildLogRecordsCounter, err)
+	}
+
+	metrics := &LogMetrics{
+		RecordsTotal: recordsTotal,
+		Fields:       make(map[string]*HistogramMetric, len(fields)),
+	}
+
+	for _, field := range fields {
+		histogram, err := builder.Histogram(
+			"log_field_"+field,
+			fmt.Sprintf("Values of the %q field of log records", field),
+		).Build()
+		if err != nil {
+			return nil, fmt.Errorf("%w (field=%q): %w", ErrFailedToBuildLogFieldHistogram, field, err)
+		}
+
+		metrics.Fields[field] = histogram
+	}
+
+	return metrics, nil
+}
+
+// Subscribe records rec. The scope of a record is its "scope_name" attribute, or else
+// the scope name of the handler that handled it.
+func (m *LogMetrics) Subscribe(ctx context.Context, rec slog.Record) error {
+	scopeName, _ := ctx.Value(scopeNameContextKey{}).(string)
+	levelAttr := slog.String("level", LevelEncoder(rec.Level))
+
+	rec.Attrs(func(attr slog.Attr) bool {
+		if attr.Key == "scope_name" && attr.Value.Kind() == slog.KindString {
+			scopeName = attr.Value.String()
+		}
+
+		histogram, exists := m.Fields[attr.Key]
+		if !exists {
+			return true
+		}
+
+		if value, numeric := numericValue(attr.Value); numeric {
+			histogram.Record(ctx, value, levelAttr)
+		}
+
+		return true
+	})
+
+	m.RecordsTotal.Inc(ctx, levelAttr, slog.String("scope", scopeName))
+
+	return nil
+}
+
+// EnableLogMetrics derives metrics from the logs of the logger and the loggers created
+// from it afterwards, with the fields of Config.MetricFields. Like other metrics,
+// they are exported once EnablePrometheus or EnableOTLP is called, so call it after.
+func (l *Logger) EnableLogMetrics() error {
+	if l.InnerHandler == nil {
+		return ErrLoggerHasNoHandler
+	}
+
+	fields := make([]string, 0)
+
+	for field := range strings.SplitSeq(l.Config.MetricFields, ",") {
+		field = strings.TrimSpace(field)
+		if field != "" {
+			fields = append(fields, field)
+		}
+	}
+
+	metrics, err := NewLogMetrics(l.NewMetricsBuilder("logfx"), fields...)
+	if err != nil {
+		return err
+	}
+
+	l.InnerHandler.AddSubscriber(metrics.Subscribe)
+
+	return nil
+}
+
+func numericValue(value slog.Value) (float64, bool) {
+	switch value.Kind() { //nolint:exhaustive
+	case slog.KindInt64:
+		return float64(value.Int64()), true
+	case slog.KindUint64:
+		return float64(value.Uint64()), true
+	case slog.KindFloat64:
+		return value.Float64(), true
+	case slog.KindDuration:
+		return value.Duration().Seconds(), true
+	default:
+		return 0, false
+	}
+}
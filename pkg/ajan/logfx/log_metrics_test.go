@@ -0,0 +1,46 @@
+package logfx_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger_EnableLogMetrics(t *testing.T) {
+	t.Parallel()
+
+	logger := logfx.NewLogger(
+		logfx.WithWriter(&bytes.Buffer{}),
+		logfx.WithConfig(&logfx.Config{ //nolint:exhaustruct
+			Level:        "INFO",
+			MetricFields: "rows, elapsed",
+		}),
+	)
+	exporter := logger.EnablePrometheus()
+
+	require.NoError(t, logger.EnableLogMetrics())
+
+	connfxLogger := logger.WithScope("connfx")
+
+	connfxLogger.Error("query failed")
+	connfxLogger.Error("query failed")
+	connfxLogger.Info("query done", slog.Int("rows", 42), slog.Duration("elapsed", 1500*time.Millisecond))
+	logger.Warn("request failed", slog.String("scope_name", "http"), slog.String("rows", "many"))
+	logger.Debug("not logged")
+
+	output, err := exporter.Gather(t.Context())
+	require.NoError(t, err)
+
+	assert.Contains(t, output, `log_records_total{level="ERROR",scope="connfx",otel_scope_name="logfx"} 2`)
+	assert.Contains(t, output, `log_records_total{level="INFO",scope="connfx",otel_scope_name="logfx"} 1`)
+	assert.Contains(t, output, `log_records_total{level="WARN",scope="http",otel_scope_name="logfx"} 1`)
+	assert.NotContains(t, output, `level="DEBUG"`)
+	assert.Contains(t, output, `log_field_rows_sum{level="INFO",otel_scope_name="logfx"} 42`)
+	assert.Contains(t, output, `log_field_rows_count{level="INFO",otel_scope_name="logfx"} 1`)
+	assert.Contains(t, output, `log_field_elapsed_sum{level="INFO",otel_scope_name="logfx"} 1.5`)
+}
@@ -2,6 +2,8 @@ package logfx
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
@@ -20,6 +22,13 @@ const (
 	DefaultScopeName = "default"
 )
 
+var ErrFailedToFlushTelemetry = errors.New("failed to flush telemetry")
+
+// telemetryFlusher is implemented by the SDK providers of OTLP, which batch exports.
+type telemetryFlusher interface {
+	ForceFlush(ctx context.Context) error
+}
+
 type OTLPConnectionResource interface {
 	GetLoggerProvider() *sdklog.LoggerProvider
 	GetMeterProvider() *sdkmetric.MeterProvider
@@ -53,6 +62,11 @@ type Logger struct {
 	InnerPropagator     propagation.TextMapPropagator
 	Writer              io.Writer
 
+	// InnerPrometheusExporter is set by EnablePrometheus.
+	InnerPrometheusExporter *PrometheusExporter
+	// InnerFileWriter is set when Config.File.Path is configured.
+	InnerFileWriter *RotatingFileWriter
+
 	ScopeName string
 }
 
@@ -74,6 +88,9 @@ func NewLogger(options ...NewLoggerOption) *Logger {
 		),
 		Writer: os.Stdout,
 
+		InnerPrometheusExporter: nil,
+		InnerFileWriter:         nil,
+
 		ScopeName: DefaultScopeName,
 	}
 
@@ -82,9 +99,25 @@ func NewLogger(options ...NewLoggerOption) *Logger {
 	}
 
 	if logger.Logger == nil {
+		var fileErr error
+
+		if logger.Config.File.Path != "" {
+			logger.InnerFileWriter, fileErr = NewRotatingFileWriter(&logger.Config.File)
+			if fileErr == nil {
+				logger.Writer = logger.InnerFileWriter
+			}
+		}
+
 		logger.InnerHandler = NewHandler(logger.ScopeName, logger.Writer, logger.Config)
 		logger.Logger = slog.New(logger.InnerHandler)
 
+		if fileErr != nil {
+			logger.Warn(
+				"an error occurred while opening the log file, logging to the writer instead",
+				slog.String("error", fileErr.Error()),
+			)
+		}
+
 		if logger.InnerHandler.InitError != nil {
 			logger.Warn(
 				"an error occurred while initializing the logger",
@@ -105,6 +138,71 @@ func (l *Logger) SetAsDefault() {
 	slog.SetDefault(l.Logger)
 }
 
+// WithScope returns a logger for a subsystem, e.g. "connfx". Its logs are filtered by
+// the level of the scope, which can be changed at runtime through Levels. Attributes
+// and subscribers added to l afterwards are not shared with it.
+func (l *Logger) WithScope(scopeName string) *Logger {
+	if l.InnerHandler == nil {
+		return l
+	}
+
+	scoped := *l
+	scoped.ScopeName = scopeName
+	scoped.InnerHandler = l.InnerHandler.WithScope(scopeName)
+	scoped.Logger = slog.New(scoped.InnerHandler)
+
+	return &scoped
+}
+
+// Levels returns the level controller of the logger, or nil when it was created from
+// a slog.Logger.
+func (l *Logger) Levels() *LevelController {
+	if l.InnerHandler == nil {
+		return nil
+	}
+
+	return l.InnerHandler.Levels
+}
+
+// Close handles the records queued in async mode and closes the log file, if there
+// is one. Logs written to the file afterwards are lost.
+func (l *Logger) Close() error {
+	if l.InnerHandler != nil {
+		l.InnerHandler.Close()
+	}
+
+	if l.InnerFileWriter == nil {
+		return nil
+	}
+
+	return l.InnerFileWriter.Close()
+}
+
+// Flush exports the logs, metrics and traces batched by the OTLP providers, e.g. before
+// the connection they export through is closed. Noop providers have nothing to flush.
+func (l *Logger) Flush(ctx context.Context) error {
+	providers := []any{l.InnerLoggerProvider, l.InnerMeterProvider, l.InnerTracerProvider}
+	errs := make([]error, 0, len(providers))
+
+	for _, provider := range providers {
+		flusher, ok := provider.(telemetryFlusher)
+		if !ok {
+			continue
+		}
+
+		err := flusher.ForceFlush(ctx)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrFailedToFlushTelemetry, errors.Join(errs...))
+	}
+
+	return nil
+}
+
 // Trace logs at [LevelTrace].
 func (l *Logger) Trace(msg string, args ...any) {
 	l.Log(context.Background(), LevelTrace, msg, args...)
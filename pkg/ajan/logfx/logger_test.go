@@ -1,11 +1,15 @@
 package logfx_test
 
 import (
+	"context"
 	"os"
 	"testing"
 
 	"github.com/eser/aya.is-services/pkg/ajan/logfx"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestRegisterLogger(t *testing.T) {
@@ -49,3 +53,31 @@ func TestRegisterLogger(t *testing.T) {
 		})
 	}
 }
+
+func TestLoggerFlush(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should export batched spans", func(t *testing.T) {
+		t.Parallel()
+
+		exporter := tracetest.NewInMemoryExporter()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+
+		t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+		logger := logfx.NewLogger()
+		logger.InnerTracerProvider = provider
+
+		_, span := logger.StartSpan(t.Context(), "batched")
+		span.End()
+
+		require.NoError(t, logger.Flush(t.Context()))
+		assert.Len(t, exporter.GetSpans(), 1)
+	})
+
+	t.Run("should do nothing for noop providers", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, logfx.NewLogger().Flush(t.Context()))
+	})
+}
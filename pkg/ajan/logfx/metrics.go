@@ -10,28 +10,31 @@ import (
 )
 
 var (
-	ErrFailedToCreateCounter   = errors.New("failed to create counter")
-	ErrFailedToCreateGauge     = errors.New("failed to create gauge")
-	ErrFailedToCreateHistogram = errors.New("failed to create histogram")
+	ErrFailedToCreateCounter       = errors.New("failed to create counter")
+	ErrFailedToCreateUpDownCounter = errors.New("failed to create up-down counter")
+	ErrFailedToCreateGauge         = errors.New("failed to create gauge")
+	ErrFailedToCreateHistogram     = errors.New("failed to create histogram")
 )
 
 // MetricsBuilder provides a fluent interface for creating and managing metrics.
 type MetricsBuilder struct {
 	meter metric.Meter
 
-	counters   map[string]metric.Int64Counter
-	gauges     map[string]metric.Int64Gauge
-	histograms map[string]metric.Float64Histogram
-	name       string
+	counters       map[string]metric.Int64Counter
+	upDownCounters map[string]metric.Int64UpDownCounter
+	gauges         map[string]metric.Int64Gauge
+	histograms     map[string]metric.Float64Histogram
+	name           string
 }
 
 func NewMetricsBuilder(meterProvider metric.MeterProvider, name string) *MetricsBuilder {
 	return &MetricsBuilder{
 		meter: meterProvider.Meter(name),
 
-		counters:   make(map[string]metric.Int64Counter),
-		gauges:     make(map[string]metric.Int64Gauge),
-		histograms: make(map[string]metric.Float64Histogram),
+		counters:       make(map[string]metric.Int64Counter),
+		upDownCounters: make(map[string]metric.Int64UpDownCounter),
+		gauges:         make(map[string]metric.Int64Gauge),
+		histograms:     make(map[string]metric.Float64Histogram),
 
 		name: name,
 	}
@@ -51,6 +54,17 @@ func (mb *MetricsBuilder) Counter(name, description string) *CounterBuilder {
 	}
 }
 
+// UpDownCounter creates a new counter metric that can also decrease, such as the
+// number of requests in flight.
+func (mb *MetricsBuilder) UpDownCounter(name, description string) *UpDownCounterBuilder {
+	return &UpDownCounterBuilder{
+		builder:     mb,
+		name:        name,
+		description: description,
+		unit:        "1", // default unit
+	}
+}
+
 // Gauge creates a new gauge metric.
 func (mb *MetricsBuilder) Gauge(name, description string) *GaugeBuilder {
 	return &GaugeBuilder{
@@ -103,6 +117,37 @@ func (cb *CounterBuilder) Build() (*CounterMetric, error) {
 	return &CounterMetric{counter: counter}, nil
 }
 
+// UpDownCounterBuilder provides a fluent interface for building up-down counter metrics.
+type UpDownCounterBuilder struct {
+	builder     *MetricsBuilder
+	name        string
+	description string
+	unit        string
+}
+
+// WithUnit sets the unit for the up-down counter.
+func (ub *UpDownCounterBuilder) WithUnit(unit string) *UpDownCounterBuilder {
+	ub.unit = unit
+
+	return ub
+}
+
+// Build creates the up-down counter metric and returns an UpDownCounterMetric wrapper.
+func (ub *UpDownCounterBuilder) Build() (*UpDownCounterMetric, error) {
+	counter, err := ub.builder.meter.Int64UpDownCounter(
+		ub.name,
+		metric.WithDescription(ub.description),
+		metric.WithUnit(ub.unit),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w (ub_name=%q): %w", ErrFailedToCreateUpDownCounter, ub.name, err)
+	}
+
+	ub.builder.upDownCounters[ub.name] = counter
+
+	return &UpDownCounterMetric{counter: counter}, nil
+}
+
 // GaugeBuilder provides a fluent interface for building gauge metrics.
 type GaugeBuilder struct {
 	builder     *MetricsBuilder
@@ -202,6 +247,26 @@ func (cm *CounterMetric) Inc(ctx context.Context, attrs ...any) {
 	cm.Add(ctx, 1, attrs...)
 }
 
+// UpDownCounterMetric wraps an up-down counter with convenient methods.
+type UpDownCounterMetric struct {
+	counter metric.Int64UpDownCounter
+}
+
+// Add changes the counter by the given value with optional attributes.
+func (um *UpDownCounterMetric) Add(ctx context.Context, value int64, attrs ...any) {
+	um.counter.Add(ctx, value, metric.WithAttributes(ConvertSlogAttrsToOtelAttr(attrs)...))
+}
+
+// Inc increments the counter by 1 with optional attributes.
+func (um *UpDownCounterMetric) Inc(ctx context.Context, attrs ...any) {
+	um.Add(ctx, 1, attrs...)
+}
+
+// Dec decrements the counter by 1 with optional attributes.
+func (um *UpDownCounterMetric) Dec(ctx context.Context, attrs ...any) {
+	um.Add(ctx, -1, attrs...)
+}
+
 // GaugeMetric wraps a gauge with convenient methods.
 type GaugeMetric struct {
 	gauge metric.Int64Gauge
@@ -0,0 +1,296 @@
+package logfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// PrometheusContentType is the content type of the Prometheus text exposition format.
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var ErrFailedToCollectMetrics = errors.New("failed to collect metrics")
+
+// PrometheusExporter collects the metrics of a meter provider on demand and writes
+// them in the Prometheus text exposition format. Serve it on a scrape endpoint such
+// as /metrics.
+type PrometheusExporter struct {
+	reader *sdkmetric.ManualReader
+}
+
+// prometheusFamily is a metric with all of its samples, as Prometheus expects the
+// samples of a metric to be grouped together.
+type prometheusFamily struct {
+	name        string
+	description string
+	kind        string
+	samples     []string
+}
+
+// NewPrometheusExporter creates an exporter and the reader to register with a meter
+// provider.
+func NewPrometheusExporter() *PrometheusExporter {
+	return &PrometheusExporter{
+		reader: sdkmetric.NewManualReader(),
+	}
+}
+
+// Reader returns the reader to pass to sdkmetric.WithReader.
+func (e *PrometheusExporter) Reader() sdkmetric.Reader { //nolint:ireturn
+	return e.reader
+}
+
+// ServeHTTP writes the current values of the metrics.
+func (e *PrometheusExporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	exposition, err := e.Gather(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", PrometheusContentType)
+	w.Header().Set("Cache-Control", "no-store")
+
+	_, _ = w.Write([]byte(exposition))
+}
+
+// Gather collects the metrics and returns them in the Prometheus text exposition
+// format.
+func (e *PrometheusExporter) Gather(ctx context.Context) (string, error) {
+	var resourceMetrics metricdata.ResourceMetrics
+
+	if err := e.reader.Collect(ctx, &resourceMetrics); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrFailedToCollectMetrics, err)
+	}
+
+	families := make(map[string]*prometheusFamily)
+
+	for _, scopeMetrics := range resourceMetrics.ScopeMetrics {
+		scopeLabel := "otel_scope_name=\"" + escapePrometheusLabelValue(scopeMetrics.Scope.Name) + "\""
+
+		for _, m := range scopeMetrics.Metrics {
+			addPrometheusSamples(families, m, scopeLabel)
+		}
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	var builder strings.Builder
+
+	for _, name := range names {
+		family := families[name]
+
+		builder.WriteString("# HELP " + family.name + " " + escapePrometheusHelp(family.description) + "\n")
+		builder.WriteString("# TYPE " + family.name + " " + family.kind + "\n")
+
+		for _, sample := range family.samples {
+			builder.WriteString(sample + "\n")
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// EnablePrometheus collects the metrics of the logger for a Prometheus scrape
+// endpoint, and returns the exporter to serve. Metrics builders created before the
+// call are not exported. OTLP metrics are exported instead when EnableOTLP is called
+// afterwards.
+func (l *Logger) EnablePrometheus() *PrometheusExporter {
+	exporter := NewPrometheusExporter()
+
+	l.InnerMeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(exporter.Reader()))
+	l.InnerPrometheusExporter = exporter
+
+	return exporter
+}
+
+func addPrometheusSamples(families map[string]*prometheusFamily, m metricdata.Metrics, scopeLabel string) {
+	name := sanitizePrometheusName(m.Name)
+
+	switch data := m.Data.(type) {
+	case metricdata.Sum[int64]:
+		name, kind := prometheusSumKind(name, data.IsMonotonic)
+		family := prometheusFamilyFor(families, name, m.Description, kind)
+
+		for _, dp := range data.DataPoints {
+			family.samples = append(family.samples, prometheusSample(name, scopeLabel, dp.Attributes, "", float64(dp.Value)))
+		}
+	case metricdata.Sum[float64]:
+		name, kind := prometheusSumKind(name, data.IsMonotonic)
+		family := prometheusFamilyFor(families, name, m.Description, kind)
+
+		for _, dp := range data.DataPoints {
+			family.samples = append(family.samples, prometheusSample(name, scopeLabel, dp.Attributes, "", dp.Value))
+		}
+	case metricdata.Gauge[int64]:
+		family := prometheusFamilyFor(families, name, m.Description, "gauge")
+
+		for _, dp := range data.DataPoints {
+			family.samples = append(family.samples, prometheusSample(name, scopeLabel, dp.Attributes, "", float64(dp.Value)))
+		}
+	case metricdata.Gauge[float64]:
+		family := prometheusFamilyFor(families, name, m.Description, "gauge")
+
+		for _, dp := range data.DataPoints {
+			family.samples = append(family.samples, prometheusSample(name, scopeLabel, dp.Attributes, "", dp.Value))
+		}
+	case metricdata.Histogram[int64]:
+		family := prometheusFamilyFor(families, name, m.Description, "histogram")
+
+		for _, dp := range data.DataPoints {
+			family.samples = append(family.samples, prometheusHistogramSamples(
+				name, scopeLabel, dp.Attributes, dp.Bounds, dp.BucketCounts, dp.Count, float64(dp.Sum),
+			)...)
+		}
+	case metricdata.Histogram[float64]:
+		family := prometheusFamilyFor(families, name, m.Description, "histogram")
+
+		for _, dp := range data.DataPoints {
+			family.samples = append(family.samples, prometheusHistogramSamples(
+				name, scopeLabel, dp.Attributes, dp.Bounds, dp.BucketCounts, dp.Count, dp.Sum,
+			)...)
+		}
+	}
+}
+
+// prometheusSumKind maps monotonic sums to counters, whose names end with _total, and
+// others to gauges.
+func prometheusSumKind(name string, monotonic bool) (string, string) {
+	if !monotonic {
+		return name, "gauge"
+	}
+
+	if !strings.HasSuffix(name, "_total") {
+		name += "_total"
+	}
+
+	return name, "counter"
+}
+
+func prometheusFamilyFor(
+	families map[string]*prometheusFamily,
+	name string,
+	description string,
+	kind string,
+) *prometheusFamily {
+	family, ok := families[name]
+	if !ok {
+		family = &prometheusFamily{
+			name:        name,
+			description: description,
+			kind:        kind,
+			samples:     nil,
+		}
+		families[name] = family
+	}
+
+	return family
+}
+
+func prometheusHistogramSamples(
+	name string,
+	scopeLabel string,
+	attrs attribute.Set,
+	bounds []float64,
+	bucketCounts []uint64,
+	count uint64,
+	sum float64,
+) []string {
+	samples := make([]string, 0, len(bounds)+3)
+
+	var cumulative uint64
+
+	for i, bound := range bounds {
+		if i < len(bucketCounts) {
+			cumulative += bucketCounts[i]
+		}
+
+		le := "le=\"" + formatPrometheusValue(bound) + "\""
+		samples = append(samples, prometheusSample(name+"_bucket", scopeLabel, attrs, le, float64(cumulative)))
+	}
+
+	samples = append(samples,
+		prometheusSample(name+"_bucket", scopeLabel, attrs, "le=\"+Inf\"", float64(count)),
+		prometheusSample(name+"_sum", scopeLabel, attrs, "", sum),
+		prometheusSample(name+"_count", scopeLabel, attrs, "", float64(count)),
+	)
+
+	return samples
+}
+
+func prometheusSample(name string, scopeLabel string, attrs attribute.Set, extraLabel string, value float64) string {
+	labels := make([]string, 0, attrs.Len()+2) //nolint:mnd
+
+	for _, kv := range attrs.ToSlice() {
+		labels = append(labels,
+			sanitizePrometheusName(string(kv.Key))+"=\""+escapePrometheusLabelValue(kv.Value.Emit())+"\"",
+		)
+	}
+
+	labels = append(labels, scopeLabel)
+
+	if extraLabel != "" {
+		labels = append(labels, extraLabel)
+	}
+
+	return name + "{" + strings.Join(labels, ",") + "} " + formatPrometheusValue(value)
+}
+
+// sanitizePrometheusName replaces the characters Prometheus does not allow in names,
+// such as the dots of OpenTelemetry attributes, with underscores.
+func sanitizePrometheusName(name string) string {
+	var builder strings.Builder
+
+	for i, r := range name {
+		switch {
+		case r == '_' || r == ':' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+			builder.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				builder.WriteRune('_')
+			}
+
+			builder.WriteRune(r)
+		default:
+			builder.WriteRune('_')
+		}
+	}
+
+	return builder.String()
+}
+
+func escapePrometheusLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func escapePrometheusHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+func formatPrometheusValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
@@ -0,0 +1,85 @@
+package logfx_test
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusExporter_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	logger := logfx.NewLogger()
+	exporter := logger.EnablePrometheus()
+
+	assert.Same(t, exporter, logger.InnerPrometheusExporter)
+
+	builder := logger.NewMetricsBuilder("test")
+
+	requests, err := builder.Counter("requests_total", "Total \"requests\"").Build()
+	require.NoError(t, err)
+
+	inFlight, err := builder.UpDownCounter("requests_in_flight", "Requests in flight").Build()
+	require.NoError(t, err)
+
+	duration, err := builder.Histogram("request_duration_seconds", "Request duration").
+		WithBuckets(0.1, 1).
+		Build()
+	require.NoError(t, err)
+
+	ctx := t.Context()
+
+	requests.Inc(ctx, slog.String("http.method", "GET"), slog.String("http.path", "/a\"b"))
+	requests.Inc(ctx, slog.String("http.method", "GET"), slog.String("http.path", "/a\"b"))
+	inFlight.Inc(ctx)
+	inFlight.Inc(ctx)
+	inFlight.Dec(ctx)
+	duration.Record(ctx, 0.05)
+	duration.Record(ctx, 0.5)
+	duration.Record(ctx, 5)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	w := httptest.NewRecorder()
+
+	exporter.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, logfx.PrometheusContentType, w.Header().Get("Content-Type"))
+
+	body := w.Body.String()
+
+	assert.Contains(t, body, "# HELP requests_total Total \"requests\"\n# TYPE requests_total counter\n")
+	assert.Contains(t, body,
+		`requests_total{http_method="GET",http_path="/a\"b",otel_scope_name="test"} 2`+"\n")
+	assert.Contains(t, body, "# TYPE requests_in_flight gauge\n")
+	assert.Contains(t, body, `requests_in_flight{otel_scope_name="test"} 1`+"\n")
+	assert.Contains(t, body, "# TYPE request_duration_seconds histogram\n")
+	assert.Contains(t, body, `request_duration_seconds_bucket{otel_scope_name="test",le="0.1"} 1`+"\n")
+	assert.Contains(t, body, `request_duration_seconds_bucket{otel_scope_name="test",le="1"} 2`+"\n")
+	assert.Contains(t, body, `request_duration_seconds_bucket{otel_scope_name="test",le="+Inf"} 3`+"\n")
+	assert.Contains(t, body, `request_duration_seconds_sum{otel_scope_name="test"} 5.55`+"\n")
+	assert.Contains(t, body, `request_duration_seconds_count{otel_scope_name="test"} 3`+"\n")
+}
+
+func TestPrometheusExporter_CounterSuffix(t *testing.T) {
+	t.Parallel()
+
+	logger := logfx.NewLogger()
+	exporter := logger.EnablePrometheus()
+
+	counter, err := logger.NewMetricsBuilder("test").Counter("jobs.processed", "Processed jobs").Build()
+	require.NoError(t, err)
+
+	counter.Inc(t.Context())
+
+	exposition, err := exporter.Gather(t.Context())
+	require.NoError(t, err)
+
+	assert.Contains(t, exposition, "# TYPE jobs_processed_total counter\n")
+	assert.Contains(t, exposition, `jobs_processed_total{otel_scope_name="test"} 1`+"\n")
+}
@@ -0,0 +1,121 @@
+package logfx
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const RedactedValue = "[REDACTED]"
+
+var ErrInvalidRedactPattern = errors.New("invalid redact pattern")
+
+// redactor masks sensitive values before records are written or exported. The values
+// of attributes whose keys contain one of the configured keys are replaced, and the
+// matches of the configured patterns are scrubbed from messages and string values.
+type redactor struct {
+	keys     []string
+	patterns []*regexp.Regexp
+}
+
+// newRedactor returns nil when neither keys nor patterns are configured.
+func newRedactor(config *Config) (*redactor, error) {
+	keys := make([]string, 0)
+
+	for key := range strings.SplitSeq(config.RedactKeys, ",") {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	patterns := make([]*regexp.Regexp, 0, len(config.RedactPatterns))
+
+	for name, expr := range config.RedactPatterns {
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("%w (name=%q): %w", ErrInvalidRedactPattern, name, err)
+		}
+
+		patterns = append(patterns, pattern)
+	}
+
+	if len(keys) == 0 && len(patterns) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	return &redactor{
+		keys:     keys,
+		patterns: patterns,
+	}, nil
+}
+
+// redact returns a copy of rec with its sensitive values masked.
+func (r *redactor) redact(rec slog.Record) slog.Record {
+	if r == nil {
+		return rec
+	}
+
+	redacted := slog.NewRecord(rec.Time, rec.Level, r.scrub(rec.Message), rec.PC)
+
+	rec.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(r.redactAttr(attr))
+
+		return true
+	})
+
+	return redacted
+}
+
+func (r *redactor) redactAttrs(attrs []slog.Attr) []slog.Attr {
+	if r == nil {
+		return attrs
+	}
+
+	redacted := make([]slog.Attr, len(attrs))
+
+	for i, attr := range attrs {
+		redacted[i] = r.redactAttr(attr)
+	}
+
+	return redacted
+}
+
+func (r *redactor) redactAttr(attr slog.Attr) slog.Attr {
+	if r.isSensitiveKey(attr.Key) {
+		return slog.String(attr.Key, RedactedValue)
+	}
+
+	value := attr.Value.Resolve()
+
+	switch value.Kind() { //nolint:exhaustive
+	case slog.KindGroup:
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(r.redactAttrs(value.Group())...)}
+	case slog.KindString:
+		return slog.String(attr.Key, r.scrub(value.String()))
+	default:
+		return slog.Attr{Key: attr.Key, Value: value}
+	}
+}
+
+func (r *redactor) isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+
+	for _, sensitive := range r.keys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *redactor) scrub(text string) string {
+	for _, pattern := range r.patterns {
+		text = pattern.ReplaceAllString(text, RedactedValue)
+	}
+
+	return text
+}
@@ -0,0 +1,73 @@
+package logfx_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_Redaction(t *testing.T) {
+	t.Parallel()
+
+	writer := &bytes.Buffer{}
+
+	handler := logfx.NewHandler(DefaultScopeName, writer, &logfx.Config{ //nolint:exhaustruct
+		Level:          "INFO",
+		RedactKeys:     "password, token,Authorization",
+		RedactPatterns: map[string]string{"bearer": `Bearer\s+\S+`},
+	})
+	require.NoError(t, handler.InitError)
+
+	var exported slog.Record
+
+	handler.AddSubscriber(func(_ context.Context, rec slog.Record) error {
+		exported = rec
+
+		return nil
+	})
+
+	logger := slog.New(handler).With(slog.String("access_token", "abc123"))
+
+	logger.Info(
+		"calling upstream with Bearer abc123",
+		slog.String("Password", "hunter2"),
+		slog.Group("request", slog.String("authorization", "Bearer abc123"), slog.String("path", "/users")),
+		slog.String("note", "sent Bearer abc123"),
+		slog.Int("attempt", 1),
+	)
+
+	output := writer.String()
+
+	assert.NotContains(t, output, "abc123")
+	assert.NotContains(t, output, "hunter2")
+	assert.Contains(t, output, `"msg":"calling upstream with [REDACTED]"`)
+	assert.Contains(t, output, `"Password":"[REDACTED]"`)
+	assert.Contains(t, output, `"access_token":"[REDACTED]"`)
+	assert.Contains(t, output, `"path":"/users"`)
+	assert.Contains(t, output, `"note":"sent [REDACTED]"`)
+	assert.Contains(t, output, `"attempt":1`)
+
+	assert.Equal(t, "calling upstream with [REDACTED]", exported.Message)
+	exported.Attrs(func(attr slog.Attr) bool {
+		assert.NotContains(t, attr.Value.String(), "abc123")
+		assert.NotContains(t, attr.Value.String(), "hunter2")
+
+		return true
+	})
+}
+
+func TestHandler_InvalidRedactPattern(t *testing.T) {
+	t.Parallel()
+
+	handler := logfx.NewHandler(DefaultScopeName, &bytes.Buffer{}, &logfx.Config{ //nolint:exhaustruct
+		Level:          "INFO",
+		RedactPatterns: map[string]string{"broken": `(`},
+	})
+
+	assert.ErrorIs(t, handler.InitError, logfx.ErrInvalidRedactPattern)
+}
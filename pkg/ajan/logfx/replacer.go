@@ -1,10 +1,10 @@
 package logfx
 
 import (
-	"errors"
 	"fmt"
 	"log/slog"
 	"runtime"
+	"slices"
 	"strings"
 )
 
@@ -38,7 +38,7 @@ func ReplacerGenerator(prettyMode bool) func([]string, slog.Attr) slog.Attr {
 
 		if attr.Value.Kind() == slog.KindAny {
 			if v, ok := attr.Value.Any().(error); ok {
-				attr.Value = fmtErr(v)
+				attr.Value = ErrorValue(v)
 			}
 		}
 
@@ -46,19 +46,34 @@ func ReplacerGenerator(prettyMode bool) func([]string, slog.Attr) slog.Attr {
 	}
 }
 
-// fmtErr returns a slog.GroupValue with keys "msg" and "trace". If the error
-// does not implement interface { StackTrace() StackTrace }, the "trace"
-// key is omitted.
-func fmtErr(err error) slog.Value {
-	var groupValues []slog.Attr
+// ErrorValue returns a slog.GroupValue with the keys:
+//   - "msg", the message of the error,
+//   - "kind", the type of the innermost error of its chain, e.g. "*fs.PathError",
+//   - "chain", the messages of the errors it wraps, when it wraps any,
+//   - "stack", the stack trace of the innermost error that implements
+//     interface { StackTrace() StackTrace }, when one does.
+func ErrorValue(err error) slog.Value {
+	chain := errorChain(err)
+
+	groupValues := []slog.Attr{
+		slog.String("msg", err.Error()),
+		slog.String("kind", fmt.Sprintf("%T", chain[len(chain)-1])),
+	}
+
+	if len(chain) > 1 {
+		messages := make([]string, len(chain)-1)
+		for i, wrapped := range chain[1:] {
+			messages[i] = wrapped.Error()
+		}
 
-	groupValues = append(groupValues, slog.String("msg", err.Error()))
+		groupValues = append(groupValues, slog.Any("chain", messages))
+	}
 
 	// Find the trace to the location of the first errors.New,
 	// errors.Wrap, or errors.WithStack call.
 	var stackTraceable StackTracer
 
-	for err := err; err != nil; err = errors.Unwrap(err) {
+	for _, err := range chain {
 		if x, ok := err.(StackTracer); ok {
 			stackTraceable = x
 		}
@@ -66,13 +81,64 @@ func fmtErr(err error) slog.Value {
 
 	if stackTraceable != nil {
 		groupValues = append(groupValues,
-			slog.Any("trace", TraceLines(stackTraceable.StackTrace())),
+			slog.Any("stack", TraceLines(stackTraceable.StackTrace())),
 		)
 	}
 
 	return slog.GroupValue(groupValues...)
 }
 
+// ErrorAttrs flattens an error into attributes for OTLP export: key holds the
+// message, and key.kind, key.chain and key.stack hold the other keys of ErrorValue.
+func ErrorAttrs(key string, err error) []slog.Attr {
+	group := ErrorValue(err).Group()
+
+	attrs := make([]slog.Attr, len(group))
+
+	for i, attr := range group {
+		if attr.Key == "msg" {
+			attrs[i] = slog.String(key, attr.Value.String())
+
+			continue
+		}
+
+		attrs[i] = slog.Attr{Key: key + "." + attr.Key, Value: attr.Value}
+	}
+
+	return attrs
+}
+
+// maxErrorChainLength bounds the errors listed from a chain, which may be deep or,
+// with faulty Unwrap methods, endless.
+const maxErrorChainLength = 32
+
+// errorChain returns err and the errors it wraps, depth first. The errors joined by
+// errors.Join and fmt.Errorf with several %w verbs are all included.
+func errorChain(err error) []error {
+	chain := make([]error, 0, 1)
+	pending := []error{err}
+
+	for len(pending) > 0 && len(chain) < maxErrorChainLength {
+		current := pending[0]
+		pending = pending[1:]
+
+		if current == nil {
+			continue
+		}
+
+		chain = append(chain, current)
+
+		switch wrapper := current.(type) { //nolint:errorlint
+		case interface{ Unwrap() error }:
+			pending = append([]error{wrapper.Unwrap()}, pending...)
+		case interface{ Unwrap() []error }:
+			pending = append(slices.Clone(wrapper.Unwrap()), pending...)
+		}
+	}
+
+	return chain
+}
+
 func TraceLines(frames StackTrace) []string {
 	traceLines := make([]string, len(frames))
 
@@ -94,8 +94,11 @@ func TestReplacerGenerator(t *testing.T) { //nolint:funlen
 				Value: slog.AnyValue(errTest),
 			},
 			expected: slog.Attr{
-				Key:   slog.TimeKey,
-				Value: slog.GroupValue(slog.String("msg", "test error")),
+				Key: slog.TimeKey,
+				Value: slog.GroupValue(
+					slog.String("msg", "test error"),
+					slog.String("kind", "*errors.errorString"),
+				),
 			},
 		},
 		{
@@ -107,8 +110,11 @@ func TestReplacerGenerator(t *testing.T) { //nolint:funlen
 				Value: slog.AnyValue(errTest),
 			},
 			expected: slog.Attr{
-				Key:   slog.TimeKey,
-				Value: slog.GroupValue(slog.String("msg", "test error")),
+				Key: slog.TimeKey,
+				Value: slog.GroupValue(
+					slog.String("msg", "test error"),
+					slog.String("kind", "*errors.errorString"),
+				),
 			},
 		},
 		{
@@ -123,7 +129,8 @@ func TestReplacerGenerator(t *testing.T) { //nolint:funlen
 				Key: slog.TimeKey,
 				Value: slog.GroupValue(
 					slog.String("msg", "test error"),
-					slog.Any("trace", make([]string, 0)),
+					slog.String("kind", "*logfx_test.mockError"),
+					slog.Any("stack", make([]string, 0)),
 				),
 			},
 		},
@@ -0,0 +1,166 @@
+package logfx
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const DefaultBurstWindow = time.Second
+
+var ErrInvalidSamplingRate = errors.New("invalid sampling rate")
+
+// sampler decides which records are written and exported. Records of a sampled
+// level are kept 1 in N, and records with the same level and message are limited to
+// a burst per window. It is shared by a handler and the handlers derived from it.
+type sampler struct {
+	// counters holds a counter per sampled level. It is not modified after creation.
+	counters map[slog.Level]*samplingCounter
+	bursts   map[burstKey]*burstState
+
+	burstLimit  int
+	burstWindow time.Duration
+	lastSweep   time.Time
+
+	mu sync.Mutex
+}
+
+type samplingCounter struct {
+	seen atomic.Uint64
+	rate uint64
+}
+
+type burstKey struct {
+	message string
+	level   slog.Level
+}
+
+type burstState struct {
+	windowStart time.Time
+	count       int
+	suppressed  int
+}
+
+// newSampler returns nil when neither sampling nor burst limiting is configured.
+func newSampler(config *Config) (*sampler, error) {
+	counters := make(map[slog.Level]*samplingCounter, len(config.Sampling))
+
+	for levelName, rateString := range config.Sampling {
+		level, err := ParseLevel(levelName, true)
+		if err != nil {
+			return nil, fmt.Errorf("%w (level=%q): %w", ErrFailedToParseLogLevel, levelName, err)
+		}
+
+		rate, err := strconv.ParseUint(rateString, 10, 64)
+		if err != nil || rate == 0 {
+			return nil, fmt.Errorf("%w (level=%q, rate=%q)", ErrInvalidSamplingRate, levelName, rateString)
+		}
+
+		// Errors and above are always kept.
+		if *level >= LevelError || rate == 1 {
+			continue
+		}
+
+		counters[*level] = &samplingCounter{rate: rate} //nolint:exhaustruct
+	}
+
+	if len(counters) == 0 && config.BurstLimit <= 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	burstWindow := config.BurstWindow
+	if burstWindow <= 0 {
+		burstWindow = DefaultBurstWindow
+	}
+
+	return &sampler{
+		counters:    counters,
+		bursts:      make(map[burstKey]*burstState),
+		burstLimit:  config.BurstLimit,
+		burstWindow: burstWindow,
+		lastSweep:   time.Now(),
+		mu:          sync.Mutex{},
+	}, nil
+}
+
+// keep reports whether rec should be handled. When earlier records of a burst were
+// dropped, their number is added to rec as "suppressed".
+func (s *sampler) keep(rec *slog.Record) bool {
+	if s == nil {
+		return true
+	}
+
+	if counter, sampled := s.counters[rec.Level]; sampled {
+		if (counter.seen.Add(1)-1)%counter.rate != 0 {
+			return false
+		}
+	}
+
+	if s.burstLimit <= 0 {
+		return true
+	}
+
+	return s.keepBurst(rec)
+}
+
+func (s *sampler) keepBurst(rec *slog.Record) bool {
+	now := rec.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	key := burstKey{message: rec.Message, level: rec.Level}
+
+	state, exists := s.bursts[key]
+	if !exists {
+		state = &burstState{windowStart: now, count: 0, suppressed: 0}
+		s.bursts[key] = state
+	}
+
+	if now.Sub(state.windowStart) >= s.burstWindow {
+		if state.suppressed > 0 {
+			rec.AddAttrs(slog.Int("suppressed", state.suppressed))
+		}
+
+		state.windowStart = now
+		state.count = 0
+		state.suppressed = 0
+	}
+
+	state.count++
+
+	if state.count > s.burstLimit {
+		state.suppressed++
+
+		return false
+	}
+
+	return true
+}
+
+// sweep forgets messages that have not been seen for a while, so one-off messages
+// do not pile up. The caller holds s.mu.
+func (s *sampler) sweep(now time.Time) {
+	const idleWindows = 10
+
+	if now.Sub(s.lastSweep) < idleWindows*s.burstWindow {
+		return
+	}
+
+	s.lastSweep = now
+
+	for key, state := range s.bursts {
+		if now.Sub(state.windowStart) >= idleWindows*s.burstWindow {
+			delete(s.bursts, key)
+		}
+	}
+}
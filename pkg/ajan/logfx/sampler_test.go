@@ -0,0 +1,89 @@
+package logfx_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSamplingHandler(t *testing.T, config *logfx.Config) (*logfx.Handler, *bytes.Buffer) {
+	t.Helper()
+
+	writer := &bytes.Buffer{}
+
+	handler := logfx.NewHandler(DefaultScopeName, writer, config)
+	require.NoError(t, handler.InitError)
+
+	return handler, writer
+}
+
+func handleRecords(t *testing.T, handler *logfx.Handler, at time.Time, level slog.Level, message string, count int) {
+	t.Helper()
+
+	for range count {
+		require.NoError(t, handler.Handle(context.Background(), slog.NewRecord(at, level, message, 0)))
+	}
+}
+
+func TestHandler_Sampling(t *testing.T) {
+	t.Parallel()
+
+	handler, writer := newSamplingHandler(t, &logfx.Config{ //nolint:exhaustruct
+		Level:    "DEBUG",
+		Sampling: map[string]string{"debug": "10", "ERROR": "10"},
+	})
+
+	now := time.Now()
+
+	handleRecords(t, handler, now, slog.LevelDebug, "debug message", 25)
+	handleRecords(t, handler, now, slog.LevelInfo, "info message", 5)
+	handleRecords(t, handler, now, slog.LevelError, "error message", 5)
+
+	output := writer.String()
+
+	assert.Equal(t, 3, strings.Count(output, "debug message"))
+	assert.Equal(t, 5, strings.Count(output, "info message"))
+	assert.Equal(t, 5, strings.Count(output, "error message"))
+}
+
+func TestHandler_BurstLimit(t *testing.T) {
+	t.Parallel()
+
+	handler, writer := newSamplingHandler(t, &logfx.Config{ //nolint:exhaustruct
+		Level:       "INFO",
+		BurstLimit:  2,
+		BurstWindow: time.Second,
+	})
+
+	now := time.Now()
+
+	handleRecords(t, handler, now, slog.LevelWarn, "connection refused", 10)
+	handleRecords(t, handler, now, slog.LevelWarn, "other message", 1)
+
+	assert.Equal(t, 2, strings.Count(writer.String(), "connection refused"))
+	assert.Equal(t, 1, strings.Count(writer.String(), "other message"))
+
+	writer.Reset()
+
+	handleRecords(t, handler, now.Add(time.Second), slog.LevelWarn, "connection refused", 1)
+
+	assert.Contains(t, writer.String(), `"suppressed":8`)
+}
+
+func TestHandler_InvalidSamplingRate(t *testing.T) {
+	t.Parallel()
+
+	handler := logfx.NewHandler(DefaultScopeName, &bytes.Buffer{}, &logfx.Config{ //nolint:exhaustruct
+		Level:    "INFO",
+		Sampling: map[string]string{"debug": "0"},
+	})
+
+	assert.ErrorIs(t, handler.InitError, logfx.ErrInvalidSamplingRate)
+}
@@ -0,0 +1,252 @@
+package processfx
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxScheduleSearch bounds the search for the next time of a schedule, so expressions
+// that never match, such as 0 0 30 2 *, end it.
+const maxScheduleSearch = 5 * 366 * 24 * time.Hour
+
+var (
+	ErrInvalidCronExpression = errors.New("invalid cron expression")
+	ErrInvalidCronField      = errors.New("invalid cron field")
+)
+
+// CronSchedule is a parsed cron expression. Next returns the zero time for schedules
+// that never match.
+type CronSchedule struct {
+	every time.Duration
+
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+
+	// Days of the month and of the week match either one when both are restricted.
+	daysRestricted     bool
+	weekdaysRestricted bool
+}
+
+type cronField struct {
+	names map[string]int
+	min   int
+	max   int
+}
+
+var cronDescriptors = map[string]string{ //nolint:gochecknoglobals
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronFields = []cronField{ //nolint:gochecknoglobals
+	{names: nil, min: 0, max: 59},
+	{names: nil, min: 0, max: 23},
+	{names: nil, min: 1, max: 31},
+	{
+		names: map[string]int{
+			"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+			"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+		},
+		min: 1,
+		max: 12,
+	},
+	// 7 is Sunday as well.
+	{
+		names: map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6},
+		min:   0,
+		max:   7,
+	},
+}
+
+// ParseCron parses a cron expression of five fields, minute, hour, day of the month,
+// month and day of the week, e.g. */15 * * * * or 0 3 * * mon-fri. Fields are *, values,
+// ranges, steps and lists of them. The descriptors @hourly, @daily, @midnight, @weekly,
+// @monthly, @yearly and @annually, and @every with a duration, e.g. @every 90s, are
+// accepted too.
+func ParseCron(expression string) (*CronSchedule, error) {
+	expression = strings.TrimSpace(expression)
+
+	if every, isEvery := strings.CutPrefix(expression, "@every "); isEvery {
+		interval, err := time.ParseDuration(strings.TrimSpace(every))
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("%w (expression=%q): positive duration expected", ErrInvalidCronExpression, expression)
+		}
+
+		return &CronSchedule{every: interval}, nil //nolint:exhaustruct
+	}
+
+	if descriptor, isDescriptor := cronDescriptors[strings.ToLower(expression)]; isDescriptor {
+		expression = descriptor
+	}
+
+	parts := strings.Fields(expression)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf(
+			"%w (expression=%q): %d fields expected, got %d",
+			ErrInvalidCronExpression,
+			expression,
+			len(cronFields),
+			len(parts),
+		)
+	}
+
+	sets := make([]uint64, len(cronFields))
+
+	for i, part := range parts {
+		set, err := cronFields[i].parse(part)
+		if err != nil {
+			return nil, fmt.Errorf("%w (expression=%q): %w", ErrInvalidCronExpression, expression, err)
+		}
+
+		sets[i] = set
+	}
+
+	// Sunday is both 0 and 7.
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1
+	}
+
+	return &CronSchedule{
+		every: 0,
+
+		minutes:  sets[0],
+		hours:    sets[1],
+		days:     sets[2],
+		months:   sets[3],
+		weekdays: sets[4],
+
+		daysRestricted:     !strings.HasPrefix(parts[2], "*"),
+		weekdaysRestricted: !strings.HasPrefix(parts[4], "*"),
+	}, nil
+}
+
+// Next returns the first time of the schedule after from, in the location of from.
+func (s *CronSchedule) Next(from time.Time) time.Time {
+	if s.every > 0 {
+		return from.Add(s.every)
+	}
+
+	next := from.Truncate(time.Minute).Add(time.Minute)
+	limit := from.Add(maxScheduleSearch)
+
+	for next.Before(limit) {
+		switch {
+		case s.months&(1<<uint(next.Month())) == 0:
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+		case !s.matchesDay(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+		case s.hours&(1<<uint(next.Hour())) == 0:
+			// Not Truncate, as zones such as +05:30 are not whole hours off UTC.
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+		case s.minutes&(1<<uint(next.Minute())) == 0:
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+
+	return time.Time{}
+}
+
+func (s *CronSchedule) matchesDay(t time.Time) bool {
+	day := s.days&(1<<uint(t.Day())) != 0
+	weekday := s.weekdays&(1<<uint(t.Weekday())) != 0
+
+	if s.daysRestricted && s.weekdaysRestricted {
+		return day || weekday
+	}
+
+	return day && weekday
+}
+
+func (f cronField) parse(field string) (uint64, error) {
+	var set uint64
+
+	for item := range strings.SplitSeq(field, ",") {
+		low, high, step, err := f.parseItem(item)
+		if err != nil {
+			return 0, err
+		}
+
+		for value := low; value <= high; value += step {
+			set |= 1 << uint(value)
+		}
+	}
+
+	return set, nil
+}
+
+func (f cronField) parseItem(item string) (int, int, int, error) {
+	rangePart, stepPart, hasStep := strings.Cut(item, "/")
+
+	step := 1
+
+	if hasStep {
+		parsed, err := strconv.Atoi(stepPart)
+		if err != nil || parsed <= 0 {
+			return 0, 0, 0, fmt.Errorf("%w (item=%q): positive step expected", ErrInvalidCronField, item)
+		}
+
+		step = parsed
+	}
+
+	if rangePart == "*" {
+		return f.min, f.max, step, nil
+	}
+
+	lowPart, highPart, isRange := strings.Cut(rangePart, "-")
+
+	low, err := f.parseValue(lowPart)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	high := low
+
+	switch {
+	case isRange:
+		high, err = f.parseValue(highPart)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+	case hasStep:
+		// 5/15 is 5-59/15 for minutes.
+		high = f.max
+	}
+
+	if low > high {
+		return 0, 0, 0, fmt.Errorf("%w (item=%q): range is reversed", ErrInvalidCronField, item)
+	}
+
+	return low, high, step, nil
+}
+
+func (f cronField) parseValue(value string) (int, error) {
+	if named, isName := f.names[strings.ToLower(value)]; isName {
+		return named, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < f.min || parsed > f.max {
+		return 0, fmt.Errorf(
+			"%w (value=%q): %d-%d expected",
+			ErrInvalidCronField,
+			value,
+			f.min,
+			f.max,
+		)
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,76 @@
+package processfx_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/processfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron_Next(t *testing.T) {
+	t.Parallel()
+
+	// A Wednesday.
+	from := time.Date(2025, time.January, 15, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		expression string
+		expected   time.Time
+	}{
+		{"*/15 * * * *", time.Date(2025, time.January, 15, 10, 15, 0, 0, time.UTC)},
+		{"5/20 * * * *", time.Date(2025, time.January, 15, 10, 25, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2025, time.January, 16, 3, 0, 0, 0, time.UTC)},
+		{"30 9 * * mon-fri", time.Date(2025, time.January, 16, 9, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, time.January, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1,15 * *", time.Date(2025, time.January, 15, 12, 0, 0, 0, time.UTC)},
+		{"0 0 1 * sat", time.Date(2025, time.January, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", time.Date(2025, time.January, 15, 10, 9, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			t.Parallel()
+
+			schedule, err := processfx.ParseCron(tt.expression)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, schedule.Next(from))
+		})
+	}
+}
+
+func TestParseCron_NextInLocation(t *testing.T) {
+	t.Parallel()
+
+	kolkata := time.FixedZone("IST", 5*60*60+30*60)
+
+	schedule, err := processfx.ParseCron("0 * * * *")
+	require.NoError(t, err)
+
+	next := schedule.Next(time.Date(2025, time.January, 15, 10, 7, 0, 0, kolkata))
+	assert.Equal(t, time.Date(2025, time.January, 15, 11, 0, 0, 0, kolkata), next)
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, expression := range []string{
+		"* * * *",
+		"60 * * * *",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"* * * foo *",
+		"@every -1m",
+	} {
+		_, err := processfx.ParseCron(expression)
+		require.ErrorIs(t, err, processfx.ErrInvalidCronExpression, expression)
+	}
+
+	schedule, err := processfx.ParseCron("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
@@ -0,0 +1,212 @@
+package processfx
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+const DefaultLifecycleHookTimeout = 15 * time.Second
+
+var (
+	ErrLifecycleHookNameRequired  = errors.New("lifecycle hook name is required")
+	ErrDuplicateLifecycleHook     = errors.New("duplicate lifecycle hook")
+	ErrUnknownLifecycleDependency = errors.New("unknown lifecycle hook dependency")
+	ErrLifecycleDependencyCycle   = errors.New("lifecycle hook dependency cycle")
+	ErrLifecycleAlreadyStarted    = errors.New("lifecycle already started")
+	ErrLifecycleHookFailed        = errors.New("lifecycle hook failed")
+)
+
+// LifecycleHook is the start and stop logic of a module, e.g. warming a cache up or
+// registering consumers. Each function is optional.
+type LifecycleHook struct {
+	// OnStart runs in Start, after the OnStart of the hooks it depends on.
+	OnStart func(ctx context.Context) error
+	// OnReady runs in Start once every OnStart succeeded, in the same order, e.g. to
+	// start serving.
+	OnReady func(ctx context.Context) error
+	// OnStop runs in StopPhase of Shutdown, before the OnStop of the hooks it depends on.
+	// It is registered only once OnStart succeeded.
+	OnStop func(ctx context.Context) error
+
+	Name string
+	// StopPhase is the shutdown phase of OnStop; PhaseCloseConnections when empty.
+	StopPhase ShutdownPhase
+	// DependsOn names the hooks that start before this one and stop after it.
+	DependsOn []string
+	// Timeout is the deadline of each function; DefaultLifecycleHookTimeout when 0.
+	Timeout time.Duration
+}
+
+// AddLifecycleHook registers a hook that Start runs. Dependencies are resolved by
+// Start, so hooks may be added in any order.
+func (p *Process) AddLifecycleHook(hook LifecycleHook) error {
+	if hook.Name == "" {
+		return ErrLifecycleHookNameRequired
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.started {
+		return fmt.Errorf("%w (name=%q)", ErrLifecycleAlreadyStarted, hook.Name)
+	}
+
+	if slices.ContainsFunc(p.lifecycleHooks, func(existing LifecycleHook) bool {
+		return existing.Name == hook.Name
+	}) {
+		return fmt.Errorf("%w (name=%q)", ErrDuplicateLifecycleHook, hook.Name)
+	}
+
+	p.lifecycleHooks = append(p.lifecycleHooks, hook)
+
+	return nil
+}
+
+// Start runs the OnStart of the lifecycle hooks in dependency order, then their
+// OnReady, after which Status reports the process ready. It stops at the first error;
+// the OnStop of the hooks started by then still run in Shutdown.
+func (p *Process) Start() error {
+	p.mu.Lock()
+
+	if p.started {
+		p.mu.Unlock()
+
+		return ErrLifecycleAlreadyStarted
+	}
+
+	p.started = true
+	hooks := slices.Clone(p.lifecycleHooks)
+
+	p.mu.Unlock()
+
+	ordered, err := sortLifecycleHooks(hooks)
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range ordered {
+		err := p.runLifecycleHook(p.Ctx, hook, "start", hook.OnStart)
+		if err != nil {
+			return err
+		}
+
+		if hook.OnStop != nil {
+			stop := func(ctx context.Context) error {
+				return p.runLifecycleHook(ctx, hook, "stop", hook.OnStop)
+			}
+
+			// Hooks of a phase run in reverse, so dependents stop first.
+			p.OnShutdownPhase(cmp.Or(hook.StopPhase, PhaseCloseConnections), hook.Name, stop)
+		}
+	}
+
+	for _, hook := range ordered {
+		err := p.runLifecycleHook(p.Ctx, hook, "ready", hook.OnReady)
+		if err != nil {
+			return err
+		}
+	}
+
+	p.mu.Lock()
+	p.ready = true
+	p.mu.Unlock()
+
+	if p.Logger != nil {
+		p.Logger.InfoContext(p.BaseCtx, "Process started", "hooks", len(ordered))
+	}
+
+	return nil
+}
+
+// runLifecycleHook runs fn within the timeout of hook, and returns once the deadline
+// passes even when fn ignores it.
+func (p *Process) runLifecycleHook(
+	ctx context.Context,
+	hook LifecycleHook,
+	stage string,
+	fn func(ctx context.Context) error, //nolint:varnamelen
+) error {
+	if fn == nil {
+		return nil
+	}
+
+	hookCtx, cancel := context.WithTimeout(ctx, cmp.Or(hook.Timeout, DefaultLifecycleHookTimeout))
+	defer cancel()
+
+	if p.Logger != nil {
+		p.Logger.DebugContext(ctx, "Lifecycle hook running", "name", hook.Name, "stage", stage)
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- runRecovered(hookCtx, fn)
+	}()
+
+	var err error
+
+	select {
+	case err = <-done:
+	case <-hookCtx.Done():
+		err = hookCtx.Err()
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w (name=%q, stage=%q): %w", ErrLifecycleHookFailed, hook.Name, stage, err)
+	}
+
+	return nil
+}
+
+// sortLifecycleHooks orders hooks after their dependencies, and otherwise in
+// registration order.
+func sortLifecycleHooks(hooks []LifecycleHook) ([]LifecycleHook, error) {
+	names := make(map[string]bool, len(hooks))
+	for _, hook := range hooks {
+		names[hook.Name] = true
+	}
+
+	for _, hook := range hooks {
+		for _, dependency := range hook.DependsOn {
+			if !names[dependency] {
+				return nil, fmt.Errorf(
+					"%w (name=%q, dependency=%q)",
+					ErrUnknownLifecycleDependency,
+					hook.Name,
+					dependency,
+				)
+			}
+		}
+	}
+
+	ordered := make([]LifecycleHook, 0, len(hooks))
+	placed := make(map[string]bool, len(hooks))
+	remaining := slices.Clone(hooks)
+
+	for len(remaining) > 0 {
+		index := slices.IndexFunc(remaining, func(hook LifecycleHook) bool {
+			return !slices.ContainsFunc(hook.DependsOn, func(dependency string) bool {
+				return !placed[dependency]
+			})
+		})
+
+		if index < 0 {
+			cycle := make([]string, len(remaining))
+			for i, hook := range remaining {
+				cycle[i] = hook.Name
+			}
+
+			return nil, fmt.Errorf("%w (names=%q)", ErrLifecycleDependencyCycle, cycle)
+		}
+
+		ordered = append(ordered, remaining[index])
+		placed[remaining[index].Name] = true
+		remaining = slices.Delete(remaining, index, index+1)
+	}
+
+	return ordered, nil
+}
@@ -0,0 +1,148 @@
+package processfx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/processfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errWarmupFailed = errors.New("warmup failed")
+
+func (r *stepRecorder) lifecycleHook(name string, dependsOn ...string) processfx.LifecycleHook {
+	return processfx.LifecycleHook{ //nolint:exhaustruct
+		Name:      name,
+		DependsOn: dependsOn,
+		OnStart:   r.hook(name + " started"),
+		OnReady:   r.hook(name + " ready"),
+		OnStop:    r.hook(name + " stopped"),
+	}
+}
+
+func TestProcess_Start(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should run the hooks in dependency order", func(t *testing.T) {
+		t.Parallel()
+
+		process := processfx.New(t.Context(), nil)
+		recorder := &stepRecorder{} //nolint:exhaustruct
+
+		require.NoError(t, process.AddLifecycleHook(recorder.lifecycleHook("http", "cache", "connections")))
+		require.NoError(t, process.AddLifecycleHook(recorder.lifecycleHook("cache", "connections")))
+		require.NoError(t, process.AddLifecycleHook(recorder.lifecycleHook("connections")))
+
+		require.NoError(t, process.Start())
+
+		process.Cancel()
+		process.Shutdown()
+
+		assert.Equal(t, []string{
+			"connections started",
+			"cache started",
+			"http started",
+			"connections ready",
+			"cache ready",
+			"http ready",
+			"http stopped",
+			"cache stopped",
+			"connections stopped",
+		}, recorder.steps)
+	})
+
+	t.Run("should stop the hooks started before a failure", func(t *testing.T) {
+		t.Parallel()
+
+		process := processfx.New(t.Context(), nil)
+		recorder := &stepRecorder{} //nolint:exhaustruct
+
+		cache := recorder.lifecycleHook("cache", "connections")
+		cache.OnStart = func(context.Context) error { return errWarmupFailed }
+
+		require.NoError(t, process.AddLifecycleHook(recorder.lifecycleHook("connections")))
+		require.NoError(t, process.AddLifecycleHook(cache))
+		require.NoError(t, process.AddLifecycleHook(recorder.lifecycleHook("http", "cache")))
+
+		err := process.Start()
+		require.ErrorIs(t, err, processfx.ErrLifecycleHookFailed)
+		require.ErrorIs(t, err, errWarmupFailed)
+
+		process.Cancel()
+		process.Shutdown()
+
+		assert.Equal(t, []string{"connections started", "connections stopped"}, recorder.steps)
+	})
+
+	t.Run("should fail hooks past their deadline", func(t *testing.T) {
+		t.Parallel()
+
+		process := processfx.New(t.Context(), nil)
+		defer process.Cancel()
+
+		release := make(chan struct{})
+		t.Cleanup(func() { close(release) })
+
+		require.NoError(t, process.AddLifecycleHook(processfx.LifecycleHook{ //nolint:exhaustruct
+			Name:    "stuck",
+			Timeout: 20 * time.Millisecond,
+			OnStart: func(context.Context) error {
+				<-release
+
+				return nil
+			},
+		}))
+
+		err := process.Start()
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("should reject invalid hooks", func(t *testing.T) {
+		t.Parallel()
+
+		process := processfx.New(t.Context(), nil)
+		defer process.Cancel()
+
+		require.ErrorIs(
+			t,
+			process.AddLifecycleHook(processfx.LifecycleHook{}), //nolint:exhaustruct
+			processfx.ErrLifecycleHookNameRequired,
+		)
+
+		require.NoError(t, process.AddLifecycleHook(processfx.LifecycleHook{Name: "a"})) //nolint:exhaustruct
+		require.ErrorIs(
+			t,
+			process.AddLifecycleHook(processfx.LifecycleHook{Name: "a"}), //nolint:exhaustruct
+			processfx.ErrDuplicateLifecycleHook,
+		)
+	})
+
+	t.Run("should reject unknown and cyclic dependencies", func(t *testing.T) {
+		t.Parallel()
+
+		unknown := processfx.New(t.Context(), nil)
+		defer unknown.Cancel()
+
+		require.NoError(t, unknown.AddLifecycleHook(processfx.LifecycleHook{ //nolint:exhaustruct
+			Name:      "cache",
+			DependsOn: []string{"redis"},
+		}))
+		require.ErrorIs(t, unknown.Start(), processfx.ErrUnknownLifecycleDependency)
+
+		cyclic := processfx.New(t.Context(), nil)
+		defer cyclic.Cancel()
+
+		require.NoError(t, cyclic.AddLifecycleHook(processfx.LifecycleHook{ //nolint:exhaustruct
+			Name:      "a",
+			DependsOn: []string{"b"},
+		}))
+		require.NoError(t, cyclic.AddLifecycleHook(processfx.LifecycleHook{ //nolint:exhaustruct
+			Name:      "b",
+			DependsOn: []string{"a"},
+		}))
+		require.ErrorIs(t, cyclic.Start(), processfx.ErrLifecycleDependencyCycle)
+	})
+}
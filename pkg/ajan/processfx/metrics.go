@@ -0,0 +1,107 @@
+package processfx
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+)
+
+var (
+	ErrFailedToBuildJobRunsCounter = errors.New(
+		"failed to build job runs counter",
+	)
+	ErrFailedToBuildJobDurationHistogram = errors.New(
+		"failed to build job duration histogram",
+	)
+	ErrFailedToBuildWorkerMessagesCounter = errors.New(
+		"failed to build worker messages counter",
+	)
+	ErrFailedToBuildWorkerDurationHistogram = errors.New(
+		"failed to build worker duration histogram",
+	)
+)
+
+// JobMetrics holds the metrics of the jobs run by Schedule.
+type JobMetrics struct {
+	builder *logfx.MetricsBuilder
+
+	RunsTotal   *logfx.CounterMetric
+	RunDuration *logfx.HistogramMetric
+}
+
+// NewJobMetrics creates job metrics on builder.
+func NewJobMetrics(builder *logfx.MetricsBuilder) *JobMetrics {
+	return &JobMetrics{
+		builder: builder,
+
+		RunsTotal:   nil,
+		RunDuration: nil,
+	}
+}
+
+func (metrics *JobMetrics) Init() error {
+	runsTotal, err := metrics.builder.Counter(
+		"processfx_job_runs_total",
+		"Total number of scheduled job runs by status",
+	).WithUnit("{run}").Build()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToBuildJobRunsCounter, err)
+	}
+
+	metrics.RunsTotal = runsTotal
+
+	runDuration, err := metrics.builder.Histogram(
+		"processfx_job_duration_seconds",
+		"Scheduled job run duration in seconds",
+	).WithDurationBuckets().Build()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToBuildJobDurationHistogram, err)
+	}
+
+	metrics.RunDuration = runDuration
+
+	return nil
+}
+
+// WorkerMetrics holds the per-queue metrics of the worker pools.
+type WorkerMetrics struct {
+	builder *logfx.MetricsBuilder
+
+	MessagesTotal  *logfx.CounterMetric
+	HandleDuration *logfx.HistogramMetric
+}
+
+// NewWorkerMetrics creates worker metrics on builder.
+func NewWorkerMetrics(builder *logfx.MetricsBuilder) *WorkerMetrics {
+	return &WorkerMetrics{
+		builder: builder,
+
+		MessagesTotal:  nil,
+		HandleDuration: nil,
+	}
+}
+
+func (metrics *WorkerMetrics) Init() error {
+	messagesTotal, err := metrics.builder.Counter(
+		"processfx_worker_messages_total",
+		"Total number of messages handled by worker pools by queue and status",
+	).WithUnit("{message}").Build()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToBuildWorkerMessagesCounter, err)
+	}
+
+	metrics.MessagesTotal = messagesTotal
+
+	handleDuration, err := metrics.builder.Histogram(
+		"processfx_worker_duration_seconds",
+		"Worker message handling duration in seconds",
+	).WithDurationBuckets().Build()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToBuildWorkerDurationHistogram, err)
+	}
+
+	metrics.HandleDuration = handleDuration
+
+	return nil
+}
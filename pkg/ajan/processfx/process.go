@@ -2,7 +2,6 @@ package processfx
 
 import (
 	"context"
-	"errors"
 	"os"
 	"os/signal"
 	"sync"
@@ -28,7 +27,34 @@ type Process struct {
 
 	WaitGroups map[string]*sync.WaitGroup
 
+	// ShutdownTimeout bounds the whole shutdown, and PhaseTimeouts each of its phases.
 	ShutdownTimeout time.Duration
+	PhaseTimeouts   map[ShutdownPhase]time.Duration
+
+	// ForceExitTimeout starts a watchdog with Shutdown that calls Exit when the shutdown
+	// takes longer; 0 disables it.
+	ForceExitTimeout time.Duration
+	Exit             func(code int)
+
+	shutdownHooks []shutdownHook
+
+	phaseContexts     map[ShutdownPhase]context.Context
+	phaseCancels      map[ShutdownPhase]context.CancelFunc
+	goroutinePhases   map[string]ShutdownPhase
+	goroutineStatuses map[string]*GoroutineStatus
+
+	// metrics are created by the first Schedule, and workerMetrics by the first
+	// StartWorkers.
+	metrics       *JobMetrics
+	workerMetrics *WorkerMetrics
+
+	crashHandlers []CrashHandler
+
+	lifecycleHooks []LifecycleHook
+	started        bool
+	ready          bool
+
+	mu sync.Mutex
 }
 
 func New(baseCtx context.Context, logger *logfx.Logger) *Process {
@@ -53,7 +79,7 @@ func New(baseCtx context.Context, logger *logfx.Logger) *Process {
 		cancel()
 	}()
 
-	return &Process{
+	process := &Process{
 		BaseCtx: baseCtx,
 		Logger:  logger,
 
@@ -62,35 +88,66 @@ func New(baseCtx context.Context, logger *logfx.Logger) *Process {
 
 		Signal: sigChan,
 
-		ShutdownTimeout: DefaultShutdownTimeout,
-		WaitGroups:      map[string]*sync.WaitGroup{},
+		ShutdownTimeout:  DefaultShutdownTimeout,
+		PhaseTimeouts:    map[ShutdownPhase]time.Duration{},
+		ForceExitTimeout: 0,
+		Exit:             os.Exit,
+		WaitGroups:       map[string]*sync.WaitGroup{},
+
+		shutdownHooks: nil,
+
+		phaseContexts:     nil,
+		phaseCancels:      nil,
+		goroutinePhases:   map[string]ShutdownPhase{},
+		goroutineStatuses: map[string]*GoroutineStatus{},
+
+		metrics:       nil,
+		workerMetrics: nil,
+		crashHandlers: nil,
+
+		lifecycleHooks: nil,
+		started:        false,
+		ready:          false,
+
+		mu: sync.Mutex{},
 	}
+
+	process.newPhaseContexts()
+
+	return process
 }
 
+// StartGoroutine runs fn in a goroutine that Shutdown waits for, in the shutdown phase of
+// the options. Errors and panics are reported as crashes; the restart policy of the
+// options decides whether fn runs again.
 func (p *Process) StartGoroutine(
 	name string,
 	fn func(ctx context.Context) error, //nolint:varnamelen
+	options ...GoroutineOption,
 ) {
+	supervisor := newGoroutineSupervisor(name, options...)
+	phase, ctx := p.phaseOf(supervisor.phase)
+
 	wg := &sync.WaitGroup{}
-	p.WaitGroups[name] = wg
 	wg.Add(1)
 
+	p.mu.Lock()
+	p.WaitGroups[name] = wg
+	p.goroutinePhases[name] = phase
+	p.goroutineStatuses[name] = &GoroutineStatus{
+		ChangedAt: time.Now(),
+		LastError: nil,
+		Name:      name,
+		State:     GoroutineStateStarting,
+		Phase:     phase,
+		Restarts:  0,
+	}
+	p.mu.Unlock()
+
 	go func() {
 		defer wg.Done()
 
-		if p.Logger != nil {
-			p.Logger.DebugContext(p.Ctx, "Goroutine starting", "name", name)
-		}
-
-		err := fn(p.Ctx)
-
-		if err != nil &&
-			p.BaseCtx.Err() == nil &&
-			!errors.Is(err, context.Canceled) {
-			if p.Logger != nil {
-				p.Logger.ErrorContext(p.BaseCtx, "Goroutine error", "name", name, "error", err)
-			}
-		}
+		supervisor.run(ctx, p, fn)
 
 		if p.Logger != nil {
 			p.Logger.DebugContext(p.BaseCtx, "Goroutine stopped", "name", name)
@@ -98,6 +155,16 @@ func (p *Process) StartGoroutine(
 	}()
 }
 
+// OnShutdown registers a function that Shutdown runs in PhaseCloseConnections, once all
+// managed goroutines have stopped, e.g. closing a connection registry. Hooks run in
+// reverse registration order and share what is left of the phase timeout.
+func (p *Process) OnShutdown(
+	name string,
+	fn func(ctx context.Context) error, //nolint:varnamelen
+) {
+	p.OnShutdownPhase(PhaseCloseConnections, name, fn)
+}
+
 func (p *Process) Wait() {
 	// Block until the context is cancelled or an OS signal is received.
 	<-p.Ctx.Done()
@@ -112,36 +179,37 @@ func (p *Process) Wait() {
 	}
 }
 
+// Shutdown runs the ShutdownPhases in order: it stops accepting work, drains the
+// workers, flushes the telemetry and closes the connections, each phase within its
+// timeout. When ForceExitTimeout is set, a watchdog exits the process if the shutdown
+// takes longer.
 func (p *Process) Shutdown() {
+	if p.ForceExitTimeout > 0 {
+		watchdog := time.AfterFunc(p.ForceExitTimeout, p.forceExit)
+		defer watchdog.Stop()
+	}
+
 	shutdownCtx, shutdownCancel := context.WithTimeout(p.BaseCtx, p.ShutdownTimeout)
 	defer shutdownCancel()
 
-	// Wait for all managed goroutines to finish.
-	shutdownComplete := make(chan struct{})
-	go func() {
-		for _, wg := range p.WaitGroups {
-			wg.Wait()
-		}
-
-		close(shutdownComplete)
-	}()
+	graceful := true
 
-	select {
-	case <-shutdownComplete:
-		if p.Logger != nil {
-			p.Logger.InfoContext(p.BaseCtx, "All services shut down gracefully.")
-		}
+	for _, phase := range ShutdownPhases {
+		graceful = p.runShutdownPhase(shutdownCtx, phase) && graceful
+	}
 
-	case <-shutdownCtx.Done():
-		if p.Logger != nil {
-			p.Logger.WarnContext(
-				p.BaseCtx,
-				"Graceful shutdown timed out. Some services may not have stopped.",
-			)
-		}
+	if p.Logger == nil {
+		return
 	}
 
-	if p.Logger != nil {
-		p.Logger.InfoContext(p.BaseCtx, "Process shutdown process complete.")
+	if graceful {
+		p.Logger.InfoContext(p.BaseCtx, "All services shut down gracefully.")
+	} else {
+		p.Logger.WarnContext(
+			p.BaseCtx,
+			"Graceful shutdown timed out. Some services may not have stopped.",
+		)
 	}
+
+	p.Logger.InfoContext(p.BaseCtx, "Process shutdown process complete.")
 }
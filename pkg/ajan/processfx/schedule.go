@@ -0,0 +1,302 @@
+package processfx
+
+import (
+	"cmp"
+	"context"
+	cryptorand "crypto/rand"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	JobStatusSuccess = "success"
+	JobStatusFailure = "failure"
+	JobStatusTimeout = "timeout"
+	JobStatusSkipped = "skipped"
+	JobStatusLocked  = "locked"
+)
+
+var (
+	ErrScheduleNeverRuns = errors.New("schedule never runs")
+	ErrLockFailed        = errors.New("failed to take the lock of the job")
+)
+
+// Locker holds named locks that the instances of a service share, e.g. in their
+// database.
+type Locker interface {
+	// TryLock takes the lock for owner until ttl passes or owner unlocks it, and
+	// returns false while another owner holds it.
+	TryLock(ctx context.Context, name string, owner string, ttl time.Duration) (bool, error)
+	// Unlock releases the lock, unless owner does not hold it any longer.
+	Unlock(ctx context.Context, name string, owner string) error
+}
+
+type ScheduleOption func(*scheduledJob)
+
+// WithJitter delays each run by a random duration up to jitter, so the instances of a
+// service do not run a job at the same moment.
+func WithJitter(jitter time.Duration) ScheduleOption {
+	return func(job *scheduledJob) {
+		job.jitter = jitter
+	}
+}
+
+// WithTimeout cancels the context of a run once it has run for timeout.
+func WithTimeout(timeout time.Duration) ScheduleOption {
+	return func(job *scheduledJob) {
+		job.timeout = timeout
+	}
+}
+
+// WithLock runs a job on one instance of a service at a time: each run takes the lock
+// named after the job from locker first, and is skipped while another instance holds
+// it. The lock expires after ttl in case its holder dies, so keep ttl above the timeout
+// of the job.
+func WithLock(locker Locker, ttl time.Duration) ScheduleOption {
+	return func(job *scheduledJob) {
+		job.locker = locker
+		job.lockTTL = ttl
+	}
+}
+
+// WithLocation evaluates the schedule in loc instead of the local time zone.
+func WithLocation(loc *time.Location) ScheduleOption {
+	return func(job *scheduledJob) {
+		job.location = loc
+	}
+}
+
+type scheduledJob struct {
+	schedule *CronSchedule
+	location *time.Location
+	locker   Locker
+	fn       func(ctx context.Context) error
+
+	name    string
+	jitter  time.Duration
+	timeout time.Duration
+	lockTTL time.Duration
+
+	running atomic.Bool
+}
+
+// Schedule runs fn at the times of a cron expression, e.g. */15 * * * *, until the
+// process shuts down; see ParseCron for the syntax. A run is skipped while the previous
+// one is still running, or another instance holds the lock of WithLock. Failed, timed
+// out and panicking runs are reported to the
+// handlers of OnCrash. Jobs stop in PhaseDrainWorkers of the shutdown. Runs are logged
+// and recorded as the processfx_job_runs_total and processfx_job_duration_seconds
+// metrics of the logger.
+func (p *Process) Schedule(
+	name string,
+	expression string,
+	fn func(ctx context.Context) error, //nolint:varnamelen
+	options ...ScheduleOption,
+) error {
+	schedule, err := ParseCron(expression)
+	if err != nil {
+		return fmt.Errorf("%w (name=%q)", err, name)
+	}
+
+	job := &scheduledJob{ //nolint:exhaustruct
+		schedule: schedule,
+		location: time.Local,
+		fn:       fn,
+		name:     name,
+	}
+
+	for _, option := range options {
+		option(job)
+	}
+
+	if schedule.Next(time.Now().In(job.location)).IsZero() {
+		return fmt.Errorf("%w (name=%q, expression=%q)", ErrScheduleNeverRuns, name, expression)
+	}
+
+	metrics, err := p.jobMetrics()
+	if err != nil {
+		return err
+	}
+
+	p.StartGoroutine(name, func(ctx context.Context) error {
+		job.loop(ctx, p, metrics)
+
+		return nil
+	}, InShutdownPhase(PhaseDrainWorkers))
+
+	return nil
+}
+
+func (p *Process) jobMetrics() (*JobMetrics, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metrics != nil || p.Logger == nil {
+		return p.metrics, nil
+	}
+
+	metrics := NewJobMetrics(p.Logger.NewMetricsBuilder("processfx"))
+
+	err := metrics.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	p.metrics = metrics
+
+	return metrics, nil
+}
+
+func (job *scheduledJob) loop(ctx context.Context, p *Process, metrics *JobMetrics) {
+	runs := &sync.WaitGroup{}
+	defer runs.Wait()
+
+	for {
+		next := job.schedule.Next(time.Now().In(job.location))
+		if next.IsZero() {
+			return
+		}
+
+		delay := time.Until(next)
+		if job.jitter > 0 {
+			delay += rand.N(job.jitter) //nolint:gosec
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return
+		case <-timer.C:
+		}
+
+		if !job.running.CompareAndSwap(false, true) {
+			job.report(ctx, p, metrics, JobStatusSkipped, 0, nil)
+
+			continue
+		}
+
+		runs.Add(1)
+
+		go func() {
+			defer runs.Done()
+			defer job.running.Store(false)
+
+			job.run(ctx, p, metrics)
+		}()
+	}
+}
+
+func (job *scheduledJob) run(ctx context.Context, p *Process, metrics *JobMetrics) {
+	runCtx := ctx
+
+	if job.timeout > 0 {
+		var cancel context.CancelFunc
+
+		runCtx, cancel = context.WithTimeout(ctx, job.timeout)
+		defer cancel()
+	}
+
+	if job.locker != nil {
+		locked, unlock := job.lock(ctx, p, metrics)
+		if !locked {
+			return
+		}
+
+		defer unlock()
+	}
+
+	if p.Logger != nil {
+		p.Logger.DebugContext(ctx, "Scheduled job starting", "name", job.name)
+	}
+
+	startTime := time.Now()
+	err := runRecovered(runCtx, job.fn)
+	duration := time.Since(startTime)
+
+	switch {
+	case errors.Is(runCtx.Err(), context.DeadlineExceeded):
+		job.report(ctx, p, metrics, JobStatusTimeout, duration, cmp.Or(err, runCtx.Err()))
+	case err != nil && ctx.Err() == nil:
+		job.report(ctx, p, metrics, JobStatusFailure, duration, err)
+	case err == nil:
+		job.report(ctx, p, metrics, JobStatusSuccess, duration, nil)
+	}
+}
+
+// lock takes the lock of the job for the run, and reports the run when it cannot.
+func (job *scheduledJob) lock(ctx context.Context, p *Process, metrics *JobMetrics) (bool, func()) {
+	owner := cryptorand.Text()
+
+	locked, err := job.locker.TryLock(ctx, job.name, owner, job.lockTTL)
+	if err != nil {
+		err = fmt.Errorf("%w (name=%q): %w", ErrLockFailed, job.name, err)
+		job.report(ctx, p, metrics, JobStatusFailure, 0, err)
+
+		return false, nil
+	}
+
+	if !locked {
+		job.report(ctx, p, metrics, JobStatusLocked, 0, nil)
+
+		return false, nil
+	}
+
+	return true, func() {
+		// the lock is released even when the run stops for the shutdown.
+		err := job.locker.Unlock(context.WithoutCancel(ctx), job.name, owner)
+		if err != nil && p.Logger != nil {
+			p.Logger.WarnContext(ctx, "Scheduled job lock not released", "name", job.name, "error", err)
+		}
+	}
+}
+
+func (job *scheduledJob) report(
+	ctx context.Context,
+	p *Process,
+	metrics *JobMetrics,
+	status string,
+	duration time.Duration,
+	err error,
+) {
+	if metrics != nil {
+		metrics.RunsTotal.Inc(ctx, slog.String("job", job.name), slog.String("status", status))
+
+		if status != JobStatusSkipped && status != JobStatusLocked {
+			metrics.RunDuration.RecordDuration(ctx, duration, slog.String("job", job.name))
+		}
+	}
+
+	if status == JobStatusFailure || status == JobStatusTimeout {
+		defer p.notifyCrash(job.name, err)
+	}
+
+	if p.Logger == nil {
+		return
+	}
+
+	switch status {
+	case JobStatusSkipped:
+		p.Logger.WarnContext(ctx, "Scheduled job skipped, the previous run is still running", "name", job.name)
+	case JobStatusLocked:
+		p.Logger.DebugContext(ctx, "Scheduled job skipped, another instance holds its lock", "name", job.name)
+	case JobStatusFailure, JobStatusTimeout:
+		p.Logger.ErrorContext(
+			ctx,
+			"Scheduled job failed",
+			"name", job.name,
+			"status", status,
+			"duration", duration,
+			"error", err,
+		)
+	default:
+		p.Logger.DebugContext(ctx, "Scheduled job finished", "name", job.name, "duration", duration)
+	}
+}
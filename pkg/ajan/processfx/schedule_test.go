@@ -0,0 +1,189 @@
+package processfx_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/ajan/processfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errJobFailed = errors.New("job failed")
+
+// memoryLocker holds the locks of the instances of a test in memory.
+type memoryLocker struct {
+	owners map[string]string
+	mu     sync.Mutex
+}
+
+func (l *memoryLocker) TryLock(_ context.Context, name string, owner string, _ time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.owners[name]; ok {
+		return false, nil
+	}
+
+	l.owners[name] = owner
+
+	return true, nil
+}
+
+func (l *memoryLocker) Unlock(_ context.Context, name string, owner string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.owners[name] == owner {
+		delete(l.owners, name)
+	}
+
+	return nil
+}
+
+func TestProcess_Schedule(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should run jobs until shutdown", func(t *testing.T) {
+		t.Parallel()
+
+		process := processfx.New(t.Context(), logfx.NewLogger())
+
+		var runs atomic.Int32
+
+		err := process.Schedule("counter", "@every 10ms", func(context.Context) error {
+			if runs.Add(1) == 2 {
+				return errJobFailed
+			}
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, 5*time.Millisecond)
+
+		process.Cancel()
+		process.Shutdown()
+
+		stopped := runs.Load()
+
+		time.Sleep(30 * time.Millisecond)
+		assert.Equal(t, stopped, runs.Load())
+	})
+
+	t.Run("should skip runs while the previous one is running", func(t *testing.T) {
+		t.Parallel()
+
+		process := processfx.New(t.Context(), nil)
+
+		var running, overlaps, runs atomic.Int32
+
+		err := process.Schedule("slow", "@every 5ms", func(context.Context) error {
+			if running.Add(1) > 1 {
+				overlaps.Add(1)
+			}
+
+			time.Sleep(30 * time.Millisecond)
+			running.Add(-1)
+			runs.Add(1)
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, 5*time.Millisecond)
+
+		process.Cancel()
+		process.Shutdown()
+
+		assert.Zero(t, overlaps.Load())
+	})
+
+	t.Run("should cancel runs after the timeout", func(t *testing.T) {
+		t.Parallel()
+
+		process := processfx.New(t.Context(), nil)
+
+		cancelled := make(chan error, 1)
+
+		err := process.Schedule("stuck", "@every 5ms", func(ctx context.Context) error {
+			<-ctx.Done()
+
+			select {
+			case cancelled <- ctx.Err():
+			default:
+			}
+
+			return ctx.Err()
+		}, processfx.WithTimeout(10*time.Millisecond), processfx.WithJitter(time.Millisecond))
+		require.NoError(t, err)
+
+		select {
+		case err := <-cancelled:
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+		case <-time.After(time.Second):
+			t.Fatal("run was not cancelled")
+		}
+
+		process.Cancel()
+		process.Shutdown()
+	})
+
+	t.Run("should run a locked job on one instance at a time", func(t *testing.T) {
+		t.Parallel()
+
+		locker := &memoryLocker{owners: map[string]string{}} //nolint:exhaustruct
+
+		var running, overlaps, runs atomic.Int32
+
+		job := func(context.Context) error {
+			if running.Add(1) > 1 {
+				overlaps.Add(1)
+			}
+
+			time.Sleep(20 * time.Millisecond)
+			running.Add(-1)
+			runs.Add(1)
+
+			return nil
+		}
+
+		instances := []*processfx.Process{
+			processfx.New(t.Context(), nil),
+			processfx.New(t.Context(), nil),
+		}
+
+		for _, process := range instances {
+			err := process.Schedule("publish", "@every 5ms", job, processfx.WithLock(locker, time.Minute))
+			require.NoError(t, err)
+		}
+
+		require.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, 5*time.Millisecond)
+
+		for _, process := range instances {
+			process.Cancel()
+			process.Shutdown()
+		}
+
+		assert.Zero(t, overlaps.Load())
+		assert.Empty(t, locker.owners)
+	})
+
+	t.Run("should reject invalid schedules", func(t *testing.T) {
+		t.Parallel()
+
+		process := processfx.New(t.Context(), nil)
+		defer process.Cancel()
+
+		err := process.Schedule("invalid", "*/15 * *", func(context.Context) error { return nil })
+		require.ErrorIs(t, err, processfx.ErrInvalidCronExpression)
+
+		err = process.Schedule("never", "0 0 31 feb *", func(context.Context) error { return nil })
+		require.ErrorIs(t, err, processfx.ErrScheduleNeverRuns)
+	})
+}
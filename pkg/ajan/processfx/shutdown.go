@@ -0,0 +1,198 @@
+package processfx
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ShutdownPhase is a step of Shutdown. Phases run in the order of ShutdownPhases; each
+// one cancels its goroutines, waits for them, then runs its hooks.
+type ShutdownPhase string
+
+const (
+	// PhaseStopAccepting stops taking new work, e.g. the HTTP server. Goroutines are in
+	// this phase unless started with InShutdownPhase, and their context is cancelled as
+	// soon as the shutdown begins.
+	PhaseStopAccepting ShutdownPhase = "stop-accepting"
+	// PhaseDrainWorkers lets the worker pools and scheduled jobs finish the work in hand.
+	PhaseDrainWorkers ShutdownPhase = "drain-workers"
+	// PhaseFlushTelemetry exports the buffered logs, metrics and traces.
+	PhaseFlushTelemetry ShutdownPhase = "flush-telemetry"
+	// PhaseCloseConnections closes the connections; OnShutdown hooks run in this phase.
+	PhaseCloseConnections ShutdownPhase = "close-connections"
+)
+
+// ShutdownPhases lists the phases in the order Shutdown runs them.
+var ShutdownPhases = []ShutdownPhase{ //nolint:gochecknoglobals
+	PhaseStopAccepting,
+	PhaseDrainWorkers,
+	PhaseFlushTelemetry,
+	PhaseCloseConnections,
+}
+
+type Config struct {
+	// ShutdownTimeout bounds the whole graceful shutdown.
+	ShutdownTimeout time.Duration `conf:"shutdown_timeout" default:"30s"`
+
+	// Timeouts of the shutdown phases, within ShutdownTimeout; 0 = none of its own.
+	StopAcceptingTimeout    time.Duration `conf:"stop_accepting_timeout"    default:"10s"`
+	DrainWorkersTimeout     time.Duration `conf:"drain_workers_timeout"     default:"15s"`
+	FlushTelemetryTimeout   time.Duration `conf:"flush_telemetry_timeout"   default:"5s"`
+	CloseConnectionsTimeout time.Duration `conf:"close_connections_timeout" default:"5s"`
+
+	// ForceExitTimeout exits the process when the shutdown takes longer, e.g. because a
+	// hook ignores its deadline; 0 disables the watchdog.
+	ForceExitTimeout time.Duration `conf:"force_exit_timeout" default:"45s"`
+}
+
+// InShutdownPhase moves a goroutine to a later shutdown phase, so its context is only
+// cancelled once the earlier phases are done.
+func InShutdownPhase(phase ShutdownPhase) GoroutineOption {
+	return func(supervisor *goroutineSupervisor) {
+		supervisor.phase = phase
+	}
+}
+
+// Configure applies the shutdown timeouts of config.
+func (p *Process) Configure(config *Config) {
+	p.ShutdownTimeout = config.ShutdownTimeout
+	p.ForceExitTimeout = config.ForceExitTimeout
+	p.PhaseTimeouts = map[ShutdownPhase]time.Duration{
+		PhaseStopAccepting:    config.StopAcceptingTimeout,
+		PhaseDrainWorkers:     config.DrainWorkersTimeout,
+		PhaseFlushTelemetry:   config.FlushTelemetryTimeout,
+		PhaseCloseConnections: config.CloseConnectionsTimeout,
+	}
+}
+
+// OnShutdownPhase registers a function that runs in phase of Shutdown, once the
+// goroutines of the phase have stopped. Hooks of a phase run in reverse registration
+// order and share what is left of its timeout.
+func (p *Process) OnShutdownPhase(
+	phase ShutdownPhase,
+	name string,
+	fn func(ctx context.Context) error, //nolint:varnamelen
+) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.shutdownHooks = append(p.shutdownHooks, shutdownHook{fn: fn, name: name, phase: phase})
+}
+
+type shutdownHook struct {
+	fn    func(ctx context.Context) error
+	name  string
+	phase ShutdownPhase
+}
+
+func (p *Process) newPhaseContexts() {
+	p.phaseContexts = map[ShutdownPhase]context.Context{PhaseStopAccepting: p.Ctx}
+	p.phaseCancels = map[ShutdownPhase]context.CancelFunc{PhaseStopAccepting: p.Cancel}
+
+	for _, phase := range ShutdownPhases[1:] {
+		p.phaseContexts[phase], p.phaseCancels[phase] = context.WithCancel(p.BaseCtx)
+	}
+}
+
+// phaseOf returns the known phase of a goroutine and its context.
+func (p *Process) phaseOf(phase ShutdownPhase) (ShutdownPhase, context.Context) {
+	ctx, known := p.phaseContexts[phase]
+	if !known {
+		return PhaseStopAccepting, p.Ctx
+	}
+
+	return phase, ctx
+}
+
+// runShutdownPhase reports whether the goroutines of phase stopped in time.
+func (p *Process) runShutdownPhase(shutdownCtx context.Context, phase ShutdownPhase) bool {
+	ctx := shutdownCtx
+
+	if timeout := p.PhaseTimeouts[phase]; timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(shutdownCtx, timeout)
+		defer cancel()
+	}
+
+	if p.Logger != nil {
+		p.Logger.DebugContext(p.BaseCtx, "Shutdown phase starting", "phase", phase)
+	}
+
+	p.phaseCancels[phase]()
+
+	stopped := p.waitForPhase(ctx, phase)
+	if !stopped && p.Logger != nil {
+		p.Logger.WarnContext(p.BaseCtx, "Shutdown phase timed out", "phase", phase)
+	}
+
+	p.mu.Lock()
+	hooks := slices.Clone(p.shutdownHooks)
+	p.mu.Unlock()
+
+	for _, hook := range slices.Backward(hooks) {
+		if hook.phase != phase {
+			continue
+		}
+
+		err := hook.fn(ctx)
+		if err != nil && p.Logger != nil {
+			p.Logger.ErrorContext(
+				p.BaseCtx,
+				"Shutdown hook error",
+				"phase", phase,
+				"name", hook.name,
+				"error", err,
+			)
+		}
+	}
+
+	return stopped
+}
+
+// waitForPhase waits for the goroutines of phase, and reports whether they stopped
+// before ctx is done.
+func (p *Process) waitForPhase(ctx context.Context, phase ShutdownPhase) bool {
+	p.mu.Lock()
+
+	waitGroups := make([]*sync.WaitGroup, 0)
+
+	for name, wg := range p.WaitGroups {
+		if p.goroutinePhases[name] == phase {
+			waitGroups = append(waitGroups, wg)
+		}
+	}
+
+	p.mu.Unlock()
+
+	stopped := make(chan struct{})
+
+	go func() {
+		for _, wg := range waitGroups {
+			wg.Wait()
+		}
+
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (p *Process) forceExit() {
+	if p.Logger != nil {
+		p.Logger.ErrorContext(
+			p.BaseCtx,
+			"Graceful shutdown did not complete in time, forcing exit.",
+			"timeout", p.ForceExitTimeout,
+		)
+	}
+
+	p.Exit(1)
+}
@@ -0,0 +1,148 @@
+package processfx_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/processfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stepRecorder struct {
+	steps []string
+	mu    sync.Mutex
+}
+
+func (r *stepRecorder) record(step string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.steps = append(r.steps, step)
+}
+
+func (r *stepRecorder) hook(step string) func(context.Context) error {
+	return func(context.Context) error {
+		r.record(step)
+
+		return nil
+	}
+}
+
+func TestProcess_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should run the phases in order", func(t *testing.T) {
+		t.Parallel()
+
+		process := processfx.New(t.Context(), nil)
+		recorder := &stepRecorder{} //nolint:exhaustruct
+
+		process.StartGoroutine("worker", func(ctx context.Context) error {
+			<-ctx.Done()
+			recorder.record("worker stopped")
+
+			return nil
+		}, processfx.InShutdownPhase(processfx.PhaseDrainWorkers))
+
+		process.StartGoroutine("server", func(ctx context.Context) error {
+			<-ctx.Done()
+			recorder.record("server stopped")
+
+			return nil
+		})
+
+		process.OnShutdown("connections", recorder.hook("connections closed"))
+		process.OnShutdownPhase(processfx.PhaseFlushTelemetry, "telemetry", recorder.hook("telemetry flushed"))
+		process.OnShutdownPhase(processfx.PhaseDrainWorkers, "queue", recorder.hook("queue drained"))
+
+		process.Shutdown()
+
+		assert.Equal(t, []string{
+			"server stopped",
+			"worker stopped",
+			"queue drained",
+			"telemetry flushed",
+			"connections closed",
+		}, recorder.steps)
+	})
+
+	t.Run("should move on when a phase times out", func(t *testing.T) {
+		t.Parallel()
+
+		process := processfx.New(t.Context(), nil)
+		process.Configure(&processfx.Config{ //nolint:exhaustruct
+			ShutdownTimeout:      time.Second,
+			StopAcceptingTimeout: 20 * time.Millisecond,
+		})
+
+		recorder := &stepRecorder{} //nolint:exhaustruct
+		release := make(chan struct{})
+
+		t.Cleanup(func() { close(release) })
+
+		process.StartGoroutine("stuck", func(context.Context) error {
+			<-release
+
+			return nil
+		})
+
+		process.OnShutdownPhase(processfx.PhaseStopAccepting, "deadline", func(ctx context.Context) error {
+			recorder.record("deadline passed")
+
+			return ctx.Err()
+		})
+		process.OnShutdown("connections", recorder.hook("connections closed"))
+
+		startTime := time.Now()
+
+		process.Shutdown()
+
+		assert.Less(t, time.Since(startTime), 500*time.Millisecond)
+		assert.Equal(t, []string{"deadline passed", "connections closed"}, recorder.steps)
+	})
+
+	t.Run("should force the exit when the shutdown takes too long", func(t *testing.T) {
+		t.Parallel()
+
+		process := processfx.New(t.Context(), nil)
+		process.ForceExitTimeout = 20 * time.Millisecond
+
+		var exitCode atomic.Int32
+
+		exited := make(chan struct{})
+		process.Exit = func(code int) {
+			exitCode.Store(int32(code))
+			close(exited)
+		}
+
+		process.OnShutdown("hanging", func(context.Context) error {
+			<-exited
+
+			return nil
+		})
+
+		process.Shutdown()
+
+		assert.Equal(t, int32(1), exitCode.Load())
+	})
+
+	t.Run("should not force the exit of a timely shutdown", func(t *testing.T) {
+		t.Parallel()
+
+		process := processfx.New(t.Context(), nil)
+		process.ForceExitTimeout = 20 * time.Millisecond
+
+		var exits atomic.Int32
+
+		process.Exit = func(int) { exits.Add(1) }
+
+		process.Shutdown()
+
+		time.Sleep(40 * time.Millisecond)
+		require.Zero(t, exits.Load())
+	})
+}
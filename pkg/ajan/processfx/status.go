@@ -0,0 +1,94 @@
+package processfx
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// GoroutineState is the state of a goroutine of StartGoroutine.
+type GoroutineState string
+
+const (
+	// GoroutineStateStarting is the state of a goroutine that has not run yet.
+	GoroutineStateStarting GoroutineState = "starting"
+	// GoroutineStateRunning is the state of a goroutine that runs.
+	GoroutineStateRunning GoroutineState = "running"
+	// GoroutineStateCrashed is the state of a goroutine whose last run returned an error
+	// or panicked, while it waits for a restart or after it gave up.
+	GoroutineStateCrashed GoroutineState = "crashed"
+	// GoroutineStateStopped is the state of a goroutine that returned for good.
+	GoroutineStateStopped GoroutineState = "stopped"
+)
+
+// GoroutineStatus describes a goroutine of StartGoroutine.
+type GoroutineStatus struct {
+	// ChangedAt is the time the goroutine entered State.
+	ChangedAt time.Time
+	// LastError is the error of the last crash, kept after restarts.
+	LastError error
+
+	Name     string
+	State    GoroutineState
+	Phase    ShutdownPhase
+	Restarts int
+}
+
+// ProcessStatus describes the process, e.g. for a readiness probe.
+type ProcessStatus struct {
+	Goroutines []GoroutineStatus
+
+	// Ready is false while the lifecycle hooks are starting, once the shutdown began,
+	// and while a goroutine is crashed, so a dead consumer takes the instance out of
+	// service.
+	Ready        bool
+	ShuttingDown bool
+}
+
+// Status returns the state of the goroutines, ordered by name.
+func (p *Process) Status() ProcessStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status := ProcessStatus{
+		Goroutines:   make([]GoroutineStatus, 0, len(p.goroutineStatuses)),
+		Ready:        !p.started || p.ready,
+		ShuttingDown: p.Ctx.Err() != nil,
+	}
+
+	for _, goroutine := range p.goroutineStatuses {
+		status.Goroutines = append(status.Goroutines, *goroutine)
+
+		if goroutine.State == GoroutineStateCrashed {
+			status.Ready = false
+		}
+	}
+
+	if status.ShuttingDown {
+		status.Ready = false
+	}
+
+	slices.SortFunc(status.Goroutines, func(a, b GoroutineStatus) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return status
+}
+
+func (p *Process) setGoroutineState(name string, state GoroutineState, restarts int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status, exists := p.goroutineStatuses[name]
+	if !exists {
+		return
+	}
+
+	status.State = state
+	status.Restarts = restarts
+	status.ChangedAt = time.Now()
+
+	if err != nil {
+		status.LastError = err
+	}
+}
@@ -0,0 +1,128 @@
+package processfx_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/processfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stateOf(process *processfx.Process, name string) processfx.GoroutineState {
+	for _, goroutine := range process.Status().Goroutines {
+		if goroutine.Name == name {
+			return goroutine.State
+		}
+	}
+
+	return ""
+}
+
+func TestProcess_Status(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should not be ready while a goroutine is crashed", func(t *testing.T) {
+		t.Parallel()
+
+		process := processfx.New(t.Context(), nil)
+
+		process.StartGoroutine("server", func(ctx context.Context) error {
+			<-ctx.Done()
+
+			return nil
+		})
+
+		require.Eventually(t, func() bool {
+			return stateOf(process, "server") == processfx.GoroutineStateRunning
+		}, time.Second, 5*time.Millisecond)
+
+		assert.True(t, process.Status().Ready)
+
+		process.StartGoroutine("consumer", func(context.Context) error {
+			return errConsumerFailed
+		})
+
+		require.Eventually(t, func() bool {
+			return stateOf(process, "consumer") == processfx.GoroutineStateCrashed
+		}, time.Second, 5*time.Millisecond)
+
+		status := process.Status()
+
+		assert.False(t, status.Ready)
+		require.Len(t, status.Goroutines, 2)
+		assert.Equal(t, "consumer", status.Goroutines[0].Name)
+		require.ErrorIs(t, status.Goroutines[0].LastError, errConsumerFailed)
+
+		process.Cancel()
+		process.Shutdown()
+
+		assert.Equal(t, processfx.GoroutineStateStopped, stateOf(process, "server"))
+	})
+
+	t.Run("should be ready again once a crashed goroutine restarts", func(t *testing.T) {
+		t.Parallel()
+
+		process := processfx.New(t.Context(), nil)
+
+		var runs atomic.Int32
+
+		process.StartGoroutine("consumer", func(ctx context.Context) error {
+			if runs.Add(1) == 1 {
+				return errConsumerFailed
+			}
+
+			<-ctx.Done()
+
+			return nil
+		},
+			processfx.WithRestartPolicy(processfx.RestartOnFailure),
+			processfx.WithBackoff(time.Millisecond, time.Millisecond),
+		)
+
+		require.Eventually(t, func() bool { return runs.Load() == 2 }, time.Second, 5*time.Millisecond)
+
+		status := process.Status()
+
+		assert.True(t, status.Ready)
+		assert.Equal(t, processfx.GoroutineStateRunning, status.Goroutines[0].State)
+		assert.Equal(t, 1, status.Goroutines[0].Restarts)
+		require.ErrorIs(t, status.Goroutines[0].LastError, errConsumerFailed)
+
+		process.Cancel()
+		process.Shutdown()
+	})
+
+	t.Run("should not be ready before the lifecycle hooks started or while shutting down", func(t *testing.T) {
+		t.Parallel()
+
+		process := processfx.New(t.Context(), nil)
+
+		var readyDuringStart atomic.Bool
+
+		require.NoError(t, process.AddLifecycleHook(processfx.LifecycleHook{ //nolint:exhaustruct
+			Name: "cache",
+			OnStart: func(context.Context) error {
+				readyDuringStart.Store(process.Status().Ready)
+
+				return nil
+			},
+		}))
+
+		require.NoError(t, process.Start())
+
+		assert.False(t, readyDuringStart.Load())
+		assert.True(t, process.Status().Ready)
+
+		process.Cancel()
+
+		status := process.Status()
+
+		assert.False(t, status.Ready)
+		assert.True(t, status.ShuttingDown)
+
+		process.Shutdown()
+	})
+}
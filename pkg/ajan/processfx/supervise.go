@@ -0,0 +1,200 @@
+package processfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+const (
+	DefaultRestartBackoff    = time.Second
+	DefaultMaxRestartBackoff = time.Minute
+)
+
+var ErrGoroutinePanicked = errors.New("goroutine panicked")
+
+// RestartPolicy decides whether a goroutine of StartGoroutine runs again once it has
+// returned before the process shuts down.
+type RestartPolicy string
+
+const (
+	// RestartNever lets the goroutine stop; it is the default.
+	RestartNever RestartPolicy = "never"
+	// RestartOnFailure restarts the goroutine when it returns an error or panics.
+	RestartOnFailure RestartPolicy = "on-failure"
+	// RestartAlways restarts the goroutine whenever it returns.
+	RestartAlways RestartPolicy = "always"
+)
+
+// CrashHandler is notified when a goroutine returns an error or panics, e.g. to send
+// the error to an error tracker. Panics are reported as ErrGoroutinePanicked errors
+// holding the stack.
+type CrashHandler func(ctx context.Context, name string, err error)
+
+type GoroutineOption func(*goroutineSupervisor)
+
+// WithRestartPolicy sets the restart policy of a goroutine.
+func WithRestartPolicy(policy RestartPolicy) GoroutineOption {
+	return func(supervisor *goroutineSupervisor) {
+		supervisor.policy = policy
+	}
+}
+
+// WithMaxRetries stops restarting a goroutine after maxRetries consecutive restarts.
+// Restarts are unlimited when it is 0.
+func WithMaxRetries(maxRetries int) GoroutineOption {
+	return func(supervisor *goroutineSupervisor) {
+		supervisor.maxRetries = maxRetries
+	}
+}
+
+// WithBackoff sets the delay before the first restart, which doubles with each
+// consecutive restart up to maxBackoff.
+func WithBackoff(backoff time.Duration, maxBackoff time.Duration) GoroutineOption {
+	return func(supervisor *goroutineSupervisor) {
+		supervisor.backoff = backoff
+		supervisor.maxBackoff = maxBackoff
+	}
+}
+
+// OnCrash registers a handler that is notified of the goroutines that return an error
+// or panic, including scheduled jobs.
+func (p *Process) OnCrash(handler CrashHandler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.crashHandlers = append(p.crashHandlers, handler)
+}
+
+type goroutineSupervisor struct {
+	name   string
+	policy RestartPolicy
+	phase  ShutdownPhase
+
+	maxRetries int
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+func newGoroutineSupervisor(name string, options ...GoroutineOption) *goroutineSupervisor {
+	supervisor := &goroutineSupervisor{
+		name:   name,
+		policy: RestartNever,
+		phase:  PhaseStopAccepting,
+
+		maxRetries: 0,
+		backoff:    DefaultRestartBackoff,
+		maxBackoff: DefaultMaxRestartBackoff,
+	}
+
+	for _, option := range options {
+		option(supervisor)
+	}
+
+	return supervisor
+}
+
+func (s *goroutineSupervisor) run(ctx context.Context, p *Process, fn func(ctx context.Context) error) {
+	restarts := 0
+	totalRestarts := 0
+	backoff := s.backoff
+
+	for {
+		if p.Logger != nil {
+			p.Logger.DebugContext(ctx, "Goroutine starting", "name", s.name, "restarts", restarts)
+		}
+
+		p.setGoroutineState(s.name, GoroutineStateRunning, totalRestarts, nil)
+
+		startTime := time.Now()
+		err := runRecovered(ctx, fn)
+
+		failed := err != nil && p.BaseCtx.Err() == nil && !errors.Is(err, context.Canceled)
+		if failed {
+			p.setGoroutineState(s.name, GoroutineStateCrashed, totalRestarts, err)
+			p.reportCrash(s.name, err)
+		} else {
+			p.setGoroutineState(s.name, GoroutineStateStopped, totalRestarts, nil)
+		}
+
+		// Nothing restarts once the shutdown has begun.
+		if p.Ctx.Err() != nil || ctx.Err() != nil || !s.shouldRestart(failed) {
+			return
+		}
+
+		// A goroutine that ran for a while is not crash-looping; start counting again.
+		if time.Since(startTime) >= s.maxBackoff {
+			restarts = 0
+			backoff = s.backoff
+		}
+
+		if s.maxRetries > 0 && restarts >= s.maxRetries {
+			if p.Logger != nil {
+				p.Logger.ErrorContext(p.BaseCtx, "Goroutine gave up restarting", "name", s.name, "restarts", restarts)
+			}
+
+			return
+		}
+
+		restarts++
+		totalRestarts++
+
+		if p.Logger != nil {
+			p.Logger.WarnContext(p.BaseCtx, "Goroutine restarting", "name", s.name, "restart", restarts, "backoff", backoff)
+		}
+
+		select {
+		case <-p.Ctx.Done():
+			return
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, s.maxBackoff) //nolint:mnd
+	}
+}
+
+func (s *goroutineSupervisor) shouldRestart(failed bool) bool {
+	switch s.policy {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return failed
+	case RestartNever:
+		return false
+	default:
+		return false
+	}
+}
+
+func (p *Process) reportCrash(name string, err error) {
+	if p.Logger != nil {
+		p.Logger.ErrorContext(p.BaseCtx, "Goroutine error", "name", name, "error", err)
+	}
+
+	p.notifyCrash(name, err)
+}
+
+func (p *Process) notifyCrash(name string, err error) {
+	p.mu.Lock()
+	handlers := p.crashHandlers
+	p.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(p.BaseCtx, name, err)
+	}
+}
+
+// runRecovered runs fn, and returns its panic as an ErrGoroutinePanicked error.
+func runRecovered(ctx context.Context, fn func(ctx context.Context) error) (err error) { //nolint:nonamedreturns
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("%w (panic=%v): %s", ErrGoroutinePanicked, recovered, debug.Stack())
+		}
+	}()
+
+	return fn(ctx)
+}
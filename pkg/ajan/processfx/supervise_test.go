@@ -0,0 +1,149 @@
+package processfx_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/ajan/processfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errConsumerFailed = errors.New("consumer failed")
+
+// crashRecorder collects the crashes reported to OnCrash.
+type crashRecorder struct {
+	errs []error
+	mu   sync.Mutex
+}
+
+func (r *crashRecorder) record(_ context.Context, _ string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errs = append(r.errs, err)
+}
+
+func (r *crashRecorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.errs)
+}
+
+func TestProcess_StartGoroutine_Restart(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should restart on failure until max retries", func(t *testing.T) {
+		t.Parallel()
+
+		process := processfx.New(t.Context(), logfx.NewLogger())
+		crashes := &crashRecorder{} //nolint:exhaustruct
+		process.OnCrash(crashes.record)
+
+		var runs atomic.Int32
+
+		process.StartGoroutine("consumer", func(context.Context) error {
+			runs.Add(1)
+
+			return errConsumerFailed
+		},
+			processfx.WithRestartPolicy(processfx.RestartOnFailure),
+			processfx.WithMaxRetries(2),
+			processfx.WithBackoff(time.Millisecond, 5*time.Millisecond),
+		)
+
+		require.Eventually(t, func() bool { return crashes.count() == 3 }, time.Second, 5*time.Millisecond)
+
+		// The goroutine gave up, so shutdown completes without cancelling it.
+		process.Shutdown()
+
+		assert.Equal(t, int32(3), runs.Load())
+		require.ErrorIs(t, crashes.errs[0], errConsumerFailed)
+
+		process.Cancel()
+	})
+
+	t.Run("should recover and report panics", func(t *testing.T) {
+		t.Parallel()
+
+		process := processfx.New(t.Context(), nil)
+		crashes := &crashRecorder{} //nolint:exhaustruct
+		process.OnCrash(crashes.record)
+
+		var runs atomic.Int32
+
+		process.StartGoroutine("consumer", func(ctx context.Context) error {
+			if runs.Add(1) == 1 {
+				panic("boom")
+			}
+
+			<-ctx.Done()
+
+			return ctx.Err()
+		},
+			processfx.WithRestartPolicy(processfx.RestartOnFailure),
+			processfx.WithBackoff(time.Millisecond, time.Millisecond),
+		)
+
+		require.Eventually(t, func() bool { return runs.Load() == 2 }, time.Second, 5*time.Millisecond)
+
+		process.Cancel()
+		process.Shutdown()
+
+		require.Equal(t, 1, crashes.count())
+		require.ErrorIs(t, crashes.errs[0], processfx.ErrGoroutinePanicked)
+		assert.Contains(t, crashes.errs[0].Error(), "boom")
+	})
+
+	t.Run("should restart always, but not on shutdown", func(t *testing.T) {
+		t.Parallel()
+
+		process := processfx.New(t.Context(), nil)
+
+		var runs atomic.Int32
+
+		process.StartGoroutine("poller", func(context.Context) error {
+			runs.Add(1)
+
+			return nil
+		},
+			processfx.WithRestartPolicy(processfx.RestartAlways),
+			processfx.WithBackoff(time.Millisecond, time.Millisecond),
+		)
+
+		require.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, time.Millisecond)
+
+		process.Cancel()
+		process.Shutdown()
+
+		stopped := runs.Load()
+
+		time.Sleep(10 * time.Millisecond)
+		assert.Equal(t, stopped, runs.Load())
+	})
+
+	t.Run("should not restart by default", func(t *testing.T) {
+		t.Parallel()
+
+		process := processfx.New(t.Context(), nil)
+		defer process.Cancel()
+
+		var runs atomic.Int32
+
+		process.StartGoroutine("once", func(context.Context) error {
+			runs.Add(1)
+
+			return errConsumerFailed
+		})
+
+		process.Shutdown()
+
+		assert.Equal(t, int32(1), runs.Load())
+	})
+}
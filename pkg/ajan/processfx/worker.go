@@ -0,0 +1,389 @@
+package processfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+)
+
+const (
+	// RetryCountHeader counts the retries of a message republished by a worker pool.
+	RetryCountHeader = "x-retry-count"
+	// ErrorHeader holds the error of a dead-lettered message.
+	ErrorHeader = "x-error"
+	// OriginalQueueHeader names the queue a dead-lettered message was consumed from.
+	OriginalQueueHeader = "x-original-queue"
+
+	MessageStatusAcked        = "acked"
+	MessageStatusRetried      = "retried"
+	MessageStatusDeadLettered = "dead_lettered"
+	MessageStatusRequeued     = "requeued"
+)
+
+var (
+	// ErrNonRetryable marks handler errors whose messages are dead-lettered without
+	// retries, e.g. messages that fail to decode.
+	ErrNonRetryable = errors.New("non-retryable message")
+
+	ErrWorkerConsumeStopped  = errors.New("worker consumer stopped")
+	ErrFailedToRetryMessage  = errors.New("failed to retry message")
+	ErrFailedToDeadLetter    = errors.New("failed to dead-letter message")
+	ErrFailedToAckMessage    = errors.New("failed to acknowledge message")
+	ErrFailedToNackMessage   = errors.New("failed to negatively acknowledge message")
+	ErrWorkerHandlerTimedOut = errors.New("worker handler timed out")
+)
+
+// WorkerHandler processes a message of a worker pool. The message is acknowledged
+// when it returns nil, and retried or dead-lettered otherwise.
+type WorkerHandler func(ctx context.Context, message *connfx.Message) error
+
+type WorkerConfig struct {
+	// ConsumerGroup shares the messages between the instances of a service; messages
+	// are consumed without a group when it is empty.
+	ConsumerGroup string `conf:"consumer_group"`
+	// ConsumerName identifies the instance in the group; defaults to the host name and
+	// the process id.
+	ConsumerName string `conf:"consumer_name"`
+	// DeadLetterQueue receives the messages that failed every retry. When it is empty
+	// they are rejected, so the dead-lettering of the queue, if any, applies.
+	DeadLetterQueue string `conf:"dead_letter_queue"`
+
+	Workers    int           `conf:"workers"     default:"1"`
+	MaxRetries int           `conf:"max_retries" default:"3"`
+	RetryDelay time.Duration `conf:"retry_delay" default:"1s"`
+	// HandlerTimeout cancels the context of a handler that runs for longer (0 = none).
+	HandlerTimeout time.Duration `conf:"handler_timeout" default:"30s"`
+}
+
+// WorkerPool binds workers to a queue. Each message is handled by one worker, and
+// failed messages are republished after a delay that doubles with each retry, until
+// they are dead-lettered.
+type WorkerPool struct {
+	queue   connfx.QueueRepository
+	handler WorkerHandler
+	config  *WorkerConfig
+	logger  *logfx.Logger
+	metrics *WorkerMetrics
+
+	queueName string
+
+	// retries tracks the delayed republishes, which Run waits for.
+	retries sync.WaitGroup
+}
+
+// NewWorkerPool creates a pool consuming queueName of queue. The logger and the
+// metrics may be nil.
+func NewWorkerPool(
+	queue connfx.QueueRepository,
+	queueName string,
+	config *WorkerConfig,
+	handler WorkerHandler,
+	logger *logfx.Logger,
+	metrics *WorkerMetrics,
+) *WorkerPool {
+	return &WorkerPool{
+		queue:   queue,
+		handler: handler,
+		config:  config,
+		logger:  logger,
+		metrics: metrics,
+
+		queueName: queueName,
+
+		retries: sync.WaitGroup{},
+	}
+}
+
+// StartWorkers starts a worker pool consuming queueName as a goroutine of the process,
+// which restarts on failure, e.g. when the consumer of the queue stops, and drains in
+// PhaseDrainWorkers of the shutdown. Messages are
+// recorded as the processfx_worker_messages_total and processfx_worker_duration_seconds
+// metrics of the logger.
+func (p *Process) StartWorkers(
+	name string,
+	queue connfx.QueueRepository,
+	queueName string,
+	config *WorkerConfig,
+	handler WorkerHandler,
+) (*WorkerPool, error) {
+	metrics, err := p.workersMetrics()
+	if err != nil {
+		return nil, err
+	}
+
+	pool := NewWorkerPool(queue, queueName, config, handler, p.Logger, metrics)
+
+	p.StartGoroutine(
+		name,
+		pool.Run,
+		WithRestartPolicy(RestartOnFailure),
+		InShutdownPhase(PhaseDrainWorkers),
+	)
+
+	return pool, nil
+}
+
+// Run consumes the queue with the workers until ctx is done, then waits for the
+// messages in hand, whose handlers are not cancelled with ctx. It returns
+// ErrWorkerConsumeStopped when the consumer stops before.
+func (w *WorkerPool) Run(ctx context.Context) error {
+	consumeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	messages, errs := w.consume(consumeCtx)
+
+	workers := &sync.WaitGroup{}
+
+	for range max(w.config.Workers, 1) {
+		workers.Add(1)
+
+		go func() {
+			defer workers.Done()
+
+			for message := range messages {
+				w.process(ctx, &message)
+			}
+		}()
+	}
+
+	go w.logConsumeErrors(ctx, errs)
+
+	workers.Wait()
+	w.retries.Wait()
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	return fmt.Errorf("%w (queue=%q)", ErrWorkerConsumeStopped, w.queueName)
+}
+
+func (p *Process) workersMetrics() (*WorkerMetrics, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.workerMetrics != nil || p.Logger == nil {
+		return p.workerMetrics, nil
+	}
+
+	metrics := NewWorkerMetrics(p.Logger.NewMetricsBuilder("processfx"))
+
+	err := metrics.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	p.workerMetrics = metrics
+
+	return metrics, nil
+}
+
+func (w *WorkerPool) consume(ctx context.Context) (<-chan connfx.Message, <-chan error) {
+	consumerConfig := connfx.DefaultConsumerConfig()
+	consumerConfig.PrefetchCount = max(consumerConfig.PrefetchCount, w.config.Workers)
+
+	if w.config.ConsumerGroup == "" {
+		return w.queue.Consume(ctx, w.queueName, consumerConfig)
+	}
+
+	consumerName := w.config.ConsumerName
+	if consumerName == "" {
+		hostname, _ := os.Hostname()
+		consumerName = hostname + "-" + strconv.Itoa(os.Getpid())
+	}
+
+	return w.queue.ConsumeWithGroup(ctx, w.queueName, w.config.ConsumerGroup, consumerName, consumerConfig)
+}
+
+func (w *WorkerPool) logConsumeErrors(ctx context.Context, errs <-chan error) {
+	for err := range errs {
+		if w.logger != nil && ctx.Err() == nil {
+			w.logger.WarnContext(ctx, "Worker consumer error", "queue", w.queueName, "error", err)
+		}
+	}
+}
+
+func (w *WorkerPool) process(ctx context.Context, message *connfx.Message) {
+	// A message in hand is handled to the end while draining; HandlerTimeout bounds it.
+	handlerCtx := context.WithoutCancel(ctx)
+
+	if w.config.HandlerTimeout > 0 {
+		var cancel context.CancelFunc
+
+		handlerCtx, cancel = context.WithTimeout(handlerCtx, w.config.HandlerTimeout)
+		defer cancel()
+	}
+
+	startTime := time.Now()
+	err := runRecovered(handlerCtx, func(ctx context.Context) error {
+		return w.handler(ctx, message)
+	})
+	duration := time.Since(startTime)
+
+	if err != nil && errors.Is(handlerCtx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("%w (timeout=%s): %w", ErrWorkerHandlerTimedOut, w.config.HandlerTimeout, err)
+	}
+
+	status, settleErr := w.settle(ctx, message, err)
+	if settleErr != nil && w.logger != nil {
+		w.logger.ErrorContext(ctx, "Worker failed to settle message",
+			"queue", w.queueName,
+			"message_id", message.MessageID,
+			"error", settleErr,
+		)
+	}
+
+	if w.metrics != nil {
+		w.metrics.MessagesTotal.Inc(ctx, slog.String("queue", w.queueName), slog.String("status", status))
+		w.metrics.HandleDuration.RecordDuration(ctx, duration, slog.String("queue", w.queueName))
+	}
+}
+
+// settle acknowledges, retries, requeues or dead-letters a handled message, and
+// returns its status.
+func (w *WorkerPool) settle(ctx context.Context, message *connfx.Message, err error) (string, error) {
+	if err == nil {
+		return MessageStatusAcked, w.ack(message)
+	}
+
+	// Shutting down; the message is redelivered to another consumer.
+	if ctx.Err() != nil {
+		return MessageStatusRequeued, w.nack(message, true)
+	}
+
+	retryCount := retryCountOf(message)
+
+	if w.logger != nil {
+		w.logger.WarnContext(ctx, "Worker handler failed",
+			"queue", w.queueName,
+			"message_id", message.MessageID,
+			"retry_count", retryCount,
+			"error", err,
+		)
+	}
+
+	if errors.Is(err, ErrNonRetryable) || retryCount >= w.config.MaxRetries {
+		return MessageStatusDeadLettered, w.deadLetter(ctx, message, err)
+	}
+
+	w.retries.Add(1)
+
+	go func() {
+		defer w.retries.Done()
+
+		retryErr := w.retry(ctx, message, retryCount)
+		if retryErr != nil && w.logger != nil {
+			w.logger.ErrorContext(ctx, "Worker failed to retry message",
+				"queue", w.queueName,
+				"message_id", message.MessageID,
+				"error", retryErr,
+			)
+		}
+	}()
+
+	return MessageStatusRetried, nil
+}
+
+// retry republishes the message after the retry delay, or at once on shutdown, and
+// acknowledges it once the copy is published.
+func (w *WorkerPool) retry(ctx context.Context, message *connfx.Message, retryCount int) error {
+	delay := w.config.RetryDelay << min(retryCount, 16) //nolint:mnd
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(delay):
+	}
+
+	headers := maps.Clone(message.Headers)
+	if headers == nil {
+		headers = make(map[string]any)
+	}
+
+	headers[RetryCountHeader] = strconv.Itoa(retryCount + 1)
+
+	err := w.queue.PublishWithHeaders(context.WithoutCancel(ctx), w.queueName, message.Body, headers)
+	if err != nil {
+		return fmt.Errorf("%w (queue=%q): %w", ErrFailedToRetryMessage, w.queueName, errors.Join(err, w.nack(message, true)))
+	}
+
+	return w.ack(message)
+}
+
+func (w *WorkerPool) deadLetter(ctx context.Context, message *connfx.Message, cause error) error {
+	if w.config.DeadLetterQueue == "" {
+		return w.nack(message, false)
+	}
+
+	headers := maps.Clone(message.Headers)
+	if headers == nil {
+		headers = make(map[string]any)
+	}
+
+	headers[ErrorHeader] = cause.Error()
+	headers[OriginalQueueHeader] = w.queueName
+
+	err := w.queue.PublishWithHeaders(ctx, w.config.DeadLetterQueue, message.Body, headers)
+	if err != nil {
+		return fmt.Errorf(
+			"%w (queue=%q, dead_letter_queue=%q): %w",
+			ErrFailedToDeadLetter,
+			w.queueName,
+			w.config.DeadLetterQueue,
+			errors.Join(err, w.nack(message, true)),
+		)
+	}
+
+	return w.ack(message)
+}
+
+func (w *WorkerPool) ack(message *connfx.Message) error {
+	err := message.Ack()
+	if err != nil {
+		return fmt.Errorf("%w (queue=%q): %w", ErrFailedToAckMessage, w.queueName, err)
+	}
+
+	return nil
+}
+
+func (w *WorkerPool) nack(message *connfx.Message, requeue bool) error {
+	err := message.Nack(requeue)
+	if err != nil {
+		return fmt.Errorf("%w (queue=%q): %w", ErrFailedToNackMessage, w.queueName, err)
+	}
+
+	return nil
+}
+
+// retryCountOf returns the RetryCountHeader of a message; adapters may hand headers
+// back as strings or numbers.
+func retryCountOf(message *connfx.Message) int {
+	switch value := message.Headers[RetryCountHeader].(type) {
+	case int:
+		return value
+	case int32:
+		return int(value)
+	case int64:
+		return int(value)
+	case float64:
+		return int(value)
+	case string:
+		count, _ := strconv.Atoi(value)
+
+		return count
+	case []byte:
+		count, _ := strconv.Atoi(string(value))
+
+		return count
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,225 @@
+package processfx_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/ajan/processfx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errHandlerFailed = errors.New("handler failed")
+
+func pendingOf(t *testing.T, queue *connfx.InMemoryAdapter, queueName string) int64 {
+	t.Helper()
+
+	infos, err := queue.ConsumerGroupInfo(t.Context(), queueName)
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+
+	return infos[0].Pending
+}
+
+func TestProcess_StartWorkers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should handle and acknowledge messages with the workers", func(t *testing.T) {
+		t.Parallel()
+
+		queue := connfx.NewInMemoryAdapter()
+		process := processfx.New(t.Context(), logfx.NewLogger())
+
+		for i := range 20 {
+			require.NoError(t, queue.Publish(t.Context(), "emails", fmt.Appendf(nil, "message-%d", i)))
+		}
+
+		var (
+			mu      sync.Mutex
+			handled = map[string]int{}
+		)
+
+		_, err := process.StartWorkers(
+			"emails",
+			queue,
+			"emails",
+			&processfx.WorkerConfig{ConsumerGroup: "senders", Workers: 4}, //nolint:exhaustruct
+			func(_ context.Context, message *connfx.Message) error {
+				mu.Lock()
+				defer mu.Unlock()
+
+				handled[string(message.Body)]++
+
+				return nil
+			},
+		)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+
+			return len(handled) == 20
+		}, time.Second, 5*time.Millisecond)
+
+		process.Cancel()
+		process.Shutdown()
+
+		for body, count := range handled {
+			assert.Equal(t, 1, count, body)
+		}
+
+		assert.Zero(t, pendingOf(t, queue, "emails"))
+	})
+
+	t.Run("should retry failed messages with a delay", func(t *testing.T) {
+		t.Parallel()
+
+		queue := connfx.NewInMemoryAdapter()
+		process := processfx.New(t.Context(), nil)
+
+		require.NoError(t, queue.Publish(t.Context(), "jobs", []byte("job")))
+
+		var (
+			attempts    atomic.Int32
+			retryCounts sync.Map
+		)
+
+		_, err := process.StartWorkers(
+			"jobs",
+			queue,
+			"jobs",
+			&processfx.WorkerConfig{ //nolint:exhaustruct
+				ConsumerGroup: "workers",
+				MaxRetries:    3,
+				RetryDelay:    5 * time.Millisecond,
+			},
+			func(_ context.Context, message *connfx.Message) error {
+				attempt := attempts.Add(1)
+				retryCounts.Store(attempt, message.Headers[processfx.RetryCountHeader])
+
+				if attempt < 3 {
+					return errHandlerFailed
+				}
+
+				return nil
+			},
+		)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool { return attempts.Load() == 3 }, time.Second, 5*time.Millisecond)
+
+		process.Cancel()
+		process.Shutdown()
+
+		first, _ := retryCounts.Load(int32(1))
+		third, _ := retryCounts.Load(int32(3))
+
+		assert.Nil(t, first)
+		assert.Equal(t, "2", third)
+		assert.Zero(t, pendingOf(t, queue, "jobs"))
+	})
+
+	t.Run("should dead-letter messages that fail every retry", func(t *testing.T) {
+		t.Parallel()
+
+		queue := connfx.NewInMemoryAdapter()
+		process := processfx.New(t.Context(), nil)
+
+		require.NoError(t, queue.Publish(t.Context(), "jobs", []byte("job")))
+
+		var attempts atomic.Int32
+
+		_, err := process.StartWorkers(
+			"jobs",
+			queue,
+			"jobs",
+			&processfx.WorkerConfig{ //nolint:exhaustruct
+				ConsumerGroup:   "workers",
+				DeadLetterQueue: "jobs.dlq",
+				MaxRetries:      2,
+				RetryDelay:      time.Millisecond,
+			},
+			func(context.Context, *connfx.Message) error {
+				attempts.Add(1)
+
+				return errHandlerFailed
+			},
+		)
+		require.NoError(t, err)
+
+		messages, _ := queue.ConsumeWithGroup(
+			t.Context(),
+			"jobs.dlq",
+			"inspectors",
+			"inspector",
+			connfx.DefaultConsumerConfig(),
+		)
+
+		select {
+		case message := <-messages:
+			assert.Equal(t, []byte("job"), message.Body)
+			assert.Equal(t, "jobs", message.Headers[processfx.OriginalQueueHeader])
+			assert.Equal(t, errHandlerFailed.Error(), message.Headers[processfx.ErrorHeader])
+		case <-time.After(time.Second):
+			require.Fail(t, "message is not dead-lettered")
+		}
+
+		process.Cancel()
+		process.Shutdown()
+
+		assert.Equal(t, int32(3), attempts.Load())
+		assert.Zero(t, pendingOf(t, queue, "jobs"))
+	})
+
+	t.Run("should not retry non-retryable messages", func(t *testing.T) {
+		t.Parallel()
+
+		queue := connfx.NewInMemoryAdapter()
+
+		require.NoError(t, queue.Publish(t.Context(), "jobs", []byte("{")))
+
+		var attempts atomic.Int32
+
+		pool := processfx.NewWorkerPool(
+			queue,
+			"jobs",
+			&processfx.WorkerConfig{ //nolint:exhaustruct
+				ConsumerGroup:   "workers",
+				DeadLetterQueue: "jobs.dlq",
+				MaxRetries:      5,
+			},
+			func(context.Context, *connfx.Message) error {
+				attempts.Add(1)
+
+				return fmt.Errorf("%w: malformed body", processfx.ErrNonRetryable)
+			},
+			nil,
+			nil,
+		)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		done := make(chan error, 1)
+
+		go func() { done <- pool.Run(ctx) }()
+
+		require.Eventually(t, func() bool {
+			info, err := queue.StreamInfo(t.Context(), "jobs.dlq")
+
+			return err == nil && info.Length == 1
+		}, time.Second, 5*time.Millisecond)
+
+		cancel()
+		require.NoError(t, <-done)
+
+		assert.Equal(t, int32(1), attempts.Load())
+		assert.Zero(t, pendingOf(t, queue, "jobs"))
+	})
+}
@@ -5,29 +5,56 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"os"
 
 	"github.com/eser/aya.is-services/pkg/ajan/configfx"
 	"github.com/eser/aya.is-services/pkg/ajan/connfx"
 	"github.com/eser/aya.is-services/pkg/ajan/httpclient"
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx/middlewares"
+	"github.com/eser/aya.is-services/pkg/ajan/lib"
 	"github.com/eser/aya.is-services/pkg/ajan/logfx"
 	"github.com/eser/aya.is-services/pkg/api/adapters/arcade"
 	"github.com/eser/aya.is-services/pkg/api/adapters/auth_providers"
 	"github.com/eser/aya.is-services/pkg/api/adapters/storage"
+	"github.com/eser/aya.is-services/pkg/api/business/comments"
 	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/eser/aya.is-services/pkg/api/business/reactions"
+	"github.com/eser/aya.is-services/pkg/api/business/sitemaps"
 	"github.com/eser/aya.is-services/pkg/api/business/stories"
 	"github.com/eser/aya.is-services/pkg/api/business/users"
 	_ "github.com/lib/pq"
 )
 
-var ErrInitFailed = errors.New("failed to initialize app context")
+var (
+	ErrInitFailed               = errors.New("failed to initialize app context")
+	ErrExportLinkSecretRequired = errors.New("exports link secret is required with an exports storage")
+	ErrJWTSecretRequired        = errors.New("auth JWT secret is required to serve")
+)
 
 type AppContext struct {
 	// Adapters
 	Config *AppConfig
 	Logger *logfx.Logger
 
+	// ConfigFlags override the config files and the environment when set; see
+	// NewConfigFlags.
+	ConfigFlags *configfx.ConfigFlags
+
+	// ConfigWatcher is nil when the config files are not watched.
+	ConfigWatcher *configfx.ConfigWatcher
+
 	HTTPClient *httpclient.Client
 
+	RequestLimits *middlewares.RequestLimits
+
+	// ResponseCache is nil when response caching is not configured.
+	ResponseCache *middlewares.ResponseCache
+	// Idempotency is nil when idempotency keys are not configured.
+	Idempotency *middlewares.Idempotency
+	// CommentRateLimiter limits how often each user posts comments and reports.
+	CommentRateLimiter httpfx.Handler
+
 	Connections *connfx.Registry
 
 	Arcade *arcade.Arcade
@@ -35,24 +62,39 @@ type AppContext struct {
 	Repository *storage.Repository
 
 	// Business
-	ProfilesService *profiles.Service
-	UsersService    *users.Service
-	StoriesService  *stories.Service
+	ProfilesService  *profiles.Service
+	UsersService     *users.Service
+	StoriesService   *stories.Service
+	SitemapsService  *sitemaps.Service
+	CommentsService  *comments.Service
+	ReactionsService *reactions.Service
 }
 
 func New() *AppContext {
 	return &AppContext{} //nolint:exhaustruct
 }
 
+// InspectConfig loads the config like Init, and returns each of its values with the
+// source that set it: a default, a file, the environment or a flag of ConfigFlags.
+// Secrets are masked.
+func (a *AppContext) InspectConfig() ([]configfx.ConfigValue, error) {
+	cl := newConfigManager()
+
+	return cl.InspectDefaults( //nolint:wrapcheck
+		&AppConfig{}, //nolint:exhaustruct
+		configfx.ConfigSource{Name: configfx.SourceFlag, Resource: cl.FromFlags(a.ConfigFlags)},
+	)
+}
+
 func (a *AppContext) Init(ctx context.Context) error {
 	// ----------------------------------------------------
 	// Adapter: Config
 	// ----------------------------------------------------
-	cl := configfx.NewConfigManager()
+	cl := newConfigManager()
 
 	a.Config = &AppConfig{} //nolint:exhaustruct
 
-	err := cl.LoadDefaults(a.Config)
+	err := cl.LoadDefaults(a.Config, cl.FromFlags(a.ConfigFlags))
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrInitFailed, err)
 	}
@@ -64,6 +106,18 @@ func (a *AppContext) Init(ctx context.Context) error {
 		logfx.WithConfig(&a.Config.Log),
 	)
 
+	// Metrics are collected for the scrape endpoint from here on.
+	if a.Config.HTTP.MetricsEnabled {
+		a.Logger.EnablePrometheus()
+	}
+
+	if a.Config.Log.Metrics {
+		err = a.Logger.EnableLogMetrics()
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInitFailed, err)
+		}
+	}
+
 	a.Logger.InfoContext(
 		ctx,
 		"[AppContext] Initialization in progress",
@@ -73,19 +127,14 @@ func (a *AppContext) Init(ctx context.Context) error {
 		slog.Any("features", a.Config.Features),
 	)
 
-	// ----------------------------------------------------
-	// Adapter: HTTPClient
-	// ----------------------------------------------------
-	a.HTTPClient = httpclient.NewClient(
-		httpclient.WithConfig(&a.Config.HTTPClient),
-	)
-
 	// ----------------------------------------------------
 	// Adapter: Connections
 	// ----------------------------------------------------
 	a.Connections = connfx.NewRegistry(
-		connfx.WithLogger(a.Logger),
+		connfx.WithLogger(a.Logger.WithScope("connfx")),
 		connfx.WithDefaultFactories(),
+		connfx.WithEnvironment(a.Config.AppEnv),
+		connfx.WithAutoReconnect(connfx.NewDefaultReconnectConfig()),
 	)
 
 	err = a.Connections.LoadFromConfig(ctx, &a.Config.Conn)
@@ -93,6 +142,98 @@ func (a *AppContext) Init(ctx context.Context) error {
 		return fmt.Errorf("%w: %w", ErrInitFailed, err)
 	}
 
+	err = a.Connections.StartAutoReconnect(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInitFailed, err)
+	}
+
+	err = a.Connections.StartCertificateWatcher(ctx, connfx.DefaultCertificateWatchInterval)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInitFailed, err)
+	}
+
+	// ----------------------------------------------------
+	// Adapter: HTTPClient
+	// ----------------------------------------------------
+	httpClientMetrics := httpclient.NewMetrics(a.Logger.NewMetricsBuilder("httpclient"))
+
+	err = httpClientMetrics.Init()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInitFailed, err)
+	}
+
+	httpClientOptions := []httpclient.NewClientOption{
+		httpclient.WithConfig(&a.Config.HTTPClient),
+		httpclient.WithResponseHook(
+			httpclient.LogResponses(a.Logger.Logger, a.Config.HTTPClient.ServerErrorThreshold),
+		),
+		httpclient.WithMetrics(httpClientMetrics),
+	}
+
+	if a.Config.HTTPCache != "" {
+		cache, err := connfx.GetCache(a.Connections, a.Config.HTTPCache)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInitFailed, err)
+		}
+
+		httpClientOptions = append(httpClientOptions, httpclient.WithCache(cache))
+	}
+
+	a.HTTPClient = httpclient.NewClient(httpClientOptions...)
+
+	// ----------------------------------------------------
+	// Adapter: RequestLimits
+	// ----------------------------------------------------
+	a.RequestLimits = middlewares.NewRequestLimits(requestLimitsOptions(&a.Config.HTTP)...)
+
+	// ----------------------------------------------------
+	// Adapter: ResponseCache
+	// ----------------------------------------------------
+	if a.Config.ResponseCache != "" {
+		cache, err := connfx.GetCache(a.Connections, a.Config.ResponseCache)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInitFailed, err)
+		}
+
+		a.ResponseCache = middlewares.NewResponseCache(cache, "")
+	}
+
+	// ----------------------------------------------------
+	// Adapter: Idempotency
+	// ----------------------------------------------------
+	if a.Config.IdempotencyCache != "" {
+		cache, err := connfx.GetCache(a.Connections, a.Config.IdempotencyCache)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInitFailed, err)
+		}
+
+		a.Idempotency = middlewares.NewIdempotency(cache, "")
+	}
+
+	// ----------------------------------------------------
+	// Adapter: CommentRateLimiter
+	// ----------------------------------------------------
+	commentRateLimitOptions := []middlewares.RateLimitOption{
+		middlewares.WithRateLimiterRequestsPerMinute(a.Config.Comments.PostsPerMinute),
+		middlewares.WithUserKeyFunc(commentRateLimitKey),
+	}
+
+	if a.Config.Comments.RateLimitCache != "" {
+		cache, err := connfx.GetCache(a.Connections, a.Config.Comments.RateLimitCache)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInitFailed, err)
+		}
+
+		commentRateLimitOptions = append(
+			commentRateLimitOptions,
+			middlewares.WithRateLimiterStore(
+				middlewares.NewRedisRateLimitStore(cache, middlewares.DefaultRateLimitKeyPrefix+"comments:"),
+			),
+		)
+	}
+
+	a.CommentRateLimiter = middlewares.RateLimitMiddleware(commentRateLimitOptions...)
+
 	// // ----------------------------------------------------
 	// // Adapter: Metrics
 	// // ----------------------------------------------------
@@ -117,7 +258,7 @@ func (a *AppContext) Init(ctx context.Context) error {
 	// ----------------------------------------------------
 	// Adapter: Repository
 	// ----------------------------------------------------
-	a.Repository, err = storage.NewRepositoryFromDefault(a.Logger, a.Connections)
+	a.Repository, err = storage.NewRepositoryFromDefault(a.Logger.WithScope("storage"), a.Connections)
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrInitFailed, err)
 	}
@@ -126,12 +267,162 @@ func (a *AppContext) Init(ctx context.Context) error {
 	// Business Services
 	// ----------------------------------------------------
 	authProviders := map[string]users.AuthProvider{
-		"github": auth_providers.NewGitHubAuthProvider(a.Logger, a.HTTPClient, a.Repository),
+		"github": auth_providers.NewGitHubAuthProvider(
+			a.Logger,
+			a.HTTPClient,
+			a.Repository,
+			a.Config.Auth.JWTSecret,
+		),
 	}
 
-	a.ProfilesService = profiles.NewService(a.Logger, a.Repository)
+	a.ProfilesService = profiles.NewService(a.Logger, a.Repository, a.ResponseCache)
 	a.UsersService = users.NewService(a.Logger, a.Repository, authProviders)
-	a.StoriesService = stories.NewService(a.Logger, a.Repository)
+	a.ReactionsService = reactions.NewService(a.Logger, a.Repository)
+	a.StoriesService = stories.NewService(
+		a.Logger,
+		a.Repository,
+		a.ResponseCache,
+		a.ReactionsService,
+	)
+	a.StoriesService.SetFeedSettings(stories.FeedSettings{
+		BaseURL:   a.Config.Feeds.BaseURL,
+		ItemCount: a.Config.Feeds.ItemCount,
+	})
+	a.StoriesService.SetRevisionRetention(a.Config.Stories.RevisionRetention)
+	a.CommentsService = comments.NewService(a.Logger, a.Repository, a.ReactionsService)
+	a.SitemapsService = sitemaps.NewService(a.Logger, a.Repository, sitemaps.Settings{
+		BaseURL: a.Config.Sitemap.BaseURL,
+		Locales: a.Config.Locales.Supported,
+	})
+
+	if a.Config.Analytics.Queue != "" {
+		queue, err := connfx.GetQueue(a.Connections, a.Config.Analytics.Queue)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInitFailed, err)
+		}
+
+		a.ProfilesService.SetViewQueue(
+			queue,
+			a.Config.Analytics.QueueName,
+			a.Config.Analytics.VisitorSecret,
+		)
+	}
+
+	if a.Config.Exports.Storage != "" {
+		if a.Config.Exports.LinkSecret == "" {
+			return fmt.Errorf("%w: %w", ErrInitFailed, ErrExportLinkSecretRequired)
+		}
+
+		objects, err := connfx.GetObjectStorage(a.Connections, a.Config.Exports.Storage)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInitFailed, err)
+		}
+
+		a.ProfilesService.SetExportStorage(
+			storage.NewExportArchives(objects),
+			profiles.ExportSettings{
+				LinkSecret: a.Config.Exports.LinkSecret,
+				LinkTTL:    a.Config.Exports.LinkTTL,
+				Retention:  a.Config.Exports.Retention,
+			},
+		)
+	}
+
+	return nil
+}
+
+// newConfigManager creates the config manager of the app, which resolves secret
+// references such as vault://secret/data/app#jwt_secret while loading, so the secrets
+// are kept out of the config files.
+func newConfigManager() *configfx.ConfigManager {
+	cl := configfx.NewConfigManager()
+
+	cl.RegisterSecretProvider("file", configfx.NewFileSecretProvider("."))
+
+	if vaultAddr := os.Getenv("VAULT_ADDR"); vaultAddr != "" {
+		cl.RegisterSecretProvider("vault", configfx.NewVaultSecretProvider(vaultAddr, os.Getenv("VAULT_TOKEN")))
+	}
+
+	return cl
+}
+
+// watchConfig applies the log levels, request limits and connections of the config
+// files whenever they change.
+func (a *AppContext) watchConfig(ctx context.Context, cl *configfx.ConfigManager) error {
+	watcher, err := cl.WatchDefaults(a.Config, cl.FromFlags(a.ConfigFlags))
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	watcher.OnError(func(ctx context.Context, err error) {
+		a.Logger.ErrorContext(
+			ctx,
+			"[AppContext] Failed to reload config",
+			slog.String("module", "appcontext"),
+			slog.Any("error", err),
+		)
+	})
+
+	watcher.Subscribe(func(ctx context.Context, changes *configfx.ConfigChanges) error {
+		a.Logger.InfoContext(
+			ctx,
+			"[AppContext] Config reloaded",
+			slog.String("module", "appcontext"),
+			slog.Any("keys", changes.Keys),
+		)
+
+		return nil
+	})
+
+	watcher.Subscribe(func(_ context.Context, changes *configfx.ConfigChanges) error {
+		config, _ := changes.Config.(*AppConfig)
+
+		return a.Logger.Levels().ApplyConfig(&config.Log) //nolint:wrapcheck
+	}, "log__level", "log__levels")
+
+	watcher.Subscribe(func(_ context.Context, changes *configfx.ConfigChanges) error {
+		config, _ := changes.Config.(*AppConfig)
+
+		a.RequestLimits.Update(requestLimitsOptions(&config.HTTP)...)
+
+		return nil
+	}, "http__max_body_size", "http__max_header_count", "http__max_header_size")
+
+	watcher.Subscribe(func(ctx context.Context, changes *configfx.ConfigChanges) error {
+		config, _ := changes.Config.(*AppConfig)
+
+		_, err := a.Connections.ApplyConfig(ctx, &config.Conn)
+
+		return err //nolint:wrapcheck
+	}, "conn")
+
+	err = watcher.Start(ctx, a.Config.ConfigWatchInterval)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	a.ConfigWatcher = watcher
 
 	return nil
 }
+
+func requestLimitsOptions(config *httpfx.Config) []middlewares.RequestLimitsOption {
+	return []middlewares.RequestLimitsOption{
+		middlewares.WithMaxBodySize(config.MaxBodySize),
+		middlewares.WithMaxHeaderCount(config.MaxHeaderCount),
+		middlewares.WithMaxHeaderSize(config.MaxHeaderSize),
+	}
+}
+
+// commentRateLimitKey limits each user on their own, and requests without a session,
+// which are rejected anyway, by their address.
+func commentRateLimitKey(ctx *httpfx.Context) string {
+	identity, ok := httpfx.AuthIdentityFromContext(ctx.Request.Context())
+	if ok && identity.Subject != "" {
+		return "user:" + identity.Subject
+	}
+
+	host, _, _ := lib.SplitHostPort(ctx.Request.RemoteAddr)
+
+	return "address:" + host
+}
@@ -1,7 +1,11 @@
 package appcontext
 
 import (
+	"time"
+
 	"github.com/eser/aya.is-services/pkg/ajan"
+	"github.com/eser/aya.is-services/pkg/ajan/configfx"
+	"github.com/eser/aya.is-services/pkg/ajan/processfx"
 	"github.com/eser/aya.is-services/pkg/api/adapters/arcade"
 )
 
@@ -13,9 +17,142 @@ type Externals struct {
 	Arcade arcade.Config `conf:"ARCADE"`
 }
 
+type AdminConfig struct {
+	// Token guards the administrative HTTP endpoints; they are disabled when empty.
+	Token string `conf:"TOKEN"`
+}
+
+type AuthConfig struct {
+	// JWTSecret signs the session tokens the auth providers issue, and verifies them.
+	JWTSecret string `conf:"JWT_SECRET"`
+	// JWKSURL verifies RSA- and ECDSA-signed tokens of an external identity provider.
+	JWKSURL  string `conf:"JWKS_URL"`
+	Issuer   string `conf:"ISSUER"`
+	Audience string `conf:"AUDIENCE"`
+}
+
+type LocalesConfig struct {
+	// Default is served when a request asks for no supported locale.
+	Default string `conf:"DEFAULT" default:"en"`
+	// Supported lists the served locales; in env values, separated by commas.
+	Supported []string `conf:"SUPPORTED" default:"en,tr"`
+}
+
+type CustomDomainsConfig struct {
+	// VerifySchedule is the cron expression the DNS challenges of pending custom domain
+	// claims are checked on. They are not checked when it is empty.
+	VerifySchedule string `conf:"VERIFY_SCHEDULE" default:"*/5 * * * *"`
+	// VerifyBatchSize is the most claims a run checks.
+	VerifyBatchSize int `conf:"VERIFY_BATCH_SIZE" default:"50"`
+}
+
+type AnalyticsConfig struct {
+	// Queue names the connection whose queue carries the views of profiles and stories
+	// until they are stored. Views are not recorded when it is empty.
+	Queue     string `conf:"QUEUE"`
+	QueueName string `conf:"QUEUE_NAME" default:"profile-views"`
+	// VisitorSecret keys the daily hashes that identify the visitors.
+	VisitorSecret string `conf:"VISITOR_SECRET"`
+
+	Workers processfx.WorkerConfig `conf:"WORKERS"`
+}
+
+type ExportsConfig struct {
+	// Storage names the connection whose object storage keeps the archives of profile
+	// exports. Exports are disabled when it is empty.
+	Storage string `conf:"STORAGE"`
+	// LinkSecret signs the download links of the archives; required with Storage.
+	LinkSecret string `conf:"LINK_SECRET"`
+	// LinkTTL is how long a download link works.
+	LinkTTL time.Duration `conf:"LINK_TTL" default:"1h"`
+	// Retention is how long an archive is kept once it is ready.
+	Retention time.Duration `conf:"RETENTION" default:"168h"`
+	// Schedule is the cron expression pending exports are run on.
+	Schedule string `conf:"SCHEDULE" default:"* * * * *"`
+	// BatchSize is the most exports a run takes.
+	BatchSize int `conf:"BATCH_SIZE" default:"5"`
+}
+
+type SitemapConfig struct {
+	// BaseURL is the address of the site whose pages the sitemap lists, e.g.
+	// https://aya.is. The sitemap is not served when it is empty.
+	BaseURL string `conf:"BASE_URL"`
+	// Schedule is the cron expression the sitemap is generated again on.
+	Schedule string `conf:"SCHEDULE" default:"0 * * * *"`
+}
+
+type FeedsConfig struct {
+	// BaseURL is the address of the site the links of the story feeds lead to, e.g.
+	// https://aya.is. The feeds are not served when it is empty.
+	BaseURL string `conf:"BASE_URL"`
+	// ItemCount is how many of the latest stories a feed lists.
+	ItemCount int `conf:"ITEM_COUNT" default:"20"`
+}
+
+type BadgesConfig struct {
+	// AwardSchedule is the cron expression the award rules of the badges are evaluated
+	// on. Badges are not awarded by rules when it is empty.
+	AwardSchedule string `conf:"AWARD_SCHEDULE" default:"*/30 * * * *"`
+}
+
+type StoriesConfig struct {
+	// PublishSchedule is the cron expression the scheduled stories are published on.
+	// Scheduled stories are not published when it is empty.
+	PublishSchedule string `conf:"PUBLISH_SCHEDULE" default:"* * * * *"`
+	// PublishBatchSize is the most stories a run publishes.
+	PublishBatchSize int `conf:"PUBLISH_BATCH_SIZE" default:"50"`
+	// RevisionRetention is the most revisions of a story kept in each locale; the
+	// oldest are removed first. All are kept when it is 0.
+	RevisionRetention int `conf:"REVISION_RETENTION" default:"50"`
+}
+
+type CommentsConfig struct {
+	// PostsPerMinute is how many comments and reports of comments a user may post in a
+	// minute.
+	PostsPerMinute int `conf:"POSTS_PER_MINUTE" default:"5"`
+	// RateLimitCache names the connection whose cache keeps the rate limits of posting,
+	// so they hold across every instance. They are kept in memory when it is empty.
+	RateLimitCache string `conf:"RATE_LIMIT_CACHE"`
+}
+
 type AppConfig struct {
 	Externals Externals `conf:"EXTERNALS"`
 	ajan.BaseConfig
 
+	Admin AdminConfig `conf:"ADMIN"`
+	Auth  AuthConfig  `conf:"AUTH"`
+
+	Locales LocalesConfig `conf:"LOCALES"`
+
+	CustomDomains CustomDomainsConfig `conf:"CUSTOM_DOMAINS"`
+	Analytics     AnalyticsConfig     `conf:"ANALYTICS"`
+	Exports       ExportsConfig       `conf:"EXPORTS"`
+	Sitemap       SitemapConfig       `conf:"SITEMAP"`
+	Feeds         FeedsConfig         `conf:"FEEDS"`
+	Badges        BadgesConfig        `conf:"BADGES"`
+	Stories       StoriesConfig       `conf:"STORIES"`
+	Comments      CommentsConfig      `conf:"COMMENTS"`
+
+	// HTTPCache names the connection whose cache stores responses of external providers.
+	// Responses are not cached when it is empty.
+	HTTPCache string `conf:"HTTP_CACHE"`
+	// ResponseCache names the connection whose cache stores responses of hot read
+	// endpoints. Responses are not cached when it is empty.
+	ResponseCache string `conf:"RESPONSE_CACHE"`
+	// IdempotencyCache names the connection whose cache stores the responses of
+	// requests with an Idempotency-Key header. The header is ignored when it is empty.
+	IdempotencyCache string `conf:"IDEMPOTENCY_CACHE"`
+
 	Features FeatureFlags `conf:"FEATURES"`
+
+	// ConfigWatchInterval is how often the config files are checked for changes, which
+	// apply the log levels, request limits and connections without a restart. The
+	// files are not watched when it is 0.
+	ConfigWatchInterval time.Duration `conf:"CONFIG_WATCH_INTERVAL" default:"0"`
+}
+
+// NewConfigFlags creates a command-line flag for each value of AppConfig, e.g.
+// --http-addr and --log-level, to be set as the ConfigFlags of an AppContext.
+func NewConfigFlags() (*configfx.ConfigFlags, error) {
+	return configfx.NewConfigManager().NewFlags(&AppConfig{}) //nolint:exhaustruct,wrapcheck
 }
@@ -0,0 +1,265 @@
+package appcontext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/eser/aya.is-services/pkg/ajan/processfx"
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+)
+
+const (
+	customDomainVerifierJitter  = 30 * time.Second
+	customDomainVerifierTimeout = 2 * time.Minute
+
+	profileExporterJitter = 10 * time.Second
+	// profileExporterTimeout stays below profiles.ExportStaleAfter, so a run is not
+	// taken over while it is still going.
+	profileExporterTimeout = 20 * time.Minute
+
+	sitemapGeneratorJitter  = time.Minute
+	sitemapGeneratorTimeout = 5 * time.Minute
+
+	badgeAwarderJitter  = time.Minute
+	badgeAwarderTimeout = 10 * time.Minute
+
+	storyPublisherJitter  = 5 * time.Second
+	storyPublisherTimeout = time.Minute
+	// storyPublisherLockTTL stays above storyPublisherTimeout, so the lock of a run is
+	// not taken over by another instance while the run is still going.
+	storyPublisherLockTTL = 2 * time.Minute
+)
+
+// RegisterLifecycleHooks registers the start and stop logic of the adapters with
+// process, so the modules of the service can depend on them by name:
+//   - "connections" close in the last shutdown phase
+//   - "telemetry" flushes the logs, metrics and traces of the shutdown before that
+//   - "config-watcher" applies the config files while the process runs, when
+//     ConfigWatchInterval is set
+//   - "custom-domain-verifier" schedules the checks of the DNS challenges of custom
+//     domain claims, when CustomDomains.VerifySchedule is set
+//   - "view-recorder" starts the workers that store the views of profiles and stories,
+//     when Analytics.Queue is set
+//   - "profile-exporter" schedules the runs of the requested profile exports, when
+//     Exports.Storage is set
+//   - "sitemap-generator" schedules the generation of the sitemap, when
+//     Sitemap.BaseURL is set
+//   - "badge-awarder" schedules the evaluation of the award rules of the badges, when
+//     Badges.AwardSchedule is set
+//   - "story-publisher" schedules the publishing of the scheduled stories on one
+//     instance at a time, when Stories.PublishSchedule is set
+func (a *AppContext) RegisterLifecycleHooks(process *processfx.Process) error {
+	// The session tokens are signed with the secret, so the service does not serve
+	// without one.
+	if a.Config.Auth.JWTSecret == "" {
+		return fmt.Errorf("%w: %w", ErrInitFailed, ErrJWTSecretRequired)
+	}
+
+	hooks := []processfx.LifecycleHook{
+		{ //nolint:exhaustruct
+			Name:   "connections",
+			OnStop: a.Connections.Close,
+		},
+		{ //nolint:exhaustruct
+			Name:      "telemetry",
+			StopPhase: processfx.PhaseFlushTelemetry,
+			OnStop:    a.Logger.Flush,
+		},
+		{ //nolint:exhaustruct
+			Name:      "config-watcher",
+			DependsOn: []string{"connections"},
+			OnStart:   a.startConfigWatcher,
+			OnStop:    a.stopConfigWatcher,
+		},
+		{ //nolint:exhaustruct
+			Name:      "custom-domain-verifier",
+			DependsOn: []string{"connections"},
+			OnStart: func(context.Context) error {
+				return a.scheduleCustomDomainVerifier(process)
+			},
+		},
+		{ //nolint:exhaustruct
+			Name:      "view-recorder",
+			DependsOn: []string{"connections"},
+			OnStart: func(ctx context.Context) error {
+				return a.startViewRecorder(ctx, process)
+			},
+		},
+		{ //nolint:exhaustruct
+			Name:      "profile-exporter",
+			DependsOn: []string{"connections"},
+			OnStart: func(context.Context) error {
+				return a.scheduleProfileExporter(process)
+			},
+		},
+		{ //nolint:exhaustruct
+			Name:      "sitemap-generator",
+			DependsOn: []string{"connections"},
+			OnStart: func(context.Context) error {
+				return a.scheduleSitemapGenerator(process)
+			},
+		},
+		{ //nolint:exhaustruct
+			Name:      "badge-awarder",
+			DependsOn: []string{"connections"},
+			OnStart: func(context.Context) error {
+				return a.scheduleBadgeAwarder(process)
+			},
+		},
+		{ //nolint:exhaustruct
+			Name:      "story-publisher",
+			DependsOn: []string{"connections"},
+			OnStart: func(context.Context) error {
+				return a.scheduleStoryPublisher(process)
+			},
+		},
+	}
+
+	for _, hook := range hooks {
+		err := process.AddLifecycleHook(hook)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInitFailed, err)
+		}
+	}
+
+	return nil
+}
+
+func (a *AppContext) startConfigWatcher(ctx context.Context) error {
+	if a.Config.ConfigWatchInterval <= 0 {
+		return nil
+	}
+
+	return a.watchConfig(ctx, newConfigManager())
+}
+
+func (a *AppContext) stopConfigWatcher(context.Context) error {
+	if a.ConfigWatcher != nil {
+		a.ConfigWatcher.Stop()
+	}
+
+	return nil
+}
+
+func (a *AppContext) scheduleCustomDomainVerifier(process *processfx.Process) error {
+	if a.Config.CustomDomains.VerifySchedule == "" {
+		return nil
+	}
+
+	return process.Schedule( //nolint:wrapcheck
+		"custom-domain-verifier",
+		a.Config.CustomDomains.VerifySchedule,
+		func(ctx context.Context) error {
+			return a.ProfilesService.VerifyPendingCustomDomains(
+				ctx,
+				a.Config.CustomDomains.VerifyBatchSize,
+			)
+		},
+		processfx.WithJitter(customDomainVerifierJitter),
+		processfx.WithTimeout(customDomainVerifierTimeout),
+	)
+}
+
+func (a *AppContext) scheduleProfileExporter(process *processfx.Process) error {
+	if a.Config.Exports.Storage == "" || a.Config.Exports.Schedule == "" {
+		return nil
+	}
+
+	return process.Schedule( //nolint:wrapcheck
+		"profile-exporter",
+		a.Config.Exports.Schedule,
+		func(ctx context.Context) error {
+			return a.ProfilesService.RunPendingExports(ctx, a.Config.Exports.BatchSize)
+		},
+		processfx.WithJitter(profileExporterJitter),
+		processfx.WithTimeout(profileExporterTimeout),
+	)
+}
+
+func (a *AppContext) scheduleSitemapGenerator(process *processfx.Process) error {
+	if a.Config.Sitemap.BaseURL == "" || a.Config.Sitemap.Schedule == "" {
+		return nil
+	}
+
+	return process.Schedule( //nolint:wrapcheck
+		"sitemap-generator",
+		a.Config.Sitemap.Schedule,
+		func(ctx context.Context) error {
+			_, err := a.SitemapsService.Generate(ctx)
+
+			return err //nolint:wrapcheck
+		},
+		processfx.WithJitter(sitemapGeneratorJitter),
+		processfx.WithTimeout(sitemapGeneratorTimeout),
+	)
+}
+
+func (a *AppContext) scheduleBadgeAwarder(process *processfx.Process) error {
+	if a.Config.Badges.AwardSchedule == "" {
+		return nil
+	}
+
+	return process.Schedule( //nolint:wrapcheck
+		"badge-awarder",
+		a.Config.Badges.AwardSchedule,
+		a.ProfilesService.AwardBadges,
+		processfx.WithJitter(badgeAwarderJitter),
+		processfx.WithTimeout(badgeAwarderTimeout),
+	)
+}
+
+func (a *AppContext) scheduleStoryPublisher(process *processfx.Process) error {
+	if a.Config.Stories.PublishSchedule == "" {
+		return nil
+	}
+
+	return process.Schedule( //nolint:wrapcheck
+		"story-publisher",
+		a.Config.Stories.PublishSchedule,
+		func(ctx context.Context) error {
+			return a.StoriesService.PublishDueStories(ctx, a.Config.Stories.PublishBatchSize)
+		},
+		processfx.WithJitter(storyPublisherJitter),
+		processfx.WithTimeout(storyPublisherTimeout),
+		processfx.WithLock(a.Repository, storyPublisherLockTTL),
+	)
+}
+
+func (a *AppContext) startViewRecorder(ctx context.Context, process *processfx.Process) error {
+	if a.Config.Analytics.Queue == "" {
+		return nil
+	}
+
+	queue, err := connfx.GetQueue(a.Connections, a.Config.Analytics.Queue)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	_, err = queue.QueueDeclare(ctx, a.Config.Analytics.QueueName)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	_, err = process.StartWorkers(
+		"view-recorder",
+		queue,
+		a.Config.Analytics.QueueName,
+		&a.Config.Analytics.Workers,
+		a.storeView,
+	)
+
+	return err //nolint:wrapcheck
+}
+
+// storeView dead-letters the views that cannot be decoded without retries.
+func (a *AppContext) storeView(ctx context.Context, message *connfx.Message) error {
+	err := a.ProfilesService.StoreView(ctx, message.Body)
+	if errors.Is(err, profiles.ErrInvalidViewEvent) {
+		return fmt.Errorf("%w: %w", processfx.ErrNonRetryable, err)
+	}
+
+	return err //nolint:wrapcheck
+}
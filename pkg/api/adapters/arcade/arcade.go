@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/eser/aya.is-services/pkg/ajan/httpclient"
 	"github.com/eser/aya.is-services/pkg/api/business/profiles"
 )
 
@@ -126,6 +127,10 @@ func (arcade *Arcade) GetRecentPostsByUsername( //nolint:funlen
 
 	payloadReader := bytes.NewReader(payloadBytes)
 
+	if arcade.Config.HedgeDelay > 0 {
+		ctx = httpclient.WithHedging(ctx, arcade.Config.HedgeDelay)
+	}
+
 	req, err := http.NewRequestWithContext(
 		ctx,
 		http.MethodPost,
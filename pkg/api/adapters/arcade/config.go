@@ -1,6 +1,10 @@
 package arcade
 
+import "time"
+
 type Config struct {
 	URL    string `conf:"URL"    default:"https://api.arcade.dev/v1/tools/execute"`
 	APIKey string `conf:"APIKEY"`
+	// HedgeDelay sends a second tool execution when the first has not answered after it.
+	HedgeDelay time.Duration `conf:"HEDGE_DELAY"`
 }
@@ -22,7 +22,10 @@ const (
 	ExpirePeriod = 24 * time.Hour
 )
 
-var ErrFailedToGetAccessToken = errors.New("failed to get access token")
+var (
+	ErrFailedToGetAccessToken = errors.New("failed to get access token")
+	ErrJWTSecretRequired      = errors.New("JWT secret is required to sign session tokens")
+)
 
 type Repository interface {
 	CreateUser(ctx context.Context, user *users.User) error
@@ -37,6 +40,7 @@ type GitHubAuthProvider struct {
 	logger     *logfx.Logger
 	httpClient HTTPClient
 	repo       Repository
+	jwtSecret  []byte
 
 	ClientID     string
 	ClientSecret string
@@ -47,11 +51,13 @@ func NewGitHubAuthProvider(
 	logger *logfx.Logger,
 	httpClient HTTPClient,
 	repo Repository,
+	jwtSecret string,
 ) *GitHubAuthProvider {
 	return &GitHubAuthProvider{
 		logger:     logger,
 		httpClient: httpClient,
 		repo:       repo,
+		jwtSecret:  []byte(jwtSecret),
 
 		ClientID:     os.Getenv("GITHUB_CLIENT_ID"),
 		ClientSecret: os.Getenv("GITHUB_CLIENT_SECRET"),
@@ -86,6 +92,10 @@ func (g *GitHubAuthProvider) HandleOAuthCallback( //nolint:funlen
 	code string,
 	state string,
 ) (_ users.AuthResult, err error) {
+	if len(g.jwtSecret) == 0 {
+		return users.AuthResult{}, ErrJWTSecretRequired
+	}
+
 	// 1. Exchange code for access token
 	values := url.Values{
 		"client_id":     {g.ClientID},
@@ -205,9 +215,7 @@ func (g *GitHubAuthProvider) HandleOAuthCallback( //nolint:funlen
 		"session_id": claims.SessionID,
 		"exp":        claims.ExpiresAt,
 	})
-	secret := os.Getenv("JWT_SECRET")
-
-	tokenString, tokenStringErr := jwtToken.SignedString([]byte(secret))
+	tokenString, tokenStringErr := jwtToken.SignedString(g.jwtSecret)
 	if tokenStringErr != nil {
 		return users.AuthResult{}, tokenStringErr //nolint:wrapcheck
 	}
@@ -1,57 +1,98 @@
 package http
 
 import (
-	"os"
+	"crypto/subtle"
+	"errors"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
 	"github.com/eser/aya.is-services/pkg/api/business/users"
-	"github.com/golang-jwt/jwt/v5"
 )
 
-const AuthHeader = "Authorization"
+const (
+	AuthHeader   = "Authorization"
+	APIKeyHeader = "X-Api-Key"
+)
 
-func AuthMiddleware(usersService *users.Service) httpfx.Handler {
+// SessionMiddleware rejects the tokens of sessions that are no longer active, such as
+// revoked ones, with 401 Unauthorized, and records when the active ones are used. It
+// runs after middlewares.JWTAuthMiddleware; tokens without a "session_id" claim, such as
+// those of external identity providers, pass through.
+func SessionMiddleware(usersService *users.Service) httpfx.Handler {
 	return func(ctx *httpfx.Context) httpfx.Result {
-		// FIXME(@eser) no need to check if the header is specified
-		auth := ctx.Request.Header.Get(AuthHeader)
-
-		if auth == "" || !strings.HasPrefix(auth, "Bearer ") {
-			return ctx.Results.Unauthorized(httpfx.WithPlainText("Unauthorized"))
-		}
-
-		tokenStr := strings.TrimPrefix(auth, "Bearer ")
-		secret := os.Getenv("JWT_SECRET")
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (any, error) {
-			return []byte(secret), nil
-		})
-
-		if err != nil || !token.Valid {
-			return ctx.Results.Unauthorized(httpfx.WithPlainText("Invalid token"))
-		}
-
-		claims, ok := token.Claims.(jwt.MapClaims)
+		identity, ok := httpfx.AuthIdentityFromContext(ctx.Request.Context())
 		if !ok {
-			return ctx.Results.Unauthorized(httpfx.WithPlainText("Invalid claims"))
+			return ctx.Next()
 		}
 
-		sessionID, _ := claims["session_id"].(string)
+		sessionID, _ := identity.Claims["session_id"].(string)
 		if sessionID == "" {
-			return ctx.Results.Unauthorized(httpfx.WithPlainText("No session"))
+			return ctx.Next()
 		}
 
-		// Load session from repository
 		session, err := usersService.GetSessionByID(ctx.Request.Context(), sessionID)
-		if err != nil || session.Status != "active" {
+		if err != nil {
+			return ctx.Results.Error(
+				http.StatusInternalServerError,
+				httpfx.WithPlainText("Session verification failed"),
+			)
+		}
+
+		if session == nil || session.Status != "active" {
 			return ctx.Results.Unauthorized(httpfx.WithPlainText("Session invalid"))
 		}
 
-		// Update logged_in_at
 		_ = usersService.UpdateSessionLoggedInAt(ctx.Request.Context(), sessionID, time.Now())
 
-		result := ctx.Next()
+		return ctx.Next()
+	}
+}
+
+// APIKeyMiddleware authenticates machine clients that send an API key in the X-Api-Key
+// header. The key's scopes become the scopes of the request's httpfx.AuthIdentity, so
+// routes guard them with RequireAuth and RequireScope like user tokens.
+func APIKeyMiddleware(usersService *users.Service) httpfx.Handler {
+	return func(ctx *httpfx.Context) httpfx.Result {
+		key := ctx.Request.Header.Get(APIKeyHeader)
+		if key == "" {
+			return ctx.Next()
+		}
+
+		apiKey, err := usersService.AuthenticateAPIKey(ctx.Request.Context(), key)
+		if err != nil {
+			if errors.Is(err, users.ErrInvalidAPIKey) {
+				return ctx.Results.Unauthorized(httpfx.WithPlainText("Invalid API key"))
+			}
+
+			return ctx.Results.Error(
+				http.StatusInternalServerError,
+				httpfx.WithPlainText("API key verification failed"),
+			)
+		}
+
+		ctx.UpdateContext(httpfx.WithAuthIdentity(ctx.Request.Context(), &httpfx.AuthIdentity{
+			Claims:  nil,
+			Subject: "api_key:" + apiKey.ID,
+			Scopes:  apiKey.Scopes,
+		}))
+
+		return ctx.Next()
+	}
+}
+
+// AdminTokenMiddleware guards administrative routes with a static bearer token.
+func AdminTokenMiddleware(adminToken string) httpfx.Handler {
+	return func(ctx *httpfx.Context) httpfx.Result {
+		auth := ctx.Request.Header.Get(AuthHeader)
+
+		tokenStr, found := strings.CutPrefix(auth, "Bearer ")
+		if !found ||
+			subtle.ConstantTimeCompare([]byte(tokenStr), []byte(adminToken)) != 1 {
+			return ctx.Results.Unauthorized(httpfx.WithPlainText("Unauthorized"))
+		}
 
-		return result
+		return ctx.Next()
 	}
 }
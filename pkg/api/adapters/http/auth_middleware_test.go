@@ -0,0 +1,282 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx/middlewares"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	adminhttp "github.com/eser/aya.is-services/pkg/api/adapters/http"
+	"github.com/eser/aya.is-services/pkg/api/business/users"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// mintedKey stands for the key minted for a test case.
+	mintedKey = "minted"
+
+	testJWTSecret = "test-secret"
+)
+
+var errDatabaseUnavailable = errors.New("database unavailable")
+
+// fakeUsersRepository keeps the API keys in memory, keyed by the hash of the key, and
+// the sessions, keyed by their id.
+type fakeUsersRepository struct {
+	users.Repository
+
+	apiKeys   map[string]*users.APIKey
+	sessions  map[string]*users.Session
+	loggedIns []string
+	err       error
+}
+
+func (r *fakeUsersRepository) CreateAPIKey(
+	_ context.Context,
+	apiKey *users.APIKey,
+	keyHash string,
+) error {
+	r.apiKeys[keyHash] = apiKey
+
+	return nil
+}
+
+func (r *fakeUsersRepository) GetAPIKeyByHash(
+	_ context.Context,
+	keyHash string,
+) (*users.APIKey, error) {
+	return r.apiKeys[keyHash], r.err
+}
+
+func (r *fakeUsersRepository) UpdateAPIKeyLastUsedAt(
+	_ context.Context,
+	_ string,
+	_ time.Time,
+) error {
+	return nil
+}
+
+func (r *fakeUsersRepository) GetSessionByID(
+	_ context.Context,
+	id string,
+) (*users.Session, error) {
+	return r.sessions[id], r.err
+}
+
+func (r *fakeUsersRepository) UpdateSessionLoggedInAt(
+	_ context.Context,
+	id string,
+	_ time.Time,
+) error {
+	r.loggedIns = append(r.loggedIns, id)
+
+	return nil
+}
+
+func newTestUsersService(repo users.Repository) *users.Service {
+	return users.NewService(logfx.NewLogger(logfx.WithWriter(io.Discard)), repo, nil)
+}
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	t.Parallel()
+
+	past := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		repoErr      error
+		expiresAt    *time.Time
+		name         string
+		key          string
+		scopes       []string
+		expectedCode int
+		revoked      bool
+	}{
+		{
+			name:         "valid key",
+			key:          mintedKey,
+			scopes:       []string{"stories:write"},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "missing key",
+			key:          "",
+			scopes:       []string{"stories:write"},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "unknown key",
+			key:          "aya_unknown",
+			scopes:       []string{"stories:write"},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "revoked key",
+			key:          mintedKey,
+			scopes:       []string{"stories:write"},
+			revoked:      true,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "expired key",
+			key:          mintedKey,
+			scopes:       []string{"stories:write"},
+			expiresAt:    &past,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "key without the scope",
+			key:          mintedKey,
+			scopes:       []string{"stories:read"},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "failed lookup",
+			key:          mintedKey,
+			scopes:       []string{"stories:write"},
+			repoErr:      errDatabaseUnavailable,
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &fakeUsersRepository{apiKeys: map[string]*users.APIKey{}} //nolint:exhaustruct
+			usersService := newTestUsersService(repo)
+
+			apiKey, key, err := usersService.MintAPIKey(t.Context(), "deploy", tt.scopes, tt.expiresAt)
+			require.NoError(t, err)
+
+			if tt.revoked {
+				now := time.Now()
+				apiKey.RevokedAt = &now
+			}
+
+			repo.err = tt.repoErr
+
+			router := httpfx.NewRouter("/")
+			router.Use(adminhttp.APIKeyMiddleware(usersService))
+			router.Route("GET /stories", func(ctx *httpfx.Context) httpfx.Result {
+				identity, _ := httpfx.AuthIdentityFromContext(ctx.Request.Context())
+
+				return ctx.Results.PlainText([]byte(identity.Subject))
+			}).RequireScope("stories:write")
+
+			req := httptest.NewRequest(http.MethodGet, "/stories", nil)
+
+			header := tt.key
+			if header == mintedKey {
+				header = key
+			}
+
+			if header != "" {
+				req.Header.Set(adminhttp.APIKeyHeader, header)
+			}
+
+			res := httptest.NewRecorder()
+			router.GetMux().ServeHTTP(res, req)
+
+			assert.Equal(t, tt.expectedCode, res.Code)
+
+			if tt.expectedCode == http.StatusOK {
+				assert.Equal(t, "api_key:"+apiKey.ID, res.Body.String())
+			}
+		})
+	}
+}
+
+func TestSessionMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		repoErr          error
+		name             string
+		sessionID        string
+		status           string
+		expectedLoggedIn []string
+		expectedCode     int
+	}{
+		{
+			name:             "active session",
+			sessionID:        "sess-1",
+			status:           "active",
+			expectedCode:     http.StatusOK,
+			expectedLoggedIn: []string{"sess-1"},
+		},
+		{
+			name:         "revoked session",
+			sessionID:    "sess-1",
+			status:       "revoked",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "unknown session",
+			sessionID:    "sess-9",
+			status:       "active",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "token without a session",
+			sessionID:    "",
+			status:       "revoked",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "failed lookup",
+			sessionID:    "sess-1",
+			status:       "active",
+			repoErr:      errDatabaseUnavailable,
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &fakeUsersRepository{ //nolint:exhaustruct
+				sessions: map[string]*users.Session{
+					"sess-1": {ID: "sess-1", Status: tt.status}, //nolint:exhaustruct
+				},
+				err: tt.repoErr,
+			}
+
+			router := httpfx.NewRouter("/")
+			router.Use(middlewares.JWTAuthMiddleware(
+				middlewares.WithJWTSecret([]byte(testJWTSecret)),
+				middlewares.WithJWTSubjectClaim("user_id"),
+			))
+			router.Use(adminhttp.SessionMiddleware(newTestUsersService(repo)))
+			router.Route("GET /me", func(ctx *httpfx.Context) httpfx.Result {
+				return ctx.Results.PlainText([]byte("ok"))
+			}).RequireAuth()
+
+			claims := jwt.MapClaims{"user_id": "user-1", "exp": time.Now().Add(time.Hour).Unix()}
+			if tt.sessionID != "" {
+				claims["session_id"] = tt.sessionID
+			}
+
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).
+				SignedString([]byte(testJWTSecret))
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req.Header.Set(adminhttp.AuthHeader, "Bearer "+token)
+
+			res := httptest.NewRecorder()
+			router.GetMux().ServeHTTP(res, req)
+
+			assert.Equal(t, tt.expectedCode, res.Code)
+			assert.Equal(t, tt.expectedLoggedIn, repo.loggedIns)
+		})
+	}
+}
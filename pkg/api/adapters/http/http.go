@@ -3,13 +3,19 @@ package http
 import (
 	"context"
 
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
 	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
 	"github.com/eser/aya.is-services/pkg/ajan/httpfx/middlewares"
 	"github.com/eser/aya.is-services/pkg/ajan/httpfx/modules/healthcheck"
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx/modules/metrics"
 	"github.com/eser/aya.is-services/pkg/ajan/httpfx/modules/openapi"
 	"github.com/eser/aya.is-services/pkg/ajan/httpfx/modules/profiling"
 	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/ajan/processfx"
+	"github.com/eser/aya.is-services/pkg/api/adapters/appcontext"
+	"github.com/eser/aya.is-services/pkg/api/business/comments"
 	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/eser/aya.is-services/pkg/api/business/sitemaps"
 	"github.com/eser/aya.is-services/pkg/api/business/stories"
 	"github.com/eser/aya.is-services/pkg/api/business/users"
 )
@@ -17,29 +23,70 @@ import (
 func Run(
 	ctx context.Context,
 	config *httpfx.Config,
+	adminConfig *appcontext.AdminConfig,
+	authConfig *appcontext.AuthConfig,
+	localesConfig *appcontext.LocalesConfig,
 	logger *logfx.Logger,
+	connections *connfx.Registry,
 	profilesService *profiles.Service,
 	storiesService *stories.Service,
 	usersService *users.Service,
+	sitemapsService *sitemaps.Service,
+	commentsService *comments.Service,
+	requestLimits *middlewares.RequestLimits,
+	responseCache *middlewares.ResponseCache,
+	idempotency *middlewares.Idempotency,
+	commentRateLimiter httpfx.Handler,
+	process *processfx.Process,
 ) (func(), error) {
+	httpLogger := logger.WithScope("httpfx")
+
 	routes := httpfx.NewRouter("/")
-	httpService := httpfx.NewHTTPService(config, routes, logger)
+	httpService := httpfx.NewHTTPService(config, routes, httpLogger)
 
 	// http middlewares
 	routes.Use(middlewares.ErrorHandlerMiddleware())
+	routes.Use(requestLimits.Middleware())
 	routes.Use(middlewares.ResolveAddressMiddleware())
 	routes.Use(middlewares.ResponseTimeMiddleware())
-	routes.Use(middlewares.TracingMiddleware(logger)) //nolint:contextcheck
+	routes.Use(middlewares.TracingMiddleware(httpLogger)) //nolint:contextcheck
 	routes.Use(middlewares.CorsMiddleware())
 	routes.Use(middlewares.MetricsMiddleware(httpService.InnerMetrics)) //nolint:contextcheck
-	// routes.Use(AuthMiddleware(usersService))
+	routes.Use(middlewares.RecoveryMiddleware(
+		httpLogger,
+		middlewares.WithRecoveryMetrics(httpService.InnerMetrics),
+	))
+	routes.Use(middlewares.TimeoutMiddleware(config.RequestTimeout))
+	routes.Use(middlewares.JWTAuthMiddleware(jwtAuthOptions(authConfig)...))
+	routes.Use(SessionMiddleware(usersService))
+	routes.Use(APIKeyMiddleware(usersService))
+	routes.Use(middlewares.LocaleMiddleware(
+		middlewares.WithDefaultLocale(localesConfig.Default),
+		middlewares.WithSupportedLocales(localesConfig.Supported...),
+	))
+	routes.Use(idempotency.Middleware(middlewares.DefaultIdempotencyTTL))
 
 	// http modules
 	healthcheck.RegisterHTTPRoutes(routes, config)
 	openapi.RegisterHTTPRoutes(routes, config)
 	profiling.RegisterHTTPRoutes(routes, config)
+	metrics.RegisterHTTPRoutes(routes, config, logger)
 
 	// http routes
+	RegisterHTTPRoutesForHealth(
+		routes,
+		logger,
+		connections,
+		process,
+	)
+	RegisterHTTPRoutesForAdmin(
+		routes,
+		logger,
+		adminConfig.Token,
+		connections,
+		usersService,
+		profilesService,
+	)
 	RegisterHTTPRoutesForUsers( //nolint:contextcheck
 		routes,
 		logger,
@@ -49,19 +96,57 @@ func Run(
 		routes,
 		logger,
 		profilesService,
+		sitemapsService,
 	)
 	RegisterHTTPRoutesForProfiles( //nolint:contextcheck
 		routes,
 		logger,
 		profilesService,
 		storiesService,
+		responseCache,
 	)
 	RegisterHTTPRoutesForStories( //nolint:contextcheck
+		routes,
+		logger,
+		profilesService,
+		storiesService,
+	)
+	RegisterHTTPRoutesForComments( //nolint:contextcheck
+		routes,
+		logger,
+		commentsService,
+		commentRateLimiter,
+	)
+	RegisterHTTPRoutesForReactions( //nolint:contextcheck
 		routes,
 		logger,
 		storiesService,
+		commentsService,
 	)
 
 	// run
 	return httpService.Start(ctx) //nolint:wrapcheck
 }
+
+func jwtAuthOptions(authConfig *appcontext.AuthConfig) []middlewares.JWTAuthOption {
+	// Session tokens issued by the auth providers identify the user with "user_id".
+	options := []middlewares.JWTAuthOption{middlewares.WithJWTSubjectClaim("user_id")}
+
+	if authConfig.JWTSecret != "" {
+		options = append(options, middlewares.WithJWTSecret([]byte(authConfig.JWTSecret)))
+	}
+
+	if authConfig.JWKSURL != "" {
+		options = append(options, middlewares.WithJWKSURL(authConfig.JWKSURL))
+	}
+
+	if authConfig.Issuer != "" {
+		options = append(options, middlewares.WithJWTIssuer(authConfig.Issuer))
+	}
+
+	if authConfig.Audience != "" {
+		options = append(options, middlewares.WithJWTAudience(authConfig.Audience))
+	}
+
+	return options
+}
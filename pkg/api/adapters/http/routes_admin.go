@@ -0,0 +1,556 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/eser/aya.is-services/pkg/api/business/users"
+)
+
+var (
+	ErrInvalidConnectionTarget = errors.New("invalid connection target")
+	ErrInvalidRedriveRequest   = errors.New("invalid redrive request")
+	ErrInvalidMintRequest      = errors.New("invalid API key request")
+	ErrInvalidLogLevelRequest  = errors.New("invalid log level request")
+)
+
+// ConnectionTargetRequest is the payload for adding or reconfiguring a connection.
+type ConnectionTargetRequest struct {
+	Properties map[string]any    `json:"properties,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+
+	Protocol string `json:"protocol"`
+	DSN      string `json:"dsn,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Host     string `json:"host,omitempty"`
+	CertFile string `json:"cert_file,omitempty"`
+	KeyFile  string `json:"key_file,omitempty"`
+	CAFile   string `json:"ca_file,omitempty"`
+	Timeout  string `json:"timeout,omitempty"` // Go duration, e.g. "5s"
+
+	Port int `json:"port,omitempty"`
+
+	TLS           bool `json:"tls,omitempty"`
+	TLSSkipVerify bool `json:"tls_skip_verify,omitempty"`
+	Lazy          bool `json:"lazy,omitempty"`
+}
+
+// ConnectionInfoResponse describes a registered connection.
+type ConnectionInfoResponse struct {
+	Tags     map[string]string `json:"tags,omitempty"`
+	Name     string            `json:"name"`
+	Protocol string            `json:"protocol"`
+	State    string            `json:"state"`
+	Lazy     bool              `json:"lazy"`
+}
+
+// RedriveRequest is the payload for moving dead-lettered messages back to their queue.
+type RedriveRequest struct {
+	Queue string `json:"queue"`
+	Limit int    `json:"limit,omitempty"` // 0 moves every dead-lettered message
+}
+
+// RedriveResponse reports how many dead-lettered messages were moved back.
+type RedriveResponse struct {
+	Queue    string `json:"queue"`
+	Redriven int    `json:"redriven"`
+}
+
+// MintAPIKeyRequest is the payload for minting an API key.
+type MintAPIKeyRequest struct {
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes,omitempty"`
+}
+
+// MintAPIKeyResponse carries the minted key, which is not shown again.
+type MintAPIKeyResponse struct {
+	*users.APIKey
+
+	Key string `json:"key"`
+}
+
+// LogLevelRequest is the payload for changing a log level.
+type LogLevelRequest struct {
+	Level    string `json:"level"`              // e.g. "debug"
+	Duration string `json:"duration,omitempty"` // Go duration, e.g. "10m"; empty keeps it until reset
+}
+
+// LogLevelOverrideResponse describes a log level changed at runtime. Scope is empty
+// for the global level.
+type LogLevelOverrideResponse struct {
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Scope     string     `json:"scope,omitempty"`
+	Level     string     `json:"level"`
+}
+
+// LogLevelsResponse describes the global log level and the levels changed at runtime.
+type LogLevelsResponse struct {
+	Level     string                     `json:"level"`
+	Overrides []LogLevelOverrideResponse `json:"overrides"`
+}
+
+func RegisterHTTPRoutesForAdmin( //nolint:funlen
+	routes *httpfx.Router,
+	logger *logfx.Logger,
+	adminToken string,
+	connections *connfx.Registry,
+	usersService *users.Service,
+	profilesService *profiles.Service,
+) {
+	if adminToken == "" {
+		return
+	}
+
+	admin := routes.Group("/admin", AdminTokenMiddleware(adminToken))
+
+	registerHTTPRoutesForAdminAPIKeys(admin, logger, usersService)
+	registerHTTPRoutesForAdminLogLevels(admin, logger)
+	registerHTTPRoutesForAdminBadges(admin, logger, profilesService)
+
+	admin.
+		Route("GET /connections", func(ctx *httpfx.Context) httpfx.Result {
+			infos := connections.ListConnectionInfo()
+
+			response := make([]ConnectionInfoResponse, 0, len(infos))
+			for _, info := range infos {
+				response = append(response, newConnectionInfoResponse(info))
+			}
+
+			return ctx.Results.JSON(response)
+		}).
+		HasSummary("List connections").
+		HasDescription("List registered connections with their protocol, state and tags.").
+		HasResponse(http.StatusOK)
+
+	admin.
+		Route("POST /connections/{name}", func(ctx *httpfx.Context) httpfx.Result {
+			nameParam := ctx.Request.PathValue("name")
+
+			target, err := decodeConnectionTarget(ctx.Request)
+			if err != nil {
+				return ctx.Results.BadRequest(httpfx.WithPlainText(err.Error()))
+			}
+
+			_, err = connections.AddConnection(ctx.Request.Context(), nameParam, target)
+			if err != nil {
+				return connectionErrorResult(ctx, err)
+			}
+
+			logger.InfoContext(
+				ctx.Request.Context(),
+				"connection added via admin API",
+				slog.String("name", nameParam),
+				slog.String("protocol", target.Protocol),
+			)
+
+			return ctx.Results.JSON(findConnectionInfo(connections, nameParam))
+		}).
+		HasSummary("Add connection").
+		HasDescription("Add and dial a new connection at runtime.").
+		HasRequestModel(ConnectionTargetRequest{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+
+	admin.
+		Route("PUT /connections/{name}", func(ctx *httpfx.Context) httpfx.Result {
+			nameParam := ctx.Request.PathValue("name")
+
+			target, err := decodeConnectionTarget(ctx.Request)
+			if err != nil {
+				return ctx.Results.BadRequest(httpfx.WithPlainText(err.Error()))
+			}
+
+			_, err = connections.ReconfigureConnection(ctx.Request.Context(), nameParam, target)
+			if err != nil {
+				return connectionErrorResult(ctx, err)
+			}
+
+			logger.InfoContext(
+				ctx.Request.Context(),
+				"connection reconfigured via admin API",
+				slog.String("name", nameParam),
+				slog.String("protocol", target.Protocol),
+			)
+
+			return ctx.Results.JSON(findConnectionInfo(connections, nameParam))
+		}).
+		HasSummary("Reconfigure connection").
+		HasDescription("Replace an existing connection with one built from a new configuration.").
+		HasRequestModel(ConnectionTargetRequest{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+
+	admin.
+		Route("DELETE /connections/{name}", func(ctx *httpfx.Context) httpfx.Result {
+			nameParam := ctx.Request.PathValue("name")
+
+			err := connections.RemoveConnection(ctx.Request.Context(), nameParam)
+			if err != nil {
+				return connectionErrorResult(ctx, err)
+			}
+
+			logger.InfoContext(
+				ctx.Request.Context(),
+				"connection removed via admin API",
+				slog.String("name", nameParam),
+			)
+
+			return ctx.Results.Ok()
+		}).
+		HasSummary("Remove connection").
+		HasDescription("Close and remove a connection.").
+		HasResponse(http.StatusNoContent)
+
+	admin.
+		Route("POST /connections/{name}/redrive", func(ctx *httpfx.Context) httpfx.Result {
+			nameParam := ctx.Request.PathValue("name")
+
+			var payload RedriveRequest
+
+			if err := json.NewDecoder(ctx.Request.Body).Decode(&payload); err != nil || payload.Queue == "" {
+				return ctx.Results.BadRequest(
+					httpfx.WithPlainText(fmt.Sprintf("%s: queue is required", ErrInvalidRedriveRequest)),
+				)
+			}
+
+			conn := connections.GetNamed(nameParam)
+			if conn == nil {
+				return connectionErrorResult(
+					ctx,
+					fmt.Errorf("%w (name=%q)", connfx.ErrConnectionNotFound, nameParam),
+				)
+			}
+
+			redriver, ok := conn.GetRawConnection().(connfx.DeadLetterRedriver)
+			if !ok {
+				return ctx.Results.BadRequest(httpfx.WithPlainText(
+					fmt.Sprintf("%s (name=%q, operation=\"redrive\")", connfx.ErrConnectionNotSupported, nameParam),
+				))
+			}
+
+			redriven, err := redriver.RedriveDeadLetters(ctx.Request.Context(), payload.Queue, payload.Limit)
+
+			logger.InfoContext(
+				ctx.Request.Context(),
+				"dead letters redriven via admin API",
+				slog.String("name", nameParam),
+				slog.String("queue", payload.Queue),
+				slog.Int("redriven", redriven),
+			)
+
+			if err != nil {
+				return connectionErrorResult(ctx, err)
+			}
+
+			return ctx.Results.JSON(RedriveResponse{Queue: payload.Queue, Redriven: redriven})
+		}).
+		HasSummary("Redrive dead letters").
+		HasDescription("Move dead-lettered messages of a queue back to the queue.").
+		HasRequestModel(RedriveRequest{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+}
+
+func registerHTTPRoutesForAdminAPIKeys(
+	admin *httpfx.Router,
+	logger *logfx.Logger,
+	usersService *users.Service,
+) {
+	admin.
+		Route("GET /api-keys", func(ctx *httpfx.Context) httpfx.Result {
+			records, err := usersService.ListAPIKeys(ctx.Request.Context())
+			if err != nil {
+				return ctx.Results.Error(
+					http.StatusInternalServerError,
+					httpfx.WithPlainText(err.Error()),
+				)
+			}
+
+			return ctx.Results.JSON(records)
+		}).
+		HasSummary("List API keys").
+		HasDescription("List API keys with their scopes and usage, without the keys themselves.").
+		HasResponse(http.StatusOK)
+
+	admin.
+		Route("POST /api-keys", func(ctx *httpfx.Context) httpfx.Result {
+			var payload MintAPIKeyRequest
+
+			if err := json.NewDecoder(ctx.Request.Body).Decode(&payload); err != nil || payload.Name == "" {
+				return ctx.Results.BadRequest(
+					httpfx.WithPlainText(fmt.Sprintf("%s: name is required", ErrInvalidMintRequest)),
+				)
+			}
+
+			apiKey, key, err := usersService.MintAPIKey(
+				ctx.Request.Context(),
+				payload.Name,
+				payload.Scopes,
+				payload.ExpiresAt,
+			)
+			if err != nil {
+				return ctx.Results.Error(
+					http.StatusInternalServerError,
+					httpfx.WithPlainText(err.Error()),
+				)
+			}
+
+			logger.InfoContext(
+				ctx.Request.Context(),
+				"API key minted via admin API",
+				slog.String("id", apiKey.ID),
+				slog.String("name", apiKey.Name),
+				slog.Any("scopes", apiKey.Scopes),
+			)
+
+			return ctx.Results.JSON(MintAPIKeyResponse{APIKey: apiKey, Key: key})
+		}).
+		HasSummary("Mint API key").
+		HasDescription("Create an API key. The key is only returned in this response.").
+		HasRequestModel(MintAPIKeyRequest{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+
+	admin.
+		Route("DELETE /api-keys/{id}", func(ctx *httpfx.Context) httpfx.Result {
+			idParam := ctx.Request.PathValue("id")
+
+			err := usersService.RevokeAPIKey(ctx.Request.Context(), idParam)
+			if err != nil {
+				if errors.Is(err, users.ErrAPIKeyNotFound) {
+					return ctx.Results.NotFound(httpfx.WithPlainText(err.Error()))
+				}
+
+				return ctx.Results.Error(
+					http.StatusInternalServerError,
+					httpfx.WithPlainText(err.Error()),
+				)
+			}
+
+			logger.InfoContext(
+				ctx.Request.Context(),
+				"API key revoked via admin API",
+				slog.String("id", idParam),
+			)
+
+			return ctx.Results.Ok()
+		}).
+		HasSummary("Revoke API key").
+		HasDescription("Revoke an API key; requests using it are rejected from then on.").
+		HasResponse(http.StatusNoContent)
+}
+
+func registerHTTPRoutesForAdminLogLevels(admin *httpfx.Router, logger *logfx.Logger) {
+	levels := logger.Levels()
+	if levels == nil {
+		return
+	}
+
+	admin.
+		Route("GET /log-levels", func(ctx *httpfx.Context) httpfx.Result {
+			return ctx.Results.JSON(newLogLevelsResponse(levels))
+		}).
+		HasSummary("List log levels").
+		HasDescription("List the global log level and the levels changed at runtime.").
+		HasResponseModel(http.StatusOK, LogLevelsResponse{}) //nolint:exhaustruct
+
+	setLevel := func(ctx *httpfx.Context) httpfx.Result {
+		scopeParam := ctx.Request.PathValue("scope")
+
+		level, duration, err := decodeLogLevelRequest(ctx.Request)
+		if err != nil {
+			return ctx.Results.BadRequest(httpfx.WithPlainText(err.Error()))
+		}
+
+		levels.SetLevel(scopeParam, level, duration)
+
+		logger.InfoContext(
+			ctx.Request.Context(),
+			"log level changed via admin API",
+			slog.String("scope", scopeParam),
+			slog.String("level", logfx.LevelEncoder(level)),
+			slog.Duration("duration", duration),
+		)
+
+		return ctx.Results.JSON(newLogLevelsResponse(levels))
+	}
+
+	resetLevel := func(ctx *httpfx.Context) httpfx.Result {
+		scopeParam := ctx.Request.PathValue("scope")
+
+		levels.ResetLevel(scopeParam)
+
+		logger.InfoContext(
+			ctx.Request.Context(),
+			"log level reset via admin API",
+			slog.String("scope", scopeParam),
+		)
+
+		return ctx.Results.JSON(newLogLevelsResponse(levels))
+	}
+
+	admin.
+		Route("PUT /log-levels", setLevel).
+		HasSummary("Change global log level").
+		HasDescription(
+			"Change the level of the scopes without a level of their own, for a duration or until reset.",
+		).
+		HasRequestModel(LogLevelRequest{}).                  //nolint:exhaustruct
+		HasResponseModel(http.StatusOK, LogLevelsResponse{}) //nolint:exhaustruct
+
+	admin.
+		Route("PUT /log-levels/{scope}", setLevel).
+		HasSummary("Change scope log level").
+		HasDescription("Change the level of a scope, e.g. connfx, for a duration or until reset.").
+		HasRequestModel(LogLevelRequest{}).                  //nolint:exhaustruct
+		HasResponseModel(http.StatusOK, LogLevelsResponse{}) //nolint:exhaustruct
+
+	admin.
+		Route("DELETE /log-levels", resetLevel).
+		HasSummary("Reset global log level").
+		HasDescription("Restore the configured global log level.").
+		HasResponseModel(http.StatusOK, LogLevelsResponse{}) //nolint:exhaustruct
+
+	admin.
+		Route("DELETE /log-levels/{scope}", resetLevel).
+		HasSummary("Reset scope log level").
+		HasDescription("Restore the level of a scope to the global one.").
+		HasResponseModel(http.StatusOK, LogLevelsResponse{}) //nolint:exhaustruct
+}
+
+func decodeLogLevelRequest(req *http.Request) (slog.Level, time.Duration, error) {
+	var payload LogLevelRequest
+
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil || payload.Level == "" {
+		return 0, 0, fmt.Errorf("%w: level is required", ErrInvalidLogLevelRequest)
+	}
+
+	level, err := logfx.ParseLevel(payload.Level, true)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w (level=%q): %w", ErrInvalidLogLevelRequest, payload.Level, err)
+	}
+
+	var duration time.Duration
+
+	if payload.Duration != "" {
+		duration, err = time.ParseDuration(payload.Duration)
+		if err != nil || duration < 0 {
+			return 0, 0, fmt.Errorf("%w (duration=%q)", ErrInvalidLogLevelRequest, payload.Duration)
+		}
+	}
+
+	return *level, duration, nil
+}
+
+func newLogLevelsResponse(levels *logfx.LevelController) LogLevelsResponse {
+	overrides := levels.Overrides()
+
+	response := LogLevelsResponse{
+		Level:     logfx.LevelEncoder(levels.Level()),
+		Overrides: make([]LogLevelOverrideResponse, 0, len(overrides)),
+	}
+
+	for _, scope := range slices.Sorted(maps.Keys(overrides)) {
+		override := overrides[scope]
+
+		item := LogLevelOverrideResponse{
+			ExpiresAt: nil,
+			Scope:     scope,
+			Level:     logfx.LevelEncoder(override.Level),
+		}
+
+		if !override.ExpiresAt.IsZero() {
+			item.ExpiresAt = &override.ExpiresAt
+		}
+
+		response.Overrides = append(response.Overrides, item)
+	}
+
+	return response
+}
+
+func decodeConnectionTarget(req *http.Request) (*connfx.ConfigTarget, error) {
+	var payload ConnectionTargetRequest
+
+	decoder := json.NewDecoder(req.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConnectionTarget, err)
+	}
+
+	if payload.Protocol == "" {
+		return nil, fmt.Errorf("%w: protocol is required", ErrInvalidConnectionTarget)
+	}
+
+	var timeout time.Duration
+
+	if payload.Timeout != "" {
+		parsed, err := time.ParseDuration(payload.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("%w (timeout=%q): %w", ErrInvalidConnectionTarget, payload.Timeout, err)
+		}
+
+		timeout = parsed
+	}
+
+	return &connfx.ConfigTarget{
+		Properties:    payload.Properties,
+		Tags:          payload.Tags,
+		Protocol:      payload.Protocol,
+		DSN:           payload.DSN,
+		URL:           payload.URL,
+		Host:          payload.Host,
+		CertFile:      payload.CertFile,
+		KeyFile:       payload.KeyFile,
+		CAFile:        payload.CAFile,
+		Port:          payload.Port,
+		Timeout:       timeout,
+		TLS:           payload.TLS,
+		TLSSkipVerify: payload.TLSSkipVerify,
+		Lazy:          payload.Lazy,
+	}, nil
+}
+
+func connectionErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	switch {
+	case errors.Is(err, connfx.ErrConnectionNotFound):
+		return ctx.Results.NotFound(httpfx.WithPlainText(err.Error()))
+	case errors.Is(err, connfx.ErrConnectionAlreadyExists):
+		return ctx.Results.Error(http.StatusConflict, httpfx.WithPlainText(err.Error()))
+	case errors.Is(err, connfx.ErrUnsupportedProtocol):
+		return ctx.Results.BadRequest(httpfx.WithPlainText(err.Error()))
+	default:
+		return ctx.Results.Error(
+			http.StatusBadGateway,
+			httpfx.WithPlainText(err.Error()),
+		)
+	}
+}
+
+func findConnectionInfo(connections *connfx.Registry, name string) ConnectionInfoResponse {
+	for _, info := range connections.ListConnectionInfo() {
+		if info.Name == name {
+			return newConnectionInfoResponse(info)
+		}
+	}
+
+	return ConnectionInfoResponse{Name: name} //nolint:exhaustruct
+}
+
+func newConnectionInfoResponse(info connfx.ConnectionInfo) ConnectionInfoResponse {
+	return ConnectionInfoResponse{
+		Tags:     info.Tags,
+		Name:     info.Name,
+		Protocol: info.Protocol,
+		State:    info.State.String(),
+		Lazy:     info.Lazy,
+	}
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+)
+
+// GrantBadgeRequest is the payload for granting a badge to a profile.
+type GrantBadgeRequest struct {
+	Note *string `json:"note,omitempty"`
+	Kind string  `json:"kind"` // e.g. "verified"
+}
+
+func registerHTTPRoutesForAdminBadges(
+	admin *httpfx.Router,
+	logger *logfx.Logger,
+	profilesService *profiles.Service,
+) {
+	admin.
+		Route("GET /profiles/{slug}/badges", func(ctx *httpfx.Context) httpfx.Result {
+			slugParam := ctx.Request.PathValue("slug")
+
+			records, err := profilesService.ListBadgesBySlug(ctx.Request.Context(), slugParam)
+			if err != nil {
+				return profileWriteErrorResult(ctx, err)
+			}
+
+			return ctx.Results.JSON(records)
+		}).
+		HasSummary("List profile badges").
+		HasDescription("List the badges a profile holds, granted by admins or awarded by rules.").
+		HasResponse(http.StatusOK)
+
+	admin.
+		Route("POST /profiles/{slug}/badges", func(ctx *httpfx.Context) httpfx.Result {
+			slugParam := ctx.Request.PathValue("slug")
+
+			var payload GrantBadgeRequest
+
+			if err := json.NewDecoder(ctx.Request.Body).Decode(&payload); err != nil || payload.Kind == "" {
+				return ctx.Results.BadRequest(
+					httpfx.WithPlainText(fmt.Sprintf("%s: kind is required", profiles.ErrInvalidBadgeKind)),
+				)
+			}
+
+			badge, err := profilesService.GrantBadge(
+				ctx.Request.Context(),
+				slugParam,
+				payload.Kind,
+				payload.Note,
+			)
+			if err != nil {
+				return profileWriteErrorResult(ctx, err)
+			}
+
+			logger.InfoContext(
+				ctx.Request.Context(),
+				"badge granted via admin API",
+				slog.String("slug", slugParam),
+				slog.String("kind", badge.Kind),
+			)
+
+			return ctx.Results.JSON(badge)
+		}).
+		HasSummary("Grant profile badge").
+		HasDescription(
+			"Grant a badge to a profile. Award rules do not revoke the badges admins grant.",
+		).
+		HasRequestModel(GrantBadgeRequest{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+
+	admin.
+		Route("DELETE /profiles/{slug}/badges/{kind}", func(ctx *httpfx.Context) httpfx.Result {
+			slugParam := ctx.Request.PathValue("slug")
+			kindParam := ctx.Request.PathValue("kind")
+
+			err := profilesService.RevokeBadge(ctx.Request.Context(), slugParam, kindParam)
+			if err != nil {
+				return profileWriteErrorResult(ctx, err)
+			}
+
+			logger.InfoContext(
+				ctx.Request.Context(),
+				"badge revoked via admin API",
+				slog.String("slug", slugParam),
+				slog.String("kind", kindParam),
+			)
+
+			return ctx.Results.Ok()
+		}).
+		HasSummary("Revoke profile badge").
+		HasDescription(
+			"Revoke a badge from a profile. Award rules do not award it to the profile again.",
+		).
+		HasResponse(http.StatusNoContent)
+}
@@ -0,0 +1,202 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	adminhttp "github.com/eser/aya.is-services/pkg/api/adapters/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const adminToken = "admin-secret"
+
+var errBrokerUnavailable = errors.New("broker unavailable")
+
+// fakeRedriverConnection is a connection whose raw connection redrives dead letters.
+type fakeRedriverConnection struct {
+	err      error
+	queue    string
+	limit    int
+	redriven int
+}
+
+func (c *fakeRedriverConnection) GetBehaviors() []connfx.ConnectionBehavior {
+	return []connfx.ConnectionBehavior{connfx.ConnectionBehaviorStateful}
+}
+
+func (c *fakeRedriverConnection) GetCapabilities() []connfx.ConnectionCapability {
+	return []connfx.ConnectionCapability{connfx.ConnectionCapabilityQueue}
+}
+
+func (c *fakeRedriverConnection) GetProtocol() string {
+	return "fake-amqp"
+}
+
+func (c *fakeRedriverConnection) GetState() connfx.ConnectionState {
+	return connfx.ConnectionStateReady
+}
+
+func (c *fakeRedriverConnection) HealthCheck(_ context.Context) *connfx.HealthStatus {
+	return &connfx.HealthStatus{ //nolint:exhaustruct
+		Timestamp: time.Now(),
+		State:     connfx.ConnectionStateReady,
+	}
+}
+
+func (c *fakeRedriverConnection) Close(_ context.Context) error {
+	return nil
+}
+
+func (c *fakeRedriverConnection) GetRawConnection() any {
+	return c
+}
+
+func (c *fakeRedriverConnection) RedriveDeadLetters(
+	_ context.Context,
+	queueName string,
+	limit int,
+) (int, error) {
+	c.queue = queueName
+	c.limit = limit
+
+	return c.redriven, c.err
+}
+
+// fakeRedriverFactory hands out its one connection.
+type fakeRedriverFactory struct {
+	conn *fakeRedriverConnection
+}
+
+func (f *fakeRedriverFactory) CreateConnection( //nolint:ireturn
+	_ context.Context,
+	_ *connfx.ConfigTarget,
+) (connfx.Connection, error) {
+	return f.conn, nil
+}
+
+func (f *fakeRedriverFactory) GetProtocol() string {
+	return "fake-amqp"
+}
+
+// newAdminRouter registers the admin routes on a registry with an "events" connection
+// that redrives dead letters and a "cache" connection that does not.
+func newAdminRouter(t *testing.T, conn *fakeRedriverConnection) *httpfx.Router {
+	t.Helper()
+
+	logger := logfx.NewLogger(logfx.WithWriter(io.Discard))
+
+	registry := connfx.NewRegistry(connfx.WithLogger(logger))
+	registry.RegisterFactory(&fakeRedriverFactory{conn: conn})
+	registry.RegisterFactory(connfx.NewInMemoryConnectionFactory("inmemory"))
+
+	_, err := registry.AddConnection(t.Context(), "events", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "fake-amqp",
+	})
+	require.NoError(t, err)
+
+	_, err = registry.AddConnection(t.Context(), "cache", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "inmemory",
+	})
+	require.NoError(t, err)
+
+	router := httpfx.NewRouter("/")
+	adminhttp.RegisterHTTPRoutesForAdmin(router, logger, adminToken, registry, nil, nil)
+
+	return router
+}
+
+func TestAdminRedrive(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err          error
+		name         string
+		connection   string
+		body         string
+		token        string
+		expectedBody string
+		expectedCode int
+	}{
+		{
+			name:         "redriven",
+			connection:   "events",
+			body:         `{"queue":"emails","limit":5}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"queue":"emails","redriven":3}`,
+		},
+		{
+			name:         "no admin token",
+			connection:   "events",
+			body:         `{"queue":"emails"}`,
+			token:        "wrong",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "no queue",
+			connection:   "events",
+			body:         `{"limit":5}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "unknown connection",
+			connection:   "missing",
+			body:         `{"queue":"emails"}`,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "connection without dead letters",
+			connection:   "cache",
+			body:         `{"queue":"emails"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "broker failure",
+			connection:   "events",
+			body:         `{"queue":"emails"}`,
+			err:          errBrokerUnavailable,
+			expectedCode: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			conn := &fakeRedriverConnection{redriven: 3, err: tt.err} //nolint:exhaustruct
+			router := newAdminRouter(t, conn)
+
+			token := adminToken
+			if tt.token != "" {
+				token = tt.token
+			}
+
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/admin/connections/"+tt.connection+"/redrive",
+				strings.NewReader(tt.body),
+			)
+			req.Header.Set(adminhttp.AuthHeader, "Bearer "+token)
+
+			res := httptest.NewRecorder()
+			router.GetMux().ServeHTTP(res, req)
+
+			assert.Equal(t, tt.expectedCode, res.Code)
+
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, res.Body.String())
+				assert.Equal(t, "emails", conn.queue)
+				assert.Equal(t, 5, conn.limit)
+			}
+		})
+	}
+}
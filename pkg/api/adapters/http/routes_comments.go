@@ -0,0 +1,290 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/api/business/comments"
+	"github.com/eser/aya.is-services/pkg/lib/cursors"
+)
+
+var ErrInvalidCommentRequest = errors.New("invalid comment request")
+
+// CommentStatusRequest is the payload for hiding a comment or showing it again.
+type CommentStatusRequest struct {
+	Status string `json:"status"` // e.g. "hidden"
+}
+
+// CommentReportRequest is the payload for reporting a comment to the moderators.
+type CommentReportRequest struct {
+	Reason string `json:"reason"`
+}
+
+func RegisterHTTPRoutesForComments( //nolint:funlen
+	routes *httpfx.Router,
+	logger *logfx.Logger,
+	commentsService *comments.Service,
+	rateLimiter httpfx.Handler,
+) {
+	routes.
+		Route("GET /{locale}/stories/{slug}/comments", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			slugParam := ctx.Request.PathValue("slug")
+			cursor := cursors.NewCursorFromRequest(ctx.Request)
+
+			userID, _ := userIDFromRequest(ctx)
+
+			records, err := commentsService.List(ctx.Request.Context(), userID, slugParam, nil, cursor)
+			if err != nil {
+				return commentErrorResult(ctx, err)
+			}
+
+			return ctx.Results.JSON(records)
+		}).
+		HasSummary("List story comments").
+		HasDescription(
+			"List the comments on a story, the earliest first, without their replies. " +
+				"The moderators of the story list the reported ones with filter_reported=true.",
+		).
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route(
+			"GET /{locale}/stories/{slug}/comments/{commentId}/replies",
+			func(ctx *httpfx.Context) httpfx.Result {
+				// get variables from path
+				slugParam := ctx.Request.PathValue("slug")
+				commentIDParam := ctx.Request.PathValue("commentId")
+				cursor := cursors.NewCursorFromRequest(ctx.Request)
+
+				userID, _ := userIDFromRequest(ctx)
+
+				records, err := commentsService.List(
+					ctx.Request.Context(),
+					userID,
+					slugParam,
+					&commentIDParam,
+					cursor,
+				)
+				if err != nil {
+					return commentErrorResult(ctx, err)
+				}
+
+				return ctx.Results.JSON(records)
+			},
+		).
+		HasSummary("List comment replies").
+		HasDescription("List the replies of a comment on a story, the earliest first.").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route(
+			"POST /{locale}/stories/{slug}/comments",
+			rateLimiter,
+			func(ctx *httpfx.Context) httpfx.Result {
+				// get variables from path
+				slugParam := ctx.Request.PathValue("slug")
+
+				userID, ok := userIDFromRequest(ctx)
+				if !ok {
+					return ctx.Results.Error(
+						http.StatusForbidden,
+						httpfx.WithPlainText("A user session is required"),
+					)
+				}
+
+				var payload comments.CreateCommentInput
+
+				if err := json.NewDecoder(ctx.Request.Body).Decode(&payload); err != nil {
+					return ctx.Results.BadRequest(
+						httpfx.WithPlainText(fmt.Sprintf("%s: %s", ErrInvalidCommentRequest, err)),
+					)
+				}
+
+				record, err := commentsService.Create(ctx.Request.Context(), userID, slugParam, &payload)
+				if err != nil {
+					return commentErrorResult(ctx, err)
+				}
+
+				wrappedResponse := cursors.WrapResponseWithCursor(record, nil)
+
+				return ctx.Results.JSON(wrappedResponse)
+			},
+		).
+		HasSummary("Post comment").
+		HasDescription(
+			"Post a comment on a published story, or a reply to a comment with parent_id. " +
+				"Each user may post a few comments and reports a minute.",
+		).
+		HasRequestModel(comments.CreateCommentInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK).
+		RequireAuth()
+
+	routes.
+		Route(
+			"PUT /{locale}/stories/{slug}/comments/{commentId}/status",
+			func(ctx *httpfx.Context) httpfx.Result {
+				// get variables from path
+				slugParam := ctx.Request.PathValue("slug")
+				commentIDParam := ctx.Request.PathValue("commentId")
+
+				userID, ok := userIDFromRequest(ctx)
+				if !ok {
+					return ctx.Results.Error(
+						http.StatusForbidden,
+						httpfx.WithPlainText("A user session is required"),
+					)
+				}
+
+				var payload CommentStatusRequest
+
+				if err := json.NewDecoder(ctx.Request.Body).Decode(&payload); err != nil || payload.Status == "" {
+					return ctx.Results.BadRequest(
+						httpfx.WithPlainText(fmt.Sprintf("%s: status is required", ErrInvalidCommentRequest)),
+					)
+				}
+
+				record, err := commentsService.SetStatus(
+					ctx.Request.Context(),
+					userID,
+					slugParam,
+					commentIDParam,
+					payload.Status,
+				)
+				if err != nil {
+					return commentErrorResult(ctx, err)
+				}
+
+				logger.InfoContext(
+					ctx.Request.Context(),
+					"comment moderated",
+					slog.String("slug", slugParam),
+					slog.String("comment_id", commentIDParam),
+					slog.String("status", payload.Status),
+					slog.String("user_id", userID),
+				)
+
+				wrappedResponse := cursors.WrapResponseWithCursor(record, nil)
+
+				return ctx.Results.JSON(wrappedResponse)
+			},
+		).
+		HasSummary("Moderate comment").
+		HasDescription(
+			"Hide a comment, or show a hidden one again, and resolve its reports. " +
+				"Only the owners of the author profile of the story may.",
+		).
+		HasRequestModel(CommentStatusRequest{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK).
+		RequireAuth()
+
+	routes.
+		Route(
+			"DELETE /{locale}/stories/{slug}/comments/{commentId}",
+			func(ctx *httpfx.Context) httpfx.Result {
+				// get variables from path
+				slugParam := ctx.Request.PathValue("slug")
+				commentIDParam := ctx.Request.PathValue("commentId")
+
+				userID, ok := userIDFromRequest(ctx)
+				if !ok {
+					return ctx.Results.Error(
+						http.StatusForbidden,
+						httpfx.WithPlainText("A user session is required"),
+					)
+				}
+
+				err := commentsService.Delete(ctx.Request.Context(), userID, slugParam, commentIDParam)
+				if err != nil {
+					return commentErrorResult(ctx, err)
+				}
+
+				logger.InfoContext(
+					ctx.Request.Context(),
+					"comment deleted",
+					slog.String("slug", slugParam),
+					slog.String("comment_id", commentIDParam),
+					slog.String("user_id", userID),
+				)
+
+				return ctx.Results.Ok()
+			},
+		).
+		HasSummary("Delete comment").
+		HasDescription(
+			"Delete a comment; its replies are kept. " +
+				"Its author and the owners of the author profile of the story may.",
+		).
+		HasResponse(http.StatusNoContent).
+		RequireAuth()
+
+	routes.
+		Route(
+			"POST /{locale}/stories/{slug}/comments/{commentId}/reports",
+			rateLimiter,
+			func(ctx *httpfx.Context) httpfx.Result {
+				// get variables from path
+				slugParam := ctx.Request.PathValue("slug")
+				commentIDParam := ctx.Request.PathValue("commentId")
+
+				userID, ok := userIDFromRequest(ctx)
+				if !ok {
+					return ctx.Results.Error(
+						http.StatusForbidden,
+						httpfx.WithPlainText("A user session is required"),
+					)
+				}
+
+				var payload CommentReportRequest
+
+				if err := json.NewDecoder(ctx.Request.Body).Decode(&payload); err != nil {
+					return ctx.Results.BadRequest(
+						httpfx.WithPlainText(fmt.Sprintf("%s: %s", ErrInvalidCommentRequest, err)),
+					)
+				}
+
+				err := commentsService.Report(
+					ctx.Request.Context(),
+					userID,
+					slugParam,
+					commentIDParam,
+					payload.Reason,
+				)
+				if err != nil {
+					return commentErrorResult(ctx, err)
+				}
+
+				return ctx.Results.Ok()
+			},
+		).
+		HasSummary("Report comment").
+		HasDescription("Report a comment to the moderators of the story.").
+		HasRequestModel(CommentReportRequest{}). //nolint:exhaustruct
+		HasResponse(http.StatusNoContent).
+		RequireAuth()
+}
+
+func commentErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	switch {
+	case errors.Is(err, comments.ErrInvalidCommentInput):
+		return ctx.Results.BadRequest(httpfx.WithPlainText(err.Error()))
+	case errors.Is(err, comments.ErrStoryNotFound),
+		errors.Is(err, comments.ErrCommentNotFound):
+		return ctx.Results.NotFound(httpfx.WithPlainText(err.Error()))
+	case errors.Is(err, comments.ErrNotModerator):
+		return ctx.Results.Error(http.StatusForbidden, httpfx.WithPlainText(err.Error()))
+	case errors.Is(err, comments.ErrCommentsClosed),
+		errors.Is(err, comments.ErrAlreadyReported):
+		return ctx.Results.Error(http.StatusConflict, httpfx.WithPlainText(err.Error()))
+	default:
+		return ctx.Results.Error(
+			http.StatusInternalServerError,
+			httpfx.WithPlainText(err.Error()),
+		)
+	}
+}
@@ -0,0 +1,200 @@
+package http
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/ajan/processfx"
+)
+
+// readinessCheckTimeout bounds the health checks of a readiness probe, which are
+// usually given a few seconds by orchestrators.
+const readinessCheckTimeout = 2 * time.Second
+
+// Readiness statuses.
+const (
+	ReadinessStatusReady    = "ready"
+	ReadinessStatusDegraded = "degraded"
+	ReadinessStatusNotReady = "not_ready"
+)
+
+// DependencyStatusResponse describes the health of a single connection.
+type DependencyStatusResponse struct {
+	Name      string `json:"name"`
+	Protocol  string `json:"protocol"`
+	Tier      string `json:"tier"`
+	State     string `json:"state"`
+	Message   string `json:"message,omitempty"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+	Healthy   bool   `json:"healthy"`
+	Lazy      bool   `json:"lazy,omitempty"`
+}
+
+// GoroutineStatusResponse describes a goroutine of the process, e.g. a queue consumer.
+type GoroutineStatusResponse struct {
+	Name     string `json:"name"`
+	State    string `json:"state"`
+	Error    string `json:"error,omitempty"`
+	Restarts int    `json:"restarts"`
+}
+
+// ReadinessResponse aggregates the health of the connections and of the goroutines of
+// the process.
+type ReadinessResponse struct {
+	Status       string                     `json:"status"`
+	Dependencies []DependencyStatusResponse `json:"dependencies"`
+	Goroutines   []GoroutineStatusResponse  `json:"goroutines"`
+}
+
+func RegisterHTTPRoutesForHealth(
+	routes *httpfx.Router,
+	logger *logfx.Logger,
+	connections *connfx.Registry,
+	process *processfx.Process,
+) {
+	routes.
+		Route("GET /healthz", func(ctx *httpfx.Context) httpfx.Result {
+			ctx.ResponseWriter.Header().Set("Cache-Control", "no-store")
+
+			return ctx.Results.JSON(map[string]string{"status": "ok"})
+		}).
+		HasSummary("Liveness probe").
+		HasDescription("Reports that the process is up. It does not check the dependencies.").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("GET /readyz", func(ctx *httpfx.Context) httpfx.Result {
+			ctx.ResponseWriter.Header().Set("Cache-Control", "no-store")
+
+			checkCtx, cancel := context.WithTimeout(ctx.Request.Context(), readinessCheckTimeout)
+			defer cancel()
+
+			response := checkReadiness(checkCtx, connections, process)
+
+			if response.Status == ReadinessStatusNotReady {
+				logger.WarnContext(
+					ctx.Request.Context(),
+					"readiness check failed",
+					slog.Any("dependencies", response.Dependencies),
+					slog.Any("goroutines", response.Goroutines),
+				)
+
+				result := ctx.Results.JSON(response)
+				result.InnerStatusCode = http.StatusServiceUnavailable
+
+				return result
+			}
+
+			return ctx.Results.JSON(response)
+		}).
+		HasSummary("Readiness probe").
+		HasDescription(
+			"Checks the health of every connection and goroutine. Responds with 503 when a "+
+				"critical connection is unhealthy, a goroutine such as a queue consumer has "+
+				"crashed, or the process is starting or shutting down, and reports "+
+				"\"degraded\" when only optional connections are unhealthy. Connections "+
+				"tagged tier=optional are optional; all others are critical.",
+		).
+		HasResponseModel(http.StatusOK, ReadinessResponse{}).                //nolint:exhaustruct
+		HasResponseModel(http.StatusServiceUnavailable, ReadinessResponse{}) //nolint:exhaustruct
+}
+
+// checkReadiness runs the health checks of the dialed connections, and reads the
+// status of the process, which may be nil. Lazy connections that have not been dialed
+// yet are listed without being dialed.
+func checkReadiness(
+	ctx context.Context,
+	connections *connfx.Registry,
+	process *processfx.Process,
+) ReadinessResponse {
+	statuses := connections.HealthCheck(ctx)
+	infos := connections.ListConnectionInfo()
+
+	response := ReadinessResponse{
+		Status:       ReadinessStatusReady,
+		Dependencies: make([]DependencyStatusResponse, 0, len(infos)),
+		Goroutines:   make([]GoroutineStatusResponse, 0),
+	}
+
+	for _, info := range infos {
+		dependency := DependencyStatusResponse{
+			Name:      info.Name,
+			Protocol:  info.Protocol,
+			Tier:      connfx.TierCritical,
+			State:     info.State.String(),
+			Message:   "",
+			Error:     "",
+			LatencyMs: 0,
+			Healthy:   true,
+			Lazy:      info.Lazy,
+		}
+
+		if info.Tags[connfx.TagTier] == connfx.TierOptional {
+			dependency.Tier = connfx.TierOptional
+		}
+
+		if status, checked := statuses[info.Name]; checked {
+			dependency.State = status.State.String()
+			dependency.Message = status.Message
+			dependency.LatencyMs = status.Latency.Milliseconds()
+			dependency.Healthy = isHealthyState(status.State) && status.Error == nil
+
+			if status.Error != nil {
+				dependency.Error = status.Error.Error()
+			}
+		}
+
+		if !dependency.Healthy {
+			switch {
+			case dependency.Tier == connfx.TierCritical:
+				response.Status = ReadinessStatusNotReady
+			case response.Status == ReadinessStatusReady:
+				response.Status = ReadinessStatusDegraded
+			}
+		}
+
+		response.Dependencies = append(response.Dependencies, dependency)
+	}
+
+	if process == nil {
+		return response
+	}
+
+	processStatus := process.Status()
+
+	for _, goroutine := range processStatus.Goroutines {
+		status := GoroutineStatusResponse{
+			Name:     goroutine.Name,
+			State:    string(goroutine.State),
+			Error:    "",
+			Restarts: goroutine.Restarts,
+		}
+
+		if goroutine.LastError != nil {
+			status.Error = goroutine.LastError.Error()
+		}
+
+		response.Goroutines = append(response.Goroutines, status)
+	}
+
+	if !processStatus.Ready {
+		response.Status = ReadinessStatusNotReady
+	}
+
+	return response
+}
+
+func isHealthyState(state connfx.ConnectionState) bool {
+	switch state { //nolint:exhaustive
+	case connfx.ConnectionStateConnected, connfx.ConnectionStateLive, connfx.ConnectionStateReady:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,102 @@
+package http_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	adminhttp "github.com/eser/aya.is-services/pkg/api/adapters/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthRoutes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		path           string
+		closed         []string
+		expectedStatus string
+		expectedCode   int
+	}{
+		{
+			name:         "liveness",
+			path:         "/healthz",
+			closed:       []string{"database"},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:           "healthy dependencies",
+			path:           "/readyz",
+			expectedCode:   http.StatusOK,
+			expectedStatus: adminhttp.ReadinessStatusReady,
+		},
+		{
+			name:           "failing critical dependency",
+			path:           "/readyz",
+			closed:         []string{"database"},
+			expectedCode:   http.StatusServiceUnavailable,
+			expectedStatus: adminhttp.ReadinessStatusNotReady,
+		},
+		{
+			name:           "failing optional dependency",
+			path:           "/readyz",
+			closed:         []string{"search"},
+			expectedCode:   http.StatusOK,
+			expectedStatus: adminhttp.ReadinessStatusDegraded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			logger := logfx.NewLogger(logfx.WithWriter(io.Discard))
+
+			registry := connfx.NewRegistry(connfx.WithLogger(logger))
+			registry.RegisterFactory(connfx.NewInMemoryConnectionFactory("inmemory"))
+
+			targets := map[string]map[string]string{
+				"database": nil,
+				"search":   {connfx.TagTier: connfx.TierOptional},
+			}
+
+			for name, tags := range targets {
+				_, err := registry.AddConnection(t.Context(), name, &connfx.ConfigTarget{ //nolint:exhaustruct
+					Protocol: "inmemory",
+					Tags:     tags,
+				})
+				require.NoError(t, err)
+			}
+
+			for _, name := range tt.closed {
+				require.NoError(t, registry.GetNamed(name).Close(t.Context()))
+			}
+
+			router := httpfx.NewRouter("/")
+			adminhttp.RegisterHTTPRoutesForHealth(router, logger, registry, nil)
+
+			res := httptest.NewRecorder()
+			router.GetMux().ServeHTTP(res, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedCode, res.Code)
+			assert.Equal(t, "no-store", res.Header().Get("Cache-Control"))
+
+			if tt.expectedStatus == "" {
+				return
+			}
+
+			var response adminhttp.ReadinessResponse
+
+			require.NoError(t, json.Unmarshal(res.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedStatus, response.Status)
+			assert.Len(t, response.Dependencies, len(targets))
+		})
+	}
+}
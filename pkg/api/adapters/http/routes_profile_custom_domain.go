@@ -0,0 +1,127 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+)
+
+// ClaimCustomDomainRequest is the payload for claiming a custom domain.
+type ClaimCustomDomainRequest struct {
+	Domain string `json:"domain"`
+}
+
+// CustomDomainChallenge is the DNS record that proves control of a claimed domain.
+type CustomDomainChallenge struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CustomDomainClaimResponse is a custom domain claim with the record its owners publish.
+type CustomDomainClaimResponse struct {
+	*profiles.CustomDomainClaim
+
+	Challenge CustomDomainChallenge `json:"challenge"`
+}
+
+func registerHTTPRoutesForProfileCustomDomain(
+	routes *httpfx.Router,
+	logger *logfx.Logger,
+	profilesService *profiles.Service,
+) {
+	routes.
+		Route("POST /{locale}/profiles/{slug}/custom-domain", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			slugParam := ctx.Request.PathValue("slug")
+
+			userID, ok := userIDFromRequest(ctx)
+			if !ok {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					httpfx.WithPlainText("A user session is required"),
+				)
+			}
+
+			var payload ClaimCustomDomainRequest
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&payload)
+			if err != nil || payload.Domain == "" {
+				return ctx.Results.BadRequest(
+					httpfx.WithPlainText(fmt.Sprintf("%s: domain is required", ErrInvalidProfileRequest)),
+				)
+			}
+
+			claim, err := profilesService.ClaimCustomDomain(
+				ctx.Request.Context(),
+				userID,
+				slugParam,
+				payload.Domain,
+			)
+			if err != nil {
+				return profileWriteErrorResult(ctx, err)
+			}
+
+			logger.InfoContext(
+				ctx.Request.Context(),
+				"custom domain claimed",
+				slog.String("slug", slugParam),
+				slog.String("custom_domain", claim.Domain),
+				slog.String("user_id", userID),
+			)
+
+			return ctx.Results.JSON(newCustomDomainClaimResponse(claim))
+		}).
+		HasSummary("Claim custom domain").
+		HasDescription(
+			"Claim a custom domain for a profile. It is served once the TXT record of the challenge is found.", //nolint:lll
+		).
+		HasRequestModel(ClaimCustomDomainRequest{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK).
+		RequireAuth()
+
+	routes.
+		Route("GET /{locale}/profiles/{slug}/custom-domain", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			slugParam := ctx.Request.PathValue("slug")
+
+			userID, ok := userIDFromRequest(ctx)
+			if !ok {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					httpfx.WithPlainText("A user session is required"),
+				)
+			}
+
+			claim, err := profilesService.GetCustomDomainClaim(
+				ctx.Request.Context(),
+				userID,
+				slugParam,
+			)
+			if err != nil {
+				return profileWriteErrorResult(ctx, err)
+			}
+
+			return ctx.Results.JSON(newCustomDomainClaimResponse(claim))
+		}).
+		HasSummary("Get custom domain claim").
+		HasDescription("Get the verification status of the latest custom domain claim of a profile.").
+		HasResponse(http.StatusOK).
+		RequireAuth()
+}
+
+func newCustomDomainClaimResponse(claim *profiles.CustomDomainClaim) CustomDomainClaimResponse {
+	return CustomDomainClaimResponse{
+		CustomDomainClaim: claim,
+		Challenge: CustomDomainChallenge{
+			Type:  "TXT",
+			Name:  claim.ChallengeRecordName(),
+			Value: claim.ChallengeRecordValue(),
+		},
+	}
+}
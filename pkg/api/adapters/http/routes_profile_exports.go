@@ -0,0 +1,179 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/eser/aya.is-services/pkg/lib/cursors"
+)
+
+func registerHTTPRoutesForProfileExports( //nolint:funlen
+	routes *httpfx.Router,
+	logger *logfx.Logger,
+	profilesService *profiles.Service,
+) {
+	routes.
+		Route("POST /{locale}/profiles/{slug}/exports", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			slugParam := ctx.Request.PathValue("slug")
+
+			userID, ok := userIDFromRequest(ctx)
+			if !ok {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					httpfx.WithPlainText("A user session is required"),
+				)
+			}
+
+			export, err := profilesService.RequestExport(ctx.Request.Context(), userID, slugParam)
+			if err != nil {
+				return profileWriteErrorResult(ctx, err)
+			}
+
+			logger.InfoContext(
+				ctx.Request.Context(),
+				"profile export requested",
+				slog.String("slug", slugParam),
+				slog.String("export_id", export.ID),
+				slog.String("user_id", userID),
+			)
+
+			wrappedResponse := cursors.WrapResponseWithCursor(export, nil)
+
+			return ctx.Results.Accepted(httpfx.WithJSON(wrappedResponse))
+		}).
+		HasSummary("Request profile export").
+		HasDescription(
+			"Queue an export of the data of a profile as a zip archive. An export that is queued or running already is returned instead.", //nolint:lll
+		).
+		HasResponse(http.StatusAccepted).
+		RequireAuth()
+
+	routes.
+		Route("GET /{locale}/profiles/{slug}/exports/{id}", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			localeParam := ctx.Request.PathValue("locale")
+			slugParam := ctx.Request.PathValue("slug")
+			idParam := ctx.Request.PathValue("id")
+
+			userID, ok := userIDFromRequest(ctx)
+			if !ok {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					httpfx.WithPlainText("A user session is required"),
+				)
+			}
+
+			export, err := profilesService.GetExport(
+				ctx.Request.Context(),
+				userID,
+				slugParam,
+				idParam,
+			)
+			if err != nil {
+				return profileWriteErrorResult(ctx, err)
+			}
+
+			if export.Status == profiles.ExportStatusCompleted {
+				expiresAt, signature := profilesService.SignExportDownload(export)
+				export.DownloadURL = exportDownloadURL(
+					localeParam,
+					slugParam,
+					export.ID,
+					expiresAt.Unix(),
+					signature,
+				)
+			}
+
+			wrappedResponse := cursors.WrapResponseWithCursor(export, nil)
+
+			return ctx.Results.JSON(wrappedResponse)
+		}).
+		HasSummary("Get profile export").
+		HasDescription(
+			"Get the status of an export of a profile, with a signed download link once its archive is ready.",
+		).
+		HasResponse(http.StatusOK).
+		RequireAuth()
+
+	routes.
+		Route(
+			"GET /{locale}/profiles/{slug}/exports/{id}/download",
+			func(ctx *httpfx.Context) httpfx.Result {
+				// get variables from path
+				slugParam := ctx.Request.PathValue("slug")
+				idParam := ctx.Request.PathValue("id")
+
+				query := ctx.Request.URL.Query()
+
+				expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+				if err != nil {
+					return profileWriteErrorResult(ctx, profiles.ErrInvalidExportLink)
+				}
+
+				archive, export, err := profilesService.OpenExportArchive(
+					ctx.Request.Context(),
+					slugParam,
+					idParam,
+					expires,
+					query.Get("signature"),
+				)
+				if err != nil {
+					return profileWriteErrorResult(ctx, err)
+				}
+
+				return ctx.Results.Stream(func(w http.ResponseWriter, req *http.Request) {
+					defer archive.Close() //nolint:errcheck
+
+					w.Header().Set("Content-Type", "application/zip")
+					w.Header().Set(
+						"Content-Disposition",
+						fmt.Sprintf(`attachment; filename="%s-%s.zip"`, export.ProfileSlug, export.ID),
+					)
+
+					if export.SizeBytes != nil {
+						w.Header().Set("Content-Length", strconv.FormatInt(*export.SizeBytes, 10))
+					}
+
+					w.WriteHeader(http.StatusOK)
+
+					_, err := io.Copy(w, archive)
+					if err != nil {
+						logger.WarnContext(
+							req.Context(),
+							"failed to send profile export archive",
+							slog.String("slug", export.ProfileSlug),
+							slog.String("export_id", export.ID),
+							slog.Any("error", err),
+						)
+					}
+				})
+			},
+		).
+		HasSummary("Download profile export").
+		HasDescription(
+			"Download the archive of an export of a profile through the signed link of the export.",
+		).
+		HasResponse(http.StatusOK)
+}
+
+func exportDownloadURL(locale string, slug string, id string, expires int64, signature string) string {
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", signature)
+
+	return fmt.Sprintf(
+		"/%s/profiles/%s/exports/%s/download?%s",
+		url.PathEscape(locale),
+		url.PathEscape(slug),
+		url.PathEscape(id),
+		query.Encode(),
+	)
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/eser/aya.is-services/pkg/lib/cursors"
+)
+
+func registerHTTPRoutesForProfileFollows( //nolint:funlen
+	routes *httpfx.Router,
+	logger *logfx.Logger,
+	profilesService *profiles.Service,
+) {
+	routes.
+		Route("POST /{locale}/profiles/{slug}/follow", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			slugParam := ctx.Request.PathValue("slug")
+
+			userID, ok := userIDFromRequest(ctx)
+			if !ok {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					httpfx.WithPlainText("A user session is required"),
+				)
+			}
+
+			err := profilesService.Follow(ctx.Request.Context(), userID, slugParam)
+			if err != nil {
+				return profileWriteErrorResult(ctx, err)
+			}
+
+			logger.InfoContext(
+				ctx.Request.Context(),
+				"profile followed",
+				slog.String("slug", slugParam),
+				slog.String("user_id", userID),
+			)
+
+			return ctx.Results.Ok()
+		}).
+		HasSummary("Follow profile").
+		HasDescription("Follow a profile with the individual profile of the user.").
+		HasResponse(http.StatusNoContent).
+		RequireAuth()
+
+	routes.
+		Route("DELETE /{locale}/profiles/{slug}/follow", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			slugParam := ctx.Request.PathValue("slug")
+
+			userID, ok := userIDFromRequest(ctx)
+			if !ok {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					httpfx.WithPlainText("A user session is required"),
+				)
+			}
+
+			err := profilesService.Unfollow(ctx.Request.Context(), userID, slugParam)
+			if err != nil {
+				return profileWriteErrorResult(ctx, err)
+			}
+
+			logger.InfoContext(
+				ctx.Request.Context(),
+				"profile unfollowed",
+				slog.String("slug", slugParam),
+				slog.String("user_id", userID),
+			)
+
+			return ctx.Results.Ok()
+		}).
+		HasSummary("Unfollow profile").
+		HasDescription("Stop following a profile with the individual profile of the user.").
+		HasResponse(http.StatusNoContent).
+		RequireAuth()
+
+	routes.
+		Route("GET /{locale}/profiles/{slug}/followers", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
+			slugParam := ctx.Request.PathValue("slug")
+			cursor := cursors.NewCursorFromRequest(ctx.Request)
+
+			records, err := profilesService.ListFollowersBySlug(
+				ctx.Request.Context(),
+				localeParam,
+				slugParam,
+				cursor,
+			)
+			if err != nil {
+				return profileWriteErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Negotiate(ctx.Request, records)
+		}).
+		HasSummary("List profile followers").
+		HasDescription("List the profiles that follow a profile, the latest first.").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("GET /{locale}/profiles/{slug}/following", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
+			slugParam := ctx.Request.PathValue("slug")
+			cursor := cursors.NewCursorFromRequest(ctx.Request)
+
+			records, err := profilesService.ListFollowingBySlug(
+				ctx.Request.Context(),
+				localeParam,
+				slugParam,
+				cursor,
+			)
+			if err != nil {
+				return profileWriteErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Negotiate(ctx.Request, records)
+		}).
+		HasSummary("List followed profiles").
+		HasDescription("List the profiles a profile follows, the latest first.").
+		HasResponse(http.StatusOK)
+}
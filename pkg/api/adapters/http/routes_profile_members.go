@@ -0,0 +1,239 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/eser/aya.is-services/pkg/lib/cursors"
+)
+
+// InviteMemberResponse carries the invitation token, which is not shown again.
+type InviteMemberResponse struct {
+	*profiles.ProfileMembershipInvitation
+
+	Token string `json:"token"`
+}
+
+// AcceptInvitationRequest is the payload for accepting a membership invitation.
+type AcceptInvitationRequest struct {
+	Token string `json:"token"`
+}
+
+// ChangeMemberKindRequest is the payload for changing the kind of a membership.
+type ChangeMemberKindRequest struct {
+	Kind string `json:"kind"`
+}
+
+func registerHTTPRoutesForProfileMembers( //nolint:funlen,cyclop
+	routes *httpfx.Router,
+	logger *logfx.Logger,
+	profilesService *profiles.Service,
+) {
+	routes.
+		Route("POST /{locale}/profiles/{slug}/members", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			slugParam := ctx.Request.PathValue("slug")
+
+			userID, ok := userIDFromRequest(ctx)
+			if !ok {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					httpfx.WithPlainText("A user session is required"),
+				)
+			}
+
+			var input profiles.InviteMemberInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&input)
+			if err != nil || input.MemberProfileSlug == "" {
+				return ctx.Results.BadRequest(
+					httpfx.WithPlainText(
+						fmt.Sprintf("%s: member_profile_slug is required", ErrInvalidProfileRequest),
+					),
+				)
+			}
+
+			invitation, token, err := profilesService.InviteMember(
+				ctx.Request.Context(),
+				userID,
+				slugParam,
+				&input,
+			)
+			if err != nil {
+				return profileWriteErrorResult(ctx, err)
+			}
+
+			logger.InfoContext(
+				ctx.Request.Context(),
+				"profile member invited",
+				slog.String("slug", slugParam),
+				slog.String("member_slug", input.MemberProfileSlug),
+				slog.String("kind", input.Kind),
+				slog.String("user_id", userID),
+			)
+
+			return ctx.Results.JSON(InviteMemberResponse{
+				ProfileMembershipInvitation: invitation,
+				Token:                       token,
+			})
+		}).
+		HasSummary("Invite profile member").
+		HasDescription(
+			"Invite a profile to be a member. The token is only returned in this response.",
+		).
+		HasRequestModel(profiles.InviteMemberInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK).
+		RequireAuth()
+
+	routes.
+		Route("POST /{locale}/profiles/{slug}/members/accept", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			slugParam := ctx.Request.PathValue("slug")
+
+			userID, ok := userIDFromRequest(ctx)
+			if !ok {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					httpfx.WithPlainText("A user session is required"),
+				)
+			}
+
+			var payload AcceptInvitationRequest
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&payload)
+			if err != nil || payload.Token == "" {
+				return ctx.Results.BadRequest(
+					httpfx.WithPlainText(fmt.Sprintf("%s: token is required", ErrInvalidProfileRequest)),
+				)
+			}
+
+			invitation, err := profilesService.AcceptInvitation(
+				ctx.Request.Context(),
+				userID,
+				slugParam,
+				payload.Token,
+			)
+			if err != nil {
+				return profileWriteErrorResult(ctx, err)
+			}
+
+			logger.InfoContext(
+				ctx.Request.Context(),
+				"profile membership invitation accepted",
+				slog.String("slug", slugParam),
+				slog.String("member_slug", invitation.MemberProfileSlug),
+				slog.String("user_id", userID),
+			)
+
+			wrappedResponse := cursors.WrapResponseWithCursor(invitation, nil)
+
+			return ctx.Results.JSON(wrappedResponse)
+		}).
+		HasSummary("Accept profile membership invitation").
+		HasDescription("Accept an invitation for a profile the user owns to be a member.").
+		HasRequestModel(AcceptInvitationRequest{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK).
+		RequireAuth()
+
+	routes.
+		Route(
+			"PATCH /{locale}/profiles/{slug}/members/{memberSlug}",
+			func(ctx *httpfx.Context) httpfx.Result {
+				// get variables from path
+				slugParam := ctx.Request.PathValue("slug")
+				memberSlugParam := ctx.Request.PathValue("memberSlug")
+
+				userID, ok := userIDFromRequest(ctx)
+				if !ok {
+					return ctx.Results.Error(
+						http.StatusForbidden,
+						httpfx.WithPlainText("A user session is required"),
+					)
+				}
+
+				var payload ChangeMemberKindRequest
+
+				if err := json.NewDecoder(ctx.Request.Body).Decode(&payload); err != nil {
+					return ctx.Results.BadRequest(
+						httpfx.WithPlainText(fmt.Sprintf("%s: %s", ErrInvalidProfileRequest, err)),
+					)
+				}
+
+				err := profilesService.ChangeMemberKind(
+					ctx.Request.Context(),
+					userID,
+					slugParam,
+					memberSlugParam,
+					payload.Kind,
+				)
+				if err != nil {
+					return profileWriteErrorResult(ctx, err)
+				}
+
+				logger.InfoContext(
+					ctx.Request.Context(),
+					"profile member kind changed",
+					slog.String("slug", slugParam),
+					slog.String("member_slug", memberSlugParam),
+					slog.String("kind", payload.Kind),
+					slog.String("user_id", userID),
+				)
+
+				return ctx.Results.Ok()
+			},
+		).
+		HasSummary("Change profile member kind").
+		HasDescription("Change the kind of a member. Only the owners of the profile may.").
+		HasRequestModel(ChangeMemberKindRequest{}). //nolint:exhaustruct
+		HasResponse(http.StatusNoContent).
+		RequireAuth()
+
+	routes.
+		Route(
+			"DELETE /{locale}/profiles/{slug}/members/{memberSlug}",
+			func(ctx *httpfx.Context) httpfx.Result {
+				// get variables from path
+				slugParam := ctx.Request.PathValue("slug")
+				memberSlugParam := ctx.Request.PathValue("memberSlug")
+
+				userID, ok := userIDFromRequest(ctx)
+				if !ok {
+					return ctx.Results.Error(
+						http.StatusForbidden,
+						httpfx.WithPlainText("A user session is required"),
+					)
+				}
+
+				err := profilesService.RemoveMember(
+					ctx.Request.Context(),
+					userID,
+					slugParam,
+					memberSlugParam,
+				)
+				if err != nil {
+					return profileWriteErrorResult(ctx, err)
+				}
+
+				logger.InfoContext(
+					ctx.Request.Context(),
+					"profile member removed",
+					slog.String("slug", slugParam),
+					slog.String("member_slug", memberSlugParam),
+					slog.String("user_id", userID),
+				)
+
+				return ctx.Results.Ok()
+			},
+		).
+		HasSummary("Remove profile member").
+		HasDescription(
+			"Remove a member. The owners of the profile may remove any member, and the owners of the member profile may leave.", //nolint:lll
+		).
+		HasResponse(http.StatusNoContent).
+		RequireAuth()
+}
@@ -0,0 +1,152 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx/middlewares"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/eser/aya.is-services/pkg/lib/cursors"
+)
+
+func registerHTTPRoutesForProfileViews(
+	routes *httpfx.Router,
+	logger *logfx.Logger,
+	profilesService *profiles.Service,
+) {
+	routes.
+		Route("GET /{locale}/profiles/{slug}/views", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			slugParam := ctx.Request.PathValue("slug")
+
+			userID, ok := userIDFromRequest(ctx)
+			if !ok {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					httpfx.WithPlainText("A user session is required"),
+				)
+			}
+
+			viewRange, err := viewRangeFromRequest(ctx)
+			if err != nil {
+				return ctx.Results.BadRequest(httpfx.WithPlainText(err.Error()))
+			}
+
+			summary, err := profilesService.GetViewSummary(
+				ctx.Request.Context(),
+				userID,
+				slugParam,
+				viewRange,
+			)
+			if err != nil {
+				return profileWriteErrorResult(ctx, err)
+			}
+
+			wrappedResponse := cursors.WrapResponseWithCursor(summary, nil)
+
+			return ctx.Results.JSON(wrappedResponse)
+		}).
+		HasSummary("Get profile view summary").
+		HasDescription(
+			"Count the views of a profile and of its stories between from and to, as YYYY-MM-DD; the last 30 days by default.", //nolint:lll
+		).
+		HasResponse(http.StatusOK).
+		RequireAuth()
+
+	routes.
+		Route("GET /{locale}/profiles/{slug}/views/daily", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			slugParam := ctx.Request.PathValue("slug")
+
+			userID, ok := userIDFromRequest(ctx)
+			if !ok {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					httpfx.WithPlainText("A user session is required"),
+				)
+			}
+
+			viewRange, err := viewRangeFromRequest(ctx)
+			if err != nil {
+				return ctx.Results.BadRequest(httpfx.WithPlainText(err.Error()))
+			}
+
+			records, err := profilesService.ListDailyViews(
+				ctx.Request.Context(),
+				userID,
+				slugParam,
+				viewRange,
+			)
+			if err != nil {
+				return profileWriteErrorResult(ctx, err)
+			}
+
+			wrappedResponse := cursors.WrapResponseWithCursor(records, nil)
+
+			return ctx.Results.JSON(wrappedResponse)
+		}).
+		HasSummary("List profile views by day").
+		HasDescription(
+			"Count the views of a profile and of its stories for each day between from and to, as YYYY-MM-DD.", //nolint:lll
+		).
+		HasResponse(http.StatusOK).
+		RequireAuth()
+}
+
+// recordView records a view of the profile or the story named by the slugParam path
+// value for the requests that succeed, cached responses included, so it comes before
+// the response cache. Failing to record a view does not fail the request.
+func recordView(
+	logger *logfx.Logger,
+	profilesService *profiles.Service,
+	kind string,
+	slugParam string,
+) httpfx.Handler {
+	return func(ctx *httpfx.Context) httpfx.Result {
+		result := ctx.Next()
+
+		if result.StatusCode() != http.StatusOK {
+			return result
+		}
+
+		slug := ctx.Request.PathValue(slugParam)
+
+		err := profilesService.RecordView(
+			ctx.Request.Context(),
+			kind,
+			slug,
+			visitorFromRequest(ctx),
+		)
+		if err != nil {
+			logger.WarnContext(
+				ctx.Request.Context(),
+				"failed to record view",
+				slog.String("kind", kind),
+				slog.String("slug", slug),
+				slog.Any("error", err),
+			)
+		}
+
+		return result
+	}
+}
+
+// visitorFromRequest identifies the viewer with its user ID when it has a session,
+// and with its address and user agent otherwise.
+func visitorFromRequest(ctx *httpfx.Context) string {
+	if userID, ok := userIDFromRequest(ctx); ok {
+		return "user:" + userID
+	}
+
+	addr, _ := ctx.Request.Context().Value(middlewares.ClientAddr).(string)
+
+	return "addr:" + addr + "|" + ctx.Request.UserAgent()
+}
+
+func viewRangeFromRequest(ctx *httpfx.Context) (profiles.ViewRange, error) {
+	query := ctx.Request.URL.Query()
+
+	return profiles.ParseViewRange(query.Get("from"), query.Get("to")) //nolint:wrapcheck
+}
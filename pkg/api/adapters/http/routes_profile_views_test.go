@@ -0,0 +1,143 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	adminhttp "github.com/eser/aya.is-services/pkg/api/adapters/http"
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/stretchr/testify/assert"
+)
+
+var errProfileUnavailable = errors.New("profile unavailable")
+
+// fakeProfilesRepository serves "acme", which was renamed from "old-acme", and fails to
+// read "broken".
+type fakeProfilesRepository struct {
+	profiles.Repository
+}
+
+func (r *fakeProfilesRepository) GetProfileIDBySlug(
+	_ context.Context,
+	slug string,
+) (string, error) {
+	switch slug {
+	case "acme":
+		return "profile-acme", nil
+	case "broken":
+		return "", errProfileUnavailable
+	default:
+		return "", nil
+	}
+}
+
+func (r *fakeProfilesRepository) GetProfileSlugRedirect(
+	_ context.Context,
+	oldSlug string,
+) (string, error) {
+	if oldSlug == "old-acme" {
+		return "acme", nil
+	}
+
+	return "", nil
+}
+
+func (r *fakeProfilesRepository) GetProfileByID(
+	_ context.Context,
+	_ string,
+	id string,
+) (*profiles.Profile, error) {
+	return &profiles.Profile{ID: id, Slug: "acme", Title: "Acme"}, nil //nolint:exhaustruct
+}
+
+func (r *fakeProfilesRepository) ListProfilePagesByProfileID(
+	_ context.Context,
+	_ string,
+	_ string,
+) ([]*profiles.ProfilePageBrief, error) {
+	return []*profiles.ProfilePageBrief{}, nil
+}
+
+func (r *fakeProfilesRepository) ListProfileLinksByProfileID(
+	_ context.Context,
+	_ string,
+	_ string,
+) ([]*profiles.ProfileLinkBrief, error) {
+	return []*profiles.ProfileLinkBrief{}, nil
+}
+
+func (r *fakeProfilesRepository) ListProfileBadges(
+	_ context.Context,
+	_ string,
+) ([]*profiles.ProfileBadge, error) {
+	return []*profiles.ProfileBadge{}, nil
+}
+
+// fakeViewQueue counts the published views.
+type fakeViewQueue struct {
+	published int
+}
+
+func (q *fakeViewQueue) Publish(_ context.Context, _ string, _ []byte) error {
+	q.published++
+
+	return nil
+}
+
+func TestProfileViewRecording(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		slug              string
+		expectedCode      int
+		expectedPublished int
+	}{
+		{
+			name:              "served profile",
+			slug:              "acme",
+			expectedCode:      http.StatusOK,
+			expectedPublished: 1,
+		},
+		{
+			name:              "renamed profile",
+			slug:              "old-acme",
+			expectedCode:      http.StatusMovedPermanently,
+			expectedPublished: 0,
+		},
+		{
+			name:              "failed lookup",
+			slug:              "broken",
+			expectedCode:      http.StatusInternalServerError,
+			expectedPublished: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			logger := logfx.NewLogger(logfx.WithWriter(io.Discard))
+
+			queue := &fakeViewQueue{} //nolint:exhaustruct
+			profilesService := profiles.NewService(logger, &fakeProfilesRepository{}, nil)
+			profilesService.SetViewQueue(queue, "views", "salt")
+
+			router := httpfx.NewRouter("/")
+			adminhttp.RegisterHTTPRoutesForProfiles(router, logger, profilesService, nil, nil)
+
+			res := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/en/profiles/"+tt.slug, nil)
+			router.GetMux().ServeHTTP(res, req)
+
+			assert.Equal(t, tt.expectedCode, res.Code)
+			assert.Equal(t, tt.expectedPublished, queue.published)
+		})
+	}
+}
@@ -1,26 +1,47 @@
 package http
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx/middlewares"
 	"github.com/eser/aya.is-services/pkg/ajan/logfx"
 	"github.com/eser/aya.is-services/pkg/api/business/profiles"
 	"github.com/eser/aya.is-services/pkg/api/business/stories"
 	"github.com/eser/aya.is-services/pkg/lib/cursors"
 )
 
+// profileCacheTTL bounds how long profile changes take to show up when they are
+// not invalidated explicitly.
+const profileCacheTTL = 5 * time.Minute
+
+var ErrInvalidProfileRequest = errors.New("invalid profile request")
+
 func RegisterHTTPRoutesForProfiles( //nolint:funlen,cyclop
 	routes *httpfx.Router,
 	logger *logfx.Logger,
 	profilesService *profiles.Service,
 	storiesService *stories.Service,
+	responseCache *middlewares.ResponseCache,
 ) {
+	cacheProfile := responseCache.Middleware(
+		profileCacheTTL,
+		middlewares.WithResponseCacheTags(func(ctx *httpfx.Context) []string {
+			return []string{profiles.CacheTagForProfile(ctx.Request.PathValue("slug"))}
+		}),
+	)
+
 	routes.
 		Route("GET /{locale}/profiles", func(ctx *httpfx.Context) httpfx.Result {
 			// get variables from path
-			localeParam := ctx.Request.PathValue("locale")
+			localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
 			cursor := cursors.NewCursorFromRequest(ctx.Request)
 
 			filterKind, filterKindOk := cursor.Filters["kind"]
@@ -43,16 +64,26 @@ func RegisterHTTPRoutesForProfiles( //nolint:funlen,cyclop
 				)
 			}
 
-			return ctx.Results.JSON(records)
+			return ctx.Results.Negotiate(ctx.Request, records)
 		}).
 		HasSummary("List profiles").
 		HasDescription("List profiles.").
 		HasResponse(http.StatusOK)
 
+	registerHTTPRoutesForProfileWrites(routes, logger, profilesService)
+	registerHTTPRoutesForProfileMembers(routes, logger, profilesService)
+	registerHTTPRoutesForProfileCustomDomain(routes, logger, profilesService)
+	registerHTTPRoutesForProfileFollows(routes, logger, profilesService)
+	registerHTTPRoutesForProfileViews(routes, logger, profilesService)
+	registerHTTPRoutesForProfileExports(routes, logger, profilesService)
+
+	recordProfileView := recordView(logger, profilesService, profiles.ViewKindProfile, "slug")
+	recordStoryView := recordView(logger, profilesService, profiles.ViewKindStory, "storySlug")
+
 	routes.
-		Route("GET /{locale}/profiles/{slug}", func(ctx *httpfx.Context) httpfx.Result {
+		Route("GET /{locale}/profiles/{slug}", recordProfileView, cacheProfile, func(ctx *httpfx.Context) httpfx.Result {
 			// get variables from path
-			localeParam := ctx.Request.PathValue("locale")
+			localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
 			slugParam := ctx.Request.PathValue("slug")
 
 			record, err := profilesService.GetBySlugEx(
@@ -61,10 +92,7 @@ func RegisterHTTPRoutesForProfiles( //nolint:funlen,cyclop
 				slugParam,
 			)
 			if err != nil {
-				return ctx.Results.Error(
-					http.StatusInternalServerError,
-					httpfx.WithPlainText(err.Error()),
-				)
+				return slugLookupErrorResult(ctx, err)
 			}
 
 			wrappedResponse := cursors.WrapResponseWithCursor(record, nil)
@@ -76,9 +104,9 @@ func RegisterHTTPRoutesForProfiles( //nolint:funlen,cyclop
 		HasResponse(http.StatusOK)
 
 	routes.
-		Route("GET /{locale}/profiles/{slug}/pages", func(ctx *httpfx.Context) httpfx.Result {
+		Route("GET /{locale}/profiles/{slug}/pages", cacheProfile, func(ctx *httpfx.Context) httpfx.Result {
 			// get variables from path
-			localeParam := ctx.Request.PathValue("locale")
+			localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
 			slugParam := ctx.Request.PathValue("slug")
 
 			records, err := profilesService.ListPagesBySlug(
@@ -87,10 +115,7 @@ func RegisterHTTPRoutesForProfiles( //nolint:funlen,cyclop
 				slugParam,
 			)
 			if err != nil {
-				return ctx.Results.Error(
-					http.StatusInternalServerError,
-					httpfx.WithPlainText(err.Error()),
-				)
+				return slugLookupErrorResult(ctx, err)
 			}
 
 			wrappedResponse := cursors.WrapResponseWithCursor(records, nil)
@@ -104,9 +129,10 @@ func RegisterHTTPRoutesForProfiles( //nolint:funlen,cyclop
 	routes.
 		Route(
 			"GET /{locale}/profiles/{slug}/pages/{pageSlug}",
+			cacheProfile,
 			func(ctx *httpfx.Context) httpfx.Result {
 				// get variables from path
-				localeParam := ctx.Request.PathValue("locale")
+				localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
 				slugParam := ctx.Request.PathValue("slug")
 				pageSlugParam := ctx.Request.PathValue("pageSlug")
 
@@ -117,10 +143,7 @@ func RegisterHTTPRoutesForProfiles( //nolint:funlen,cyclop
 					pageSlugParam,
 				)
 				if err != nil {
-					return ctx.Results.Error(
-						http.StatusInternalServerError,
-						httpfx.WithPlainText(err.Error()),
-					)
+					return slugLookupErrorResult(ctx, err)
 				}
 
 				wrappedResponse := cursors.WrapResponseWithCursor(records, nil)
@@ -133,9 +156,9 @@ func RegisterHTTPRoutesForProfiles( //nolint:funlen,cyclop
 		HasResponse(http.StatusOK)
 
 	routes.
-		Route("GET /{locale}/profiles/{slug}/links", func(ctx *httpfx.Context) httpfx.Result {
+		Route("GET /{locale}/profiles/{slug}/links", cacheProfile, func(ctx *httpfx.Context) httpfx.Result {
 			// get variables from path
-			localeParam := ctx.Request.PathValue("locale")
+			localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
 			slugParam := ctx.Request.PathValue("slug")
 
 			records, err := profilesService.ListLinksBySlug(
@@ -144,10 +167,7 @@ func RegisterHTTPRoutesForProfiles( //nolint:funlen,cyclop
 				slugParam,
 			)
 			if err != nil {
-				return ctx.Results.Error(
-					http.StatusInternalServerError,
-					httpfx.WithPlainText(err.Error()),
-				)
+				return slugLookupErrorResult(ctx, err)
 			}
 
 			wrappedResponse := cursors.WrapResponseWithCursor(records, nil)
@@ -159,9 +179,9 @@ func RegisterHTTPRoutesForProfiles( //nolint:funlen,cyclop
 		HasResponse(http.StatusOK)
 
 	routes.
-		Route("GET /{locale}/profiles/{slug}/stories", func(ctx *httpfx.Context) httpfx.Result {
+		Route("GET /{locale}/profiles/{slug}/stories", cacheProfile, func(ctx *httpfx.Context) httpfx.Result {
 			// get variables from path
-			localeParam := ctx.Request.PathValue("locale")
+			localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
 			slugParam := ctx.Request.PathValue("slug")
 			cursor := cursors.NewCursorFromRequest(ctx.Request)
 
@@ -172,24 +192,78 @@ func RegisterHTTPRoutesForProfiles( //nolint:funlen,cyclop
 				cursor,
 			)
 			if err != nil {
-				return ctx.Results.Error(
-					http.StatusInternalServerError,
-					httpfx.WithPlainText(err.Error()),
-				)
+				return slugLookupErrorResult(ctx, err)
 			}
 
-			return ctx.Results.JSON(records)
+			return ctx.Results.Negotiate(ctx.Request, records)
 		}).
 		HasSummary("List stories published to profile slug").
 		HasDescription("List stories published to profile slug.").
 		HasResponse(http.StatusOK)
 
+	conditionalGet := middlewares.ConditionalGetMiddleware()
+
+	profileFeed := func(format string) httpfx.Handler {
+		return func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
+			slugParam := ctx.Request.PathValue("slug")
+
+			feed, err := storiesService.RenderProfileFeed(
+				ctx.Request.Context(),
+				localeParam,
+				slugParam,
+				format,
+			)
+			if err != nil {
+				if errors.Is(err, stories.ErrFeedsNotEnabled) ||
+					errors.Is(err, profiles.ErrProfileNotFound) {
+					return ctx.Results.NotFound(httpfx.WithPlainText(err.Error()))
+				}
+
+				return slugLookupErrorResult(ctx, err)
+			}
+
+			ctx.ResponseWriter.Header().Set("Content-Type", feed.ContentType)
+			ctx.ResponseWriter.Header().Set(
+				"Last-Modified",
+				feed.UpdatedAt.UTC().Format(http.TimeFormat),
+			)
+
+			return ctx.Results.Bytes(feed.Body)
+		}
+	}
+
+	routes.
+		Route(
+			"GET /{locale}/profiles/{slug}/stories/feed.rss",
+			conditionalGet,
+			cacheProfile,
+			profileFeed(stories.FeedFormatRSS),
+		).
+		HasSummary("Get RSS feed of profile stories").
+		HasDescription("Get the latest stories published to profile slug as an RSS 2.0 feed.").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route(
+			"GET /{locale}/profiles/{slug}/stories/feed.atom",
+			conditionalGet,
+			cacheProfile,
+			profileFeed(stories.FeedFormatAtom),
+		).
+		HasSummary("Get Atom feed of profile stories").
+		HasDescription("Get the latest stories published to profile slug as an Atom feed.").
+		HasResponse(http.StatusOK)
+
 	routes.
 		Route(
 			"GET /{locale}/profiles/{slug}/stories/{storySlug}",
+			recordStoryView,
+			cacheProfile,
 			func(ctx *httpfx.Context) httpfx.Result {
 				// get variables from path
-				localeParam := ctx.Request.PathValue("locale")
+				localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
 				// slugParam := ctx.Request.PathValue("slug")
 				storySlugParam := ctx.Request.PathValue("storySlug")
 
@@ -200,10 +274,7 @@ func RegisterHTTPRoutesForProfiles( //nolint:funlen,cyclop
 					storySlugParam,
 				)
 				if err != nil {
-					return ctx.Results.Error(
-						http.StatusInternalServerError,
-						httpfx.WithPlainText(err.Error()),
-					)
+					return slugLookupErrorResult(ctx, err)
 				}
 
 				// if record == nil {
@@ -222,9 +293,10 @@ func RegisterHTTPRoutesForProfiles( //nolint:funlen,cyclop
 	routes.
 		Route(
 			"GET /{locale}/profiles/{slug}/contributions",
+			cacheProfile,
 			func(ctx *httpfx.Context) httpfx.Result {
 				// get variables from path
-				localeParam := ctx.Request.PathValue("locale")
+				localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
 				slugParam := ctx.Request.PathValue("slug")
 				cursor := cursors.NewCursorFromRequest(ctx.Request)
 
@@ -235,13 +307,10 @@ func RegisterHTTPRoutesForProfiles( //nolint:funlen,cyclop
 					cursor,
 				)
 				if err != nil {
-					return ctx.Results.Error(
-						http.StatusInternalServerError,
-						httpfx.WithPlainText(err.Error()),
-					)
+					return slugLookupErrorResult(ctx, err)
 				}
 
-				return ctx.Results.JSON(records)
+				return ctx.Results.Negotiate(ctx.Request, records)
 			},
 		).
 		HasSummary("List profile contributions by profile slug").
@@ -251,9 +320,10 @@ func RegisterHTTPRoutesForProfiles( //nolint:funlen,cyclop
 	routes.
 		Route(
 			"GET /{locale}/profiles/{slug}/members",
+			cacheProfile,
 			func(ctx *httpfx.Context) httpfx.Result {
 				// get variables from path
-				localeParam := ctx.Request.PathValue("locale")
+				localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
 				slugParam := ctx.Request.PathValue("slug")
 				cursor := cursors.NewCursorFromRequest(ctx.Request)
 
@@ -264,16 +334,236 @@ func RegisterHTTPRoutesForProfiles( //nolint:funlen,cyclop
 					cursor,
 				)
 				if err != nil {
-					return ctx.Results.Error(
-						http.StatusInternalServerError,
-						httpfx.WithPlainText(err.Error()),
-					)
+					return slugLookupErrorResult(ctx, err)
 				}
 
-				return ctx.Results.JSON(records)
+				return ctx.Results.Negotiate(ctx.Request, records)
 			},
 		).
 		HasSummary("List profile members by profile slug").
 		HasDescription("List profile members by profile slug.").
 		HasResponse(http.StatusOK)
 }
+
+func registerHTTPRoutesForProfileWrites( //nolint:funlen
+	routes *httpfx.Router,
+	logger *logfx.Logger,
+	profilesService *profiles.Service,
+) {
+	routes.
+		Route("POST /{locale}/profiles/{slug}", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
+			slugParam := ctx.Request.PathValue("slug")
+
+			userID, ok := userIDFromRequest(ctx)
+			if !ok {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					httpfx.WithPlainText("A user session is required"),
+				)
+			}
+
+			var input profiles.CreateProfileInput
+
+			if err := json.NewDecoder(ctx.Request.Body).Decode(&input); err != nil {
+				return ctx.Results.BadRequest(
+					httpfx.WithPlainText(fmt.Sprintf("%s: %s", ErrInvalidProfileRequest, err)),
+				)
+			}
+
+			input.Slug = slugParam
+
+			record, err := profilesService.Create(ctx.Request.Context(), localeParam, userID, &input)
+			if err != nil {
+				return profileWriteErrorResult(ctx, err)
+			}
+
+			logger.InfoContext(
+				ctx.Request.Context(),
+				"profile created",
+				slog.String("slug", record.Slug),
+				slog.String("kind", record.Kind),
+				slog.String("user_id", userID),
+			)
+
+			wrappedResponse := cursors.WrapResponseWithCursor(record, nil)
+
+			return ctx.Results.JSON(wrappedResponse)
+		}).
+		HasSummary("Create profile").
+		HasDescription("Create a profile with the slug. The user becomes its owner.").
+		HasRequestModel(profiles.CreateProfileInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK).
+		RequireAuth()
+
+	routes.
+		Route("PATCH /{locale}/profiles/{slug}", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
+			slugParam := ctx.Request.PathValue("slug")
+
+			userID, ok := userIDFromRequest(ctx)
+			if !ok {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					httpfx.WithPlainText("A user session is required"),
+				)
+			}
+
+			var input profiles.UpdateProfileInput
+
+			if err := json.NewDecoder(ctx.Request.Body).Decode(&input); err != nil {
+				return ctx.Results.BadRequest(
+					httpfx.WithPlainText(fmt.Sprintf("%s: %s", ErrInvalidProfileRequest, err)),
+				)
+			}
+
+			record, err := profilesService.Update(
+				ctx.Request.Context(),
+				localeParam,
+				userID,
+				slugParam,
+				&input,
+			)
+			if err != nil {
+				return profileWriteErrorResult(ctx, err)
+			}
+
+			wrappedResponse := cursors.WrapResponseWithCursor(record, nil)
+
+			return ctx.Results.JSON(wrappedResponse)
+		}).
+		HasSummary("Update profile").
+		HasDescription("Update the fields of a profile that are set. Only its owners may.").
+		HasRequestModel(profiles.UpdateProfileInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK).
+		RequireAuth()
+
+	routes.
+		Route("DELETE /{locale}/profiles/{slug}", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			slugParam := ctx.Request.PathValue("slug")
+
+			userID, ok := userIDFromRequest(ctx)
+			if !ok {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					httpfx.WithPlainText("A user session is required"),
+				)
+			}
+
+			err := profilesService.Delete(ctx.Request.Context(), userID, slugParam)
+			if err != nil {
+				return profileWriteErrorResult(ctx, err)
+			}
+
+			logger.InfoContext(
+				ctx.Request.Context(),
+				"profile deleted",
+				slog.String("slug", slugParam),
+				slog.String("user_id", userID),
+			)
+
+			return ctx.Results.Ok()
+		}).
+		HasSummary("Delete profile").
+		HasDescription("Delete a profile. Only its owners may; its slug is not released.").
+		HasResponse(http.StatusNoContent).
+		RequireAuth()
+}
+
+// userIDFromRequest returns the ID of the user who sent the request. Requests
+// authenticated with an API key come from machine clients, which have none.
+func userIDFromRequest(ctx *httpfx.Context) (string, bool) {
+	identity, ok := httpfx.AuthIdentityFromContext(ctx.Request.Context())
+	if !ok || identity.Subject == "" || strings.HasPrefix(identity.Subject, "api_key:") {
+		return "", false
+	}
+
+	return identity.Subject, true
+}
+
+// slugLookupErrorResult redirects requests that look a record up with a slug it had
+// before to the same path with its current slug.
+func slugLookupErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	var movedErr *profiles.SlugMovedError
+	if !errors.As(err, &movedErr) {
+		return ctx.Results.Error(
+			http.StatusInternalServerError,
+			httpfx.WithPlainText(err.Error()),
+		)
+	}
+
+	location := replaceSlugInPath(*ctx.Request.URL, movedErr.OldSlug, movedErr.Slug)
+
+	return ctx.Results.MovedPermanently(
+		location,
+		httpfx.WithJSON(map[string]string{
+			"redirect_to": location,
+			"slug":        movedErr.Slug,
+		}),
+	)
+}
+
+// replaceSlugInPath replaces the last path segment that holds oldSlug after a
+// "profiles" or "stories" segment, keeping the query string.
+func replaceSlugInPath(uri url.URL, oldSlug string, slug string) string {
+	segments := strings.Split(uri.Path, "/")
+
+	for i := len(segments) - 1; i > 0; i-- {
+		if segments[i] == oldSlug &&
+			(segments[i-1] == "profiles" || segments[i-1] == "stories") {
+			segments[i] = slug
+
+			break
+		}
+	}
+
+	uri.Path = strings.Join(segments, "/")
+	uri.RawPath = ""
+
+	return uri.RequestURI()
+}
+
+func profileWriteErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	switch {
+	case errors.Is(err, profiles.ErrInvalidProfileInput),
+		errors.Is(err, profiles.ErrMembershipNotSupported),
+		errors.Is(err, profiles.ErrInvalidCustomDomain),
+		errors.Is(err, profiles.ErrCannotFollowSelf),
+		errors.Is(err, profiles.ErrInvalidBadgeKind):
+		return ctx.Results.BadRequest(httpfx.WithPlainText(err.Error()))
+	case errors.Is(err, profiles.ErrProfileNotFound),
+		errors.Is(err, profiles.ErrMembershipNotFound),
+		errors.Is(err, profiles.ErrInvitationNotFound),
+		errors.Is(err, profiles.ErrCustomDomainClaimNotFound),
+		errors.Is(err, profiles.ErrExportNotFound),
+		errors.Is(err, profiles.ErrBadgeNotFound):
+		return ctx.Results.NotFound(httpfx.WithPlainText(err.Error()))
+	case errors.Is(err, profiles.ErrNotProfileOwner),
+		errors.Is(err, profiles.ErrInvalidExportLink):
+		return ctx.Results.Error(http.StatusForbidden, httpfx.WithPlainText(err.Error()))
+	case errors.Is(err, profiles.ErrInvitationExpired):
+		return ctx.Results.Error(http.StatusGone, httpfx.WithPlainText(err.Error()))
+	case errors.Is(err, profiles.ErrSlugAlreadyTaken),
+		errors.Is(err, profiles.ErrIndividualProfileExists),
+		errors.Is(err, profiles.ErrIndividualProfileRequired),
+		errors.Is(err, profiles.ErrAlreadyMember),
+		errors.Is(err, profiles.ErrLastOwner),
+		errors.Is(err, profiles.ErrCustomDomainTaken),
+		errors.Is(err, profiles.ErrExportNotReady),
+		errors.Is(err, profiles.ErrBadgeAlreadyGranted):
+		return ctx.Results.Error(http.StatusConflict, httpfx.WithPlainText(err.Error()))
+	case errors.Is(err, profiles.ErrExportsNotEnabled):
+		return ctx.Results.Error(
+			http.StatusServiceUnavailable,
+			httpfx.WithPlainText(err.Error()),
+		)
+	default:
+		return ctx.Results.Error(
+			http.StatusInternalServerError,
+			httpfx.WithPlainText(err.Error()),
+		)
+	}
+}
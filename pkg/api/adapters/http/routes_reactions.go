@@ -0,0 +1,155 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/api/business/comments"
+	"github.com/eser/aya.is-services/pkg/api/business/reactions"
+	"github.com/eser/aya.is-services/pkg/api/business/stories"
+	"github.com/eser/aya.is-services/pkg/lib/cursors"
+)
+
+func RegisterHTTPRoutesForReactions(
+	routes *httpfx.Router,
+	logger *logfx.Logger,
+	storiesService *stories.Service,
+	commentsService *comments.Service,
+) {
+	routes.
+		Route(
+			"PUT /{locale}/stories/{slug}/reactions/{kind}",
+			storyReactionHandler(storiesService, true),
+		).
+		HasSummary("React to story").
+		HasDescription(
+			"Add the reaction of the user of a kind (like, love, celebrate or insightful) to a " +
+				"published story and return its counts. Reacting again changes nothing.",
+		).
+		HasResponse(http.StatusOK).
+		RequireAuth()
+
+	routes.
+		Route(
+			"DELETE /{locale}/stories/{slug}/reactions/{kind}",
+			storyReactionHandler(storiesService, false),
+		).
+		HasSummary("Remove story reaction").
+		HasDescription(
+			"Remove the reaction of the user of a kind from a story and return its counts. " +
+				"Removing a reaction that is not there changes nothing.",
+		).
+		HasResponse(http.StatusOK).
+		RequireAuth()
+
+	routes.
+		Route(
+			"PUT /{locale}/stories/{slug}/comments/{commentId}/reactions/{kind}",
+			commentReactionHandler(commentsService, true),
+		).
+		HasSummary("React to comment").
+		HasDescription(
+			"Add the reaction of the user of a kind to a visible comment on a story and return " +
+				"its counts. Reacting again changes nothing.",
+		).
+		HasResponse(http.StatusOK).
+		RequireAuth()
+
+	routes.
+		Route(
+			"DELETE /{locale}/stories/{slug}/comments/{commentId}/reactions/{kind}",
+			commentReactionHandler(commentsService, false),
+		).
+		HasSummary("Remove comment reaction").
+		HasDescription(
+			"Remove the reaction of the user of a kind from a comment and return its counts. " +
+				"Removing a reaction that is not there changes nothing.",
+		).
+		HasResponse(http.StatusOK).
+		RequireAuth()
+}
+
+// storyReactionHandler adds the reaction of the user to the story when reacted is
+// true, or removes it otherwise; either way it is safe to repeat.
+func storyReactionHandler(storiesService *stories.Service, reacted bool) httpfx.Handler {
+	return func(ctx *httpfx.Context) httpfx.Result {
+		// get variables from path
+		slugParam := ctx.Request.PathValue("slug")
+		kindParam := ctx.Request.PathValue("kind")
+
+		userID, ok := userIDFromRequest(ctx)
+		if !ok {
+			return ctx.Results.Error(
+				http.StatusForbidden,
+				httpfx.WithPlainText("A user session is required"),
+			)
+		}
+
+		counts, err := storiesService.React(
+			ctx.Request.Context(),
+			userID,
+			slugParam,
+			kindParam,
+			reacted,
+		)
+		if err != nil {
+			return reactionErrorResult(ctx, err)
+		}
+
+		wrappedResponse := cursors.WrapResponseWithCursor(counts, nil)
+
+		return ctx.Results.JSON(wrappedResponse)
+	}
+}
+
+// commentReactionHandler is storyReactionHandler for the comments on a story.
+func commentReactionHandler(commentsService *comments.Service, reacted bool) httpfx.Handler {
+	return func(ctx *httpfx.Context) httpfx.Result {
+		// get variables from path
+		slugParam := ctx.Request.PathValue("slug")
+		commentIDParam := ctx.Request.PathValue("commentId")
+		kindParam := ctx.Request.PathValue("kind")
+
+		userID, ok := userIDFromRequest(ctx)
+		if !ok {
+			return ctx.Results.Error(
+				http.StatusForbidden,
+				httpfx.WithPlainText("A user session is required"),
+			)
+		}
+
+		counts, err := commentsService.React(
+			ctx.Request.Context(),
+			userID,
+			slugParam,
+			commentIDParam,
+			kindParam,
+			reacted,
+		)
+		if err != nil {
+			return reactionErrorResult(ctx, err)
+		}
+
+		wrappedResponse := cursors.WrapResponseWithCursor(counts, nil)
+
+		return ctx.Results.JSON(wrappedResponse)
+	}
+}
+
+func reactionErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	switch {
+	case errors.Is(err, reactions.ErrInvalidReactionKind):
+		return ctx.Results.BadRequest(httpfx.WithPlainText(err.Error()))
+	case errors.Is(err, stories.ErrStoryNotFound),
+		errors.Is(err, comments.ErrStoryNotFound),
+		errors.Is(err, comments.ErrCommentNotFound):
+		return ctx.Results.NotFound(httpfx.WithPlainText(err.Error()))
+	default:
+		return ctx.Results.Error(
+			http.StatusInternalServerError,
+			httpfx.WithPlainText(err.Error()),
+		)
+	}
+}
@@ -1,11 +1,13 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
 	"github.com/eser/aya.is-services/pkg/ajan/logfx"
 	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/eser/aya.is-services/pkg/api/business/sitemaps"
 	"github.com/eser/aya.is-services/pkg/lib/cursors"
 )
 
@@ -13,13 +15,14 @@ func RegisterHTTPRoutesForSite(
 	routes *httpfx.Router,
 	logger *logfx.Logger,
 	profilesService *profiles.Service,
+	sitemapsService *sitemaps.Service,
 ) {
 	routes.
 		Route(
 			"GET /{locale}/site/custom-domains/{domain}",
 			func(ctx *httpfx.Context) httpfx.Result {
 				// get variables from path
-				localeParam := ctx.Request.PathValue("locale")
+				localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
 				domainParam := ctx.Request.PathValue("domain")
 
 				records, err := profilesService.GetByCustomDomain(
@@ -43,10 +46,35 @@ func RegisterHTTPRoutesForSite(
 		HasDescription("Get profile by a custom domain.").
 		HasResponse(http.StatusOK)
 
+	routes.
+		Route("GET /{locale}/site/tls-check", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from query
+			domainParam := ctx.Request.URL.Query().Get("domain")
+
+			served, err := profilesService.IsCustomDomainServed(ctx.Request.Context(), domainParam)
+			if err != nil {
+				return ctx.Results.Error(
+					http.StatusInternalServerError,
+					httpfx.WithPlainText(err.Error()),
+				)
+			}
+
+			if !served {
+				return ctx.Results.NotFound(httpfx.WithPlainText("Domain is not served"))
+			}
+
+			return ctx.Results.Ok()
+		}).
+		HasSummary("Check a domain for TLS").
+		HasDescription(
+			"Respond with 204 only for verified custom domains, for TLS terminators that issue certificates on demand.", //nolint:lll
+		).
+		HasResponse(http.StatusNoContent)
+
 	routes.
 		Route("GET /{locale}/site/spotlight", func(ctx *httpfx.Context) httpfx.Result {
 			// get variables from path
-			localeParam := ctx.Request.PathValue("locale")
+			localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
 
 			records, err := profilesService.List(
 				ctx.Request.Context(),
@@ -65,4 +93,42 @@ func RegisterHTTPRoutesForSite(
 		HasSummary("Gets spotlight metadata").
 		HasDescription("Gets spotlight metadata.").
 		HasResponse(http.StatusOK)
+
+	routes.
+		Route("GET /sitemap.xml", func(ctx *httpfx.Context) httpfx.Result {
+			sitemap, err := sitemapsService.Get(ctx.Request.Context())
+			if err != nil {
+				if errors.Is(err, sitemaps.ErrSitemapNotEnabled) {
+					return ctx.Results.NotFound(httpfx.WithPlainText(err.Error()))
+				}
+
+				return ctx.Results.Error(
+					http.StatusInternalServerError,
+					httpfx.WithPlainText(err.Error()),
+				)
+			}
+
+			ctx.ResponseWriter.Header().Set("Content-Type", "application/xml; charset=utf-8")
+			ctx.ResponseWriter.Header().Set(
+				"Last-Modified",
+				sitemap.GeneratedAt.UTC().Format(http.TimeFormat),
+			)
+
+			return ctx.Results.Bytes(sitemap.XML)
+		}).
+		HasSummary("Get sitemap").
+		HasDescription(
+			"Get the sitemap of the profiles, profile pages and stories of the site in every locale, as generated last.", //nolint:lll
+		).
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("GET /robots.txt", func(ctx *httpfx.Context) httpfx.Result {
+			ctx.ResponseWriter.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+			return ctx.Results.PlainText(sitemapsService.Robots())
+		}).
+		HasSummary("Get robots.txt").
+		HasDescription("Get the robots.txt of the site, which points crawlers at the sitemap.").
+		HasResponse(http.StatusOK)
 }
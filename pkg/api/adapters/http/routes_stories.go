@@ -1,23 +1,47 @@
 package http
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
 	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
 	"github.com/eser/aya.is-services/pkg/api/business/stories"
 	"github.com/eser/aya.is-services/pkg/lib/cursors"
 )
 
+var ErrInvalidStoryRequest = errors.New("invalid story request")
+
+// StoryStatusRequest is the payload for moving a story to another status.
+type StoryStatusRequest struct {
+	Status string `json:"status"` // e.g. "in_review"
+}
+
+// StoryPublishAtRequest is the payload for scheduling a story to be published.
+type StoryPublishAtRequest struct {
+	PublishAt *time.Time `json:"publish_at"` // e.g. "2026-01-02T09:00:00Z"
+}
+
 func RegisterHTTPRoutesForStories(
 	routes *httpfx.Router,
 	logger *logfx.Logger,
+	profilesService *profiles.Service,
 	storiesService *stories.Service,
 ) {
+	recordStoryView := recordView(logger, profilesService, profiles.ViewKindStory, "slug")
+
+	registerHTTPRoutesForStoryWrites(routes, logger, storiesService)
+	registerHTTPRoutesForStoryRevisions(routes, logger, storiesService)
+
 	routes.
 		Route("GET /{locale}/stories", func(ctx *httpfx.Context) httpfx.Result {
 			// get variables from path
-			localeParam := ctx.Request.PathValue("locale")
+			localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
 			cursor := cursors.NewCursorFromRequest(ctx.Request)
 
 			records, err := storiesService.List(ctx.Request.Context(), localeParam, cursor)
@@ -28,26 +52,66 @@ func RegisterHTTPRoutesForStories(
 				)
 			}
 
-			return ctx.Results.JSON(records)
+			return ctx.Results.Negotiate(ctx.Request, records)
 		}).
 		HasSummary("List stories").
 		HasDescription("List stories.").
 		HasResponse(http.StatusOK)
 
 	routes.
-		Route("GET /{locale}/stories/{slug}", func(ctx *httpfx.Context) httpfx.Result {
+		Route("GET /{locale}/feed", func(ctx *httpfx.Context) httpfx.Result {
 			// get variables from path
-			localeParam := ctx.Request.PathValue("locale")
-			slugParam := ctx.Request.PathValue("slug")
+			localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
+			cursor := cursors.NewCursorFromRequest(ctx.Request)
 
-			record, err := storiesService.GetBySlug(ctx.Request.Context(), localeParam, slugParam)
+			userID, ok := userIDFromRequest(ctx)
+			if !ok {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					httpfx.WithPlainText("A user session is required"),
+				)
+			}
+
+			records, err := storiesService.ListFeed(
+				ctx.Request.Context(),
+				localeParam,
+				userID,
+				cursor,
+			)
 			if err != nil {
+				if errors.Is(err, profiles.ErrIndividualProfileRequired) {
+					return ctx.Results.Error(
+						http.StatusConflict,
+						httpfx.WithPlainText(err.Error()),
+					)
+				}
+
 				return ctx.Results.Error(
 					http.StatusInternalServerError,
 					httpfx.WithPlainText(err.Error()),
 				)
 			}
 
+			return ctx.Results.Negotiate(ctx.Request, records)
+		}).
+		HasSummary("List feed").
+		HasDescription(
+			"List the stories of the profiles the user follows, the latest first.",
+		).
+		HasResponse(http.StatusOK).
+		RequireAuth()
+
+	routes.
+		Route("GET /{locale}/stories/{slug}", recordStoryView, func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
+			slugParam := ctx.Request.PathValue("slug")
+
+			record, err := storiesService.GetBySlug(ctx.Request.Context(), localeParam, slugParam)
+			if err != nil {
+				return slugLookupErrorResult(ctx, err)
+			}
+
 			// if record == nil {
 			// 	return ctx.Results.NotFound(httpfx.WithPlainText("story not found"))
 			// }
@@ -60,3 +124,286 @@ func RegisterHTTPRoutesForStories(
 		HasDescription("Get story by slug.").
 		HasResponse(http.StatusOK)
 }
+
+func registerHTTPRoutesForStoryWrites( //nolint:funlen
+	routes *httpfx.Router,
+	logger *logfx.Logger,
+	storiesService *stories.Service,
+) {
+	routes.
+		Route("POST /{locale}/profiles/{slug}/stories", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
+			slugParam := ctx.Request.PathValue("slug")
+
+			userID, ok := userIDFromRequest(ctx)
+			if !ok {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					httpfx.WithPlainText("A user session is required"),
+				)
+			}
+
+			var input stories.CreateStoryInput
+
+			if err := json.NewDecoder(ctx.Request.Body).Decode(&input); err != nil {
+				return ctx.Results.BadRequest(
+					httpfx.WithPlainText(fmt.Sprintf("%s: %s", ErrInvalidStoryRequest, err)),
+				)
+			}
+
+			record, err := storiesService.Create(
+				ctx.Request.Context(),
+				localeParam,
+				userID,
+				slugParam,
+				&input,
+			)
+			if err != nil {
+				return storyWriteErrorResult(ctx, err)
+			}
+
+			logger.InfoContext(
+				ctx.Request.Context(),
+				"story created",
+				slog.String("slug", input.Slug),
+				slog.String("author_profile", slugParam),
+				slog.String("user_id", userID),
+			)
+
+			wrappedResponse := cursors.WrapResponseWithCursor(record, nil)
+
+			return ctx.Results.JSON(wrappedResponse)
+		}).
+		HasSummary("Create story").
+		HasDescription("Create a draft story whose author is the profile. Only its owners may.").
+		HasRequestModel(stories.CreateStoryInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK).
+		RequireAuth()
+
+	routes.
+		Route("PATCH /{locale}/stories/{slug}", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
+			slugParam := ctx.Request.PathValue("slug")
+
+			userID, ok := userIDFromRequest(ctx)
+			if !ok {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					httpfx.WithPlainText("A user session is required"),
+				)
+			}
+
+			var input stories.UpdateStoryInput
+
+			if err := json.NewDecoder(ctx.Request.Body).Decode(&input); err != nil {
+				return ctx.Results.BadRequest(
+					httpfx.WithPlainText(fmt.Sprintf("%s: %s", ErrInvalidStoryRequest, err)),
+				)
+			}
+
+			record, err := storiesService.Update(
+				ctx.Request.Context(),
+				localeParam,
+				userID,
+				slugParam,
+				&input,
+			)
+			if err != nil {
+				return storyWriteErrorResult(ctx, err)
+			}
+
+			wrappedResponse := cursors.WrapResponseWithCursor(record, nil)
+
+			return ctx.Results.JSON(wrappedResponse)
+		}).
+		HasSummary("Update story").
+		HasDescription(
+			"Update the fields of a story that are set. Only the owners of its author profile may.",
+		).
+		HasRequestModel(stories.UpdateStoryInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK).
+		RequireAuth()
+
+	routes.
+		Route("PUT /{locale}/stories/{slug}/status", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
+			slugParam := ctx.Request.PathValue("slug")
+
+			userID, ok := userIDFromRequest(ctx)
+			if !ok {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					httpfx.WithPlainText("A user session is required"),
+				)
+			}
+
+			var payload StoryStatusRequest
+
+			if err := json.NewDecoder(ctx.Request.Body).Decode(&payload); err != nil || payload.Status == "" {
+				return ctx.Results.BadRequest(
+					httpfx.WithPlainText(fmt.Sprintf("%s: status is required", ErrInvalidStoryRequest)),
+				)
+			}
+
+			record, err := storiesService.UpdateStatus(
+				ctx.Request.Context(),
+				localeParam,
+				userID,
+				slugParam,
+				payload.Status,
+			)
+			if err != nil {
+				return storyWriteErrorResult(ctx, err)
+			}
+
+			wrappedResponse := cursors.WrapResponseWithCursor(record, nil)
+
+			return ctx.Results.JSON(wrappedResponse)
+		}).
+		HasSummary("Change story status").
+		HasDescription(
+			"Move a story along draft, in_review, published and archived. " +
+				"Only the owners of its author profile may.",
+		).
+		HasRequestModel(StoryStatusRequest{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK).
+		RequireAuth()
+
+	routes.
+		Route("PUT /{locale}/stories/{slug}/publish-at", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
+			slugParam := ctx.Request.PathValue("slug")
+
+			userID, ok := userIDFromRequest(ctx)
+			if !ok {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					httpfx.WithPlainText("A user session is required"),
+				)
+			}
+
+			var payload StoryPublishAtRequest
+
+			if err := json.NewDecoder(ctx.Request.Body).Decode(&payload); err != nil || payload.PublishAt == nil {
+				return ctx.Results.BadRequest(
+					httpfx.WithPlainText(fmt.Sprintf("%s: publish_at is required", ErrInvalidStoryRequest)),
+				)
+			}
+
+			record, err := storiesService.SchedulePublish(
+				ctx.Request.Context(),
+				localeParam,
+				userID,
+				slugParam,
+				payload.PublishAt,
+			)
+			if err != nil {
+				return storyWriteErrorResult(ctx, err)
+			}
+
+			wrappedResponse := cursors.WrapResponseWithCursor(record, nil)
+
+			return ctx.Results.JSON(wrappedResponse)
+		}).
+		HasSummary("Schedule story").
+		HasDescription(
+			"Schedule a draft or a story in review to be published at publish_at. " +
+				"Only the owners of its author profile may.",
+		).
+		HasRequestModel(StoryPublishAtRequest{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK).
+		RequireAuth()
+
+	routes.
+		Route("DELETE /{locale}/stories/{slug}/publish-at", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
+			slugParam := ctx.Request.PathValue("slug")
+
+			userID, ok := userIDFromRequest(ctx)
+			if !ok {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					httpfx.WithPlainText("A user session is required"),
+				)
+			}
+
+			record, err := storiesService.SchedulePublish(
+				ctx.Request.Context(),
+				localeParam,
+				userID,
+				slugParam,
+				nil,
+			)
+			if err != nil {
+				return storyWriteErrorResult(ctx, err)
+			}
+
+			wrappedResponse := cursors.WrapResponseWithCursor(record, nil)
+
+			return ctx.Results.JSON(wrappedResponse)
+		}).
+		HasSummary("Unschedule story").
+		HasDescription("Clear the publish time of a scheduled story.").
+		HasResponse(http.StatusOK).
+		RequireAuth()
+
+	routes.
+		Route("DELETE /{locale}/stories/{slug}", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			slugParam := ctx.Request.PathValue("slug")
+
+			userID, ok := userIDFromRequest(ctx)
+			if !ok {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					httpfx.WithPlainText("A user session is required"),
+				)
+			}
+
+			err := storiesService.Delete(ctx.Request.Context(), userID, slugParam)
+			if err != nil {
+				return storyWriteErrorResult(ctx, err)
+			}
+
+			logger.InfoContext(
+				ctx.Request.Context(),
+				"story deleted",
+				slog.String("slug", slugParam),
+				slog.String("user_id", userID),
+			)
+
+			return ctx.Results.Ok()
+		}).
+		HasSummary("Delete story").
+		HasDescription(
+			"Delete a story. Only the owners of its author profile may; its slug is not released.",
+		).
+		HasResponse(http.StatusNoContent).
+		RequireAuth()
+}
+
+func storyWriteErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	switch {
+	case errors.Is(err, stories.ErrInvalidStoryInput):
+		return ctx.Results.BadRequest(httpfx.WithPlainText(err.Error()))
+	case errors.Is(err, stories.ErrStoryNotFound),
+		errors.Is(err, stories.ErrRevisionNotFound),
+		errors.Is(err, profiles.ErrProfileNotFound):
+		return ctx.Results.NotFound(httpfx.WithPlainText(err.Error()))
+	case errors.Is(err, stories.ErrNotStoryAuthor):
+		return ctx.Results.Error(http.StatusForbidden, httpfx.WithPlainText(err.Error()))
+	case errors.Is(err, stories.ErrStorySlugAlreadyTaken),
+		errors.Is(err, stories.ErrInvalidStatusTransition):
+		return ctx.Results.Error(http.StatusConflict, httpfx.WithPlainText(err.Error()))
+	default:
+		return ctx.Results.Error(
+			http.StatusInternalServerError,
+			httpfx.WithPlainText(err.Error()),
+		)
+	}
+}
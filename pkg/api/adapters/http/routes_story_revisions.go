@@ -0,0 +1,145 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/eser/aya.is-services/pkg/ajan/httpfx"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/api/business/stories"
+	"github.com/eser/aya.is-services/pkg/lib/cursors"
+)
+
+func registerHTTPRoutesForStoryRevisions( //nolint:funlen
+	routes *httpfx.Router,
+	logger *logfx.Logger,
+	storiesService *stories.Service,
+) {
+	routes.
+		Route("GET /{locale}/stories/{slug}/revisions", func(ctx *httpfx.Context) httpfx.Result {
+			// get variables from path
+			localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
+			slugParam := ctx.Request.PathValue("slug")
+
+			userID, ok := userIDFromRequest(ctx)
+			if !ok {
+				return ctx.Results.Error(
+					http.StatusForbidden,
+					httpfx.WithPlainText("A user session is required"),
+				)
+			}
+
+			records, err := storiesService.ListRevisions(
+				ctx.Request.Context(),
+				localeParam,
+				userID,
+				slugParam,
+			)
+			if err != nil {
+				return storyWriteErrorResult(ctx, err)
+			}
+
+			wrappedResponse := cursors.WrapResponseWithCursor(records, nil)
+
+			return ctx.Results.JSON(wrappedResponse)
+		}).
+		HasSummary("List story revisions").
+		HasDescription(
+			"List the revisions of a story in the locale, the latest first. " +
+				"Only the owners of its author profile may.",
+		).
+		HasResponse(http.StatusOK).
+		RequireAuth()
+
+	routes.
+		Route(
+			"GET /{locale}/stories/{slug}/revisions/{revisionId}/diff",
+			func(ctx *httpfx.Context) httpfx.Result {
+				// get variables from path
+				localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
+				slugParam := ctx.Request.PathValue("slug")
+				revisionIDParam := ctx.Request.PathValue("revisionId")
+				againstParam := ctx.Request.URL.Query().Get("against")
+
+				userID, ok := userIDFromRequest(ctx)
+				if !ok {
+					return ctx.Results.Error(
+						http.StatusForbidden,
+						httpfx.WithPlainText("A user session is required"),
+					)
+				}
+
+				record, err := storiesService.DiffRevisions(
+					ctx.Request.Context(),
+					localeParam,
+					userID,
+					slugParam,
+					revisionIDParam,
+					againstParam,
+				)
+				if err != nil {
+					return storyWriteErrorResult(ctx, err)
+				}
+
+				wrappedResponse := cursors.WrapResponseWithCursor(record, nil)
+
+				return ctx.Results.JSON(wrappedResponse)
+			},
+		).
+		HasSummary("Diff story revision").
+		HasDescription(
+			"Compare a revision of a story line by line to the revision in the against " +
+				"query parameter, or to the current story when it is not given.",
+		).
+		HasResponse(http.StatusOK).
+		RequireAuth()
+
+	routes.
+		Route(
+			"POST /{locale}/stories/{slug}/revisions/{revisionId}/restore",
+			func(ctx *httpfx.Context) httpfx.Result {
+				// get variables from path
+				localeParam := httpfx.LocaleFromContext(ctx.Request.Context())
+				slugParam := ctx.Request.PathValue("slug")
+				revisionIDParam := ctx.Request.PathValue("revisionId")
+
+				userID, ok := userIDFromRequest(ctx)
+				if !ok {
+					return ctx.Results.Error(
+						http.StatusForbidden,
+						httpfx.WithPlainText("A user session is required"),
+					)
+				}
+
+				record, err := storiesService.RestoreRevision(
+					ctx.Request.Context(),
+					localeParam,
+					userID,
+					slugParam,
+					revisionIDParam,
+				)
+				if err != nil {
+					return storyWriteErrorResult(ctx, err)
+				}
+
+				logger.InfoContext(
+					ctx.Request.Context(),
+					"story revision restored",
+					slog.String("slug", slugParam),
+					slog.String("revision_id", revisionIDParam),
+					slog.String("user_id", userID),
+				)
+
+				wrappedResponse := cursors.WrapResponseWithCursor(record, nil)
+
+				return ctx.Results.JSON(wrappedResponse)
+			},
+		).
+		HasSummary("Restore story revision").
+		HasDescription(
+			"Set the title, summary and content of a story back to a revision. " +
+				"The restored content is stored as a new revision.",
+		).
+		HasResponse(http.StatusOK).
+		RequireAuth()
+}
@@ -119,5 +119,6 @@ func RegisterHTTPRoutesForUsers( //nolint:funlen
 		}).
 		HasSummary("Logout").
 		HasDescription("Logs out the user.").
-		HasResponse(http.StatusOK)
+		HasResponse(http.StatusOK).
+		RequireAuth()
 }
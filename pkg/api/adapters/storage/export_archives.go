@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+)
+
+const exportArchiveContentType = "application/zip"
+
+// ExportArchives keeps the archives of profile exports in an object storage.
+type ExportArchives struct {
+	objects connfx.ObjectStorageRepository
+}
+
+func NewExportArchives(objects connfx.ObjectStorageRepository) *ExportArchives {
+	return &ExportArchives{objects: objects}
+}
+
+// PutArchive writes the archive to a temporary file first, so its size is known and
+// large profiles are not held in memory.
+func (a *ExportArchives) PutArchive(
+	ctx context.Context,
+	key string,
+	write func(w io.Writer) error,
+) (int64, error) {
+	file, err := os.CreateTemp("", "profile-export-*.zip")
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}()
+
+	err = write(file)
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	err = a.objects.PutObject(
+		ctx,
+		key,
+		file,
+		&connfx.PutObjectOptions{ //nolint:exhaustruct
+			ContentType: exportArchiveContentType,
+		},
+	)
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	return size, nil
+}
+
+func (a *ExportArchives) OpenArchive(ctx context.Context, key string) (io.ReadCloser, error) {
+	reader, _, err := a.objects.GetObject(ctx, key)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return reader, nil
+}
+
+func (a *ExportArchives) DeleteArchive(ctx context.Context, key string) error {
+	return a.objects.DeleteObject(ctx, key) //nolint:wrapcheck
+}
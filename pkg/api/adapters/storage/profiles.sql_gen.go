@@ -11,25 +11,108 @@ import (
 	"time"
 )
 
+const clearUserIndividualProfileID = `-- name: ClearUserIndividualProfileID :execrows
+UPDATE "user"
+SET individual_profile_id = NULL,
+  updated_at = NOW()
+WHERE individual_profile_id = $1
+`
+
+type ClearUserIndividualProfileIDParams struct {
+	IndividualProfileID sql.NullString `db:"individual_profile_id" json:"individual_profile_id"`
+}
+
+// ClearUserIndividualProfileID
+//
+//	UPDATE "user"
+//	SET individual_profile_id = NULL,
+//	  updated_at = NOW()
+//	WHERE individual_profile_id = $1
+func (q *Queries) ClearUserIndividualProfileID(ctx context.Context, arg ClearUserIndividualProfileIDParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, clearUserIndividualProfileID, arg.IndividualProfileID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const createProfile = `-- name: CreateProfile :exec
-INSERT INTO "profile" (id, slug)
-VALUES ($1, $2)
+INSERT INTO "profile" (id, slug, kind, profile_picture_uri, pronouns)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+  )
 `
 
 type CreateProfileParams struct {
-	ID   string `db:"id" json:"id"`
-	Slug string `db:"slug" json:"slug"`
+	ID                string         `db:"id" json:"id"`
+	Slug              string         `db:"slug" json:"slug"`
+	Kind              string         `db:"kind" json:"kind"`
+	ProfilePictureURI sql.NullString `db:"profile_picture_uri" json:"profile_picture_uri"`
+	Pronouns          sql.NullString `db:"pronouns" json:"pronouns"`
 }
 
 // CreateProfile
 //
-//	INSERT INTO "profile" (id, slug)
-//	VALUES ($1, $2)
+//	INSERT INTO "profile" (id, slug, kind, profile_picture_uri, pronouns)
+//	VALUES (
+//	    $1,
+//	    $2,
+//	    $3,
+//	    $4,
+//	    $5
+//	  )
 func (q *Queries) CreateProfile(ctx context.Context, arg CreateProfileParams) error {
-	_, err := q.db.ExecContext(ctx, createProfile, arg.ID, arg.Slug)
+	_, err := q.db.ExecContext(ctx, createProfile,
+		arg.ID,
+		arg.Slug,
+		arg.Kind,
+		arg.ProfilePictureURI,
+		arg.Pronouns,
+	)
 	return err
 }
 
+const createProfileMembershipForUser = `-- name: CreateProfileMembershipForUser :execrows
+INSERT INTO "profile_membership" (id, profile_id, member_profile_id, kind, started_at)
+SELECT $1, $2, u.individual_profile_id, $3, NOW()
+FROM "user" u
+WHERE u.id = $4
+  AND u.individual_profile_id IS NOT NULL
+  AND u.deleted_at IS NULL
+`
+
+type CreateProfileMembershipForUserParams struct {
+	ID        string `db:"id" json:"id"`
+	ProfileID string `db:"profile_id" json:"profile_id"`
+	Kind      string `db:"kind" json:"kind"`
+	UserID    string `db:"user_id" json:"user_id"`
+}
+
+// CreateProfileMembershipForUser
+//
+//	INSERT INTO "profile_membership" (id, profile_id, member_profile_id, kind, started_at)
+//	SELECT $1, $2, u.individual_profile_id, $3, NOW()
+//	FROM "user" u
+//	WHERE u.id = $4
+//	  AND u.individual_profile_id IS NOT NULL
+//	  AND u.deleted_at IS NULL
+func (q *Queries) CreateProfileMembershipForUser(ctx context.Context, arg CreateProfileMembershipForUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createProfileMembershipForUser,
+		arg.ID,
+		arg.ProfileID,
+		arg.Kind,
+		arg.UserID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getProfileByID = `-- name: GetProfileByID :one
 SELECT p.id, p.slug, p.kind, p.custom_domain, p.profile_picture_uri, p.pronouns, p.properties, p.created_at, p.updated_at, p.deleted_at, pt.profile_id, pt.locale_code, pt.title, pt.description, pt.properties
 FROM "profile" p
@@ -134,6 +217,32 @@ func (q *Queries) GetProfileIDBySlug(ctx context.Context, arg GetProfileIDBySlug
 	return id, err
 }
 
+const getProfileKindByID = `-- name: GetProfileKindByID :one
+SELECT kind
+FROM "profile"
+WHERE id = $1
+  AND deleted_at IS NULL
+LIMIT 1
+`
+
+type GetProfileKindByIDParams struct {
+	ID string `db:"id" json:"id"`
+}
+
+// GetProfileKindByID
+//
+//	SELECT kind
+//	FROM "profile"
+//	WHERE id = $1
+//	  AND deleted_at IS NULL
+//	LIMIT 1
+func (q *Queries) GetProfileKindByID(ctx context.Context, arg GetProfileKindByIDParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getProfileKindByID, arg.ID)
+	var kind string
+	err := row.Scan(&kind)
+	return kind, err
+}
+
 const getProfilePageByProfileIDAndSlug = `-- name: GetProfilePageByProfileIDAndSlug :one
 SELECT pp.id, pp.profile_id, pp.slug, pp."order", pp.cover_picture_uri, pp.published_at, pp.created_at, pp.updated_at, pp.deleted_at, ppt.profile_page_id, ppt.locale_code, ppt.title, ppt.summary, ppt.content
 FROM "profile_page" pp
@@ -196,6 +305,71 @@ func (q *Queries) GetProfilePageByProfileIDAndSlug(ctx context.Context, arg GetP
 	return &i, err
 }
 
+const getUserIndividualProfileID = `-- name: GetUserIndividualProfileID :one
+SELECT individual_profile_id
+FROM "user"
+WHERE id = $1
+  AND deleted_at IS NULL
+LIMIT 1
+`
+
+type GetUserIndividualProfileIDParams struct {
+	UserID string `db:"user_id" json:"user_id"`
+}
+
+// GetUserIndividualProfileID
+//
+//	SELECT individual_profile_id
+//	FROM "user"
+//	WHERE id = $1
+//	  AND deleted_at IS NULL
+//	LIMIT 1
+func (q *Queries) GetUserIndividualProfileID(ctx context.Context, arg GetUserIndividualProfileIDParams) (sql.NullString, error) {
+	row := q.db.QueryRowContext(ctx, getUserIndividualProfileID, arg.UserID)
+	var individual_profile_id sql.NullString
+	err := row.Scan(&individual_profile_id)
+	return individual_profile_id, err
+}
+
+const isProfileOwnedByUser = `-- name: IsProfileOwnedByUser :one
+SELECT EXISTS (
+    SELECT 1
+    FROM "user" u
+      LEFT JOIN "profile_membership" pm ON pm.member_profile_id = u.individual_profile_id
+      AND pm.profile_id = $1
+      AND pm.kind = 'owner'
+      AND pm.deleted_at IS NULL
+    WHERE u.id = $2
+      AND u.deleted_at IS NULL
+      AND (u.individual_profile_id = $1 OR pm.id IS NOT NULL)
+  ) AS is_owner
+`
+
+type IsProfileOwnedByUserParams struct {
+	ProfileID string `db:"profile_id" json:"profile_id"`
+	UserID    string `db:"user_id" json:"user_id"`
+}
+
+// IsProfileOwnedByUser
+//
+//	SELECT EXISTS (
+//	    SELECT 1
+//	    FROM "user" u
+//	      LEFT JOIN "profile_membership" pm ON pm.member_profile_id = u.individual_profile_id
+//	      AND pm.profile_id = $1
+//	      AND pm.kind = 'owner'
+//	      AND pm.deleted_at IS NULL
+//	    WHERE u.id = $2
+//	      AND u.deleted_at IS NULL
+//	      AND (u.individual_profile_id = $1 OR pm.id IS NOT NULL)
+//	  ) AS is_owner
+func (q *Queries) IsProfileOwnedByUser(ctx context.Context, arg IsProfileOwnedByUserParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isProfileOwnedByUser, arg.ProfileID, arg.UserID)
+	var is_owner bool
+	err := row.Scan(&is_owner)
+	return is_owner, err
+}
+
 const listProfileLinksByProfileID = `-- name: ListProfileLinksByProfileID :many
 SELECT id, profile_id, kind, "order", is_managed, is_verified, is_hidden, remote_id, public_id, uri, title, auth_provider, auth_access_token_scope, auth_access_token, auth_access_token_expires_at, auth_refresh_token, auth_refresh_token_expires_at, properties, created_at, updated_at, deleted_at
 FROM "profile_link"
@@ -633,28 +807,109 @@ func (q *Queries) RemoveProfile(ctx context.Context, arg RemoveProfileParams) (i
 	return result.RowsAffected()
 }
 
+const setUserIndividualProfileID = `-- name: SetUserIndividualProfileID :execrows
+UPDATE "user"
+SET individual_profile_id = $1,
+  updated_at = NOW()
+WHERE id = $2
+  AND individual_profile_id IS NULL
+  AND deleted_at IS NULL
+`
+
+type SetUserIndividualProfileIDParams struct {
+	IndividualProfileID sql.NullString `db:"individual_profile_id" json:"individual_profile_id"`
+	UserID              string         `db:"user_id" json:"user_id"`
+}
+
+// SetUserIndividualProfileID
+//
+//	UPDATE "user"
+//	SET individual_profile_id = $1,
+//	  updated_at = NOW()
+//	WHERE id = $2
+//	  AND individual_profile_id IS NULL
+//	  AND deleted_at IS NULL
+func (q *Queries) SetUserIndividualProfileID(ctx context.Context, arg SetUserIndividualProfileIDParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setUserIndividualProfileID, arg.IndividualProfileID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateProfile = `-- name: UpdateProfile :execrows
 UPDATE "profile"
-SET slug = $1
-WHERE id = $2
+SET slug = COALESCE($1, slug),
+  profile_picture_uri = COALESCE($2, profile_picture_uri),
+  pronouns = COALESCE($3, pronouns),
+  updated_at = NOW()
+WHERE id = $4
   AND deleted_at IS NULL
 `
 
 type UpdateProfileParams struct {
-	Slug string `db:"slug" json:"slug"`
-	ID   string `db:"id" json:"id"`
+	Slug              sql.NullString `db:"slug" json:"slug"`
+	ProfilePictureURI sql.NullString `db:"profile_picture_uri" json:"profile_picture_uri"`
+	Pronouns          sql.NullString `db:"pronouns" json:"pronouns"`
+	ID                string         `db:"id" json:"id"`
 }
 
 // UpdateProfile
 //
 //	UPDATE "profile"
-//	SET slug = $1
-//	WHERE id = $2
+//	SET slug = COALESCE($1, slug),
+//	  profile_picture_uri = COALESCE($2, profile_picture_uri),
+//	  pronouns = COALESCE($3, pronouns),
+//	  updated_at = NOW()
+//	WHERE id = $4
 //	  AND deleted_at IS NULL
 func (q *Queries) UpdateProfile(ctx context.Context, arg UpdateProfileParams) (int64, error) {
-	result, err := q.db.ExecContext(ctx, updateProfile, arg.Slug, arg.ID)
+	result, err := q.db.ExecContext(ctx, updateProfile,
+		arg.Slug,
+		arg.ProfilePictureURI,
+		arg.Pronouns,
+		arg.ID,
+	)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected()
 }
+
+const upsertProfileTx = `-- name: UpsertProfileTx :exec
+INSERT INTO "profile_tx" (profile_id, locale_code, title, description)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+  )
+ON CONFLICT (profile_id, locale_code) DO UPDATE SET title = $3, description = $4
+`
+
+type UpsertProfileTxParams struct {
+	ProfileID   string `db:"profile_id" json:"profile_id"`
+	LocaleCode  string `db:"locale_code" json:"locale_code"`
+	Title       string `db:"title" json:"title"`
+	Description string `db:"description" json:"description"`
+}
+
+// UpsertProfileTx
+//
+//	INSERT INTO "profile_tx" (profile_id, locale_code, title, description)
+//	VALUES (
+//	    $1,
+//	    $2,
+//	    $3,
+//	    $4
+//	  )
+//	ON CONFLICT (profile_id, locale_code) DO UPDATE SET title = $3, description = $4
+func (q *Queries) UpsertProfileTx(ctx context.Context, arg UpsertProfileTxParams) error {
+	_, err := q.db.ExecContext(ctx, upsertProfileTx,
+		arg.ProfileID,
+		arg.LocaleCode,
+		arg.Title,
+		arg.Description,
+	)
+	return err
+}
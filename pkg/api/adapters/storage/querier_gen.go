@@ -6,14 +6,160 @@ package storage
 
 import (
 	"context"
+	"database/sql"
 )
 
 type Querier interface {
+	//AcceptProfileMembershipInvitation
+	//
+	//  UPDATE "profile_membership_invitation"
+	//  SET accepted_at = NOW()
+	//  WHERE id = $1
+	//    AND accepted_at IS NULL
+	AcceptProfileMembershipInvitation(ctx context.Context, arg AcceptProfileMembershipInvitationParams) (int64, error)
+	//AddReaction
+	//
+	//  INSERT INTO "reaction" (id, target_kind, target_id, user_id, kind)
+	//  VALUES (
+	//      $1,
+	//      $2,
+	//      $3,
+	//      $4,
+	//      $5
+	//    )
+	//  ON CONFLICT (target_kind, target_id, user_id, kind) DO NOTHING
+	AddReaction(ctx context.Context, arg AddReactionParams) (int64, error)
+	//AwardProfileBadge
+	//
+	//  INSERT INTO "profile_badge" (id, profile_id, kind, source)
+	//  SELECT $1, $2, $3, 'rule'
+	//  WHERE NOT EXISTS (
+	//    SELECT 1
+	//    FROM "profile_badge"
+	//    WHERE profile_id = $2
+	//      AND kind = $3
+	//      AND revoked_by = 'admin'
+	//  )
+	//  ON CONFLICT (profile_id, kind) WHERE revoked_at IS NULL DO NOTHING
+	AwardProfileBadge(ctx context.Context, arg AwardProfileBadgeParams) (int64, error)
+	//ClaimProfileExports
+	//
+	//  UPDATE "profile_export"
+	//  SET status = 'running',
+	//    attempts = attempts + 1,
+	//    started_at = NOW()
+	//  WHERE id IN (
+	//      SELECT pe.id
+	//      FROM "profile_export" pe
+	//      WHERE pe.status = 'pending'
+	//        OR (pe.status = 'running' AND pe.started_at < $1)
+	//      ORDER BY pe.created_at
+	//      LIMIT $2
+	//      FOR UPDATE SKIP LOCKED
+	//    )
+	//  RETURNING id, profile_id, requested_by_user_id, status, attempts, object_key, size_bytes, last_error, created_at, started_at, completed_at, expires_at
+	ClaimProfileExports(ctx context.Context, arg ClaimProfileExportsParams) ([]*ProfileExport, error)
+	//ClearUserIndividualProfileID
+	//
+	//  UPDATE "user"
+	//  SET individual_profile_id = NULL,
+	//    updated_at = NOW()
+	//  WHERE individual_profile_id = $1
+	ClearUserIndividualProfileID(ctx context.Context, arg ClearUserIndividualProfileIDParams) (int64, error)
+	//CompleteProfileExport
+	//
+	//  UPDATE "profile_export"
+	//  SET status = 'completed',
+	//    object_key = $1,
+	//    size_bytes = $2,
+	//    last_error = NULL,
+	//    completed_at = NOW(),
+	//    expires_at = $3
+	//  WHERE id = $4
+	//    AND status = 'running'
+	CompleteProfileExport(ctx context.Context, arg CompleteProfileExportParams) (int64, error)
+	//CountProfileMembershipsOfKind
+	//
+	//  SELECT COUNT(*)
+	//  FROM "profile_membership"
+	//  WHERE profile_id = $1
+	//    AND kind = $2
+	//    AND deleted_at IS NULL
+	CountProfileMembershipsOfKind(ctx context.Context, arg CountProfileMembershipsOfKindParams) (int64, error)
+	//CreateAPIKey
+	//
+	//  INSERT INTO "api_key" (id, name, key_prefix, key_hash, scopes, expires_at, created_at)
+	//  VALUES ($1, $2, $3, $4, $5, $6, $7)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) error
+	//CreateCustomDomainClaim
+	//
+	//  INSERT INTO "profile_custom_domain_claim" (
+	//      id,
+	//      profile_id,
+	//      domain,
+	//      challenge_token,
+	//      status,
+	//      expires_at,
+	//      created_at
+	//    )
+	//  VALUES (
+	//      $1,
+	//      $2,
+	//      $3,
+	//      $4,
+	//      $5,
+	//      $6,
+	//      $7
+	//    )
+	CreateCustomDomainClaim(ctx context.Context, arg CreateCustomDomainClaimParams) error
 	//CreateProfile
 	//
-	//  INSERT INTO "profile" (id, slug)
-	//  VALUES ($1, $2)
+	//  INSERT INTO "profile" (id, slug, kind, profile_picture_uri, pronouns)
+	//  VALUES (
+	//      $1,
+	//      $2,
+	//      $3,
+	//      $4,
+	//      $5
+	//    )
 	CreateProfile(ctx context.Context, arg CreateProfileParams) error
+	//CreateProfileExport
+	//
+	//  INSERT INTO "profile_export" (id, profile_id, requested_by_user_id, status)
+	//  VALUES ($1, $2, $3, 'pending')
+	CreateProfileExport(ctx context.Context, arg CreateProfileExportParams) error
+	//CreateProfileMembershipForUser
+	//
+	//  INSERT INTO "profile_membership" (id, profile_id, member_profile_id, kind, started_at)
+	//  SELECT $1, $2, u.individual_profile_id, $3, NOW()
+	//  FROM "user" u
+	//  WHERE u.id = $4
+	//    AND u.individual_profile_id IS NOT NULL
+	//    AND u.deleted_at IS NULL
+	CreateProfileMembershipForUser(ctx context.Context, arg CreateProfileMembershipForUserParams) (int64, error)
+	//CreateProfileMembershipInvitation
+	//
+	//  INSERT INTO "profile_membership_invitation" (
+	//      id,
+	//      profile_id,
+	//      member_profile_id,
+	//      kind,
+	//      token_hash,
+	//      invited_by_user_id,
+	//      expires_at,
+	//      created_at
+	//    )
+	//  VALUES (
+	//      $1,
+	//      $2,
+	//      $3,
+	//      $4,
+	//      $5,
+	//      $6,
+	//      $7,
+	//      $8
+	//    )
+	CreateProfileMembershipInvitation(ctx context.Context, arg CreateProfileMembershipInvitationParams) error
 	//CreateSession
 	//
 	//  INSERT INTO
@@ -43,6 +189,57 @@ type Querier interface {
 	//      $10
 	//    )
 	CreateSession(ctx context.Context, arg CreateSessionParams) error
+	//CreateStory
+	//
+	//  INSERT INTO "story" (id, author_profile_id, slug, kind, status, story_picture_uri, title, summary, content)
+	//  VALUES (
+	//      $1,
+	//      $2,
+	//      $3,
+	//      $4,
+	//      $5,
+	//      $6,
+	//      $7,
+	//      $8,
+	//      $9
+	//    )
+	CreateStory(ctx context.Context, arg CreateStoryParams) error
+	//CreateStoryComment
+	//
+	//  INSERT INTO "story_comment" (id, story_id, parent_id, author_user_id, content, status)
+	//  VALUES (
+	//      $1,
+	//      $2,
+	//      $3,
+	//      $4,
+	//      $5,
+	//      $6
+	//    )
+	CreateStoryComment(ctx context.Context, arg CreateStoryCommentParams) error
+	//CreateStoryCommentReport
+	//
+	//  INSERT INTO "story_comment_report" (id, comment_id, reporter_user_id, reason)
+	//  VALUES (
+	//      $1,
+	//      $2,
+	//      $3,
+	//      $4
+	//    )
+	//  ON CONFLICT (comment_id, reporter_user_id) DO NOTHING
+	CreateStoryCommentReport(ctx context.Context, arg CreateStoryCommentReportParams) (int64, error)
+	//CreateStoryRevision
+	//
+	//  INSERT INTO "story_revision" (id, story_id, locale_code, author_user_id, title, summary, content)
+	//  VALUES (
+	//      $1,
+	//      $2,
+	//      $3,
+	//      $4,
+	//      $5,
+	//      $6,
+	//      $7
+	//    )
+	CreateStoryRevision(ctx context.Context, arg CreateStoryRevisionParams) error
 	//CreateUser
 	//
 	//  INSERT INTO "user" (
@@ -80,6 +277,57 @@ type Querier interface {
 	//      $15
 	//    )
 	CreateUser(ctx context.Context, arg CreateUserParams) error
+	//DecrementReactionCount
+	//
+	//  UPDATE "reaction_count"
+	//  SET count = GREATEST(count - 1, 0)
+	//  WHERE target_kind = $1
+	//    AND target_id = $2
+	//    AND kind = $3
+	DecrementReactionCount(ctx context.Context, arg DecrementReactionCountParams) error
+	//ExpireProfileExport
+	//
+	//  UPDATE "profile_export"
+	//  SET status = 'expired'
+	//  WHERE id = $1
+	//    AND status = 'completed'
+	ExpireProfileExport(ctx context.Context, arg ExpireProfileExportParams) (int64, error)
+	//FollowProfile
+	//
+	//  INSERT INTO "profile_follow" (id, follower_profile_id, followed_profile_id)
+	//  VALUES ($1, $2, $3)
+	//  ON CONFLICT (follower_profile_id, followed_profile_id) DO NOTHING
+	FollowProfile(ctx context.Context, arg FollowProfileParams) (int64, error)
+	//GetAPIKeyByHash
+	//
+	//  SELECT id, name, key_prefix, key_hash, scopes, expires_at, last_used_at, created_at, revoked_at
+	//  FROM "api_key"
+	//  WHERE key_hash = $1
+	//  LIMIT 1
+	GetAPIKeyByHash(ctx context.Context, arg GetAPIKeyByHashParams) (*ApiKey, error)
+	//GetActiveProfileExport
+	//
+	//  SELECT id, profile_id, requested_by_user_id, status, attempts, object_key, size_bytes, last_error, created_at, started_at, completed_at, expires_at
+	//  FROM "profile_export"
+	//  WHERE profile_id = $1
+	//    AND status IN ('pending', 'running')
+	//  ORDER BY created_at DESC
+	//  LIMIT 1
+	GetActiveProfileExport(ctx context.Context, arg GetActiveProfileExportParams) (*ProfileExport, error)
+	//GetAuthoredStoryBySlug
+	//
+	//  SELECT
+	//    s.id,
+	//    s.status,
+	//    p.id AS author_profile_id,
+	//    p.slug AS author_profile_slug
+	//  FROM "story" s
+	//    INNER JOIN "profile" p ON p.id = s.author_profile_id
+	//    AND p.deleted_at IS NULL
+	//  WHERE s.slug = $1
+	//    AND s.deleted_at IS NULL
+	//  LIMIT 1
+	GetAuthoredStoryBySlug(ctx context.Context, arg GetAuthoredStoryBySlugParams) (*GetAuthoredStoryBySlugRow, error)
 	//GetFromCache
 	//
 	//  SELECT value, updated_at
@@ -95,6 +343,14 @@ type Querier interface {
 	//    AND updated_at > $2
 	//  LIMIT 1
 	GetFromCacheSince(ctx context.Context, arg GetFromCacheSinceParams) (*GetFromCacheSinceRow, error)
+	//GetLatestCustomDomainClaimByProfileID
+	//
+	//  SELECT id, profile_id, domain, challenge_token, status, attempts, last_error, last_checked_at, verified_at, expires_at, created_at
+	//  FROM "profile_custom_domain_claim"
+	//  WHERE profile_id = $1
+	//  ORDER BY created_at DESC
+	//  LIMIT 1
+	GetLatestCustomDomainClaimByProfileID(ctx context.Context, arg GetLatestCustomDomainClaimByProfileIDParams) (*ProfileCustomDomainClaim, error)
 	//GetProfileByID
 	//
 	//  SELECT p.id, p.slug, p.kind, p.custom_domain, p.profile_picture_uri, p.pronouns, p.properties, p.created_at, p.updated_at, p.deleted_at, pt.profile_id, pt.locale_code, pt.title, pt.description, pt.properties
@@ -105,6 +361,22 @@ type Querier interface {
 	//    AND p.deleted_at IS NULL
 	//  LIMIT 1
 	GetProfileByID(ctx context.Context, arg GetProfileByIDParams) (*GetProfileByIDRow, error)
+	//GetProfileExport
+	//
+	//  SELECT id, profile_id, requested_by_user_id, status, attempts, object_key, size_bytes, last_error, created_at, started_at, completed_at, expires_at
+	//  FROM "profile_export"
+	//  WHERE id = $1
+	//    AND profile_id = $2
+	//  LIMIT 1
+	GetProfileExport(ctx context.Context, arg GetProfileExportParams) (*ProfileExport, error)
+	//GetProfileForExport
+	//
+	//  SELECT id, slug, kind, custom_domain, profile_picture_uri, pronouns, properties, created_at, updated_at, deleted_at
+	//  FROM "profile"
+	//  WHERE id = $1
+	//    AND deleted_at IS NULL
+	//  LIMIT 1
+	GetProfileForExport(ctx context.Context, arg GetProfileForExportParams) (*Profile, error)
 	//GetProfileIDByCustomDomain
 	//
 	//  SELECT id
@@ -121,6 +393,35 @@ type Querier interface {
 	//    AND deleted_at IS NULL
 	//  LIMIT 1
 	GetProfileIDBySlug(ctx context.Context, arg GetProfileIDBySlugParams) (string, error)
+	//GetProfileKindByID
+	//
+	//  SELECT kind
+	//  FROM "profile"
+	//  WHERE id = $1
+	//    AND deleted_at IS NULL
+	//  LIMIT 1
+	GetProfileKindByID(ctx context.Context, arg GetProfileKindByIDParams) (string, error)
+	//GetProfileMembershipInvitationByTokenHash
+	//
+	//  SELECT pmi.id, pmi.profile_id, pmi.member_profile_id, pmi.kind, pmi.token_hash, pmi.invited_by_user_id, pmi.expires_at, pmi.accepted_at, pmi.created_at, p.slug AS profile_slug, mp.slug AS member_profile_slug
+	//  FROM "profile_membership_invitation" pmi
+	//    INNER JOIN "profile" p ON p.id = pmi.profile_id
+	//    AND p.deleted_at IS NULL
+	//    INNER JOIN "profile" mp ON mp.id = pmi.member_profile_id
+	//    AND mp.deleted_at IS NULL
+	//  WHERE pmi.token_hash = $1
+	//    AND pmi.accepted_at IS NULL
+	//  LIMIT 1
+	GetProfileMembershipInvitationByTokenHash(ctx context.Context, arg GetProfileMembershipInvitationByTokenHashParams) (*GetProfileMembershipInvitationByTokenHashRow, error)
+	//GetProfileMembershipKind
+	//
+	//  SELECT kind
+	//  FROM "profile_membership"
+	//  WHERE profile_id = $1
+	//    AND member_profile_id = $2
+	//    AND deleted_at IS NULL
+	//  LIMIT 1
+	GetProfileMembershipKind(ctx context.Context, arg GetProfileMembershipKindParams) (string, error)
 	//GetProfilePageByProfileIDAndSlug
 	//
 	//  SELECT pp.id, pp.profile_id, pp.slug, pp."order", pp.cover_picture_uri, pp.published_at, pp.created_at, pp.updated_at, pp.deleted_at, ppt.profile_page_id, ppt.locale_code, ppt.title, ppt.summary, ppt.content
@@ -130,6 +431,27 @@ type Querier interface {
 	//  WHERE pp.profile_id = $2 AND pp.slug = $3 AND pp.deleted_at IS NULL
 	//  ORDER BY pp."order"
 	GetProfilePageByProfileIDAndSlug(ctx context.Context, arg GetProfilePageByProfileIDAndSlugParams) (*GetProfilePageByProfileIDAndSlugRow, error)
+	//GetProfileSlugRedirect
+	//
+	//  SELECT p.slug
+	//  FROM "slug_redirect" sr
+	//    INNER JOIN "profile" p ON p.id = sr.target_id
+	//    AND p.deleted_at IS NULL
+	//  WHERE sr.kind = 'profile'
+	//    AND sr.old_slug = $1
+	//  LIMIT 1
+	GetProfileSlugRedirect(ctx context.Context, arg GetProfileSlugRedirectParams) (string, error)
+	//GetProfileViewSummary
+	//
+	//  SELECT
+	//    COUNT(*) FILTER (WHERE story_id IS NULL) AS profile_views,
+	//    COUNT(*) FILTER (WHERE story_id IS NOT NULL) AS story_views,
+	//    COUNT(DISTINCT story_id) AS viewed_stories
+	//  FROM "profile_view"
+	//  WHERE profile_id = $1
+	//    AND viewed_on >= $2::DATE
+	//    AND viewed_on <= $3::DATE
+	GetProfileViewSummary(ctx context.Context, arg GetProfileViewSummaryParams) (*GetProfileViewSummaryRow, error)
 	//GetSessionByID
 	//
 	//  SELECT
@@ -151,7 +473,7 @@ type Querier interface {
 	//GetStoryByID
 	//
 	//  SELECT
-	//    s.id, s.author_profile_id, s.slug, s.kind, s.status, s.is_featured, s.story_picture_uri, s.title, s.summary, s.content, s.properties, s.created_at, s.updated_at, s.deleted_at,
+	//    s.id, s.author_profile_id, s.slug, s.kind, s.status, s.is_featured, s.story_picture_uri, s.title, s.summary, s.content, s.properties, s.created_at, s.updated_at, s.deleted_at, s.publish_at,
 	//    st.story_id, st.locale_code, st.title, st.summary, st.content,
 	//    p.id, p.slug, p.kind, p.custom_domain, p.profile_picture_uri, p.pronouns, p.properties, p.created_at, p.updated_at, p.deleted_at,
 	//    pt.profile_id, pt.locale_code, pt.title, pt.description, pt.properties,
@@ -181,6 +503,32 @@ type Querier interface {
 	//    AND s.deleted_at IS NULL
 	//  LIMIT 1
 	GetStoryByID(ctx context.Context, arg GetStoryByIDParams) (*GetStoryByIDRow, error)
+	//GetStoryComment
+	//
+	//  SELECT c.id, c.story_id, c.parent_id, c.author_user_id, c.content, c.status, c.created_at, c.updated_at, c.deleted_at,
+	//    u.name AS author_name,
+	//    p.slug AS author_profile_slug,
+	//    (
+	//      SELECT COUNT(*)
+	//      FROM "story_comment" r
+	//      WHERE r.parent_id = c.id
+	//        AND r.deleted_at IS NULL
+	//    ) AS reply_count,
+	//    (
+	//      SELECT COUNT(*)
+	//      FROM "story_comment_report" cr
+	//      WHERE cr.comment_id = c.id
+	//        AND cr.resolved_at IS NULL
+	//    ) AS report_count
+	//  FROM "story_comment" c
+	//    INNER JOIN "user" u ON u.id = c.author_user_id
+	//    LEFT JOIN "profile" p ON p.id = u.individual_profile_id
+	//    AND p.deleted_at IS NULL
+	//  WHERE c.id = $1
+	//    AND c.story_id = $2
+	//    AND c.deleted_at IS NULL
+	//  LIMIT 1
+	GetStoryComment(ctx context.Context, arg GetStoryCommentParams) (*GetStoryCommentRow, error)
 	//GetStoryIDBySlug
 	//
 	//  SELECT id
@@ -189,6 +537,25 @@ type Querier interface {
 	//    AND deleted_at IS NULL
 	//  LIMIT 1
 	GetStoryIDBySlug(ctx context.Context, arg GetStoryIDBySlugParams) (string, error)
+	//GetStoryRevision
+	//
+	//  SELECT id, story_id, locale_code, author_user_id, title, summary, content, created_at
+	//  FROM "story_revision"
+	//  WHERE id = $1
+	//    AND story_id = $2
+	//    AND locale_code = $3
+	//  LIMIT 1
+	GetStoryRevision(ctx context.Context, arg GetStoryRevisionParams) (*StoryRevision, error)
+	//GetStorySlugRedirect
+	//
+	//  SELECT s.slug
+	//  FROM "slug_redirect" sr
+	//    INNER JOIN "story" s ON s.id = sr.target_id
+	//    AND s.deleted_at IS NULL
+	//  WHERE sr.kind = 'story'
+	//    AND sr.old_slug = $1
+	//  LIMIT 1
+	GetStorySlugRedirect(ctx context.Context, arg GetStorySlugRedirectParams) (string, error)
 	//GetUserByEmail
 	//
 	//  SELECT id, kind, name, email, phone, github_handle, github_remote_id, bsky_handle, bsky_remote_id, x_handle, x_remote_id, individual_profile_id, created_at, updated_at, deleted_at
@@ -205,6 +572,151 @@ type Querier interface {
 	//    AND deleted_at IS NULL
 	//  LIMIT 1
 	GetUserByID(ctx context.Context, arg GetUserByIDParams) (*User, error)
+	//GetUserIndividualProfileID
+	//
+	//  SELECT individual_profile_id
+	//  FROM "user"
+	//  WHERE id = $1
+	//    AND deleted_at IS NULL
+	//  LIMIT 1
+	GetUserIndividualProfileID(ctx context.Context, arg GetUserIndividualProfileIDParams) (sql.NullString, error)
+	//GrantProfileBadge
+	//
+	//  INSERT INTO "profile_badge" (id, profile_id, kind, source, note)
+	//  VALUES ($1, $2, $3, $4, $5)
+	//  ON CONFLICT (profile_id, kind) WHERE revoked_at IS NULL DO NOTHING
+	GrantProfileBadge(ctx context.Context, arg GrantProfileBadgeParams) (int64, error)
+	//IncrementReactionCount
+	//
+	//  INSERT INTO "reaction_count" (target_kind, target_id, kind, count)
+	//  VALUES ($1, $2, $3, 1)
+	//  ON CONFLICT (target_kind, target_id, kind) DO UPDATE
+	//  SET count = "reaction_count".count + 1
+	IncrementReactionCount(ctx context.Context, arg IncrementReactionCountParams) error
+	//IsProfileOwnedByUser
+	//
+	//  SELECT EXISTS (
+	//      SELECT 1
+	//      FROM "user" u
+	//        LEFT JOIN "profile_membership" pm ON pm.member_profile_id = u.individual_profile_id
+	//        AND pm.profile_id = $1
+	//        AND pm.kind = 'owner'
+	//        AND pm.deleted_at IS NULL
+	//      WHERE u.id = $2
+	//        AND u.deleted_at IS NULL
+	//        AND (u.individual_profile_id = $1 OR pm.id IS NOT NULL)
+	//    ) AS is_owner
+	IsProfileOwnedByUser(ctx context.Context, arg IsProfileOwnedByUserParams) (bool, error)
+	//ListAPIKeys
+	//
+	//  SELECT id, name, key_prefix, key_hash, scopes, expires_at, last_used_at, created_at, revoked_at
+	//  FROM "api_key"
+	//  ORDER BY created_at DESC
+	ListAPIKeys(ctx context.Context) ([]*ApiKey, error)
+	//ListCoreContributorBadgeCandidates
+	//
+	//  SELECT DISTINCT p.id, p.slug
+	//  FROM "profile_membership" pm
+	//    INNER JOIN "profile" p ON p.id = pm.member_profile_id
+	//    AND p.kind = 'individual'
+	//    AND p.deleted_at IS NULL
+	//    INNER JOIN "profile" pp ON pp.id = pm.profile_id
+	//    AND pp.kind = 'product'
+	//    AND pp.deleted_at IS NULL
+	//  WHERE pm.kind IN ('owner', 'maintainer')
+	//    AND (pm.finished_at IS NULL OR pm.finished_at > NOW())
+	//    AND pm.deleted_at IS NULL
+	ListCoreContributorBadgeCandidates(ctx context.Context) ([]*ListCoreContributorBadgeCandidatesRow, error)
+	//ListDueStories
+	//
+	//  SELECT
+	//    s.id,
+	//    s.status,
+	//    p.id AS author_profile_id,
+	//    p.slug AS author_profile_slug
+	//  FROM "story" s
+	//    INNER JOIN "profile" p ON p.id = s.author_profile_id
+	//    AND p.deleted_at IS NULL
+	//  WHERE s.publish_at <= $1
+	//    AND s.status IN ('draft', 'in_review')
+	//    AND s.deleted_at IS NULL
+	//  ORDER BY s.publish_at
+	//  LIMIT $2
+	ListDueStories(ctx context.Context, arg ListDueStoriesParams) ([]*ListDueStoriesRow, error)
+	//ListEventSpeakerBadgeCandidates
+	//
+	//  SELECT DISTINCT p.id, p.slug
+	//  FROM "event_attendance" ea
+	//    INNER JOIN "event" e ON e.id = ea.event_id
+	//    AND e.deleted_at IS NULL
+	//    INNER JOIN "profile" p ON p.id = ea.profile_id
+	//    AND p.deleted_at IS NULL
+	//  WHERE ea.kind = 'speaker'
+	//    AND ea.deleted_at IS NULL
+	ListEventSpeakerBadgeCandidates(ctx context.Context) ([]*ListEventSpeakerBadgeCandidatesRow, error)
+	//ListExpiredProfileExports
+	//
+	//  SELECT id, profile_id, requested_by_user_id, status, attempts, object_key, size_bytes, last_error, created_at, started_at, completed_at, expires_at
+	//  FROM "profile_export"
+	//  WHERE status = 'completed'
+	//    AND expires_at < NOW()
+	//  ORDER BY expires_at
+	//  LIMIT $1
+	ListExpiredProfileExports(ctx context.Context, arg ListExpiredProfileExportsParams) ([]*ProfileExport, error)
+	//ListPendingCustomDomainClaims
+	//
+	//  SELECT c.id, c.profile_id, c.domain, c.challenge_token, c.status, c.attempts, c.last_error, c.last_checked_at, c.verified_at, c.expires_at, c.created_at, p.slug AS profile_slug, p.custom_domain AS current_domain
+	//  FROM "profile_custom_domain_claim" c
+	//    INNER JOIN "profile" p ON p.id = c.profile_id
+	//    AND p.deleted_at IS NULL
+	//  WHERE c.status = 'pending'
+	//  ORDER BY c.last_checked_at NULLS FIRST
+	//  LIMIT $1
+	ListPendingCustomDomainClaims(ctx context.Context, arg ListPendingCustomDomainClaimsParams) ([]*ListPendingCustomDomainClaimsRow, error)
+	//ListProfileBadgeHoldersBySource
+	//
+	//  SELECT p.id, p.slug
+	//  FROM "profile_badge" pb
+	//    INNER JOIN "profile" p ON p.id = pb.profile_id
+	//    AND p.deleted_at IS NULL
+	//  WHERE pb.kind = $1
+	//    AND pb.source = $2
+	//    AND pb.revoked_at IS NULL
+	ListProfileBadgeHoldersBySource(ctx context.Context, arg ListProfileBadgeHoldersBySourceParams) ([]*ListProfileBadgeHoldersBySourceRow, error)
+	//ListProfileBadges
+	//
+	//  SELECT id, profile_id, kind, source, note, granted_at, revoked_at, revoked_by
+	//  FROM "profile_badge"
+	//  WHERE profile_id = $1
+	//    AND revoked_at IS NULL
+	//  ORDER BY granted_at, kind
+	ListProfileBadges(ctx context.Context, arg ListProfileBadgesParams) ([]*ProfileBadge, error)
+	//ListProfileFollowers
+	//
+	//  SELECT pf.id, pf.follower_profile_id, pf.followed_profile_id, pf.created_at, p.id, p.slug, p.kind, p.custom_domain, p.profile_picture_uri, p.pronouns, p.properties, p.created_at, p.updated_at, p.deleted_at, pt.profile_id, pt.locale_code, pt.title, pt.description, pt.properties
+	//  FROM "profile_follow" pf
+	//    INNER JOIN "profile" p ON p.id = pf.follower_profile_id
+	//    AND p.deleted_at IS NULL
+	//    INNER JOIN "profile_tx" pt ON pt.profile_id = p.id
+	//    AND pt.locale_code = $1
+	//  WHERE pf.followed_profile_id = $2
+	//    AND ($3::CHAR(26) IS NULL OR pf.id < $3::CHAR(26))
+	//  ORDER BY pf.id DESC
+	//  LIMIT $4
+	ListProfileFollowers(ctx context.Context, arg ListProfileFollowersParams) ([]*ListProfileFollowersRow, error)
+	//ListProfileFollowing
+	//
+	//  SELECT pf.id, pf.follower_profile_id, pf.followed_profile_id, pf.created_at, p.id, p.slug, p.kind, p.custom_domain, p.profile_picture_uri, p.pronouns, p.properties, p.created_at, p.updated_at, p.deleted_at, pt.profile_id, pt.locale_code, pt.title, pt.description, pt.properties
+	//  FROM "profile_follow" pf
+	//    INNER JOIN "profile" p ON p.id = pf.followed_profile_id
+	//    AND p.deleted_at IS NULL
+	//    INNER JOIN "profile_tx" pt ON pt.profile_id = p.id
+	//    AND pt.locale_code = $1
+	//  WHERE pf.follower_profile_id = $2
+	//    AND ($3::CHAR(26) IS NULL OR pf.id < $3::CHAR(26))
+	//  ORDER BY pf.id DESC
+	//  LIMIT $4
+	ListProfileFollowing(ctx context.Context, arg ListProfileFollowingParams) ([]*ListProfileFollowingRow, error)
 	//ListProfileLinksByProfileID
 	//
 	//  SELECT id, profile_id, kind, "order", is_managed, is_verified, is_hidden, remote_id, public_id, uri, title, auth_provider, auth_access_token_scope, auth_access_token, auth_access_token_expires_at, auth_refresh_token, auth_refresh_token_expires_at, properties, created_at, updated_at, deleted_at
@@ -214,6 +726,14 @@ type Querier interface {
 	//    AND deleted_at IS NULL
 	//  ORDER BY "order"
 	ListProfileLinksByProfileID(ctx context.Context, arg ListProfileLinksByProfileIDParams) ([]*ProfileLink, error)
+	//ListProfileLinksForExport
+	//
+	//  SELECT id, kind, "order", is_verified, is_hidden, public_id, uri, title, created_at
+	//  FROM "profile_link"
+	//  WHERE profile_id = $1
+	//    AND deleted_at IS NULL
+	//  ORDER BY "order"
+	ListProfileLinksForExport(ctx context.Context, arg ListProfileLinksForExportParams) ([]*ListProfileLinksForExportRow, error)
 	//ListProfileLinksForKind
 	//
 	//  SELECT pl.id, pl.profile_id, pl.kind, pl."order", pl.is_managed, pl.is_verified, pl.is_hidden, pl.remote_id, pl.public_id, pl.uri, pl.title, pl.auth_provider, pl.auth_access_token_scope, pl.auth_access_token, pl.auth_access_token_expires_at, pl.auth_refresh_token, pl.auth_refresh_token_expires_at, pl.properties, pl.created_at, pl.updated_at, pl.deleted_at
@@ -248,6 +768,26 @@ type Querier interface {
 	//      AND ($4::TEXT IS NULL OR pm.profile_id = $4::TEXT)
 	//      AND ($5::TEXT IS NULL OR pm.member_profile_id = $5::TEXT)
 	ListProfileMemberships(ctx context.Context, arg ListProfileMembershipsParams) ([]*ListProfileMembershipsRow, error)
+	//ListProfileMembershipsForExport
+	//
+	//  SELECT pm.id, p.slug AS profile_slug, mp.slug AS member_profile_slug, pm.kind,
+	//    pm.started_at, pm.finished_at, pm.created_at
+	//  FROM "profile_membership" pm
+	//    INNER JOIN "profile" p ON p.id = pm.profile_id
+	//    INNER JOIN "profile" mp ON mp.id = pm.member_profile_id
+	//  WHERE (pm.profile_id = $1 OR pm.member_profile_id = $1)
+	//    AND pm.deleted_at IS NULL
+	//  ORDER BY pm.created_at
+	ListProfileMembershipsForExport(ctx context.Context, arg ListProfileMembershipsForExportParams) ([]*ListProfileMembershipsForExportRow, error)
+	//ListProfilePageTxsForExport
+	//
+	//  SELECT ppt.profile_page_id, ppt.locale_code, ppt.title, ppt.summary, ppt.content
+	//  FROM "profile_page_tx" ppt
+	//    INNER JOIN "profile_page" pp ON pp.id = ppt.profile_page_id
+	//    AND pp.deleted_at IS NULL
+	//  WHERE pp.profile_id = $1
+	//  ORDER BY ppt.profile_page_id, ppt.locale_code
+	ListProfilePageTxsForExport(ctx context.Context, arg ListProfilePageTxsForExportParams) ([]*ProfilePageTx, error)
 	//ListProfilePagesByProfileID
 	//
 	//  SELECT pp.id, pp.profile_id, pp.slug, pp."order", pp.cover_picture_uri, pp.published_at, pp.created_at, pp.updated_at, pp.deleted_at, ppt.profile_page_id, ppt.locale_code, ppt.title, ppt.summary, ppt.content
@@ -258,6 +798,34 @@ type Querier interface {
 	//    AND pp.deleted_at IS NULL
 	//  ORDER BY pp."order"
 	ListProfilePagesByProfileID(ctx context.Context, arg ListProfilePagesByProfileIDParams) ([]*ListProfilePagesByProfileIDRow, error)
+	//ListProfilePagesForExport
+	//
+	//  SELECT id, profile_id, slug, "order", cover_picture_uri, published_at, created_at, updated_at, deleted_at
+	//  FROM "profile_page"
+	//  WHERE profile_id = $1
+	//    AND deleted_at IS NULL
+	//  ORDER BY "order"
+	ListProfilePagesForExport(ctx context.Context, arg ListProfilePagesForExportParams) ([]*ProfilePage, error)
+	//ListProfileTxsForExport
+	//
+	//  SELECT profile_id, locale_code, title, description, properties
+	//  FROM "profile_tx"
+	//  WHERE profile_id = $1
+	//  ORDER BY locale_code
+	ListProfileTxsForExport(ctx context.Context, arg ListProfileTxsForExportParams) ([]*ProfileTx, error)
+	//ListProfileViewsByDay
+	//
+	//  SELECT
+	//    viewed_on,
+	//    COUNT(*) FILTER (WHERE story_id IS NULL) AS profile_views,
+	//    COUNT(*) FILTER (WHERE story_id IS NOT NULL) AS story_views
+	//  FROM "profile_view"
+	//  WHERE profile_id = $1
+	//    AND viewed_on >= $2::DATE
+	//    AND viewed_on <= $3::DATE
+	//  GROUP BY viewed_on
+	//  ORDER BY viewed_on
+	ListProfileViewsByDay(ctx context.Context, arg ListProfileViewsByDayParams) ([]*ListProfileViewsByDayRow, error)
 	//ListProfiles
 	//
 	//  SELECT p.id, p.slug, p.kind, p.custom_domain, p.profile_picture_uri, p.pronouns, p.properties, p.created_at, p.updated_at, p.deleted_at, pt.profile_id, pt.locale_code, pt.title, pt.description, pt.properties
@@ -281,7 +849,7 @@ type Querier interface {
 	//
 	//
 	//  SELECT
-	//    s.id, s.author_profile_id, s.slug, s.kind, s.status, s.is_featured, s.story_picture_uri, s.title, s.summary, s.content, s.properties, s.created_at, s.updated_at, s.deleted_at,
+	//    s.id, s.author_profile_id, s.slug, s.kind, s.status, s.is_featured, s.story_picture_uri, s.title, s.summary, s.content, s.properties, s.created_at, s.updated_at, s.deleted_at, s.publish_at,
 	//    st.story_id, st.locale_code, st.title, st.summary, st.content,
 	//    p1.id, p1.slug, p1.kind, p1.custom_domain, p1.profile_picture_uri, p1.pronouns, p1.properties, p1.created_at, p1.updated_at, p1.deleted_at,
 	//    p1t.profile_id, p1t.locale_code, p1t.title, p1t.description, p1t.properties,
@@ -313,6 +881,183 @@ type Querier interface {
 	//    AND s.deleted_at IS NULL
 	//  ORDER BY s.created_at DESC
 	ListStoriesOfPublication(ctx context.Context, arg ListStoriesOfPublicationParams) ([]*ListStoriesOfPublicationRow, error)
+	//ListReactionCounts
+	//
+	//  SELECT target_id, kind, count
+	//  FROM "reaction_count"
+	//  WHERE target_kind = $1
+	//    AND target_id = ANY($2::CHAR(26)[])
+	//    AND count > 0
+	//  ORDER BY target_id, count DESC, kind
+	ListReactionCounts(ctx context.Context, arg ListReactionCountsParams) ([]*ListReactionCountsRow, error)
+	//ListSitemapProfilePages
+	//
+	//  SELECT
+	//    p.slug AS profile_slug,
+	//    pp.slug,
+	//    ppt.locale_code,
+	//    COALESCE(pp.updated_at, pp.created_at) AS last_modified_at
+	//  FROM "profile_page" pp
+	//    INNER JOIN "profile" p ON p.id = pp.profile_id
+	//    AND p.deleted_at IS NULL
+	//    INNER JOIN "profile_page_tx" ppt ON ppt.profile_page_id = pp.id
+	//  WHERE pp.deleted_at IS NULL
+	//  ORDER BY p.slug, pp."order", pp.slug, ppt.locale_code
+	ListSitemapProfilePages(ctx context.Context) ([]*ListSitemapProfilePagesRow, error)
+	//ListSitemapProfiles
+	//
+	//  SELECT
+	//    p.slug,
+	//    pt.locale_code,
+	//    COALESCE(p.updated_at, p.created_at) AS last_modified_at
+	//  FROM "profile" p
+	//    INNER JOIN "profile_tx" pt ON pt.profile_id = p.id
+	//  WHERE p.deleted_at IS NULL
+	//  ORDER BY p.slug, pt.locale_code
+	ListSitemapProfiles(ctx context.Context) ([]*ListSitemapProfilesRow, error)
+	//ListSitemapStories
+	//
+	//  SELECT
+	//    s.slug,
+	//    st.locale_code,
+	//    COALESCE(s.updated_at, s.created_at) AS last_modified_at
+	//  FROM "story" s
+	//    INNER JOIN "profile" p ON p.id = s.author_profile_id
+	//    AND p.deleted_at IS NULL
+	//    INNER JOIN "story_tx" st ON st.story_id = s.id
+	//  WHERE s.deleted_at IS NULL
+	//    AND EXISTS (
+	//      SELECT 1
+	//      FROM "story_publication" sp
+	//        INNER JOIN "profile" p2 ON p2.id = sp.profile_id
+	//        AND p2.deleted_at IS NULL
+	//      WHERE sp.story_id = s.id
+	//        AND sp.deleted_at IS NULL
+	//    )
+	//  ORDER BY s.slug, st.locale_code
+	ListSitemapStories(ctx context.Context) ([]*ListSitemapStoriesRow, error)
+	//ListStoriesForExport
+	//
+	//  SELECT id, author_profile_id, slug, kind, status, is_featured, story_picture_uri, title, summary, content, properties, created_at, updated_at, deleted_at, publish_at
+	//  FROM "story"
+	//  WHERE author_profile_id = $1
+	//    AND deleted_at IS NULL
+	//  ORDER BY created_at
+	ListStoriesForExport(ctx context.Context, arg ListStoriesForExportParams) ([]*Story, error)
+	//ListStoriesOfFollowedProfiles
+	//
+	//  SELECT
+	//    s.id, s.author_profile_id, s.slug, s.kind, s.status, s.is_featured, s.story_picture_uri, s.title, s.summary, s.content, s.properties, s.created_at, s.updated_at, s.deleted_at, s.publish_at,
+	//    st.story_id, st.locale_code, st.title, st.summary, st.content,
+	//    p1.id, p1.slug, p1.kind, p1.custom_domain, p1.profile_picture_uri, p1.pronouns, p1.properties, p1.created_at, p1.updated_at, p1.deleted_at,
+	//    p1t.profile_id, p1t.locale_code, p1t.title, p1t.description, p1t.properties,
+	//    pb.publications
+	//  FROM "story" s
+	//    INNER JOIN "story_tx" st ON st.story_id = s.id
+	//    AND st.locale_code = $1
+	//    LEFT JOIN "profile" p1 ON p1.id = s.author_profile_id
+	//    AND p1.deleted_at IS NULL
+	//    INNER JOIN "profile_tx" p1t ON p1t.profile_id = p1.id
+	//    AND p1t.locale_code = $1
+	//    LEFT JOIN LATERAL (
+	//      SELECT JSONB_AGG(
+	//        JSONB_BUILD_OBJECT('profile', row_to_json(p2), 'profile_tx', row_to_json(p2t))
+	//      ) AS "publications"
+	//      FROM story_publication sp
+	//        INNER JOIN "profile" p2 ON p2.id = sp.profile_id
+	//        AND p2.deleted_at IS NULL
+	//        INNER JOIN "profile_tx" p2t ON p2t.profile_id = p2.id
+	//        AND p2t.locale_code = $1
+	//      WHERE sp.story_id = s.id
+	//        AND sp.deleted_at IS NULL
+	//    ) pb ON TRUE
+	//  WHERE
+	//    pb.publications IS NOT NULL
+	//    AND (
+	//      s.author_profile_id IN (
+	//        SELECT pf.followed_profile_id
+	//        FROM "profile_follow" pf
+	//        WHERE pf.follower_profile_id = $2
+	//      )
+	//      OR EXISTS (
+	//        SELECT 1
+	//        FROM "story_publication" fsp
+	//          INNER JOIN "profile_follow" fpf ON fpf.followed_profile_id = fsp.profile_id
+	//          AND fpf.follower_profile_id = $2
+	//        WHERE fsp.story_id = s.id
+	//          AND fsp.deleted_at IS NULL
+	//      )
+	//    )
+	//    AND ($3::CHAR(26) IS NULL OR s.id < $3::CHAR(26))
+	//    AND s.deleted_at IS NULL
+	//  ORDER BY s.id DESC
+	//  LIMIT $4
+	ListStoriesOfFollowedProfiles(ctx context.Context, arg ListStoriesOfFollowedProfilesParams) ([]*ListStoriesOfFollowedProfilesRow, error)
+	//ListStoryComments
+	//
+	//  SELECT c.id, c.story_id, c.parent_id, c.author_user_id, c.content, c.status, c.created_at, c.updated_at, c.deleted_at,
+	//    u.name AS author_name,
+	//    p.slug AS author_profile_slug,
+	//    (
+	//      SELECT COUNT(*)
+	//      FROM "story_comment" r
+	//      WHERE r.parent_id = c.id
+	//        AND r.deleted_at IS NULL
+	//    ) AS reply_count,
+	//    (
+	//      SELECT COUNT(*)
+	//      FROM "story_comment_report" cr
+	//      WHERE cr.comment_id = c.id
+	//        AND cr.resolved_at IS NULL
+	//    ) AS report_count
+	//  FROM "story_comment" c
+	//    INNER JOIN "user" u ON u.id = c.author_user_id
+	//    LEFT JOIN "profile" p ON p.id = u.individual_profile_id
+	//    AND p.deleted_at IS NULL
+	//  WHERE c.story_id = $1
+	//    AND (
+	//      ($2::CHAR(26) IS NULL AND c.parent_id IS NULL)
+	//      OR c.parent_id = $2::CHAR(26)
+	//    )
+	//    AND (
+	//      c.deleted_at IS NULL
+	//      OR EXISTS (
+	//        SELECT 1
+	//        FROM "story_comment" r
+	//        WHERE r.parent_id = c.id
+	//          AND r.deleted_at IS NULL
+	//      )
+	//    )
+	//    AND (
+	//      NOT $3::BOOLEAN
+	//      OR EXISTS (
+	//        SELECT 1
+	//        FROM "story_comment_report" cr
+	//        WHERE cr.comment_id = c.id
+	//          AND cr.resolved_at IS NULL
+	//      )
+	//    )
+	//    AND ($4::CHAR(26) IS NULL OR c.id > $4::CHAR(26))
+	//  ORDER BY c.id
+	//  LIMIT $5
+	ListStoryComments(ctx context.Context, arg ListStoryCommentsParams) ([]*ListStoryCommentsRow, error)
+	//ListStoryRevisions
+	//
+	//  SELECT id, story_id, locale_code, author_user_id, title, summary, content, created_at
+	//  FROM "story_revision"
+	//  WHERE story_id = $1
+	//    AND locale_code = $2
+	//  ORDER BY id DESC
+	ListStoryRevisions(ctx context.Context, arg ListStoryRevisionsParams) ([]*StoryRevision, error)
+	//ListStoryTxsForExport
+	//
+	//  SELECT st.story_id, st.locale_code, st.title, st.summary, st.content
+	//  FROM "story_tx" st
+	//    INNER JOIN "story" s ON s.id = st.story_id
+	//    AND s.deleted_at IS NULL
+	//  WHERE s.author_profile_id = $1
+	//  ORDER BY st.story_id, st.locale_code
+	ListStoryTxsForExport(ctx context.Context, arg ListStoryTxsForExportParams) ([]*StoryTx, error)
 	//ListUsers
 	//
 	//  SELECT id, kind, name, email, phone, github_handle, github_remote_id, bsky_handle, bsky_remote_id, x_handle, x_remote_id, individual_profile_id, created_at, updated_at, deleted_at
@@ -320,6 +1065,66 @@ type Querier interface {
 	//  WHERE ($1::TEXT IS NULL OR kind = ANY(string_to_array($1::TEXT, ',')))
 	//    AND deleted_at IS NULL
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]*User, error)
+	//ListVerifiedBadgeCandidates
+	//
+	//  SELECT p.id, p.slug
+	//  FROM "profile" p
+	//  WHERE p.custom_domain IS NOT NULL
+	//    AND p.deleted_at IS NULL
+	ListVerifiedBadgeCandidates(ctx context.Context) ([]*ListVerifiedBadgeCandidatesRow, error)
+	//PruneStoryRevisions
+	//
+	//  DELETE FROM "story_revision"
+	//  WHERE story_id = $1
+	//    AND locale_code = $2
+	//    AND id NOT IN (
+	//      SELECT id
+	//      FROM "story_revision"
+	//      WHERE story_id = $1
+	//        AND locale_code = $2
+	//      ORDER BY id DESC
+	//      LIMIT $3
+	//    )
+	PruneStoryRevisions(ctx context.Context, arg PruneStoryRevisionsParams) (int64, error)
+	//RecordCustomDomainClaimCheck
+	//
+	//  UPDATE "profile_custom_domain_claim"
+	//  SET status = $1,
+	//    attempts = attempts + 1,
+	//    last_error = $2,
+	//    last_checked_at = NOW()
+	//  WHERE id = $3
+	//    AND status = 'pending'
+	RecordCustomDomainClaimCheck(ctx context.Context, arg RecordCustomDomainClaimCheckParams) (int64, error)
+	//RecordProfileExportFailure
+	//
+	//  UPDATE "profile_export"
+	//  SET status = $1,
+	//    last_error = $2
+	//  WHERE id = $3
+	//    AND status = 'running'
+	RecordProfileExportFailure(ctx context.Context, arg RecordProfileExportFailureParams) (int64, error)
+	//RecordProfileView
+	//
+	//  INSERT INTO "profile_view" (id, profile_id, story_id, visitor_hash, viewed_on)
+	//  SELECT $1, p.id, NULL, $2, $3
+	//  FROM "profile" p
+	//  WHERE p.slug = $4
+	//    AND p.deleted_at IS NULL
+	//  LIMIT 1
+	//  ON CONFLICT DO NOTHING
+	RecordProfileView(ctx context.Context, arg RecordProfileViewParams) (int64, error)
+	//RecordStoryView
+	//
+	//  INSERT INTO "profile_view" (id, profile_id, story_id, visitor_hash, viewed_on)
+	//  SELECT $1, s.author_profile_id, s.id, $2, $3
+	//  FROM "story" s
+	//  WHERE s.slug = $4
+	//    AND s.author_profile_id IS NOT NULL
+	//    AND s.deleted_at IS NULL
+	//  LIMIT 1
+	//  ON CONFLICT DO NOTHING
+	RecordStoryView(ctx context.Context, arg RecordStoryViewParams) (int64, error)
 	//RemoveAllFromCache
 	//
 	//  DELETE FROM "cache"
@@ -341,6 +1146,47 @@ type Querier interface {
 	//  WHERE id = $1
 	//    AND deleted_at IS NULL
 	RemoveProfile(ctx context.Context, arg RemoveProfileParams) (int64, error)
+	//RemoveProfileMembership
+	//
+	//  UPDATE "profile_membership"
+	//  SET finished_at = NOW(),
+	//    deleted_at = NOW()
+	//  WHERE profile_id = $1
+	//    AND member_profile_id = $2
+	//    AND deleted_at IS NULL
+	RemoveProfileMembership(ctx context.Context, arg RemoveProfileMembershipParams) (int64, error)
+	//RemoveReaction
+	//
+	//  DELETE FROM "reaction"
+	//  WHERE target_kind = $1
+	//    AND target_id = $2
+	//    AND user_id = $3
+	//    AND kind = $4
+	RemoveReaction(ctx context.Context, arg RemoveReactionParams) (int64, error)
+	//RemoveStory
+	//
+	//  UPDATE "story"
+	//  SET deleted_at = NOW()
+	//  WHERE id = $1
+	//    AND deleted_at IS NULL
+	RemoveStory(ctx context.Context, arg RemoveStoryParams) (int64, error)
+	//RemoveStoryComment
+	//
+	//  UPDATE "story_comment"
+	//  SET status = 'deleted',
+	//    deleted_at = NOW()
+	//  WHERE id = $1
+	//    AND deleted_at IS NULL
+	RemoveStoryComment(ctx context.Context, arg RemoveStoryCommentParams) (int64, error)
+	//RemoveStoryPublication
+	//
+	//  UPDATE "story_publication"
+	//  SET deleted_at = NOW()
+	//  WHERE story_id = $1
+	//    AND profile_id = $2
+	//    AND kind = $3
+	//    AND deleted_at IS NULL
+	RemoveStoryPublication(ctx context.Context, arg RemoveStoryPublicationParams) (int64, error)
 	//RemoveUser
 	//
 	//  UPDATE "user"
@@ -348,19 +1194,126 @@ type Querier interface {
 	//  WHERE id = $1
 	//    AND deleted_at IS NULL
 	RemoveUser(ctx context.Context, arg RemoveUserParams) (int64, error)
+	//ResolveStoryCommentReports
+	//
+	//  UPDATE "story_comment_report"
+	//  SET resolved_at = NOW()
+	//  WHERE comment_id = $1
+	//    AND resolved_at IS NULL
+	ResolveStoryCommentReports(ctx context.Context, arg ResolveStoryCommentReportsParams) (int64, error)
+	//RevokeAPIKey
+	//
+	//  UPDATE "api_key"
+	//  SET revoked_at = NOW()
+	//  WHERE id = $1
+	//    AND revoked_at IS NULL
+	RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (int64, error)
+	//RevokeProfileBadge
+	//
+	//  UPDATE "profile_badge"
+	//  SET revoked_at = NOW(),
+	//    revoked_by = $1
+	//  WHERE profile_id = $2
+	//    AND kind = $3
+	//    AND ($4::TEXT IS NULL OR source = $4::TEXT)
+	//    AND revoked_at IS NULL
+	RevokeProfileBadge(ctx context.Context, arg RevokeProfileBadgeParams) (int64, error)
 	//SetInCache
 	//
 	//  INSERT INTO "cache" (key, value, updated_at)
 	//  VALUES ($1, $2, NOW())
 	//  ON CONFLICT ("key") DO UPDATE SET value = $2, updated_at = NOW()
 	SetInCache(ctx context.Context, arg SetInCacheParams) (int64, error)
-	//UpdateProfile
+	//SetProfileCustomDomain
 	//
 	//  UPDATE "profile"
-	//  SET slug = $1
+	//  SET custom_domain = $1,
+	//    updated_at = NOW()
 	//  WHERE id = $2
 	//    AND deleted_at IS NULL
+	//    AND NOT EXISTS (
+	//      SELECT 1
+	//      FROM "profile" other
+	//      WHERE other.custom_domain = $1
+	//        AND other.id <> $2
+	//        AND other.deleted_at IS NULL
+	//    )
+	SetProfileCustomDomain(ctx context.Context, arg SetProfileCustomDomainParams) (int64, error)
+	//SetStoryPublishAt
+	//
+	//  UPDATE "story"
+	//  SET publish_at = $1,
+	//    updated_at = NOW()
+	//  WHERE id = $2
+	//    AND status IN ('draft', 'in_review')
+	//    AND deleted_at IS NULL
+	SetStoryPublishAt(ctx context.Context, arg SetStoryPublishAtParams) (int64, error)
+	//SetUserIndividualProfileID
+	//
+	//  UPDATE "user"
+	//  SET individual_profile_id = $1,
+	//    updated_at = NOW()
+	//  WHERE id = $2
+	//    AND individual_profile_id IS NULL
+	//    AND deleted_at IS NULL
+	SetUserIndividualProfileID(ctx context.Context, arg SetUserIndividualProfileIDParams) (int64, error)
+	//SupersedeCustomDomainClaims
+	//
+	//  UPDATE "profile_custom_domain_claim"
+	//  SET status = 'superseded'
+	//  WHERE profile_id = $1
+	//    AND status = 'pending'
+	SupersedeCustomDomainClaims(ctx context.Context, arg SupersedeCustomDomainClaimsParams) (int64, error)
+	//TryLockJob
+	//
+	//  INSERT INTO "job_lock" (name, owner, expires_at)
+	//  VALUES (
+	//      $1,
+	//      $2,
+	//      NOW() + ($3::INTEGER * INTERVAL '1 second')
+	//    )
+	//  ON CONFLICT (name) DO UPDATE
+	//  SET owner = EXCLUDED.owner,
+	//    expires_at = EXCLUDED.expires_at
+	//  WHERE "job_lock".expires_at < NOW()
+	TryLockJob(ctx context.Context, arg TryLockJobParams) (int64, error)
+	//UnfollowProfile
+	//
+	//  DELETE FROM "profile_follow"
+	//  WHERE follower_profile_id = $1
+	//    AND followed_profile_id = $2
+	UnfollowProfile(ctx context.Context, arg UnfollowProfileParams) (int64, error)
+	//UnlockJob
+	//
+	//  DELETE FROM "job_lock"
+	//  WHERE name = $1
+	//    AND owner = $2
+	UnlockJob(ctx context.Context, arg UnlockJobParams) (int64, error)
+	//UpdateAPIKeyLastUsedAt
+	//
+	//  UPDATE "api_key"
+	//  SET last_used_at = $1
+	//  WHERE id = $2
+	UpdateAPIKeyLastUsedAt(ctx context.Context, arg UpdateAPIKeyLastUsedAtParams) error
+	//UpdateProfile
+	//
+	//  UPDATE "profile"
+	//  SET slug = COALESCE($1, slug),
+	//    profile_picture_uri = COALESCE($2, profile_picture_uri),
+	//    pronouns = COALESCE($3, pronouns),
+	//    updated_at = NOW()
+	//  WHERE id = $4
+	//    AND deleted_at IS NULL
 	UpdateProfile(ctx context.Context, arg UpdateProfileParams) (int64, error)
+	//UpdateProfileMembershipKind
+	//
+	//  UPDATE "profile_membership"
+	//  SET kind = $1,
+	//    updated_at = NOW()
+	//  WHERE profile_id = $2
+	//    AND member_profile_id = $3
+	//    AND deleted_at IS NULL
+	UpdateProfileMembershipKind(ctx context.Context, arg UpdateProfileMembershipKindParams) (int64, error)
 	//UpdateSessionLoggedInAt
 	//
 	//  UPDATE
@@ -371,6 +1324,33 @@ type Querier interface {
 	//  WHERE
 	//    id = $2
 	UpdateSessionLoggedInAt(ctx context.Context, arg UpdateSessionLoggedInAtParams) error
+	//UpdateStory
+	//
+	//  UPDATE "story"
+	//  SET slug = COALESCE($1, slug),
+	//    story_picture_uri = COALESCE($2, story_picture_uri),
+	//    updated_at = NOW()
+	//  WHERE id = $3
+	//    AND deleted_at IS NULL
+	UpdateStory(ctx context.Context, arg UpdateStoryParams) (int64, error)
+	//UpdateStoryCommentStatus
+	//
+	//  UPDATE "story_comment"
+	//  SET status = $1,
+	//    updated_at = NOW()
+	//  WHERE id = $2
+	//    AND deleted_at IS NULL
+	UpdateStoryCommentStatus(ctx context.Context, arg UpdateStoryCommentStatusParams) (int64, error)
+	//UpdateStoryStatus
+	//
+	//  UPDATE "story"
+	//  SET status = $1,
+	//    publish_at = CASE WHEN $1::TEXT = 'published' THEN NULL ELSE publish_at END,
+	//    updated_at = NOW()
+	//  WHERE id = $2
+	//    AND status = $3
+	//    AND deleted_at IS NULL
+	UpdateStoryStatus(ctx context.Context, arg UpdateStoryStatusParams) (int64, error)
 	//UpdateUser
 	//
 	//  UPDATE "user"
@@ -388,6 +1368,75 @@ type Querier interface {
 	//  WHERE id = $12
 	//    AND deleted_at IS NULL
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (int64, error)
+	//UpsertProfileMembership
+	//
+	//  INSERT INTO "profile_membership" (id, profile_id, member_profile_id, kind, started_at)
+	//  VALUES (
+	//      $1,
+	//      $2,
+	//      $3,
+	//      $4,
+	//      NOW()
+	//    )
+	//  ON CONFLICT (profile_id, member_profile_id) DO UPDATE SET kind = $4,
+	//    started_at = NOW(),
+	//    finished_at = NULL,
+	//    updated_at = NOW(),
+	//    deleted_at = NULL
+	UpsertProfileMembership(ctx context.Context, arg UpsertProfileMembershipParams) error
+	//UpsertProfileTx
+	//
+	//  INSERT INTO "profile_tx" (profile_id, locale_code, title, description)
+	//  VALUES (
+	//      $1,
+	//      $2,
+	//      $3,
+	//      $4
+	//    )
+	//  ON CONFLICT (profile_id, locale_code) DO UPDATE SET title = $3, description = $4
+	UpsertProfileTx(ctx context.Context, arg UpsertProfileTxParams) error
+	//UpsertSlugRedirect
+	//
+	//  INSERT INTO "slug_redirect" (kind, old_slug, target_id)
+	//  VALUES ($1, $2, $3)
+	//  ON CONFLICT (kind, old_slug) DO UPDATE
+	//  SET target_id = EXCLUDED.target_id,
+	//    created_at = NOW()
+	UpsertSlugRedirect(ctx context.Context, arg UpsertSlugRedirectParams) error
+	//UpsertStoryPublication
+	//
+	//  INSERT INTO "story_publication" (id, story_id, profile_id, kind)
+	//  VALUES (
+	//      $1,
+	//      $2,
+	//      $3,
+	//      $4
+	//    )
+	//  ON CONFLICT (story_id, profile_id, kind) DO UPDATE SET deleted_at = NULL, updated_at = NOW()
+	UpsertStoryPublication(ctx context.Context, arg UpsertStoryPublicationParams) error
+	//UpsertStoryTx
+	//
+	//  INSERT INTO "story_tx" (story_id, locale_code, title, summary, content)
+	//  VALUES (
+	//      $1,
+	//      $2,
+	//      $3,
+	//      $4,
+	//      $5
+	//    )
+	//  ON CONFLICT (story_id, locale_code) DO UPDATE SET title = $3, summary = $4, content = $5
+	UpsertStoryTx(ctx context.Context, arg UpsertStoryTxParams) error
+	//VerifyCustomDomainClaim
+	//
+	//  UPDATE "profile_custom_domain_claim"
+	//  SET status = 'verified',
+	//    attempts = attempts + 1,
+	//    last_error = NULL,
+	//    last_checked_at = NOW(),
+	//    verified_at = NOW()
+	//  WHERE id = $1
+	//    AND status = 'pending'
+	VerifyCustomDomainClaim(ctx context.Context, arg VerifyCustomDomainClaimParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
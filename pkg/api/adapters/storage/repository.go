@@ -19,6 +19,7 @@ const (
 var ErrDatasourceNotFound = errors.New("datasource not found")
 
 type Repository struct {
+	db       *sql.DB
 	queries  *Queries
 	cache    *caching.Cache
 	logger   *logfx.Logger
@@ -43,6 +44,7 @@ func NewRepositoryFromNamed(
 	}
 
 	repository := &Repository{ //nolint:exhaustruct
+		db:       sqlDB,
 		queries:  &Queries{db: sqlDB},
 		cacheTTL: DefaultCacheTTL,
 		logger:   logger,
@@ -87,3 +89,19 @@ func NewRepositoryFromNamed(
 
 	return repository, nil
 }
+
+// withTx runs fn with queries bound to a transaction, which is committed when fn
+// succeeds and rolled back otherwise.
+func (r *Repository) withTx(ctx context.Context, fn func(queries *Queries) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	err = fn(r.queries.WithTx(tx))
+	if err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	return tx.Commit() //nolint:wrapcheck
+}
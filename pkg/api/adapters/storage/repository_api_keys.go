@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/api/business/users"
+	"github.com/eser/aya.is-services/pkg/lib/vars"
+)
+
+func (r *Repository) CreateAPIKey(
+	ctx context.Context,
+	apiKey *users.APIKey,
+	keyHash string,
+) error {
+	err := r.queries.CreateAPIKey(ctx, CreateAPIKeyParams{
+		ID:        apiKey.ID,
+		Name:      apiKey.Name,
+		KeyPrefix: apiKey.Prefix,
+		KeyHash:   keyHash,
+		Scopes:    strings.Join(apiKey.Scopes, " "),
+		ExpiresAt: vars.ToSQLNullTime(apiKey.ExpiresAt),
+		CreatedAt: apiKey.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) GetAPIKeyByHash(
+	ctx context.Context,
+	keyHash string,
+) (*users.APIKey, error) {
+	row, err := r.queries.GetAPIKeyByHash(ctx, GetAPIKeyByHashParams{KeyHash: keyHash})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, err
+	}
+
+	return toAPIKey(row), nil
+}
+
+func (r *Repository) ListAPIKeys(ctx context.Context) ([]*users.APIKey, error) {
+	rows, err := r.queries.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*users.APIKey, len(rows))
+	for i, row := range rows {
+		result[i] = toAPIKey(row)
+	}
+
+	return result, nil
+}
+
+func (r *Repository) RevokeAPIKey(ctx context.Context, id string) (int64, error) {
+	return r.queries.RevokeAPIKey(ctx, RevokeAPIKeyParams{ID: id})
+}
+
+func (r *Repository) UpdateAPIKeyLastUsedAt(
+	ctx context.Context,
+	id string,
+	lastUsedAt time.Time,
+) error {
+	err := r.queries.UpdateAPIKeyLastUsedAt(ctx, UpdateAPIKeyLastUsedAtParams{
+		LastUsedAt: sql.NullTime{Time: lastUsedAt, Valid: true},
+		ID:         id,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func toAPIKey(row *ApiKey) *users.APIKey {
+	return &users.APIKey{
+		CreatedAt:  row.CreatedAt,
+		ExpiresAt:  vars.ToTimePtr(row.ExpiresAt),
+		LastUsedAt: vars.ToTimePtr(row.LastUsedAt),
+		RevokedAt:  vars.ToTimePtr(row.RevokedAt),
+		ID:         row.ID,
+		Name:       row.Name,
+		Prefix:     row.KeyPrefix,
+		Scopes:     strings.Fields(row.Scopes),
+	}
+}
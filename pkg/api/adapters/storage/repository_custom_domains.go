@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/eser/aya.is-services/pkg/lib/vars"
+)
+
+func (r *Repository) CreateCustomDomainClaim(
+	ctx context.Context,
+	claim *profiles.CustomDomainClaim,
+) error {
+	return r.withTx(ctx, func(queries *Queries) error {
+		_, err := queries.SupersedeCustomDomainClaims(
+			ctx,
+			SupersedeCustomDomainClaimsParams{ProfileID: claim.ProfileID},
+		)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		return queries.CreateCustomDomainClaim(ctx, CreateCustomDomainClaimParams{
+			ID:             claim.ID,
+			ProfileID:      claim.ProfileID,
+			Domain:         claim.Domain,
+			ChallengeToken: claim.ChallengeToken,
+			Status:         claim.Status,
+			ExpiresAt:      claim.ExpiresAt,
+			CreatedAt:      claim.CreatedAt,
+		})
+	})
+}
+
+func (r *Repository) GetLatestCustomDomainClaim(
+	ctx context.Context,
+	profileID string,
+) (*profiles.CustomDomainClaim, error) {
+	row, err := r.queries.GetLatestCustomDomainClaimByProfileID(
+		ctx,
+		GetLatestCustomDomainClaimByProfileIDParams{ProfileID: profileID},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, err
+	}
+
+	return toCustomDomainClaim(row), nil
+}
+
+func (r *Repository) ListPendingCustomDomainClaims(
+	ctx context.Context,
+	limit int,
+) ([]*profiles.CustomDomainClaim, error) {
+	rows, err := r.queries.ListPendingCustomDomainClaims(
+		ctx,
+		ListPendingCustomDomainClaimsParams{LimitCount: int32(limit)}, //nolint:gosec
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*profiles.CustomDomainClaim, len(rows))
+
+	for i, row := range rows {
+		result[i] = toCustomDomainClaim(&row.ProfileCustomDomainClaim)
+		result[i].ProfileSlug = row.ProfileSlug
+		result[i].CurrentDomain = vars.ToStringPtr(row.CurrentDomain)
+	}
+
+	return result, nil
+}
+
+func (r *Repository) RecordCustomDomainClaimCheck(
+	ctx context.Context,
+	id string,
+	status string,
+	lastError string,
+) (int64, error) {
+	return r.queries.RecordCustomDomainClaimCheck( //nolint:wrapcheck
+		ctx,
+		RecordCustomDomainClaimCheckParams{
+			Status:    status,
+			LastError: sql.NullString{String: lastError, Valid: lastError != ""},
+			ID:        id,
+		},
+	)
+}
+
+func (r *Repository) VerifyCustomDomainClaim(
+	ctx context.Context,
+	claim *profiles.CustomDomainClaim,
+) (bool, error) {
+	var verified bool
+
+	err := r.withTx(ctx, func(queries *Queries) error {
+		updated, err := queries.VerifyCustomDomainClaim(
+			ctx,
+			VerifyCustomDomainClaimParams{ID: claim.ID},
+		)
+		if err != nil || updated == 0 {
+			return err
+		}
+
+		updated, err = queries.SetProfileCustomDomain(ctx, SetProfileCustomDomainParams{
+			CustomDomain: sql.NullString{String: claim.Domain, Valid: true},
+			ID:           claim.ProfileID,
+		})
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		if updated == 0 {
+			return fmt.Errorf("%w(custom_domain: %s)", profiles.ErrCustomDomainTaken, claim.Domain)
+		}
+
+		verified = true
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	r.forgetProfileIDByCustomDomain(ctx, claim.Domain)
+
+	if claim.CurrentDomain != nil && *claim.CurrentDomain != claim.Domain {
+		r.forgetProfileIDByCustomDomain(ctx, *claim.CurrentDomain)
+	}
+
+	return verified, nil
+}
+
+func (r *Repository) forgetProfileIDByCustomDomain(ctx context.Context, domain string) {
+	err := r.CacheRemove(ctx, "profile_id_by_custom_domain:"+domain)
+	if err != nil {
+		r.logger.WarnContext(
+			ctx,
+			"failed to remove cached profile id",
+			"custom_domain", domain,
+			"error", err,
+		)
+	}
+}
+
+func toCustomDomainClaim(row *ProfileCustomDomainClaim) *profiles.CustomDomainClaim {
+	return &profiles.CustomDomainClaim{
+		CreatedAt:      row.CreatedAt,
+		ExpiresAt:      row.ExpiresAt,
+		LastCheckedAt:  vars.ToTimePtr(row.LastCheckedAt),
+		VerifiedAt:     vars.ToTimePtr(row.VerifiedAt),
+		LastError:      vars.ToStringPtr(row.LastError),
+		CurrentDomain:  nil,
+		ID:             row.ID,
+		ProfileID:      row.ProfileID,
+		ProfileSlug:    "",
+		Domain:         row.Domain,
+		ChallengeToken: row.ChallengeToken,
+		Status:         row.Status,
+		Attempts:       int(row.Attempts),
+	}
+}
@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// TryLock takes the lock of the job unless another owner holds it and it has not
+// expired yet. It satisfies processfx.Locker.
+func (r *Repository) TryLock(
+	ctx context.Context,
+	name string,
+	owner string,
+	ttl time.Duration,
+) (bool, error) {
+	affected, err := r.queries.TryLockJob(ctx, TryLockJobParams{
+		Name:       name,
+		Owner:      owner,
+		TtlSeconds: int32(ttl / time.Second), //nolint:gosec
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// Unlock releases the lock of the job if the owner still holds it.
+func (r *Repository) Unlock(ctx context.Context, name string, owner string) error {
+	_, err := r.queries.UnlockJob(ctx, UnlockJobParams{Name: name, Owner: owner})
+
+	return err
+}
@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/eser/aya.is-services/pkg/lib/vars"
+)
+
+func (r *Repository) ListProfileBadges(
+	ctx context.Context,
+	profileID string,
+) ([]*profiles.ProfileBadge, error) {
+	rows, err := r.queries.ListProfileBadges(ctx, ListProfileBadgesParams{ProfileID: profileID})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*profiles.ProfileBadge, len(rows))
+	for i, row := range rows {
+		result[i] = &profiles.ProfileBadge{
+			GrantedAt: row.GrantedAt,
+			Note:      vars.ToStringPtr(row.Note),
+			ID:        row.ID,
+			ProfileID: row.ProfileID,
+			Kind:      row.Kind,
+			Source:    row.Source,
+		}
+	}
+
+	return result, nil
+}
+
+func (r *Repository) GrantProfileBadge(
+	ctx context.Context,
+	badge *profiles.ProfileBadge,
+) (bool, error) {
+	affected, err := r.queries.GrantProfileBadge(
+		ctx,
+		GrantProfileBadgeParams{
+			ID:        badge.ID,
+			ProfileID: badge.ProfileID,
+			Kind:      badge.Kind,
+			Source:    badge.Source,
+			Note:      vars.ToSQLNullString(badge.Note),
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (r *Repository) AwardProfileBadge(
+	ctx context.Context,
+	id string,
+	profileID string,
+	kind string,
+) (bool, error) {
+	affected, err := r.queries.AwardProfileBadge(
+		ctx,
+		AwardProfileBadgeParams{ID: id, ProfileID: profileID, Kind: kind},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (r *Repository) RevokeProfileBadge(
+	ctx context.Context,
+	profileID string,
+	kind string,
+	source string,
+	revokedBy string,
+) (bool, error) {
+	affected, err := r.queries.RevokeProfileBadge(
+		ctx,
+		RevokeProfileBadgeParams{
+			RevokedBy:    sql.NullString{String: revokedBy, Valid: true},
+			ProfileID:    profileID,
+			Kind:         kind,
+			FilterSource: sql.NullString{String: source, Valid: source != ""},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (r *Repository) ListBadgeHolders(
+	ctx context.Context,
+	kind string,
+	source string,
+) ([]*profiles.BadgeHolder, error) {
+	rows, err := r.queries.ListProfileBadgeHoldersBySource(
+		ctx,
+		ListProfileBadgeHoldersBySourceParams{Kind: kind, Source: source},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*profiles.BadgeHolder, len(rows))
+	for i, row := range rows {
+		result[i] = &profiles.BadgeHolder{ProfileID: row.ID, ProfileSlug: row.Slug}
+	}
+
+	return result, nil
+}
+
+func (r *Repository) ListBadgeRuleMatches(
+	ctx context.Context,
+	kind string,
+) ([]*profiles.BadgeHolder, error) {
+	var result []*profiles.BadgeHolder
+
+	switch kind {
+	case profiles.BadgeKindVerified:
+		rows, err := r.queries.ListVerifiedBadgeCandidates(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			result = append(result, &profiles.BadgeHolder{ProfileID: row.ID, ProfileSlug: row.Slug})
+		}
+	case profiles.BadgeKindCoreContributor:
+		rows, err := r.queries.ListCoreContributorBadgeCandidates(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			result = append(result, &profiles.BadgeHolder{ProfileID: row.ID, ProfileSlug: row.Slug})
+		}
+	case profiles.BadgeKindEventSpeaker:
+		rows, err := r.queries.ListEventSpeakerBadgeCandidates(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			result = append(result, &profiles.BadgeHolder{ProfileID: row.ID, ProfileSlug: row.Slug})
+		}
+	default:
+		return nil, fmt.Errorf("%w(kind: %s)", profiles.ErrInvalidBadgeKind, kind)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,353 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/eser/aya.is-services/pkg/lib/vars"
+)
+
+func (r *Repository) CreateProfileExport(
+	ctx context.Context,
+	export *profiles.ProfileExport,
+	requestedByUserID string,
+) error {
+	return r.queries.CreateProfileExport( //nolint:wrapcheck
+		ctx,
+		CreateProfileExportParams{
+			ID:                export.ID,
+			ProfileID:         export.ProfileID,
+			RequestedByUserID: requestedByUserID,
+		},
+	)
+}
+
+func (r *Repository) GetProfileExport(
+	ctx context.Context,
+	id string,
+	profileID string,
+) (*profiles.ProfileExport, error) {
+	row, err := r.queries.GetProfileExport(
+		ctx,
+		GetProfileExportParams{ID: id, ProfileID: profileID},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, err
+	}
+
+	return toProfileExport(row), nil
+}
+
+func (r *Repository) GetActiveProfileExport(
+	ctx context.Context,
+	profileID string,
+) (*profiles.ProfileExport, error) {
+	row, err := r.queries.GetActiveProfileExport(
+		ctx,
+		GetActiveProfileExportParams{ProfileID: profileID},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, err
+	}
+
+	return toProfileExport(row), nil
+}
+
+func (r *Repository) ClaimProfileExports(
+	ctx context.Context,
+	staleBefore time.Time,
+	limit int,
+) ([]*profiles.ProfileExport, error) {
+	rows, err := r.queries.ClaimProfileExports(
+		ctx,
+		ClaimProfileExportsParams{
+			StaleBefore: sql.NullTime{Time: staleBefore, Valid: true},
+			LimitCount:  int32(limit), //nolint:gosec
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProfileExports(rows), nil
+}
+
+func (r *Repository) CompleteProfileExport(
+	ctx context.Context,
+	id string,
+	objectKey string,
+	sizeBytes int64,
+	expiresAt time.Time,
+) (int64, error) {
+	return r.queries.CompleteProfileExport( //nolint:wrapcheck
+		ctx,
+		CompleteProfileExportParams{
+			ObjectKey: sql.NullString{String: objectKey, Valid: true},
+			SizeBytes: sql.NullInt64{Int64: sizeBytes, Valid: true},
+			ExpiresAt: sql.NullTime{Time: expiresAt, Valid: true},
+			ID:        id,
+		},
+	)
+}
+
+func (r *Repository) RecordProfileExportFailure(
+	ctx context.Context,
+	id string,
+	status string,
+	lastError string,
+) (int64, error) {
+	return r.queries.RecordProfileExportFailure( //nolint:wrapcheck
+		ctx,
+		RecordProfileExportFailureParams{
+			Status:    status,
+			LastError: sql.NullString{String: lastError, Valid: true},
+			ID:        id,
+		},
+	)
+}
+
+func (r *Repository) ListExpiredProfileExports(
+	ctx context.Context,
+	limit int,
+) ([]*profiles.ProfileExport, error) {
+	rows, err := r.queries.ListExpiredProfileExports(
+		ctx,
+		ListExpiredProfileExportsParams{LimitCount: int32(limit)}, //nolint:gosec
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProfileExports(rows), nil
+}
+
+func (r *Repository) ExpireProfileExport(ctx context.Context, id string) (int64, error) {
+	return r.queries.ExpireProfileExport(ctx, ExpireProfileExportParams{ID: id}) //nolint:wrapcheck
+}
+
+func (r *Repository) GetProfileExportData( //nolint:funlen,cyclop
+	ctx context.Context,
+	profileID string,
+) (*profiles.ProfileExportData, error) {
+	profile, err := r.queries.GetProfileForExport(ctx, GetProfileForExportParams{ID: profileID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, err
+	}
+
+	profileTxs, err := r.queries.ListProfileTxsForExport(
+		ctx,
+		ListProfileTxsForExportParams{ProfileID: profileID},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := r.queries.ListProfilePagesForExport(
+		ctx,
+		ListProfilePagesForExportParams{ProfileID: profileID},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	pageTxs, err := r.queries.ListProfilePageTxsForExport(
+		ctx,
+		ListProfilePageTxsForExportParams{ProfileID: profileID},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := r.queries.ListProfileLinksForExport(
+		ctx,
+		ListProfileLinksForExportParams{ProfileID: profileID},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	authorProfileID := sql.NullString{String: profileID, Valid: true}
+
+	stories, err := r.queries.ListStoriesForExport(
+		ctx,
+		ListStoriesForExportParams{AuthorProfileID: authorProfileID},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	storyTxs, err := r.queries.ListStoryTxsForExport(
+		ctx,
+		ListStoryTxsForExportParams{AuthorProfileID: authorProfileID},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	memberships, err := r.queries.ListProfileMembershipsForExport(
+		ctx,
+		ListProfileMembershipsForExportParams{ProfileID: profileID},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &profiles.ProfileExportData{
+		ExportedAt: time.Now(),
+		Profile: &profiles.ExportedProfile{
+			CreatedAt:         profile.CreatedAt,
+			Properties:        vars.ToObject(profile.Properties),
+			CustomDomain:      vars.ToStringPtr(profile.CustomDomain),
+			ProfilePictureURI: vars.ToStringPtr(profile.ProfilePictureURI),
+			Pronouns:          vars.ToStringPtr(profile.Pronouns),
+			UpdatedAt:         vars.ToTimePtr(profile.UpdatedAt),
+			ID:                profile.ID,
+			Slug:              profile.Slug,
+			Kind:              profile.Kind,
+			Translations:      make([]*profiles.ExportedProfileTranslation, len(profileTxs)),
+		},
+		Pages:       make([]*profiles.ExportedPage, len(pages)),
+		Links:       make([]*profiles.ExportedLink, len(links)),
+		Stories:     make([]*profiles.ExportedStory, len(stories)),
+		Memberships: make([]*profiles.ExportedMembership, len(memberships)),
+	}
+
+	for i, row := range profileTxs {
+		data.Profile.Translations[i] = &profiles.ExportedProfileTranslation{
+			Properties:  vars.ToObject(row.Properties),
+			LocaleCode:  strings.TrimSpace(row.LocaleCode),
+			Title:       row.Title,
+			Description: row.Description,
+		}
+	}
+
+	pageTranslations := make(map[string][]*profiles.ExportedTranslation, len(pages))
+	for _, row := range pageTxs {
+		pageTranslations[row.ProfilePageID] = append(
+			pageTranslations[row.ProfilePageID],
+			&profiles.ExportedTranslation{
+				LocaleCode: strings.TrimSpace(row.LocaleCode),
+				Title:      row.Title,
+				Summary:    row.Summary,
+				Content:    row.Content,
+			},
+		)
+	}
+
+	for i, row := range pages {
+		data.Pages[i] = &profiles.ExportedPage{
+			CreatedAt:       row.CreatedAt,
+			CoverPictureURI: vars.ToStringPtr(row.CoverPictureURI),
+			PublishedAt:     vars.ToTimePtr(row.PublishedAt),
+			UpdatedAt:       vars.ToTimePtr(row.UpdatedAt),
+			ID:              row.ID,
+			Slug:            row.Slug,
+			Translations:    pageTranslations[row.ID],
+			Order:           int(row.Order),
+		}
+	}
+
+	for i, row := range links {
+		data.Links[i] = &profiles.ExportedLink{
+			CreatedAt:  row.CreatedAt,
+			PublicID:   vars.ToStringPtr(row.PublicID),
+			URI:        vars.ToStringPtr(row.URI),
+			ID:         row.ID,
+			Kind:       row.Kind,
+			Title:      row.Title,
+			Order:      int(row.Order),
+			IsVerified: row.IsVerified,
+			IsHidden:   row.IsHidden,
+		}
+	}
+
+	storyTranslations := make(map[string][]*profiles.ExportedTranslation, len(stories))
+	for _, row := range storyTxs {
+		storyTranslations[row.StoryID] = append(
+			storyTranslations[row.StoryID],
+			&profiles.ExportedTranslation{
+				LocaleCode: strings.TrimSpace(row.LocaleCode),
+				Title:      row.Title,
+				Summary:    row.Summary,
+				Content:    row.Content,
+			},
+		)
+	}
+
+	for i, row := range stories {
+		data.Stories[i] = &profiles.ExportedStory{
+			CreatedAt:       row.CreatedAt,
+			Properties:      vars.ToObject(row.Properties),
+			StoryPictureURI: vars.ToStringPtr(row.StoryPictureURI),
+			UpdatedAt:       vars.ToTimePtr(row.UpdatedAt),
+			ID:              row.ID,
+			Slug:            row.Slug,
+			Kind:            row.Kind,
+			Status:          row.Status,
+			Translations:    storyTranslations[row.ID],
+			IsFeatured:      row.IsFeatured,
+		}
+	}
+
+	for i, row := range memberships {
+		data.Memberships[i] = &profiles.ExportedMembership{
+			CreatedAt:         row.CreatedAt,
+			StartedAt:         vars.ToTimePtr(row.StartedAt),
+			FinishedAt:        vars.ToTimePtr(row.FinishedAt),
+			ID:                row.ID,
+			ProfileSlug:       row.ProfileSlug,
+			MemberProfileSlug: row.MemberProfileSlug,
+			Kind:              row.Kind,
+		}
+	}
+
+	return data, nil
+}
+
+func toProfileExports(rows []*ProfileExport) []*profiles.ProfileExport {
+	result := make([]*profiles.ProfileExport, len(rows))
+	for i, row := range rows {
+		result[i] = toProfileExport(row)
+	}
+
+	return result
+}
+
+func toProfileExport(row *ProfileExport) *profiles.ProfileExport {
+	var sizeBytes *int64
+	if row.SizeBytes.Valid {
+		sizeBytes = &row.SizeBytes.Int64
+	}
+
+	return &profiles.ProfileExport{
+		CreatedAt:   row.CreatedAt,
+		StartedAt:   vars.ToTimePtr(row.StartedAt),
+		CompletedAt: vars.ToTimePtr(row.CompletedAt),
+		ExpiresAt:   vars.ToTimePtr(row.ExpiresAt),
+		LastError:   vars.ToStringPtr(row.LastError),
+		SizeBytes:   sizeBytes,
+		ObjectKey:   vars.ToStringPtr(row.ObjectKey),
+		ID:          row.ID,
+		ProfileID:   row.ProfileID,
+		ProfileSlug: "",
+		Status:      row.Status,
+		DownloadURL: "",
+		Attempts:    int(row.Attempts),
+	}
+}
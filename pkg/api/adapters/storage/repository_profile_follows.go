@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/eser/aya.is-services/pkg/lib/cursors"
+	"github.com/eser/aya.is-services/pkg/lib/vars"
+)
+
+func (r *Repository) GetUserIndividualProfileID(ctx context.Context, userID string) (string, error) {
+	profileID, err := r.queries.GetUserIndividualProfileID(
+		ctx,
+		GetUserIndividualProfileIDParams{UserID: userID},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return profileID.String, nil
+}
+
+func (r *Repository) FollowProfile(
+	ctx context.Context,
+	id string,
+	followerProfileID string,
+	followedProfileID string,
+) (int64, error) {
+	return r.queries.FollowProfile( //nolint:wrapcheck
+		ctx,
+		FollowProfileParams{
+			ID:                id,
+			FollowerProfileID: followerProfileID,
+			FollowedProfileID: followedProfileID,
+		},
+	)
+}
+
+func (r *Repository) UnfollowProfile(
+	ctx context.Context,
+	followerProfileID string,
+	followedProfileID string,
+) (int64, error) {
+	return r.queries.UnfollowProfile( //nolint:wrapcheck
+		ctx,
+		UnfollowProfileParams{
+			FollowerProfileID: followerProfileID,
+			FollowedProfileID: followedProfileID,
+		},
+	)
+}
+
+func (r *Repository) ListProfileFollowers(
+	ctx context.Context,
+	localeCode string,
+	profileID string,
+	cursor *cursors.Cursor,
+) (cursors.Cursored[[]*profiles.ProfileFollow], error) {
+	var wrappedResponse cursors.Cursored[[]*profiles.ProfileFollow]
+
+	rows, err := r.queries.ListProfileFollowers(ctx, ListProfileFollowersParams{
+		LocaleCode: localeCode,
+		ProfileID:  profileID,
+		CursorID:   cursorOffset(cursor),
+		LimitCount: int32(cursor.Limit), //nolint:gosec
+	})
+	if err != nil {
+		return wrappedResponse, err
+	}
+
+	result := make([]*profiles.ProfileFollow, len(rows))
+	for i, row := range rows {
+		result[i] = toProfileFollow(&row.ProfileFollow, &row.Profile, &row.ProfileTx)
+	}
+
+	return wrapProfileFollows(result, cursor), nil
+}
+
+func (r *Repository) ListProfileFollowing(
+	ctx context.Context,
+	localeCode string,
+	profileID string,
+	cursor *cursors.Cursor,
+) (cursors.Cursored[[]*profiles.ProfileFollow], error) {
+	var wrappedResponse cursors.Cursored[[]*profiles.ProfileFollow]
+
+	rows, err := r.queries.ListProfileFollowing(ctx, ListProfileFollowingParams{
+		LocaleCode: localeCode,
+		ProfileID:  profileID,
+		CursorID:   cursorOffset(cursor),
+		LimitCount: int32(cursor.Limit), //nolint:gosec
+	})
+	if err != nil {
+		return wrappedResponse, err
+	}
+
+	result := make([]*profiles.ProfileFollow, len(rows))
+	for i, row := range rows {
+		result[i] = toProfileFollow(&row.ProfileFollow, &row.Profile, &row.ProfileTx)
+	}
+
+	return wrapProfileFollows(result, cursor), nil
+}
+
+// cursorOffset is the ID of the last record of the previous page, which the listings
+// that page by ID continue after.
+func cursorOffset(cursor *cursors.Cursor) sql.NullString {
+	if cursor.Offset == nil || *cursor.Offset == "" {
+		return sql.NullString{String: "", Valid: false}
+	}
+
+	return sql.NullString{String: *cursor.Offset, Valid: true}
+}
+
+func wrapProfileFollows(
+	result []*profiles.ProfileFollow,
+	cursor *cursors.Cursor,
+) cursors.Cursored[[]*profiles.ProfileFollow] {
+	var cursorPtr *string
+
+	if len(result) == cursor.Limit {
+		cursorPtr = &result[len(result)-1].ID
+	}
+
+	return cursors.WrapResponseWithCursor(result, cursorPtr)
+}
+
+func toProfileFollow(
+	follow *ProfileFollow,
+	profile *Profile,
+	profileTx *ProfileTx,
+) *profiles.ProfileFollow {
+	return &profiles.ProfileFollow{
+		CreatedAt: follow.CreatedAt,
+		Profile: &profiles.Profile{
+			ID:                profile.ID,
+			Slug:              profile.Slug,
+			Kind:              profile.Kind,
+			CustomDomain:      vars.ToStringPtr(profile.CustomDomain),
+			ProfilePictureURI: vars.ToStringPtr(profile.ProfilePictureURI),
+			Pronouns:          vars.ToStringPtr(profile.Pronouns),
+			Title:             profileTx.Title,
+			Description:       profileTx.Description,
+			Properties:        vars.ToObject(profile.Properties),
+			CreatedAt:         profile.CreatedAt,
+			UpdatedAt:         vars.ToTimePtr(profile.UpdatedAt),
+			DeletedAt:         vars.ToTimePtr(profile.DeletedAt),
+		},
+		ID: follow.ID,
+	}
+}
@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/eser/aya.is-services/pkg/lib/vars"
+)
+
+func (r *Repository) GetProfileMembershipKind(
+	ctx context.Context,
+	profileID string,
+	memberProfileID string,
+) (string, error) {
+	kind, err := r.queries.GetProfileMembershipKind(
+		ctx,
+		GetProfileMembershipKindParams{ProfileID: profileID, MemberProfileID: memberProfileID},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return kind, nil
+}
+
+func (r *Repository) CountProfileMembershipsOfKind(
+	ctx context.Context,
+	profileID string,
+	kind string,
+) (int64, error) {
+	return r.queries.CountProfileMembershipsOfKind( //nolint:wrapcheck
+		ctx,
+		CountProfileMembershipsOfKindParams{ProfileID: profileID, Kind: kind},
+	)
+}
+
+func (r *Repository) UpdateProfileMembershipKind(
+	ctx context.Context,
+	profileID string,
+	memberProfileID string,
+	kind string,
+) (int64, error) {
+	return r.queries.UpdateProfileMembershipKind( //nolint:wrapcheck
+		ctx,
+		UpdateProfileMembershipKindParams{
+			Kind:            kind,
+			ProfileID:       profileID,
+			MemberProfileID: memberProfileID,
+		},
+	)
+}
+
+func (r *Repository) RemoveProfileMembership(
+	ctx context.Context,
+	profileID string,
+	memberProfileID string,
+) (int64, error) {
+	return r.queries.RemoveProfileMembership( //nolint:wrapcheck
+		ctx,
+		RemoveProfileMembershipParams{ProfileID: profileID, MemberProfileID: memberProfileID},
+	)
+}
+
+func (r *Repository) CreateProfileMembershipInvitation(
+	ctx context.Context,
+	invitation *profiles.ProfileMembershipInvitation,
+	tokenHash string,
+) error {
+	return r.queries.CreateProfileMembershipInvitation( //nolint:wrapcheck
+		ctx,
+		CreateProfileMembershipInvitationParams{
+			ID:              invitation.ID,
+			ProfileID:       invitation.ProfileID,
+			MemberProfileID: invitation.MemberProfileID,
+			Kind:            invitation.Kind,
+			TokenHash:       tokenHash,
+			InvitedByUserID: invitation.InvitedByUserID,
+			ExpiresAt:       invitation.ExpiresAt,
+			CreatedAt:       invitation.CreatedAt,
+		},
+	)
+}
+
+func (r *Repository) GetProfileMembershipInvitationByTokenHash(
+	ctx context.Context,
+	tokenHash string,
+) (*profiles.ProfileMembershipInvitation, error) {
+	row, err := r.queries.GetProfileMembershipInvitationByTokenHash(
+		ctx,
+		GetProfileMembershipInvitationByTokenHashParams{TokenHash: tokenHash},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, err
+	}
+
+	result := &profiles.ProfileMembershipInvitation{
+		CreatedAt:         row.ProfileMembershipInvitation.CreatedAt,
+		ExpiresAt:         row.ProfileMembershipInvitation.ExpiresAt,
+		AcceptedAt:        vars.ToTimePtr(row.ProfileMembershipInvitation.AcceptedAt),
+		ID:                row.ProfileMembershipInvitation.ID,
+		ProfileID:         row.ProfileMembershipInvitation.ProfileID,
+		ProfileSlug:       row.ProfileSlug,
+		MemberProfileID:   row.ProfileMembershipInvitation.MemberProfileID,
+		MemberProfileSlug: row.MemberProfileSlug,
+		Kind:              row.ProfileMembershipInvitation.Kind,
+		InvitedByUserID:   row.ProfileMembershipInvitation.InvitedByUserID,
+	}
+
+	return result, nil
+}
+
+func (r *Repository) AcceptProfileMembershipInvitation(
+	ctx context.Context,
+	invitation *profiles.ProfileMembershipInvitation,
+	membershipID string,
+) (bool, error) {
+	var accepted bool
+
+	err := r.withTx(ctx, func(queries *Queries) error {
+		updated, err := queries.AcceptProfileMembershipInvitation(
+			ctx,
+			AcceptProfileMembershipInvitationParams{ID: invitation.ID},
+		)
+		if err != nil || updated == 0 {
+			return err
+		}
+
+		accepted = true
+
+		return queries.UpsertProfileMembership(ctx, UpsertProfileMembershipParams{
+			ID:              membershipID,
+			ProfileID:       invitation.ProfileID,
+			MemberProfileID: invitation.MemberProfileID,
+			Kind:            invitation.Kind,
+		})
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return accepted, nil
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+)
+
+func (r *Repository) RecordProfileView(
+	ctx context.Context,
+	id string,
+	slug string,
+	visitorHash string,
+	viewedOn time.Time,
+) (int64, error) {
+	return r.queries.RecordProfileView( //nolint:wrapcheck
+		ctx,
+		RecordProfileViewParams{
+			ID:          id,
+			VisitorHash: visitorHash,
+			ViewedOn:    viewedOn,
+			Slug:        slug,
+		},
+	)
+}
+
+func (r *Repository) RecordStoryView(
+	ctx context.Context,
+	id string,
+	slug string,
+	visitorHash string,
+	viewedOn time.Time,
+) (int64, error) {
+	return r.queries.RecordStoryView( //nolint:wrapcheck
+		ctx,
+		RecordStoryViewParams{
+			ID:          id,
+			VisitorHash: visitorHash,
+			ViewedOn:    viewedOn,
+			Slug:        slug,
+		},
+	)
+}
+
+func (r *Repository) GetProfileViewSummary(
+	ctx context.Context,
+	profileID string,
+	from time.Time,
+	to time.Time,
+) (*profiles.ViewSummary, error) {
+	row, err := r.queries.GetProfileViewSummary(
+		ctx,
+		GetProfileViewSummaryParams{ProfileID: profileID, FromDate: from, ToDate: to},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &profiles.ViewSummary{
+		From:          from.Format(time.DateOnly),
+		To:            to.Format(time.DateOnly),
+		ProfileViews:  row.ProfileViews,
+		StoryViews:    row.StoryViews,
+		ViewedStories: row.ViewedStories,
+	}, nil
+}
+
+func (r *Repository) ListProfileViewsByDay(
+	ctx context.Context,
+	profileID string,
+	from time.Time,
+	to time.Time,
+) ([]*profiles.DailyViews, error) {
+	rows, err := r.queries.ListProfileViewsByDay(
+		ctx,
+		ListProfileViewsByDayParams{ProfileID: profileID, FromDate: from, ToDate: to},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*profiles.DailyViews, len(rows))
+	for i, row := range rows {
+		result[i] = &profiles.DailyViews{
+			Date:         row.ViewedOn.Format(time.DateOnly),
+			ProfileViews: row.ProfileViews,
+			StoryViews:   row.StoryViews,
+		}
+	}
+
+	return result, nil
+}
@@ -9,8 +9,12 @@ import (
 	"github.com/eser/aya.is-services/pkg/api/business/profiles"
 	"github.com/eser/aya.is-services/pkg/lib/cursors"
 	"github.com/eser/aya.is-services/pkg/lib/vars"
+	"github.com/lib/pq"
 )
 
+// pqUniqueViolation is the PostgreSQL error code of unique constraint violations.
+const pqUniqueViolation = "23505"
+
 func (r *Repository) GetProfileIDBySlug(ctx context.Context, slug string) (string, error) {
 	var result string
 
@@ -376,3 +380,217 @@ func (r *Repository) ListProfileMembers(
 
 	return wrappedResponse, nil
 }
+
+func (r *Repository) GetProfileKindByID(ctx context.Context, id string) (string, error) {
+	kind, err := r.queries.GetProfileKindByID(ctx, GetProfileKindByIDParams{ID: id})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return kind, nil
+}
+
+func (r *Repository) IsProfileOwnedByUser(
+	ctx context.Context,
+	profileID string,
+	userID string,
+) (bool, error) {
+	return r.queries.IsProfileOwnedByUser( //nolint:wrapcheck
+		ctx,
+		IsProfileOwnedByUserParams{ProfileID: profileID, UserID: userID},
+	)
+}
+
+func (r *Repository) CreateProfile(
+	ctx context.Context,
+	localeCode string,
+	profile *profiles.Profile,
+	ownerUserID string,
+	membershipID string,
+) error {
+	err := r.withTx(ctx, func(queries *Queries) error {
+		err := queries.CreateProfile(ctx, CreateProfileParams{
+			ID:                profile.ID,
+			Slug:              profile.Slug,
+			Kind:              profile.Kind,
+			ProfilePictureURI: vars.ToSQLNullString(profile.ProfilePictureURI),
+			Pronouns:          vars.ToSQLNullString(profile.Pronouns),
+		})
+		if err != nil {
+			if isUniqueViolation(err) {
+				return profiles.ErrSlugAlreadyTaken
+			}
+
+			return err
+		}
+
+		err = queries.UpsertProfileTx(ctx, UpsertProfileTxParams{
+			ProfileID:   profile.ID,
+			LocaleCode:  localeCode,
+			Title:       profile.Title,
+			Description: profile.Description,
+		})
+		if err != nil {
+			return err
+		}
+
+		if profile.Kind == profiles.ProfileKindIndividual {
+			updated, err := queries.SetUserIndividualProfileID(ctx, SetUserIndividualProfileIDParams{
+				IndividualProfileID: sql.NullString{String: profile.ID, Valid: true},
+				UserID:              ownerUserID,
+			})
+			if err != nil {
+				return err
+			}
+
+			if updated == 0 {
+				return profiles.ErrIndividualProfileExists
+			}
+
+			return nil
+		}
+
+		created, err := queries.CreateProfileMembershipForUser(
+			ctx,
+			CreateProfileMembershipForUserParams{
+				ID:        membershipID,
+				ProfileID: profile.ID,
+				Kind:      profiles.MembershipKindOwner,
+				UserID:    ownerUserID,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		if created == 0 {
+			return profiles.ErrIndividualProfileRequired
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// a lookup of the slug before it was taken may be cached as missing.
+	r.forgetProfileIDBySlug(ctx, profile.Slug)
+
+	return nil
+}
+
+func (r *Repository) UpdateProfile(
+	ctx context.Context,
+	localeCode string,
+	id string,
+	slug string,
+	input *profiles.UpdateProfileInput,
+) (int64, error) {
+	var updated int64
+
+	err := r.withTx(ctx, func(queries *Queries) error {
+		var err error
+
+		updated, err = queries.UpdateProfile(ctx, UpdateProfileParams{
+			Slug:              vars.ToSQLNullString(input.Slug),
+			ProfilePictureURI: vars.ToSQLNullString(input.ProfilePictureURI),
+			Pronouns:          vars.ToSQLNullString(input.Pronouns),
+			ID:                id,
+		})
+		if err != nil {
+			if isUniqueViolation(err) {
+				return profiles.ErrSlugAlreadyTaken
+			}
+
+			return err
+		}
+
+		if updated == 0 {
+			return nil
+		}
+
+		if input.Slug != nil && *input.Slug != slug {
+			err = queries.UpsertSlugRedirect(ctx, UpsertSlugRedirectParams{
+				Kind:     slugRedirectKindProfile,
+				OldSlug:  slug,
+				TargetID: id,
+			})
+			if err != nil {
+				return err //nolint:wrapcheck
+			}
+		}
+
+		if input.Title == nil {
+			return nil
+		}
+
+		description := ""
+		if input.Description != nil {
+			description = *input.Description
+		}
+
+		return queries.UpsertProfileTx(ctx, UpsertProfileTxParams{
+			ProfileID:   id,
+			LocaleCode:  localeCode,
+			Title:       *input.Title,
+			Description: description,
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	r.forgetProfileIDBySlug(ctx, slug)
+
+	if input.Slug != nil {
+		r.forgetProfileIDBySlug(ctx, *input.Slug)
+	}
+
+	return updated, nil
+}
+
+func (r *Repository) DeleteProfile(ctx context.Context, id string, slug string) (int64, error) {
+	var deleted int64
+
+	err := r.withTx(ctx, func(queries *Queries) error {
+		var err error
+
+		deleted, err = queries.RemoveProfile(ctx, RemoveProfileParams{ID: id})
+		if err != nil || deleted == 0 {
+			return err
+		}
+
+		// a user whose individual profile is deleted may create another one.
+		_, err = queries.ClearUserIndividualProfileID(ctx, ClearUserIndividualProfileIDParams{
+			IndividualProfileID: sql.NullString{String: id, Valid: true},
+		})
+
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	r.forgetProfileIDBySlug(ctx, slug)
+
+	return deleted, nil
+}
+
+// forgetProfileIDBySlug drops the cached ID of a slug. The write is stored already,
+// so a failure only delays it until the cached ID expires.
+func (r *Repository) forgetProfileIDBySlug(ctx context.Context, slug string) {
+	err := r.CacheRemove(ctx, "profile_id_by_slug:"+slug)
+	if err != nil {
+		r.logger.WarnContext(ctx, "failed to remove cached profile id", "slug", slug, "error", err)
+	}
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+
+	return errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation
+}
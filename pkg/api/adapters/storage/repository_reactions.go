@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/eser/aya.is-services/pkg/api/business/reactions"
+)
+
+func (r *Repository) AddReaction(ctx context.Context, reaction *reactions.Reaction) (bool, error) {
+	var added bool
+
+	err := r.withTx(ctx, func(queries *Queries) error {
+		affected, err := queries.AddReaction(ctx, AddReactionParams{
+			ID:         reaction.ID,
+			TargetKind: reaction.TargetKind,
+			TargetID:   reaction.TargetID,
+			UserID:     reaction.UserID,
+			Kind:       reaction.Kind,
+		})
+		if err != nil || affected == 0 {
+			return err
+		}
+
+		added = true
+
+		return queries.IncrementReactionCount(ctx, IncrementReactionCountParams{
+			TargetKind: reaction.TargetKind,
+			TargetID:   reaction.TargetID,
+			Kind:       reaction.Kind,
+		})
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return added, nil
+}
+
+func (r *Repository) RemoveReaction(
+	ctx context.Context,
+	targetKind string,
+	targetID string,
+	userID string,
+	kind string,
+) (bool, error) {
+	var removed bool
+
+	err := r.withTx(ctx, func(queries *Queries) error {
+		affected, err := queries.RemoveReaction(ctx, RemoveReactionParams{
+			TargetKind: targetKind,
+			TargetID:   targetID,
+			UserID:     userID,
+			Kind:       kind,
+		})
+		if err != nil || affected == 0 {
+			return err
+		}
+
+		removed = true
+
+		return queries.DecrementReactionCount(ctx, DecrementReactionCountParams{
+			TargetKind: targetKind,
+			TargetID:   targetID,
+			Kind:       kind,
+		})
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return removed, nil
+}
+
+func (r *Repository) ListReactionCounts(
+	ctx context.Context,
+	targetKind string,
+	targetIDs []string,
+) (map[string][]*reactions.Count, error) {
+	rows, err := r.queries.ListReactionCounts(ctx, ListReactionCountsParams{
+		TargetKind: targetKind,
+		TargetIds:  targetIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]*reactions.Count)
+	for _, row := range rows {
+		result[row.TargetID] = append(result[row.TargetID], &reactions.Count{
+			Kind:  row.Kind,
+			Count: row.Count,
+		})
+	}
+
+	return result, nil
+}
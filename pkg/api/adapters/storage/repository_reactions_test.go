@@ -0,0 +1,218 @@
+package storage_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/connfx"
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/api/adapters/storage"
+	"github.com/eser/aya.is-services/pkg/api/business/reactions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"modernc.org/sqlite"
+)
+
+// reactionSchema is the reaction migration in the SQLite dialect.
+const reactionSchema = `
+CREATE TABLE "reaction" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "target_kind" TEXT NOT NULL,
+  "target_id" CHAR(26) NOT NULL,
+  "user_id" CHAR(26) NOT NULL,
+  "kind" TEXT NOT NULL,
+  "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX "reaction_target_kind_target_id_user_id_kind_unique"
+ON "reaction" ("target_kind", "target_id", "user_id", "kind");
+
+CREATE TABLE "reaction_count" (
+  "target_kind" TEXT NOT NULL,
+  "target_id" CHAR(26) NOT NULL,
+  "kind" TEXT NOT NULL,
+  "count" BIGINT NOT NULL,
+  PRIMARY KEY ("target_kind", "target_id", "kind")
+);
+`
+
+var registerGreatest sync.Once //nolint:gochecknoglobals
+
+// newSQLiteRepository opens a repository on a new SQLite database with the reaction
+// tables. SQLite has no GREATEST, so it is registered as a function.
+func newSQLiteRepository(t *testing.T) (*storage.Repository, *sql.DB) {
+	t.Helper()
+
+	registerGreatest.Do(func() {
+		sqlite.MustRegisterDeterministicScalarFunction(
+			"greatest",
+			2, //nolint:mnd
+			func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+				return max(args[0].(int64), args[1].(int64)), nil //nolint:forcetypeassert
+			},
+		)
+	})
+
+	logger := logfx.NewLogger(logfx.WithWriter(io.Discard))
+
+	registry := connfx.NewRegistry(connfx.WithLogger(logger))
+	registry.RegisterFactory(connfx.NewSQLConnectionFactory("sqlite"))
+
+	_, err := registry.AddConnection(t.Context(), "test", &connfx.ConfigTarget{ //nolint:exhaustruct
+		Protocol: "sqlite",
+		DSN:      filepath.Join(t.TempDir(), "reactions.db"),
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = registry.Close(t.Context()) })
+
+	db, err := connfx.GetTypedConnection[*sql.DB](registry, "test")
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(t.Context(), reactionSchema)
+	require.NoError(t, err)
+
+	repo, err := storage.NewRepositoryFromNamed(logger, registry, "test")
+	require.NoError(t, err)
+
+	return repo, db
+}
+
+func newReaction(id string, userID string) *reactions.Reaction {
+	return &reactions.Reaction{ //nolint:exhaustruct
+		ID:         id,
+		TargetKind: reactions.TargetKindStory,
+		TargetID:   "story-1",
+		UserID:     userID,
+		Kind:       reactions.KindLike,
+	}
+}
+
+// countReactions returns the reactions stored and the count of them.
+func countReactions(t *testing.T, db *sql.DB) (int64, int64) {
+	t.Helper()
+
+	var stored, counted int64
+
+	err := db.QueryRowContext(t.Context(), `SELECT COUNT(*) FROM "reaction"`).Scan(&stored)
+	require.NoError(t, err)
+
+	err = db.QueryRowContext(
+		t.Context(),
+		`SELECT COALESCE(SUM("count"), 0) FROM "reaction_count"`,
+	).Scan(&counted)
+	require.NoError(t, err)
+
+	return stored, counted
+}
+
+func TestRepositoryAddReaction(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should count a reaction once", func(t *testing.T) {
+		t.Parallel()
+
+		repo, db := newSQLiteRepository(t)
+
+		added, err := repo.AddReaction(t.Context(), newReaction("reaction-1", "user-1"))
+		require.NoError(t, err)
+		assert.True(t, added)
+
+		added, err = repo.AddReaction(t.Context(), newReaction("reaction-2", "user-1"))
+		require.NoError(t, err)
+		assert.False(t, added)
+
+		added, err = repo.AddReaction(t.Context(), newReaction("reaction-3", "user-2"))
+		require.NoError(t, err)
+		assert.True(t, added)
+
+		stored, counted := countReactions(t, db)
+		assert.Equal(t, int64(2), stored)
+		assert.Equal(t, int64(2), counted)
+	})
+
+	t.Run("should not store a reaction it fails to count", func(t *testing.T) {
+		t.Parallel()
+
+		repo, db := newSQLiteRepository(t)
+
+		_, err := db.ExecContext(t.Context(), `DROP TABLE "reaction_count"`)
+		require.NoError(t, err)
+
+		_, err = repo.AddReaction(t.Context(), newReaction("reaction-1", "user-1"))
+		require.Error(t, err)
+
+		var stored int64
+
+		err = db.QueryRowContext(t.Context(), `SELECT COUNT(*) FROM "reaction"`).Scan(&stored)
+		require.NoError(t, err)
+		assert.Zero(t, stored)
+	})
+}
+
+func TestRepositoryRemoveReaction(t *testing.T) {
+	t.Parallel()
+
+	remove := func(t *testing.T, repo *storage.Repository, userID string) (bool, error) {
+		t.Helper()
+
+		return repo.RemoveReaction(
+			t.Context(),
+			reactions.TargetKindStory,
+			"story-1",
+			userID,
+			reactions.KindLike,
+		)
+	}
+
+	t.Run("should uncount a removed reaction", func(t *testing.T) {
+		t.Parallel()
+
+		repo, db := newSQLiteRepository(t)
+
+		for _, reaction := range []*reactions.Reaction{
+			newReaction("reaction-1", "user-1"),
+			newReaction("reaction-2", "user-2"),
+		} {
+			_, err := repo.AddReaction(t.Context(), reaction)
+			require.NoError(t, err)
+		}
+
+		removed, err := remove(t, repo, "user-1")
+		require.NoError(t, err)
+		assert.True(t, removed)
+
+		removed, err = remove(t, repo, "user-1")
+		require.NoError(t, err)
+		assert.False(t, removed)
+
+		stored, counted := countReactions(t, db)
+		assert.Equal(t, int64(1), stored)
+		assert.Equal(t, int64(1), counted)
+	})
+
+	t.Run("should keep a reaction it fails to uncount", func(t *testing.T) {
+		t.Parallel()
+
+		repo, db := newSQLiteRepository(t)
+
+		_, err := repo.AddReaction(t.Context(), newReaction("reaction-1", "user-1"))
+		require.NoError(t, err)
+
+		_, err = db.ExecContext(t.Context(), `DROP TABLE "reaction_count"`)
+		require.NoError(t, err)
+
+		_, err = remove(t, repo, "user-1")
+		require.Error(t, err)
+
+		var stored int64
+
+		err = db.QueryRowContext(t.Context(), `SELECT COUNT(*) FROM "reaction"`).Scan(&stored)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), stored)
+	})
+}
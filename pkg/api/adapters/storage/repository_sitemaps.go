@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/eser/aya.is-services/pkg/api/business/sitemaps"
+)
+
+// sitemapCacheKey is the cache key of the generated sitemap, which is kept until the
+// next one is generated.
+const sitemapCacheKey = "sitemap"
+
+func (r *Repository) ListSitemapProfiles(ctx context.Context) ([]*sitemaps.Entry, error) {
+	rows, err := r.queries.ListSitemapProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*sitemaps.Entry, len(rows))
+	for i, row := range rows {
+		result[i] = &sitemaps.Entry{
+			LastModifiedAt: row.LastModifiedAt,
+			ProfileSlug:    "",
+			Slug:           row.Slug,
+			LocaleCode:     strings.TrimSpace(row.LocaleCode),
+		}
+	}
+
+	return result, nil
+}
+
+func (r *Repository) ListSitemapProfilePages(ctx context.Context) ([]*sitemaps.Entry, error) {
+	rows, err := r.queries.ListSitemapProfilePages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*sitemaps.Entry, len(rows))
+	for i, row := range rows {
+		result[i] = &sitemaps.Entry{
+			LastModifiedAt: row.LastModifiedAt,
+			ProfileSlug:    row.ProfileSlug,
+			Slug:           row.Slug,
+			LocaleCode:     strings.TrimSpace(row.LocaleCode),
+		}
+	}
+
+	return result, nil
+}
+
+func (r *Repository) ListSitemapStories(ctx context.Context) ([]*sitemaps.Entry, error) {
+	rows, err := r.queries.ListSitemapStories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*sitemaps.Entry, len(rows))
+	for i, row := range rows {
+		result[i] = &sitemaps.Entry{
+			LastModifiedAt: row.LastModifiedAt,
+			ProfileSlug:    "",
+			Slug:           row.Slug,
+			LocaleCode:     strings.TrimSpace(row.LocaleCode),
+		}
+	}
+
+	return result, nil
+}
+
+func (r *Repository) GetCachedSitemap(ctx context.Context) (*sitemaps.Sitemap, error) {
+	message, err := r.CacheGet(ctx, sitemapCacheKey)
+	if err != nil || message == nil {
+		return nil, err
+	}
+
+	var result sitemaps.Sitemap
+
+	err = json.Unmarshal(*message, &result)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return &result, nil
+}
+
+func (r *Repository) SetCachedSitemap(ctx context.Context, sitemap *sitemaps.Sitemap) error {
+	message, err := json.Marshal(sitemap)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return r.CacheSet(ctx, sitemapCacheKey, message)
+}
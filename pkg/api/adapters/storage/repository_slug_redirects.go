@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+const (
+	// slugRedirectKindProfile is the kind of the slug redirects that lead to profiles.
+	slugRedirectKindProfile = "profile"
+	// slugRedirectKindStory is the kind of the slug redirects that lead to stories.
+	slugRedirectKindStory = "story"
+)
+
+func (r *Repository) GetProfileSlugRedirect(ctx context.Context, oldSlug string) (string, error) {
+	slug, err := r.queries.GetProfileSlugRedirect(
+		ctx,
+		GetProfileSlugRedirectParams{OldSlug: oldSlug},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return slug, nil
+}
+
+func (r *Repository) GetStorySlugRedirect(ctx context.Context, oldSlug string) (string, error) {
+	slug, err := r.queries.GetStorySlugRedirect(ctx, GetStorySlugRedirectParams{OldSlug: oldSlug})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return slug, nil
+}
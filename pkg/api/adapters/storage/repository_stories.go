@@ -140,6 +140,298 @@ func (r *Repository) ListStoriesOfPublication( //nolint:funlen
 	return wrappedResponse, nil
 }
 
+func (r *Repository) ListStoriesOfFollowedProfiles(
+	ctx context.Context,
+	localeCode string,
+	followerProfileID string,
+	cursor *cursors.Cursor,
+) (cursors.Cursored[[]*stories.StoryWithChildren], error) {
+	var wrappedResponse cursors.Cursored[[]*stories.StoryWithChildren]
+
+	rows, err := r.queries.ListStoriesOfFollowedProfiles(
+		ctx,
+		ListStoriesOfFollowedProfilesParams{
+			LocaleCode:        localeCode,
+			FollowerProfileID: followerProfileID,
+			CursorID:          cursorOffset(cursor),
+			LimitCount:        int32(cursor.Limit), //nolint:gosec
+		},
+	)
+	if err != nil {
+		return wrappedResponse, err
+	}
+
+	result := make([]*stories.StoryWithChildren, len(rows))
+	for i, row := range rows {
+		storyWithChildren, err := r.parseStoryWithChildren(
+			row.Profile,
+			row.ProfileTx,
+			row.Story,
+			row.StoryTx,
+			row.Publications,
+		)
+		if err != nil {
+			return wrappedResponse, err
+		}
+
+		result[i] = storyWithChildren
+	}
+
+	wrappedResponse.Data = result
+
+	if len(result) == cursor.Limit {
+		wrappedResponse.CursorPtr = &result[len(result)-1].ID
+	}
+
+	return wrappedResponse, nil
+}
+
+func (r *Repository) GetAuthoredStoryBySlug(
+	ctx context.Context,
+	slug string,
+) (*stories.AuthoredStory, error) {
+	row, err := r.queries.GetAuthoredStoryBySlug(ctx, GetAuthoredStoryBySlugParams{Slug: slug})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, err
+	}
+
+	return &stories.AuthoredStory{
+		ID:                row.ID,
+		Status:            row.Status,
+		AuthorProfileID:   row.AuthorProfileID,
+		AuthorProfileSlug: row.AuthorProfileSlug,
+	}, nil
+}
+
+func (r *Repository) CreateStory(
+	ctx context.Context,
+	localeCode string,
+	story *stories.Story,
+	revision *stories.StoryRevision,
+) error {
+	err := r.withTx(ctx, func(queries *Queries) error {
+		err := queries.CreateStory(ctx, CreateStoryParams{
+			ID:              story.ID,
+			AuthorProfileID: vars.ToSQLNullString(story.AuthorProfileID),
+			Slug:            story.Slug,
+			Kind:            story.Kind,
+			Status:          story.Status,
+			StoryPictureURI: vars.ToSQLNullString(story.StoryPictureURI),
+			Title:           story.Title,
+			Summary:         story.Summary,
+			Content:         story.Content,
+		})
+		if err != nil {
+			if isUniqueViolation(err) {
+				return stories.ErrStorySlugAlreadyTaken
+			}
+
+			return err
+		}
+
+		err = queries.UpsertStoryTx(ctx, UpsertStoryTxParams{
+			StoryID:    story.ID,
+			LocaleCode: localeCode,
+			Title:      story.Title,
+			Summary:    story.Summary,
+			Content:    story.Content,
+		})
+		if err != nil {
+			return err
+		}
+
+		return insertStoryRevision(ctx, queries, revision)
+	})
+	if err != nil {
+		return err
+	}
+
+	// a lookup of the slug before it was taken may be cached as missing.
+	r.forgetStoryIDBySlug(ctx, story.Slug)
+
+	return nil
+}
+
+func (r *Repository) UpdateStory(
+	ctx context.Context,
+	localeCode string,
+	id string,
+	slug string,
+	input *stories.UpdateStoryInput,
+	revision *stories.StoryRevision,
+) (int64, error) {
+	var updated int64
+
+	err := r.withTx(ctx, func(queries *Queries) error {
+		var err error
+
+		updated, err = queries.UpdateStory(ctx, UpdateStoryParams{
+			Slug:            vars.ToSQLNullString(input.Slug),
+			StoryPictureURI: vars.ToSQLNullString(input.StoryPictureURI),
+			ID:              id,
+		})
+		if err != nil {
+			if isUniqueViolation(err) {
+				return stories.ErrStorySlugAlreadyTaken
+			}
+
+			return err
+		}
+
+		if updated == 0 {
+			return nil
+		}
+
+		if input.Slug != nil && *input.Slug != slug {
+			err = queries.UpsertSlugRedirect(ctx, UpsertSlugRedirectParams{
+				Kind:     slugRedirectKindStory,
+				OldSlug:  slug,
+				TargetID: id,
+			})
+			if err != nil {
+				return err //nolint:wrapcheck
+			}
+		}
+
+		if input.Title == nil {
+			return nil
+		}
+
+		summary := ""
+		if input.Summary != nil {
+			summary = *input.Summary
+		}
+
+		content := ""
+		if input.Content != nil {
+			content = *input.Content
+		}
+
+		err = queries.UpsertStoryTx(ctx, UpsertStoryTxParams{
+			StoryID:    id,
+			LocaleCode: localeCode,
+			Title:      *input.Title,
+			Summary:    summary,
+			Content:    content,
+		})
+		if err != nil {
+			return err
+		}
+
+		return insertStoryRevision(ctx, queries, revision)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	r.forgetStoryIDBySlug(ctx, slug)
+
+	if input.Slug != nil {
+		r.forgetStoryIDBySlug(ctx, *input.Slug)
+	}
+
+	return updated, nil
+}
+
+func (r *Repository) UpdateStoryStatus(
+	ctx context.Context,
+	story *stories.AuthoredStory,
+	status string,
+	publicationID string,
+) (int64, error) {
+	var updated int64
+
+	err := r.withTx(ctx, func(queries *Queries) error {
+		var err error
+
+		updated, err = queries.UpdateStoryStatus(ctx, UpdateStoryStatusParams{
+			Status:        status,
+			ID:            story.ID,
+			CurrentStatus: story.Status,
+		})
+		if err != nil || updated == 0 {
+			return err
+		}
+
+		switch {
+		case status == stories.StoryStatusPublished:
+			return queries.UpsertStoryPublication(ctx, UpsertStoryPublicationParams{
+				ID:        publicationID,
+				StoryID:   story.ID,
+				ProfileID: story.AuthorProfileID,
+				Kind:      stories.PublicationKindOriginal,
+			})
+		case story.Status == stories.StoryStatusPublished:
+			_, err = queries.RemoveStoryPublication(ctx, RemoveStoryPublicationParams{
+				StoryID:   story.ID,
+				ProfileID: story.AuthorProfileID,
+				Kind:      stories.PublicationKindOriginal,
+			})
+
+			return err
+		default:
+			return nil
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return updated, nil
+}
+
+func (r *Repository) RemoveStory(ctx context.Context, id string, slug string) (int64, error) {
+	deleted, err := r.queries.RemoveStory(ctx, RemoveStoryParams{ID: id})
+	if err != nil {
+		return 0, err
+	}
+
+	r.forgetStoryIDBySlug(ctx, slug)
+
+	return deleted, nil
+}
+
+func (r *Repository) SetStoryPublishAt(
+	ctx context.Context,
+	id string,
+	publishAt *time.Time,
+) (int64, error) {
+	return r.queries.SetStoryPublishAt(ctx, SetStoryPublishAtParams{ //nolint:wrapcheck
+		PublishAt: vars.ToSQLNullTime(publishAt),
+		ID:        id,
+	})
+}
+
+func (r *Repository) ListDueStories(
+	ctx context.Context,
+	dueAt time.Time,
+	limit int,
+) ([]*stories.AuthoredStory, error) {
+	rows, err := r.queries.ListDueStories(ctx, ListDueStoriesParams{
+		DueAt:      dueAt,
+		LimitCount: int32(limit), //nolint:gosec
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*stories.AuthoredStory, len(rows))
+	for i, row := range rows {
+		result[i] = &stories.AuthoredStory{
+			ID:                row.ID,
+			Status:            row.Status,
+			AuthorProfileID:   row.AuthorProfileID,
+			AuthorProfileSlug: row.AuthorProfileSlug,
+		}
+	}
+
+	return result, nil
+}
+
 func (r *Repository) parseStoryWithChildren( //nolint:funlen
 	profile Profile,
 	profileTx ProfileTx,
@@ -160,6 +452,7 @@ func (r *Repository) parseStoryWithChildren( //nolint:funlen
 			Summary:         storyTx.Summary,
 			Content:         storyTx.Content,
 			Properties:      vars.ToObject(story.Properties),
+			PublishAt:       vars.ToTimePtr(story.PublishAt),
 			CreatedAt:       story.CreatedAt,
 			UpdatedAt:       vars.ToTimePtr(story.UpdatedAt),
 			DeletedAt:       vars.ToTimePtr(story.DeletedAt),
@@ -203,6 +496,13 @@ func (r *Repository) parseStoryWithChildren( //nolint:funlen
 		} `json:"profile_tx"`
 	}
 
+	// stories that are not published have no publications.
+	if len(publications) == 0 {
+		storyWithChildren.Publications = []*profiles.Profile{}
+
+		return storyWithChildren, nil
+	}
+
 	err := json.Unmarshal(publications, &publicationProfiles)
 	if err != nil {
 		r.logger.Error("failed to unmarshal publications", "error", err)
@@ -230,3 +530,12 @@ func (r *Repository) parseStoryWithChildren( //nolint:funlen
 
 	return storyWithChildren, nil
 }
+
+// forgetStoryIDBySlug drops the cached ID of a slug. The write is stored already, so
+// a failure only delays it until the cached ID expires.
+func (r *Repository) forgetStoryIDBySlug(ctx context.Context, slug string) {
+	err := r.CacheRemove(ctx, "story_id_by_slug:"+slug)
+	if err != nil {
+		r.logger.WarnContext(ctx, "failed to remove cached story id", "slug", slug, "error", err)
+	}
+}
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/eser/aya.is-services/pkg/api/business/comments"
+	"github.com/eser/aya.is-services/pkg/lib/cursors"
+	"github.com/eser/aya.is-services/pkg/lib/vars"
+)
+
+func (r *Repository) CreateStoryComment(ctx context.Context, comment *comments.Comment) error {
+	return r.queries.CreateStoryComment(ctx, CreateStoryCommentParams{ //nolint:wrapcheck
+		ID:           comment.ID,
+		StoryID:      comment.StoryID,
+		ParentID:     vars.ToSQLNullString(comment.ParentID),
+		AuthorUserID: comment.AuthorUserID,
+		Content:      comment.Content,
+		Status:       comment.Status,
+	})
+}
+
+func (r *Repository) GetStoryComment(
+	ctx context.Context,
+	storyID string,
+	id string,
+) (*comments.Comment, error) {
+	row, err := r.queries.GetStoryComment(ctx, GetStoryCommentParams{ID: id, StoryID: storyID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, err
+	}
+
+	return toComment(
+		&row.StoryComment,
+		row.AuthorName,
+		row.AuthorProfileSlug,
+		row.ReplyCount,
+		row.ReportCount,
+	), nil
+}
+
+func (r *Repository) ListStoryComments(
+	ctx context.Context,
+	storyID string,
+	parentID *string,
+	onlyReported bool,
+	cursor *cursors.Cursor,
+) (cursors.Cursored[[]*comments.Comment], error) {
+	var wrappedResponse cursors.Cursored[[]*comments.Comment]
+
+	rows, err := r.queries.ListStoryComments(ctx, ListStoryCommentsParams{
+		StoryID:        storyID,
+		FilterParentID: vars.ToSQLNullString(parentID),
+		OnlyReported:   onlyReported,
+		CursorID:       cursorOffset(cursor),
+		LimitCount:     int32(cursor.Limit), //nolint:gosec
+	})
+	if err != nil {
+		return wrappedResponse, err
+	}
+
+	result := make([]*comments.Comment, len(rows))
+	for i, row := range rows {
+		result[i] = toComment(
+			&row.StoryComment,
+			row.AuthorName,
+			row.AuthorProfileSlug,
+			row.ReplyCount,
+			row.ReportCount,
+		)
+	}
+
+	wrappedResponse.Data = result
+
+	if len(result) == cursor.Limit {
+		wrappedResponse.CursorPtr = &result[len(result)-1].ID
+	}
+
+	return wrappedResponse, nil
+}
+
+func (r *Repository) UpdateStoryCommentStatus(
+	ctx context.Context,
+	id string,
+	status string,
+) (int64, error) {
+	var updated int64
+
+	err := r.withTx(ctx, func(queries *Queries) error {
+		var err error
+
+		updated, err = queries.UpdateStoryCommentStatus(
+			ctx,
+			UpdateStoryCommentStatusParams{Status: status, ID: id},
+		)
+		if err != nil || updated == 0 {
+			return err
+		}
+
+		_, err = queries.ResolveStoryCommentReports(
+			ctx,
+			ResolveStoryCommentReportsParams{CommentID: id},
+		)
+
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return updated, nil
+}
+
+func (r *Repository) RemoveStoryComment(ctx context.Context, id string) (int64, error) {
+	var deleted int64
+
+	err := r.withTx(ctx, func(queries *Queries) error {
+		var err error
+
+		deleted, err = queries.RemoveStoryComment(ctx, RemoveStoryCommentParams{ID: id})
+		if err != nil || deleted == 0 {
+			return err
+		}
+
+		_, err = queries.ResolveStoryCommentReports(
+			ctx,
+			ResolveStoryCommentReportsParams{CommentID: id},
+		)
+
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+func (r *Repository) CreateStoryCommentReport(
+	ctx context.Context,
+	id string,
+	commentID string,
+	reporterUserID string,
+	reason string,
+) (bool, error) {
+	affected, err := r.queries.CreateStoryCommentReport(ctx, CreateStoryCommentReportParams{
+		ID:             id,
+		CommentID:      commentID,
+		ReporterUserID: reporterUserID,
+		Reason:         reason,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func toComment(
+	comment *StoryComment,
+	authorName string,
+	authorProfileSlug sql.NullString,
+	replyCount int64,
+	reportCount int64,
+) *comments.Comment {
+	return &comments.Comment{
+		CreatedAt:         comment.CreatedAt,
+		ParentID:          vars.ToStringPtr(comment.ParentID),
+		AuthorProfileSlug: vars.ToStringPtr(authorProfileSlug),
+		UpdatedAt:         vars.ToTimePtr(comment.UpdatedAt),
+		ReportCount:       &reportCount,
+		ID:                comment.ID,
+		StoryID:           comment.StoryID,
+		AuthorUserID:      comment.AuthorUserID,
+		AuthorName:        authorName,
+		Content:           comment.Content,
+		Status:            comment.Status,
+		ReplyCount:        replyCount,
+	}
+}
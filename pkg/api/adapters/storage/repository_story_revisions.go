@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/eser/aya.is-services/pkg/api/business/stories"
+)
+
+func (r *Repository) ListStoryRevisions(
+	ctx context.Context,
+	storyID string,
+	localeCode string,
+) ([]*stories.StoryRevision, error) {
+	rows, err := r.queries.ListStoryRevisions(
+		ctx,
+		ListStoryRevisionsParams{StoryID: storyID, LocaleCode: localeCode},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*stories.StoryRevision, len(rows))
+	for i, row := range rows {
+		result[i] = parseStoryRevision(row)
+	}
+
+	return result, nil
+}
+
+func (r *Repository) GetStoryRevision(
+	ctx context.Context,
+	storyID string,
+	localeCode string,
+	id string,
+) (*stories.StoryRevision, error) {
+	row, err := r.queries.GetStoryRevision(
+		ctx,
+		GetStoryRevisionParams{ID: id, StoryID: storyID, LocaleCode: localeCode},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, err
+	}
+
+	return parseStoryRevision(row), nil
+}
+
+func (r *Repository) PruneStoryRevisions(
+	ctx context.Context,
+	storyID string,
+	localeCode string,
+	keep int,
+) (int64, error) {
+	return r.queries.PruneStoryRevisions(ctx, PruneStoryRevisionsParams{ //nolint:wrapcheck
+		StoryID:    storyID,
+		LocaleCode: localeCode,
+		KeepCount:  int32(keep), //nolint:gosec
+	})
+}
+
+func insertStoryRevision(
+	ctx context.Context,
+	queries *Queries,
+	revision *stories.StoryRevision,
+) error {
+	if revision == nil {
+		return nil
+	}
+
+	return queries.CreateStoryRevision(ctx, CreateStoryRevisionParams{
+		ID:           revision.ID,
+		StoryID:      revision.StoryID,
+		LocaleCode:   revision.LocaleCode,
+		AuthorUserID: revision.AuthorUserID,
+		Title:        revision.Title,
+		Summary:      revision.Summary,
+		Content:      revision.Content,
+	})
+}
+
+func parseStoryRevision(row *StoryRevision) *stories.StoryRevision {
+	return &stories.StoryRevision{
+		CreatedAt:    row.CreatedAt,
+		ID:           row.ID,
+		StoryID:      row.StoryID,
+		LocaleCode:   row.LocaleCode,
+		AuthorUserID: row.AuthorUserID,
+		Title:        row.Title,
+		Summary:      row.Summary,
+		Content:      row.Content,
+	}
+}
@@ -9,11 +9,118 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"time"
 )
 
+const createStory = `-- name: CreateStory :exec
+INSERT INTO "story" (id, author_profile_id, slug, kind, status, story_picture_uri, title, summary, content)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+  )
+`
+
+type CreateStoryParams struct {
+	ID              string         `db:"id" json:"id"`
+	AuthorProfileID sql.NullString `db:"author_profile_id" json:"author_profile_id"`
+	Slug            string         `db:"slug" json:"slug"`
+	Kind            string         `db:"kind" json:"kind"`
+	Status          string         `db:"status" json:"status"`
+	StoryPictureURI sql.NullString `db:"story_picture_uri" json:"story_picture_uri"`
+	Title           string         `db:"title" json:"title"`
+	Summary         string         `db:"summary" json:"summary"`
+	Content         string         `db:"content" json:"content"`
+}
+
+// CreateStory
+//
+//	INSERT INTO "story" (id, author_profile_id, slug, kind, status, story_picture_uri, title, summary, content)
+//	VALUES (
+//	    $1,
+//	    $2,
+//	    $3,
+//	    $4,
+//	    $5,
+//	    $6,
+//	    $7,
+//	    $8,
+//	    $9
+//	  )
+func (q *Queries) CreateStory(ctx context.Context, arg CreateStoryParams) error {
+	_, err := q.db.ExecContext(ctx, createStory,
+		arg.ID,
+		arg.AuthorProfileID,
+		arg.Slug,
+		arg.Kind,
+		arg.Status,
+		arg.StoryPictureURI,
+		arg.Title,
+		arg.Summary,
+		arg.Content,
+	)
+	return err
+}
+
+const getAuthoredStoryBySlug = `-- name: GetAuthoredStoryBySlug :one
+SELECT
+  s.id,
+  s.status,
+  p.id AS author_profile_id,
+  p.slug AS author_profile_slug
+FROM "story" s
+  INNER JOIN "profile" p ON p.id = s.author_profile_id
+  AND p.deleted_at IS NULL
+WHERE s.slug = $1
+  AND s.deleted_at IS NULL
+LIMIT 1
+`
+
+type GetAuthoredStoryBySlugParams struct {
+	Slug string `db:"slug" json:"slug"`
+}
+
+type GetAuthoredStoryBySlugRow struct {
+	ID                string `db:"id" json:"id"`
+	Status            string `db:"status" json:"status"`
+	AuthorProfileID   string `db:"author_profile_id" json:"author_profile_id"`
+	AuthorProfileSlug string `db:"author_profile_slug" json:"author_profile_slug"`
+}
+
+// GetAuthoredStoryBySlug
+//
+//	SELECT
+//	  s.id,
+//	  s.status,
+//	  p.id AS author_profile_id,
+//	  p.slug AS author_profile_slug
+//	FROM "story" s
+//	  INNER JOIN "profile" p ON p.id = s.author_profile_id
+//	  AND p.deleted_at IS NULL
+//	WHERE s.slug = $1
+//	  AND s.deleted_at IS NULL
+//	LIMIT 1
+func (q *Queries) GetAuthoredStoryBySlug(ctx context.Context, arg GetAuthoredStoryBySlugParams) (*GetAuthoredStoryBySlugRow, error) {
+	row := q.db.QueryRowContext(ctx, getAuthoredStoryBySlug, arg.Slug)
+	var i GetAuthoredStoryBySlugRow
+	err := row.Scan(
+		&i.ID,
+		&i.Status,
+		&i.AuthorProfileID,
+		&i.AuthorProfileSlug,
+	)
+	return &i, err
+}
+
 const getStoryByID = `-- name: GetStoryByID :one
 SELECT
-  s.id, s.author_profile_id, s.slug, s.kind, s.status, s.is_featured, s.story_picture_uri, s.title, s.summary, s.content, s.properties, s.created_at, s.updated_at, s.deleted_at,
+  s.id, s.author_profile_id, s.slug, s.kind, s.status, s.is_featured, s.story_picture_uri, s.title, s.summary, s.content, s.properties, s.created_at, s.updated_at, s.deleted_at, s.publish_at,
   st.story_id, st.locale_code, st.title, st.summary, st.content,
   p.id, p.slug, p.kind, p.custom_domain, p.profile_picture_uri, p.pronouns, p.properties, p.created_at, p.updated_at, p.deleted_at,
   pt.profile_id, pt.locale_code, pt.title, pt.description, pt.properties,
@@ -62,7 +169,7 @@ type GetStoryByIDRow struct {
 // GetStoryByID
 //
 //	SELECT
-//	  s.id, s.author_profile_id, s.slug, s.kind, s.status, s.is_featured, s.story_picture_uri, s.title, s.summary, s.content, s.properties, s.created_at, s.updated_at, s.deleted_at,
+//	  s.id, s.author_profile_id, s.slug, s.kind, s.status, s.is_featured, s.story_picture_uri, s.title, s.summary, s.content, s.properties, s.created_at, s.updated_at, s.deleted_at, s.publish_at,
 //	  st.story_id, st.locale_code, st.title, st.summary, st.content,
 //	  p.id, p.slug, p.kind, p.custom_domain, p.profile_picture_uri, p.pronouns, p.properties, p.created_at, p.updated_at, p.deleted_at,
 //	  pt.profile_id, pt.locale_code, pt.title, pt.description, pt.properties,
@@ -114,6 +221,7 @@ func (q *Queries) GetStoryByID(ctx context.Context, arg GetStoryByIDParams) (*Ge
 		&i.Story.CreatedAt,
 		&i.Story.UpdatedAt,
 		&i.Story.DeletedAt,
+		&i.Story.PublishAt,
 		&i.StoryTx.StoryID,
 		&i.StoryTx.LocaleCode,
 		&i.StoryTx.Title,
@@ -165,10 +273,258 @@ func (q *Queries) GetStoryIDBySlug(ctx context.Context, arg GetStoryIDBySlugPara
 	return id, err
 }
 
+const listDueStories = `-- name: ListDueStories :many
+SELECT
+  s.id,
+  s.status,
+  p.id AS author_profile_id,
+  p.slug AS author_profile_slug
+FROM "story" s
+  INNER JOIN "profile" p ON p.id = s.author_profile_id
+  AND p.deleted_at IS NULL
+WHERE s.publish_at <= $1
+  AND s.status IN ('draft', 'in_review')
+  AND s.deleted_at IS NULL
+ORDER BY s.publish_at
+LIMIT $2
+`
+
+type ListDueStoriesParams struct {
+	DueAt      time.Time `db:"due_at" json:"due_at"`
+	LimitCount int32     `db:"limit_count" json:"limit_count"`
+}
+
+type ListDueStoriesRow struct {
+	ID                string `db:"id" json:"id"`
+	Status            string `db:"status" json:"status"`
+	AuthorProfileID   string `db:"author_profile_id" json:"author_profile_id"`
+	AuthorProfileSlug string `db:"author_profile_slug" json:"author_profile_slug"`
+}
+
+// ListDueStories
+//
+//	SELECT
+//	  s.id,
+//	  s.status,
+//	  p.id AS author_profile_id,
+//	  p.slug AS author_profile_slug
+//	FROM "story" s
+//	  INNER JOIN "profile" p ON p.id = s.author_profile_id
+//	  AND p.deleted_at IS NULL
+//	WHERE s.publish_at <= $1
+//	  AND s.status IN ('draft', 'in_review')
+//	  AND s.deleted_at IS NULL
+//	ORDER BY s.publish_at
+//	LIMIT $2
+func (q *Queries) ListDueStories(ctx context.Context, arg ListDueStoriesParams) ([]*ListDueStoriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listDueStories, arg.DueAt, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*ListDueStoriesRow{}
+	for rows.Next() {
+		var i ListDueStoriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Status,
+			&i.AuthorProfileID,
+			&i.AuthorProfileSlug,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStoriesOfFollowedProfiles = `-- name: ListStoriesOfFollowedProfiles :many
+SELECT
+  s.id, s.author_profile_id, s.slug, s.kind, s.status, s.is_featured, s.story_picture_uri, s.title, s.summary, s.content, s.properties, s.created_at, s.updated_at, s.deleted_at, s.publish_at,
+  st.story_id, st.locale_code, st.title, st.summary, st.content,
+  p1.id, p1.slug, p1.kind, p1.custom_domain, p1.profile_picture_uri, p1.pronouns, p1.properties, p1.created_at, p1.updated_at, p1.deleted_at,
+  p1t.profile_id, p1t.locale_code, p1t.title, p1t.description, p1t.properties,
+  pb.publications
+FROM "story" s
+  INNER JOIN "story_tx" st ON st.story_id = s.id
+  AND st.locale_code = $1
+  LEFT JOIN "profile" p1 ON p1.id = s.author_profile_id
+  AND p1.deleted_at IS NULL
+  INNER JOIN "profile_tx" p1t ON p1t.profile_id = p1.id
+  AND p1t.locale_code = $1
+  LEFT JOIN LATERAL (
+    SELECT JSONB_AGG(
+      JSONB_BUILD_OBJECT('profile', row_to_json(p2), 'profile_tx', row_to_json(p2t))
+    ) AS "publications"
+    FROM story_publication sp
+      INNER JOIN "profile" p2 ON p2.id = sp.profile_id
+      AND p2.deleted_at IS NULL
+      INNER JOIN "profile_tx" p2t ON p2t.profile_id = p2.id
+      AND p2t.locale_code = $1
+    WHERE sp.story_id = s.id
+      AND sp.deleted_at IS NULL
+  ) pb ON TRUE
+WHERE
+  pb.publications IS NOT NULL
+  AND (
+    s.author_profile_id IN (
+      SELECT pf.followed_profile_id
+      FROM "profile_follow" pf
+      WHERE pf.follower_profile_id = $2
+    )
+    OR EXISTS (
+      SELECT 1
+      FROM "story_publication" fsp
+        INNER JOIN "profile_follow" fpf ON fpf.followed_profile_id = fsp.profile_id
+        AND fpf.follower_profile_id = $2
+      WHERE fsp.story_id = s.id
+        AND fsp.deleted_at IS NULL
+    )
+  )
+  AND ($3::CHAR(26) IS NULL OR s.id < $3::CHAR(26))
+  AND s.deleted_at IS NULL
+ORDER BY s.id DESC
+LIMIT $4
+`
+
+type ListStoriesOfFollowedProfilesParams struct {
+	LocaleCode        string         `db:"locale_code" json:"locale_code"`
+	FollowerProfileID string         `db:"follower_profile_id" json:"follower_profile_id"`
+	CursorID          sql.NullString `db:"cursor_id" json:"cursor_id"`
+	LimitCount        int32          `db:"limit_count" json:"limit_count"`
+}
+
+type ListStoriesOfFollowedProfilesRow struct {
+	Story        Story           `db:"story" json:"story"`
+	StoryTx      StoryTx         `db:"story_tx" json:"story_tx"`
+	Profile      Profile         `db:"profile" json:"profile"`
+	ProfileTx    ProfileTx       `db:"profile_tx" json:"profile_tx"`
+	Publications json.RawMessage `db:"publications" json:"publications"`
+}
+
+// ListStoriesOfFollowedProfiles
+//
+//	SELECT
+//	  s.id, s.author_profile_id, s.slug, s.kind, s.status, s.is_featured, s.story_picture_uri, s.title, s.summary, s.content, s.properties, s.created_at, s.updated_at, s.deleted_at, s.publish_at,
+//	  st.story_id, st.locale_code, st.title, st.summary, st.content,
+//	  p1.id, p1.slug, p1.kind, p1.custom_domain, p1.profile_picture_uri, p1.pronouns, p1.properties, p1.created_at, p1.updated_at, p1.deleted_at,
+//	  p1t.profile_id, p1t.locale_code, p1t.title, p1t.description, p1t.properties,
+//	  pb.publications
+//	FROM "story" s
+//	  INNER JOIN "story_tx" st ON st.story_id = s.id
+//	  AND st.locale_code = $1
+//	  LEFT JOIN "profile" p1 ON p1.id = s.author_profile_id
+//	  AND p1.deleted_at IS NULL
+//	  INNER JOIN "profile_tx" p1t ON p1t.profile_id = p1.id
+//	  AND p1t.locale_code = $1
+//	  LEFT JOIN LATERAL (
+//	    SELECT JSONB_AGG(
+//	      JSONB_BUILD_OBJECT('profile', row_to_json(p2), 'profile_tx', row_to_json(p2t))
+//	    ) AS "publications"
+//	    FROM story_publication sp
+//	      INNER JOIN "profile" p2 ON p2.id = sp.profile_id
+//	      AND p2.deleted_at IS NULL
+//	      INNER JOIN "profile_tx" p2t ON p2t.profile_id = p2.id
+//	      AND p2t.locale_code = $1
+//	    WHERE sp.story_id = s.id
+//	      AND sp.deleted_at IS NULL
+//	  ) pb ON TRUE
+//	WHERE
+//	  pb.publications IS NOT NULL
+//	  AND (
+//	    s.author_profile_id IN (
+//	      SELECT pf.followed_profile_id
+//	      FROM "profile_follow" pf
+//	      WHERE pf.follower_profile_id = $2
+//	    )
+//	    OR EXISTS (
+//	      SELECT 1
+//	      FROM "story_publication" fsp
+//	        INNER JOIN "profile_follow" fpf ON fpf.followed_profile_id = fsp.profile_id
+//	        AND fpf.follower_profile_id = $2
+//	      WHERE fsp.story_id = s.id
+//	        AND fsp.deleted_at IS NULL
+//	    )
+//	  )
+//	  AND ($3::CHAR(26) IS NULL OR s.id < $3::CHAR(26))
+//	  AND s.deleted_at IS NULL
+//	ORDER BY s.id DESC
+//	LIMIT $4
+func (q *Queries) ListStoriesOfFollowedProfiles(ctx context.Context, arg ListStoriesOfFollowedProfilesParams) ([]*ListStoriesOfFollowedProfilesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listStoriesOfFollowedProfiles,
+		arg.LocaleCode,
+		arg.FollowerProfileID,
+		arg.CursorID,
+		arg.LimitCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*ListStoriesOfFollowedProfilesRow{}
+	for rows.Next() {
+		var i ListStoriesOfFollowedProfilesRow
+		if err := rows.Scan(
+			&i.Story.ID,
+			&i.Story.AuthorProfileID,
+			&i.Story.Slug,
+			&i.Story.Kind,
+			&i.Story.Status,
+			&i.Story.IsFeatured,
+			&i.Story.StoryPictureURI,
+			&i.Story.Title,
+			&i.Story.Summary,
+			&i.Story.Content,
+			&i.Story.Properties,
+			&i.Story.CreatedAt,
+			&i.Story.UpdatedAt,
+			&i.Story.DeletedAt,
+			&i.Story.PublishAt,
+			&i.StoryTx.StoryID,
+			&i.StoryTx.LocaleCode,
+			&i.StoryTx.Title,
+			&i.StoryTx.Summary,
+			&i.StoryTx.Content,
+			&i.Profile.ID,
+			&i.Profile.Slug,
+			&i.Profile.Kind,
+			&i.Profile.CustomDomain,
+			&i.Profile.ProfilePictureURI,
+			&i.Profile.Pronouns,
+			&i.Profile.Properties,
+			&i.Profile.CreatedAt,
+			&i.Profile.UpdatedAt,
+			&i.Profile.DeletedAt,
+			&i.ProfileTx.ProfileID,
+			&i.ProfileTx.LocaleCode,
+			&i.ProfileTx.Title,
+			&i.ProfileTx.Description,
+			&i.ProfileTx.Properties,
+			&i.Publications,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listStoriesOfPublication = `-- name: ListStoriesOfPublication :many
 
 SELECT
-  s.id, s.author_profile_id, s.slug, s.kind, s.status, s.is_featured, s.story_picture_uri, s.title, s.summary, s.content, s.properties, s.created_at, s.updated_at, s.deleted_at,
+  s.id, s.author_profile_id, s.slug, s.kind, s.status, s.is_featured, s.story_picture_uri, s.title, s.summary, s.content, s.properties, s.created_at, s.updated_at, s.deleted_at, s.publish_at,
   st.story_id, st.locale_code, st.title, st.summary, st.content,
   p1.id, p1.slug, p1.kind, p1.custom_domain, p1.profile_picture_uri, p1.pronouns, p1.properties, p1.created_at, p1.updated_at, p1.deleted_at,
   p1t.profile_id, p1t.locale_code, p1t.title, p1t.description, p1t.properties,
@@ -231,7 +587,7 @@ type ListStoriesOfPublicationRow struct {
 // ORDER BY s.created_at DESC;
 //
 //	SELECT
-//	  s.id, s.author_profile_id, s.slug, s.kind, s.status, s.is_featured, s.story_picture_uri, s.title, s.summary, s.content, s.properties, s.created_at, s.updated_at, s.deleted_at,
+//	  s.id, s.author_profile_id, s.slug, s.kind, s.status, s.is_featured, s.story_picture_uri, s.title, s.summary, s.content, s.properties, s.created_at, s.updated_at, s.deleted_at, s.publish_at,
 //	  st.story_id, st.locale_code, st.title, st.summary, st.content,
 //	  p1.id, p1.slug, p1.kind, p1.custom_domain, p1.profile_picture_uri, p1.pronouns, p1.properties, p1.created_at, p1.updated_at, p1.deleted_at,
 //	  p1t.profile_id, p1t.locale_code, p1t.title, p1t.description, p1t.properties,
@@ -291,6 +647,7 @@ func (q *Queries) ListStoriesOfPublication(ctx context.Context, arg ListStoriesO
 			&i.Story.CreatedAt,
 			&i.Story.UpdatedAt,
 			&i.Story.DeletedAt,
+			&i.Story.PublishAt,
 			&i.StoryTx.StoryID,
 			&i.StoryTx.LocaleCode,
 			&i.StoryTx.Title,
@@ -325,3 +682,233 @@ func (q *Queries) ListStoriesOfPublication(ctx context.Context, arg ListStoriesO
 	}
 	return items, nil
 }
+
+const removeStory = `-- name: RemoveStory :execrows
+UPDATE "story"
+SET deleted_at = NOW()
+WHERE id = $1
+  AND deleted_at IS NULL
+`
+
+type RemoveStoryParams struct {
+	ID string `db:"id" json:"id"`
+}
+
+// RemoveStory
+//
+//	UPDATE "story"
+//	SET deleted_at = NOW()
+//	WHERE id = $1
+//	  AND deleted_at IS NULL
+func (q *Queries) RemoveStory(ctx context.Context, arg RemoveStoryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, removeStory, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const removeStoryPublication = `-- name: RemoveStoryPublication :execrows
+UPDATE "story_publication"
+SET deleted_at = NOW()
+WHERE story_id = $1
+  AND profile_id = $2
+  AND kind = $3
+  AND deleted_at IS NULL
+`
+
+type RemoveStoryPublicationParams struct {
+	StoryID   string `db:"story_id" json:"story_id"`
+	ProfileID string `db:"profile_id" json:"profile_id"`
+	Kind      string `db:"kind" json:"kind"`
+}
+
+// RemoveStoryPublication
+//
+//	UPDATE "story_publication"
+//	SET deleted_at = NOW()
+//	WHERE story_id = $1
+//	  AND profile_id = $2
+//	  AND kind = $3
+//	  AND deleted_at IS NULL
+func (q *Queries) RemoveStoryPublication(ctx context.Context, arg RemoveStoryPublicationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, removeStoryPublication, arg.StoryID, arg.ProfileID, arg.Kind)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setStoryPublishAt = `-- name: SetStoryPublishAt :execrows
+UPDATE "story"
+SET publish_at = $1,
+  updated_at = NOW()
+WHERE id = $2
+  AND status IN ('draft', 'in_review')
+  AND deleted_at IS NULL
+`
+
+type SetStoryPublishAtParams struct {
+	PublishAt sql.NullTime `db:"publish_at" json:"publish_at"`
+	ID        string       `db:"id" json:"id"`
+}
+
+// SetStoryPublishAt
+//
+//	UPDATE "story"
+//	SET publish_at = $1,
+//	  updated_at = NOW()
+//	WHERE id = $2
+//	  AND status IN ('draft', 'in_review')
+//	  AND deleted_at IS NULL
+func (q *Queries) SetStoryPublishAt(ctx context.Context, arg SetStoryPublishAtParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setStoryPublishAt, arg.PublishAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateStory = `-- name: UpdateStory :execrows
+UPDATE "story"
+SET slug = COALESCE($1, slug),
+  story_picture_uri = COALESCE($2, story_picture_uri),
+  updated_at = NOW()
+WHERE id = $3
+  AND deleted_at IS NULL
+`
+
+type UpdateStoryParams struct {
+	Slug            sql.NullString `db:"slug" json:"slug"`
+	StoryPictureURI sql.NullString `db:"story_picture_uri" json:"story_picture_uri"`
+	ID              string         `db:"id" json:"id"`
+}
+
+// UpdateStory
+//
+//	UPDATE "story"
+//	SET slug = COALESCE($1, slug),
+//	  story_picture_uri = COALESCE($2, story_picture_uri),
+//	  updated_at = NOW()
+//	WHERE id = $3
+//	  AND deleted_at IS NULL
+func (q *Queries) UpdateStory(ctx context.Context, arg UpdateStoryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateStory, arg.Slug, arg.StoryPictureURI, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateStoryStatus = `-- name: UpdateStoryStatus :execrows
+UPDATE "story"
+SET status = $1,
+  publish_at = CASE WHEN $1::TEXT = 'published' THEN NULL ELSE publish_at END,
+  updated_at = NOW()
+WHERE id = $2
+  AND status = $3
+  AND deleted_at IS NULL
+`
+
+type UpdateStoryStatusParams struct {
+	Status        string `db:"status" json:"status"`
+	ID            string `db:"id" json:"id"`
+	CurrentStatus string `db:"current_status" json:"current_status"`
+}
+
+// UpdateStoryStatus
+//
+//	UPDATE "story"
+//	SET status = $1,
+//	  publish_at = CASE WHEN $1::TEXT = 'published' THEN NULL ELSE publish_at END,
+//	  updated_at = NOW()
+//	WHERE id = $2
+//	  AND status = $3
+//	  AND deleted_at IS NULL
+func (q *Queries) UpdateStoryStatus(ctx context.Context, arg UpdateStoryStatusParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateStoryStatus, arg.Status, arg.ID, arg.CurrentStatus)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const upsertStoryPublication = `-- name: UpsertStoryPublication :exec
+INSERT INTO "story_publication" (id, story_id, profile_id, kind)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+  )
+ON CONFLICT (story_id, profile_id, kind) DO UPDATE SET deleted_at = NULL, updated_at = NOW()
+`
+
+type UpsertStoryPublicationParams struct {
+	ID        string `db:"id" json:"id"`
+	StoryID   string `db:"story_id" json:"story_id"`
+	ProfileID string `db:"profile_id" json:"profile_id"`
+	Kind      string `db:"kind" json:"kind"`
+}
+
+// UpsertStoryPublication
+//
+//	INSERT INTO "story_publication" (id, story_id, profile_id, kind)
+//	VALUES (
+//	    $1,
+//	    $2,
+//	    $3,
+//	    $4
+//	  )
+//	ON CONFLICT (story_id, profile_id, kind) DO UPDATE SET deleted_at = NULL, updated_at = NOW()
+func (q *Queries) UpsertStoryPublication(ctx context.Context, arg UpsertStoryPublicationParams) error {
+	_, err := q.db.ExecContext(ctx, upsertStoryPublication,
+		arg.ID,
+		arg.StoryID,
+		arg.ProfileID,
+		arg.Kind,
+	)
+	return err
+}
+
+const upsertStoryTx = `-- name: UpsertStoryTx :exec
+INSERT INTO "story_tx" (story_id, locale_code, title, summary, content)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+  )
+ON CONFLICT (story_id, locale_code) DO UPDATE SET title = $3, summary = $4, content = $5
+`
+
+type UpsertStoryTxParams struct {
+	StoryID    string `db:"story_id" json:"story_id"`
+	LocaleCode string `db:"locale_code" json:"locale_code"`
+	Title      string `db:"title" json:"title"`
+	Summary    string `db:"summary" json:"summary"`
+	Content    string `db:"content" json:"content"`
+}
+
+// UpsertStoryTx
+//
+//	INSERT INTO "story_tx" (story_id, locale_code, title, summary, content)
+//	VALUES (
+//	    $1,
+//	    $2,
+//	    $3,
+//	    $4,
+//	    $5
+//	  )
+//	ON CONFLICT (story_id, locale_code) DO UPDATE SET title = $3, summary = $4, content = $5
+func (q *Queries) UpsertStoryTx(ctx context.Context, arg UpsertStoryTxParams) error {
+	_, err := q.db.ExecContext(ctx, upsertStoryTx,
+		arg.StoryID,
+		arg.LocaleCode,
+		arg.Title,
+		arg.Summary,
+		arg.Content,
+	)
+	return err
+}
@@ -11,6 +11,18 @@ import (
 	"github.com/sqlc-dev/pqtype"
 )
 
+type ApiKey struct {
+	ID         string       `db:"id" json:"id"`
+	Name       string       `db:"name" json:"name"`
+	KeyPrefix  string       `db:"key_prefix" json:"key_prefix"`
+	KeyHash    string       `db:"key_hash" json:"key_hash"`
+	Scopes     string       `db:"scopes" json:"scopes"`
+	ExpiresAt  sql.NullTime `db:"expires_at" json:"expires_at"`
+	LastUsedAt sql.NullTime `db:"last_used_at" json:"last_used_at"`
+	CreatedAt  time.Time    `db:"created_at" json:"created_at"`
+	RevokedAt  sql.NullTime `db:"revoked_at" json:"revoked_at"`
+}
+
 type Cache struct {
 	Key       string                `db:"key" json:"key"`
 	Value     pqtype.NullRawMessage `db:"value" json:"value"`
@@ -69,6 +81,53 @@ type Profile struct {
 	DeletedAt         sql.NullTime          `db:"deleted_at" json:"deleted_at"`
 }
 
+type ProfileCustomDomainClaim struct {
+	ID             string         `db:"id" json:"id"`
+	ProfileID      string         `db:"profile_id" json:"profile_id"`
+	Domain         string         `db:"domain" json:"domain"`
+	ChallengeToken string         `db:"challenge_token" json:"challenge_token"`
+	Status         string         `db:"status" json:"status"`
+	Attempts       int32          `db:"attempts" json:"attempts"`
+	LastError      sql.NullString `db:"last_error" json:"last_error"`
+	LastCheckedAt  sql.NullTime   `db:"last_checked_at" json:"last_checked_at"`
+	VerifiedAt     sql.NullTime   `db:"verified_at" json:"verified_at"`
+	ExpiresAt      time.Time      `db:"expires_at" json:"expires_at"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+}
+
+type ProfileBadge struct {
+	ID        string         `db:"id" json:"id"`
+	ProfileID string         `db:"profile_id" json:"profile_id"`
+	Kind      string         `db:"kind" json:"kind"`
+	Source    string         `db:"source" json:"source"`
+	Note      sql.NullString `db:"note" json:"note"`
+	GrantedAt time.Time      `db:"granted_at" json:"granted_at"`
+	RevokedAt sql.NullTime   `db:"revoked_at" json:"revoked_at"`
+	RevokedBy sql.NullString `db:"revoked_by" json:"revoked_by"`
+}
+
+type ProfileExport struct {
+	ID                string         `db:"id" json:"id"`
+	ProfileID         string         `db:"profile_id" json:"profile_id"`
+	RequestedByUserID string         `db:"requested_by_user_id" json:"requested_by_user_id"`
+	Status            string         `db:"status" json:"status"`
+	Attempts          int32          `db:"attempts" json:"attempts"`
+	ObjectKey         sql.NullString `db:"object_key" json:"object_key"`
+	SizeBytes         sql.NullInt64  `db:"size_bytes" json:"size_bytes"`
+	LastError         sql.NullString `db:"last_error" json:"last_error"`
+	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
+	StartedAt         sql.NullTime   `db:"started_at" json:"started_at"`
+	CompletedAt       sql.NullTime   `db:"completed_at" json:"completed_at"`
+	ExpiresAt         sql.NullTime   `db:"expires_at" json:"expires_at"`
+}
+
+type ProfileFollow struct {
+	ID                string    `db:"id" json:"id"`
+	FollowerProfileID string    `db:"follower_profile_id" json:"follower_profile_id"`
+	FollowedProfileID string    `db:"followed_profile_id" json:"followed_profile_id"`
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
+}
+
 type ProfileLink struct {
 	ID                        string                `db:"id" json:"id"`
 	ProfileID                 string                `db:"profile_id" json:"profile_id"`
@@ -116,6 +175,18 @@ type ProfileMembership struct {
 	DeletedAt       sql.NullTime          `db:"deleted_at" json:"deleted_at"`
 }
 
+type ProfileMembershipInvitation struct {
+	ID              string       `db:"id" json:"id"`
+	ProfileID       string       `db:"profile_id" json:"profile_id"`
+	MemberProfileID string       `db:"member_profile_id" json:"member_profile_id"`
+	Kind            string       `db:"kind" json:"kind"`
+	TokenHash       string       `db:"token_hash" json:"token_hash"`
+	InvitedByUserID string       `db:"invited_by_user_id" json:"invited_by_user_id"`
+	ExpiresAt       time.Time    `db:"expires_at" json:"expires_at"`
+	AcceptedAt      sql.NullTime `db:"accepted_at" json:"accepted_at"`
+	CreatedAt       time.Time    `db:"created_at" json:"created_at"`
+}
+
 type ProfilePage struct {
 	ID              string         `db:"id" json:"id"`
 	ProfileID       string         `db:"profile_id" json:"profile_id"`
@@ -144,6 +215,15 @@ type ProfileTx struct {
 	Properties  pqtype.NullRawMessage `db:"properties" json:"properties"`
 }
 
+type ProfileView struct {
+	ID          string         `db:"id" json:"id"`
+	ProfileID   string         `db:"profile_id" json:"profile_id"`
+	StoryID     sql.NullString `db:"story_id" json:"story_id"`
+	VisitorHash string         `db:"visitor_hash" json:"visitor_hash"`
+	ViewedOn    time.Time      `db:"viewed_on" json:"viewed_on"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+}
+
 type Question struct {
 	ID            string         `db:"id" json:"id"`
 	UserID        string         `db:"user_id" json:"user_id"`
@@ -168,6 +248,22 @@ type QuestionVote struct {
 	CreatedAt  time.Time `db:"created_at" json:"created_at"`
 }
 
+type Reaction struct {
+	ID         string    `db:"id" json:"id"`
+	TargetKind string    `db:"target_kind" json:"target_kind"`
+	TargetID   string    `db:"target_id" json:"target_id"`
+	UserID     string    `db:"user_id" json:"user_id"`
+	Kind       string    `db:"kind" json:"kind"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+}
+
+type ReactionCount struct {
+	TargetKind string `db:"target_kind" json:"target_kind"`
+	TargetID   string `db:"target_id" json:"target_id"`
+	Kind       string `db:"kind" json:"kind"`
+	Count      int64  `db:"count" json:"count"`
+}
+
 type Session struct {
 	ID                       string         `db:"id" json:"id"`
 	Status                   string         `db:"status" json:"status"`
@@ -181,6 +277,13 @@ type Session struct {
 	UpdatedAt                sql.NullTime   `db:"updated_at" json:"updated_at"`
 }
 
+type SlugRedirect struct {
+	Kind      string    `db:"kind" json:"kind"`
+	OldSlug   string    `db:"old_slug" json:"old_slug"`
+	TargetID  string    `db:"target_id" json:"target_id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
 type Story struct {
 	ID              string                `db:"id" json:"id"`
 	AuthorProfileID sql.NullString        `db:"author_profile_id" json:"author_profile_id"`
@@ -196,6 +299,28 @@ type Story struct {
 	CreatedAt       time.Time             `db:"created_at" json:"created_at"`
 	UpdatedAt       sql.NullTime          `db:"updated_at" json:"updated_at"`
 	DeletedAt       sql.NullTime          `db:"deleted_at" json:"deleted_at"`
+	PublishAt       sql.NullTime          `db:"publish_at" json:"publish_at"`
+}
+
+type StoryComment struct {
+	ID           string         `db:"id" json:"id"`
+	StoryID      string         `db:"story_id" json:"story_id"`
+	ParentID     sql.NullString `db:"parent_id" json:"parent_id"`
+	AuthorUserID string         `db:"author_user_id" json:"author_user_id"`
+	Content      string         `db:"content" json:"content"`
+	Status       string         `db:"status" json:"status"`
+	CreatedAt    time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt    sql.NullTime   `db:"updated_at" json:"updated_at"`
+	DeletedAt    sql.NullTime   `db:"deleted_at" json:"deleted_at"`
+}
+
+type StoryCommentReport struct {
+	ID             string       `db:"id" json:"id"`
+	CommentID      string       `db:"comment_id" json:"comment_id"`
+	ReporterUserID string       `db:"reporter_user_id" json:"reporter_user_id"`
+	Reason         string       `db:"reason" json:"reason"`
+	CreatedAt      time.Time    `db:"created_at" json:"created_at"`
+	ResolvedAt     sql.NullTime `db:"resolved_at" json:"resolved_at"`
 }
 
 type StoryPublication struct {
@@ -209,6 +334,17 @@ type StoryPublication struct {
 	DeletedAt  sql.NullTime          `db:"deleted_at" json:"deleted_at"`
 }
 
+type StoryRevision struct {
+	ID           string    `db:"id" json:"id"`
+	StoryID      string    `db:"story_id" json:"story_id"`
+	LocaleCode   string    `db:"locale_code" json:"locale_code"`
+	AuthorUserID string    `db:"author_user_id" json:"author_user_id"`
+	Title        string    `db:"title" json:"title"`
+	Summary      string    `db:"summary" json:"summary"`
+	Content      string    `db:"content" json:"content"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
+
 type StoryTx struct {
 	StoryID    string `db:"story_id" json:"story_id"`
 	LocaleCode string `db:"locale_code" json:"locale_code"`
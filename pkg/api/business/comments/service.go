@@ -0,0 +1,488 @@
+package comments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/api/business/reactions"
+	"github.com/eser/aya.is-services/pkg/api/business/stories"
+	"github.com/eser/aya.is-services/pkg/lib/cursors"
+)
+
+const (
+	StatusVisible = "visible"
+	StatusHidden  = "hidden"
+	StatusDeleted = "deleted"
+
+	MaxContentLength = 10_000
+	MaxReasonLength  = 1_000
+
+	// FilterReported lists the comments with open reports alone, when it is "true" in
+	// the filters of a cursor.
+	FilterReported = "reported"
+)
+
+var (
+	ErrFailedToGetRecord    = errors.New("failed to get record")
+	ErrFailedToListRecords  = errors.New("failed to list records")
+	ErrFailedToCreateRecord = errors.New("failed to create record")
+	ErrFailedToUpdateRecord = errors.New("failed to update record")
+	ErrFailedToDeleteRecord = errors.New("failed to delete record")
+
+	ErrInvalidCommentInput = errors.New("invalid comment input")
+	ErrStoryNotFound       = errors.New("story not found")
+	ErrCommentsClosed      = errors.New("story is not open to comments")
+	ErrCommentNotFound     = errors.New("comment not found")
+	ErrNotModerator        = errors.New("not a moderator of the story")
+	ErrAlreadyReported     = errors.New("comment is reported by the user already")
+)
+
+type Repository interface {
+	// GetAuthoredStoryBySlug returns nil when no story with an author has the slug.
+	GetAuthoredStoryBySlug(ctx context.Context, slug string) (*stories.AuthoredStory, error)
+	IsProfileOwnedByUser(ctx context.Context, profileID string, userID string) (bool, error)
+	CreateStoryComment(ctx context.Context, comment *Comment) error
+	// GetStoryComment returns nil when the story has no such comment, or it is deleted.
+	GetStoryComment(ctx context.Context, storyID string, id string) (*Comment, error)
+	// ListStoryComments lists the replies of the comment parentID, or the comments on
+	// the story when it is nil, the earliest first. Deleted comments are listed while
+	// they have replies.
+	ListStoryComments(
+		ctx context.Context,
+		storyID string,
+		parentID *string,
+		onlyReported bool,
+		cursor *cursors.Cursor,
+	) (cursors.Cursored[[]*Comment], error)
+	// UpdateStoryCommentStatus and RemoveStoryComment resolve the open reports of the
+	// comment as well.
+	UpdateStoryCommentStatus(ctx context.Context, id string, status string) (int64, error)
+	RemoveStoryComment(ctx context.Context, id string) (int64, error)
+	CreateStoryCommentReport(
+		ctx context.Context,
+		id string,
+		commentID string,
+		reporterUserID string,
+		reason string,
+	) (bool, error)
+}
+
+type Service struct {
+	logger      *logfx.Logger
+	repo        Repository
+	reactions   *reactions.Service
+	idGenerator RecordIDGenerator
+}
+
+func NewService(
+	logger *logfx.Logger,
+	repo Repository,
+	reactionsService *reactions.Service,
+) *Service {
+	return &Service{
+		logger:      logger,
+		repo:        repo,
+		reactions:   reactionsService,
+		idGenerator: DefaultIDGenerator,
+	}
+}
+
+// List lists the comments on a published or archived story, or the replies of the
+// comment parentID. userID is "" for anonymous requests; the owners of the author
+// profile of the story moderate its comments, and they alone may list the reported
+// comments.
+func (s *Service) List(
+	ctx context.Context,
+	userID string,
+	storySlug string,
+	parentID *string,
+	cursor *cursors.Cursor,
+) (cursors.Cursored[[]*Comment], error) {
+	var result cursors.Cursored[[]*Comment]
+
+	story, err := s.getStory(ctx, storySlug)
+	if err != nil {
+		return result, err
+	}
+
+	if story.Status != stories.StoryStatusPublished && story.Status != stories.StoryStatusArchived {
+		return result, fmt.Errorf("%w(slug: %s)", ErrStoryNotFound, storySlug)
+	}
+
+	isModerator, err := s.isModerator(ctx, story, userID)
+	if err != nil {
+		return result, err
+	}
+
+	onlyReported := cursor.Filters[FilterReported] == "true"
+	if onlyReported && !isModerator {
+		return result, fmt.Errorf("%w(slug: %s)", ErrNotModerator, storySlug)
+	}
+
+	result, err = s.repo.ListStoryComments(ctx, story.ID, parentID, onlyReported, cursor)
+	if err != nil {
+		return result, fmt.Errorf("%w(slug: %s): %w", ErrFailedToListRecords, storySlug, err)
+	}
+
+	for _, comment := range result.Data {
+		redact(comment, isModerator)
+	}
+
+	err = s.attachReactions(ctx, result.Data...)
+	if err != nil {
+		return result, fmt.Errorf("%w(slug: %s): %w", ErrFailedToListRecords, storySlug, err)
+	}
+
+	return result, nil
+}
+
+// Create posts a comment on a published story on behalf of the user, or a reply to a
+// visible comment on it.
+func (s *Service) Create(
+	ctx context.Context,
+	userID string,
+	storySlug string,
+	input *CreateCommentInput,
+) (*Comment, error) {
+	content := strings.TrimSpace(input.Content)
+
+	if content == "" || utf8.RuneCountInString(content) > MaxContentLength {
+		return nil, fmt.Errorf(
+			"%w: content must be between 1 and %d characters",
+			ErrInvalidCommentInput,
+			MaxContentLength,
+		)
+	}
+
+	story, err := s.getStory(ctx, storySlug)
+	if err != nil {
+		return nil, err
+	}
+
+	if story.Status != stories.StoryStatusPublished {
+		return nil, fmt.Errorf("%w(slug: %s, status: %s)", ErrCommentsClosed, storySlug, story.Status)
+	}
+
+	if input.ParentID != nil {
+		parent, err := s.getComment(ctx, story.ID, *input.ParentID)
+		if err != nil {
+			return nil, err
+		}
+
+		if parent.Status != StatusVisible {
+			return nil, fmt.Errorf("%w(comment_id: %s)", ErrCommentNotFound, parent.ID)
+		}
+	}
+
+	comment := &Comment{ //nolint:exhaustruct
+		ParentID:     input.ParentID,
+		ID:           string(s.idGenerator()),
+		StoryID:      story.ID,
+		AuthorUserID: userID,
+		Content:      content,
+		Status:       StatusVisible,
+	}
+
+	err = s.repo.CreateStoryComment(ctx, comment)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToCreateRecord, storySlug, err)
+	}
+
+	s.logger.InfoContext(ctx, "comment posted", "slug", storySlug, "comment_id", comment.ID)
+
+	created, err := s.getComment(ctx, story.ID, comment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	redact(created, false)
+
+	created.Reactions = []*reactions.Count{}
+
+	return created, nil
+}
+
+// SetStatus hides a comment, or makes a hidden one visible again, on behalf of a
+// moderator of the story. Either way, the open reports of the comment are resolved.
+func (s *Service) SetStatus(
+	ctx context.Context,
+	userID string,
+	storySlug string,
+	commentID string,
+	status string,
+) (*Comment, error) {
+	if status != StatusVisible && status != StatusHidden {
+		return nil, fmt.Errorf(
+			"%w: status must be visible or hidden (status: %s)",
+			ErrInvalidCommentInput,
+			status,
+		)
+	}
+
+	story, err := s.getStory(ctx, storySlug)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.checkModerator(ctx, story, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the comment is looked up within the story, which the user moderates.
+	_, err = s.getComment(ctx, story.ID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.UpdateStoryCommentStatus(ctx, commentID, status)
+	if err != nil {
+		return nil, fmt.Errorf("%w(comment_id: %s): %w", ErrFailedToUpdateRecord, commentID, err)
+	}
+
+	if updated == 0 {
+		return nil, fmt.Errorf("%w(comment_id: %s)", ErrCommentNotFound, commentID)
+	}
+
+	s.logger.InfoContext(
+		ctx,
+		"comment moderated",
+		"slug", storySlug,
+		"comment_id", commentID,
+		"status", status,
+	)
+
+	comment, err := s.getComment(ctx, story.ID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	redact(comment, true)
+
+	err = s.attachReactions(ctx, comment)
+	if err != nil {
+		return nil, fmt.Errorf("%w(comment_id: %s): %w", ErrFailedToGetRecord, commentID, err)
+	}
+
+	return comment, nil
+}
+
+// Delete deletes a comment on behalf of its author or a moderator of the story. Its
+// replies are kept.
+func (s *Service) Delete(
+	ctx context.Context,
+	userID string,
+	storySlug string,
+	commentID string,
+) error {
+	story, err := s.getStory(ctx, storySlug)
+	if err != nil {
+		return err
+	}
+
+	comment, err := s.getComment(ctx, story.ID, commentID)
+	if err != nil {
+		return err
+	}
+
+	if comment.AuthorUserID != userID {
+		err = s.checkModerator(ctx, story, userID)
+		if err != nil {
+			return err
+		}
+	}
+
+	deleted, err := s.repo.RemoveStoryComment(ctx, commentID)
+	if err != nil {
+		return fmt.Errorf("%w(comment_id: %s): %w", ErrFailedToDeleteRecord, commentID, err)
+	}
+
+	if deleted == 0 {
+		return fmt.Errorf("%w(comment_id: %s)", ErrCommentNotFound, commentID)
+	}
+
+	s.logger.InfoContext(ctx, "comment deleted", "slug", storySlug, "comment_id", commentID)
+
+	return nil
+}
+
+// Report reports a comment to the moderators of the story on behalf of the user. A
+// user reports a comment once.
+func (s *Service) Report(
+	ctx context.Context,
+	userID string,
+	storySlug string,
+	commentID string,
+	reason string,
+) error {
+	reason = strings.TrimSpace(reason)
+
+	if reason == "" || utf8.RuneCountInString(reason) > MaxReasonLength {
+		return fmt.Errorf(
+			"%w: reason must be between 1 and %d characters",
+			ErrInvalidCommentInput,
+			MaxReasonLength,
+		)
+	}
+
+	story, err := s.getStory(ctx, storySlug)
+	if err != nil {
+		return err
+	}
+
+	comment, err := s.getComment(ctx, story.ID, commentID)
+	if err != nil {
+		return err
+	}
+
+	reported, err := s.repo.CreateStoryCommentReport(
+		ctx,
+		string(s.idGenerator()),
+		comment.ID,
+		userID,
+		reason,
+	)
+	if err != nil {
+		return fmt.Errorf("%w(comment_id: %s): %w", ErrFailedToCreateRecord, commentID, err)
+	}
+
+	if !reported {
+		return fmt.Errorf("%w(comment_id: %s)", ErrAlreadyReported, commentID)
+	}
+
+	s.logger.InfoContext(ctx, "comment reported", "slug", storySlug, "comment_id", commentID)
+
+	return nil
+}
+
+// React adds the reaction of the user of the kind to a visible comment on a published
+// or archived story when reacted is true, or removes it otherwise, and returns the
+// counts of the comment.
+func (s *Service) React(
+	ctx context.Context,
+	userID string,
+	storySlug string,
+	commentID string,
+	kind string,
+	reacted bool,
+) ([]*reactions.Count, error) {
+	story, err := s.getStory(ctx, storySlug)
+	if err != nil {
+		return nil, err
+	}
+
+	if story.Status != stories.StoryStatusPublished && story.Status != stories.StoryStatusArchived {
+		return nil, fmt.Errorf("%w(slug: %s)", ErrStoryNotFound, storySlug)
+	}
+
+	comment, err := s.getComment(ctx, story.ID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.Status != StatusVisible {
+		return nil, fmt.Errorf("%w(comment_id: %s)", ErrCommentNotFound, commentID)
+	}
+
+	counts, err := s.reactions.Set(ctx, reactions.TargetKindComment, comment.ID, userID, kind, reacted)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return counts, nil
+}
+
+func (s *Service) getStory(ctx context.Context, slug string) (*stories.AuthoredStory, error) {
+	story, err := s.repo.GetAuthoredStoryBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if story == nil {
+		return nil, fmt.Errorf("%w(slug: %s)", ErrStoryNotFound, slug)
+	}
+
+	return story, nil
+}
+
+func (s *Service) getComment(ctx context.Context, storyID string, id string) (*Comment, error) {
+	comment, err := s.repo.GetStoryComment(ctx, storyID, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w(comment_id: %s): %w", ErrFailedToGetRecord, id, err)
+	}
+
+	if comment == nil {
+		return nil, fmt.Errorf("%w(comment_id: %s)", ErrCommentNotFound, id)
+	}
+
+	return comment, nil
+}
+
+// attachReactions sets the counts of the comments with a single lookup.
+func (s *Service) attachReactions(ctx context.Context, comments ...*Comment) error {
+	ids := make([]string, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+	}
+
+	counts, err := s.reactions.CountsOf(ctx, reactions.TargetKindComment, ids)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	for _, comment := range comments {
+		comment.Reactions = counts[comment.ID]
+	}
+
+	return nil
+}
+
+// isModerator reports whether the user owns the author profile of the story.
+func (s *Service) isModerator(
+	ctx context.Context,
+	story *stories.AuthoredStory,
+	userID string,
+) (bool, error) {
+	if userID == "" {
+		return false, nil
+	}
+
+	owned, err := s.repo.IsProfileOwnedByUser(ctx, story.AuthorProfileID, userID)
+	if err != nil {
+		return false, fmt.Errorf("%w(story_id: %s): %w", ErrFailedToGetRecord, story.ID, err)
+	}
+
+	return owned, nil
+}
+
+func (s *Service) checkModerator(
+	ctx context.Context,
+	story *stories.AuthoredStory,
+	userID string,
+) error {
+	isModerator, err := s.isModerator(ctx, story, userID)
+	if err != nil {
+		return err
+	}
+
+	if !isModerator {
+		return fmt.Errorf("%w(story_id: %s)", ErrNotModerator, story.ID)
+	}
+
+	return nil
+}
+
+// redact clears what the viewer may not see of the comment.
+func redact(comment *Comment, isModerator bool) {
+	if comment.Status == StatusDeleted || (comment.Status == StatusHidden && !isModerator) {
+		comment.Content = ""
+	}
+
+	if !isModerator {
+		comment.ReportCount = nil
+	}
+}
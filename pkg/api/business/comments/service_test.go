@@ -0,0 +1,177 @@
+package comments_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/api/business/comments"
+	"github.com/eser/aya.is-services/pkg/api/business/reactions"
+	"github.com/eser/aya.is-services/pkg/api/business/stories"
+	"github.com/eser/aya.is-services/pkg/lib/cursors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	ownerUserID     = "user-owner"
+	authorProfileID = "profile-author"
+)
+
+// fakeRepository keeps a published story, "hello", with a visible and a hidden comment
+// in memory.
+type fakeRepository struct {
+	comments.Repository
+
+	listed       bool
+	onlyReported bool
+}
+
+func (r *fakeRepository) GetAuthoredStoryBySlug(
+	_ context.Context,
+	slug string,
+) (*stories.AuthoredStory, error) {
+	if slug != "hello" {
+		return nil, nil //nolint:nilnil
+	}
+
+	return &stories.AuthoredStory{
+		ID:                "story-1",
+		Status:            stories.StoryStatusPublished,
+		AuthorProfileID:   authorProfileID,
+		AuthorProfileSlug: "author",
+	}, nil
+}
+
+func (r *fakeRepository) IsProfileOwnedByUser(
+	_ context.Context,
+	profileID string,
+	userID string,
+) (bool, error) {
+	return profileID == authorProfileID && userID == ownerUserID, nil
+}
+
+func (r *fakeRepository) ListStoryComments(
+	_ context.Context,
+	storyID string,
+	_ *string,
+	onlyReported bool,
+	_ *cursors.Cursor,
+) (cursors.Cursored[[]*comments.Comment], error) {
+	r.listed = true
+	r.onlyReported = onlyReported
+
+	reports := int64(2)
+
+	return cursors.WrapResponseWithCursor([]*comments.Comment{
+		{ //nolint:exhaustruct
+			ID:      "comment-1",
+			StoryID: storyID,
+			Content: "Nice story",
+			Status:  comments.StatusVisible,
+		},
+		{ //nolint:exhaustruct
+			ID:          "comment-2",
+			StoryID:     storyID,
+			Content:     "Spam",
+			Status:      comments.StatusHidden,
+			ReportCount: &reports,
+		},
+	}, nil), nil
+}
+
+// fakeReactionsRepository has no reactions.
+type fakeReactionsRepository struct {
+	reactions.Repository
+}
+
+func (r *fakeReactionsRepository) ListReactionCounts(
+	_ context.Context,
+	_ string,
+	_ []string,
+) (map[string][]*reactions.Count, error) {
+	return map[string][]*reactions.Count{}, nil
+}
+
+func newTestService(repo comments.Repository) *comments.Service {
+	logger := logfx.NewLogger(logfx.WithWriter(io.Discard))
+
+	return comments.NewService(
+		logger,
+		repo,
+		reactions.NewService(logger, &fakeReactionsRepository{}), //nolint:exhaustruct
+	)
+}
+
+func TestServiceList(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expectedErr error
+		name        string
+		userID      string
+		reported    bool
+		isModerator bool
+	}{
+		{name: "anonymous", userID: ""},
+		{name: "reader", userID: "user-reader"},
+		{name: "moderator", userID: ownerUserID, isModerator: true},
+		{name: "moderator reported", userID: ownerUserID, reported: true, isModerator: true},
+		{
+			name:        "anonymous reported",
+			userID:      "",
+			reported:    true,
+			expectedErr: comments.ErrNotModerator,
+		},
+		{
+			name:        "reader reported",
+			userID:      "user-reader",
+			reported:    true,
+			expectedErr: comments.ErrNotModerator,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &fakeRepository{} //nolint:exhaustruct
+			service := newTestService(repo)
+
+			cursor := &cursors.Cursor{ //nolint:exhaustruct
+				Filters: map[string]string{},
+				Limit:   10,
+			}
+			if tt.reported {
+				cursor.Filters[comments.FilterReported] = "true"
+			}
+
+			result, err := service.List(t.Context(), tt.userID, "hello", nil, cursor)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				assert.False(t, repo.listed)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.reported, repo.onlyReported)
+			require.Len(t, result.Data, 2)
+
+			visible, hidden := result.Data[0], result.Data[1]
+			assert.Equal(t, "Nice story", visible.Content)
+
+			if tt.isModerator {
+				assert.Equal(t, "Spam", hidden.Content)
+				require.NotNil(t, hidden.ReportCount)
+				assert.Equal(t, int64(2), *hidden.ReportCount)
+
+				return
+			}
+
+			assert.Empty(t, hidden.Content)
+			assert.Nil(t, hidden.ReportCount)
+		})
+	}
+}
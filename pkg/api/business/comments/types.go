@@ -0,0 +1,45 @@
+package comments
+
+import (
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/lib"
+	"github.com/eser/aya.is-services/pkg/api/business/reactions"
+)
+
+type RecordID string
+
+type RecordIDGenerator func() RecordID
+
+func DefaultIDGenerator() RecordID {
+	return RecordID(lib.IDsGenerateUnique())
+}
+
+// Comment is a comment on a story, or a reply to another comment when ParentID is
+// set. The content of hidden comments is shown to the moderators of the story alone,
+// and that of deleted ones to no one.
+type Comment struct {
+	CreatedAt         time.Time  `json:"created_at"`
+	ParentID          *string    `json:"parent_id"`
+	AuthorProfileSlug *string    `json:"author_profile_slug"`
+	UpdatedAt         *time.Time `json:"updated_at"`
+	// ReportCount is the number of open reports, shown to the moderators alone.
+	ReportCount *int64 `json:"report_count,omitempty"`
+	// Reactions are the counts of the reactions to the comment, the most reacted kinds
+	// first.
+	Reactions    []*reactions.Count `json:"reactions"`
+	ID           string             `json:"id"`
+	StoryID      string             `json:"story_id"`
+	AuthorUserID string             `json:"-"`
+	AuthorName   string             `json:"author_name"`
+	Content      string             `json:"content"`
+	Status       string             `json:"status"`
+	ReplyCount   int64              `json:"reply_count"`
+}
+
+// CreateCommentInput is the payload of posting a comment; a comment with ParentID is a
+// reply to that comment.
+type CreateCommentInput struct {
+	ParentID *string `json:"parent_id"`
+	Content  string  `json:"content"`
+}
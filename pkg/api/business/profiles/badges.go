@@ -0,0 +1,192 @@
+package profiles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	// BadgeKindVerified is awarded to profiles served on a verified custom domain.
+	BadgeKindVerified = "verified"
+	// BadgeKindCoreContributor is awarded to individual profiles that own or maintain
+	// a product profile.
+	BadgeKindCoreContributor = "core-contributor"
+	// BadgeKindEventSpeaker is awarded to profiles that spoke at an event.
+	BadgeKindEventSpeaker = "event-speaker"
+
+	BadgeSourceAdmin = "admin"
+	BadgeSourceRule  = "rule"
+)
+
+var (
+	ErrInvalidBadgeKind    = errors.New("invalid badge kind")
+	ErrBadgeAlreadyGranted = errors.New("profile holds the badge already")
+	ErrBadgeNotFound       = errors.New("badge not found")
+)
+
+// BadgeKinds lists the kinds of badges admins grant; each of them has an award rule
+// as well.
+var BadgeKinds = []string{ //nolint:gochecknoglobals
+	BadgeKindVerified,
+	BadgeKindCoreContributor,
+	BadgeKindEventSpeaker,
+}
+
+// ListBadgesBySlug lists the badges the profile holds.
+func (s *Service) ListBadgesBySlug(ctx context.Context, slug string) ([]*ProfileBadge, error) {
+	profileID, err := s.getExistingProfileID(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	badges, err := s.repo.ListProfileBadges(ctx, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToListRecords, slug, err)
+	}
+
+	return badges, nil
+}
+
+// GrantBadge grants a badge of the kind to the profile on behalf of an admin. Award
+// rules do not revoke the badges admins grant.
+func (s *Service) GrantBadge(
+	ctx context.Context,
+	slug string,
+	kind string,
+	note *string,
+) (*ProfileBadge, error) {
+	if !slices.Contains(BadgeKinds, kind) {
+		return nil, fmt.Errorf("%w(kind: %s)", ErrInvalidBadgeKind, kind)
+	}
+
+	profileID, err := s.getExistingProfileID(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	badge := &ProfileBadge{
+		GrantedAt: time.Now(),
+		Note:      note,
+		ID:        string(s.idGenerator()),
+		ProfileID: profileID,
+		Kind:      kind,
+		Source:    BadgeSourceAdmin,
+	}
+
+	granted, err := s.repo.GrantProfileBadge(ctx, badge)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToCreateRecord, slug, err)
+	}
+
+	if !granted {
+		return nil, fmt.Errorf("%w(slug: %s, kind: %s)", ErrBadgeAlreadyGranted, slug, kind)
+	}
+
+	s.invalidateProfile(ctx, slug)
+
+	return badge, nil
+}
+
+// RevokeBadge revokes the badge of the kind from the profile on behalf of an admin.
+// Award rules do not award a badge of the kind to the profile again.
+func (s *Service) RevokeBadge(ctx context.Context, slug string, kind string) error {
+	profileID, err := s.getExistingProfileID(ctx, slug)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := s.repo.RevokeProfileBadge(ctx, profileID, kind, "", BadgeSourceAdmin)
+	if err != nil {
+		return fmt.Errorf("%w(slug: %s): %w", ErrFailedToUpdateRecord, slug, err)
+	}
+
+	if !revoked {
+		return fmt.Errorf("%w(slug: %s, kind: %s)", ErrBadgeNotFound, slug, kind)
+	}
+
+	s.invalidateProfile(ctx, slug)
+
+	return nil
+}
+
+// AwardBadges evaluates the award rule of each badge kind: profiles the rule holds
+// for are awarded the badge, and the badges the rule awarded before are revoked from
+// profiles it no longer holds for. A failed rule is logged and the others still run.
+func (s *Service) AwardBadges(ctx context.Context) error {
+	var errs []error
+
+	for _, kind := range BadgeKinds {
+		if ctx.Err() != nil {
+			return ctx.Err() //nolint:wrapcheck
+		}
+
+		err := s.awardBadgesOfKind(ctx, kind)
+		if err != nil {
+			s.logger.WarnContext(ctx, "failed to award badges", "kind", kind, "error", err)
+
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *Service) awardBadgesOfKind(ctx context.Context, kind string) error {
+	matches, err := s.repo.ListBadgeRuleMatches(ctx, kind)
+	if err != nil {
+		return fmt.Errorf("%w(kind: %s): %w", ErrFailedToListRecords, kind, err)
+	}
+
+	holders, err := s.repo.ListBadgeHolders(ctx, kind, BadgeSourceRule)
+	if err != nil {
+		return fmt.Errorf("%w(kind: %s): %w", ErrFailedToListRecords, kind, err)
+	}
+
+	matched := make(map[string]struct{}, len(matches))
+
+	for _, match := range matches {
+		matched[match.ProfileID] = struct{}{}
+
+		awarded, err := s.repo.AwardProfileBadge(
+			ctx,
+			string(s.idGenerator()),
+			match.ProfileID,
+			kind,
+		)
+		if err != nil {
+			return fmt.Errorf("%w(slug: %s, kind: %s): %w", ErrFailedToCreateRecord, match.ProfileSlug, kind, err)
+		}
+
+		if awarded {
+			s.logger.InfoContext(ctx, "badge awarded", "slug", match.ProfileSlug, "kind", kind)
+			s.invalidateProfile(ctx, match.ProfileSlug)
+		}
+	}
+
+	for _, holder := range holders {
+		if _, ok := matched[holder.ProfileID]; ok {
+			continue
+		}
+
+		revoked, err := s.repo.RevokeProfileBadge(
+			ctx,
+			holder.ProfileID,
+			kind,
+			BadgeSourceRule,
+			BadgeSourceRule,
+		)
+		if err != nil {
+			return fmt.Errorf("%w(slug: %s, kind: %s): %w", ErrFailedToUpdateRecord, holder.ProfileSlug, kind, err)
+		}
+
+		if revoked {
+			s.logger.InfoContext(ctx, "badge revoked", "slug", holder.ProfileSlug, "kind", kind)
+			s.invalidateProfile(ctx, holder.ProfileSlug)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package profiles_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storedBadge is a badge stored through the repository; revokedBy is set once it is
+// revoked.
+type storedBadge struct {
+	profiles.ProfileBadge
+
+	revokedBy string
+}
+
+// matchRule makes the award rule of the kind hold for "acme".
+func (r *fakeRepository) matchRule(kind string) {
+	r.ruleMatches[kind] = []*profiles.BadgeHolder{{ProfileID: profileID, ProfileSlug: "acme"}}
+}
+
+// activeBadges lists the badges that are not revoked.
+func (r *fakeRepository) activeBadges() []*storedBadge {
+	active := []*storedBadge{}
+
+	for _, badge := range r.badges {
+		if badge.revokedBy == "" {
+			active = append(active, badge)
+		}
+	}
+
+	return active
+}
+
+func (r *fakeRepository) findActiveBadge(profileID string, kind string) *storedBadge {
+	for _, badge := range r.activeBadges() {
+		if badge.ProfileID == profileID && badge.Kind == kind {
+			return badge
+		}
+	}
+
+	return nil
+}
+
+func (r *fakeRepository) GrantProfileBadge(
+	_ context.Context,
+	badge *profiles.ProfileBadge,
+) (bool, error) {
+	if r.findActiveBadge(badge.ProfileID, badge.Kind) != nil {
+		return false, nil
+	}
+
+	r.badges = append(r.badges, &storedBadge{ProfileBadge: *badge}) //nolint:exhaustruct
+
+	return true, nil
+}
+
+func (r *fakeRepository) AwardProfileBadge(
+	ctx context.Context,
+	id string,
+	profileID string,
+	kind string,
+) (bool, error) {
+	for _, badge := range r.badges {
+		if badge.ProfileID == profileID && badge.Kind == kind &&
+			badge.revokedBy == profiles.BadgeSourceAdmin {
+			return false, nil
+		}
+	}
+
+	return r.GrantProfileBadge(ctx, &profiles.ProfileBadge{ //nolint:exhaustruct
+		ID:        id,
+		ProfileID: profileID,
+		Kind:      kind,
+		Source:    profiles.BadgeSourceRule,
+	})
+}
+
+func (r *fakeRepository) RevokeProfileBadge(
+	_ context.Context,
+	profileID string,
+	kind string,
+	source string,
+	revokedBy string,
+) (bool, error) {
+	badge := r.findActiveBadge(profileID, kind)
+	if badge == nil || (source != "" && badge.Source != source) {
+		return false, nil
+	}
+
+	badge.revokedBy = revokedBy
+
+	return true, nil
+}
+
+func (r *fakeRepository) ListBadgeHolders(
+	_ context.Context,
+	kind string,
+	source string,
+) ([]*profiles.BadgeHolder, error) {
+	holders := []*profiles.BadgeHolder{}
+
+	for _, badge := range r.activeBadges() {
+		if badge.Kind == kind && badge.Source == source {
+			holders = append(holders, &profiles.BadgeHolder{
+				ProfileID:   badge.ProfileID,
+				ProfileSlug: "acme",
+			})
+		}
+	}
+
+	return holders, nil
+}
+
+func (r *fakeRepository) ListBadgeRuleMatches(
+	_ context.Context,
+	kind string,
+) ([]*profiles.BadgeHolder, error) {
+	return r.ruleMatches[kind], nil
+}
+
+func TestServiceGrantBadge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expectedErr error
+		name        string
+		slug        string
+		kind        string
+	}{
+		{name: "should grant a badge", slug: "acme", kind: profiles.BadgeKindVerified},
+		{
+			name:        "should reject unknown kinds",
+			slug:        "acme",
+			kind:        "founder",
+			expectedErr: profiles.ErrInvalidBadgeKind,
+		},
+		{
+			name:        "should report unknown profiles",
+			slug:        "missing",
+			kind:        profiles.BadgeKindVerified,
+			expectedErr: profiles.ErrProfileNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newFakeRepository()
+			service := newTestService(repo)
+
+			badge, err := service.GrantBadge(t.Context(), tt.slug, tt.kind, nil)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				assert.Empty(t, repo.badges)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, profileID, badge.ProfileID)
+			assert.Equal(t, profiles.BadgeSourceAdmin, badge.Source)
+			require.Len(t, repo.badges, 1)
+		})
+	}
+
+	t.Run("should not grant a badge twice", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newFakeRepository()
+		service := newTestService(repo)
+
+		_, err := service.GrantBadge(t.Context(), "acme", profiles.BadgeKindVerified, nil)
+		require.NoError(t, err)
+
+		_, err = service.GrantBadge(t.Context(), "acme", profiles.BadgeKindVerified, nil)
+		require.ErrorIs(t, err, profiles.ErrBadgeAlreadyGranted)
+		assert.Len(t, repo.badges, 1)
+	})
+}
+
+func TestServiceRevokeBadge(t *testing.T) {
+	t.Parallel()
+
+	repo := newFakeRepository()
+	service := newTestService(repo)
+
+	_, err := service.GrantBadge(t.Context(), "acme", profiles.BadgeKindEventSpeaker, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, service.RevokeBadge(t.Context(), "acme", profiles.BadgeKindEventSpeaker))
+	assert.Empty(t, repo.activeBadges())
+
+	err = service.RevokeBadge(t.Context(), "acme", profiles.BadgeKindEventSpeaker)
+	require.ErrorIs(t, err, profiles.ErrBadgeNotFound)
+}
+
+func TestServiceAwardBadges(t *testing.T) {
+	t.Parallel()
+
+	for _, kind := range profiles.BadgeKinds {
+		t.Run(kind, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("should award a badge once to matching profiles", func(t *testing.T) {
+				t.Parallel()
+
+				repo := newFakeRepository()
+				repo.matchRule(kind)
+				service := newTestService(repo)
+
+				require.NoError(t, service.AwardBadges(t.Context()))
+				require.NoError(t, service.AwardBadges(t.Context()))
+
+				require.Len(t, repo.badges, 1)
+				assert.Equal(t, kind, repo.badges[0].Kind)
+				assert.Equal(t, profiles.BadgeSourceRule, repo.badges[0].Source)
+			})
+
+			t.Run("should revoke the badge when the rule stops matching", func(t *testing.T) {
+				t.Parallel()
+
+				repo := newFakeRepository()
+				repo.matchRule(kind)
+				service := newTestService(repo)
+
+				require.NoError(t, service.AwardBadges(t.Context()))
+
+				delete(repo.ruleMatches, kind)
+
+				require.NoError(t, service.AwardBadges(t.Context()))
+				assert.Empty(t, repo.activeBadges())
+				assert.Equal(t, profiles.BadgeSourceRule, repo.badges[0].revokedBy)
+
+				// A badge the rule revoked is awarded again once the rule matches.
+				repo.matchRule(kind)
+
+				require.NoError(t, service.AwardBadges(t.Context()))
+				assert.Len(t, repo.activeBadges(), 1)
+			})
+
+			t.Run("should keep the badges admins grant", func(t *testing.T) {
+				t.Parallel()
+
+				repo := newFakeRepository()
+				service := newTestService(repo)
+
+				_, err := service.GrantBadge(t.Context(), "acme", kind, nil)
+				require.NoError(t, err)
+
+				require.NoError(t, service.AwardBadges(t.Context()))
+				require.Len(t, repo.activeBadges(), 1)
+				assert.Equal(t, profiles.BadgeSourceAdmin, repo.activeBadges()[0].Source)
+			})
+
+			t.Run("should not award a badge an admin revoked", func(t *testing.T) {
+				t.Parallel()
+
+				repo := newFakeRepository()
+				repo.matchRule(kind)
+				service := newTestService(repo)
+
+				require.NoError(t, service.AwardBadges(t.Context()))
+				require.NoError(t, service.RevokeBadge(t.Context(), "acme", kind))
+
+				require.NoError(t, service.AwardBadges(t.Context()))
+				assert.Empty(t, repo.activeBadges())
+				assert.Len(t, repo.badges, 1)
+			})
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package profiles
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	CustomDomainStatusPending    = "pending"
+	CustomDomainStatusVerified   = "verified"
+	CustomDomainStatusFailed     = "failed"
+	CustomDomainStatusExpired    = "expired"
+	CustomDomainStatusSuperseded = "superseded"
+
+	// CustomDomainClaimTTL is how long a claim waits for its DNS challenge.
+	CustomDomainClaimTTL = 72 * time.Hour
+
+	// CustomDomainChallengePrefix is prepended to the claimed domain to name the TXT
+	// record of the challenge.
+	CustomDomainChallengePrefix = "_aya-challenge."
+	// CustomDomainChallengeValuePrefix is prepended to the token of a claim to form the
+	// value of the TXT record.
+	CustomDomainChallengeValuePrefix = "aya-domain-verification="
+
+	maxCustomDomainLength       = 253
+	challengeTokenRandomBytes   = 16
+	customDomainMismatchMessage = "challenge record not found"
+)
+
+var (
+	ErrInvalidCustomDomain       = errors.New("invalid custom domain")
+	ErrCustomDomainTaken         = errors.New("custom domain is used by another profile")
+	ErrCustomDomainClaimNotFound = errors.New("custom domain claim not found")
+)
+
+var customDomainPattern = regexp.MustCompile( //nolint:gochecknoglobals
+	`^(?:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z][a-z0-9-]{0,61}[a-z0-9]$`,
+)
+
+// TXTResolver looks the TXT records of a name up; net.DefaultResolver satisfies it.
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// ChallengeRecordName is the name of the TXT record that proves control of the domain.
+func (c *CustomDomainClaim) ChallengeRecordName() string {
+	return CustomDomainChallengePrefix + c.Domain
+}
+
+// ChallengeRecordValue is the value of the TXT record that proves control of the domain.
+func (c *CustomDomainClaim) ChallengeRecordValue() string {
+	return CustomDomainChallengeValuePrefix + c.ChallengeToken
+}
+
+// ClaimCustomDomain starts serving the profile the user owns on domain. The domain is
+// set on the profile only once VerifyPendingCustomDomains finds the challenge record;
+// a previous pending claim of the profile is superseded.
+func (s *Service) ClaimCustomDomain(
+	ctx context.Context,
+	userID string,
+	slug string,
+	domain string,
+) (*CustomDomainClaim, error) {
+	domain, err := normalizeCustomDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	profileID, err := s.getOwnedProfileID(ctx, userID, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	currentProfileID, err := s.repo.GetProfileIDByCustomDomain(ctx, domain)
+	if err != nil {
+		return nil, fmt.Errorf("%w(custom_domain: %s): %w", ErrFailedToGetRecord, domain, err)
+	}
+
+	if currentProfileID != nil && *currentProfileID != profileID {
+		return nil, fmt.Errorf("%w(custom_domain: %s)", ErrCustomDomainTaken, domain)
+	}
+
+	token, err := generateChallengeToken()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToCreateRecord, err)
+	}
+
+	now := time.Now()
+	claim := &CustomDomainClaim{ //nolint:exhaustruct
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(CustomDomainClaimTTL),
+		ID:             string(s.idGenerator()),
+		ProfileID:      profileID,
+		ProfileSlug:    slug,
+		Domain:         domain,
+		ChallengeToken: token,
+		Status:         CustomDomainStatusPending,
+	}
+
+	err = s.repo.CreateCustomDomainClaim(ctx, claim)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToCreateRecord, slug, err)
+	}
+
+	return claim, nil
+}
+
+// GetCustomDomainClaim returns the latest custom domain claim of the profile the user
+// owns.
+func (s *Service) GetCustomDomainClaim(
+	ctx context.Context,
+	userID string,
+	slug string,
+) (*CustomDomainClaim, error) {
+	profileID, err := s.getOwnedProfileID(ctx, userID, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	claim, err := s.repo.GetLatestCustomDomainClaim(ctx, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if claim == nil {
+		return nil, fmt.Errorf("%w(slug: %s)", ErrCustomDomainClaimNotFound, slug)
+	}
+
+	claim.ProfileSlug = slug
+
+	return claim, nil
+}
+
+// IsCustomDomainServed tells whether domain is the verified custom domain of a
+// profile, e.g. for a TLS terminator that issues certificates on demand.
+func (s *Service) IsCustomDomainServed(ctx context.Context, domain string) (bool, error) {
+	domain, err := normalizeCustomDomain(domain)
+	if err != nil {
+		return false, nil //nolint:nilerr
+	}
+
+	profileID, err := s.repo.GetProfileIDByCustomDomain(ctx, domain)
+	if err != nil {
+		return false, fmt.Errorf("%w(custom_domain: %s): %w", ErrFailedToGetRecord, domain, err)
+	}
+
+	return profileID != nil, nil
+}
+
+// VerifyPendingCustomDomains checks the DNS challenges of up to limit pending claims,
+// the least recently checked first. A failed check of a claim is stored on it, so only
+// failing to list the claims is returned.
+func (s *Service) VerifyPendingCustomDomains(ctx context.Context, limit int) error {
+	claims, err := s.repo.ListPendingCustomDomainClaims(ctx, limit)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToListRecords, err)
+	}
+
+	for _, claim := range claims {
+		if ctx.Err() != nil {
+			return ctx.Err() //nolint:wrapcheck
+		}
+
+		s.verifyCustomDomainClaim(ctx, claim)
+	}
+
+	return nil
+}
+
+func (s *Service) verifyCustomDomainClaim(ctx context.Context, claim *CustomDomainClaim) {
+	if time.Now().After(claim.ExpiresAt) {
+		s.recordCustomDomainClaimCheck(
+			ctx,
+			claim,
+			CustomDomainStatusExpired,
+			"challenge record was not found before the claim expired",
+		)
+
+		return
+	}
+
+	records, err := s.txtResolver.LookupTXT(ctx, claim.ChallengeRecordName())
+	if err != nil {
+		s.recordCustomDomainClaimCheck(ctx, claim, CustomDomainStatusPending, err.Error())
+
+		return
+	}
+
+	if !slices.Contains(records, claim.ChallengeRecordValue()) {
+		s.recordCustomDomainClaimCheck(
+			ctx,
+			claim,
+			CustomDomainStatusPending,
+			customDomainMismatchMessage,
+		)
+
+		return
+	}
+
+	verified, err := s.repo.VerifyCustomDomainClaim(ctx, claim)
+	if err != nil {
+		if errors.Is(err, ErrCustomDomainTaken) {
+			s.recordCustomDomainClaimCheck(ctx, claim, CustomDomainStatusFailed, err.Error())
+
+			return
+		}
+
+		s.logger.WarnContext(
+			ctx,
+			"failed to verify custom domain claim",
+			"slug", claim.ProfileSlug,
+			"custom_domain", claim.Domain,
+			"error", err,
+		)
+
+		return
+	}
+
+	if !verified {
+		return
+	}
+
+	s.logger.InfoContext(
+		ctx,
+		"custom domain verified",
+		"slug", claim.ProfileSlug,
+		"custom_domain", claim.Domain,
+	)
+
+	s.invalidateProfile(ctx, claim.ProfileSlug)
+}
+
+func (s *Service) recordCustomDomainClaimCheck(
+	ctx context.Context,
+	claim *CustomDomainClaim,
+	status string,
+	lastError string,
+) {
+	_, err := s.repo.RecordCustomDomainClaimCheck(ctx, claim.ID, status, lastError)
+	if err != nil {
+		s.logger.WarnContext(
+			ctx,
+			"failed to record custom domain check",
+			"slug", claim.ProfileSlug,
+			"custom_domain", claim.Domain,
+			"error", err,
+		)
+	}
+}
+
+// normalizeCustomDomain lowercases domain and drops its trailing dot.
+func normalizeCustomDomain(domain string) (string, error) {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+
+	if len(domain) > maxCustomDomainLength || !customDomainPattern.MatchString(domain) {
+		return "", fmt.Errorf("%w(custom_domain: %s)", ErrInvalidCustomDomain, domain)
+	}
+
+	return domain, nil
+}
+
+func generateChallengeToken() (string, error) {
+	random := make([]byte, challengeTokenRandomBytes)
+	if _, err := rand.Read(random); err != nil {
+		return "", err //nolint:wrapcheck
+	}
+
+	return hex.EncodeToString(random), nil
+}
@@ -0,0 +1,157 @@
+package profiles_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (r *fakeRepository) GetProfileIDByCustomDomain(
+	_ context.Context,
+	domain string,
+) (*string, error) {
+	id, ok := r.domains[domain]
+	if !ok {
+		return nil, nil //nolint:nilnil
+	}
+
+	return &id, nil
+}
+
+func (r *fakeRepository) CreateCustomDomainClaim(
+	_ context.Context,
+	claim *profiles.CustomDomainClaim,
+) error {
+	r.claims = append(r.claims, claim)
+
+	return nil
+}
+
+func (r *fakeRepository) ListPendingCustomDomainClaims(
+	_ context.Context,
+	_ int,
+) ([]*profiles.CustomDomainClaim, error) {
+	return r.pending, nil
+}
+
+func (r *fakeRepository) RecordCustomDomainClaimCheck(
+	_ context.Context,
+	id string,
+	status string,
+	_ string,
+) (int64, error) {
+	r.checks[id] = status
+
+	return 1, nil
+}
+
+func TestServiceClaimCustomDomain(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		domain  string
+		want    string
+		wantErr error
+	}{
+		{name: "new domain", domain: "blog.example.com", want: "blog.example.com"},
+		{name: "normalized", domain: " Blog.Example.COM. ", want: "blog.example.com"},
+		{name: "own domain", domain: "acme.example.com", want: "acme.example.com"},
+		{
+			name:    "domain of another profile",
+			domain:  "Other.Example.com",
+			wantErr: profiles.ErrCustomDomainTaken,
+		},
+		{name: "no dot", domain: "localhost", wantErr: profiles.ErrInvalidCustomDomain},
+		{name: "underscore", domain: "bad_domain.com", wantErr: profiles.ErrInvalidCustomDomain},
+		{name: "numeric tld", domain: "example.123", wantErr: profiles.ErrInvalidCustomDomain},
+		{name: "ip address", domain: "10.0.0.1", wantErr: profiles.ErrInvalidCustomDomain},
+		{
+			name:    "too long",
+			domain:  strings.Repeat("a.", 126) + "com",
+			wantErr: profiles.ErrInvalidCustomDomain,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newFakeRepository()
+			service := newTestService(repo)
+
+			claim, err := service.ClaimCustomDomain(
+				t.Context(),
+				ownerUserID,
+				"acme",
+				tt.domain,
+			)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, repo.claims)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, claim.Domain)
+			assert.Equal(t, profiles.CustomDomainStatusPending, claim.Status)
+			require.Len(t, repo.claims, 1)
+			assert.Equal(t, tt.want, repo.claims[0].Domain)
+		})
+	}
+}
+
+func TestServiceIsCustomDomainServed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		domain string
+		want   bool
+	}{
+		{name: "served", domain: "acme.example.com", want: true},
+		{name: "normalized", domain: "ACME.example.com.", want: true},
+		{name: "not served", domain: "blog.example.com", want: false},
+		{name: "invalid", domain: "localhost", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := newTestService(newFakeRepository())
+
+			served, err := service.IsCustomDomainServed(t.Context(), tt.domain)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, served)
+		})
+	}
+}
+
+func TestServiceVerifyPendingCustomDomainsExpired(t *testing.T) {
+	t.Parallel()
+
+	repo := newFakeRepository()
+	repo.pending = []*profiles.CustomDomainClaim{
+		{ //nolint:exhaustruct
+			ExpiresAt:   time.Now().Add(-time.Minute),
+			ID:          "claim-1",
+			ProfileID:   profileID,
+			ProfileSlug: "acme",
+			Domain:      "blog.example.com",
+			Status:      profiles.CustomDomainStatusPending,
+		},
+	}
+
+	// An expired claim is closed before its challenge record is looked up, so the test
+	// never reaches the resolver.
+	err := newTestService(repo).VerifyPendingCustomDomains(t.Context(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"claim-1": profiles.CustomDomainStatusExpired}, repo.checks)
+}
@@ -0,0 +1,417 @@
+package profiles
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ExportStatusPending   = "pending"
+	ExportStatusRunning   = "running"
+	ExportStatusCompleted = "completed"
+	ExportStatusFailed    = "failed"
+	ExportStatusExpired   = "expired"
+
+	// MaxExportAttempts is how many times an export is tried before it fails.
+	MaxExportAttempts = 3
+	// ExportStaleAfter is how long a running export may take before another run takes
+	// it over, e.g. after the instance that ran it stopped.
+	ExportStaleAfter = 30 * time.Minute
+
+	exportObjectKeyPrefix = "profile-exports/"
+	exportDataFileName    = "profile.json"
+)
+
+var (
+	ErrExportsNotEnabled = errors.New("profile exports are not enabled")
+	ErrExportNotFound    = errors.New("profile export not found")
+	ErrExportNotReady    = errors.New("profile export is not ready")
+	ErrInvalidExportLink = errors.New("invalid or expired export link")
+)
+
+// ExportStorage keeps the archives of profile exports.
+type ExportStorage interface {
+	// PutArchive stores the archive that write writes, and returns its size.
+	PutArchive(ctx context.Context, key string, write func(w io.Writer) error) (int64, error)
+	// OpenArchive opens an archive for reading; the caller closes it.
+	OpenArchive(ctx context.Context, key string) (io.ReadCloser, error)
+	DeleteArchive(ctx context.Context, key string) error
+}
+
+type ExportSettings struct {
+	// LinkSecret signs the download links of the archives.
+	LinkSecret string
+	// LinkTTL is how long a download link works.
+	LinkTTL time.Duration
+	// Retention is how long an archive is kept once it is ready.
+	Retention time.Duration
+}
+
+// SetExportStorage enables the exports of profiles, whose archives storage keeps.
+func (s *Service) SetExportStorage(storage ExportStorage, settings ExportSettings) {
+	s.exportStorage = storage
+	s.exportSettings = settings
+}
+
+// RequestExport queues an export of the data of the profile the user owns, or returns
+// the export of the profile that is queued or running already.
+func (s *Service) RequestExport(
+	ctx context.Context,
+	userID string,
+	slug string,
+) (*ProfileExport, error) {
+	if s.exportStorage == nil {
+		return nil, ErrExportsNotEnabled
+	}
+
+	profileID, err := s.getOwnedProfileID(ctx, userID, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	export, err := s.repo.GetActiveProfileExport(ctx, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if export == nil {
+		export = &ProfileExport{ //nolint:exhaustruct
+			CreatedAt: time.Now(),
+			ID:        string(s.idGenerator()),
+			ProfileID: profileID,
+			Status:    ExportStatusPending,
+		}
+
+		err = s.repo.CreateProfileExport(ctx, export, userID)
+		if err != nil {
+			return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToCreateRecord, slug, err)
+		}
+	}
+
+	export.ProfileSlug = slug
+
+	return export, nil
+}
+
+// GetExport returns an export of the profile the user owns.
+func (s *Service) GetExport(
+	ctx context.Context,
+	userID string,
+	slug string,
+	id string,
+) (*ProfileExport, error) {
+	profileID, err := s.getOwnedProfileID(ctx, userID, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	export, err := s.repo.GetProfileExport(ctx, id, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("%w(id: %s): %w", ErrFailedToGetRecord, id, err)
+	}
+
+	if export == nil {
+		return nil, fmt.Errorf("%w(id: %s)", ErrExportNotFound, id)
+	}
+
+	export.ProfileSlug = slug
+
+	return export, nil
+}
+
+// SignExportDownload signs a download link of a completed export, which works until
+// the returned time.
+func (s *Service) SignExportDownload(export *ProfileExport) (time.Time, string) {
+	expiresAt := time.Now().Add(s.exportSettings.LinkTTL).Truncate(time.Second)
+	if export.ExpiresAt != nil && export.ExpiresAt.Before(expiresAt) {
+		expiresAt = export.ExpiresAt.Truncate(time.Second)
+	}
+
+	return expiresAt, s.signExportDownload(export.ID, expiresAt.Unix())
+}
+
+// OpenExportArchive opens the archive of an export of the profile with slug for a
+// download link signed by SignExportDownload. The caller closes it.
+func (s *Service) OpenExportArchive(
+	ctx context.Context,
+	slug string,
+	id string,
+	expires int64,
+	signature string,
+) (io.ReadCloser, *ProfileExport, error) {
+	if s.exportStorage == nil {
+		return nil, nil, ErrExportsNotEnabled
+	}
+
+	expected := s.signExportDownload(id, expires)
+	if !hmac.Equal([]byte(signature), []byte(expected)) || time.Now().Unix() > expires {
+		return nil, nil, fmt.Errorf("%w(id: %s)", ErrInvalidExportLink, id)
+	}
+
+	profileID, err := s.getExistingProfileID(ctx, slug)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	export, err := s.repo.GetProfileExport(ctx, id, profileID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w(id: %s): %w", ErrFailedToGetRecord, id, err)
+	}
+
+	if export == nil {
+		return nil, nil, fmt.Errorf("%w(id: %s)", ErrExportNotFound, id)
+	}
+
+	if export.Status != ExportStatusCompleted || export.ObjectKey == nil {
+		return nil, nil, fmt.Errorf("%w(id: %s, status: %s)", ErrExportNotReady, id, export.Status)
+	}
+
+	archive, err := s.exportStorage.OpenArchive(ctx, *export.ObjectKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w(id: %s): %w", ErrFailedToGetRecord, id, err)
+	}
+
+	export.ProfileSlug = slug
+
+	return archive, export, nil
+}
+
+// RunPendingExports archives up to limit queued exports, the oldest first, and drops
+// the archives that expired. A failed export is stored on it, and tried again until
+// MaxExportAttempts, so only failing to list the exports is returned.
+func (s *Service) RunPendingExports(ctx context.Context, limit int) error {
+	if s.exportStorage == nil {
+		return nil
+	}
+
+	s.expireExports(ctx, limit)
+
+	exports, err := s.repo.ClaimProfileExports(ctx, time.Now().Add(-ExportStaleAfter), limit)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToListRecords, err)
+	}
+
+	for _, export := range exports {
+		if ctx.Err() != nil {
+			return ctx.Err() //nolint:wrapcheck
+		}
+
+		s.runExport(ctx, export)
+	}
+
+	return nil
+}
+
+func (s *Service) runExport(ctx context.Context, export *ProfileExport) {
+	data, err := s.repo.GetProfileExportData(ctx, export.ProfileID)
+	if err != nil {
+		s.recordExportFailure(ctx, export, err)
+
+		return
+	}
+
+	if data == nil {
+		export.Attempts = MaxExportAttempts // a deleted profile is not tried again
+		s.recordExportFailure(
+			ctx,
+			export,
+			fmt.Errorf("%w(id: %s)", ErrProfileNotFound, export.ProfileID),
+		)
+
+		return
+	}
+
+	key := exportObjectKeyPrefix + export.ProfileID + "/" + export.ID + ".zip"
+
+	size, err := s.exportStorage.PutArchive(ctx, key, func(w io.Writer) error {
+		return writeExportArchive(w, data)
+	})
+	if err != nil {
+		s.recordExportFailure(ctx, export, err)
+
+		return
+	}
+
+	_, err = s.repo.CompleteProfileExport(
+		ctx,
+		export.ID,
+		key,
+		size,
+		time.Now().Add(s.exportSettings.Retention),
+	)
+	if err != nil {
+		s.recordExportFailure(ctx, export, err)
+
+		return
+	}
+
+	s.logger.InfoContext(
+		ctx,
+		"profile export completed",
+		"profile_id", export.ProfileID,
+		"export_id", export.ID,
+		"size_bytes", size,
+	)
+}
+
+func (s *Service) recordExportFailure(ctx context.Context, export *ProfileExport, cause error) {
+	status := ExportStatusPending
+	if export.Attempts >= MaxExportAttempts {
+		status = ExportStatusFailed
+	}
+
+	s.logger.WarnContext(
+		ctx,
+		"profile export failed",
+		"profile_id", export.ProfileID,
+		"export_id", export.ID,
+		"status", status,
+		"error", cause,
+	)
+
+	_, err := s.repo.RecordProfileExportFailure(ctx, export.ID, status, cause.Error())
+	if err != nil {
+		s.logger.WarnContext(
+			ctx,
+			"failed to record profile export failure",
+			"export_id", export.ID,
+			"error", err,
+		)
+	}
+}
+
+func (s *Service) expireExports(ctx context.Context, limit int) {
+	exports, err := s.repo.ListExpiredProfileExports(ctx, limit)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to list expired profile exports", "error", err)
+
+		return
+	}
+
+	for _, export := range exports {
+		if export.ObjectKey != nil {
+			err = s.exportStorage.DeleteArchive(ctx, *export.ObjectKey)
+			if err != nil {
+				s.logger.WarnContext(
+					ctx,
+					"failed to delete profile export archive",
+					"export_id", export.ID,
+					"error", err,
+				)
+
+				continue
+			}
+		}
+
+		_, err = s.repo.ExpireProfileExport(ctx, export.ID)
+		if err != nil {
+			s.logger.WarnContext(
+				ctx,
+				"failed to expire profile export",
+				"export_id", export.ID,
+				"error", err,
+			)
+		}
+	}
+}
+
+func (s *Service) signExportDownload(id string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(s.exportSettings.LinkSecret))
+	mac.Write([]byte(id + "|" + strconv.FormatInt(expires, 10)))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// writeExportArchive writes profile.json with all of data, and the content of each
+// page and story as Markdown, in pages/<slug>/<locale>.md and stories/<slug>/<locale>.md.
+func writeExportArchive(w io.Writer, data *ProfileExportData) error {
+	archive := zip.NewWriter(w)
+
+	encoded, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	err = writeArchiveFile(archive, exportDataFileName, encoded, data.ExportedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, page := range data.Pages {
+		err = writeArchiveMarkdown(archive, "pages", page.Slug, page.Translations, data.ExportedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, story := range data.Stories {
+		err = writeArchiveMarkdown(
+			archive,
+			"stories",
+			story.Slug,
+			story.Translations,
+			data.ExportedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return archive.Close() //nolint:wrapcheck
+}
+
+func writeArchiveMarkdown(
+	archive *zip.Writer,
+	dir string,
+	slug string,
+	translations []*ExportedTranslation,
+	modified time.Time,
+) error {
+	for _, translation := range translations {
+		var content strings.Builder
+
+		content.WriteString("# " + translation.Title + "\n\n")
+
+		if translation.Summary != "" {
+			content.WriteString(translation.Summary + "\n\n")
+		}
+
+		content.WriteString(translation.Content + "\n")
+
+		name := dir + "/" + url.PathEscape(slug) + "/" +
+			url.PathEscape(translation.LocaleCode) + ".md"
+
+		err := writeArchiveFile(archive, name, []byte(content.String()), modified)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeArchiveFile(archive *zip.Writer, name string, content []byte, modified time.Time) error {
+	file, err := archive.CreateHeader(&zip.FileHeader{ //nolint:exhaustruct
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	_, err = file.Write(content)
+
+	return err //nolint:wrapcheck
+}
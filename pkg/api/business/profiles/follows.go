@@ -0,0 +1,139 @@
+package profiles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/eser/aya.is-services/pkg/lib/cursors"
+)
+
+var ErrCannotFollowSelf = errors.New("a profile cannot follow itself")
+
+// Follow makes the individual profile of the user follow the profile. Following a
+// profile again is not an error.
+func (s *Service) Follow(ctx context.Context, userID string, slug string) error {
+	followerProfileID, followedProfileID, err := s.getFollowProfileIDs(ctx, userID, slug)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.repo.FollowProfile(
+		ctx,
+		string(s.idGenerator()),
+		followerProfileID,
+		followedProfileID,
+	)
+	if err != nil {
+		return fmt.Errorf("%w(slug: %s): %w", ErrFailedToCreateRecord, slug, err)
+	}
+
+	return nil
+}
+
+// Unfollow makes the individual profile of the user stop following the profile.
+// Unfollowing a profile that is not followed is not an error.
+func (s *Service) Unfollow(ctx context.Context, userID string, slug string) error {
+	followerProfileID, followedProfileID, err := s.getFollowProfileIDs(ctx, userID, slug)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.repo.UnfollowProfile(ctx, followerProfileID, followedProfileID)
+	if err != nil {
+		return fmt.Errorf("%w(slug: %s): %w", ErrFailedToDeleteRecord, slug, err)
+	}
+
+	return nil
+}
+
+// ListFollowersBySlug lists the profiles that follow the profile, the latest first.
+func (s *Service) ListFollowersBySlug(
+	ctx context.Context,
+	localeCode string,
+	slug string,
+	cursor *cursors.Cursor,
+) (cursors.Cursored[[]*ProfileFollow], error) {
+	profileID, err := s.getExistingProfileID(ctx, slug)
+	if err != nil {
+		return cursors.Cursored[[]*ProfileFollow]{}, err
+	}
+
+	records, err := s.repo.ListProfileFollowers(ctx, localeCode, profileID, cursor)
+	if err != nil {
+		return cursors.Cursored[[]*ProfileFollow]{}, fmt.Errorf(
+			"%w(slug: %s): %w",
+			ErrFailedToListRecords,
+			slug,
+			err,
+		)
+	}
+
+	return records, nil
+}
+
+// ListFollowingBySlug lists the profiles the profile follows, the latest first.
+func (s *Service) ListFollowingBySlug(
+	ctx context.Context,
+	localeCode string,
+	slug string,
+	cursor *cursors.Cursor,
+) (cursors.Cursored[[]*ProfileFollow], error) {
+	profileID, err := s.getExistingProfileID(ctx, slug)
+	if err != nil {
+		return cursors.Cursored[[]*ProfileFollow]{}, err
+	}
+
+	records, err := s.repo.ListProfileFollowing(ctx, localeCode, profileID, cursor)
+	if err != nil {
+		return cursors.Cursored[[]*ProfileFollow]{}, fmt.Errorf(
+			"%w(slug: %s): %w",
+			ErrFailedToListRecords,
+			slug,
+			err,
+		)
+	}
+
+	return records, nil
+}
+
+// getFollowProfileIDs resolves the individual profile of the user, which follows, and
+// the profile it follows.
+func (s *Service) getFollowProfileIDs(
+	ctx context.Context,
+	userID string,
+	slug string,
+) (string, string, error) {
+	followerProfileID, err := s.repo.GetUserIndividualProfileID(ctx, userID)
+	if err != nil {
+		return "", "", fmt.Errorf("%w(user_id: %s): %w", ErrFailedToGetRecord, userID, err)
+	}
+
+	if followerProfileID == "" {
+		return "", "", fmt.Errorf("%w(user_id: %s)", ErrIndividualProfileRequired, userID)
+	}
+
+	followedProfileID, err := s.getExistingProfileID(ctx, slug)
+	if err != nil {
+		return "", "", err
+	}
+
+	if followedProfileID == followerProfileID {
+		return "", "", fmt.Errorf("%w(slug: %s)", ErrCannotFollowSelf, slug)
+	}
+
+	return followerProfileID, followedProfileID, nil
+}
+
+func (s *Service) getExistingProfileID(ctx context.Context, slug string) (string, error) {
+	profileID, err := s.repo.GetProfileIDBySlug(ctx, slug)
+	if err != nil {
+		return "", fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if profileID == "" {
+		return "", fmt.Errorf("%w(slug: %s)", ErrProfileNotFound, slug)
+	}
+
+	return profileID, nil
+}
@@ -0,0 +1,185 @@
+package profiles_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/eser/aya.is-services/pkg/lib/cursors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	readerUserID    = "user-reader"
+	readerProfileID = "profile-reader"
+)
+
+// addReader adds "reader", the individual profile of readerUserID.
+func (r *fakeRepository) addReader() {
+	r.profileIDs["reader"] = readerProfileID
+	r.owners[readerProfileID] = readerUserID
+	r.individuals[readerUserID] = readerProfileID
+}
+
+func (r *fakeRepository) GetUserIndividualProfileID(
+	_ context.Context,
+	userID string,
+) (string, error) {
+	return r.individuals[userID], nil
+}
+
+func (r *fakeRepository) FollowProfile(
+	_ context.Context,
+	_ string,
+	followerProfileID string,
+	followedProfileID string,
+) (int64, error) {
+	follow := [2]string{followerProfileID, followedProfileID}
+	if slices.Contains(r.follows, follow) {
+		return 0, nil
+	}
+
+	r.follows = append(r.follows, follow)
+
+	return 1, nil
+}
+
+func (r *fakeRepository) UnfollowProfile(
+	_ context.Context,
+	followerProfileID string,
+	followedProfileID string,
+) (int64, error) {
+	count := len(r.follows)
+	r.follows = slices.DeleteFunc(r.follows, func(follow [2]string) bool {
+		return follow == [2]string{followerProfileID, followedProfileID}
+	})
+
+	return int64(count - len(r.follows)), nil
+}
+
+func (r *fakeRepository) ListProfileFollowers(
+	_ context.Context,
+	_ string,
+	profileID string,
+	_ *cursors.Cursor,
+) (cursors.Cursored[[]*profiles.ProfileFollow], error) {
+	records := []*profiles.ProfileFollow{}
+
+	for _, follow := range slices.Backward(r.follows) {
+		if follow[1] == profileID {
+			records = append(records, &profiles.ProfileFollow{ //nolint:exhaustruct
+				Profile: &profiles.Profile{ID: follow[0]}, //nolint:exhaustruct
+			})
+		}
+	}
+
+	return cursors.WrapResponseWithCursor(records, nil), nil
+}
+
+func TestServiceFollow(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expectedErr error
+		name        string
+		userID      string
+		slug        string
+	}{
+		{name: "should follow a profile", userID: readerUserID, slug: "acme"},
+		{
+			name:        "should not follow itself",
+			userID:      readerUserID,
+			slug:        "reader",
+			expectedErr: profiles.ErrCannotFollowSelf,
+		},
+		{
+			name:        "should require an individual profile",
+			userID:      ownerUserID,
+			slug:        "reader",
+			expectedErr: profiles.ErrIndividualProfileRequired,
+		},
+		{
+			name:        "should report unknown profiles",
+			userID:      readerUserID,
+			slug:        "missing",
+			expectedErr: profiles.ErrProfileNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newFakeRepository()
+			repo.addReader()
+			service := newTestService(repo)
+
+			err := service.Follow(t.Context(), tt.userID, tt.slug)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				assert.Empty(t, repo.follows)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, [][2]string{{readerProfileID, profileID}}, repo.follows)
+		})
+	}
+
+	t.Run("should follow a profile once when following it twice", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newFakeRepository()
+		repo.addReader()
+		service := newTestService(repo)
+
+		require.NoError(t, service.Follow(t.Context(), readerUserID, "acme"))
+		require.NoError(t, service.Follow(t.Context(), readerUserID, "acme"))
+
+		assert.Equal(t, [][2]string{{readerProfileID, profileID}}, repo.follows)
+	})
+}
+
+func TestServiceUnfollow(t *testing.T) {
+	t.Parallel()
+
+	repo := newFakeRepository()
+	repo.addReader()
+	service := newTestService(repo)
+
+	require.NoError(t, service.Follow(t.Context(), readerUserID, "acme"))
+
+	require.NoError(t, service.Unfollow(t.Context(), readerUserID, "acme"))
+	assert.Empty(t, repo.follows)
+
+	// Unfollowing a profile that is not followed is not an error.
+	require.NoError(t, service.Unfollow(t.Context(), readerUserID, "acme"))
+
+	err := service.Unfollow(t.Context(), readerUserID, "reader")
+	require.ErrorIs(t, err, profiles.ErrCannotFollowSelf)
+}
+
+func TestServiceListFollowersBySlug(t *testing.T) {
+	t.Parallel()
+
+	repo := newFakeRepository()
+	repo.addReader()
+	repo.individuals[ownerUserID] = profileID
+	service := newTestService(repo)
+
+	require.NoError(t, service.Follow(t.Context(), readerUserID, "acme"))
+	require.NoError(t, service.Follow(t.Context(), ownerUserID, "reader"))
+
+	cursor := &cursors.Cursor{Limit: 10} //nolint:exhaustruct
+
+	followers, err := service.ListFollowersBySlug(t.Context(), "en", "acme", cursor)
+	require.NoError(t, err)
+	require.Len(t, followers.Data, 1)
+	assert.Equal(t, readerProfileID, followers.Data[0].Profile.ID)
+
+	_, err = service.ListFollowersBySlug(t.Context(), "en", "missing", cursor)
+	require.ErrorIs(t, err, profiles.ErrProfileNotFound)
+}
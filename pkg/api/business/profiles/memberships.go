@@ -0,0 +1,484 @@
+package profiles
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	// MembershipKindOwner is the membership kind that lets a member edit the profile
+	// and manage its members.
+	MembershipKindOwner      = "owner"
+	MembershipKindMaintainer = "maintainer"
+	MembershipKindMember     = "member"
+
+	InvitationTTL = 7 * 24 * time.Hour
+
+	invitationTokenRandomBytes = 32
+)
+
+var (
+	ErrMembershipNotSupported = errors.New("only organization and product profiles have members")
+	ErrMembershipNotFound     = errors.New("membership not found")
+	ErrAlreadyMember          = errors.New("profile is already a member")
+	ErrLastOwner              = errors.New("profile must keep an owner")
+	ErrInvitationNotFound     = errors.New("invitation not found")
+	ErrInvitationExpired      = errors.New("invitation expired")
+)
+
+var membershipKinds = []string{ //nolint:gochecknoglobals
+	MembershipKindOwner,
+	MembershipKindMaintainer,
+	MembershipKindMember,
+}
+
+// MembershipEventKind tells what happened to a membership.
+type MembershipEventKind string
+
+const (
+	MembershipEventInvited     MembershipEventKind = "invited"
+	MembershipEventAccepted    MembershipEventKind = "accepted"
+	MembershipEventKindChanged MembershipEventKind = "kind_changed"
+	MembershipEventRemoved     MembershipEventKind = "removed"
+)
+
+// MembershipEvent describes a change of a membership. Invitation is set for invitations
+// and their acceptance; Token only for invitations, so a notifier can deliver it.
+type MembershipEvent struct {
+	Invitation        *ProfileMembershipInvitation
+	Kind              MembershipEventKind
+	ProfileSlug       string
+	MemberProfileSlug string
+	MembershipKind    string
+	ActorUserID       string
+	Token             string
+}
+
+// MembershipNotifier is told about membership changes, e.g. to email an invitation to
+// the owners of the member profile.
+type MembershipNotifier interface {
+	NotifyMembership(ctx context.Context, event *MembershipEvent) error
+}
+
+// AddMembershipNotifier registers a notifier. Notifiers run once a change is stored,
+// and their errors are logged without failing the change.
+func (s *Service) AddMembershipNotifier(notifier MembershipNotifier) {
+	s.membershipNotifiers = append(s.membershipNotifiers, notifier)
+}
+
+// InviteMember invites a member profile to an organization or product profile the user
+// owns. The token is returned only here; the owners of the member profile accept the
+// invitation with it.
+func (s *Service) InviteMember(
+	ctx context.Context,
+	userID string,
+	slug string,
+	input *InviteMemberInput,
+) (*ProfileMembershipInvitation, string, error) {
+	err := validateMembershipKind(input.Kind)
+	if err != nil {
+		return nil, "", err
+	}
+
+	profileID, err := s.getManagedProfileID(ctx, userID, slug)
+	if err != nil {
+		return nil, "", err
+	}
+
+	memberProfileID, err := s.repo.GetProfileIDBySlug(ctx, input.MemberProfileSlug)
+	if err != nil {
+		return nil, "", fmt.Errorf(
+			"%w(member_slug: %s): %w",
+			ErrFailedToGetRecord,
+			input.MemberProfileSlug,
+			err,
+		)
+	}
+
+	if memberProfileID == "" {
+		return nil, "", fmt.Errorf("%w(member_slug: %s)", ErrProfileNotFound, input.MemberProfileSlug)
+	}
+
+	if memberProfileID == profileID {
+		return nil, "", fmt.Errorf("%w: a profile cannot be its own member", ErrInvalidProfileInput)
+	}
+
+	currentKind, err := s.repo.GetProfileMembershipKind(ctx, profileID, memberProfileID)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if currentKind != "" {
+		return nil, "", fmt.Errorf(
+			"%w(slug: %s, member_slug: %s)",
+			ErrAlreadyMember,
+			slug,
+			input.MemberProfileSlug,
+		)
+	}
+
+	token, tokenHash, err := generateInvitationToken()
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %w", ErrFailedToCreateRecord, err)
+	}
+
+	now := time.Now()
+	invitation := &ProfileMembershipInvitation{
+		CreatedAt:         now,
+		ExpiresAt:         now.Add(InvitationTTL),
+		AcceptedAt:        nil,
+		ID:                string(s.idGenerator()),
+		ProfileID:         profileID,
+		ProfileSlug:       slug,
+		MemberProfileID:   memberProfileID,
+		MemberProfileSlug: input.MemberProfileSlug,
+		Kind:              input.Kind,
+		InvitedByUserID:   userID,
+	}
+
+	err = s.repo.CreateProfileMembershipInvitation(ctx, invitation, tokenHash)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w(slug: %s): %w", ErrFailedToCreateRecord, slug, err)
+	}
+
+	s.notifyMembership(ctx, &MembershipEvent{
+		Invitation:        invitation,
+		Kind:              MembershipEventInvited,
+		ProfileSlug:       slug,
+		MemberProfileSlug: input.MemberProfileSlug,
+		MembershipKind:    input.Kind,
+		ActorUserID:       userID,
+		Token:             token,
+	})
+
+	return invitation, token, nil
+}
+
+// AcceptInvitation adds the member profile of the invitation to the profile, once it
+// checks that the user owns the member profile.
+func (s *Service) AcceptInvitation(
+	ctx context.Context,
+	userID string,
+	slug string,
+	token string,
+) (*ProfileMembershipInvitation, error) {
+	invitation, err := s.repo.GetProfileMembershipInvitationByTokenHash(
+		ctx,
+		hashInvitationToken(token),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if invitation == nil || invitation.ProfileSlug != slug {
+		return nil, fmt.Errorf("%w(slug: %s)", ErrInvitationNotFound, slug)
+	}
+
+	if time.Now().After(invitation.ExpiresAt) {
+		return nil, fmt.Errorf("%w(slug: %s, id: %s)", ErrInvitationExpired, slug, invitation.ID)
+	}
+
+	owned, err := s.repo.IsProfileOwnedByUser(ctx, invitation.MemberProfileID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if !owned {
+		return nil, fmt.Errorf("%w(slug: %s)", ErrNotProfileOwner, invitation.MemberProfileSlug)
+	}
+
+	accepted, err := s.repo.AcceptProfileMembershipInvitation(
+		ctx,
+		invitation,
+		string(s.idGenerator()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToUpdateRecord, slug, err)
+	}
+
+	if !accepted {
+		return nil, fmt.Errorf("%w(slug: %s)", ErrInvitationNotFound, slug)
+	}
+
+	acceptedAt := time.Now()
+	invitation.AcceptedAt = &acceptedAt
+
+	s.invalidateProfile(ctx, slug)
+	s.invalidateProfile(ctx, invitation.MemberProfileSlug)
+
+	s.notifyMembership(ctx, &MembershipEvent{
+		Invitation:        invitation,
+		Kind:              MembershipEventAccepted,
+		ProfileSlug:       slug,
+		MemberProfileSlug: invitation.MemberProfileSlug,
+		MembershipKind:    invitation.Kind,
+		ActorUserID:       userID,
+		Token:             "",
+	})
+
+	return invitation, nil
+}
+
+// ChangeMemberKind changes the kind of a membership of a profile the user owns. The
+// last owner of a profile cannot become another kind.
+func (s *Service) ChangeMemberKind(
+	ctx context.Context,
+	userID string,
+	slug string,
+	memberSlug string,
+	kind string,
+) error {
+	err := validateMembershipKind(kind)
+	if err != nil {
+		return err
+	}
+
+	profileID, err := s.getManagedProfileID(ctx, userID, slug)
+	if err != nil {
+		return err
+	}
+
+	memberProfileID, currentKind, err := s.getMembership(ctx, profileID, slug, memberSlug)
+	if err != nil {
+		return err
+	}
+
+	if currentKind == MembershipKindOwner && kind != MembershipKindOwner {
+		err = s.ensureAnotherOwner(ctx, profileID, slug)
+		if err != nil {
+			return err
+		}
+	}
+
+	updated, err := s.repo.UpdateProfileMembershipKind(ctx, profileID, memberProfileID, kind)
+	if err != nil {
+		return fmt.Errorf(
+			"%w(slug: %s, member_slug: %s): %w",
+			ErrFailedToUpdateRecord,
+			slug,
+			memberSlug,
+			err,
+		)
+	}
+
+	if updated == 0 {
+		return fmt.Errorf("%w(slug: %s, member_slug: %s)", ErrMembershipNotFound, slug, memberSlug)
+	}
+
+	s.invalidateProfile(ctx, slug)
+	s.invalidateProfile(ctx, memberSlug)
+
+	s.notifyMembership(ctx, &MembershipEvent{
+		Invitation:        nil,
+		Kind:              MembershipEventKindChanged,
+		ProfileSlug:       slug,
+		MemberProfileSlug: memberSlug,
+		MembershipKind:    kind,
+		ActorUserID:       userID,
+		Token:             "",
+	})
+
+	return nil
+}
+
+// RemoveMember ends a membership. The owners of the profile may remove any member, and
+// the owners of the member profile may leave. The last owner of a profile cannot leave.
+func (s *Service) RemoveMember(
+	ctx context.Context,
+	userID string,
+	slug string,
+	memberSlug string,
+) error {
+	profileID, err := s.repo.GetProfileIDBySlug(ctx, slug)
+	if err != nil {
+		return fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if profileID == "" {
+		return fmt.Errorf("%w(slug: %s)", ErrProfileNotFound, slug)
+	}
+
+	memberProfileID, currentKind, err := s.getMembership(ctx, profileID, slug, memberSlug)
+	if err != nil {
+		return err
+	}
+
+	allowed, err := s.repo.IsProfileOwnedByUser(ctx, profileID, userID)
+	if err == nil && !allowed {
+		allowed, err = s.repo.IsProfileOwnedByUser(ctx, memberProfileID, userID)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if !allowed {
+		return fmt.Errorf("%w(slug: %s)", ErrNotProfileOwner, slug)
+	}
+
+	if currentKind == MembershipKindOwner {
+		err = s.ensureAnotherOwner(ctx, profileID, slug)
+		if err != nil {
+			return err
+		}
+	}
+
+	removed, err := s.repo.RemoveProfileMembership(ctx, profileID, memberProfileID)
+	if err != nil {
+		return fmt.Errorf(
+			"%w(slug: %s, member_slug: %s): %w",
+			ErrFailedToDeleteRecord,
+			slug,
+			memberSlug,
+			err,
+		)
+	}
+
+	if removed == 0 {
+		return fmt.Errorf("%w(slug: %s, member_slug: %s)", ErrMembershipNotFound, slug, memberSlug)
+	}
+
+	s.invalidateProfile(ctx, slug)
+	s.invalidateProfile(ctx, memberSlug)
+
+	s.notifyMembership(ctx, &MembershipEvent{
+		Invitation:        nil,
+		Kind:              MembershipEventRemoved,
+		ProfileSlug:       slug,
+		MemberProfileSlug: memberSlug,
+		MembershipKind:    currentKind,
+		ActorUserID:       userID,
+		Token:             "",
+	})
+
+	return nil
+}
+
+// getManagedProfileID is like getOwnedProfileID, and also fails with
+// ErrMembershipNotSupported unless the profile is an organization or a product.
+func (s *Service) getManagedProfileID(
+	ctx context.Context,
+	userID string,
+	slug string,
+) (string, error) {
+	profileID, err := s.getOwnedProfileID(ctx, userID, slug)
+	if err != nil {
+		return "", err
+	}
+
+	kind, err := s.repo.GetProfileKindByID(ctx, profileID)
+	if err != nil {
+		return "", fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if kind != ProfileKindOrganization && kind != ProfileKindProduct {
+		return "", fmt.Errorf("%w(slug: %s, kind: %s)", ErrMembershipNotSupported, slug, kind)
+	}
+
+	return profileID, nil
+}
+
+// getMembership resolves the member profile of a membership and its kind.
+func (s *Service) getMembership(
+	ctx context.Context,
+	profileID string,
+	slug string,
+	memberSlug string,
+) (string, string, error) {
+	memberProfileID, err := s.repo.GetProfileIDBySlug(ctx, memberSlug)
+	if err != nil {
+		return "", "", fmt.Errorf("%w(member_slug: %s): %w", ErrFailedToGetRecord, memberSlug, err)
+	}
+
+	if memberProfileID == "" {
+		return "", "", fmt.Errorf(
+			"%w(slug: %s, member_slug: %s)",
+			ErrMembershipNotFound,
+			slug,
+			memberSlug,
+		)
+	}
+
+	kind, err := s.repo.GetProfileMembershipKind(ctx, profileID, memberProfileID)
+	if err != nil {
+		return "", "", fmt.Errorf(
+			"%w(slug: %s, member_slug: %s): %w",
+			ErrFailedToGetRecord,
+			slug,
+			memberSlug,
+			err,
+		)
+	}
+
+	if kind == "" {
+		return "", "", fmt.Errorf(
+			"%w(slug: %s, member_slug: %s)",
+			ErrMembershipNotFound,
+			slug,
+			memberSlug,
+		)
+	}
+
+	return memberProfileID, kind, nil
+}
+
+func (s *Service) ensureAnotherOwner(ctx context.Context, profileID string, slug string) error {
+	owners, err := s.repo.CountProfileMembershipsOfKind(ctx, profileID, MembershipKindOwner)
+	if err != nil {
+		return fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if owners <= 1 {
+		return fmt.Errorf("%w(slug: %s)", ErrLastOwner, slug)
+	}
+
+	return nil
+}
+
+func (s *Service) notifyMembership(ctx context.Context, event *MembershipEvent) {
+	for _, notifier := range s.membershipNotifiers {
+		err := notifier.NotifyMembership(ctx, event)
+		if err != nil {
+			s.logger.WarnContext(
+				ctx,
+				"membership notifier failed",
+				"event", event.Kind,
+				"slug", event.ProfileSlug,
+				"member_slug", event.MemberProfileSlug,
+				"error", err,
+			)
+		}
+	}
+}
+
+func validateMembershipKind(kind string) error {
+	if !slices.Contains(membershipKinds, kind) {
+		return fmt.Errorf("%w: kind is invalid (kind: %s)", ErrInvalidProfileInput, kind)
+	}
+
+	return nil
+}
+
+func generateInvitationToken() (string, string, error) {
+	random := make([]byte, invitationTokenRandomBytes)
+	if _, err := rand.Read(random); err != nil {
+		return "", "", err //nolint:wrapcheck
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(random)
+
+	return token, hashInvitationToken(token), nil
+}
+
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,302 @@
+package profiles_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	memberUserID    = "user-member"
+	memberProfileID = "profile-member"
+)
+
+// addMember adds "member", a profile owned by memberUserID, to the members of "acme".
+func (r *fakeRepository) addMember(kind string) {
+	r.profileIDs["member"] = memberProfileID
+	r.owners[memberProfileID] = memberUserID
+	r.memberships[memberProfileID] = kind
+}
+
+// addCoOwner adds another owner to the members of "acme".
+func (r *fakeRepository) addCoOwner() {
+	r.memberships["profile-co-owner"] = profiles.MembershipKindOwner
+}
+
+func (r *fakeRepository) GetProfileKindByID(_ context.Context, _ string) (string, error) {
+	return profiles.ProfileKindOrganization, nil
+}
+
+func (r *fakeRepository) GetProfileMembershipKind(
+	_ context.Context,
+	_ string,
+	memberProfileID string,
+) (string, error) {
+	return r.memberships[memberProfileID], nil
+}
+
+func (r *fakeRepository) CountProfileMembershipsOfKind(
+	_ context.Context,
+	_ string,
+	kind string,
+) (int64, error) {
+	var count int64
+
+	for _, membershipKind := range r.memberships {
+		if membershipKind == kind {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *fakeRepository) UpdateProfileMembershipKind(
+	_ context.Context,
+	_ string,
+	memberProfileID string,
+	kind string,
+) (int64, error) {
+	if _, ok := r.memberships[memberProfileID]; !ok {
+		return 0, nil
+	}
+
+	r.memberships[memberProfileID] = kind
+
+	return 1, nil
+}
+
+func (r *fakeRepository) RemoveProfileMembership(
+	_ context.Context,
+	_ string,
+	memberProfileID string,
+) (int64, error) {
+	if _, ok := r.memberships[memberProfileID]; !ok {
+		return 0, nil
+	}
+
+	delete(r.memberships, memberProfileID)
+
+	return 1, nil
+}
+
+func (r *fakeRepository) GetProfileMembershipInvitationByTokenHash(
+	_ context.Context,
+	_ string,
+) (*profiles.ProfileMembershipInvitation, error) {
+	return r.invitation, nil
+}
+
+func (r *fakeRepository) AcceptProfileMembershipInvitation(
+	_ context.Context,
+	invitation *profiles.ProfileMembershipInvitation,
+	_ string,
+) (bool, error) {
+	r.accepted = true
+	r.memberships[invitation.MemberProfileID] = invitation.Kind
+
+	return true, nil
+}
+
+func TestServiceChangeMemberKind(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expectedErr error
+		name        string
+		currentKind string
+		kind        string
+		coOwner     bool
+	}{
+		{
+			name:        "should keep the last owner an owner",
+			currentKind: profiles.MembershipKindOwner,
+			kind:        profiles.MembershipKindMaintainer,
+			expectedErr: profiles.ErrLastOwner,
+		},
+		{
+			name:        "should let an owner step down while another is left",
+			currentKind: profiles.MembershipKindOwner,
+			kind:        profiles.MembershipKindMaintainer,
+			coOwner:     true,
+			expectedErr: nil,
+		},
+		{
+			name:        "should promote a member to an owner",
+			currentKind: profiles.MembershipKindMember,
+			kind:        profiles.MembershipKindOwner,
+			expectedErr: nil,
+		},
+		{
+			name:        "should reject unknown kinds",
+			currentKind: profiles.MembershipKindMember,
+			kind:        "admin",
+			expectedErr: profiles.ErrInvalidProfileInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newFakeRepository()
+			repo.addMember(tt.currentKind)
+
+			if tt.coOwner {
+				repo.addCoOwner()
+			}
+
+			service := newTestService(repo)
+
+			err := service.ChangeMemberKind(t.Context(), ownerUserID, "acme", "member", tt.kind)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				assert.Equal(t, tt.currentKind, repo.memberships[memberProfileID])
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.kind, repo.memberships[memberProfileID])
+		})
+	}
+}
+
+func TestServiceRemoveMember(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expectedErr error
+		name        string
+		userID      string
+		currentKind string
+		coOwner     bool
+	}{
+		{
+			name:        "should keep the last owner",
+			userID:      ownerUserID,
+			currentKind: profiles.MembershipKindOwner,
+			expectedErr: profiles.ErrLastOwner,
+		},
+		{
+			name:        "should let the owners of the profile remove a member",
+			userID:      ownerUserID,
+			currentKind: profiles.MembershipKindMember,
+			expectedErr: nil,
+		},
+		{
+			name:        "should let the owners of the member profile leave",
+			userID:      memberUserID,
+			currentKind: profiles.MembershipKindMember,
+			expectedErr: nil,
+		},
+		{
+			name:        "should reject other users",
+			userID:      "user-other",
+			currentKind: profiles.MembershipKindMember,
+			expectedErr: profiles.ErrNotProfileOwner,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newFakeRepository()
+			repo.addMember(tt.currentKind)
+
+			if tt.coOwner {
+				repo.addCoOwner()
+			}
+
+			service := newTestService(repo)
+
+			err := service.RemoveMember(t.Context(), tt.userID, "acme", "member")
+			require.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expectedErr != nil, repo.memberships[memberProfileID] != "")
+		})
+	}
+}
+
+func TestServiceAcceptInvitation(t *testing.T) {
+	t.Parallel()
+
+	invitation := func(slug string, expiresAt time.Time) *profiles.ProfileMembershipInvitation {
+		return &profiles.ProfileMembershipInvitation{ //nolint:exhaustruct
+			ExpiresAt:         expiresAt,
+			ID:                "invitation-1",
+			ProfileID:         profileID,
+			ProfileSlug:       slug,
+			MemberProfileID:   memberProfileID,
+			MemberProfileSlug: "member",
+			Kind:              profiles.MembershipKindMember,
+		}
+	}
+
+	tests := []struct {
+		expectedErr error
+		invitation  *profiles.ProfileMembershipInvitation
+		name        string
+		userID      string
+	}{
+		{
+			name:        "should accept pending invitations",
+			invitation:  invitation("acme", time.Now().Add(time.Hour)),
+			userID:      memberUserID,
+			expectedErr: nil,
+		},
+		{
+			name:        "should reject expired invitations",
+			invitation:  invitation("acme", time.Now().Add(-time.Minute)),
+			userID:      memberUserID,
+			expectedErr: profiles.ErrInvitationExpired,
+		},
+		{
+			name:        "should not find the invitations of other profiles",
+			invitation:  invitation("other", time.Now().Add(time.Hour)),
+			userID:      memberUserID,
+			expectedErr: profiles.ErrInvitationNotFound,
+		},
+		{
+			name:        "should not find unknown tokens",
+			invitation:  nil,
+			userID:      memberUserID,
+			expectedErr: profiles.ErrInvitationNotFound,
+		},
+		{
+			name:        "should reject the users who do not own the member profile",
+			invitation:  invitation("acme", time.Now().Add(time.Hour)),
+			userID:      ownerUserID,
+			expectedErr: profiles.ErrNotProfileOwner,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newFakeRepository()
+			repo.profileIDs["member"] = memberProfileID
+			repo.owners[memberProfileID] = memberUserID
+			repo.invitation = tt.invitation
+			service := newTestService(repo)
+
+			accepted, err := service.AcceptInvitation(t.Context(), tt.userID, "acme", "token")
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				assert.False(t, repo.accepted)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, repo.accepted)
+			assert.NotNil(t, accepted.AcceptedAt)
+			assert.Equal(t, profiles.MembershipKindMember, repo.memberships[memberProfileID])
+		})
+	}
+}
@@ -4,15 +4,47 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"regexp"
+	"slices"
+	"time"
 
 	"github.com/eser/aya.is-services/pkg/ajan/logfx"
 	"github.com/eser/aya.is-services/pkg/lib/cursors"
 )
 
+const (
+	ProfileKindIndividual   = "individual"
+	ProfileKindOrganization = "organization"
+	ProfileKindProduct      = "product"
+
+	MaxSlugLength = 64
+)
+
+var (
+	ErrFailedToGetRecord    = errors.New("failed to get record")
+	ErrFailedToListRecords  = errors.New("failed to list records")
+	ErrFailedToCreateRecord = errors.New("failed to create record")
+	ErrFailedToUpdateRecord = errors.New("failed to update record")
+	ErrFailedToDeleteRecord = errors.New("failed to delete record")
+	ErrFailedToInvalidate   = errors.New("failed to invalidate cached records")
+
+	ErrInvalidProfileInput       = errors.New("invalid profile input")
+	ErrProfileNotFound           = errors.New("profile not found")
+	ErrNotProfileOwner           = errors.New("not an owner of the profile")
+	ErrSlugAlreadyTaken          = errors.New("slug is already taken")
+	ErrIndividualProfileExists   = errors.New("user already has an individual profile")
+	ErrIndividualProfileRequired = errors.New("user has no individual profile")
+)
+
 var (
-	ErrFailedToGetRecord   = errors.New("failed to get record")
-	ErrFailedToListRecords = errors.New("failed to list records")
-	// ErrFailedToCreateRecord = errors.New("failed to create record").
+	slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`) //nolint:gochecknoglobals
+
+	profileKinds = []string{ //nolint:gochecknoglobals
+		ProfileKindIndividual,
+		ProfileKindOrganization,
+		ProfileKindProduct,
+	}
 )
 
 type RecentPostsFetcher interface {
@@ -23,8 +55,16 @@ type RecentPostsFetcher interface {
 	) ([]*ExternalPost, error)
 }
 
+// CacheInvalidator drops cached responses once the records they show change.
+type CacheInvalidator interface {
+	Invalidate(ctx context.Context, tags ...string) error
+}
+
 type Repository interface {
 	GetProfileIDBySlug(ctx context.Context, slug string) (string, error)
+	// GetProfileSlugRedirect returns the current slug of the profile that had oldSlug,
+	// or "" when none had it.
+	GetProfileSlugRedirect(ctx context.Context, oldSlug string) (string, error)
 	GetProfileIDByCustomDomain(ctx context.Context, domain string) (*string, error)
 	GetProfileByID(ctx context.Context, localeCode string, id string) (*Profile, error)
 	ListProfiles(
@@ -63,19 +103,239 @@ type Repository interface {
 		kinds []string,
 		cursor *cursors.Cursor,
 	) (cursors.Cursored[[]*ProfileMembership], error)
-	// CreateProfile(ctx context.Context, arg CreateProfileParams) (*Profile, error)
-	// UpdateProfile(ctx context.Context, arg UpdateProfileParams) (int64, error)
-	// DeleteProfile(ctx context.Context, id string) (int64, error)
+	IsProfileOwnedByUser(ctx context.Context, profileID string, userID string) (bool, error)
+	// CreateProfile stores the profile with its title and description for the locale.
+	// An individual profile becomes the individual profile of the user, which fails
+	// with ErrIndividualProfileExists when the user has one; other profiles get the
+	// individual profile of the user as their owner, which fails with
+	// ErrIndividualProfileRequired when the user has none.
+	CreateProfile(
+		ctx context.Context,
+		localeCode string,
+		profile *Profile,
+		ownerUserID string,
+		membershipID string,
+	) error
+	// UpdateProfile changes the fields of input that are set, and drops the cached ID
+	// of slug, the current slug of the profile. When the slug changes, slug keeps
+	// redirecting to the profile.
+	UpdateProfile(
+		ctx context.Context,
+		localeCode string,
+		id string,
+		slug string,
+		input *UpdateProfileInput,
+	) (int64, error)
+	// DeleteProfile soft-deletes the profile; its slug stays taken.
+	DeleteProfile(ctx context.Context, id string, slug string) (int64, error)
+	GetProfileKindByID(ctx context.Context, id string) (string, error)
+
+	// GetProfileMembershipKind returns "" when the member profile is not a member.
+	GetProfileMembershipKind(
+		ctx context.Context,
+		profileID string,
+		memberProfileID string,
+	) (string, error)
+	CountProfileMembershipsOfKind(ctx context.Context, profileID string, kind string) (int64, error)
+	UpdateProfileMembershipKind(
+		ctx context.Context,
+		profileID string,
+		memberProfileID string,
+		kind string,
+	) (int64, error)
+	RemoveProfileMembership(
+		ctx context.Context,
+		profileID string,
+		memberProfileID string,
+	) (int64, error)
+	CreateProfileMembershipInvitation(
+		ctx context.Context,
+		invitation *ProfileMembershipInvitation,
+		tokenHash string,
+	) error
+	// GetProfileMembershipInvitationByTokenHash returns nil when no pending invitation
+	// has the token.
+	GetProfileMembershipInvitationByTokenHash(
+		ctx context.Context,
+		tokenHash string,
+	) (*ProfileMembershipInvitation, error)
+	// AcceptProfileMembershipInvitation marks the invitation accepted and adds the
+	// membership, or returns false when it was accepted already.
+	AcceptProfileMembershipInvitation(
+		ctx context.Context,
+		invitation *ProfileMembershipInvitation,
+		membershipID string,
+	) (bool, error)
+
+	// GetUserIndividualProfileID returns "" when the user has no individual profile.
+	GetUserIndividualProfileID(ctx context.Context, userID string) (string, error)
+	// FollowProfile returns 0 when the follower follows the profile already.
+	FollowProfile(
+		ctx context.Context,
+		id string,
+		followerProfileID string,
+		followedProfileID string,
+	) (int64, error)
+	UnfollowProfile(
+		ctx context.Context,
+		followerProfileID string,
+		followedProfileID string,
+	) (int64, error)
+	ListProfileFollowers(
+		ctx context.Context,
+		localeCode string,
+		profileID string,
+		cursor *cursors.Cursor,
+	) (cursors.Cursored[[]*ProfileFollow], error)
+	ListProfileFollowing(
+		ctx context.Context,
+		localeCode string,
+		profileID string,
+		cursor *cursors.Cursor,
+	) (cursors.Cursored[[]*ProfileFollow], error)
+
+	// CreateCustomDomainClaim stores the claim and supersedes the pending claims of
+	// the profile.
+	CreateCustomDomainClaim(ctx context.Context, claim *CustomDomainClaim) error
+	// GetLatestCustomDomainClaim returns nil when the profile has no claim.
+	GetLatestCustomDomainClaim(ctx context.Context, profileID string) (*CustomDomainClaim, error)
+	ListPendingCustomDomainClaims(ctx context.Context, limit int) ([]*CustomDomainClaim, error)
+	// RecordCustomDomainClaimCheck counts a failed check of a pending claim and moves it
+	// to status, or returns 0 when the claim is no longer pending.
+	RecordCustomDomainClaimCheck(
+		ctx context.Context,
+		id string,
+		status string,
+		lastError string,
+	) (int64, error)
+	// VerifyCustomDomainClaim sets the domain of the claim as the custom domain of the
+	// profile, which fails with ErrCustomDomainTaken when another profile has it, and
+	// returns false when the claim is no longer pending.
+	VerifyCustomDomainClaim(ctx context.Context, claim *CustomDomainClaim) (bool, error)
+
+	// RecordProfileView and RecordStoryView return 0 when the visitor viewed the record
+	// on viewedOn already, or when no record has slug.
+	RecordProfileView(
+		ctx context.Context,
+		id string,
+		slug string,
+		visitorHash string,
+		viewedOn time.Time,
+	) (int64, error)
+	RecordStoryView(
+		ctx context.Context,
+		id string,
+		slug string,
+		visitorHash string,
+		viewedOn time.Time,
+	) (int64, error)
+	GetProfileViewSummary(
+		ctx context.Context,
+		profileID string,
+		from time.Time,
+		to time.Time,
+	) (*ViewSummary, error)
+	// ListProfileViewsByDay lists the days with views only, the earliest first.
+	ListProfileViewsByDay(
+		ctx context.Context,
+		profileID string,
+		from time.Time,
+		to time.Time,
+	) ([]*DailyViews, error)
+
+	CreateProfileExport(ctx context.Context, export *ProfileExport, requestedByUserID string) error
+	// GetProfileExport and GetActiveProfileExport return nil when there is no such
+	// export; the active export of a profile is a pending or running one.
+	GetProfileExport(ctx context.Context, id string, profileID string) (*ProfileExport, error)
+	GetActiveProfileExport(ctx context.Context, profileID string) (*ProfileExport, error)
+	// ClaimProfileExports marks up to limit pending exports, and running ones started
+	// before staleBefore, as running, and returns them.
+	ClaimProfileExports(
+		ctx context.Context,
+		staleBefore time.Time,
+		limit int,
+	) ([]*ProfileExport, error)
+	CompleteProfileExport(
+		ctx context.Context,
+		id string,
+		objectKey string,
+		sizeBytes int64,
+		expiresAt time.Time,
+	) (int64, error)
+	RecordProfileExportFailure(
+		ctx context.Context,
+		id string,
+		status string,
+		lastError string,
+	) (int64, error)
+	ListExpiredProfileExports(ctx context.Context, limit int) ([]*ProfileExport, error)
+	ExpireProfileExport(ctx context.Context, id string) (int64, error)
+	// GetProfileExportData returns nil when the profile does not exist.
+	GetProfileExportData(ctx context.Context, profileID string) (*ProfileExportData, error)
+
+	ListProfileBadges(ctx context.Context, profileID string) ([]*ProfileBadge, error)
+	// GrantProfileBadge returns false when the profile holds a badge of the kind already.
+	GrantProfileBadge(ctx context.Context, badge *ProfileBadge) (bool, error)
+	// AwardProfileBadge grants a badge by a rule, and returns false when the profile
+	// holds one of the kind already, or an admin revoked one of the kind from it.
+	AwardProfileBadge(ctx context.Context, id string, profileID string, kind string) (bool, error)
+	// RevokeProfileBadge revokes the badge of the kind the profile holds, only when it
+	// is from source unless source is "", and returns false when there is none.
+	RevokeProfileBadge(
+		ctx context.Context,
+		profileID string,
+		kind string,
+		source string,
+		revokedBy string,
+	) (bool, error)
+	ListBadgeHolders(ctx context.Context, kind string, source string) ([]*BadgeHolder, error)
+	// ListBadgeRuleMatches lists the profiles the award rule of the kind holds for.
+	ListBadgeRuleMatches(ctx context.Context, kind string) ([]*BadgeHolder, error)
 }
 
 type Service struct {
-	logger      *logfx.Logger
-	repo        Repository
-	idGenerator RecordIDGenerator
+	logger              *logfx.Logger
+	repo                Repository
+	cacheInvalidator    CacheInvalidator
+	idGenerator         RecordIDGenerator
+	txtResolver         TXTResolver
+	membershipNotifiers []MembershipNotifier
+
+	// viewQueue is nil until SetViewQueue is called.
+	viewQueue       ViewQueue
+	viewQueueName   string
+	viewVisitorSalt []byte
+
+	// exportStorage is nil until SetExportStorage is called.
+	exportStorage  ExportStorage
+	exportSettings ExportSettings
+}
+
+func NewService(
+	logger *logfx.Logger,
+	repo Repository,
+	cacheInvalidator CacheInvalidator,
+) *Service {
+	return &Service{
+		logger:              logger,
+		repo:                repo,
+		cacheInvalidator:    cacheInvalidator,
+		idGenerator:         DefaultIDGenerator,
+		txtResolver:         net.DefaultResolver,
+		membershipNotifiers: nil,
+
+		viewQueue:       nil,
+		viewQueueName:   "",
+		viewVisitorSalt: nil,
+
+		exportStorage:  nil,
+		exportSettings: ExportSettings{}, //nolint:exhaustruct
+	}
 }
 
-func NewService(logger *logfx.Logger, repo Repository) *Service {
-	return &Service{logger: logger, repo: repo, idGenerator: DefaultIDGenerator}
+// CacheTagForProfile tags cached responses that show the profile with the given slug.
+func CacheTagForProfile(slug string) string {
+	return "profile:" + slug
 }
 
 func (s *Service) GetByID(ctx context.Context, localeCode string, id string) (*Profile, error) {
@@ -88,9 +348,9 @@ func (s *Service) GetByID(ctx context.Context, localeCode string, id string) (*P
 }
 
 func (s *Service) GetBySlug(ctx context.Context, localeCode string, slug string) (*Profile, error) {
-	profileID, err := s.repo.GetProfileIDBySlug(ctx, slug)
+	profileID, err := s.resolveProfileSlug(ctx, slug)
 	if err != nil {
-		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+		return nil, err
 	}
 
 	record, err := s.repo.GetProfileByID(ctx, localeCode, profileID)
@@ -106,9 +366,9 @@ func (s *Service) GetBySlugEx(
 	localeCode string,
 	slug string,
 ) (*ProfileWithChildren, error) {
-	profileID, err := s.repo.GetProfileIDBySlug(ctx, slug)
+	profileID, err := s.resolveProfileSlug(ctx, slug)
 	if err != nil {
-		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+		return nil, err
 	}
 
 	record, err := s.repo.GetProfileByID(ctx, localeCode, profileID)
@@ -126,10 +386,16 @@ func (s *Service) GetBySlugEx(
 		return nil, fmt.Errorf("%w(profile_id: %s): %w", ErrFailedToGetRecord, profileID, err)
 	}
 
+	badges, err := s.repo.ListProfileBadges(ctx, record.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w(profile_id: %s): %w", ErrFailedToGetRecord, profileID, err)
+	}
+
 	result := &ProfileWithChildren{
 		Profile: record,
 		Pages:   pages,
 		Links:   links,
+		Badges:  badges,
 	}
 
 	return result, nil
@@ -175,9 +441,9 @@ func (s *Service) ListPagesBySlug(
 	localeCode string,
 	slug string,
 ) ([]*ProfilePageBrief, error) {
-	profileID, err := s.repo.GetProfileIDBySlug(ctx, slug)
+	profileID, err := s.resolveProfileSlug(ctx, slug)
 	if err != nil {
-		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+		return nil, err
 	}
 
 	pages, err := s.repo.ListProfilePagesByProfileID(ctx, localeCode, profileID)
@@ -194,9 +460,9 @@ func (s *Service) GetPageBySlug(
 	slug string,
 	pageSlug string,
 ) (*ProfilePage, error) {
-	profileID, err := s.repo.GetProfileIDBySlug(ctx, slug)
+	profileID, err := s.resolveProfileSlug(ctx, slug)
 	if err != nil {
-		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+		return nil, err
 	}
 
 	page, err := s.repo.GetProfilePageByProfileIDAndSlug(ctx, localeCode, profileID, pageSlug)
@@ -218,9 +484,9 @@ func (s *Service) ListLinksBySlug(
 	localeCode string,
 	slug string,
 ) ([]*ProfileLinkBrief, error) {
-	profileID, err := s.repo.GetProfileIDBySlug(ctx, slug)
+	profileID, err := s.resolveProfileSlug(ctx, slug)
 	if err != nil {
-		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+		return nil, err
 	}
 
 	links, err := s.repo.ListProfileLinksByProfileID(ctx, localeCode, profileID)
@@ -237,14 +503,9 @@ func (s *Service) ListProfileContributionsBySlug(
 	slug string,
 	cursor *cursors.Cursor,
 ) (cursors.Cursored[[]*ProfileMembership], error) {
-	profileID, err := s.repo.GetProfileIDBySlug(ctx, slug)
+	profileID, err := s.resolveProfileSlug(ctx, slug)
 	if err != nil {
-		return cursors.Cursored[[]*ProfileMembership]{}, fmt.Errorf(
-			"%w(slug: %s): %w",
-			ErrFailedToGetRecord,
-			slug,
-			err,
-		)
+		return cursors.Cursored[[]*ProfileMembership]{}, err
 	}
 
 	memberships, err := s.repo.ListProfileContributions(
@@ -271,14 +532,9 @@ func (s *Service) ListProfileMembersBySlug(
 	slug string,
 	cursor *cursors.Cursor,
 ) (cursors.Cursored[[]*ProfileMembership], error) {
-	profileID, err := s.repo.GetProfileIDBySlug(ctx, slug)
+	profileID, err := s.resolveProfileSlug(ctx, slug)
 	if err != nil {
-		return cursors.Cursored[[]*ProfileMembership]{}, fmt.Errorf(
-			"%w(slug: %s): %w",
-			ErrFailedToGetRecord,
-			slug,
-			err,
-		)
+		return cursors.Cursored[[]*ProfileMembership]{}, err
 	}
 
 	memberships, err := s.repo.ListProfileMembers(
@@ -299,6 +555,21 @@ func (s *Service) ListProfileMembersBySlug(
 	return memberships, nil
 }
 
+// InvalidateProfile drops the cached responses of a profile and its pages, links
+// and stories. Call it once the profile changes.
+func (s *Service) InvalidateProfile(ctx context.Context, slug string) error {
+	if s.cacheInvalidator == nil {
+		return nil
+	}
+
+	err := s.cacheInvalidator.Invalidate(ctx, CacheTagForProfile(slug))
+	if err != nil {
+		return fmt.Errorf("%w (slug=%q): %w", ErrFailedToInvalidate, slug, err)
+	}
+
+	return nil
+}
+
 func (s *Service) Import(ctx context.Context, fetcher RecentPostsFetcher) error {
 	// 	links, err := s.repo.ListProfileLinksForKind(ctx, "x")
 	// 	if err != nil {
@@ -315,11 +586,205 @@ func (s *Service) Import(ctx context.Context, fetcher RecentPostsFetcher) error
 	return nil
 }
 
-// func (s *Service) Create(ctx context.Context, input *Profile) (*Profile, error) {
-// 	record, err := s.repo.CreateProfile(ctx, input)
-// 	if err != nil {
-// 		return nil, fmt.Errorf("%w: %w", ErrFailedToCreateRecord, err)
-// 	}
+// Create creates a profile for the user, who becomes its owner. Title and Description
+// are stored for the locale.
+func (s *Service) Create(
+	ctx context.Context,
+	localeCode string,
+	userID string,
+	input *CreateProfileInput,
+) (*Profile, error) {
+	err := validateCreateProfileInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &Profile{
+		CreatedAt:         time.Now(),
+		Properties:        nil,
+		CustomDomain:      nil,
+		ProfilePictureURI: input.ProfilePictureURI,
+		Pronouns:          input.Pronouns,
+		UpdatedAt:         nil,
+		DeletedAt:         nil,
+		ID:                string(s.idGenerator()),
+		Slug:              input.Slug,
+		Kind:              input.Kind,
+		Title:             input.Title,
+		Description:       input.Description,
+	}
+
+	err = s.repo.CreateProfile(ctx, localeCode, record, userID, string(s.idGenerator()))
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToCreateRecord, input.Slug, err)
+	}
+
+	s.invalidateProfile(ctx, input.Slug)
+
+	return record, nil
+}
+
+// Update changes the fields of input that are set, once it checks that the user owns
+// the profile. Title is required when the profile has no title for the locale yet.
+func (s *Service) Update(
+	ctx context.Context,
+	localeCode string,
+	userID string,
+	slug string,
+	input *UpdateProfileInput,
+) (*Profile, error) {
+	err := validateUpdateProfileInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	profileID, err := s.getOwnedProfileID(ctx, userID, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.GetProfileByID(ctx, localeCode, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	changes := *input
+
+	if current != nil {
+		if changes.Title == nil {
+			changes.Title = &current.Title
+		}
+
+		if changes.Description == nil {
+			changes.Description = &current.Description
+		}
+	} else if changes.Title == nil && changes.Description != nil {
+		return nil, fmt.Errorf(
+			"%w: title is required for a new locale (locale: %s)",
+			ErrInvalidProfileInput,
+			localeCode,
+		)
+	}
+
+	updated, err := s.repo.UpdateProfile(ctx, localeCode, profileID, slug, &changes)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToUpdateRecord, slug, err)
+	}
+
+	if updated == 0 {
+		return nil, fmt.Errorf("%w(slug: %s)", ErrProfileNotFound, slug)
+	}
+
+	s.invalidateProfile(ctx, slug)
+
+	if changes.Slug != nil && *changes.Slug != slug {
+		s.invalidateProfile(ctx, *changes.Slug)
+	}
+
+	record, err := s.repo.GetProfileByID(ctx, localeCode, profileID)
+	if err != nil {
+		return nil, fmt.Errorf("%w(profile_id: %s): %w", ErrFailedToGetRecord, profileID, err)
+	}
+
+	return record, nil
+}
+
+// Delete soft-deletes a profile, once it checks that the user owns it. The slug of a
+// deleted profile is not released.
+func (s *Service) Delete(ctx context.Context, userID string, slug string) error {
+	profileID, err := s.getOwnedProfileID(ctx, userID, slug)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.repo.DeleteProfile(ctx, profileID, slug)
+	if err != nil {
+		return fmt.Errorf("%w(slug: %s): %w", ErrFailedToDeleteRecord, slug, err)
+	}
+
+	if deleted == 0 {
+		return fmt.Errorf("%w(slug: %s)", ErrProfileNotFound, slug)
+	}
+
+	s.invalidateProfile(ctx, slug)
+
+	return nil
+}
+
+// getOwnedProfileID resolves the ID of the profile, and fails with ErrNotProfileOwner
+// unless it is the individual profile of the user or the user is one of its owners.
+func (s *Service) getOwnedProfileID(ctx context.Context, userID string, slug string) (string, error) {
+	profileID, err := s.repo.GetProfileIDBySlug(ctx, slug)
+	if err != nil {
+		return "", fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if profileID == "" {
+		return "", fmt.Errorf("%w(slug: %s)", ErrProfileNotFound, slug)
+	}
+
+	owned, err := s.repo.IsProfileOwnedByUser(ctx, profileID, userID)
+	if err != nil {
+		return "", fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if !owned {
+		return "", fmt.Errorf("%w(slug: %s)", ErrNotProfileOwner, slug)
+	}
+
+	return profileID, nil
+}
+
+// invalidateProfile drops the cached responses of a profile that changed. The change
+// is stored already, so a failure only delays it until the cached responses expire.
+func (s *Service) invalidateProfile(ctx context.Context, slug string) {
+	err := s.InvalidateProfile(ctx, slug)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to invalidate profile", "slug", slug, "error", err)
+	}
+}
+
+func validateSlug(slug string) error {
+	if len(slug) > MaxSlugLength || !slugPattern.MatchString(slug) {
+		return fmt.Errorf(
+			"%w: slug must be up to %d lowercase letters, digits and dashes (slug: %s)",
+			ErrInvalidProfileInput,
+			MaxSlugLength,
+			slug,
+		)
+	}
+
+	return nil
+}
+
+func validateCreateProfileInput(input *CreateProfileInput) error {
+	err := validateSlug(input.Slug)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(profileKinds, input.Kind) {
+		return fmt.Errorf("%w: kind is invalid (kind: %s)", ErrInvalidProfileInput, input.Kind)
+	}
 
-// 	return record, nil
-// }
+	if input.Title == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidProfileInput)
+	}
+
+	return nil
+}
+
+func validateUpdateProfileInput(input *UpdateProfileInput) error {
+	if input.Slug != nil {
+		err := validateSlug(*input.Slug)
+		if err != nil {
+			return err
+		}
+	}
+
+	if input.Title != nil && *input.Title == "" {
+		return fmt.Errorf("%w: title cannot be empty", ErrInvalidProfileInput)
+	}
+
+	return nil
+}
@@ -0,0 +1,270 @@
+package profiles_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	ownerUserID = "user-owner"
+	profileID   = "profile-1"
+)
+
+// fakeRepository keeps the profiles in memory. Every test starts with "acme", owned by
+// ownerUserID, and adds the profiles and records it needs to the maps.
+type fakeRepository struct {
+	profiles.Repository
+
+	profileIDs map[string]string // slug -> id
+	owners     map[string]string // profile id -> id of the owning user
+	// records holds "acme" by locale; a locale without one has no title yet.
+	records map[string]*profiles.Profile
+	created []*profiles.Profile
+	updates []*profiles.UpdateProfileInput
+
+	memberships map[string]string // member profile id -> kind of its membership of "acme"
+	invitation  *profiles.ProfileMembershipInvitation
+	accepted    bool
+
+	domains map[string]string // custom domain -> profile id
+	pending []*profiles.CustomDomainClaim
+	claims  []*profiles.CustomDomainClaim
+	checks  map[string]string // claim id -> status of its last check
+
+	individuals map[string]string // user id -> id of the individual profile of the user
+	follows     [][2]string       // follower profile id, followed profile id; oldest first
+
+	views []storedView
+
+	badges      []*storedBadge
+	ruleMatches map[string][]*profiles.BadgeHolder // badge kind -> profiles its rule holds for
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{ //nolint:exhaustruct
+		profileIDs: map[string]string{"acme": profileID},
+		owners:     map[string]string{profileID: ownerUserID},
+		records: map[string]*profiles.Profile{
+			"en": {ID: profileID, Slug: "acme", Title: "Acme", Description: "Tools"}, //nolint:exhaustruct
+		},
+		memberships: map[string]string{},
+		domains: map[string]string{
+			"acme.example.com":  profileID,
+			"other.example.com": "profile-other",
+		},
+		checks:      map[string]string{},
+		individuals: map[string]string{},
+		ruleMatches: map[string][]*profiles.BadgeHolder{},
+	}
+}
+
+func newTestService(repo profiles.Repository) *profiles.Service {
+	return profiles.NewService(logfx.NewLogger(logfx.WithWriter(io.Discard)), repo, nil)
+}
+
+func (r *fakeRepository) GetProfileIDBySlug(_ context.Context, slug string) (string, error) {
+	return r.profileIDs[slug], nil
+}
+
+func (r *fakeRepository) IsProfileOwnedByUser(
+	_ context.Context,
+	id string,
+	userID string,
+) (bool, error) {
+	owner, ok := r.owners[id]
+
+	return ok && owner == userID, nil
+}
+
+func (r *fakeRepository) GetProfileByID(
+	_ context.Context,
+	localeCode string,
+	_ string,
+) (*profiles.Profile, error) {
+	return r.records[localeCode], nil
+}
+
+func (r *fakeRepository) CreateProfile(
+	_ context.Context,
+	_ string,
+	profile *profiles.Profile,
+	_ string,
+	_ string,
+) error {
+	r.created = append(r.created, profile)
+
+	return nil
+}
+
+func (r *fakeRepository) UpdateProfile(
+	_ context.Context,
+	_ string,
+	_ string,
+	_ string,
+	input *profiles.UpdateProfileInput,
+) (int64, error) {
+	r.updates = append(r.updates, input)
+
+	return 1, nil
+}
+
+func TestServiceCreate(t *testing.T) {
+	t.Parallel()
+
+	createInput := func(slug string, kind string, title string) profiles.CreateProfileInput {
+		return profiles.CreateProfileInput{ //nolint:exhaustruct
+			Slug:  slug,
+			Kind:  kind,
+			Title: title,
+		}
+	}
+
+	tests := []struct {
+		expectedErr error
+		input       profiles.CreateProfileInput
+		name        string
+	}{
+		{
+			name:        "should create valid profiles",
+			input:       createInput("new-org", "organization", "New"),
+			expectedErr: nil,
+		},
+		{
+			name:        "should reject slugs with uppercase letters",
+			input:       createInput("New-Org", "organization", "New"),
+			expectedErr: profiles.ErrInvalidProfileInput,
+		},
+		{
+			name:        "should reject slugs with repeated dashes",
+			input:       createInput("new--org", "organization", "New"),
+			expectedErr: profiles.ErrInvalidProfileInput,
+		},
+		{
+			name:        "should reject slugs that are too long",
+			input:       createInput(strings.Repeat("a", profiles.MaxSlugLength+1), "organization", "New"),
+			expectedErr: profiles.ErrInvalidProfileInput,
+		},
+		{
+			name:        "should reject unknown kinds",
+			input:       createInput("new-org", "team", "New"),
+			expectedErr: profiles.ErrInvalidProfileInput,
+		},
+		{
+			name:        "should require a title",
+			input:       createInput("new-org", "organization", ""),
+			expectedErr: profiles.ErrInvalidProfileInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newFakeRepository()
+			service := newTestService(repo)
+
+			_, err := service.Create(t.Context(), "en", ownerUserID, &tt.input)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				assert.Empty(t, repo.created)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, repo.created, 1)
+			assert.Equal(t, tt.input.Slug, repo.created[0].Slug)
+		})
+	}
+}
+
+func TestServiceUpdate(t *testing.T) {
+	t.Parallel()
+
+	text := func(value string) *string { return &value }
+
+	tests := []struct {
+		expectedErr   error
+		input         profiles.UpdateProfileInput
+		expectedTitle *string
+		name          string
+		localeCode    string
+		userID        string
+	}{
+		{
+			name:       "should keep the title when the description changes",
+			localeCode: "en",
+			input: profiles.UpdateProfileInput{ //nolint:exhaustruct
+				Description: text("Better tools"),
+			},
+			expectedTitle: text("Acme"),
+		},
+		{
+			name:        "should require a title for a new locale",
+			localeCode:  "tr",
+			input:       profiles.UpdateProfileInput{Description: text("Araçlar")}, //nolint:exhaustruct
+			expectedErr: profiles.ErrInvalidProfileInput,
+		},
+		{
+			name:       "should add a new locale with a title",
+			localeCode: "tr",
+			input: profiles.UpdateProfileInput{ //nolint:exhaustruct
+				Title:       text("Acme"),
+				Description: text("Araçlar"),
+			},
+			expectedTitle: text("Acme"),
+		},
+		{
+			name:        "should reject empty titles",
+			localeCode:  "en",
+			input:       profiles.UpdateProfileInput{Title: text("")}, //nolint:exhaustruct
+			expectedErr: profiles.ErrInvalidProfileInput,
+		},
+		{
+			name:        "should reject invalid slugs",
+			localeCode:  "en",
+			input:       profiles.UpdateProfileInput{Slug: text("acme inc")}, //nolint:exhaustruct
+			expectedErr: profiles.ErrInvalidProfileInput,
+		},
+		{
+			name:        "should reject the users who do not own the profile",
+			localeCode:  "en",
+			userID:      "user-other",
+			input:       profiles.UpdateProfileInput{Title: text("Acme Inc")}, //nolint:exhaustruct
+			expectedErr: profiles.ErrNotProfileOwner,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newFakeRepository()
+			service := newTestService(repo)
+
+			userID := ownerUserID
+			if tt.userID != "" {
+				userID = tt.userID
+			}
+
+			_, err := service.Update(t.Context(), tt.localeCode, userID, "acme", &tt.input)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				assert.Empty(t, repo.updates)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, repo.updates, 1)
+			assert.Equal(t, tt.expectedTitle, repo.updates[0].Title)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package profiles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrSlugMoved = errors.New("slug moved")
+
+// SlugMovedError tells that a record is looked up with a slug it had before; Slug is
+// its current slug, which clients should use from then on.
+type SlugMovedError struct {
+	OldSlug string
+	Slug    string
+}
+
+func (e *SlugMovedError) Error() string {
+	return fmt.Sprintf("%s(slug: %s, current_slug: %s)", ErrSlugMoved, e.OldSlug, e.Slug)
+}
+
+func (e *SlugMovedError) Unwrap() error {
+	return ErrSlugMoved
+}
+
+// resolveProfileSlug returns the ID of the profile with slug, or a *SlugMovedError
+// when a profile had slug before. It returns "" when no profile has or had it.
+func (s *Service) resolveProfileSlug(ctx context.Context, slug string) (string, error) {
+	profileID, err := s.repo.GetProfileIDBySlug(ctx, slug)
+	if err != nil {
+		return "", fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if profileID != "" {
+		return profileID, nil
+	}
+
+	currentSlug, err := s.repo.GetProfileSlugRedirect(ctx, slug)
+	if err != nil {
+		return "", fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if currentSlug != "" && currentSlug != slug {
+		return "", &SlugMovedError{OldSlug: slug, Slug: currentSlug}
+	}
+
+	return "", nil
+}
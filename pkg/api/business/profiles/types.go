@@ -29,10 +29,32 @@ type Profile struct {
 	Description       string     `json:"description"`
 }
 
+// CreateProfileInput is the payload of creating a profile. Title and Description are
+// stored for the locale of the request.
+type CreateProfileInput struct {
+	ProfilePictureURI *string `json:"profile_picture_uri"`
+	Pronouns          *string `json:"pronouns"`
+	Slug              string  `json:"-"`
+	Kind              string  `json:"kind"`
+	Title             string  `json:"title"`
+	Description       string  `json:"description"`
+}
+
+// UpdateProfileInput is the payload of updating a profile. Only the fields that are set
+// change; Title and Description change for the locale of the request.
+type UpdateProfileInput struct {
+	Slug              *string `json:"slug"`
+	ProfilePictureURI *string `json:"profile_picture_uri"`
+	Pronouns          *string `json:"pronouns"`
+	Title             *string `json:"title"`
+	Description       *string `json:"description"`
+}
+
 type ProfileWithChildren struct {
 	*Profile
-	Pages []*ProfilePageBrief `json:"pages"`
-	Links []*ProfileLinkBrief `json:"links"`
+	Pages  []*ProfilePageBrief `json:"pages"`
+	Links  []*ProfileLinkBrief `json:"links"`
+	Badges []*ProfileBadge     `json:"badges"`
 }
 
 type ProfilePage struct {
@@ -72,9 +94,194 @@ type ProfileMembership struct {
 	Kind          string     `json:"kind"`
 }
 
+// ProfileFollow is a follow relationship; Profile is the follower or the followed
+// profile, depending on the listing.
+type ProfileFollow struct {
+	CreatedAt time.Time `json:"created_at"`
+	Profile   *Profile  `json:"profile"`
+	ID        string    `json:"id"`
+}
+
+// ProfileBadge is a badge a profile holds, granted by an admin or awarded by a rule.
+type ProfileBadge struct {
+	GrantedAt time.Time `json:"granted_at"`
+	Note      *string   `json:"note"`
+	ID        string    `json:"id"`
+	ProfileID string    `json:"profile_id"`
+	Kind      string    `json:"kind"`
+	Source    string    `json:"source"`
+}
+
+// BadgeHolder is a profile an award rule holds for, or that holds a badge.
+type BadgeHolder struct {
+	ProfileID   string
+	ProfileSlug string
+}
+
+// ProfileMembershipInvitation invites a member profile to join a profile. Accepting it
+// requires the token that was returned when it was created.
+type ProfileMembershipInvitation struct {
+	CreatedAt         time.Time  `json:"created_at"`
+	ExpiresAt         time.Time  `json:"expires_at"`
+	AcceptedAt        *time.Time `json:"accepted_at"`
+	ID                string     `json:"id"`
+	ProfileID         string     `json:"profile_id"`
+	ProfileSlug       string     `json:"profile_slug"`
+	MemberProfileID   string     `json:"member_profile_id"`
+	MemberProfileSlug string     `json:"member_profile_slug"`
+	Kind              string     `json:"kind"`
+	InvitedByUserID   string     `json:"invited_by_user_id"`
+}
+
+// InviteMemberInput is the payload of inviting a member profile.
+type InviteMemberInput struct {
+	MemberProfileSlug string `json:"member_profile_slug"`
+	Kind              string `json:"kind"`
+}
+
+// CustomDomainClaim is a request to serve a profile on a custom domain, which becomes
+// the custom domain of the profile once its DNS challenge is verified.
+type CustomDomainClaim struct {
+	CreatedAt     time.Time  `json:"created_at"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	LastCheckedAt *time.Time `json:"last_checked_at"`
+	VerifiedAt    *time.Time `json:"verified_at"`
+	LastError     *string    `json:"last_error"`
+	// CurrentDomain is the custom domain of the profile when the claim was read.
+	CurrentDomain  *string `json:"-"`
+	ID             string  `json:"id"`
+	ProfileID      string  `json:"profile_id"`
+	ProfileSlug    string  `json:"profile_slug"`
+	Domain         string  `json:"domain"`
+	ChallengeToken string  `json:"-"`
+	Status         string  `json:"status"`
+	Attempts       int     `json:"attempts"`
+}
+
 type ExternalPost struct {
 	CreatedAt *time.Time `json:"created_at"` //nolint:tagliatelle
 	ID        string     `json:"id"`
 	Content   string     `json:"content"`
 	Permalink string     `json:"permalink"`
 }
+
+// ViewSummary counts the views of a profile and of its stories between two days, both
+// included. A visitor counts once a day for each of them.
+type ViewSummary struct {
+	From          string `json:"from"`
+	To            string `json:"to"`
+	ProfileViews  int64  `json:"profile_views"`
+	StoryViews    int64  `json:"story_views"`
+	ViewedStories int64  `json:"viewed_stories"`
+}
+
+// DailyViews counts the views of a profile and of its stories on a day.
+type DailyViews struct {
+	Date         string `json:"date"`
+	ProfileViews int64  `json:"profile_views"`
+	StoryViews   int64  `json:"story_views"`
+}
+
+// ProfileExport is a request to archive the data of a profile. DownloadURL is set
+// when the archive is ready, for a limited time.
+type ProfileExport struct {
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	LastError   *string    `json:"last_error"`
+	SizeBytes   *int64     `json:"size_bytes"`
+	ObjectKey   *string    `json:"-"`
+	ID          string     `json:"id"`
+	ProfileID   string     `json:"profile_id"`
+	ProfileSlug string     `json:"profile_slug"`
+	Status      string     `json:"status"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	Attempts    int        `json:"attempts"`
+}
+
+// ProfileExportData is the data of a profile that its export archives, in every
+// locale. Link credentials are left out.
+type ProfileExportData struct {
+	ExportedAt  time.Time             `json:"exported_at"`
+	Profile     *ExportedProfile      `json:"profile"`
+	Pages       []*ExportedPage       `json:"pages"`
+	Links       []*ExportedLink       `json:"links"`
+	Stories     []*ExportedStory      `json:"stories"`
+	Memberships []*ExportedMembership `json:"memberships"`
+}
+
+type ExportedProfile struct {
+	CreatedAt         time.Time                     `json:"created_at"`
+	Properties        any                           `json:"properties"`
+	CustomDomain      *string                       `json:"custom_domain"`
+	ProfilePictureURI *string                       `json:"profile_picture_uri"`
+	Pronouns          *string                       `json:"pronouns"`
+	UpdatedAt         *time.Time                    `json:"updated_at"`
+	ID                string                        `json:"id"`
+	Slug              string                        `json:"slug"`
+	Kind              string                        `json:"kind"`
+	Translations      []*ExportedProfileTranslation `json:"translations"`
+}
+
+type ExportedProfileTranslation struct {
+	Properties  any    `json:"properties"`
+	LocaleCode  string `json:"locale_code"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// ExportedTranslation is the content of a page or a story in a locale.
+type ExportedTranslation struct {
+	LocaleCode string `json:"locale_code"`
+	Title      string `json:"title"`
+	Summary    string `json:"summary"`
+	Content    string `json:"content"`
+}
+
+type ExportedPage struct {
+	CreatedAt       time.Time              `json:"created_at"`
+	CoverPictureURI *string                `json:"cover_picture_uri"`
+	PublishedAt     *time.Time             `json:"published_at"`
+	UpdatedAt       *time.Time             `json:"updated_at"`
+	ID              string                 `json:"id"`
+	Slug            string                 `json:"slug"`
+	Translations    []*ExportedTranslation `json:"translations"`
+	Order           int                    `json:"order"`
+}
+
+type ExportedLink struct {
+	CreatedAt  time.Time `json:"created_at"`
+	PublicID   *string   `json:"public_id"`
+	URI        *string   `json:"uri"`
+	ID         string    `json:"id"`
+	Kind       string    `json:"kind"`
+	Title      string    `json:"title"`
+	Order      int       `json:"order"`
+	IsVerified bool      `json:"is_verified"`
+	IsHidden   bool      `json:"is_hidden"`
+}
+
+type ExportedStory struct {
+	CreatedAt       time.Time              `json:"created_at"`
+	Properties      any                    `json:"properties"`
+	StoryPictureURI *string                `json:"story_picture_uri"`
+	UpdatedAt       *time.Time             `json:"updated_at"`
+	ID              string                 `json:"id"`
+	Slug            string                 `json:"slug"`
+	Kind            string                 `json:"kind"`
+	Status          string                 `json:"status"`
+	Translations    []*ExportedTranslation `json:"translations"`
+	IsFeatured      bool                   `json:"is_featured"`
+}
+
+// ExportedMembership is a membership in the profile, or of the profile in another.
+type ExportedMembership struct {
+	CreatedAt         time.Time  `json:"created_at"`
+	StartedAt         *time.Time `json:"started_at"`
+	FinishedAt        *time.Time `json:"finished_at"`
+	ID                string     `json:"id"`
+	ProfileSlug       string     `json:"profile_slug"`
+	MemberProfileSlug string     `json:"member_profile_slug"`
+	Kind              string     `json:"kind"`
+}
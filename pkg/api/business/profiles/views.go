@@ -0,0 +1,251 @@
+package profiles
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	ViewKindProfile = "profile"
+	ViewKindStory   = "story"
+
+	// DefaultViewRangeDays is the number of days, today included, a view range spans
+	// when it is not given.
+	DefaultViewRangeDays = 30
+	// MaxViewRangeDays bounds the number of days of a view range.
+	MaxViewRangeDays = 366
+)
+
+var (
+	ErrInvalidViewEvent    = errors.New("invalid view event")
+	ErrInvalidViewRange    = errors.New("invalid view range")
+	ErrFailedToPublishView = errors.New("failed to publish view")
+)
+
+// ViewQueue carries the views to be stored; connfx.QueueRepository satisfies it.
+type ViewQueue interface {
+	Publish(ctx context.Context, queueName string, body []byte) error
+}
+
+// ViewEvent is a view of a profile or a story, queued by RecordView.
+type ViewEvent struct {
+	ViewedAt    time.Time `json:"viewed_at"`
+	Kind        string    `json:"kind"`
+	Slug        string    `json:"slug"`
+	VisitorHash string    `json:"visitor_hash"`
+}
+
+// ViewRange is a range of days; both From and To are included.
+type ViewRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// ParseViewRange parses the days of a range in the YYYY-MM-DD format. An empty to is
+// today, and an empty from spans DefaultViewRangeDays until to.
+func ParseViewRange(from string, to string) (ViewRange, error) {
+	viewRange := ViewRange{From: time.Time{}, To: viewDay(time.Now())}
+
+	if to != "" {
+		day, err := time.Parse(time.DateOnly, to)
+		if err != nil {
+			return ViewRange{}, fmt.Errorf("%w(to: %s): %w", ErrInvalidViewRange, to, err)
+		}
+
+		viewRange.To = day
+	}
+
+	viewRange.From = viewRange.To.AddDate(0, 0, 1-DefaultViewRangeDays)
+
+	if from != "" {
+		day, err := time.Parse(time.DateOnly, from)
+		if err != nil {
+			return ViewRange{}, fmt.Errorf("%w(from: %s): %w", ErrInvalidViewRange, from, err)
+		}
+
+		viewRange.From = day
+	}
+
+	if viewRange.To.Before(viewRange.From) ||
+		viewRange.From.AddDate(0, 0, MaxViewRangeDays).Before(viewRange.To.AddDate(0, 0, 1)) {
+		return ViewRange{}, fmt.Errorf(
+			"%w: from must precede to by up to %d days (from: %s, to: %s)",
+			ErrInvalidViewRange,
+			MaxViewRangeDays,
+			viewRange.From.Format(time.DateOnly),
+			viewRange.To.Format(time.DateOnly),
+		)
+	}
+
+	return viewRange, nil
+}
+
+// SetViewQueue records the views of profiles and stories through queueName of queue.
+// Visitors are hashed with visitorSalt. Views are not recorded until it is set.
+func (s *Service) SetViewQueue(queue ViewQueue, queueName string, visitorSalt string) {
+	s.viewQueue = queue
+	s.viewQueueName = queueName
+	s.viewVisitorSalt = []byte(visitorSalt)
+}
+
+// RecordView queues a view of the profile or the story with slug. visitor identifies
+// the viewer, e.g. with a user ID or an address; it is kept as a hash that changes
+// daily, so a visitor counts once a day and cannot be followed across days.
+func (s *Service) RecordView(ctx context.Context, kind string, slug string, visitor string) error {
+	if s.viewQueue == nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	event := &ViewEvent{
+		ViewedAt:    now,
+		Kind:        kind,
+		Slug:        slug,
+		VisitorHash: s.hashVisitor(viewDay(now), visitor),
+	}
+
+	err := validateViewEvent(event)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("%w(slug: %s): %w", ErrFailedToPublishView, slug, err)
+	}
+
+	err = s.viewQueue.Publish(ctx, s.viewQueueName, body)
+	if err != nil {
+		return fmt.Errorf("%w(slug: %s): %w", ErrFailedToPublishView, slug, err)
+	}
+
+	return nil
+}
+
+// StoreView stores a view queued by RecordView. Repeated views of a visitor on the
+// same day, and views of records that no longer exist, are dropped. Views that
+// cannot be decoded fail with ErrInvalidViewEvent.
+func (s *Service) StoreView(ctx context.Context, body []byte) error {
+	var event ViewEvent
+
+	err := json.Unmarshal(body, &event)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidViewEvent, err)
+	}
+
+	err = validateViewEvent(&event)
+	if err != nil {
+		return err
+	}
+
+	id := string(s.idGenerator())
+	viewedOn := viewDay(event.ViewedAt)
+
+	if event.Kind == ViewKindStory {
+		_, err = s.repo.RecordStoryView(ctx, id, event.Slug, event.VisitorHash, viewedOn)
+	} else {
+		_, err = s.repo.RecordProfileView(ctx, id, event.Slug, event.VisitorHash, viewedOn)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w(slug: %s): %w", ErrFailedToCreateRecord, event.Slug, err)
+	}
+
+	return nil
+}
+
+// GetViewSummary counts the views of the profile the user owns, and of its stories,
+// in viewRange.
+func (s *Service) GetViewSummary(
+	ctx context.Context,
+	userID string,
+	slug string,
+	viewRange ViewRange,
+) (*ViewSummary, error) {
+	profileID, err := s.getOwnedProfileID(ctx, userID, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.repo.GetProfileViewSummary(ctx, profileID, viewRange.From, viewRange.To)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	return summary, nil
+}
+
+// ListDailyViews counts the views of the profile the user owns, and of its stories,
+// for each day of viewRange, the days without views included.
+func (s *Service) ListDailyViews(
+	ctx context.Context,
+	userID string,
+	slug string,
+	viewRange ViewRange,
+) ([]*DailyViews, error) {
+	profileID, err := s.getOwnedProfileID(ctx, userID, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.repo.ListProfileViewsByDay(ctx, profileID, viewRange.From, viewRange.To)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToListRecords, slug, err)
+	}
+
+	recordsByDate := make(map[string]*DailyViews, len(records))
+	for _, record := range records {
+		recordsByDate[record.Date] = record
+	}
+
+	days := make([]*DailyViews, 0, len(records))
+
+	for day := viewRange.From; !day.After(viewRange.To); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+
+		record, ok := recordsByDate[date]
+		if !ok {
+			record = &DailyViews{Date: date, ProfileViews: 0, StoryViews: 0}
+		}
+
+		days = append(days, record)
+	}
+
+	return days, nil
+}
+
+func (s *Service) hashVisitor(day time.Time, visitor string) string {
+	mac := hmac.New(sha256.New, s.viewVisitorSalt)
+	mac.Write([]byte(day.Format(time.DateOnly) + "|" + visitor))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func validateViewEvent(event *ViewEvent) error {
+	if event.Kind != ViewKindProfile && event.Kind != ViewKindStory {
+		return fmt.Errorf("%w(kind: %s)", ErrInvalidViewEvent, event.Kind)
+	}
+
+	if event.Slug == "" || event.VisitorHash == "" || event.ViewedAt.IsZero() {
+		return fmt.Errorf(
+			"%w: slug, visitor_hash and viewed_at are required (slug: %s)",
+			ErrInvalidViewEvent,
+			event.Slug,
+		)
+	}
+
+	return nil
+}
+
+// viewDay is the day of t in UTC, which views are counted by.
+func viewDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
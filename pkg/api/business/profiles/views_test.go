@@ -0,0 +1,293 @@
+package profiles_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	viewQueueName   = "views"
+	viewVisitorSalt = "salt"
+)
+
+// storedView is a view stored through the repository. The storage adapter stores a
+// view once per kind, slug, visitor hash and day.
+type storedView struct {
+	viewedOn    time.Time
+	kind        string
+	slug        string
+	visitorHash string
+}
+
+// fakeViewQueue keeps the published views.
+type fakeViewQueue struct {
+	bodies [][]byte
+}
+
+func (q *fakeViewQueue) Publish(_ context.Context, queueName string, body []byte) error {
+	if queueName != viewQueueName {
+		return nil
+	}
+
+	q.bodies = append(q.bodies, body)
+
+	return nil
+}
+
+func (r *fakeRepository) RecordProfileView(
+	_ context.Context,
+	_ string,
+	slug string,
+	visitorHash string,
+	viewedOn time.Time,
+) (int64, error) {
+	return r.storeView(storedView{viewedOn, profiles.ViewKindProfile, slug, visitorHash}), nil
+}
+
+func (r *fakeRepository) RecordStoryView(
+	_ context.Context,
+	_ string,
+	slug string,
+	visitorHash string,
+	viewedOn time.Time,
+) (int64, error) {
+	return r.storeView(storedView{viewedOn, profiles.ViewKindStory, slug, visitorHash}), nil
+}
+
+func (r *fakeRepository) storeView(view storedView) int64 {
+	for _, stored := range r.views {
+		if stored == view {
+			return 0
+		}
+	}
+
+	r.views = append(r.views, view)
+
+	return 1
+}
+
+func TestParseViewRange(t *testing.T) {
+	t.Parallel()
+
+	day := func(value string) time.Time {
+		parsed, err := time.Parse(time.DateOnly, value)
+		require.NoError(t, err)
+
+		return parsed
+	}
+
+	tests := []struct {
+		expected    profiles.ViewRange
+		expectedErr error
+		name        string
+		from        string
+		to          string
+	}{
+		{
+			name:     "default span until to",
+			to:       "2025-03-31",
+			expected: profiles.ViewRange{From: day("2025-03-02"), To: day("2025-03-31")},
+		},
+		{
+			name:     "single day",
+			from:     "2025-03-31",
+			to:       "2025-03-31",
+			expected: profiles.ViewRange{From: day("2025-03-31"), To: day("2025-03-31")},
+		},
+		{
+			name:     "366 days",
+			from:     "2024-01-01",
+			to:       "2024-12-31",
+			expected: profiles.ViewRange{From: day("2024-01-01"), To: day("2024-12-31")},
+		},
+		{
+			name:        "367 days",
+			from:        "2024-01-01",
+			to:          "2025-01-01",
+			expectedErr: profiles.ErrInvalidViewRange,
+		},
+		{
+			name:        "from after to",
+			from:        "2025-04-01",
+			to:          "2025-03-31",
+			expectedErr: profiles.ErrInvalidViewRange,
+		},
+		{
+			name:        "malformed from",
+			from:        "01/04/2025",
+			to:          "2025-03-31",
+			expectedErr: profiles.ErrInvalidViewRange,
+		},
+		{
+			name:        "malformed to",
+			to:          "yesterday",
+			expectedErr: profiles.ErrInvalidViewRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			viewRange, err := profiles.ParseViewRange(tt.from, tt.to)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, viewRange)
+		})
+	}
+
+	t.Run("default range ends today", func(t *testing.T) {
+		t.Parallel()
+
+		viewRange, err := profiles.ParseViewRange("", "")
+		require.NoError(t, err)
+
+		today := time.Now().UTC().Format(time.DateOnly)
+		assert.Equal(t, today, viewRange.To.Format(time.DateOnly))
+		assert.Equal(t, viewRange.To.AddDate(0, 0, 1-profiles.DefaultViewRangeDays), viewRange.From)
+	})
+}
+
+func TestServiceRecordView(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should not record views without a queue", func(t *testing.T) {
+		t.Parallel()
+
+		service := newTestService(newFakeRepository())
+
+		err := service.RecordView(t.Context(), profiles.ViewKindProfile, "acme", "user:1")
+		require.NoError(t, err)
+	})
+
+	t.Run("should hash visitors with the salt and the day", func(t *testing.T) {
+		t.Parallel()
+
+		queue := &fakeViewQueue{} //nolint:exhaustruct
+		service := newTestService(newFakeRepository())
+		service.SetViewQueue(queue, viewQueueName, viewVisitorSalt)
+
+		for _, visitor := range []string{"user:1", "user:1", "user:2"} {
+			err := service.RecordView(t.Context(), profiles.ViewKindProfile, "acme", visitor)
+			require.NoError(t, err)
+		}
+
+		events := decodeViewEvents(t, queue.bodies)
+		require.Len(t, events, 3)
+
+		// A visitor hashes the same within a day, and differently on the next one.
+		today := time.Now().UTC()
+		assert.Equal(t, hashVisitor(today, "user:1"), events[0].VisitorHash)
+		assert.Equal(t, events[0].VisitorHash, events[1].VisitorHash)
+		assert.NotEqual(t, hashVisitor(today.AddDate(0, 0, 1), "user:1"), events[0].VisitorHash)
+		assert.NotEqual(t, events[0].VisitorHash, events[2].VisitorHash)
+		assert.NotContains(t, string(queue.bodies[0]), "user:1")
+	})
+
+	t.Run("should reject unknown kinds", func(t *testing.T) {
+		t.Parallel()
+
+		queue := &fakeViewQueue{} //nolint:exhaustruct
+		service := newTestService(newFakeRepository())
+		service.SetViewQueue(queue, viewQueueName, viewVisitorSalt)
+
+		err := service.RecordView(t.Context(), "page", "acme", "user:1")
+		require.ErrorIs(t, err, profiles.ErrInvalidViewEvent)
+		assert.Empty(t, queue.bodies)
+	})
+}
+
+func TestServiceStoreView(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should store a visitor once a day", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newFakeRepository()
+		queue := &fakeViewQueue{} //nolint:exhaustruct
+		service := newTestService(repo)
+		service.SetViewQueue(queue, viewQueueName, viewVisitorSalt)
+
+		views := []struct{ kind, visitor string }{
+			{profiles.ViewKindProfile, "user:1"},
+			{profiles.ViewKindProfile, "user:1"},
+			{profiles.ViewKindProfile, "user:2"},
+			{profiles.ViewKindStory, "user:1"},
+		}
+
+		for _, view := range views {
+			require.NoError(t, service.RecordView(t.Context(), view.kind, "acme", view.visitor))
+		}
+
+		for _, body := range queue.bodies {
+			require.NoError(t, service.StoreView(t.Context(), body))
+		}
+
+		require.Len(t, repo.views, 3)
+		assert.Equal(t, profiles.ViewKindProfile, repo.views[0].kind)
+		assert.Equal(t, profiles.ViewKindProfile, repo.views[1].kind)
+		assert.Equal(t, profiles.ViewKindStory, repo.views[2].kind)
+
+		today := time.Now().UTC().Format(time.DateOnly)
+		assert.Equal(t, today, repo.views[0].viewedOn.Format(time.DateOnly))
+	})
+
+	t.Run("should reject invalid events", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newFakeRepository()
+		service := newTestService(repo)
+
+		bodies := []string{
+			`not json`,
+			`{"kind":"page","slug":"acme","visitor_hash":"h","viewed_at":"2025-03-31T10:00:00Z"}`,
+			`{"kind":"profile","slug":"","visitor_hash":"h","viewed_at":"2025-03-31T10:00:00Z"}`,
+			`{"kind":"profile","slug":"acme","visitor_hash":"","viewed_at":"2025-03-31T10:00:00Z"}`,
+			`{"kind":"profile","slug":"acme","visitor_hash":"h"}`,
+		}
+
+		for _, body := range bodies {
+			err := service.StoreView(t.Context(), []byte(body))
+			require.ErrorIs(t, err, profiles.ErrInvalidViewEvent, body)
+		}
+
+		assert.Empty(t, repo.views)
+	})
+}
+
+func decodeViewEvents(t *testing.T, bodies [][]byte) []*profiles.ViewEvent {
+	t.Helper()
+
+	events := make([]*profiles.ViewEvent, 0, len(bodies))
+
+	for _, body := range bodies {
+		var event profiles.ViewEvent
+
+		require.NoError(t, json.Unmarshal(body, &event))
+
+		events = append(events, &event)
+	}
+
+	return events
+}
+
+func hashVisitor(day time.Time, visitor string) string {
+	mac := hmac.New(sha256.New, []byte(viewVisitorSalt))
+	mac.Write([]byte(day.Format(time.DateOnly) + "|" + visitor))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
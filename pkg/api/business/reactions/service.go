@@ -0,0 +1,168 @@
+package reactions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+)
+
+const (
+	TargetKindStory   = "story"
+	TargetKindComment = "comment"
+
+	KindLike       = "like"
+	KindLove       = "love"
+	KindCelebrate  = "celebrate"
+	KindInsightful = "insightful"
+)
+
+var (
+	ErrFailedToListRecords  = errors.New("failed to list records")
+	ErrFailedToCreateRecord = errors.New("failed to create record")
+	ErrFailedToDeleteRecord = errors.New("failed to delete record")
+
+	ErrInvalidReactionKind = errors.New("invalid reaction kind")
+)
+
+// Kinds lists the kinds of reactions a user may react with.
+var Kinds = []string{KindLike, KindLove, KindCelebrate, KindInsightful} //nolint:gochecknoglobals
+
+type Repository interface {
+	// AddReaction stores the reaction and counts it, unless the user reacted with the
+	// kind to the target already; it reports whether the reaction is stored.
+	AddReaction(ctx context.Context, reaction *Reaction) (bool, error)
+	// RemoveReaction removes the reaction of the user and uncounts it; it reports
+	// whether there was one.
+	RemoveReaction(
+		ctx context.Context,
+		targetKind string,
+		targetID string,
+		userID string,
+		kind string,
+	) (bool, error)
+	// ListReactionCounts returns the counts of the targets by their ids, the most
+	// reacted kinds first. Targets without reactions are left out.
+	ListReactionCounts(
+		ctx context.Context,
+		targetKind string,
+		targetIDs []string,
+	) (map[string][]*Count, error)
+}
+
+type Service struct {
+	logger      *logfx.Logger
+	repo        Repository
+	idGenerator RecordIDGenerator
+}
+
+func NewService(logger *logfx.Logger, repo Repository) *Service {
+	return &Service{logger: logger, repo: repo, idGenerator: DefaultIDGenerator}
+}
+
+// Set adds the reaction of the user of the kind to the target when reacted is true, or
+// removes it otherwise, and returns the counts of the target. Adding a reaction twice,
+// or removing one that is not there, leaves the counts as they are.
+func (s *Service) Set(
+	ctx context.Context,
+	targetKind string,
+	targetID string,
+	userID string,
+	kind string,
+	reacted bool,
+) ([]*Count, error) {
+	if !slices.Contains(Kinds, kind) {
+		return nil, fmt.Errorf("%w(kind: %s)", ErrInvalidReactionKind, kind)
+	}
+
+	if reacted {
+		added, err := s.repo.AddReaction(ctx, &Reaction{
+			CreatedAt:  time.Now(),
+			ID:         string(s.idGenerator()),
+			TargetKind: targetKind,
+			TargetID:   targetID,
+			UserID:     userID,
+			Kind:       kind,
+		})
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w(%s_id: %s, kind: %s): %w",
+				ErrFailedToCreateRecord,
+				targetKind,
+				targetID,
+				kind,
+				err,
+			)
+		}
+
+		if added {
+			s.logger.InfoContext(
+				ctx,
+				"reaction added",
+				"target_kind", targetKind,
+				"target_id", targetID,
+				"kind", kind,
+			)
+		}
+	} else {
+		removed, err := s.repo.RemoveReaction(ctx, targetKind, targetID, userID, kind)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w(%s_id: %s, kind: %s): %w",
+				ErrFailedToDeleteRecord,
+				targetKind,
+				targetID,
+				kind,
+				err,
+			)
+		}
+
+		if removed {
+			s.logger.InfoContext(
+				ctx,
+				"reaction removed",
+				"target_kind", targetKind,
+				"target_id", targetID,
+				"kind", kind,
+			)
+		}
+	}
+
+	counts, err := s.CountsOf(ctx, targetKind, []string{targetID})
+	if err != nil {
+		return nil, err
+	}
+
+	return counts[targetID], nil
+}
+
+// CountsOf returns the counts of the targets by their ids. Each of them has an entry,
+// empty when it has no reactions.
+func (s *Service) CountsOf(
+	ctx context.Context,
+	targetKind string,
+	targetIDs []string,
+) (map[string][]*Count, error) {
+	result := make(map[string][]*Count, len(targetIDs))
+
+	if len(targetIDs) == 0 {
+		return result, nil
+	}
+
+	counts, err := s.repo.ListReactionCounts(ctx, targetKind, targetIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%w(target_kind: %s): %w", ErrFailedToListRecords, targetKind, err)
+	}
+
+	for _, targetID := range targetIDs {
+		result[targetID] = counts[targetID]
+		if result[targetID] == nil {
+			result[targetID] = []*Count{}
+		}
+	}
+
+	return result, nil
+}
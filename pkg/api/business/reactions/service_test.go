@@ -0,0 +1,241 @@
+package reactions_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/api/business/reactions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	storyID     = "story-1"
+	readerID    = "user-reader"
+	otherUserID = "user-other"
+)
+
+var errStoreUnavailable = errors.New("store unavailable")
+
+// fakeRepository keeps the reactions and their counts in memory, and counts a reaction
+// only when it is stored, like the storage adapter.
+type fakeRepository struct {
+	reactions []*reactions.Reaction
+	counts    map[string]int64 // target id + "/" + kind -> count
+	err       error
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{reactions: nil, counts: map[string]int64{}, err: nil}
+}
+
+func newTestService(repo reactions.Repository) *reactions.Service {
+	return reactions.NewService(logfx.NewLogger(logfx.WithWriter(io.Discard)), repo)
+}
+
+func (r *fakeRepository) AddReaction(
+	_ context.Context,
+	reaction *reactions.Reaction,
+) (bool, error) {
+	if r.err != nil {
+		return false, r.err
+	}
+
+	if r.indexOf(reaction.TargetKind, reaction.TargetID, reaction.UserID, reaction.Kind) >= 0 {
+		return false, nil
+	}
+
+	r.reactions = append(r.reactions, reaction)
+	r.counts[reaction.TargetID+"/"+reaction.Kind]++
+
+	return true, nil
+}
+
+func (r *fakeRepository) RemoveReaction(
+	_ context.Context,
+	targetKind string,
+	targetID string,
+	userID string,
+	kind string,
+) (bool, error) {
+	if r.err != nil {
+		return false, r.err
+	}
+
+	index := r.indexOf(targetKind, targetID, userID, kind)
+	if index < 0 {
+		return false, nil
+	}
+
+	r.reactions = slices.Delete(r.reactions, index, index+1)
+	r.counts[targetID+"/"+kind]--
+
+	return true, nil
+}
+
+func (r *fakeRepository) ListReactionCounts(
+	_ context.Context,
+	_ string,
+	targetIDs []string,
+) (map[string][]*reactions.Count, error) {
+	result := map[string][]*reactions.Count{}
+
+	for _, targetID := range targetIDs {
+		for _, kind := range reactions.Kinds {
+			if count := r.counts[targetID+"/"+kind]; count > 0 {
+				result[targetID] = append(result[targetID], &reactions.Count{Kind: kind, Count: count})
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (r *fakeRepository) indexOf(targetKind, targetID, userID, kind string) int {
+	return slices.IndexFunc(r.reactions, func(reaction *reactions.Reaction) bool {
+		return reaction.TargetKind == targetKind && reaction.TargetID == targetID &&
+			reaction.UserID == userID && reaction.Kind == kind
+	})
+}
+
+func TestServiceSet(t *testing.T) {
+	t.Parallel()
+
+	set := func(
+		t *testing.T,
+		service *reactions.Service,
+		userID string,
+		kind string,
+		reacted bool,
+	) []*reactions.Count {
+		t.Helper()
+
+		counts, err := service.Set(t.Context(), reactions.TargetKindStory, storyID, userID, kind, reacted)
+		require.NoError(t, err)
+
+		return counts
+	}
+
+	t.Run("should count a reaction once when it is set twice", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newFakeRepository()
+		service := newTestService(repo)
+
+		set(t, service, readerID, reactions.KindLike, true)
+		counts := set(t, service, readerID, reactions.KindLike, true)
+
+		assert.Equal(t, []*reactions.Count{{Kind: reactions.KindLike, Count: 1}}, counts)
+		assert.Len(t, repo.reactions, 1)
+	})
+
+	t.Run("should count the reactions of each user and kind", func(t *testing.T) {
+		t.Parallel()
+
+		service := newTestService(newFakeRepository())
+
+		set(t, service, readerID, reactions.KindLike, true)
+		set(t, service, otherUserID, reactions.KindLike, true)
+		counts := set(t, service, readerID, reactions.KindLove, true)
+
+		assert.Equal(t, []*reactions.Count{
+			{Kind: reactions.KindLike, Count: 2},
+			{Kind: reactions.KindLove, Count: 1},
+		}, counts)
+	})
+
+	t.Run("should toggle a reaction off", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newFakeRepository()
+		service := newTestService(repo)
+
+		set(t, service, readerID, reactions.KindLike, true)
+		set(t, service, otherUserID, reactions.KindLike, true)
+
+		counts := set(t, service, readerID, reactions.KindLike, false)
+		assert.Equal(t, []*reactions.Count{{Kind: reactions.KindLike, Count: 1}}, counts)
+
+		// removing a reaction that is not there leaves the counts as they are.
+		counts = set(t, service, readerID, reactions.KindLike, false)
+		assert.Equal(t, []*reactions.Count{{Kind: reactions.KindLike, Count: 1}}, counts)
+
+		counts = set(t, service, otherUserID, reactions.KindLike, false)
+		assert.Empty(t, counts)
+		assert.NotNil(t, counts)
+		assert.Empty(t, repo.reactions)
+	})
+
+	t.Run("should reject unknown kinds", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newFakeRepository()
+		service := newTestService(repo)
+
+		_, err := service.Set(t.Context(), reactions.TargetKindStory, storyID, readerID, "dislike", true)
+		require.ErrorIs(t, err, reactions.ErrInvalidReactionKind)
+		assert.Empty(t, repo.reactions)
+	})
+
+	t.Run("should report repository errors", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newFakeRepository()
+		repo.err = errStoreUnavailable
+		service := newTestService(repo)
+
+		_, err := service.Set(
+			t.Context(),
+			reactions.TargetKindStory,
+			storyID,
+			readerID,
+			reactions.KindLike,
+			true,
+		)
+		require.ErrorIs(t, err, reactions.ErrFailedToCreateRecord)
+		require.ErrorIs(t, err, errStoreUnavailable)
+
+		_, err = service.Set(
+			t.Context(),
+			reactions.TargetKindStory,
+			storyID,
+			readerID,
+			reactions.KindLike,
+			false,
+		)
+		require.ErrorIs(t, err, reactions.ErrFailedToDeleteRecord)
+	})
+}
+
+func TestServiceCountsOf(t *testing.T) {
+	t.Parallel()
+
+	service := newTestService(newFakeRepository())
+
+	_, err := service.Set(
+		t.Context(),
+		reactions.TargetKindStory,
+		storyID,
+		readerID,
+		reactions.KindLike,
+		true,
+	)
+	require.NoError(t, err)
+
+	counts, err := service.CountsOf(
+		t.Context(),
+		reactions.TargetKindStory,
+		[]string{storyID, "story-2"},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []*reactions.Count{{Kind: reactions.KindLike, Count: 1}}, counts[storyID])
+	assert.Equal(t, []*reactions.Count{}, counts["story-2"])
+
+	counts, err = service.CountsOf(t.Context(), reactions.TargetKindStory, nil)
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+}
@@ -0,0 +1,32 @@
+package reactions
+
+import (
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/lib"
+)
+
+type RecordID string
+
+type RecordIDGenerator func() RecordID
+
+func DefaultIDGenerator() RecordID {
+	return RecordID(lib.IDsGenerateUnique())
+}
+
+// Reaction is a reaction of a user of one kind to a story or a comment. A user reacts
+// with each kind once.
+type Reaction struct {
+	CreatedAt  time.Time `json:"created_at"`
+	ID         string    `json:"id"`
+	TargetKind string    `json:"target_kind"`
+	TargetID   string    `json:"target_id"`
+	UserID     string    `json:"user_id"`
+	Kind       string    `json:"kind"`
+}
+
+// Count is the number of reactions of one kind to a story or a comment.
+type Count struct {
+	Kind  string `json:"kind"`
+	Count int64  `json:"count"`
+}
@@ -0,0 +1,212 @@
+package sitemaps
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+)
+
+const (
+	// MaxURLs is the most URLs a sitemap may list by the sitemaps protocol.
+	MaxURLs = 50_000
+
+	sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	xhtmlNamespace   = "http://www.w3.org/1999/xhtml"
+)
+
+var (
+	ErrFailedToGetRecord   = errors.New("failed to get record")
+	ErrFailedToListRecords = errors.New("failed to list records")
+	ErrFailedToGenerate    = errors.New("failed to generate sitemap")
+	ErrSitemapNotEnabled   = errors.New("sitemap is not enabled")
+)
+
+type Repository interface {
+	ListSitemapProfiles(ctx context.Context) ([]*Entry, error)
+	ListSitemapProfilePages(ctx context.Context) ([]*Entry, error)
+	ListSitemapStories(ctx context.Context) ([]*Entry, error)
+	// GetCachedSitemap returns nil when no sitemap is cached yet.
+	GetCachedSitemap(ctx context.Context) (*Sitemap, error)
+	SetCachedSitemap(ctx context.Context, sitemap *Sitemap) error
+}
+
+type Settings struct {
+	// BaseURL is the address of the site whose pages the sitemap lists, e.g.
+	// https://aya.is. The sitemap is disabled when it is empty.
+	BaseURL string
+	// Locales are the locales the site is served in; translations in other locales
+	// are left out.
+	Locales []string
+}
+
+type Service struct {
+	logger   *logfx.Logger
+	repo     Repository
+	settings Settings
+}
+
+func NewService(logger *logfx.Logger, repo Repository, settings Settings) *Service {
+	settings.BaseURL = strings.TrimSuffix(settings.BaseURL, "/")
+
+	return &Service{logger: logger, repo: repo, settings: settings}
+}
+
+// Get returns the cached sitemap, and generates it when none is cached yet.
+func (s *Service) Get(ctx context.Context) (*Sitemap, error) {
+	if s.settings.BaseURL == "" {
+		return nil, ErrSitemapNotEnabled
+	}
+
+	sitemap, err := s.repo.GetCachedSitemap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToGetRecord, err)
+	}
+
+	if sitemap != nil {
+		return sitemap, nil
+	}
+
+	return s.Generate(ctx)
+}
+
+// Generate lists the profiles, the pages of profiles and the published stories in
+// every locale they are translated in, and caches the sitemap for Get.
+func (s *Service) Generate(ctx context.Context) (*Sitemap, error) {
+	if s.settings.BaseURL == "" {
+		return nil, ErrSitemapNotEnabled
+	}
+
+	profiles, err := s.repo.ListSitemapProfiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToListRecords, err)
+	}
+
+	pages, err := s.repo.ListSitemapProfilePages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToListRecords, err)
+	}
+
+	stories, err := s.repo.ListSitemapStories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToListRecords, err)
+	}
+
+	set := urlSet{ //nolint:exhaustruct
+		Namespace:  sitemapNamespace,
+		XHTMLSpace: xhtmlNamespace,
+	}
+
+	set.URLs = s.appendURLs(set.URLs, profiles, func(entry *Entry) string {
+		return "/" + url.PathEscape(entry.Slug)
+	})
+	set.URLs = s.appendURLs(set.URLs, pages, func(entry *Entry) string {
+		return "/" + url.PathEscape(entry.ProfileSlug) + "/" + url.PathEscape(entry.Slug)
+	})
+	set.URLs = s.appendURLs(set.URLs, stories, func(entry *Entry) string {
+		return "/stories/" + url.PathEscape(entry.Slug)
+	})
+
+	if len(set.URLs) > MaxURLs {
+		s.logger.WarnContext(
+			ctx,
+			"sitemap lists too many urls, the rest are left out",
+			"url_count", len(set.URLs),
+			"max_url_count", MaxURLs,
+		)
+
+		set.URLs = set.URLs[:MaxURLs]
+	}
+
+	var buffer bytes.Buffer
+
+	buffer.WriteString(xml.Header)
+
+	err = xml.NewEncoder(&buffer).Encode(set)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToGenerate, err)
+	}
+
+	sitemap := &Sitemap{
+		GeneratedAt: time.Now(),
+		XML:         buffer.Bytes(),
+		URLCount:    len(set.URLs),
+	}
+
+	err = s.repo.SetCachedSitemap(ctx, sitemap)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToGenerate, err)
+	}
+
+	return sitemap, nil
+}
+
+// Robots returns the robots.txt of the site, which points crawlers at the sitemap
+// when it is enabled.
+func (s *Service) Robots() []byte {
+	var builder strings.Builder
+
+	builder.WriteString("User-agent: *\nAllow: /\n")
+
+	if s.settings.BaseURL != "" {
+		builder.WriteString("\nSitemap: " + s.settings.BaseURL + "/sitemap.xml\n")
+	}
+
+	return []byte(builder.String())
+}
+
+// appendURLs adds a URL for each translation of the records in entries, which are
+// sorted by record, linking the translations of a record to each other.
+func (s *Service) appendURLs(
+	urls []sitemapURL,
+	entries []*Entry,
+	pathOf func(entry *Entry) string,
+) []sitemapURL {
+	for start := 0; start < len(entries); {
+		end := start + 1
+		for end < len(entries) &&
+			entries[end].ProfileSlug == entries[start].ProfileSlug &&
+			entries[end].Slug == entries[start].Slug {
+			end++
+		}
+
+		translations := slices.DeleteFunc(
+			slices.Clone(entries[start:end]),
+			func(entry *Entry) bool {
+				return !slices.Contains(s.settings.Locales, entry.LocaleCode)
+			},
+		)
+
+		alternates := make([]alternateLink, 0, len(translations))
+		for _, entry := range translations {
+			alternates = append(alternates, alternateLink{
+				Rel:      "alternate",
+				HrefLang: entry.LocaleCode,
+				Href:     s.locationOf(entry, pathOf),
+			})
+		}
+
+		for _, entry := range translations {
+			urls = append(urls, sitemapURL{
+				Location:     s.locationOf(entry, pathOf),
+				LastModified: entry.LastModifiedAt.UTC().Format(time.RFC3339),
+				Alternates:   alternates,
+			})
+		}
+
+		start = end
+	}
+
+	return urls
+}
+
+func (s *Service) locationOf(entry *Entry, pathOf func(entry *Entry) string) string {
+	return s.settings.BaseURL + "/" + url.PathEscape(entry.LocaleCode) + pathOf(entry)
+}
@@ -0,0 +1,192 @@
+package sitemaps_test
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/api/business/sitemaps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepository lists the records the storage adapter lists: the profiles, the pages of
+// profiles and the published stories that are not deleted, sorted by record.
+type fakeRepository struct {
+	cached *sitemaps.Sitemap
+
+	profiles []*sitemaps.Entry
+	pages    []*sitemaps.Entry
+	stories  []*sitemaps.Entry
+}
+
+func newFakeRepository() *fakeRepository {
+	modifiedAt := time.Date(2025, 3, 31, 10, 0, 0, 0, time.UTC)
+
+	return &fakeRepository{
+		cached: nil,
+		profiles: []*sitemaps.Entry{
+			{Slug: "acme", LocaleCode: "en", LastModifiedAt: modifiedAt},
+			{Slug: "acme", LocaleCode: "tr", LastModifiedAt: modifiedAt},
+			{Slug: "acme", LocaleCode: "xx", LastModifiedAt: modifiedAt},
+		},
+		pages: []*sitemaps.Entry{
+			{ProfileSlug: "acme", Slug: "about", LocaleCode: "en", LastModifiedAt: modifiedAt},
+		},
+		stories: []*sitemaps.Entry{
+			{Slug: "hello world", LocaleCode: "tr", LastModifiedAt: modifiedAt},
+		},
+	}
+}
+
+func (r *fakeRepository) ListSitemapProfiles(_ context.Context) ([]*sitemaps.Entry, error) {
+	return r.profiles, nil
+}
+
+func (r *fakeRepository) ListSitemapProfilePages(_ context.Context) ([]*sitemaps.Entry, error) {
+	return r.pages, nil
+}
+
+func (r *fakeRepository) ListSitemapStories(_ context.Context) ([]*sitemaps.Entry, error) {
+	return r.stories, nil
+}
+
+func (r *fakeRepository) GetCachedSitemap(_ context.Context) (*sitemaps.Sitemap, error) {
+	return r.cached, nil
+}
+
+func (r *fakeRepository) SetCachedSitemap(_ context.Context, sitemap *sitemaps.Sitemap) error {
+	r.cached = sitemap
+
+	return nil
+}
+
+// decodedURLSet reads back the urlset of a generated sitemap.
+type decodedURLSet struct {
+	XMLName xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []struct {
+		Location     string `xml:"loc"`
+		LastModified string `xml:"lastmod"`
+		Alternates   []struct {
+			Rel      string `xml:"rel,attr"`
+			HrefLang string `xml:"hreflang,attr"`
+			Href     string `xml:"href,attr"`
+		} `xml:"http://www.w3.org/1999/xhtml link"`
+	} `xml:"url"`
+}
+
+func newTestService(repo sitemaps.Repository, baseURL string) *sitemaps.Service {
+	return sitemaps.NewService(
+		logfx.NewLogger(logfx.WithWriter(io.Discard)),
+		repo,
+		sitemaps.Settings{BaseURL: baseURL, Locales: []string{"en", "tr"}},
+	)
+}
+
+func TestServiceGenerate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should list the records in the locales of the site", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newFakeRepository()
+		service := newTestService(repo, "https://aya.is/")
+
+		sitemap, err := service.Generate(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, 4, sitemap.URLCount)
+		assert.Equal(t, sitemap, repo.cached)
+		assert.Contains(t, string(sitemap.XML), xml.Header)
+
+		var set decodedURLSet
+
+		require.NoError(t, xml.Unmarshal(sitemap.XML, &set))
+
+		locations := make([]string, 0, len(set.URLs))
+		for _, entry := range set.URLs {
+			locations = append(locations, entry.Location)
+		}
+
+		// The "xx" translation is left out, as the site is not served in it.
+		assert.Equal(t, []string{
+			"https://aya.is/en/acme",
+			"https://aya.is/tr/acme",
+			"https://aya.is/en/acme/about",
+			"https://aya.is/tr/stories/hello%20world",
+		}, locations)
+
+		assert.Equal(t, "2025-03-31T10:00:00Z", set.URLs[0].LastModified)
+
+		// The translations of a record link to each other.
+		for _, entry := range set.URLs[:2] {
+			require.Len(t, entry.Alternates, 2)
+			assert.Equal(t, "alternate", entry.Alternates[0].Rel)
+			assert.Equal(t, "en", entry.Alternates[0].HrefLang)
+			assert.Equal(t, "https://aya.is/en/acme", entry.Alternates[0].Href)
+			assert.Equal(t, "tr", entry.Alternates[1].HrefLang)
+			assert.Equal(t, "https://aya.is/tr/acme", entry.Alternates[1].Href)
+		}
+
+		require.Len(t, set.URLs[2].Alternates, 1)
+		assert.Equal(t, "https://aya.is/en/acme/about", set.URLs[2].Alternates[0].Href)
+	})
+
+	t.Run("should only list the records of the repository", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newFakeRepository()
+		repo.stories = []*sitemaps.Entry{}
+		service := newTestService(repo, "https://aya.is")
+
+		sitemap, err := service.Generate(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, 3, sitemap.URLCount)
+		assert.NotContains(t, string(sitemap.XML), "/stories/")
+	})
+
+	t.Run("should not generate without a base url", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newFakeRepository()
+		service := newTestService(repo, "")
+
+		_, err := service.Generate(t.Context())
+		require.ErrorIs(t, err, sitemaps.ErrSitemapNotEnabled)
+		assert.Nil(t, repo.cached)
+	})
+}
+
+func TestServiceGet(t *testing.T) {
+	t.Parallel()
+
+	repo := newFakeRepository()
+	service := newTestService(repo, "https://aya.is")
+
+	generated, err := service.Get(t.Context())
+	require.NoError(t, err)
+	require.NotNil(t, repo.cached)
+
+	// Get serves the cached sitemap until it is generated again.
+	repo.stories = []*sitemaps.Entry{}
+
+	cached, err := service.Get(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, generated, cached)
+}
+
+func TestServiceRobots(t *testing.T) {
+	t.Parallel()
+
+	enabled := newTestService(newFakeRepository(), "https://aya.is/")
+	assert.Equal(
+		t,
+		"User-agent: *\nAllow: /\n\nSitemap: https://aya.is/sitemap.xml\n",
+		string(enabled.Robots()),
+	)
+
+	disabled := newTestService(newFakeRepository(), "")
+	assert.Equal(t, "User-agent: *\nAllow: /\n", string(disabled.Robots()))
+}
@@ -0,0 +1,41 @@
+package sitemaps
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Entry is a translation of a record the sitemap lists.
+type Entry struct {
+	LastModifiedAt time.Time
+	// ProfileSlug is set for the pages of profiles only.
+	ProfileSlug string
+	Slug        string
+	LocaleCode  string
+}
+
+// Sitemap is a generated sitemap.xml document.
+type Sitemap struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	XML         []byte    `json:"xml"`
+	URLCount    int       `json:"url_count"`
+}
+
+type urlSet struct {
+	XMLName    xml.Name     `xml:"urlset"`
+	Namespace  string       `xml:"xmlns,attr"`
+	XHTMLSpace string       `xml:"xmlns:xhtml,attr"`
+	URLs       []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Location     string          `xml:"loc"`
+	LastModified string          `xml:"lastmod,omitempty"`
+	Alternates   []alternateLink `xml:"xhtml:link"`
+}
+
+type alternateLink struct {
+	Rel      string `xml:"rel,attr"`
+	HrefLang string `xml:"hreflang,attr"`
+	Href     string `xml:"href,attr"`
+}
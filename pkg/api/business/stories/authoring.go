@@ -0,0 +1,442 @@
+package stories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+)
+
+const (
+	StoryStatusDraft     = "draft"
+	StoryStatusInReview  = "in_review"
+	StoryStatusPublished = "published"
+	StoryStatusArchived  = "archived"
+
+	// PublicationKindOriginal is the kind of the publication of a story to its author
+	// profile, which lasts while the story is published.
+	PublicationKindOriginal = "original"
+
+	MaxSlugLength = 128
+)
+
+var (
+	ErrInvalidStoryInput       = errors.New("invalid story input")
+	ErrStoryNotFound           = errors.New("story not found")
+	ErrNotStoryAuthor          = errors.New("not an author of the story")
+	ErrStorySlugAlreadyTaken   = errors.New("story slug is already taken")
+	ErrInvalidStatusTransition = errors.New("invalid story status transition")
+)
+
+var (
+	slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`) //nolint:gochecknoglobals
+
+	// statusTransitions lists the statuses a story may move to from each status. A
+	// story in review goes back to draft when changes are requested, and an archived
+	// one goes back to draft to be reworked.
+	statusTransitions = map[string][]string{ //nolint:gochecknoglobals
+		StoryStatusDraft:     {StoryStatusInReview},
+		StoryStatusInReview:  {StoryStatusDraft, StoryStatusPublished},
+		StoryStatusPublished: {StoryStatusArchived},
+		StoryStatusArchived:  {StoryStatusDraft},
+	}
+)
+
+// Create creates a draft story whose author is the profile with authorProfileSlug,
+// once it checks that the user owns the profile. Its content is stored as its first
+// revision.
+func (s *Service) Create(
+	ctx context.Context,
+	localeCode string,
+	userID string,
+	authorProfileSlug string,
+	input *CreateStoryInput,
+) (*StoryWithChildren, error) {
+	err := validateCreateStoryInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	authorProfileID, err := s.getOwnedAuthorProfileID(ctx, userID, authorProfileSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.checkSlugAvailable(ctx, input.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &Story{
+		CreatedAt:       time.Now(),
+		Properties:      nil,
+		AuthorProfileID: &authorProfileID,
+		StoryPictureURI: input.StoryPictureURI,
+		PublishAt:       nil,
+		UpdatedAt:       nil,
+		DeletedAt:       nil,
+		ID:              string(s.idGenerator()),
+		Slug:            input.Slug,
+		Kind:            input.Kind,
+		Status:          StoryStatusDraft,
+		Title:           input.Title,
+		Summary:         input.Summary,
+		Content:         input.Content,
+		IsFeatured:      false,
+	}
+
+	revision := s.newRevision(
+		record.ID,
+		localeCode,
+		userID,
+		record.Title,
+		record.Summary,
+		record.Content,
+	)
+
+	err = s.repo.CreateStory(ctx, localeCode, record, revision)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToCreateRecord, input.Slug, err)
+	}
+
+	return s.getAuthoredRecord(ctx, localeCode, record.ID)
+}
+
+// Update changes the fields of input that are set, once it checks that the user owns
+// the author profile of the story. Title is required when the story has no title for
+// the locale yet. A change of the title, summary or content is stored as a revision.
+func (s *Service) Update(
+	ctx context.Context,
+	localeCode string,
+	userID string,
+	slug string,
+	input *UpdateStoryInput,
+) (*StoryWithChildren, error) {
+	err := validateUpdateStoryInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	story, err := s.getAuthoredStory(ctx, userID, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Slug != nil && *input.Slug != slug {
+		err = s.checkSlugAvailable(ctx, *input.Slug)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	current, err := s.repo.GetStoryByID(ctx, localeCode, story.ID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	changes := *input
+
+	if current != nil {
+		if changes.Title == nil {
+			changes.Title = &current.Title
+		}
+
+		if changes.Summary == nil {
+			changes.Summary = &current.Summary
+		}
+
+		if changes.Content == nil {
+			changes.Content = &current.Content
+		}
+	} else if changes.Title == nil && (changes.Summary != nil || changes.Content != nil) {
+		return nil, fmt.Errorf(
+			"%w: title is required for a new locale (locale: %s)",
+			ErrInvalidStoryInput,
+			localeCode,
+		)
+	}
+
+	var revision *StoryRevision
+
+	if changes.Title != nil && !isSameContent(current, &changes) {
+		revision = s.newRevision(
+			story.ID,
+			localeCode,
+			userID,
+			*changes.Title,
+			stringOrEmpty(changes.Summary),
+			stringOrEmpty(changes.Content),
+		)
+	}
+
+	updated, err := s.repo.UpdateStory(ctx, localeCode, story.ID, slug, &changes, revision)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToUpdateRecord, slug, err)
+	}
+
+	if updated == 0 {
+		return nil, fmt.Errorf("%w(slug: %s)", ErrStoryNotFound, slug)
+	}
+
+	if revision != nil {
+		s.pruneRevisions(ctx, story.ID, localeCode)
+	}
+
+	record, err := s.getAuthoredRecord(ctx, localeCode, story.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateStory(ctx, story, record)
+
+	return record, nil
+}
+
+// UpdateStatus moves the story to status, once it checks that the user owns the
+// author profile of the story and that the story may move there from its status.
+func (s *Service) UpdateStatus(
+	ctx context.Context,
+	localeCode string,
+	userID string,
+	slug string,
+	status string,
+) (*StoryWithChildren, error) {
+	if _, ok := statusTransitions[status]; !ok {
+		return nil, fmt.Errorf("%w: status is invalid (status: %s)", ErrInvalidStoryInput, status)
+	}
+
+	story, err := s.getAuthoredStory(ctx, userID, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(statusTransitions[story.Status], status) {
+		return nil, fmt.Errorf(
+			"%w(slug: %s, from: %s, to: %s)",
+			ErrInvalidStatusTransition,
+			slug,
+			story.Status,
+			status,
+		)
+	}
+
+	updated, err := s.repo.UpdateStoryStatus(ctx, story, status, string(s.idGenerator()))
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToUpdateRecord, slug, err)
+	}
+
+	// the status changed since it was read.
+	if updated == 0 {
+		return nil, fmt.Errorf(
+			"%w(slug: %s, from: %s, to: %s)",
+			ErrInvalidStatusTransition,
+			slug,
+			story.Status,
+			status,
+		)
+	}
+
+	s.logger.InfoContext(
+		ctx,
+		"story status changed",
+		"slug", slug,
+		"from", story.Status,
+		"to", status,
+	)
+
+	record, err := s.getAuthoredRecord(ctx, localeCode, story.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateStory(ctx, story, record)
+
+	return record, nil
+}
+
+// Delete soft-deletes a story, once it checks that the user owns its author profile.
+// The slug of a deleted story is not released.
+func (s *Service) Delete(ctx context.Context, userID string, slug string) error {
+	story, err := s.getAuthoredStory(ctx, userID, slug)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.repo.RemoveStory(ctx, story.ID, slug)
+	if err != nil {
+		return fmt.Errorf("%w(slug: %s): %w", ErrFailedToDeleteRecord, slug, err)
+	}
+
+	if deleted == 0 {
+		return fmt.Errorf("%w(slug: %s)", ErrStoryNotFound, slug)
+	}
+
+	s.invalidateStory(ctx, story, nil)
+
+	return nil
+}
+
+// getOwnedAuthorProfileID resolves the ID of the profile, and fails with
+// ErrNotStoryAuthor unless it is the individual profile of the user or the user is
+// one of its owners.
+func (s *Service) getOwnedAuthorProfileID(
+	ctx context.Context,
+	userID string,
+	slug string,
+) (string, error) {
+	profileID, err := s.repo.GetProfileIDBySlug(ctx, slug)
+	if err != nil {
+		return "", fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if profileID == "" {
+		return "", fmt.Errorf("%w(slug: %s)", profiles.ErrProfileNotFound, slug)
+	}
+
+	owned, err := s.repo.IsProfileOwnedByUser(ctx, profileID, userID)
+	if err != nil {
+		return "", fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if !owned {
+		return "", fmt.Errorf("%w(profile: %s)", ErrNotStoryAuthor, slug)
+	}
+
+	return profileID, nil
+}
+
+// getAuthoredStory resolves the story, and fails with ErrNotStoryAuthor unless the
+// user owns its author profile.
+func (s *Service) getAuthoredStory(
+	ctx context.Context,
+	userID string,
+	slug string,
+) (*AuthoredStory, error) {
+	story, err := s.repo.GetAuthoredStoryBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if story == nil {
+		return nil, fmt.Errorf("%w(slug: %s)", ErrStoryNotFound, slug)
+	}
+
+	owned, err := s.repo.IsProfileOwnedByUser(ctx, story.AuthorProfileID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if !owned {
+		return nil, fmt.Errorf("%w(slug: %s)", ErrNotStoryAuthor, slug)
+	}
+
+	return story, nil
+}
+
+// getAuthoredRecord reads a story back after it is written, whatever its status is.
+func (s *Service) getAuthoredRecord(
+	ctx context.Context,
+	localeCode string,
+	id string,
+) (*StoryWithChildren, error) {
+	record, err := s.repo.GetStoryByID(ctx, localeCode, id, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w(story_id: %s): %w", ErrFailedToGetRecord, id, err)
+	}
+
+	return record, nil
+}
+
+// checkSlugAvailable fails with ErrStorySlugAlreadyTaken when a story has the slug,
+// since stories are looked up by their slugs alone.
+func (s *Service) checkSlugAvailable(ctx context.Context, slug string) error {
+	storyID, err := s.repo.GetStoryIDBySlug(ctx, slug)
+	if err != nil {
+		return fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if storyID != "" {
+		return fmt.Errorf("%w(slug: %s)", ErrStorySlugAlreadyTaken, slug)
+	}
+
+	return nil
+}
+
+// invalidateStory drops the cached responses of the profiles that list a story that
+// changed. The change is stored already, so a failure only delays it until the
+// cached responses expire.
+func (s *Service) invalidateStory(
+	ctx context.Context,
+	story *AuthoredStory,
+	record *StoryWithChildren,
+) {
+	if s.cacheInvalidator == nil {
+		return
+	}
+
+	tags := []string{profiles.CacheTagForProfile(story.AuthorProfileSlug)}
+
+	if record != nil {
+		for _, publication := range record.Publications {
+			tags = append(tags, profiles.CacheTagForProfile(publication.Slug))
+		}
+	}
+
+	err := s.cacheInvalidator.Invalidate(ctx, tags...)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to invalidate story", "story_id", story.ID, "error", err)
+	}
+}
+
+func isVisibleStatus(status string) bool {
+	return status != StoryStatusDraft && status != StoryStatusInReview
+}
+
+func validateSlug(slug string) error {
+	if len(slug) > MaxSlugLength || !slugPattern.MatchString(slug) {
+		return fmt.Errorf(
+			"%w: slug must be up to %d lowercase letters, digits and dashes (slug: %s)",
+			ErrInvalidStoryInput,
+			MaxSlugLength,
+			slug,
+		)
+	}
+
+	return nil
+}
+
+func validateCreateStoryInput(input *CreateStoryInput) error {
+	err := validateSlug(input.Slug)
+	if err != nil {
+		return err
+	}
+
+	if input.Kind == "" {
+		return fmt.Errorf("%w: kind is required", ErrInvalidStoryInput)
+	}
+
+	if input.Title == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidStoryInput)
+	}
+
+	return nil
+}
+
+func validateUpdateStoryInput(input *UpdateStoryInput) error {
+	if input.Slug != nil {
+		err := validateSlug(*input.Slug)
+		if err != nil {
+			return err
+		}
+	}
+
+	if input.Title != nil && *input.Title == "" {
+		return fmt.Errorf("%w: title cannot be empty", ErrInvalidStoryInput)
+	}
+
+	return nil
+}
@@ -0,0 +1,266 @@
+package stories_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/eser/aya.is-services/pkg/api/business/stories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (r *fakeRepository) GetAuthoredStoryBySlug(
+	_ context.Context,
+	slug string,
+) (*stories.AuthoredStory, error) {
+	story, ok := r.stories[slug]
+	if !ok {
+		return nil, nil //nolint:nilnil
+	}
+
+	copied := *story
+
+	return &copied, nil
+}
+
+func (r *fakeRepository) CreateStory(
+	_ context.Context,
+	_ string,
+	story *stories.Story,
+	revision *stories.StoryRevision,
+) error {
+	r.stories[story.Slug] = &stories.AuthoredStory{
+		ID:                story.ID,
+		Status:            story.Status,
+		AuthorProfileID:   *story.AuthorProfileID,
+		AuthorProfileSlug: "author",
+	}
+	r.records[story.ID] = &stories.StoryWithChildren{Story: story} //nolint:exhaustruct
+	r.created = append(r.created, story)
+	r.revisions = append(r.revisions, revision)
+
+	return nil
+}
+
+func (r *fakeRepository) UpdateStory(
+	_ context.Context,
+	_ string,
+	id string,
+	_ string,
+	input *stories.UpdateStoryInput,
+	revision *stories.StoryRevision,
+) (int64, error) {
+	r.updates = append(r.updates, input)
+
+	if record := r.records[id]; record != nil {
+		record.Title = *input.Title
+		record.Summary = *input.Summary
+		record.Content = *input.Content
+	}
+
+	if revision != nil {
+		r.revisions = append(r.revisions, revision)
+	}
+
+	return 1, nil
+}
+
+func (r *fakeRepository) UpdateStoryStatus(
+	_ context.Context,
+	story *stories.AuthoredStory,
+	status string,
+	_ string,
+) (int64, error) {
+	for _, stored := range r.stories {
+		if stored.ID == story.ID && stored.Status == story.Status {
+			stored.Status = status
+
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+func TestServiceUpdateStatus(t *testing.T) {
+	t.Parallel()
+
+	const (
+		draft     = stories.StoryStatusDraft
+		inReview  = stories.StoryStatusInReview
+		published = stories.StoryStatusPublished
+		archived  = stories.StoryStatusArchived
+	)
+
+	tests := []struct {
+		expectedErr error
+		from        string
+		to          string
+	}{
+		{from: draft, to: inReview, expectedErr: nil},
+		{from: draft, to: published, expectedErr: stories.ErrInvalidStatusTransition},
+		{from: draft, to: archived, expectedErr: stories.ErrInvalidStatusTransition},
+		{from: draft, to: draft, expectedErr: stories.ErrInvalidStatusTransition},
+		{from: inReview, to: draft, expectedErr: nil},
+		{from: inReview, to: published, expectedErr: nil},
+		{from: inReview, to: archived, expectedErr: stories.ErrInvalidStatusTransition},
+		{from: published, to: archived, expectedErr: nil},
+		{from: published, to: draft, expectedErr: stories.ErrInvalidStatusTransition},
+		{from: published, to: inReview, expectedErr: stories.ErrInvalidStatusTransition},
+		{from: archived, to: draft, expectedErr: nil},
+		{from: archived, to: published, expectedErr: stories.ErrInvalidStatusTransition},
+		{from: draft, to: "deleted", expectedErr: stories.ErrInvalidStoryInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newFakeRepository()
+			repo.stories["hello"].Status = tt.from
+			service := newTestService(repo)
+
+			_, err := service.UpdateStatus(t.Context(), "en", ownerUserID, "hello", tt.to)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				assert.Equal(t, tt.from, repo.stories["hello"].Status)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.to, repo.stories["hello"].Status)
+		})
+	}
+
+	t.Run("should reject the users who do not own the author profile", func(t *testing.T) {
+		t.Parallel()
+
+		service := newTestService(newFakeRepository())
+
+		_, err := service.UpdateStatus(t.Context(), "en", "user-other", "hello", inReview)
+		require.ErrorIs(t, err, stories.ErrNotStoryAuthor)
+	})
+
+	t.Run("should report unknown stories", func(t *testing.T) {
+		t.Parallel()
+
+		service := newTestService(newFakeRepository())
+
+		_, err := service.UpdateStatus(t.Context(), "en", ownerUserID, "missing", inReview)
+		require.ErrorIs(t, err, stories.ErrStoryNotFound)
+	})
+}
+
+func TestServiceCreate(t *testing.T) {
+	t.Parallel()
+
+	valid := func() *stories.CreateStoryInput {
+		return &stories.CreateStoryInput{ //nolint:exhaustruct
+			Slug:    "new-story",
+			Kind:    "article",
+			Title:   "New story",
+			Content: "Once upon a time",
+		}
+	}
+
+	tests := []struct {
+		expectedErr error
+		change      func(input *stories.CreateStoryInput)
+		name        string
+		userID      string
+		profileSlug string
+	}{
+		{
+			name:        "should reject slugs with uppercase letters and spaces",
+			change:      func(input *stories.CreateStoryInput) { input.Slug = "New Story" },
+			expectedErr: stories.ErrInvalidStoryInput,
+		},
+		{
+			name:        "should reject slugs with leading dashes",
+			change:      func(input *stories.CreateStoryInput) { input.Slug = "-new-story" },
+			expectedErr: stories.ErrInvalidStoryInput,
+		},
+		{
+			name: "should reject slugs that are too long",
+			change: func(input *stories.CreateStoryInput) {
+				input.Slug = strings.Repeat("a", stories.MaxSlugLength+1)
+			},
+			expectedErr: stories.ErrInvalidStoryInput,
+		},
+		{
+			name:        "should require a kind",
+			change:      func(input *stories.CreateStoryInput) { input.Kind = "" },
+			expectedErr: stories.ErrInvalidStoryInput,
+		},
+		{
+			name:        "should require a title",
+			change:      func(input *stories.CreateStoryInput) { input.Title = "" },
+			expectedErr: stories.ErrInvalidStoryInput,
+		},
+		{
+			name:        "should reject slugs that are taken",
+			change:      func(input *stories.CreateStoryInput) { input.Slug = "hello" },
+			expectedErr: stories.ErrStorySlugAlreadyTaken,
+		},
+		{
+			name:        "should reject the users who do not own the author profile",
+			userID:      "user-other",
+			expectedErr: stories.ErrNotStoryAuthor,
+		},
+		{
+			name:        "should report unknown author profiles",
+			profileSlug: "missing",
+			expectedErr: profiles.ErrProfileNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newFakeRepository()
+			service := newTestService(repo)
+
+			input := valid()
+			if tt.change != nil {
+				tt.change(input)
+			}
+
+			userID := ownerUserID
+			if tt.userID != "" {
+				userID = tt.userID
+			}
+
+			profileSlug := "author"
+			if tt.profileSlug != "" {
+				profileSlug = tt.profileSlug
+			}
+
+			_, err := service.Create(t.Context(), "en", userID, profileSlug, input)
+			require.ErrorIs(t, err, tt.expectedErr)
+			assert.Empty(t, repo.created)
+		})
+	}
+
+	t.Run("should create a draft with its first revision", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newFakeRepository()
+		service := newTestService(repo)
+
+		record, err := service.Create(t.Context(), "en", ownerUserID, "author", valid())
+		require.NoError(t, err)
+
+		assert.Equal(t, stories.StoryStatusDraft, record.Status)
+		assert.Equal(t, authorProfileID, *record.AuthorProfileID)
+		assert.Len(t, repo.created, 1)
+
+		require.Len(t, repo.revisions, 1)
+		assert.Equal(t, record.ID, repo.revisions[0].StoryID)
+		assert.Equal(t, "en", repo.revisions[0].LocaleCode)
+		assert.Equal(t, "Once upon a time", repo.revisions[0].Content)
+	})
+}
@@ -0,0 +1,244 @@
+package stories
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/eser/aya.is-services/pkg/lib/cursors"
+)
+
+const (
+	FeedFormatRSS  = "rss"
+	FeedFormatAtom = "atom"
+
+	// DefaultFeedItemCount is how many of the latest stories a feed lists by default.
+	DefaultFeedItemCount = 20
+
+	rssContentType  = "application/rss+xml; charset=utf-8"
+	atomContentType = "application/atom+xml; charset=utf-8"
+	atomNamespace   = "http://www.w3.org/2005/Atom"
+)
+
+var (
+	ErrFeedsNotEnabled    = errors.New("feeds are not enabled")
+	ErrUnknownFeedFormat  = errors.New("unknown feed format")
+	ErrFailedToRenderFeed = errors.New("failed to render feed")
+)
+
+type FeedSettings struct {
+	// BaseURL is the address of the site the links of the feeds lead to, e.g.
+	// https://aya.is.
+	BaseURL string
+	// ItemCount is how many of the latest stories a feed lists.
+	ItemCount int
+}
+
+// SetFeedSettings enables the feeds of the stories of profiles.
+func (s *Service) SetFeedSettings(settings FeedSettings) {
+	settings.BaseURL = strings.TrimSuffix(settings.BaseURL, "/")
+	if settings.ItemCount <= 0 {
+		settings.ItemCount = DefaultFeedItemCount
+	}
+
+	s.feedSettings = settings
+}
+
+// RenderProfileFeed renders the latest stories published to the profile with slug as
+// an RSS 2.0 or Atom feed, in format.
+func (s *Service) RenderProfileFeed(
+	ctx context.Context,
+	localeCode string,
+	slug string,
+	format string,
+) (*Feed, error) {
+	if s.feedSettings.BaseURL == "" {
+		return nil, ErrFeedsNotEnabled
+	}
+
+	if format != FeedFormatRSS && format != FeedFormatAtom {
+		return nil, fmt.Errorf("%w(format: %s)", ErrUnknownFeedFormat, format)
+	}
+
+	records, err := s.ListByPublicationProfileSlug(
+		ctx,
+		localeCode,
+		slug,
+		cursors.NewCursor(s.feedSettings.ItemCount, nil),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	profileID, err := s.repo.GetProfileIDBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	var profile *profiles.Profile
+
+	if profileID != "" {
+		profile, err = s.repo.GetProfileByID(ctx, localeCode, profileID)
+		if err != nil {
+			return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+		}
+	}
+
+	if profile == nil {
+		return nil, fmt.Errorf("%w(slug: %s)", profiles.ErrProfileNotFound, slug)
+	}
+
+	items := records.Data
+	if len(items) > s.feedSettings.ItemCount {
+		items = items[:s.feedSettings.ItemCount]
+	}
+
+	feed := &Feed{ //nolint:exhaustruct
+		UpdatedAt: feedUpdatedAt(profile, items),
+	}
+
+	var document any
+
+	if format == FeedFormatRSS {
+		feed.ContentType = rssContentType
+		document = s.rssDocument(localeCode, profile, items, feed.UpdatedAt)
+	} else {
+		feed.ContentType = atomContentType
+		document = s.atomDocument(localeCode, profile, items, feed.UpdatedAt)
+	}
+
+	var buffer bytes.Buffer
+
+	buffer.WriteString(xml.Header)
+
+	err = xml.NewEncoder(&buffer).Encode(document)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToRenderFeed, slug, err)
+	}
+
+	feed.Body = buffer.Bytes()
+
+	return feed, nil
+}
+
+func (s *Service) rssDocument(
+	localeCode string,
+	profile *profiles.Profile,
+	items []*StoryWithChildren,
+	updatedAt time.Time,
+) *rssDocument {
+	channel := rssChannel{
+		Title:         profile.Title,
+		Link:          s.profileURL(localeCode, profile.Slug),
+		Description:   profile.Description,
+		Language:      localeCode,
+		LastBuildDate: updatedAt.UTC().Format(time.RFC1123Z),
+		Items:         make([]rssItem, 0, len(items)),
+	}
+
+	for _, item := range items {
+		link := s.storyURL(localeCode, item.Slug)
+
+		channel.Items = append(channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        link,
+			GUID:        rssGUID{IsPermaLink: true, Value: link},
+			PubDate:     item.CreatedAt.UTC().Format(time.RFC1123Z),
+			Description: item.Summary,
+		})
+	}
+
+	return &rssDocument{Version: "2.0", Channel: channel} //nolint:exhaustruct
+}
+
+func (s *Service) atomDocument(
+	localeCode string,
+	profile *profiles.Profile,
+	items []*StoryWithChildren,
+	updatedAt time.Time,
+) *atomDocument {
+	profileURL := s.profileURL(localeCode, profile.Slug)
+
+	document := &atomDocument{ //nolint:exhaustruct
+		Namespace: atomNamespace,
+		Language:  localeCode,
+		ID:        profileURL + "/stories",
+		Title:     profile.Title,
+		Subtitle:  profile.Description,
+		Updated:   updatedAt.UTC().Format(time.RFC3339),
+		Links:     []atomLink{{Rel: "alternate", Href: profileURL}},
+		Author:    &atomPerson{Name: profile.Title, URI: profileURL},
+		Entries:   make([]atomEntry, 0, len(items)),
+	}
+
+	for _, item := range items {
+		link := s.storyURL(localeCode, item.Slug)
+
+		entry := atomEntry{ //nolint:exhaustruct
+			ID:        link,
+			Title:     item.Title,
+			Links:     []atomLink{{Rel: "alternate", Href: link}},
+			Published: item.CreatedAt.UTC().Format(time.RFC3339),
+			Updated:   storyUpdatedAt(item.Story).UTC().Format(time.RFC3339),
+			Summary:   &atomText{Type: "text", Value: item.Summary},
+			Content:   &atomText{Type: "text", Value: item.Content},
+		}
+
+		if item.AuthorProfile != nil {
+			entry.Author = &atomPerson{
+				Name: item.AuthorProfile.Title,
+				URI:  s.profileURL(localeCode, item.AuthorProfile.Slug),
+			}
+		}
+
+		document.Entries = append(document.Entries, entry)
+	}
+
+	return document
+}
+
+func (s *Service) profileURL(localeCode string, slug string) string {
+	return s.feedSettings.BaseURL + "/" + url.PathEscape(localeCode) + "/" + url.PathEscape(slug)
+}
+
+func (s *Service) storyURL(localeCode string, slug string) string {
+	return s.feedSettings.BaseURL + "/" + url.PathEscape(localeCode) + "/stories/" +
+		url.PathEscape(slug)
+}
+
+// feedUpdatedAt is the latest update of the stories, or of the profile when it has
+// none.
+func feedUpdatedAt(profile *profiles.Profile, items []*StoryWithChildren) time.Time {
+	updatedAt := profile.CreatedAt
+	if profile.UpdatedAt != nil {
+		updatedAt = *profile.UpdatedAt
+	}
+
+	if len(items) == 0 {
+		return updatedAt
+	}
+
+	latest := storyUpdatedAt(items[0].Story)
+
+	for _, item := range items[1:] {
+		if itemUpdatedAt := storyUpdatedAt(item.Story); itemUpdatedAt.After(latest) {
+			latest = itemUpdatedAt
+		}
+	}
+
+	return latest
+}
+
+func storyUpdatedAt(story *Story) time.Time {
+	if story.UpdatedAt != nil {
+		return *story.UpdatedAt
+	}
+
+	return story.CreatedAt
+}
@@ -0,0 +1,58 @@
+package stories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/eser/aya.is-services/pkg/api/business/reactions"
+)
+
+// React adds the reaction of the user of the kind to a published or archived story
+// when reacted is true, or removes it otherwise, and returns the counts of the story.
+func (s *Service) React(
+	ctx context.Context,
+	userID string,
+	slug string,
+	kind string,
+	reacted bool,
+) ([]*reactions.Count, error) {
+	story, err := s.repo.GetAuthoredStoryBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if story == nil || !isVisibleStatus(story.Status) {
+		return nil, fmt.Errorf("%w(slug: %s)", ErrStoryNotFound, slug)
+	}
+
+	counts, err := s.reactions.Set(ctx, reactions.TargetKindStory, story.ID, userID, kind, reacted)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return counts, nil
+}
+
+// attachReactions sets the counts of the stories with a single lookup.
+func (s *Service) attachReactions(ctx context.Context, records ...*StoryWithChildren) error {
+	ids := make([]string, 0, len(records))
+
+	for _, record := range records {
+		if record != nil {
+			ids = append(ids, record.ID)
+		}
+	}
+
+	counts, err := s.reactions.CountsOf(ctx, reactions.TargetKindStory, ids)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	for _, record := range records {
+		if record != nil {
+			record.Reactions = counts[record.ID]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,345 @@
+package stories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DiffOpEqual  = "equal"
+	DiffOpInsert = "insert"
+	DiffOpDelete = "delete"
+
+	// maxDiffCells bounds the line comparisons a diff takes, the product of the line
+	// counts of the texts; texts with more lines than it allows are diffed as a whole,
+	// deleted and inserted. The diff keeps two rows of lengths only, not the table.
+	maxDiffCells = 4_000_000
+)
+
+var ErrRevisionNotFound = errors.New("story revision not found")
+
+// SetRevisionRetention keeps at most keep revisions of a story in each locale; the
+// oldest are removed when a new one is stored. All are kept when keep is 0.
+func (s *Service) SetRevisionRetention(keep int) {
+	s.revisionRetention = max(keep, 0)
+}
+
+// ListRevisions lists the revisions of the story in the locale, the latest first,
+// once it checks that the user owns the author profile of the story.
+func (s *Service) ListRevisions(
+	ctx context.Context,
+	localeCode string,
+	userID string,
+	slug string,
+) ([]*StoryRevision, error) {
+	story, err := s.getAuthoredStory(ctx, userID, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.repo.ListStoryRevisions(ctx, story.ID, localeCode)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToListRecords, slug, err)
+	}
+
+	return revisions, nil
+}
+
+// DiffRevisions compares the revision fromID of the story to the revision toID, or to
+// the current story when toID is "", once it checks that the user owns the author
+// profile of the story.
+func (s *Service) DiffRevisions(
+	ctx context.Context,
+	localeCode string,
+	userID string,
+	slug string,
+	fromID string,
+	toID string,
+) (*RevisionDiff, error) {
+	story, err := s.getAuthoredStory(ctx, userID, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := s.getRevision(ctx, localeCode, story.ID, slug, fromID)
+	if err != nil {
+		return nil, err
+	}
+
+	var to *StoryRevision
+
+	if toID != "" {
+		to, err = s.getRevision(ctx, localeCode, story.ID, slug, toID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		current, err := s.getAuthoredRecord(ctx, localeCode, story.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		to = &StoryRevision{} //nolint:exhaustruct
+		if current != nil {
+			to.Title = current.Title
+			to.Summary = current.Summary
+			to.Content = current.Content
+		}
+	}
+
+	return &RevisionDiff{
+		FromRevisionID: from.ID,
+		ToRevisionID:   toID,
+		Title:          diffLines(from.Title, to.Title),
+		Summary:        diffLines(from.Summary, to.Summary),
+		Content:        diffLines(from.Content, to.Content),
+	}, nil
+}
+
+// RestoreRevision sets the title, summary and content of the story in the locale to
+// those of the revision, once it checks that the user owns the author profile of the
+// story. The restored content is stored as a new revision; the history is kept.
+func (s *Service) RestoreRevision(
+	ctx context.Context,
+	localeCode string,
+	userID string,
+	slug string,
+	revisionID string,
+) (*StoryWithChildren, error) {
+	story, err := s.getAuthoredStory(ctx, userID, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := s.getRevision(ctx, localeCode, story.ID, slug, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := s.Update(ctx, localeCode, userID, slug, &UpdateStoryInput{
+		Slug:            nil,
+		StoryPictureURI: nil,
+		Title:           &revision.Title,
+		Summary:         &revision.Summary,
+		Content:         &revision.Content,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "story revision restored", "slug", slug, "revision_id", revisionID)
+
+	return record, nil
+}
+
+func (s *Service) newRevision(
+	storyID string,
+	localeCode string,
+	userID string,
+	title string,
+	summary string,
+	content string,
+) *StoryRevision {
+	return &StoryRevision{
+		CreatedAt:    time.Now(),
+		ID:           string(s.idGenerator()),
+		StoryID:      storyID,
+		LocaleCode:   localeCode,
+		AuthorUserID: userID,
+		Title:        title,
+		Summary:      summary,
+		Content:      content,
+	}
+}
+
+func (s *Service) getRevision(
+	ctx context.Context,
+	localeCode string,
+	storyID string,
+	slug string,
+	id string,
+) (*StoryRevision, error) {
+	revision, err := s.repo.GetStoryRevision(ctx, storyID, localeCode, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s, revision_id: %s): %w", ErrFailedToGetRecord, slug, id, err)
+	}
+
+	if revision == nil {
+		return nil, fmt.Errorf("%w(slug: %s, revision_id: %s)", ErrRevisionNotFound, slug, id)
+	}
+
+	return revision, nil
+}
+
+// pruneRevisions removes the revisions of the story in the locale beyond the
+// retention. The new revision is stored already, so a failure only keeps more of
+// them until the next one is stored.
+func (s *Service) pruneRevisions(ctx context.Context, storyID string, localeCode string) {
+	if s.revisionRetention == 0 {
+		return
+	}
+
+	_, err := s.repo.PruneStoryRevisions(ctx, storyID, localeCode, s.revisionRetention)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to prune story revisions", "story_id", storyID, "error", err)
+	}
+}
+
+// isSameContent reports whether the changes leave the title, summary and content of
+// the current story as they are.
+func isSameContent(current *StoryWithChildren, changes *UpdateStoryInput) bool {
+	return current != nil &&
+		stringOrEmpty(changes.Title) == current.Title &&
+		stringOrEmpty(changes.Summary) == current.Summary &&
+		stringOrEmpty(changes.Content) == current.Content
+}
+
+func stringOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
+
+// diffLines lists the lines of from and to in order, each marked as kept, deleted
+// from from, or inserted into to, along their longest common subsequence.
+func diffLines(from string, to string) []*DiffLine {
+	fromLines := splitLines(from)
+	toLines := splitLines(to)
+
+	// the lines both texts start and end with are kept without comparing them.
+	prefix := 0
+	for prefix < len(fromLines) && prefix < len(toLines) && fromLines[prefix] == toLines[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(fromLines)-prefix && suffix < len(toLines)-prefix &&
+		fromLines[len(fromLines)-1-suffix] == toLines[len(toLines)-1-suffix] {
+		suffix++
+	}
+
+	result := make([]*DiffLine, 0, len(fromLines)+len(toLines))
+	result = appendLines(result, DiffOpEqual, fromLines[:prefix])
+	result = append(
+		result,
+		diffMiddle(fromLines[prefix:len(fromLines)-suffix], toLines[prefix:len(toLines)-suffix])...,
+	)
+
+	return appendLines(result, DiffOpEqual, fromLines[len(fromLines)-suffix:])
+}
+
+func diffMiddle(fromLines []string, toLines []string) []*DiffLine {
+	result := make([]*DiffLine, 0, len(fromLines)+len(toLines))
+
+	if (len(fromLines)+1)*(len(toLines)+1) > maxDiffCells {
+		result = appendLines(result, DiffOpDelete, fromLines)
+
+		return appendLines(result, DiffOpInsert, toLines)
+	}
+
+	return appendLCSDiff(result, fromLines, toLines)
+}
+
+// appendLCSDiff diffs the lines along their longest common subsequence in linear
+// space, by Hirschberg's algorithm: the from lines are halved, the to lines are split
+// where the subsequences of both halves add up to the longest, and each half is diffed
+// on its own.
+func appendLCSDiff(result []*DiffLine, fromLines []string, toLines []string) []*DiffLine {
+	switch {
+	case len(fromLines) == 0:
+		return appendLines(result, DiffOpInsert, toLines)
+	case len(toLines) == 0:
+		return appendLines(result, DiffOpDelete, fromLines)
+	case len(fromLines) == 1:
+		for j, line := range toLines {
+			if line == fromLines[0] {
+				result = appendLines(result, DiffOpInsert, toLines[:j])
+				result = append(result, &DiffLine{Op: DiffOpEqual, Text: line})
+
+				return appendLines(result, DiffOpInsert, toLines[j+1:])
+			}
+		}
+
+		result = appendLines(result, DiffOpDelete, fromLines)
+
+		return appendLines(result, DiffOpInsert, toLines)
+	}
+
+	middle := len(fromLines) / 2
+	heads := lcsPrefixLengths(fromLines[:middle], toLines)
+	tails := lcsSuffixLengths(fromLines[middle:], toLines)
+
+	split := 0
+	for j := range heads {
+		if heads[j]+tails[j] > heads[split]+tails[split] {
+			split = j
+		}
+	}
+
+	result = appendLCSDiff(result, fromLines[:middle], toLines[:split])
+
+	return appendLCSDiff(result, fromLines[middle:], toLines[split:])
+}
+
+// lcsPrefixLengths returns, for each j, the length of the longest common subsequence
+// of a and b[:j].
+func lcsPrefixLengths(a []string, b []string) []int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for _, line := range a {
+		for j := range b {
+			if line == b[j] {
+				current[j+1] = previous[j] + 1
+			} else {
+				current[j+1] = max(previous[j+1], current[j])
+			}
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous
+}
+
+// lcsSuffixLengths returns, for each j, the length of the longest common subsequence
+// of a and b[j:].
+func lcsSuffixLengths(a []string, b []string) []int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				current[j] = previous[j+1] + 1
+			} else {
+				current[j] = max(previous[j], current[j+1])
+			}
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous
+}
+
+func appendLines(result []*DiffLine, op string, lines []string) []*DiffLine {
+	for _, line := range lines {
+		result = append(result, &DiffLine{Op: op, Text: line})
+	}
+
+	return result
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(text, "\n")
+}
@@ -0,0 +1,344 @@
+package stories_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/eser/aya.is-services/pkg/api/business/stories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addRevision stores a revision of "hello" in English with the content.
+func (r *fakeRepository) addRevision(id string, content string) {
+	r.revisions = append(r.revisions, &stories.StoryRevision{ //nolint:exhaustruct
+		ID:         id,
+		StoryID:    "story-1",
+		LocaleCode: "en",
+		Title:      "Hello",
+		Content:    content,
+	})
+}
+
+func (r *fakeRepository) ListStoryRevisions(
+	_ context.Context,
+	storyID string,
+	localeCode string,
+) ([]*stories.StoryRevision, error) {
+	revisions := []*stories.StoryRevision{}
+
+	for _, revision := range slices.Backward(r.revisions) {
+		if revision.StoryID == storyID && revision.LocaleCode == localeCode {
+			revisions = append(revisions, revision)
+		}
+	}
+
+	return revisions, nil
+}
+
+func (r *fakeRepository) GetStoryRevision(
+	ctx context.Context,
+	storyID string,
+	localeCode string,
+	id string,
+) (*stories.StoryRevision, error) {
+	revisions, _ := r.ListStoryRevisions(ctx, storyID, localeCode)
+
+	for _, revision := range revisions {
+		if revision.ID == id {
+			return revision, nil
+		}
+	}
+
+	return nil, nil //nolint:nilnil
+}
+
+func (r *fakeRepository) PruneStoryRevisions(
+	ctx context.Context,
+	storyID string,
+	localeCode string,
+	keep int,
+) (int64, error) {
+	r.pruned = append(r.pruned, keep)
+
+	revisions, _ := r.ListStoryRevisions(ctx, storyID, localeCode)
+	if len(revisions) <= keep {
+		return 0, nil
+	}
+
+	removed := revisions[keep:]
+	r.revisions = slices.DeleteFunc(r.revisions, func(revision *stories.StoryRevision) bool {
+		return slices.Contains(removed, revision)
+	})
+
+	return int64(len(removed)), nil
+}
+
+func TestServiceDiffRevisions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		expected []string
+	}{
+		{
+			name:     "same text",
+			from:     "a\nb",
+			to:       "a\nb",
+			expected: []string{"=a", "=b"},
+		},
+		{
+			name:     "common start and end",
+			from:     "a\nb\nc\nd",
+			to:       "a\nx\nd",
+			expected: []string{"=a", "-b", "-c", "+x", "=d"},
+		},
+		{
+			name:     "longest common subsequence",
+			from:     "a\nb\nc\nd\ne",
+			to:       "b\nx\nd\ne\nf",
+			expected: []string{"-a", "=b", "-c", "+x", "=d", "=e", "+f"},
+		},
+		{
+			name:     "moved line",
+			from:     "a\nb\nc",
+			to:       "c\na\nb",
+			expected: []string{"+c", "=a", "=b", "-c"},
+		},
+		{
+			name:     "empty text",
+			from:     "",
+			to:       "a",
+			expected: []string{"+a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newFakeRepository()
+			repo.addRevision("rev-1", tt.from)
+			repo.addRevision("rev-2", tt.to)
+			service := newTestService(repo)
+
+			diff, err := service.DiffRevisions(t.Context(), "en", ownerUserID, "hello", "rev-1", "rev-2")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, diffOps(diff.Content))
+			assert.Equal(t, []string{"=Hello"}, diffOps(diff.Title))
+		})
+	}
+
+	t.Run("should diff to the current story", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newFakeRepository()
+		repo.addRevision("rev-1", "a\nb")
+		repo.records["story-1"] = &stories.StoryWithChildren{ //nolint:exhaustruct
+			Story: &stories.Story{ID: "story-1", Title: "Hello", Content: "a\nc"}, //nolint:exhaustruct
+		}
+		service := newTestService(repo)
+
+		diff, err := service.DiffRevisions(t.Context(), "en", ownerUserID, "hello", "rev-1", "")
+		require.NoError(t, err)
+		assert.Empty(t, diff.ToRevisionID)
+		assert.Equal(t, []string{"=a", "-b", "+c"}, diffOps(diff.Content))
+	})
+
+	t.Run("should diff long texts along their common lines", func(t *testing.T) {
+		t.Parallel()
+
+		from, to := numberedLines("from", 1500), numberedLines("to", 1500)
+		from[700], to[800] = "common", "common"
+
+		repo := newFakeRepository()
+		repo.addRevision("rev-1", strings.Join(from, "\n"))
+		repo.addRevision("rev-2", strings.Join(to, "\n"))
+		service := newTestService(repo)
+
+		diff, err := service.DiffRevisions(t.Context(), "en", ownerUserID, "hello", "rev-1", "rev-2")
+		require.NoError(t, err)
+		assertDiffOf(t, from, to, diff.Content)
+		assert.Contains(t, diffOps(diff.Content), "=common")
+	})
+
+	t.Run("should diff texts over the bound as a whole", func(t *testing.T) {
+		t.Parallel()
+
+		// the common start and end do not count towards the bound.
+		from, to := numberedLines("from", 2100), numberedLines("to", 2100)
+		from[1000], to[1100] = "common", "common"
+		from = append([]string{"start"}, append(from, "end")...)
+		to = append([]string{"start"}, append(to, "end")...)
+
+		repo := newFakeRepository()
+		repo.addRevision("rev-1", strings.Join(from, "\n"))
+		repo.addRevision("rev-2", strings.Join(to, "\n"))
+		service := newTestService(repo)
+
+		diff, err := service.DiffRevisions(t.Context(), "en", ownerUserID, "hello", "rev-1", "rev-2")
+		require.NoError(t, err)
+		assertDiffOf(t, from, to, diff.Content)
+
+		ops := diffOps(diff.Content)
+		assert.Equal(t, "=start", ops[0])
+		assert.Equal(t, "-from-0", ops[1])
+		assert.Equal(t, "-common", ops[1001])
+		assert.Equal(t, "+to-0", ops[2101])
+		assert.Equal(t, "=end", ops[len(ops)-1])
+		assert.NotContains(t, ops, "=common")
+	})
+
+	t.Run("should report unknown revisions", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newFakeRepository()
+		repo.addRevision("rev-1", "a")
+		service := newTestService(repo)
+
+		_, err := service.DiffRevisions(t.Context(), "en", ownerUserID, "hello", "rev-1", "rev-9")
+		require.ErrorIs(t, err, stories.ErrRevisionNotFound)
+
+		_, err = service.DiffRevisions(t.Context(), "tr", ownerUserID, "hello", "rev-1", "")
+		require.ErrorIs(t, err, stories.ErrRevisionNotFound)
+	})
+
+	t.Run("should reject the users who do not own the author profile", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newFakeRepository()
+		repo.addRevision("rev-1", "a")
+		service := newTestService(repo)
+
+		_, err := service.DiffRevisions(t.Context(), "en", "user-other", "hello", "rev-1", "")
+		require.ErrorIs(t, err, stories.ErrNotStoryAuthor)
+	})
+}
+
+func TestServiceRestoreRevision(t *testing.T) {
+	t.Parallel()
+
+	repo := newFakeRepository()
+	repo.addRevision("rev-1", "Once upon a time")
+	repo.addRevision("rev-2", "Once upon a time, again")
+	repo.records["story-1"] = &stories.StoryWithChildren{ //nolint:exhaustruct
+		Story: &stories.Story{ //nolint:exhaustruct
+			ID:      "story-1",
+			Title:   "Hello",
+			Content: "Once upon a time, again",
+		},
+	}
+	service := newTestService(repo)
+
+	record, err := service.RestoreRevision(t.Context(), "en", ownerUserID, "hello", "rev-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Once upon a time", record.Content)
+
+	require.Len(t, repo.updates, 1)
+	assert.Equal(t, "Once upon a time", *repo.updates[0].Content)
+
+	// the restored content is a new revision, and the history is kept.
+	revisions, err := service.ListRevisions(t.Context(), "en", ownerUserID, "hello")
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	assert.NotEqual(t, "rev-1", revisions[0].ID)
+	assert.Equal(t, "Once upon a time", revisions[0].Content)
+	assert.Equal(t, ownerUserID, revisions[0].AuthorUserID)
+
+	_, err = service.RestoreRevision(t.Context(), "en", ownerUserID, "hello", "rev-9")
+	require.ErrorIs(t, err, stories.ErrRevisionNotFound)
+}
+
+func TestServiceRevisionRetention(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name              string
+		retention         int
+		expectedPruned    []int
+		expectedRevisions int
+	}{
+		{name: "kept all", retention: 0, expectedRevisions: 4},
+		{name: "kept all below zero", retention: -1, expectedRevisions: 4},
+		{name: "pruned", retention: 2, expectedPruned: []int{2, 2}, expectedRevisions: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newFakeRepository()
+			repo.addRevision("rev-1", "first")
+			repo.addRevision("rev-2", "second")
+			service := newTestService(repo)
+			service.SetRevisionRetention(tt.retention)
+
+			title := "Hello"
+
+			for _, content := range []string{"third", "fourth"} {
+				input := &stories.UpdateStoryInput{Title: &title, Content: &content} //nolint:exhaustruct
+
+				_, err := service.Update(t.Context(), "en", ownerUserID, "hello", input)
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.expectedPruned, repo.pruned)
+
+			revisions, err := service.ListRevisions(t.Context(), "en", ownerUserID, "hello")
+			require.NoError(t, err)
+			require.Len(t, revisions, tt.expectedRevisions)
+			assert.Equal(t, "fourth", revisions[0].Content)
+		})
+	}
+}
+
+// diffOps writes each line of a diff as its op, "=", "-" or "+", and its text.
+func diffOps(lines []*stories.DiffLine) []string {
+	signs := map[string]string{
+		stories.DiffOpEqual:  "=",
+		stories.DiffOpDelete: "-",
+		stories.DiffOpInsert: "+",
+	}
+
+	ops := make([]string, 0, len(lines))
+	for _, line := range lines {
+		ops = append(ops, signs[line.Op]+line.Text)
+	}
+
+	return ops
+}
+
+// assertDiffOf checks that the lines the diff keeps and deletes are from, and the
+// lines it keeps and inserts are to.
+func assertDiffOf(t *testing.T, from []string, to []string, lines []*stories.DiffLine) {
+	t.Helper()
+
+	var fromLines, toLines []string
+
+	for _, line := range lines {
+		if line.Op != stories.DiffOpInsert {
+			fromLines = append(fromLines, line.Text)
+		}
+
+		if line.Op != stories.DiffOpDelete {
+			toLines = append(toLines, line.Text)
+		}
+	}
+
+	assert.Equal(t, from, fromLines)
+	assert.Equal(t, to, toLines)
+}
+
+func numberedLines(prefix string, count int) []string {
+	lines := make([]string, count)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("%s-%d", prefix, i)
+	}
+
+	return lines
+}
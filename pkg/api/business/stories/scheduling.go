@@ -0,0 +1,117 @@
+package stories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SchedulePublish schedules a draft or a story in review to be published at
+// publishAt, once it checks that the user owns the author profile of the story. A
+// nil publishAt clears the schedule. Scheduled stories are published by
+// PublishDueStories, whether they are reviewed or not.
+func (s *Service) SchedulePublish(
+	ctx context.Context,
+	localeCode string,
+	userID string,
+	slug string,
+	publishAt *time.Time,
+) (*StoryWithChildren, error) {
+	if publishAt != nil && !publishAt.After(time.Now()) {
+		return nil, fmt.Errorf(
+			"%w: publish_at must be in the future (slug: %s)",
+			ErrInvalidStoryInput,
+			slug,
+		)
+	}
+
+	story, err := s.getAuthoredStory(ctx, userID, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if isVisibleStatus(story.Status) {
+		return nil, fmt.Errorf(
+			"%w(slug: %s, from: %s, to: %s)",
+			ErrInvalidStatusTransition,
+			slug,
+			story.Status,
+			StoryStatusPublished,
+		)
+	}
+
+	updated, err := s.repo.SetStoryPublishAt(ctx, story.ID, publishAt)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToUpdateRecord, slug, err)
+	}
+
+	// the story was published or archived since it was read.
+	if updated == 0 {
+		return nil, fmt.Errorf(
+			"%w(slug: %s, to: %s)",
+			ErrInvalidStatusTransition,
+			slug,
+			StoryStatusPublished,
+		)
+	}
+
+	s.logger.InfoContext(ctx, "story publish time set", "slug", slug, "publish_at", publishAt)
+
+	return s.getAuthoredRecord(ctx, localeCode, story.ID)
+}
+
+// PublishDueStories publishes up to batchSize scheduled stories whose publish time
+// has come. A story that fails is logged and the others are still published; the
+// next run picks the failed one again.
+func (s *Service) PublishDueStories(ctx context.Context, batchSize int) error {
+	due, err := s.repo.ListDueStories(ctx, time.Now(), batchSize)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToListRecords, err)
+	}
+
+	var errs []error
+
+	for _, story := range due {
+		if ctx.Err() != nil {
+			return ctx.Err() //nolint:wrapcheck
+		}
+
+		updated, err := s.repo.UpdateStoryStatus(
+			ctx,
+			story,
+			StoryStatusPublished,
+			string(s.idGenerator()),
+		)
+		if err != nil {
+			s.logger.WarnContext(
+				ctx,
+				"failed to publish scheduled story",
+				"story_id", story.ID,
+				"author", story.AuthorProfileSlug,
+				"error", err,
+			)
+
+			errs = append(errs, fmt.Errorf("%w(story_id: %s): %w", ErrFailedToUpdateRecord, story.ID, err))
+
+			continue
+		}
+
+		// the status changed since it was listed.
+		if updated == 0 {
+			continue
+		}
+
+		s.logger.InfoContext(
+			ctx,
+			"scheduled story published",
+			"story_id", story.ID,
+			"from", story.Status,
+			"author", story.AuthorProfileSlug,
+		)
+
+		s.invalidateStory(ctx, story, nil)
+	}
+
+	return errors.Join(errs...)
+}
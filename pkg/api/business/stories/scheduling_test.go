@@ -0,0 +1,163 @@
+package stories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/api/business/stories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (r *fakeRepository) SetStoryPublishAt(
+	_ context.Context,
+	id string,
+	publishAt *time.Time,
+) (int64, error) {
+	for _, stored := range r.stories {
+		if stored.ID == id &&
+			(stored.Status == stories.StoryStatusDraft ||
+				stored.Status == stories.StoryStatusInReview) {
+			r.publishAt[id] = publishAt
+
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// ListDueStories lists the scheduled stories that are still drafts or in review, like
+// the storage adapter.
+func (r *fakeRepository) ListDueStories(
+	_ context.Context,
+	dueAt time.Time,
+	limit int,
+) ([]*stories.AuthoredStory, error) {
+	due := []*stories.AuthoredStory{}
+
+	for _, stored := range r.stories {
+		publishAt := r.publishAt[stored.ID]
+		if publishAt == nil || publishAt.After(dueAt) || len(due) == limit {
+			continue
+		}
+
+		if stored.Status == stories.StoryStatusDraft ||
+			stored.Status == stories.StoryStatusInReview {
+			copied := *stored
+			due = append(due, &copied)
+		}
+	}
+
+	return due, nil
+}
+
+func TestServiceSchedulePublish(t *testing.T) {
+	t.Parallel()
+
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		expectedErr error
+		publishAt   *time.Time
+		name        string
+		status      string
+		userID      string
+	}{
+		{
+			name:      "draft in the future",
+			status:    stories.StoryStatusDraft,
+			userID:    ownerUserID,
+			publishAt: &future,
+		},
+		{
+			name:      "in review in the future",
+			status:    stories.StoryStatusInReview,
+			userID:    ownerUserID,
+			publishAt: &future,
+		},
+		{
+			name:   "cleared schedule",
+			status: stories.StoryStatusDraft,
+			userID: ownerUserID,
+		},
+		{
+			name:        "draft in the past",
+			status:      stories.StoryStatusDraft,
+			userID:      ownerUserID,
+			publishAt:   &past,
+			expectedErr: stories.ErrInvalidStoryInput,
+		},
+		{
+			name:        "published story",
+			status:      stories.StoryStatusPublished,
+			userID:      ownerUserID,
+			publishAt:   &future,
+			expectedErr: stories.ErrInvalidStatusTransition,
+		},
+		{
+			name:        "archived story",
+			status:      stories.StoryStatusArchived,
+			userID:      ownerUserID,
+			publishAt:   &future,
+			expectedErr: stories.ErrInvalidStatusTransition,
+		},
+		{
+			name:        "not the author",
+			status:      stories.StoryStatusDraft,
+			userID:      "user-other",
+			publishAt:   &future,
+			expectedErr: stories.ErrNotStoryAuthor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newFakeRepository()
+			repo.stories["hello"].Status = tt.status
+			service := newTestService(repo)
+
+			_, err := service.SchedulePublish(t.Context(), "en", tt.userID, "hello", tt.publishAt)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				assert.Empty(t, repo.publishAt)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Contains(t, repo.publishAt, "story-1")
+			assert.Equal(t, tt.publishAt, repo.publishAt["story-1"])
+		})
+	}
+}
+
+func TestServicePublishDueStories(t *testing.T) {
+	t.Parallel()
+
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	repo := newFakeRepository()
+	repo.stories["later"] = &stories.AuthoredStory{
+		ID:                "story-2",
+		Status:            stories.StoryStatusInReview,
+		AuthorProfileID:   authorProfileID,
+		AuthorProfileSlug: "author",
+	}
+	repo.publishAt["story-1"] = &past
+	repo.publishAt["story-2"] = &future
+	service := newTestService(repo)
+
+	require.NoError(t, service.PublishDueStories(t.Context(), 10))
+	assert.Equal(t, stories.StoryStatusPublished, repo.stories["hello"].Status)
+	assert.Equal(t, stories.StoryStatusInReview, repo.stories["later"].Status)
+
+	// A published story is not listed as due again.
+	require.NoError(t, service.PublishDueStories(t.Context(), 10))
+	assert.Equal(t, stories.StoryStatusPublished, repo.stories["hello"].Status)
+}
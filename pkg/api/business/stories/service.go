@@ -4,22 +4,30 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/eser/aya.is-services/pkg/ajan/logfx"
 	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/eser/aya.is-services/pkg/api/business/reactions"
 	"github.com/eser/aya.is-services/pkg/lib/cursors"
 )
 
 var (
-	ErrFailedToGetRecord   = errors.New("failed to get record")
-	ErrFailedToListRecords = errors.New("failed to list records")
-	// ErrFailedToCreateRecord = errors.New("failed to create record").
+	ErrFailedToGetRecord    = errors.New("failed to get record")
+	ErrFailedToListRecords  = errors.New("failed to list records")
+	ErrFailedToCreateRecord = errors.New("failed to create record")
+	ErrFailedToUpdateRecord = errors.New("failed to update record")
+	ErrFailedToDeleteRecord = errors.New("failed to delete record")
 )
 
 type Repository interface {
 	GetProfileIDBySlug(ctx context.Context, slug string) (string, error)
 	GetProfileByID(ctx context.Context, localeCode string, id string) (*profiles.Profile, error)
 	GetStoryIDBySlug(ctx context.Context, slug string) (string, error)
+	// GetProfileSlugRedirect and GetStorySlugRedirect return the current slug of the
+	// record that had oldSlug before, or "" when none had it.
+	GetProfileSlugRedirect(ctx context.Context, oldSlug string) (string, error)
+	GetStorySlugRedirect(ctx context.Context, oldSlug string) (string, error)
 	GetStoryByID(
 		ctx context.Context,
 		localeCode string,
@@ -31,16 +39,87 @@ type Repository interface {
 		localeCode string,
 		cursor *cursors.Cursor,
 	) (cursors.Cursored[[]*StoryWithChildren], error)
+	// GetUserIndividualProfileID returns "" when the user has no individual profile.
+	GetUserIndividualProfileID(ctx context.Context, userID string) (string, error)
+	// ListStoriesOfFollowedProfiles lists the stories written by or published in the
+	// profiles the follower follows, the latest first.
+	ListStoriesOfFollowedProfiles(
+		ctx context.Context,
+		localeCode string,
+		followerProfileID string,
+		cursor *cursors.Cursor,
+	) (cursors.Cursored[[]*StoryWithChildren], error)
+
+	IsProfileOwnedByUser(ctx context.Context, profileID string, userID string) (bool, error)
+	// GetAuthoredStoryBySlug returns nil when no story with an author has the slug.
+	GetAuthoredStoryBySlug(ctx context.Context, slug string) (*AuthoredStory, error)
+	// CreateStory and UpdateStory store the revision along with the story, unless it
+	// is nil.
+	CreateStory(ctx context.Context, localeCode string, story *Story, revision *StoryRevision) error
+	UpdateStory(
+		ctx context.Context,
+		localeCode string,
+		id string,
+		slug string,
+		input *UpdateStoryInput,
+		revision *StoryRevision,
+	) (int64, error)
+	// UpdateStoryStatus changes the status of the story only while it is still
+	// story.Status. The story is published to its author profile while it is published.
+	UpdateStoryStatus(
+		ctx context.Context,
+		story *AuthoredStory,
+		status string,
+		publicationID string,
+	) (int64, error)
+	RemoveStory(ctx context.Context, id string, slug string) (int64, error)
+	// SetStoryPublishAt schedules the story only while it is a draft or in review; a
+	// nil publishAt clears the schedule.
+	SetStoryPublishAt(ctx context.Context, id string, publishAt *time.Time) (int64, error)
+	// ListDueStories lists the scheduled stories whose publish time is not after
+	// dueAt, the earliest first.
+	ListDueStories(ctx context.Context, dueAt time.Time, limit int) ([]*AuthoredStory, error)
+	// ListStoryRevisions lists the revisions of the story in the locale, the latest
+	// first.
+	ListStoryRevisions(ctx context.Context, storyID string, localeCode string) ([]*StoryRevision, error)
+	// GetStoryRevision returns nil when the story has no such revision in the locale.
+	GetStoryRevision(
+		ctx context.Context,
+		storyID string,
+		localeCode string,
+		id string,
+	) (*StoryRevision, error)
+	// PruneStoryRevisions removes the revisions of the story in the locale but the
+	// latest keep.
+	PruneStoryRevisions(ctx context.Context, storyID string, localeCode string, keep int) (int64, error)
 }
 
 type Service struct {
-	logger      *logfx.Logger
-	repo        Repository
-	idGenerator RecordIDGenerator
+	logger           *logfx.Logger
+	repo             Repository
+	cacheInvalidator profiles.CacheInvalidator
+	reactions        *reactions.Service
+	idGenerator      RecordIDGenerator
+
+	feedSettings      FeedSettings
+	revisionRetention int
 }
 
-func NewService(logger *logfx.Logger, repo Repository) *Service {
-	return &Service{logger: logger, repo: repo, idGenerator: DefaultIDGenerator}
+func NewService(
+	logger *logfx.Logger,
+	repo Repository,
+	cacheInvalidator profiles.CacheInvalidator,
+	reactionsService *reactions.Service,
+) *Service {
+	return &Service{
+		logger:            logger,
+		repo:              repo,
+		cacheInvalidator:  cacheInvalidator,
+		reactions:         reactionsService,
+		idGenerator:       DefaultIDGenerator,
+		feedSettings:      FeedSettings{BaseURL: "", ItemCount: DefaultFeedItemCount},
+		revisionRetention: 0,
+	}
 }
 
 func (s *Service) GetByID(
@@ -53,6 +132,11 @@ func (s *Service) GetByID(
 		return nil, fmt.Errorf("%w(id: %s): %w", ErrFailedToGetRecord, id, err)
 	}
 
+	err = s.attachReactions(ctx, record)
+	if err != nil {
+		return nil, fmt.Errorf("%w(id: %s): %w", ErrFailedToGetRecord, id, err)
+	}
+
 	return record, nil
 }
 
@@ -66,11 +150,28 @@ func (s *Service) GetBySlug(
 		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
 	}
 
+	if storyID == "" {
+		err = s.checkSlugRedirect(ctx, slug, s.repo.GetStorySlugRedirect)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	record, err := s.repo.GetStoryByID(ctx, localeCode, storyID, nil)
 	if err != nil {
 		return nil, fmt.Errorf("%w(story_id: %s): %w", ErrFailedToGetRecord, storyID, err)
 	}
 
+	// drafts and stories in review are not shown before they are published.
+	if record != nil && !isVisibleStatus(record.Status) {
+		return nil, nil //nolint:nilnil
+	}
+
+	err = s.attachReactions(ctx, record)
+	if err != nil {
+		return nil, fmt.Errorf("%w(story_id: %s): %w", ErrFailedToGetRecord, storyID, err)
+	}
+
 	return record, nil
 }
 
@@ -88,6 +189,15 @@ func (s *Service) List(
 		)
 	}
 
+	err = s.attachReactions(ctx, records.Data...)
+	if err != nil {
+		return cursors.Cursored[[]*StoryWithChildren]{}, fmt.Errorf(
+			"%w: %w",
+			ErrFailedToListRecords,
+			err,
+		)
+	}
+
 	return records, nil
 }
 
@@ -107,6 +217,13 @@ func (s *Service) ListByPublicationProfileSlug(
 		)
 	}
 
+	if publicationProfileID == "" {
+		err = s.checkSlugRedirect(ctx, publicationProfileSlug, s.repo.GetProfileSlugRedirect)
+		if err != nil {
+			return cursors.Cursored[[]*StoryWithChildren]{}, err
+		}
+	}
+
 	cursor.Filters["publication_profile_id"] = publicationProfileID
 
 	records, err := s.repo.ListStoriesOfPublication(
@@ -122,5 +239,84 @@ func (s *Service) ListByPublicationProfileSlug(
 		)
 	}
 
+	err = s.attachReactions(ctx, records.Data...)
+	if err != nil {
+		return cursors.Cursored[[]*StoryWithChildren]{}, fmt.Errorf(
+			"%w: %w",
+			ErrFailedToListRecords,
+			err,
+		)
+	}
+
 	return records, nil
 }
+
+// ListFeed lists the stories of the profiles the individual profile of the user
+// follows, the latest first.
+func (s *Service) ListFeed(
+	ctx context.Context,
+	localeCode string,
+	userID string,
+	cursor *cursors.Cursor,
+) (cursors.Cursored[[]*StoryWithChildren], error) {
+	followerProfileID, err := s.repo.GetUserIndividualProfileID(ctx, userID)
+	if err != nil {
+		return cursors.Cursored[[]*StoryWithChildren]{}, fmt.Errorf(
+			"%w(user_id: %s): %w",
+			ErrFailedToGetRecord,
+			userID,
+			err,
+		)
+	}
+
+	if followerProfileID == "" {
+		return cursors.Cursored[[]*StoryWithChildren]{}, fmt.Errorf(
+			"%w(user_id: %s)",
+			profiles.ErrIndividualProfileRequired,
+			userID,
+		)
+	}
+
+	records, err := s.repo.ListStoriesOfFollowedProfiles(
+		ctx,
+		localeCode,
+		followerProfileID,
+		cursor,
+	)
+	if err != nil {
+		return cursors.Cursored[[]*StoryWithChildren]{}, fmt.Errorf(
+			"%w: %w",
+			ErrFailedToListRecords,
+			err,
+		)
+	}
+
+	err = s.attachReactions(ctx, records.Data...)
+	if err != nil {
+		return cursors.Cursored[[]*StoryWithChildren]{}, fmt.Errorf(
+			"%w: %w",
+			ErrFailedToListRecords,
+			err,
+		)
+	}
+
+	return records, nil
+}
+
+// checkSlugRedirect returns a *profiles.SlugMovedError when a record had slug before.
+func (s *Service) checkSlugRedirect(
+	ctx context.Context,
+	slug string,
+	getRedirect func(ctx context.Context, oldSlug string) (string, error),
+) error {
+	currentSlug, err := getRedirect(ctx, slug)
+	if err != nil {
+		return fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if currentSlug != "" && currentSlug != slug {
+		return &profiles.SlugMovedError{OldSlug: slug, Slug: currentSlug}
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package stories_test
+
+import (
+	"context"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/eser/aya.is-services/pkg/api/business/reactions"
+	"github.com/eser/aya.is-services/pkg/api/business/stories"
+	"github.com/eser/aya.is-services/pkg/lib/cursors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	ownerUserID     = "user-owner"
+	authorProfileID = "profile-author"
+	readerUserID    = "user-reader"
+	readerProfileID = "profile-reader"
+)
+
+// fakeRepository keeps the stories in memory. Every test starts with "hello", a draft
+// of the "author" profile owned by ownerUserID, and changes the records it needs.
+type fakeRepository struct {
+	stories.Repository
+
+	stories map[string]*stories.AuthoredStory     // slug -> story
+	records map[string]*stories.StoryWithChildren // story id -> record
+	created []*stories.Story
+	updates []*stories.UpdateStoryInput
+	// revisions holds the stored revisions, the oldest first.
+	revisions []*stories.StoryRevision
+	pruned    []int // keep of each prune of the revisions
+
+	publishAt map[string]*time.Time // story id -> publish time of the story
+
+	individuals map[string]string // user id -> id of the individual profile of the user
+	// feed holds the stories of the profiles readerProfileID follows, the latest first.
+	feed []*stories.StoryWithChildren
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{ //nolint:exhaustruct
+		stories: map[string]*stories.AuthoredStory{
+			"hello": {
+				ID:                "story-1",
+				Status:            stories.StoryStatusDraft,
+				AuthorProfileID:   authorProfileID,
+				AuthorProfileSlug: "author",
+			},
+		},
+		records:     map[string]*stories.StoryWithChildren{},
+		publishAt:   map[string]*time.Time{},
+		individuals: map[string]string{readerUserID: readerProfileID},
+		feed: []*stories.StoryWithChildren{
+			{Story: &stories.Story{ID: "story-3"}}, //nolint:exhaustruct
+			{Story: &stories.Story{ID: "story-2"}}, //nolint:exhaustruct
+			{Story: &stories.Story{ID: "story-1"}}, //nolint:exhaustruct
+		},
+	}
+}
+
+// fakeReactionsRepository has no reactions.
+type fakeReactionsRepository struct {
+	reactions.Repository
+}
+
+func (r *fakeReactionsRepository) ListReactionCounts(
+	_ context.Context,
+	_ string,
+	_ []string,
+) (map[string][]*reactions.Count, error) {
+	return map[string][]*reactions.Count{}, nil
+}
+
+func newTestService(repo stories.Repository) *stories.Service {
+	logger := logfx.NewLogger(logfx.WithWriter(io.Discard))
+
+	return stories.NewService(
+		logger,
+		repo,
+		nil,
+		reactions.NewService(logger, &fakeReactionsRepository{}), //nolint:exhaustruct
+	)
+}
+
+func (r *fakeRepository) GetProfileIDBySlug(_ context.Context, slug string) (string, error) {
+	if slug == "author" {
+		return authorProfileID, nil
+	}
+
+	return "", nil
+}
+
+func (r *fakeRepository) IsProfileOwnedByUser(
+	_ context.Context,
+	profileID string,
+	userID string,
+) (bool, error) {
+	return profileID == authorProfileID && userID == ownerUserID, nil
+}
+
+func (r *fakeRepository) GetStoryIDBySlug(_ context.Context, slug string) (string, error) {
+	if story, ok := r.stories[slug]; ok {
+		return story.ID, nil
+	}
+
+	return "", nil
+}
+
+func (r *fakeRepository) GetStoryByID(
+	_ context.Context,
+	_ string,
+	id string,
+	_ *string,
+) (*stories.StoryWithChildren, error) {
+	return r.records[id], nil
+}
+
+func (r *fakeRepository) GetUserIndividualProfileID(
+	_ context.Context,
+	userID string,
+) (string, error) {
+	return r.individuals[userID], nil
+}
+
+// ListStoriesOfFollowedProfiles pages through the feed like the storage adapter: a page
+// starts after the story of the cursor, and a full page carries the last ID as cursor.
+func (r *fakeRepository) ListStoriesOfFollowedProfiles(
+	_ context.Context,
+	_ string,
+	followerProfileID string,
+	cursor *cursors.Cursor,
+) (cursors.Cursored[[]*stories.StoryWithChildren], error) {
+	if followerProfileID != readerProfileID {
+		return cursors.WrapResponseWithCursor([]*stories.StoryWithChildren{}, nil), nil
+	}
+
+	start := 0
+	if cursor.Offset != nil {
+		start = slices.IndexFunc(r.feed, func(story *stories.StoryWithChildren) bool {
+			return story.ID == *cursor.Offset
+		}) + 1
+	}
+
+	page := r.feed[start:min(start+cursor.Limit, len(r.feed))]
+
+	var next *string
+	if len(page) == cursor.Limit {
+		next = &page[len(page)-1].ID
+	}
+
+	return cursors.WrapResponseWithCursor(page, next), nil
+}
+
+func TestServiceListFeed(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should page through the feed, the latest first", func(t *testing.T) {
+		t.Parallel()
+
+		service := newTestService(newFakeRepository())
+
+		cursor := &cursors.Cursor{Limit: 2} //nolint:exhaustruct
+
+		first, err := service.ListFeed(t.Context(), "en", readerUserID, cursor)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"story-3", "story-2"}, storyIDs(first.Data))
+		require.NotNil(t, first.CursorPtr)
+
+		cursor.Offset = first.CursorPtr
+
+		second, err := service.ListFeed(t.Context(), "en", readerUserID, cursor)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"story-1"}, storyIDs(second.Data))
+		assert.Nil(t, second.CursorPtr)
+	})
+
+	t.Run("should require an individual profile", func(t *testing.T) {
+		t.Parallel()
+
+		service := newTestService(newFakeRepository())
+
+		cursor := &cursors.Cursor{Limit: 2} //nolint:exhaustruct
+
+		_, err := service.ListFeed(t.Context(), "en", "user-other", cursor)
+		require.ErrorIs(t, err, profiles.ErrIndividualProfileRequired)
+	})
+}
+
+func storyIDs(records []*stories.StoryWithChildren) []string {
+	ids := make([]string, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+
+	return ids
+}
@@ -1,10 +1,12 @@
 package stories
 
 import (
+	"encoding/xml"
 	"time"
 
 	"github.com/eser/aya.is-services/pkg/ajan/lib"
 	"github.com/eser/aya.is-services/pkg/api/business/profiles"
+	"github.com/eser/aya.is-services/pkg/api/business/reactions"
 )
 
 type RecordID string
@@ -20,16 +22,80 @@ type Story struct {
 	Properties      any        `json:"properties"`
 	AuthorProfileID *string    `json:"author_profile_id"`
 	StoryPictureURI *string    `json:"story_picture_uri"`
+	PublishAt       *time.Time `json:"publish_at"`
 	UpdatedAt       *time.Time `json:"updated_at"`
 	DeletedAt       *time.Time `json:"deleted_at"`
-	ID              string     `json:"id"`
-	Slug            string     `json:"slug"`
-	Kind            string     `json:"kind"`
-	Status          string     `json:"status"`
-	Title           string     `json:"title"`
-	Summary         string     `json:"summary"`
-	Content         string     `json:"content"`
-	IsFeatured      bool       `json:"is_featured"`
+	// Reactions are the counts of the reactions to the story, the most reacted kinds
+	// first.
+	Reactions  []*reactions.Count `json:"reactions"`
+	ID         string             `json:"id"`
+	Slug       string             `json:"slug"`
+	Kind       string             `json:"kind"`
+	Status     string             `json:"status"`
+	Title      string             `json:"title"`
+	Summary    string             `json:"summary"`
+	Content    string             `json:"content"`
+	IsFeatured bool               `json:"is_featured"`
+}
+
+// CreateStoryInput is the payload of creating a story. Title, Summary and Content are
+// stored for the locale of the request.
+type CreateStoryInput struct {
+	StoryPictureURI *string `json:"story_picture_uri"`
+	Slug            string  `json:"slug"`
+	Kind            string  `json:"kind"`
+	Title           string  `json:"title"`
+	Summary         string  `json:"summary"`
+	Content         string  `json:"content"`
+}
+
+// UpdateStoryInput is the payload of updating a story. Only the fields that are set
+// change; Title, Summary and Content change for the locale of the request.
+type UpdateStoryInput struct {
+	Slug            *string `json:"slug"`
+	StoryPictureURI *string `json:"story_picture_uri"`
+	Title           *string `json:"title"`
+	Summary         *string `json:"summary"`
+	Content         *string `json:"content"`
+}
+
+// AuthoredStory is what authoring a story needs to know of it.
+type AuthoredStory struct {
+	ID                string
+	Status            string
+	AuthorProfileID   string
+	AuthorProfileSlug string
+}
+
+// StoryRevision is a stored version of the title, summary and content of a story in
+// a locale. Revisions are not changed once they are stored.
+type StoryRevision struct {
+	CreatedAt    time.Time `json:"created_at"`
+	ID           string    `json:"id"`
+	StoryID      string    `json:"story_id"`
+	LocaleCode   string    `json:"locale_code"`
+	AuthorUserID string    `json:"author_user_id"`
+	Title        string    `json:"title"`
+	Summary      string    `json:"summary"`
+	Content      string    `json:"content"`
+}
+
+// RevisionDiff lists the changes of the title, summary and content of a story from a
+// revision to another, line by line.
+type RevisionDiff struct {
+	FromRevisionID string `json:"from_revision_id"`
+	// ToRevisionID is "" when the revision is compared to the current story.
+	ToRevisionID string      `json:"to_revision_id"`
+	Title        []*DiffLine `json:"title"`
+	Summary      []*DiffLine `json:"summary"`
+	Content      []*DiffLine `json:"content"`
+}
+
+// DiffLine is a line of a diff; Op is one of DiffOpEqual, DiffOpInsert and
+// DiffOpDelete.
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
 }
 
 type StoryWithChildren struct {
@@ -37,3 +103,77 @@ type StoryWithChildren struct {
 	AuthorProfile *profiles.Profile   `json:"author_profile"`
 	Publications  []*profiles.Profile `json:"publications"`
 }
+
+// Feed is a rendered feed of stories.
+type Feed struct {
+	UpdatedAt   time.Time
+	ContentType string
+	Body        []byte
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+type atomDocument struct {
+	XMLName   xml.Name    `xml:"feed"`
+	Author    *atomPerson `xml:"author"`
+	Namespace string      `xml:"xmlns,attr"`
+	Language  string      `xml:"xml:lang,attr"`
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Subtitle  string      `xml:"subtitle,omitempty"`
+	Updated   string      `xml:"updated"`
+	Links     []atomLink  `xml:"link"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Author    *atomPerson `xml:"author"`
+	Summary   *atomText   `xml:"summary"`
+	Content   *atomText   `xml:"content"`
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Published string      `xml:"published"`
+	Updated   string      `xml:"updated"`
+	Links     []atomLink  `xml:"link"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
@@ -0,0 +1,115 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	APIKeyPrefix = "aya_"
+
+	apiKeyRandomBytes   = 32
+	apiKeyDisplayLength = len(APIKeyPrefix) + 8
+)
+
+var (
+	ErrInvalidAPIKey  = errors.New("invalid API key")
+	ErrAPIKeyNotFound = errors.New("API key not found")
+)
+
+// MintAPIKey creates an API key with the given scopes, and returns the record together
+// with the key. The key cannot be recovered later.
+func (s *Service) MintAPIKey(
+	ctx context.Context,
+	name string,
+	scopes []string,
+	expiresAt *time.Time,
+) (*APIKey, string, error) {
+	random := make([]byte, apiKeyRandomBytes)
+	if _, err := rand.Read(random); err != nil {
+		return nil, "", fmt.Errorf("%w: %w", ErrFailedToCreateRecord, err)
+	}
+
+	key := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(random)
+
+	apiKey := &APIKey{
+		CreatedAt:  time.Now(),
+		ExpiresAt:  expiresAt,
+		LastUsedAt: nil,
+		RevokedAt:  nil,
+		ID:         string(s.idGenerator()),
+		Name:       name,
+		Prefix:     key[:apiKeyDisplayLength],
+		Scopes:     scopes,
+	}
+
+	err := s.repo.CreateAPIKey(ctx, apiKey, HashAPIKey(key))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %w", ErrFailedToCreateRecord, err)
+	}
+
+	return apiKey, key, nil
+}
+
+func (s *Service) ListAPIKeys(ctx context.Context) ([]*APIKey, error) {
+	records, err := s.repo.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToListRecords, err)
+	}
+
+	return records, nil
+}
+
+func (s *Service) RevokeAPIKey(ctx context.Context, id string) error {
+	revoked, err := s.repo.RevokeAPIKey(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, id, err)
+	}
+
+	if revoked == 0 {
+		return fmt.Errorf("%w(id: %s)", ErrAPIKeyNotFound, id)
+	}
+
+	return nil
+}
+
+// AuthenticateAPIKey returns the API key record of a key that is neither revoked nor
+// expired, and records that it was used.
+func (s *Service) AuthenticateAPIKey(ctx context.Context, key string) (*APIKey, error) {
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	apiKey, err := s.repo.GetAPIKeyByHash(ctx, HashAPIKey(key))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToGetRecord, err)
+	}
+
+	now := time.Now()
+
+	if apiKey == nil ||
+		apiKey.RevokedAt != nil ||
+		(apiKey.ExpiresAt != nil && !now.Before(*apiKey.ExpiresAt)) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// Failing to record the usage must not lock the client out.
+	_ = s.repo.UpdateAPIKeyLastUsedAt(ctx, apiKey.ID, now)
+
+	return apiKey, nil
+}
+
+// HashAPIKey returns the stored form of a key. API keys carry 256 random bits, so an
+// unsalted SHA-256 is enough and allows looking keys up by their hash.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,168 @@
+package users_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eser/aya.is-services/pkg/ajan/logfx"
+	"github.com/eser/aya.is-services/pkg/api/business/users"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepository keeps the API keys in memory, keyed by the hash of the key.
+type fakeRepository struct {
+	users.Repository
+
+	apiKeys  map[string]*users.APIKey
+	lastUsed map[string]time.Time
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		apiKeys:  map[string]*users.APIKey{},
+		lastUsed: map[string]time.Time{},
+	}
+}
+
+func newTestService(repo users.Repository) *users.Service {
+	return users.NewService(logfx.NewLogger(logfx.WithWriter(io.Discard)), repo, nil)
+}
+
+func (r *fakeRepository) CreateAPIKey(
+	_ context.Context,
+	apiKey *users.APIKey,
+	keyHash string,
+) error {
+	r.apiKeys[keyHash] = apiKey
+
+	return nil
+}
+
+func (r *fakeRepository) GetAPIKeyByHash(
+	_ context.Context,
+	keyHash string,
+) (*users.APIKey, error) {
+	return r.apiKeys[keyHash], nil
+}
+
+func (r *fakeRepository) RevokeAPIKey(_ context.Context, id string) (int64, error) {
+	for _, apiKey := range r.apiKeys {
+		if apiKey.ID == id && apiKey.RevokedAt == nil {
+			now := time.Now()
+			apiKey.RevokedAt = &now
+
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+func (r *fakeRepository) UpdateAPIKeyLastUsedAt(
+	_ context.Context,
+	id string,
+	lastUsedAt time.Time,
+) error {
+	r.lastUsed[id] = lastUsedAt
+
+	return nil
+}
+
+func TestServiceMintAPIKey(t *testing.T) {
+	t.Parallel()
+
+	repo := newFakeRepository()
+	service := newTestService(repo)
+
+	apiKey, key, err := service.MintAPIKey(t.Context(), "deploy", []string{"stories:write"}, nil)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(key, users.APIKeyPrefix))
+	assert.True(t, strings.HasPrefix(key, apiKey.Prefix))
+	assert.Less(t, len(apiKey.Prefix), len(key))
+
+	// Only the hash of the key is stored, and the key is found by it.
+	require.Contains(t, repo.apiKeys, users.HashAPIKey(key))
+	assert.NotContains(t, repo.apiKeys, key)
+
+	authenticated, err := service.AuthenticateAPIKey(t.Context(), key)
+	require.NoError(t, err)
+	assert.Equal(t, apiKey.ID, authenticated.ID)
+	assert.Equal(t, []string{"stories:write"}, authenticated.Scopes)
+	assert.Contains(t, repo.lastUsed, apiKey.ID)
+
+	_, other, err := service.MintAPIKey(t.Context(), "deploy", nil, nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+}
+
+func TestServiceAuthenticateAPIKey(t *testing.T) {
+	t.Parallel()
+
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		expectedErr error
+		expiresAt   *time.Time
+		name        string
+		key         string
+		revoked     bool
+	}{
+		{name: "valid", expiresAt: nil},
+		{name: "not expired yet", expiresAt: &future},
+		{name: "expired", expiresAt: &past, expectedErr: users.ErrInvalidAPIKey},
+		{name: "revoked", revoked: true, expectedErr: users.ErrInvalidAPIKey},
+		{name: "unknown", key: "aya_unknown", expectedErr: users.ErrInvalidAPIKey},
+		{name: "without prefix", key: "unknown", expectedErr: users.ErrInvalidAPIKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newFakeRepository()
+			service := newTestService(repo)
+
+			apiKey, key, err := service.MintAPIKey(t.Context(), "deploy", nil, tt.expiresAt)
+			require.NoError(t, err)
+
+			if tt.revoked {
+				require.NoError(t, service.RevokeAPIKey(t.Context(), apiKey.ID))
+			}
+
+			if tt.key != "" {
+				key = tt.key
+			}
+
+			authenticated, err := service.AuthenticateAPIKey(t.Context(), key)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, authenticated)
+				assert.Empty(t, repo.lastUsed)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, apiKey.ID, authenticated.ID)
+		})
+	}
+}
+
+func TestServiceRevokeAPIKey(t *testing.T) {
+	t.Parallel()
+
+	service := newTestService(newFakeRepository())
+
+	apiKey, _, err := service.MintAPIKey(t.Context(), "deploy", nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, service.RevokeAPIKey(t.Context(), apiKey.ID))
+	require.ErrorIs(t, service.RevokeAPIKey(t.Context(), apiKey.ID), users.ErrAPIKeyNotFound)
+	require.ErrorIs(t, service.RevokeAPIKey(t.Context(), "missing"), users.ErrAPIKeyNotFound)
+}
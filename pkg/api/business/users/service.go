@@ -29,6 +29,12 @@ type Repository interface {
 	CreateSession(ctx context.Context, session *Session) error
 	GetSessionByID(ctx context.Context, id string) (*Session, error)
 	UpdateSessionLoggedInAt(ctx context.Context, id string, loggedInAt time.Time) error
+
+	CreateAPIKey(ctx context.Context, apiKey *APIKey, keyHash string) error
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]*APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string) (int64, error)
+	UpdateAPIKeyLastUsedAt(ctx context.Context, id string, lastUsedAt time.Time) error
 }
 
 type AuthProvider interface {
@@ -45,6 +45,19 @@ type Session struct {
 	OauthRequestCodeVerifier string     `json:"oauth_request_code_verifier"`
 }
 
+// APIKey authenticates a machine client. Only the hash of the key is stored; the key
+// itself is shown once, when it is minted.
+type APIKey struct {
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+}
+
 // --- OAuth & Auth types ---
 
 type OAuthState struct {
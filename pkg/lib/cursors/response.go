@@ -11,3 +11,8 @@ func WrapResponseWithCursor[T any](data T, cursorPtr *string) Cursored[T] {
 		CursorPtr: cursorPtr,
 	}
 }
+
+// CSVRows exports the records without the cursor when responses are served as CSV.
+func (c Cursored[T]) CSVRows() any {
+	return c.Data
+}